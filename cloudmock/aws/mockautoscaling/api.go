@@ -30,6 +30,9 @@ type MockAutoscaling struct {
 	mutex                sync.Mutex
 	Groups               map[string]*autoscaling.Group
 	LaunchConfigurations map[string]*autoscaling.LaunchConfiguration
+
+	WarmPools      map[string]*autoscaling.WarmPoolConfiguration
+	LifecycleHooks map[string]*autoscaling.LifecycleHook
 }
 
 var _ autoscalingiface.AutoScalingAPI = &MockAutoscaling{}
@@ -93,6 +93,19 @@ func (m *MockAutoscaling) CreateAutoScalingGroup(input *autoscaling.CreateAutoSc
 		})
 	}
 
+	for _, hook := range input.LifecycleHookSpecificationList {
+		if m.LifecycleHooks == nil {
+			m.LifecycleHooks = make(map[string]*autoscaling.LifecycleHook)
+		}
+		m.LifecycleHooks[*g.AutoScalingGroupName+"/"+aws.StringValue(hook.LifecycleHookName)] = &autoscaling.LifecycleHook{
+			AutoScalingGroupName: g.AutoScalingGroupName,
+			DefaultResult:        hook.DefaultResult,
+			HeartbeatTimeout:     hook.HeartbeatTimeout,
+			LifecycleHookName:    hook.LifecycleHookName,
+			LifecycleTransition:  hook.LifecycleTransition,
+		}
+	}
+
 	if m.Groups == nil {
 		m.Groups = make(map[string]*autoscaling.Group)
 	}
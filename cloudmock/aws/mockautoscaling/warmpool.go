@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockautoscaling
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"k8s.io/klog/v2"
+)
+
+func (m *MockAutoscaling) DescribeWarmPool(input *autoscaling.DescribeWarmPoolInput) (*autoscaling.DescribeWarmPoolOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Mock DescribeWarmPool: %v", input)
+
+	name := aws.StringValue(input.AutoScalingGroupName)
+	if m.Groups[name] == nil {
+		return nil, fmt.Errorf("AutoScalingGroup %q not found", name)
+	}
+
+	return &autoscaling.DescribeWarmPoolOutput{WarmPoolConfiguration: m.WarmPools[name]}, nil
+}
+
+func (m *MockAutoscaling) PutWarmPool(input *autoscaling.PutWarmPoolInput) (*autoscaling.PutWarmPoolOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Mock PutWarmPool: %v", input)
+
+	name := aws.StringValue(input.AutoScalingGroupName)
+	if m.Groups[name] == nil {
+		return nil, fmt.Errorf("AutoScalingGroup %q not found", name)
+	}
+
+	if m.WarmPools == nil {
+		m.WarmPools = make(map[string]*autoscaling.WarmPoolConfiguration)
+	}
+	m.WarmPools[name] = &autoscaling.WarmPoolConfiguration{
+		MaxGroupPreparedCapacity: input.MaxGroupPreparedCapacity,
+		MinSize:                  input.MinSize,
+		PoolState:                input.PoolState,
+	}
+
+	return &autoscaling.PutWarmPoolOutput{}, nil
+}
+
+func (m *MockAutoscaling) DeleteWarmPool(input *autoscaling.DeleteWarmPoolInput) (*autoscaling.DeleteWarmPoolOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Mock DeleteWarmPool: %v", input)
+
+	name := aws.StringValue(input.AutoScalingGroupName)
+	if m.WarmPools[name] == nil {
+		return nil, fmt.Errorf("warm pool for AutoScalingGroup %q not found", name)
+	}
+	delete(m.WarmPools, name)
+
+	return &autoscaling.DeleteWarmPoolOutput{}, nil
+}
+
+func (m *MockAutoscaling) PutLifecycleHook(input *autoscaling.PutLifecycleHookInput) (*autoscaling.PutLifecycleHookOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Mock PutLifecycleHook: %v", input)
+
+	name := aws.StringValue(input.AutoScalingGroupName)
+	if m.Groups[name] == nil {
+		return nil, fmt.Errorf("AutoScalingGroup %q not found", name)
+	}
+
+	if m.LifecycleHooks == nil {
+		m.LifecycleHooks = make(map[string]*autoscaling.LifecycleHook)
+	}
+	m.LifecycleHooks[name+"/"+aws.StringValue(input.LifecycleHookName)] = &autoscaling.LifecycleHook{
+		AutoScalingGroupName: input.AutoScalingGroupName,
+		DefaultResult:        input.DefaultResult,
+		HeartbeatTimeout:     input.HeartbeatTimeout,
+		LifecycleHookName:    input.LifecycleHookName,
+		LifecycleTransition:  input.LifecycleTransition,
+	}
+
+	return &autoscaling.PutLifecycleHookOutput{}, nil
+}
+
+func (m *MockAutoscaling) DeleteLifecycleHook(input *autoscaling.DeleteLifecycleHookInput) (*autoscaling.DeleteLifecycleHookOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Mock DeleteLifecycleHook: %v", input)
+
+	key := aws.StringValue(input.AutoScalingGroupName) + "/" + aws.StringValue(input.LifecycleHookName)
+	if m.LifecycleHooks[key] == nil {
+		return nil, fmt.Errorf("LifecycleHook %q not found", key)
+	}
+	delete(m.LifecycleHooks, key)
+
+	return &autoscaling.DeleteLifecycleHookOutput{}, nil
+}
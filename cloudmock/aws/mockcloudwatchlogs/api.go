@@ -0,0 +1,35 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcloudwatchlogs
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+)
+
+type MockCloudWatchLogs struct {
+	// Mock out interface
+	cloudwatchlogsiface.CloudWatchLogsAPI
+
+	mutex     sync.Mutex
+	LogGroups map[string]*cloudwatchlogs.LogGroup
+	Tags      map[string]map[string]*string
+}
+
+var _ cloudwatchlogsiface.CloudWatchLogsAPI = &MockCloudWatchLogs{}
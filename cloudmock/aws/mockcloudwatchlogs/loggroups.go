@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcloudwatchlogs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"k8s.io/klog/v2"
+)
+
+func (m *MockCloudWatchLogs) CreateLogGroup(request *cloudwatchlogs.CreateLogGroupInput) (*cloudwatchlogs.CreateLogGroupOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("CreateLogGroup: %v", request)
+
+	name := aws.StringValue(request.LogGroupName)
+	if m.LogGroups[name] != nil {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceAlreadyExistsException, "The specified log group already exists", nil)
+	}
+
+	if m.LogGroups == nil {
+		m.LogGroups = make(map[string]*cloudwatchlogs.LogGroup)
+	}
+	if m.Tags == nil {
+		m.Tags = make(map[string]map[string]*string)
+	}
+	m.LogGroups[name] = &cloudwatchlogs.LogGroup{
+		Arn:          aws.String(fmt.Sprintf("arn:aws:logs:us-test-1:000000000000:log-group:%s:*", name)),
+		LogGroupName: aws.String(name),
+	}
+	m.Tags[name] = make(map[string]*string)
+	for k, v := range request.Tags {
+		m.Tags[name][k] = v
+	}
+
+	return &cloudwatchlogs.CreateLogGroupOutput{}, nil
+}
+
+func (m *MockCloudWatchLogs) DescribeLogGroups(request *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeLogGroups: %v", request)
+
+	var names []string
+	for name := range m.LogGroups {
+		if strings.HasPrefix(name, aws.StringValue(request.LogGroupNamePrefix)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	response := &cloudwatchlogs.DescribeLogGroupsOutput{}
+	for _, name := range names {
+		copy := *m.LogGroups[name]
+		response.LogGroups = append(response.LogGroups, &copy)
+	}
+	return response, nil
+}
+
+func (m *MockCloudWatchLogs) DescribeLogGroupsPages(request *cloudwatchlogs.DescribeLogGroupsInput, callback func(*cloudwatchlogs.DescribeLogGroupsOutput, bool) bool) error {
+	// For the mock, we just send everything in one page
+	page, err := m.DescribeLogGroups(request)
+	if err != nil {
+		return err
+	}
+
+	callback(page, false)
+
+	return nil
+}
+
+func (m *MockCloudWatchLogs) DeleteLogGroup(request *cloudwatchlogs.DeleteLogGroupInput) (*cloudwatchlogs.DeleteLogGroupOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DeleteLogGroup: %v", request)
+
+	name := aws.StringValue(request.LogGroupName)
+	if m.LogGroups[name] == nil {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "The specified log group does not exist", nil)
+	}
+	delete(m.LogGroups, name)
+	delete(m.Tags, name)
+
+	return &cloudwatchlogs.DeleteLogGroupOutput{}, nil
+}
+
+func (m *MockCloudWatchLogs) PutRetentionPolicy(request *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("PutRetentionPolicy: %v", request)
+
+	logGroup := m.LogGroups[aws.StringValue(request.LogGroupName)]
+	if logGroup == nil {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "The specified log group does not exist", nil)
+	}
+	logGroup.RetentionInDays = request.RetentionInDays
+
+	return &cloudwatchlogs.PutRetentionPolicyOutput{}, nil
+}
+
+func (m *MockCloudWatchLogs) DeleteRetentionPolicy(request *cloudwatchlogs.DeleteRetentionPolicyInput) (*cloudwatchlogs.DeleteRetentionPolicyOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DeleteRetentionPolicy: %v", request)
+
+	logGroup := m.LogGroups[aws.StringValue(request.LogGroupName)]
+	if logGroup == nil {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "The specified log group does not exist", nil)
+	}
+	logGroup.RetentionInDays = nil
+
+	return &cloudwatchlogs.DeleteRetentionPolicyOutput{}, nil
+}
+
+func (m *MockCloudWatchLogs) ListTagsLogGroup(request *cloudwatchlogs.ListTagsLogGroupInput) (*cloudwatchlogs.ListTagsLogGroupOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("ListTagsLogGroup: %v", request)
+
+	name := aws.StringValue(request.LogGroupName)
+	if m.LogGroups[name] == nil {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "The specified log group does not exist", nil)
+	}
+
+	tags := make(map[string]*string)
+	for k, v := range m.Tags[name] {
+		tags[k] = v
+	}
+	return &cloudwatchlogs.ListTagsLogGroupOutput{Tags: tags}, nil
+}
+
+func (m *MockCloudWatchLogs) TagLogGroup(request *cloudwatchlogs.TagLogGroupInput) (*cloudwatchlogs.TagLogGroupOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("TagLogGroup: %v", request)
+
+	name := aws.StringValue(request.LogGroupName)
+	if m.LogGroups[name] == nil {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "The specified log group does not exist", nil)
+	}
+	for k, v := range request.Tags {
+		m.Tags[name][k] = v
+	}
+
+	return &cloudwatchlogs.TagLogGroupOutput{}, nil
+}
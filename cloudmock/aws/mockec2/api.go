@@ -51,7 +51,8 @@ type MockEC2 struct {
 
 	Tags []*ec2.TagDescription
 
-	Vpcs map[string]*vpcInfo
+	Vpcs          map[string]*vpcInfo
+	ipv6CIDRCount int
 
 	InternetGateways map[string]*ec2.InternetGateway
 
@@ -60,6 +61,12 @@ type MockEC2 struct {
 
 	NatGateways map[string]*ec2.NatGateway
 
+	PlacementGroups map[string]*ec2.PlacementGroup
+
+	VpcEndpoints map[string]*ec2.VpcEndpoint
+
+	FlowLogs map[string]*ec2.FlowLog
+
 	idsMutex sync.Mutex
 	ids      map[string]*idAllocator
 }
@@ -105,6 +112,15 @@ func (m *MockEC2) All() map[string]interface{} {
 	for id, o := range m.NatGateways {
 		all[id] = o
 	}
+	for id, o := range m.PlacementGroups {
+		all[id] = o
+	}
+	for id, o := range m.VpcEndpoints {
+		all[id] = o
+	}
+	for id, o := range m.FlowLogs {
+		all[id] = o
+	}
 
 	return all
 }
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockec2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+func (m *MockEC2) CreateFlowLogs(request *ec2.CreateFlowLogsInput) (*ec2.CreateFlowLogsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("CreateFlowLogs: %v", request)
+
+	tags := tagSpecificationsToTags(request.TagSpecifications, ec2.ResourceTypeVpcFlowLog)
+
+	if m.FlowLogs == nil {
+		m.FlowLogs = make(map[string]*ec2.FlowLog)
+	}
+
+	response := &ec2.CreateFlowLogsOutput{}
+	for _, resourceID := range request.ResourceIds {
+		id := m.allocateId("fl")
+
+		flowLog := &ec2.FlowLog{
+			FlowLogId:                s(id),
+			FlowLogStatus:            s("ACTIVE"),
+			ResourceId:               resourceID,
+			TrafficType:              request.TrafficType,
+			LogDestinationType:       request.LogDestinationType,
+			LogDestination:           request.LogDestination,
+			LogGroupName:             request.LogGroupName,
+			DeliverLogsPermissionArn: request.DeliverLogsPermissionArn,
+		}
+		if flowLog.LogDestinationType == nil {
+			flowLog.LogDestinationType = s(ec2.LogDestinationTypeCloudWatchLogs)
+		}
+		m.FlowLogs[id] = flowLog
+
+		m.addTags(id, tags...)
+
+		response.FlowLogIds = append(response.FlowLogIds, s(id))
+	}
+
+	return response, nil
+}
+
+func (m *MockEC2) DescribeFlowLogs(request *ec2.DescribeFlowLogsInput) (*ec2.DescribeFlowLogsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeFlowLogs: %v", request)
+
+	var flowLogs []*ec2.FlowLog
+	for id, flowLog := range m.FlowLogs {
+		if len(request.FlowLogIds) != 0 && !sets.NewString(aws.StringValueSlice(request.FlowLogIds)...).Has(id) {
+			continue
+		}
+
+		allFiltersMatch := true
+		for _, filter := range request.Filter {
+			match := false
+			switch *filter.Name {
+			case "resource-id":
+				for _, v := range filter.Values {
+					if aws.StringValue(flowLog.ResourceId) == *v {
+						match = true
+					}
+				}
+			default:
+				if strings.HasPrefix(*filter.Name, "tag:") || *filter.Name == "tag-key" {
+					match = m.hasTag(ec2.ResourceTypeVpcFlowLog, id, filter)
+				} else {
+					return nil, fmt.Errorf("unknown filter name: %q", *filter.Name)
+				}
+			}
+
+			if !match {
+				allFiltersMatch = false
+				break
+			}
+		}
+
+		if !allFiltersMatch {
+			continue
+		}
+
+		copy := *flowLog
+		copy.Tags = m.getTags(ec2.ResourceTypeVpcFlowLog, id)
+		flowLogs = append(flowLogs, &copy)
+	}
+
+	return &ec2.DescribeFlowLogsOutput{
+		FlowLogs: flowLogs,
+	}, nil
+}
+
+func (m *MockEC2) DeleteFlowLogs(request *ec2.DeleteFlowLogsInput) (*ec2.DeleteFlowLogsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DeleteFlowLogs: %v", request)
+
+	for _, id := range aws.StringValueSlice(request.FlowLogIds) {
+		if m.FlowLogs[id] == nil {
+			return nil, fmt.Errorf("FlowLog %q not found", id)
+		}
+		delete(m.FlowLogs, id)
+	}
+
+	return &ec2.DeleteFlowLogsOutput{}, nil
+}
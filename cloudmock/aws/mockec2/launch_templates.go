@@ -277,6 +277,24 @@ func responseLaunchTemplateData(req *ec2.RequestLaunchTemplateData) *ec2.Respons
 			})
 		}
 	}
+	if req.Placement != nil {
+		resp.Placement = &ec2.LaunchTemplatePlacement{
+			GroupName:       req.Placement.GroupName,
+			PartitionNumber: req.Placement.PartitionNumber,
+			Tenancy:         req.Placement.Tenancy,
+		}
+	}
+	if req.CapacityReservationSpecification != nil {
+		resp.CapacityReservationSpecification = &ec2.LaunchTemplateCapacityReservationSpecificationResponse{
+			CapacityReservationPreference: req.CapacityReservationSpecification.CapacityReservationPreference,
+		}
+		if target := req.CapacityReservationSpecification.CapacityReservationTarget; target != nil {
+			resp.CapacityReservationSpecification.CapacityReservationTarget = &ec2.CapacityReservationTargetResponse{
+				CapacityReservationId:               target.CapacityReservationId,
+				CapacityReservationResourceGroupArn: target.CapacityReservationResourceGroupArn,
+			}
+		}
+	}
 	if len(req.TagSpecifications) > 0 {
 		for _, x := range req.TagSpecifications {
 			resp.TagSpecifications = append(resp.TagSpecifications, &ec2.LaunchTemplateTagSpecification{
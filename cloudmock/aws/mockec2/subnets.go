@@ -74,6 +74,18 @@ func (m *MockEC2) CreateSubnetWithId(request *ec2.CreateSubnetInput, id string)
 		AvailabilityZone: request.AvailabilityZone,
 	}
 
+	if request.Ipv6CidrBlock != nil {
+		subnet.Ipv6CidrBlockAssociationSet = []*ec2.SubnetIpv6CidrBlockAssociation{
+			{
+				AssociationId: aws.String(id + "-ipv6"),
+				Ipv6CidrBlock: request.Ipv6CidrBlock,
+				Ipv6CidrBlockState: &ec2.SubnetCidrBlockState{
+					State: aws.String(ec2.SubnetCidrBlockStateCodeAssociated),
+				},
+			},
+		}
+	}
+
 	if m.subnets == nil {
 		m.subnets = make(map[string]*subnetInfo)
 	}
@@ -234,3 +246,32 @@ func (m *MockEC2) DeleteSubnetWithContext(aws.Context, *ec2.DeleteSubnetInput, .
 func (m *MockEC2) DeleteSubnetRequest(*ec2.DeleteSubnetInput) (*request.Request, *ec2.DeleteSubnetOutput) {
 	panic("Not implemented")
 }
+
+func (m *MockEC2) ModifySubnetAttribute(request *ec2.ModifySubnetAttributeInput) (*ec2.ModifySubnetAttributeOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("ModifySubnetAttribute: %v", request)
+
+	subnet := m.subnets[aws.StringValue(request.SubnetId)]
+	if subnet == nil {
+		return nil, fmt.Errorf("subnet not found")
+	}
+
+	if request.AssignIpv6AddressOnCreation != nil {
+		subnet.main.AssignIpv6AddressOnCreation = request.AssignIpv6AddressOnCreation.Value
+	}
+	if request.MapPublicIpOnLaunch != nil {
+		subnet.main.MapPublicIpOnLaunch = request.MapPublicIpOnLaunch.Value
+	}
+
+	return &ec2.ModifySubnetAttributeOutput{}, nil
+}
+
+func (m *MockEC2) ModifySubnetAttributeWithContext(aws.Context, *ec2.ModifySubnetAttributeInput, ...request.Option) (*ec2.ModifySubnetAttributeOutput, error) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) ModifySubnetAttributeRequest(*ec2.ModifySubnetAttributeInput) (*request.Request, *ec2.ModifySubnetAttributeOutput) {
+	panic("Not implemented")
+}
@@ -73,6 +73,12 @@ func (m *MockEC2) addTags(resourceId string, tags ...*ec2.Tag) {
 		resourceType = ec2.ResourceTypeLaunchTemplate
 	} else if strings.HasPrefix(resourceId, "key-") {
 		resourceType = ec2.ResourceTypeKeyPair
+	} else if strings.HasPrefix(resourceId, "pg-") {
+		resourceType = ec2.ResourceTypePlacementGroup
+	} else if strings.HasPrefix(resourceId, "vpce-") {
+		resourceType = ResourceTypeVpcEndpoint
+	} else if strings.HasPrefix(resourceId, "fl-") {
+		resourceType = ec2.ResourceTypeVpcFlowLog
 	} else {
 		klog.Fatalf("Unknown resource-type in create tags: %v", resourceId)
 	}
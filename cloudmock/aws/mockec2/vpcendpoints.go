@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockec2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// ResourceTypeVpcEndpoint is the EC2 resource type of VPC endpoints, which is missing from the enum of the SDK
+const ResourceTypeVpcEndpoint = "vpc-endpoint"
+
+func (m *MockEC2) CreateVpcEndpoint(request *ec2.CreateVpcEndpointInput) (*ec2.CreateVpcEndpointOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("CreateVpcEndpoint: %v", request)
+
+	id := m.allocateId("vpce")
+	tags := tagSpecificationsToTags(request.TagSpecifications, ResourceTypeVpcEndpoint)
+
+	endpoint := &ec2.VpcEndpoint{
+		VpcEndpointId:     s(id),
+		VpcId:             request.VpcId,
+		ServiceName:       request.ServiceName,
+		VpcEndpointType:   request.VpcEndpointType,
+		State:             s("available"),
+		RouteTableIds:     request.RouteTableIds,
+		SubnetIds:         request.SubnetIds,
+		PrivateDnsEnabled: request.PrivateDnsEnabled,
+	}
+	if endpoint.VpcEndpointType == nil {
+		endpoint.VpcEndpointType = s(ec2.VpcEndpointTypeGateway)
+	}
+	for _, id := range request.SecurityGroupIds {
+		endpoint.Groups = append(endpoint.Groups, &ec2.SecurityGroupIdentifier{GroupId: id})
+	}
+
+	if m.VpcEndpoints == nil {
+		m.VpcEndpoints = make(map[string]*ec2.VpcEndpoint)
+	}
+	m.VpcEndpoints[id] = endpoint
+
+	m.addTags(id, tags...)
+
+	copy := *endpoint
+	copy.Tags = m.getTags(ResourceTypeVpcEndpoint, id)
+	return &ec2.CreateVpcEndpointOutput{VpcEndpoint: &copy}, nil
+}
+
+func (m *MockEC2) DescribeVpcEndpoints(request *ec2.DescribeVpcEndpointsInput) (*ec2.DescribeVpcEndpointsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeVpcEndpoints: %v", request)
+
+	var endpoints []*ec2.VpcEndpoint
+	for id, endpoint := range m.VpcEndpoints {
+		if len(request.VpcEndpointIds) != 0 && !sets.NewString(aws.StringValueSlice(request.VpcEndpointIds)...).Has(id) {
+			continue
+		}
+
+		allFiltersMatch := true
+		for _, filter := range request.Filters {
+			match := false
+			switch *filter.Name {
+			case "vpc-id":
+				for _, v := range filter.Values {
+					if aws.StringValue(endpoint.VpcId) == *v {
+						match = true
+					}
+				}
+			case "service-name":
+				for _, v := range filter.Values {
+					if aws.StringValue(endpoint.ServiceName) == *v {
+						match = true
+					}
+				}
+			default:
+				if strings.HasPrefix(*filter.Name, "tag:") || *filter.Name == "tag-key" {
+					match = m.hasTag(ResourceTypeVpcEndpoint, id, filter)
+				} else {
+					return nil, fmt.Errorf("unknown filter name: %q", *filter.Name)
+				}
+			}
+
+			if !match {
+				allFiltersMatch = false
+				break
+			}
+		}
+
+		if !allFiltersMatch {
+			continue
+		}
+
+		copy := *endpoint
+		copy.Tags = m.getTags(ResourceTypeVpcEndpoint, id)
+		endpoints = append(endpoints, &copy)
+	}
+
+	return &ec2.DescribeVpcEndpointsOutput{
+		VpcEndpoints: endpoints,
+	}, nil
+}
+
+func (m *MockEC2) ModifyVpcEndpoint(request *ec2.ModifyVpcEndpointInput) (*ec2.ModifyVpcEndpointOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("ModifyVpcEndpoint: %v", request)
+
+	id := aws.StringValue(request.VpcEndpointId)
+	endpoint := m.VpcEndpoints[id]
+	if endpoint == nil {
+		return nil, fmt.Errorf("VpcEndpoint %q not found", id)
+	}
+
+	endpoint.RouteTableIds = modifyIds(endpoint.RouteTableIds, request.AddRouteTableIds, request.RemoveRouteTableIds)
+	endpoint.SubnetIds = modifyIds(endpoint.SubnetIds, request.AddSubnetIds, request.RemoveSubnetIds)
+
+	var groupIds []*string
+	for _, group := range endpoint.Groups {
+		groupIds = append(groupIds, group.GroupId)
+	}
+	endpoint.Groups = nil
+	for _, id := range modifyIds(groupIds, request.AddSecurityGroupIds, request.RemoveSecurityGroupIds) {
+		endpoint.Groups = append(endpoint.Groups, &ec2.SecurityGroupIdentifier{GroupId: id})
+	}
+
+	if request.PrivateDnsEnabled != nil {
+		endpoint.PrivateDnsEnabled = request.PrivateDnsEnabled
+	}
+
+	return &ec2.ModifyVpcEndpointOutput{Return: aws.Bool(true)}, nil
+}
+
+func modifyIds(ids []*string, add []*string, remove []*string) []*string {
+	removed := sets.NewString(aws.StringValueSlice(remove)...)
+	var result []*string
+	for _, id := range ids {
+		if !removed.Has(aws.StringValue(id)) {
+			result = append(result, id)
+		}
+	}
+	return append(result, add...)
+}
+
+func (m *MockEC2) DeleteVpcEndpoints(request *ec2.DeleteVpcEndpointsInput) (*ec2.DeleteVpcEndpointsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DeleteVpcEndpoints: %v", request)
+
+	for _, id := range aws.StringValueSlice(request.VpcEndpointIds) {
+		if m.VpcEndpoints[id] == nil {
+			return nil, fmt.Errorf("VpcEndpoint %q not found", id)
+		}
+		delete(m.VpcEndpoints, id)
+	}
+
+	return &ec2.DeleteVpcEndpointsOutput{}, nil
+}
@@ -70,6 +70,10 @@ func (m *MockEC2) CreateVpcWithId(request *ec2.CreateVpcInput, id string) (*ec2.
 		},
 	}
 
+	if aws.BoolValue(request.AmazonProvidedIpv6CidrBlock) {
+		vpc.main.Ipv6CidrBlockAssociationSet = append(vpc.main.Ipv6CidrBlockAssociationSet, m.amazonIPv6Association(id, 0))
+	}
+
 	if m.Vpcs == nil {
 		m.Vpcs = make(map[string]*vpcInfo)
 	}
@@ -243,6 +247,14 @@ func (m *MockEC2) AssociateVpcCidrBlock(request *ec2.AssociateVpcCidrBlockInput)
 	if !ok {
 		return nil, fmt.Errorf("VPC %q not found", id)
 	}
+	if aws.BoolValue(request.AmazonProvidedIpv6CidrBlock) {
+		association := m.amazonIPv6Association(id, len(vpc.main.Ipv6CidrBlockAssociationSet))
+		vpc.main.Ipv6CidrBlockAssociationSet = append(vpc.main.Ipv6CidrBlockAssociationSet, association)
+		return &ec2.AssociateVpcCidrBlockOutput{
+			Ipv6CidrBlockAssociation: association,
+			VpcId:                    request.VpcId,
+		}, nil
+	}
 	association := &ec2.VpcCidrBlockAssociation{
 		CidrBlock:     request.CidrBlock,
 		AssociationId: aws.String(fmt.Sprintf("%v-%v", id, len(vpc.main.CidrBlockAssociationSet))),
@@ -286,3 +298,16 @@ func (m *MockEC2) DisassociateVpcCidrBlock(request *ec2.DisassociateVpcCidrBlock
 		VpcId:                vpcID,
 	}, nil
 }
+
+// amazonIPv6Association allocates an IPv6 CIDR for a VPC, from the documentation prefix
+func (m *MockEC2) amazonIPv6Association(vpcID string, index int) *ec2.VpcIpv6CidrBlockAssociation {
+	m.ipv6CIDRCount++
+	return &ec2.VpcIpv6CidrBlockAssociation{
+		AssociationId: aws.String(fmt.Sprintf("%v-ipv6-%v", vpcID, index)),
+		Ipv6CidrBlock: aws.String(fmt.Sprintf("2001:db8:0:%x00::/56", m.ipv6CIDRCount)),
+		Ipv6Pool:      aws.String("Amazon"),
+		Ipv6CidrBlockState: &ec2.VpcCidrBlockState{
+			State: aws.String(ec2.VpcCidrBlockStateCodeAssociated),
+		},
+	}
+}
@@ -56,6 +56,7 @@ func (m *MockIAM) CreateRole(request *iam.CreateRoleInput) (*iam.CreateRoleOutpu
 
 	roleID := m.createID()
 	r := &iam.Role{
+		Arn:                      aws.String(fmt.Sprintf("arn:aws:iam::0000000000:role/%s", aws.StringValue(request.RoleName))),
 		AssumeRolePolicyDocument: request.AssumeRolePolicyDocument,
 		Description:              request.Description,
 		Path:                     request.Path,
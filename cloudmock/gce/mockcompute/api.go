@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcompute
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	compute "google.golang.org/api/compute/v1"
+)
+
+const (
+	// basePath is the path the compute API is served under
+	basePath = "/compute/v1/"
+
+	// selfLinkPrefix is the prefix of the self links of all resources, matching the real API
+	selfLinkPrefix = "https://www.googleapis.com/compute/v1/"
+)
+
+// MockClient represents a mocked GCE compute API
+type MockClient struct {
+	Mux    *http.ServeMux
+	Server *httptest.Server
+
+	mutex  sync.Mutex
+	lastID uint64
+
+	// zones maps zone names to region names
+	zones                 map[string]string
+	instanceTemplates     map[string]*compute.InstanceTemplate
+	instanceGroupManagers map[string]*compute.InstanceGroupManager
+	instances             map[string]*compute.Instance
+	// managedInstances maps instance keys to the state the owning instance group manager keeps about them
+	managedInstances map[string]*managedInstance
+}
+
+// managedInstance is an instance that is a member of an instance group manager
+type managedInstance struct {
+	// instanceGroupManager is the key of the owning instance group manager
+	instanceGroupManager string
+	// instanceTemplate is the URL of the template the instance was created from
+	instanceTemplate string
+}
+
+// CreateClient will create a new mock GCE compute API server
+func CreateClient() *MockClient {
+	m := &MockClient{}
+	m.Mux = http.NewServeMux()
+	m.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotImplemented)
+		panic(fmt.Sprintf("Unhandled mock request: %+v\n", r))
+	})
+	m.Mux.HandleFunc(basePath+"projects/", m.route)
+	m.Reset()
+	m.Server = httptest.NewServer(m.Mux)
+	return m
+}
+
+// Reset will empty the state of the mock data
+func (m *MockClient) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.zones = make(map[string]string)
+	m.instanceTemplates = make(map[string]*compute.InstanceTemplate)
+	m.instanceGroupManagers = make(map[string]*compute.InstanceGroupManager)
+	m.instances = make(map[string]*compute.Instance)
+	m.managedInstances = make(map[string]*managedInstance)
+}
+
+// Endpoint returns the URL the compute client should be pointed at
+func (m *MockClient) Endpoint() string {
+	return m.Server.URL + basePath
+}
+
+// TeardownHTTP releases HTTP-related resources.
+func (m *MockClient) TeardownHTTP() {
+	m.Server.Close()
+}
+
+// AddZone registers a zone in the specified region
+func (m *MockClient) AddZone(region string, zone string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.zones[zone] = region
+}
+
+// route dispatches requests on projects/<project>/... to the resource handlers.
+func (m *MockClient) route(w http.ResponseWriter, r *http.Request) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	w.Header().Add("Content-Type", "application/json")
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, basePath), "/"), "/")
+	if len(parts) < 3 || parts[0] != "projects" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown path %q", r.URL.Path))
+		return
+	}
+	project := parts[1]
+
+	switch {
+	case len(parts) == 3 && parts[2] == "zones":
+		m.listZones(w, r, project)
+	case len(parts) >= 4 && parts[2] == "global" && parts[3] == "instanceTemplates":
+		m.handleInstanceTemplates(w, r, project, parts[4:])
+	case len(parts) >= 5 && parts[2] == "zones" && parts[4] == "instanceGroupManagers":
+		m.handleInstanceGroupManagers(w, r, project, parts[3], parts[5:])
+	case len(parts) >= 5 && parts[2] == "zones" && parts[4] == "instances":
+		m.handleInstances(w, r, project, parts[3], parts[5:])
+	default:
+		writeError(w, http.StatusNotImplemented, fmt.Sprintf("unhandled path %q", r.URL.Path))
+	}
+}
+
+func (m *MockClient) listZones(w http.ResponseWriter, r *http.Request, project string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var names []string
+	for name := range m.zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp := &compute.ZoneList{}
+	for _, name := range names {
+		resp.Items = append(resp.Items, &compute.Zone{
+			Name:     name,
+			Region:   selfLinkPrefix + "projects/" + project + "/regions/" + m.zones[name],
+			SelfLink: zoneURL(project, name),
+			Status:   "UP",
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (m *MockClient) nextID() uint64 {
+	m.lastID++
+	return m.lastID
+}
+
+// doneOperation returns an operation that has already finished successfully,
+// so callers waiting for it never need to poll
+func (m *MockClient) doneOperation(project string, zone string, operationType string, targetLink string) *compute.Operation {
+	id := m.nextID()
+	name := fmt.Sprintf("operation-%d", id)
+
+	op := &compute.Operation{
+		Id:            id,
+		Name:          name,
+		OperationType: operationType,
+		TargetLink:    targetLink,
+		Status:        "DONE",
+		Progress:      100,
+	}
+	if zone != "" {
+		op.Zone = zoneURL(project, zone)
+		op.SelfLink = op.Zone + "/operations/" + name
+	} else {
+		op.SelfLink = selfLinkPrefix + "projects/" + project + "/global/operations/" + name
+	}
+	return op
+}
+
+func zoneURL(project string, zone string) string {
+	return selfLinkPrefix + "projects/" + project + "/zones/" + zone
+}
+
+// lastComponent returns the last component of a URL, i.e. the name of a resource from its self link
+func lastComponent(s string) string {
+	return s[strings.LastIndex(s, "/")+1:]
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		panic(fmt.Sprintf("error encoding mock response: %v", err))
+	}
+}
+
+// writeError writes an error in the format the googleapi client understands
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    status,
+			"message": message,
+		},
+	})
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
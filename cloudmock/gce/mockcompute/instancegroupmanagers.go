@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcompute
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	compute "google.golang.org/api/compute/v1"
+)
+
+func (m *MockClient) handleInstanceGroupManagers(w http.ResponseWriter, r *http.Request, project string, zone string, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			resp := &compute.InstanceGroupManagerList{}
+			for _, key := range m.instanceGroupManagerKeys() {
+				if mig := m.instanceGroupManagers[key]; lastComponent(mig.Zone) == zone {
+					resp.Items = append(resp.Items, mig)
+				}
+			}
+			writeJSON(w, http.StatusOK, resp)
+
+		case http.MethodPost:
+			mig := &compute.InstanceGroupManager{}
+			if !decodeRequest(w, r, mig) {
+				return
+			}
+			key := zone + "/" + mig.Name
+			if m.instanceGroupManagers[key] != nil {
+				writeError(w, http.StatusConflict, fmt.Sprintf("instance group manager %q already exists", mig.Name))
+				return
+			}
+			mig.Id = m.nextID()
+			mig.Zone = zoneURL(project, zone)
+			mig.SelfLink = mig.Zone + "/instanceGroupManagers/" + mig.Name
+			mig.Versions = []*compute.InstanceGroupManagerVersion{{InstanceTemplate: mig.InstanceTemplate}}
+			m.instanceGroupManagers[key] = mig
+			m.reconcile(project, key)
+			writeJSON(w, http.StatusOK, m.doneOperation(project, zone, "insert", mig.SelfLink))
+
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+
+	key := zone + "/" + parts[0]
+	mig := m.instanceGroupManagers[key]
+	if mig == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("instance group manager %q not found", parts[0]))
+		return
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, mig)
+
+		case http.MethodDelete:
+			// Deleting an instance group manager also deletes its instances
+			for _, k := range m.members(key) {
+				delete(m.managedInstances, k)
+				delete(m.instances, k)
+			}
+			delete(m.instanceGroupManagers, key)
+			writeJSON(w, http.StatusOK, m.doneOperation(project, zone, "delete", mig.SelfLink))
+
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+
+	if len(parts) != 2 || r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch parts[1] {
+	case "abandonInstances":
+		req := &compute.InstanceGroupManagersAbandonInstancesRequest{}
+		if !decodeRequest(w, r, req) {
+			return
+		}
+		for _, instance := range req.Instances {
+			k := zone + "/" + lastComponent(instance)
+			if mi := m.managedInstances[k]; mi == nil || mi.instanceGroupManager != key {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("instance %q is not a member of instance group manager %q", instance, mig.Name))
+				return
+			}
+		}
+		for _, instance := range req.Instances {
+			// The instance keeps running, but the group no longer counts it
+			delete(m.managedInstances, zone+"/"+lastComponent(instance))
+			mig.TargetSize--
+		}
+		writeJSON(w, http.StatusOK, m.doneOperation(project, zone, "compute.instanceGroupManagers.abandonInstances", mig.SelfLink))
+
+	case "recreateInstances":
+		req := &compute.InstanceGroupManagersRecreateInstancesRequest{}
+		if !decodeRequest(w, r, req) {
+			return
+		}
+		for _, instance := range req.Instances {
+			k := zone + "/" + lastComponent(instance)
+			if mi := m.managedInstances[k]; mi == nil || mi.instanceGroupManager != key {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("instance %q is not a member of instance group manager %q", instance, mig.Name))
+				return
+			}
+		}
+		for _, instance := range req.Instances {
+			// Recreated instances keep their name, but are built from the current template
+			m.createInstance(project, zone, lastComponent(instance), key)
+		}
+		writeJSON(w, http.StatusOK, m.doneOperation(project, zone, "compute.instanceGroupManagers.recreateInstances", mig.SelfLink))
+
+	case "resize":
+		size, err := strconv.ParseInt(r.URL.Query().Get("size"), 10, 64)
+		if err != nil || size < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid size %q", r.URL.Query().Get("size")))
+			return
+		}
+		mig.TargetSize = size
+		m.reconcile(project, key)
+		writeJSON(w, http.StatusOK, m.doneOperation(project, zone, "compute.instanceGroupManagers.resize", mig.SelfLink))
+
+	case "setInstanceTemplate":
+		req := &compute.InstanceGroupManagersSetInstanceTemplateRequest{}
+		if !decodeRequest(w, r, req) {
+			return
+		}
+		mig.InstanceTemplate = req.InstanceTemplate
+		mig.Versions = []*compute.InstanceGroupManagerVersion{{InstanceTemplate: req.InstanceTemplate}}
+		writeJSON(w, http.StatusOK, m.doneOperation(project, zone, "compute.instanceGroupManagers.setInstanceTemplate", mig.SelfLink))
+
+	case "listManagedInstances":
+		resp := &compute.InstanceGroupManagersListManagedInstancesResponse{}
+		for _, k := range m.members(key) {
+			instance := m.instances[k]
+			resp.ManagedInstances = append(resp.ManagedInstances, &compute.ManagedInstance{
+				Id:             instance.Id,
+				Instance:       instance.SelfLink,
+				InstanceStatus: instance.Status,
+				CurrentAction:  "NONE",
+				Version: &compute.ManagedInstanceVersion{
+					InstanceTemplate: m.managedInstances[k].instanceTemplate,
+				},
+			})
+		}
+		writeJSON(w, http.StatusOK, resp)
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (m *MockClient) instanceGroupManagerKeys() []string {
+	var keys []string
+	for key := range m.instanceGroupManagers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// members returns the sorted keys of the instances managed by the instance group manager
+func (m *MockClient) members(migKey string) []string {
+	var keys []string
+	for key, mi := range m.managedInstances {
+		if mi.instanceGroupManager == migKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reconcile creates or deletes instances until the instance group manager reaches its target size
+func (m *MockClient) reconcile(project string, migKey string) {
+	mig := m.instanceGroupManagers[migKey]
+	zone := lastComponent(mig.Zone)
+
+	members := m.members(migKey)
+	for i := int64(len(members)); i < mig.TargetSize; i++ {
+		name := fmt.Sprintf("%s-%04d", mig.BaseInstanceName, m.nextID())
+		m.createInstance(project, zone, name, migKey)
+	}
+	for i := int64(len(members)); i > mig.TargetSize; i-- {
+		k := members[i-1]
+		delete(m.managedInstances, k)
+		delete(m.instances, k)
+	}
+}
+
+// createInstance (re)creates an instance from the current template of the instance group manager
+func (m *MockClient) createInstance(project string, zone string, name string, migKey string) {
+	mig := m.instanceGroupManagers[migKey]
+
+	labels := make(map[string]string)
+	if t := m.instanceTemplates[lastComponent(mig.InstanceTemplate)]; t != nil && t.Properties != nil {
+		for k, v := range t.Properties.Labels {
+			labels[k] = v
+		}
+	}
+
+	id := m.nextID()
+	key := zone + "/" + name
+	m.instances[key] = &compute.Instance{
+		Id:               id,
+		Name:             name,
+		Zone:             zoneURL(project, zone),
+		SelfLink:         zoneURL(project, zone) + "/instances/" + name,
+		Status:           "RUNNING",
+		Labels:           labels,
+		LabelFingerprint: strconv.FormatUint(id, 10),
+	}
+	m.managedInstances[key] = &managedInstance{
+		instanceGroupManager: migKey,
+		instanceTemplate:     mig.InstanceTemplate,
+	}
+}
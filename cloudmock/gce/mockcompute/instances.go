@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcompute
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+
+	compute "google.golang.org/api/compute/v1"
+)
+
+// labelFilter matches the only filter expression the mock understands: labels.<key> = "<value>"
+var labelFilter = regexp.MustCompile(`^labels\.([a-z0-9_-]+)\s*=\s*"?([a-z0-9_-]*)"?$`)
+
+func (m *MockClient) handleInstances(w http.ResponseWriter, r *http.Request, project string, zone string, parts []string) {
+	if len(parts) == 0 {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		filterKey, filterValue := "", ""
+		if filter := r.URL.Query().Get("filter"); filter != "" {
+			match := labelFilter.FindStringSubmatch(filter)
+			if match == nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported filter %q", filter))
+				return
+			}
+			filterKey, filterValue = match[1], match[2]
+		}
+
+		var keys []string
+		for key := range m.instances {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		resp := &compute.InstanceList{}
+		for _, key := range keys {
+			instance := m.instances[key]
+			if lastComponent(instance.Zone) != zone {
+				continue
+			}
+			if filterKey != "" && instance.Labels[filterKey] != filterValue {
+				continue
+			}
+			resp.Items = append(resp.Items, instance)
+		}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	key := zone + "/" + parts[0]
+	instance := m.instances[key]
+	if instance == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("instance %q not found", parts[0]))
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, instance)
+
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		delete(m.instances, key)
+		if mi := m.managedInstances[key]; mi != nil {
+			// The instance group manager replaces members that are deleted behind its back
+			delete(m.managedInstances, key)
+			m.reconcile(project, mi.instanceGroupManager)
+		}
+		writeJSON(w, http.StatusOK, m.doneOperation(project, zone, "delete", instance.SelfLink))
+
+	case len(parts) == 2 && parts[1] == "setLabels" && r.Method == http.MethodPost:
+		req := &compute.InstancesSetLabelsRequest{}
+		if !decodeRequest(w, r, req) {
+			return
+		}
+		if req.LabelFingerprint != instance.LabelFingerprint {
+			writeError(w, http.StatusPreconditionFailed, fmt.Sprintf("label fingerprint of instance %q does not match", instance.Name))
+			return
+		}
+		instance.Labels = req.Labels
+		instance.LabelFingerprint = strconv.FormatUint(m.nextID(), 10)
+		writeJSON(w, http.StatusOK, m.doneOperation(project, zone, "setLabels", instance.SelfLink))
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
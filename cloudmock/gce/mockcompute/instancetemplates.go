@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcompute
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	compute "google.golang.org/api/compute/v1"
+)
+
+// InstanceTemplates returns the names of all instance templates
+func (m *MockClient) InstanceTemplates() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.instanceTemplateNames()
+}
+
+func (m *MockClient) handleInstanceTemplates(w http.ResponseWriter, r *http.Request, project string, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		resp := &compute.InstanceTemplateList{}
+		for _, name := range m.instanceTemplateNames() {
+			resp.Items = append(resp.Items, m.instanceTemplates[name])
+		}
+		writeJSON(w, http.StatusOK, resp)
+
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		t := &compute.InstanceTemplate{}
+		if !decodeRequest(w, r, t) {
+			return
+		}
+		if m.instanceTemplates[t.Name] != nil {
+			writeError(w, http.StatusConflict, fmt.Sprintf("instance template %q already exists", t.Name))
+			return
+		}
+		t.Id = m.nextID()
+		t.SelfLink = selfLinkPrefix + "projects/" + project + "/global/instanceTemplates/" + t.Name
+		m.instanceTemplates[t.Name] = t
+		writeJSON(w, http.StatusOK, m.doneOperation(project, "", "insert", t.SelfLink))
+
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		t := m.instanceTemplates[parts[0]]
+		if t == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("instance template %q not found", parts[0]))
+			return
+		}
+		writeJSON(w, http.StatusOK, t)
+
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		t := m.instanceTemplates[parts[0]]
+		if t == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("instance template %q not found", parts[0]))
+			return
+		}
+		// Like GCE, refuse to delete templates that are still used by an instance group manager
+		for _, mig := range m.instanceGroupManagers {
+			if mig.InstanceTemplate == t.SelfLink {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("instance template %q is in use by instance group manager %q", t.Name, mig.Name))
+				return
+			}
+		}
+		delete(m.instanceTemplates, t.Name)
+		writeJSON(w, http.StatusOK, m.doneOperation(project, "", "delete", t.SelfLink))
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (m *MockClient) instanceTemplateNames() []string {
+	var names []string
+	for name := range m.instanceTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
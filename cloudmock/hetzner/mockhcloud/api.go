@@ -0,0 +1,323 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockhcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/hcloud/schema"
+)
+
+// MockClient represents a mocked Hetzner Cloud API
+type MockClient struct {
+	Mux    *http.ServeMux
+	Server *httptest.Server
+
+	mutex  sync.Mutex
+	lastID int
+
+	actions         map[int]schema.Action
+	sshKeys         map[int]schema.SSHKey
+	networks        map[int]schema.Network
+	firewalls       map[int]schema.Firewall
+	placementGroups map[int]schema.PlacementGroup
+	servers         map[int]schema.Server
+	loadBalancers   map[int]schema.LoadBalancer
+	volumes         map[int]schema.Volume
+}
+
+// CreateClient will create a new mock Hetzner Cloud API server
+func CreateClient() *MockClient {
+	m := &MockClient{}
+	m.Mux = http.NewServeMux()
+	m.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotImplemented)
+		panic(fmt.Sprintf("Unhandled mock request: %+v\n", r))
+	})
+	m.Reset()
+	m.mockActions()
+	m.mockSSHKeys()
+	m.mockNetworks()
+	m.mockFirewalls()
+	m.mockPlacementGroups()
+	m.mockServers()
+	m.mockLoadBalancers()
+	m.mockVolumes()
+	m.Server = httptest.NewServer(m.Mux)
+	return m
+}
+
+// Reset will empty the state of the mock data
+func (m *MockClient) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.actions = make(map[int]schema.Action)
+	m.sshKeys = make(map[int]schema.SSHKey)
+	m.networks = make(map[int]schema.Network)
+	m.firewalls = make(map[int]schema.Firewall)
+	m.placementGroups = make(map[int]schema.PlacementGroup)
+	m.servers = make(map[int]schema.Server)
+	m.loadBalancers = make(map[int]schema.LoadBalancer)
+	m.volumes = make(map[int]schema.Volume)
+}
+
+// Endpoint returns the URL the hcloud client should be pointed at
+func (m *MockClient) Endpoint() string {
+	return m.Server.URL
+}
+
+// TeardownHTTP releases HTTP-related resources.
+func (m *MockClient) TeardownHTTP() {
+	m.Server.Close()
+}
+
+// All returns a map of all resource IDs to their resources
+func (m *MockClient) All() map[string]interface{} {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	all := make(map[string]interface{})
+	for id, r := range m.sshKeys {
+		all["ssh-key:"+strconv.Itoa(id)] = r
+	}
+	for id, r := range m.networks {
+		all["network:"+strconv.Itoa(id)] = r
+	}
+	for id, r := range m.firewalls {
+		all["firewall:"+strconv.Itoa(id)] = r
+	}
+	for id, r := range m.placementGroups {
+		all["placement-group:"+strconv.Itoa(id)] = r
+	}
+	for id, r := range m.servers {
+		all["server:"+strconv.Itoa(id)] = r
+	}
+	for id, r := range m.loadBalancers {
+		all["load-balancer:"+strconv.Itoa(id)] = r
+	}
+	for id, r := range m.volumes {
+		all["volume:"+strconv.Itoa(id)] = r
+	}
+	return all
+}
+
+// collectionHandler describes how the requests to a resource collection are served.
+// All handlers are called with the mutex held.
+type collectionHandler struct {
+	list   func(w http.ResponseWriter, r *http.Request)
+	create func(w http.ResponseWriter, r *http.Request)
+	get    func(w http.ResponseWriter, id int)
+	update func(w http.ResponseWriter, r *http.Request, id int)
+	delete func(w http.ResponseWriter, id int)
+	action func(w http.ResponseWriter, r *http.Request, id int, action string)
+}
+
+// handleCollection routes /<collection>, /<collection>/<id> and /<collection>/<id>/actions/<action>
+func (m *MockClient) handleCollection(collection string, h collectionHandler) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		w.Header().Add("Content-Type", "application/json")
+
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) == 1 {
+			switch {
+			case r.Method == http.MethodGet && h.list != nil:
+				h.list(w, r)
+			case r.Method == http.MethodPost && h.create != nil:
+				h.create(w, r)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			return
+		}
+
+		id, err := strconv.Atoi(parts[1])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_input", fmt.Sprintf("invalid id %q", parts[1]))
+			return
+		}
+
+		if len(parts) == 4 && parts[2] == "actions" && r.Method == http.MethodPost && h.action != nil {
+			h.action(w, r, id, parts[3])
+			return
+		}
+		if len(parts) != 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && h.get != nil:
+			h.get(w, id)
+		case r.Method == http.MethodPut && h.update != nil:
+			h.update(w, r, id)
+		case r.Method == http.MethodDelete && h.delete != nil:
+			h.delete(w, id)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}
+	m.Mux.HandleFunc("/"+collection, handler)
+	m.Mux.HandleFunc("/"+collection+"/", handler)
+}
+
+func (m *MockClient) nextID() int {
+	m.lastID++
+	return m.lastID
+}
+
+// newAction records an action that has already finished successfully
+func (m *MockClient) newAction(command string, resourceType string, resourceID int) schema.Action {
+	now := time.Now()
+	a := schema.Action{
+		ID:       m.nextID(),
+		Status:   "success",
+		Command:  command,
+		Progress: 100,
+		Started:  now,
+		Finished: &now,
+		Resources: []schema.ActionResourceReference{
+			{ID: resourceID, Type: resourceType},
+		},
+	}
+	m.actions[a.ID] = a
+	return a
+}
+
+func (m *MockClient) mockActions() {
+	m.handleCollection("actions", collectionHandler{
+		list: func(w http.ResponseWriter, r *http.Request) {
+			resp := schema.ActionListResponse{Actions: []schema.Action{}}
+			for _, idValue := range r.URL.Query()["id"] {
+				id, err := strconv.Atoi(idValue)
+				if err != nil {
+					writeError(w, http.StatusBadRequest, "invalid_input", fmt.Sprintf("invalid id %q", idValue))
+					return
+				}
+				if a, ok := m.actions[id]; ok {
+					resp.Actions = append(resp.Actions, a)
+				}
+			}
+			writeJSON(w, http.StatusOK, resp)
+		},
+		get: func(w http.ResponseWriter, id int) {
+			a, ok := m.actions[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, schema.ActionGetResponse{Action: a})
+		},
+	})
+}
+
+// matchesListOptions implements the name and label_selector filters of the list calls
+func matchesListOptions(r *http.Request, name string, labels map[string]string) bool {
+	query := r.URL.Query()
+	if n := query.Get("name"); n != "" && n != name {
+		return false
+	}
+	return matchesLabelSelector(query.Get("label_selector"), labels)
+}
+
+// matchesLabelSelector supports the "key=value", "key!=value", "key" and "!key" expressions
+func matchesLabelSelector(selector string, labels map[string]string) bool {
+	if selector == "" {
+		return true
+	}
+	for _, expr := range strings.Split(selector, ",") {
+		expr = strings.TrimSpace(expr)
+		switch {
+		case strings.Contains(expr, "!="):
+			kv := strings.SplitN(expr, "!=", 2)
+			if labels[kv[0]] == kv[1] {
+				return false
+			}
+		case strings.Contains(expr, "="):
+			kv := strings.SplitN(expr, "=", 2)
+			if v, ok := labels[kv[0]]; !ok || v != kv[1] {
+				return false
+			}
+		case strings.HasPrefix(expr, "!"):
+			if _, ok := labels[strings.TrimPrefix(expr, "!")]; ok {
+				return false
+			}
+		default:
+			if _, ok := labels[expr]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func decodeRequest(r *http.Request, v interface{}) {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		panic(fmt.Sprintf("error decoding request %s %s: %v", r.Method, r.URL.Path, err))
+	}
+}
+
+func labelsValue(labels *map[string]string) map[string]string {
+	result := make(map[string]string)
+	if labels != nil {
+		for k, v := range *labels {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal %+v", v))
+	}
+	w.WriteHeader(status)
+	if _, err := w.Write(b); err != nil {
+		panic("failed to write body")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, schema.ErrorResponse{
+		Error: schema.Error{
+			Code:       code,
+			Message:    message,
+			DetailsRaw: json.RawMessage("null"),
+		},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "not_found", "resource not found")
+}
+
+func writeNoContent(w http.ResponseWriter) {
+	// The client only parses the body of JSON responses
+	w.Header().Del("Content-Type")
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockhcloud
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/hcloud/schema"
+)
+
+func (m *MockClient) mockFirewalls() {
+	m.handleCollection("firewalls", collectionHandler{
+		list: func(w http.ResponseWriter, r *http.Request) {
+			resp := schema.FirewallListResponse{Firewalls: []schema.Firewall{}}
+			for _, f := range m.firewalls {
+				if matchesListOptions(r, f.Name, f.Labels) {
+					resp.Firewalls = append(resp.Firewalls, f)
+				}
+			}
+			writeJSON(w, http.StatusOK, resp)
+		},
+		create: func(w http.ResponseWriter, r *http.Request) {
+			var req schema.FirewallCreateRequest
+			decodeRequest(r, &req)
+
+			f := schema.Firewall{
+				ID:        m.nextID(),
+				Name:      req.Name,
+				Labels:    labelsValue(req.Labels),
+				Created:   time.Now(),
+				Rules:     req.Rules,
+				AppliedTo: req.ApplyTo,
+			}
+			if f.Rules == nil {
+				f.Rules = []schema.FirewallRule{}
+			}
+			if f.AppliedTo == nil {
+				f.AppliedTo = []schema.FirewallResource{}
+			}
+			m.firewalls[f.ID] = f
+
+			resp := schema.FirewallCreateResponse{
+				Firewall: f,
+				Actions:  []schema.Action{m.newAction("set_firewall_rules", "firewall", f.ID)},
+			}
+			if len(f.AppliedTo) != 0 {
+				resp.Actions = append(resp.Actions, m.newAction("apply_firewall", "firewall", f.ID))
+			}
+			writeJSON(w, http.StatusCreated, resp)
+		},
+		get: func(w http.ResponseWriter, id int) {
+			f, ok := m.firewalls[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, schema.FirewallGetResponse{Firewall: f})
+		},
+		update: func(w http.ResponseWriter, r *http.Request, id int) {
+			f, ok := m.firewalls[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			var req schema.FirewallUpdateRequest
+			decodeRequest(r, &req)
+			if req.Name != nil {
+				f.Name = *req.Name
+			}
+			if req.Labels != nil {
+				f.Labels = labelsValue(req.Labels)
+			}
+			m.firewalls[id] = f
+			writeJSON(w, http.StatusOK, schema.FirewallUpdateResponse{Firewall: f})
+		},
+		delete: func(w http.ResponseWriter, id int) {
+			if _, ok := m.firewalls[id]; !ok {
+				writeNotFound(w)
+				return
+			}
+			delete(m.firewalls, id)
+			writeNoContent(w)
+		},
+		action: func(w http.ResponseWriter, r *http.Request, id int, action string) {
+			f, ok := m.firewalls[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			switch action {
+			case "set_rules":
+				var req schema.FirewallActionSetRulesRequest
+				decodeRequest(r, &req)
+				f.Rules = req.Rules
+			case "apply_to_resources":
+				var req schema.FirewallActionApplyToResourcesRequest
+				decodeRequest(r, &req)
+				f.AppliedTo = append(f.AppliedTo, req.ApplyTo...)
+			case "remove_from_resources":
+				var req schema.FirewallActionRemoveFromResourcesRequest
+				decodeRequest(r, &req)
+				var appliedTo []schema.FirewallResource
+				for _, resource := range f.AppliedTo {
+					if !containsFirewallResource(req.RemoveFrom, resource) {
+						appliedTo = append(appliedTo, resource)
+					}
+				}
+				f.AppliedTo = appliedTo
+			default:
+				writeError(w, http.StatusBadRequest, "invalid_input", fmt.Sprintf("unsupported firewall action %q", action))
+				return
+			}
+			m.firewalls[id] = f
+			writeJSON(w, http.StatusCreated, schema.ActionListResponse{
+				Actions: []schema.Action{m.newAction(action, "firewall", id)},
+			})
+		},
+	})
+}
+
+func containsFirewallResource(resources []schema.FirewallResource, resource schema.FirewallResource) bool {
+	for _, r := range resources {
+		if r.Type != resource.Type {
+			continue
+		}
+		if r.Server != nil && resource.Server != nil && r.Server.ID == resource.Server.ID {
+			return true
+		}
+		if r.LabelSelector != nil && resource.LabelSelector != nil && r.LabelSelector.Selector == resource.LabelSelector.Selector {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockhcloud
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/hcloud/schema"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func (m *MockClient) mockLoadBalancers() {
+	m.handleCollection("load_balancers", collectionHandler{
+		list: func(w http.ResponseWriter, r *http.Request) {
+			resp := schema.LoadBalancerListResponse{LoadBalancers: []schema.LoadBalancer{}}
+			for _, lb := range m.loadBalancers {
+				if matchesListOptions(r, lb.Name, lb.Labels) {
+					resp.LoadBalancers = append(resp.LoadBalancers, lb)
+				}
+			}
+			writeJSON(w, http.StatusOK, resp)
+		},
+		create: func(w http.ResponseWriter, r *http.Request) {
+			var req schema.LoadBalancerCreateRequest
+			decodeRequest(r, &req)
+
+			id := m.nextID()
+			lb := schema.LoadBalancer{
+				ID:   id,
+				Name: req.Name,
+				PublicNet: schema.LoadBalancerPublicNet{
+					Enabled: req.PublicInterface == nil || *req.PublicInterface,
+					IPv4:    schema.LoadBalancerPublicNetIPv4{IP: fmt.Sprintf("198.51.100.%d", id%256)},
+				},
+				PrivateNet:       []schema.LoadBalancerPrivateNet{},
+				LoadBalancerType: schema.LoadBalancerType{Name: fmt.Sprintf("%v", req.LoadBalancerType)},
+				Labels:           labelsValue(req.Labels),
+				Created:          time.Now(),
+				Services:         []schema.LoadBalancerService{},
+				Targets:          []schema.LoadBalancerTarget{},
+				Algorithm:        schema.LoadBalancerAlgorithm{Type: "round_robin"},
+			}
+			if req.Location != nil {
+				lb.Location = schema.Location{Name: *req.Location}
+			}
+			if req.Algorithm != nil {
+				lb.Algorithm.Type = req.Algorithm.Type
+			}
+			if req.Network != nil {
+				lb.PrivateNet = append(lb.PrivateNet, schema.LoadBalancerPrivateNet{
+					Network: *req.Network,
+					IP:      fmt.Sprintf("10.10.255.%d", id%256),
+				})
+			}
+			for _, service := range req.Services {
+				lb.Services = append(lb.Services, newLoadBalancerService(service.Protocol, service.ListenPort, service.DestinationPort, service.Proxyprotocol))
+			}
+			for _, target := range req.Targets {
+				t := schema.LoadBalancerTarget{
+					Type:         target.Type,
+					UsePrivateIP: fi.BoolValue(target.UsePrivateIP),
+				}
+				if target.Server != nil {
+					t.Server = &schema.LoadBalancerTargetServer{ID: target.Server.ID}
+				}
+				if target.LabelSelector != nil {
+					t.LabelSelector = &schema.LoadBalancerTargetLabelSelector{Selector: target.LabelSelector.Selector}
+				}
+				lb.Targets = append(lb.Targets, t)
+			}
+			m.loadBalancers[id] = lb
+
+			writeJSON(w, http.StatusCreated, schema.LoadBalancerCreateResponse{
+				LoadBalancer: lb,
+				Action:       m.newAction("create_load_balancer", "load_balancer", id),
+			})
+		},
+		get: func(w http.ResponseWriter, id int) {
+			lb, ok := m.loadBalancers[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, schema.LoadBalancerGetResponse{LoadBalancer: lb})
+		},
+		update: func(w http.ResponseWriter, r *http.Request, id int) {
+			lb, ok := m.loadBalancers[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			var req schema.LoadBalancerUpdateRequest
+			decodeRequest(r, &req)
+			if req.Name != nil {
+				lb.Name = *req.Name
+			}
+			if req.Labels != nil {
+				lb.Labels = labelsValue(req.Labels)
+			}
+			m.loadBalancers[id] = lb
+			writeJSON(w, http.StatusOK, schema.LoadBalancerUpdateResponse{LoadBalancer: lb})
+		},
+		delete: func(w http.ResponseWriter, id int) {
+			if _, ok := m.loadBalancers[id]; !ok {
+				writeNotFound(w)
+				return
+			}
+			delete(m.loadBalancers, id)
+			writeNoContent(w)
+		},
+		action: func(w http.ResponseWriter, r *http.Request, id int, action string) {
+			lb, ok := m.loadBalancers[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			switch action {
+			case "add_service":
+				var req schema.LoadBalancerActionAddServiceRequest
+				decodeRequest(r, &req)
+				for _, service := range lb.Services {
+					if service.ListenPort == fi.IntValue(req.ListenPort) {
+						writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("listen port %d is already in use", service.ListenPort))
+						return
+					}
+				}
+				lb.Services = append(lb.Services, newLoadBalancerService(req.Protocol, req.ListenPort, req.DestinationPort, req.Proxyprotocol))
+			case "add_target":
+				var req schema.LoadBalancerActionAddTargetRequest
+				decodeRequest(r, &req)
+				t := schema.LoadBalancerTarget{
+					Type:         req.Type,
+					UsePrivateIP: fi.BoolValue(req.UsePrivateIP),
+				}
+				if req.Server != nil {
+					t.Server = &schema.LoadBalancerTargetServer{ID: req.Server.ID}
+				}
+				if req.LabelSelector != nil {
+					t.LabelSelector = &schema.LoadBalancerTargetLabelSelector{Selector: req.LabelSelector.Selector}
+				}
+				lb.Targets = append(lb.Targets, t)
+			case "remove_target":
+				var req schema.LoadBalancerActionRemoveTargetRequest
+				decodeRequest(r, &req)
+				var targets []schema.LoadBalancerTarget
+				for _, t := range lb.Targets {
+					if req.LabelSelector != nil && t.LabelSelector != nil && t.LabelSelector.Selector == req.LabelSelector.Selector {
+						continue
+					}
+					if req.Server != nil && t.Server != nil && t.Server.ID == req.Server.ID {
+						continue
+					}
+					targets = append(targets, t)
+				}
+				lb.Targets = targets
+			default:
+				writeError(w, http.StatusBadRequest, "invalid_input", fmt.Sprintf("unsupported load balancer action %q", action))
+				return
+			}
+			m.loadBalancers[id] = lb
+			writeJSON(w, http.StatusCreated, schema.ActionGetResponse{Action: m.newAction(action, "load_balancer", id)})
+		},
+	})
+}
+
+// newLoadBalancerService builds a service with the default TCP health check the API adds
+func newLoadBalancerService(protocol string, listenPort *int, destinationPort *int, proxyprotocol *bool) schema.LoadBalancerService {
+	return schema.LoadBalancerService{
+		Protocol:        protocol,
+		ListenPort:      fi.IntValue(listenPort),
+		DestinationPort: fi.IntValue(destinationPort),
+		Proxyprotocol:   fi.BoolValue(proxyprotocol),
+		HealthCheck: &schema.LoadBalancerServiceHealthCheck{
+			Protocol: "tcp",
+			Port:     fi.IntValue(destinationPort),
+			Interval: 15,
+			Timeout:  10,
+			Retries:  3,
+		},
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockhcloud
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/hcloud/schema"
+)
+
+func (m *MockClient) mockNetworks() {
+	m.handleCollection("networks", collectionHandler{
+		list: func(w http.ResponseWriter, r *http.Request) {
+			resp := schema.NetworkListResponse{Networks: []schema.Network{}}
+			for _, n := range m.networks {
+				if matchesListOptions(r, n.Name, n.Labels) {
+					resp.Networks = append(resp.Networks, n)
+				}
+			}
+			writeJSON(w, http.StatusOK, resp)
+		},
+		create: func(w http.ResponseWriter, r *http.Request) {
+			var req schema.NetworkCreateRequest
+			decodeRequest(r, &req)
+
+			n := schema.Network{
+				ID:      m.nextID(),
+				Name:    req.Name,
+				Created: time.Now(),
+				IPRange: req.IPRange,
+				Subnets: req.Subnets,
+				Routes:  req.Routes,
+				Servers: []int{},
+				Labels:  labelsValue(req.Labels),
+			}
+			m.networks[n.ID] = n
+			writeJSON(w, http.StatusCreated, schema.NetworkCreateResponse{Network: n})
+		},
+		get: func(w http.ResponseWriter, id int) {
+			n, ok := m.networks[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, schema.NetworkGetResponse{Network: n})
+		},
+		update: func(w http.ResponseWriter, r *http.Request, id int) {
+			n, ok := m.networks[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			var req schema.NetworkUpdateRequest
+			decodeRequest(r, &req)
+			if req.Name != "" {
+				n.Name = req.Name
+			}
+			if req.Labels != nil {
+				n.Labels = labelsValue(req.Labels)
+			}
+			m.networks[id] = n
+			writeJSON(w, http.StatusOK, schema.NetworkUpdateResponse{Network: n})
+		},
+		delete: func(w http.ResponseWriter, id int) {
+			n, ok := m.networks[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			if len(n.Servers) != 0 {
+				writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("network %d is still in use", id))
+				return
+			}
+			delete(m.networks, id)
+			writeNoContent(w)
+		},
+		action: func(w http.ResponseWriter, r *http.Request, id int, action string) {
+			n, ok := m.networks[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			switch action {
+			case "add_subnet":
+				var req schema.NetworkActionAddSubnetRequest
+				decodeRequest(r, &req)
+				n.Subnets = append(n.Subnets, schema.NetworkSubnet{
+					Type:        req.Type,
+					IPRange:     req.IPRange,
+					NetworkZone: req.NetworkZone,
+					VSwitchID:   req.VSwitchID,
+				})
+			default:
+				writeError(w, http.StatusBadRequest, "invalid_input", fmt.Sprintf("unsupported network action %q", action))
+				return
+			}
+			m.networks[id] = n
+			a := m.newAction(action, "network", id)
+			writeJSON(w, http.StatusCreated, schema.ActionGetResponse{Action: a})
+		},
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockhcloud
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/hcloud/schema"
+)
+
+func (m *MockClient) mockPlacementGroups() {
+	m.handleCollection("placement_groups", collectionHandler{
+		list: func(w http.ResponseWriter, r *http.Request) {
+			resp := schema.PlacementGroupListResponse{PlacementGroups: []schema.PlacementGroup{}}
+			for _, pg := range m.placementGroups {
+				if matchesListOptions(r, pg.Name, pg.Labels) {
+					resp.PlacementGroups = append(resp.PlacementGroups, pg)
+				}
+			}
+			writeJSON(w, http.StatusOK, resp)
+		},
+		create: func(w http.ResponseWriter, r *http.Request) {
+			var req schema.PlacementGroupCreateRequest
+			decodeRequest(r, &req)
+
+			pg := schema.PlacementGroup{
+				ID:      m.nextID(),
+				Name:    req.Name,
+				Labels:  labelsValue(req.Labels),
+				Created: time.Now(),
+				Servers: []int{},
+				Type:    req.Type,
+			}
+			m.placementGroups[pg.ID] = pg
+			writeJSON(w, http.StatusCreated, schema.PlacementGroupCreateResponse{PlacementGroup: pg})
+		},
+		get: func(w http.ResponseWriter, id int) {
+			pg, ok := m.placementGroups[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, schema.PlacementGroupGetResponse{PlacementGroup: pg})
+		},
+		update: func(w http.ResponseWriter, r *http.Request, id int) {
+			pg, ok := m.placementGroups[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			var req schema.PlacementGroupUpdateRequest
+			decodeRequest(r, &req)
+			if req.Name != nil {
+				pg.Name = *req.Name
+			}
+			if req.Labels != nil {
+				pg.Labels = labelsValue(req.Labels)
+			}
+			m.placementGroups[id] = pg
+			writeJSON(w, http.StatusOK, schema.PlacementGroupUpdateResponse{PlacementGroup: pg})
+		},
+		delete: func(w http.ResponseWriter, id int) {
+			pg, ok := m.placementGroups[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			if len(pg.Servers) != 0 {
+				writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("placement group %d is still in use", id))
+				return
+			}
+			delete(m.placementGroups, id)
+			writeNoContent(w)
+		},
+	})
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockhcloud
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/hcloud/schema"
+)
+
+func (m *MockClient) mockServers() {
+	m.handleCollection("servers", collectionHandler{
+		list: func(w http.ResponseWriter, r *http.Request) {
+			resp := schema.ServerListResponse{Servers: []schema.Server{}}
+			for _, s := range m.servers {
+				if matchesListOptions(r, s.Name, s.Labels) {
+					resp.Servers = append(resp.Servers, s)
+				}
+			}
+			writeJSON(w, http.StatusOK, resp)
+		},
+		create: func(w http.ResponseWriter, r *http.Request) {
+			var req schema.ServerCreateRequest
+			decodeRequest(r, &req)
+
+			for _, s := range m.servers {
+				if s.Name == req.Name {
+					writeError(w, http.StatusConflict, "uniqueness_error", fmt.Sprintf("server name %q is already used", req.Name))
+					return
+				}
+			}
+
+			id := m.nextID()
+			imageName := fmt.Sprintf("%v", req.Image)
+			s := schema.Server{
+				ID:      id,
+				Name:    req.Name,
+				Status:  "running",
+				Created: time.Now(),
+				PublicNet: schema.ServerPublicNet{
+					IPv4: schema.ServerPublicNetIPv4{IP: fmt.Sprintf("203.0.113.%d", id%256)},
+					IPv6: schema.ServerPublicNetIPv6{IP: fmt.Sprintf("2001:db8:%x::/64", id)},
+				},
+				PrivateNet: []schema.ServerPrivateNet{},
+				ServerType: schema.ServerType{Name: fmt.Sprintf("%v", req.ServerType)},
+				Datacenter: schema.Datacenter{
+					Name:     req.Location + "-dc1",
+					Location: schema.Location{Name: req.Location},
+				},
+				Image:   &schema.Image{Name: &imageName},
+				Labels:  labelsValue(req.Labels),
+				Volumes: req.Volumes,
+			}
+			if s.Volumes == nil {
+				s.Volumes = []int{}
+			}
+
+			for _, networkID := range req.Networks {
+				n, ok := m.networks[networkID]
+				if !ok {
+					writeError(w, http.StatusUnprocessableEntity, "invalid_input", fmt.Sprintf("network %d not found", networkID))
+					return
+				}
+				n.Servers = append(n.Servers, id)
+				m.networks[networkID] = n
+				s.PrivateNet = append(s.PrivateNet, schema.ServerPrivateNet{
+					Network: networkID,
+					IP:      fmt.Sprintf("10.10.%d.%d", id/256, id%256),
+				})
+			}
+
+			if req.PlacementGroup != 0 {
+				pg, ok := m.placementGroups[req.PlacementGroup]
+				if !ok {
+					writeError(w, http.StatusUnprocessableEntity, "invalid_input", fmt.Sprintf("placement group %d not found", req.PlacementGroup))
+					return
+				}
+				pg.Servers = append(pg.Servers, id)
+				m.placementGroups[pg.ID] = pg
+				s.PlacementGroup = &pg
+			}
+
+			for _, f := range req.Firewalls {
+				s.PublicNet.Firewalls = append(s.PublicNet.Firewalls, schema.ServerFirewall{ID: f.Firewall, Status: "applied"})
+			}
+
+			m.servers[id] = s
+			writeJSON(w, http.StatusCreated, schema.ServerCreateResponse{
+				Server:      s,
+				Action:      m.newAction("create_server", "server", id),
+				NextActions: []schema.Action{m.newAction("start_server", "server", id)},
+			})
+		},
+		get: func(w http.ResponseWriter, id int) {
+			s, ok := m.servers[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, schema.ServerGetResponse{Server: s})
+		},
+		update: func(w http.ResponseWriter, r *http.Request, id int) {
+			s, ok := m.servers[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			var req schema.ServerUpdateRequest
+			decodeRequest(r, &req)
+			if req.Name != "" {
+				s.Name = req.Name
+			}
+			if req.Labels != nil {
+				s.Labels = labelsValue(req.Labels)
+			}
+			m.servers[id] = s
+			writeJSON(w, http.StatusOK, schema.ServerUpdateResponse{Server: s})
+		},
+		delete: func(w http.ResponseWriter, id int) {
+			s, ok := m.servers[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			for _, privateNet := range s.PrivateNet {
+				if n, ok := m.networks[privateNet.Network]; ok {
+					n.Servers = removeID(n.Servers, id)
+					m.networks[n.ID] = n
+				}
+			}
+			if s.PlacementGroup != nil {
+				if pg, ok := m.placementGroups[s.PlacementGroup.ID]; ok {
+					pg.Servers = removeID(pg.Servers, id)
+					m.placementGroups[pg.ID] = pg
+				}
+			}
+			for _, volumeID := range s.Volumes {
+				if v, ok := m.volumes[volumeID]; ok {
+					v.Server = nil
+					m.volumes[volumeID] = v
+				}
+			}
+			delete(m.servers, id)
+			writeJSON(w, http.StatusOK, schema.ActionGetResponse{Action: m.newAction("delete_server", "server", id)})
+		},
+		action: func(w http.ResponseWriter, r *http.Request, id int, action string) {
+			s, ok := m.servers[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			switch action {
+			case "poweroff", "shutdown":
+				s.Status = "off"
+			case "poweron":
+				s.Status = "running"
+			default:
+				writeError(w, http.StatusBadRequest, "invalid_input", fmt.Sprintf("unsupported server action %q", action))
+				return
+			}
+			m.servers[id] = s
+			writeJSON(w, http.StatusCreated, schema.ActionGetResponse{Action: m.newAction(action, "server", id)})
+		},
+	})
+}
+
+func removeID(ids []int, id int) []int {
+	result := []int{}
+	for _, i := range ids {
+		if i != id {
+			result = append(result, i)
+		}
+	}
+	return result
+}
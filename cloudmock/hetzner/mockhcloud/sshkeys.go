@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockhcloud
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/hcloud/schema"
+	"k8s.io/kops/pkg/pki"
+)
+
+func (m *MockClient) mockSSHKeys() {
+	m.handleCollection("ssh_keys", collectionHandler{
+		list: func(w http.ResponseWriter, r *http.Request) {
+			resp := schema.SSHKeyListResponse{SSHKeys: []schema.SSHKey{}}
+			fingerprint := r.URL.Query().Get("fingerprint")
+			for _, k := range m.sshKeys {
+				if fingerprint != "" && fingerprint != k.Fingerprint {
+					continue
+				}
+				if matchesListOptions(r, k.Name, k.Labels) {
+					resp.SSHKeys = append(resp.SSHKeys, k)
+				}
+			}
+			writeJSON(w, http.StatusOK, resp)
+		},
+		create: func(w http.ResponseWriter, r *http.Request) {
+			var req schema.SSHKeyCreateRequest
+			decodeRequest(r, &req)
+
+			fingerprint, err := pki.ComputeOpenSSHKeyFingerprint(req.PublicKey)
+			if err != nil {
+				writeError(w, http.StatusUnprocessableEntity, "invalid_input", err.Error())
+				return
+			}
+
+			k := schema.SSHKey{
+				ID:          m.nextID(),
+				Name:        req.Name,
+				Fingerprint: fingerprint,
+				PublicKey:   req.PublicKey,
+				Labels:      labelsValue(req.Labels),
+				Created:     time.Now(),
+			}
+			m.sshKeys[k.ID] = k
+			writeJSON(w, http.StatusCreated, schema.SSHKeyCreateResponse{SSHKey: k})
+		},
+		get: func(w http.ResponseWriter, id int) {
+			k, ok := m.sshKeys[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, schema.SSHKeyGetResponse{SSHKey: k})
+		},
+		delete: func(w http.ResponseWriter, id int) {
+			if _, ok := m.sshKeys[id]; !ok {
+				writeNotFound(w)
+				return
+			}
+			delete(m.sshKeys, id)
+			writeNoContent(w)
+		},
+	})
+}
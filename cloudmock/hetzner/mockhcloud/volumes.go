@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockhcloud
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/hcloud/schema"
+)
+
+func (m *MockClient) mockVolumes() {
+	m.handleCollection("volumes", collectionHandler{
+		list: func(w http.ResponseWriter, r *http.Request) {
+			resp := schema.VolumeListResponse{Volumes: []schema.Volume{}}
+			for _, v := range m.volumes {
+				if matchesListOptions(r, v.Name, v.Labels) {
+					resp.Volumes = append(resp.Volumes, v)
+				}
+			}
+			writeJSON(w, http.StatusOK, resp)
+		},
+		create: func(w http.ResponseWriter, r *http.Request) {
+			var req schema.VolumeCreateRequest
+			decodeRequest(r, &req)
+
+			location, _ := req.Location.(string)
+			v := schema.Volume{
+				ID:       m.nextID(),
+				Name:     req.Name,
+				Status:   "available",
+				Location: schema.Location{Name: location},
+				Size:     req.Size,
+				Labels:   labelsValue(req.Labels),
+				Created:  time.Now(),
+			}
+			v.LinuxDevice = fmt.Sprintf("/dev/disk/by-id/scsi-0HC_Volume_%d", v.ID)
+			if req.Server != nil {
+				if !m.attachVolume(&v, *req.Server) {
+					writeError(w, http.StatusUnprocessableEntity, "invalid_input", fmt.Sprintf("server %d not found", *req.Server))
+					return
+				}
+			}
+			m.volumes[v.ID] = v
+
+			action := m.newAction("create_volume", "volume", v.ID)
+			writeJSON(w, http.StatusCreated, schema.VolumeCreateResponse{
+				Volume:      v,
+				Action:      &action,
+				NextActions: []schema.Action{},
+			})
+		},
+		get: func(w http.ResponseWriter, id int) {
+			v, ok := m.volumes[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, schema.VolumeGetResponse{Volume: v})
+		},
+		update: func(w http.ResponseWriter, r *http.Request, id int) {
+			v, ok := m.volumes[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			var req schema.VolumeUpdateRequest
+			decodeRequest(r, &req)
+			if req.Name != "" {
+				v.Name = req.Name
+			}
+			if req.Labels != nil {
+				v.Labels = labelsValue(req.Labels)
+			}
+			m.volumes[id] = v
+			writeJSON(w, http.StatusOK, schema.VolumeUpdateResponse{Volume: v})
+		},
+		delete: func(w http.ResponseWriter, id int) {
+			v, ok := m.volumes[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			if v.Server != nil {
+				writeError(w, http.StatusLocked, "locked", fmt.Sprintf("volume %d is still attached", id))
+				return
+			}
+			delete(m.volumes, id)
+			writeNoContent(w)
+		},
+		action: func(w http.ResponseWriter, r *http.Request, id int, action string) {
+			v, ok := m.volumes[id]
+			if !ok {
+				writeNotFound(w)
+				return
+			}
+			switch action {
+			case "attach":
+				var req schema.VolumeActionAttachVolumeRequest
+				decodeRequest(r, &req)
+				if !m.attachVolume(&v, req.Server) {
+					writeError(w, http.StatusUnprocessableEntity, "invalid_input", fmt.Sprintf("server %d not found", req.Server))
+					return
+				}
+			case "detach":
+				m.detachVolume(&v)
+			default:
+				writeError(w, http.StatusBadRequest, "invalid_input", fmt.Sprintf("unsupported volume action %q", action))
+				return
+			}
+			m.volumes[id] = v
+			writeJSON(w, http.StatusCreated, schema.ActionGetResponse{Action: m.newAction(action+"_volume", "volume", id)})
+		},
+	})
+}
+
+func (m *MockClient) attachVolume(v *schema.Volume, serverID int) bool {
+	s, ok := m.servers[serverID]
+	if !ok {
+		return false
+	}
+	m.detachVolume(v)
+	s.Volumes = append(s.Volumes, v.ID)
+	m.servers[serverID] = s
+	v.Server = &serverID
+	return true
+}
+
+func (m *MockClient) detachVolume(v *schema.Volume) {
+	if v.Server == nil {
+		return
+	}
+	if s, ok := m.servers[*v.Server]; ok {
+		s.Volumes = removeID(s.Volumes, v.ID)
+		m.servers[s.ID] = s
+	}
+	v.Server = nil
+}
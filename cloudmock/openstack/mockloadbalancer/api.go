@@ -17,11 +17,13 @@ limitations under the License.
 package mockloadbalancer
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"sync"
 
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
 	"k8s.io/kops/cloudmock/openstack"
 )
@@ -34,15 +36,19 @@ type MockClient struct {
 	loadbalancers map[string]loadbalancers.LoadBalancer
 	listeners     map[string]listeners.Listener
 	pools         map[string]pools.Pool
+	monitors      map[string]monitors.Monitor
 }
 
 // CreateClient will create a new mock networking client
 func CreateClient() *MockClient {
 	m := &MockClient{}
 	m.Reset()
-	m.SetupMux()
+	// The root path serves the API versions, so the catch-all handler of SetupMux cannot be used
+	m.Mux = http.NewServeMux()
+	m.mockVersions()
 	m.mockListeners()
 	m.mockLoadBalancers()
+	m.mockMonitors()
 	m.mockPools()
 	m.Server = httptest.NewServer(m.Mux)
 	return m
@@ -53,6 +59,7 @@ func (m *MockClient) Reset() {
 	m.loadbalancers = make(map[string]loadbalancers.LoadBalancer)
 	m.listeners = make(map[string]listeners.Listener)
 	m.pools = make(map[string]pools.Pool)
+	m.monitors = make(map[string]monitors.Monitor)
 }
 
 // All returns a map of all resource IDs to their resources
@@ -67,5 +74,8 @@ func (m *MockClient) All() map[string]interface{} {
 	for id, p := range m.pools {
 		all[id] = p
 	}
+	for id, mon := range m.monitors {
+		all[id] = mon
+	}
 	return all
 }
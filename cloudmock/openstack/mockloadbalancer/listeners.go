@@ -39,6 +39,13 @@ type listenerCreateRequest struct {
 	Listener listeners.CreateOpts `json:"listener"`
 }
 
+type listenerUpdateRequest struct {
+	Listener listeners.UpdateOpts `json:"listener"`
+}
+
+// defaultListenerTimeout is the timeout in milliseconds Octavia uses when none is specified
+const defaultListenerTimeout = 50000
+
 func (m *MockClient) mockListeners() {
 	re := regexp.MustCompile(`/lbaas/listeners/?`)
 
@@ -55,6 +62,8 @@ func (m *MockClient) mockListeners() {
 			m.listListeners(w, r.Form)
 		case http.MethodPost:
 			m.createListener(w, r)
+		case http.MethodPut:
+			m.updateListener(w, r, listenerID)
 		case http.MethodDelete:
 			m.deleteListener(w, listenerID)
 		default:
@@ -121,9 +130,57 @@ func (m *MockClient) createListener(w http.ResponseWriter, r *http.Request) {
 		Protocol:      string(create.Listener.Protocol),
 		ProtocolPort:  create.Listener.ProtocolPort,
 		AllowedCIDRs:  create.Listener.AllowedCIDRs,
+
+		TimeoutClientData: defaultListenerTimeout,
+		TimeoutMemberData: defaultListenerTimeout,
+	}
+	if create.Listener.TimeoutClientData != nil {
+		l.TimeoutClientData = *create.Listener.TimeoutClientData
+	}
+	if create.Listener.TimeoutMemberData != nil {
+		l.TimeoutMemberData = *create.Listener.TimeoutMemberData
+	}
+	m.listeners[l.ID] = l
+
+	resp := listenerGetResponse{
+		Listener: l,
+	}
+	respB, err := json.Marshal(resp)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal %+v", resp))
+	}
+	_, err = w.Write(respB)
+	if err != nil {
+		panic("failed to write body")
+	}
+}
+
+func (m *MockClient) updateListener(w http.ResponseWriter, r *http.Request, listenerID string) {
+	l, ok := m.listeners[listenerID]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var update listenerUpdateRequest
+	err := json.NewDecoder(r.Body).Decode(&update)
+	if err != nil {
+		panic("error decoding update listener request")
+	}
+
+	if update.Listener.AllowedCIDRs != nil {
+		l.AllowedCIDRs = *update.Listener.AllowedCIDRs
+	}
+	if update.Listener.TimeoutClientData != nil {
+		l.TimeoutClientData = *update.Listener.TimeoutClientData
+	}
+	if update.Listener.TimeoutMemberData != nil {
+		l.TimeoutMemberData = *update.Listener.TimeoutMemberData
 	}
 	m.listeners[l.ID] = l
 
+	w.WriteHeader(http.StatusOK)
+
 	resp := listenerGetResponse{
 		Listener: l,
 	}
@@ -0,0 +1,218 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockloadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
+)
+
+type monitorListResponse struct {
+	Monitors []monitors.Monitor `json:"healthmonitors"`
+}
+
+type monitorGetResponse struct {
+	Monitor monitors.Monitor `json:"healthmonitor"`
+}
+
+type monitorCreateRequest struct {
+	Monitor monitors.CreateOpts `json:"healthmonitor"`
+}
+
+type monitorUpdateRequest struct {
+	Monitor monitors.UpdateOpts `json:"healthmonitor"`
+}
+
+func (m *MockClient) mockMonitors() {
+	re := regexp.MustCompile(`/lbaas/healthmonitors/?`)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		w.Header().Add("Content-Type", "application/json")
+
+		monitorID := re.ReplaceAllString(r.URL.Path, "")
+		switch r.Method {
+		case http.MethodGet:
+			if monitorID == "" {
+				r.ParseForm()
+				m.listMonitors(w, r.Form)
+			} else {
+				m.getMonitor(w, monitorID)
+			}
+		case http.MethodPost:
+			m.createMonitor(w, r)
+		case http.MethodPut:
+			m.updateMonitor(w, r, monitorID)
+		case http.MethodDelete:
+			m.deleteMonitor(w, monitorID)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}
+	m.Mux.HandleFunc("/lbaas/healthmonitors/", handler)
+	m.Mux.HandleFunc("/lbaas/healthmonitors", handler)
+}
+
+func (m *MockClient) listMonitors(w http.ResponseWriter, vals url.Values) {
+
+	w.WriteHeader(http.StatusOK)
+
+	monitors := make([]monitors.Monitor, 0)
+	id := vals.Get("id")
+	name := vals.Get("name")
+	poolID := vals.Get("pool_id")
+	for _, mon := range m.monitors {
+		if id != "" && id != mon.ID {
+			continue
+		}
+		if name != "" && name != mon.Name {
+			continue
+		}
+		if poolID != "" && (len(mon.Pools) == 0 || poolID != mon.Pools[0].ID) {
+			continue
+		}
+		monitors = append(monitors, mon)
+	}
+
+	resp := monitorListResponse{
+		Monitors: monitors,
+	}
+	m.writeMonitorResponse(w, resp)
+}
+
+func (m *MockClient) getMonitor(w http.ResponseWriter, monitorID string) {
+	if mon, ok := m.monitors[monitorID]; ok {
+		resp := monitorGetResponse{
+			Monitor: mon,
+		}
+		m.writeMonitorResponse(w, resp)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *MockClient) deleteMonitor(w http.ResponseWriter, monitorID string) {
+	if mon, ok := m.monitors[monitorID]; ok {
+		for _, poolID := range mon.Pools {
+			if p, ok := m.pools[poolID.ID]; ok {
+				p.MonitorID = ""
+				m.pools[p.ID] = p
+			}
+		}
+		delete(m.monitors, monitorID)
+		w.WriteHeader(http.StatusNoContent)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *MockClient) createMonitor(w http.ResponseWriter, r *http.Request) {
+	var create monitorCreateRequest
+	err := json.NewDecoder(r.Body).Decode(&create)
+	if err != nil {
+		panic("error decoding create monitor request")
+	}
+
+	p, ok := m.pools[create.Monitor.PoolID]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if p.MonitorID != "" {
+		// Octavia allows only one health monitor per pool
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	mon := monitors.Monitor{
+		ID:                 uuid.New().String(),
+		Name:               create.Monitor.Name,
+		Type:               create.Monitor.Type,
+		Delay:              create.Monitor.Delay,
+		Timeout:            create.Monitor.Timeout,
+		MaxRetries:         create.Monitor.MaxRetries,
+		MaxRetriesDown:     create.Monitor.MaxRetriesDown,
+		Pools:              []monitors.PoolID{{ID: p.ID}},
+		AdminStateUp:       true,
+		ProvisioningStatus: "ACTIVE",
+	}
+	m.monitors[mon.ID] = mon
+
+	p.MonitorID = mon.ID
+	m.pools[p.ID] = p
+
+	resp := monitorGetResponse{
+		Monitor: mon,
+	}
+	m.writeMonitorResponse(w, resp)
+}
+
+func (m *MockClient) updateMonitor(w http.ResponseWriter, r *http.Request, monitorID string) {
+	mon, ok := m.monitors[monitorID]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var update monitorUpdateRequest
+	err := json.NewDecoder(r.Body).Decode(&update)
+	if err != nil {
+		panic("error decoding update monitor request")
+	}
+
+	if update.Monitor.Delay != 0 {
+		mon.Delay = update.Monitor.Delay
+	}
+	if update.Monitor.Timeout != 0 {
+		mon.Timeout = update.Monitor.Timeout
+	}
+	if update.Monitor.MaxRetries != 0 {
+		mon.MaxRetries = update.Monitor.MaxRetries
+	}
+	if update.Monitor.MaxRetriesDown != 0 {
+		mon.MaxRetriesDown = update.Monitor.MaxRetriesDown
+	}
+	m.monitors[mon.ID] = mon
+
+	w.WriteHeader(http.StatusOK)
+
+	resp := monitorGetResponse{
+		Monitor: mon,
+	}
+	m.writeMonitorResponse(w, resp)
+}
+
+func (m *MockClient) writeMonitorResponse(w http.ResponseWriter, resp interface{}) {
+	respB, err := json.Marshal(resp)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal %+v", resp))
+	}
+	_, err = w.Write(respB)
+	if err != nil {
+		panic("failed to write body")
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockloadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/apiversions"
+)
+
+// octaviaVersion is the API version the mock reports, which supports VIP ACLs and listener timeouts
+const octaviaVersion = "v2.12"
+
+type versionListResponse struct {
+	Versions []apiversions.APIVersion `json:"versions"`
+}
+
+// mockVersions serves the list of API versions at the root of the endpoint, which is how
+// clients detect the features of Octavia. Requests for any other unhandled path still fail.
+func (m *MockClient) mockVersions() {
+	m.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotImplemented)
+			panic(fmt.Sprintf("Unhandled mock request: %+v\n", r))
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		resp := versionListResponse{
+			Versions: []apiversions.APIVersion{{ID: octaviaVersion, Status: "CURRENT"}},
+		}
+		respB, err := json.Marshal(resp)
+		if err != nil {
+			panic(fmt.Sprintf("failed to marshal %+v", resp))
+		}
+		_, err = w.Write(respB)
+		if err != nil {
+			panic("failed to write body")
+		}
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"net/url"
 	"regexp"
 
+	"github.com/google/uuid"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 )
 
@@ -30,6 +31,18 @@ type floatingIPListResponse struct {
 	FloatingIPs []floatingips.FloatingIP `json:"floatingips"`
 }
 
+type floatingIPGetResponse struct {
+	FloatingIP floatingips.FloatingIP `json:"floatingip"`
+}
+
+type floatingIPCreateRequest struct {
+	FloatingIP floatingips.CreateOpts `json:"floatingip"`
+}
+
+type floatingIPUpdateRequest struct {
+	FloatingIP floatingips.UpdateOpts `json:"floatingip"`
+}
+
 func (m *MockClient) mockFloatingIPs() {
 	re := regexp.MustCompile(`/floatingips/?`)
 
@@ -45,7 +58,13 @@ func (m *MockClient) mockFloatingIPs() {
 			if floatingIPID == "" {
 				r.ParseForm()
 				m.listFloatingIPs(w, r.Form)
+			} else {
+				m.getFloatingIP(w, floatingIPID)
 			}
+		case http.MethodPost:
+			m.createFloatingIP(w, r)
+		case http.MethodPut:
+			m.updateFloatingIP(w, r, floatingIPID)
 		default:
 			w.WriteHeader(http.StatusBadRequest)
 		}
@@ -60,6 +79,18 @@ func (m *MockClient) listFloatingIPs(w http.ResponseWriter, vals url.Values) {
 
 	floatingips := make([]floatingips.FloatingIP, 0)
 	for _, p := range m.floatingips {
+		if id := vals.Get("id"); id != "" && id != p.ID {
+			continue
+		}
+		if address := vals.Get("floating_ip_address"); address != "" && address != p.FloatingIP {
+			continue
+		}
+		if portID := vals.Get("port_id"); portID != "" && portID != p.PortID {
+			continue
+		}
+		if description := vals.Get("description"); description != "" && description != p.Description {
+			continue
+		}
 		floatingips = append(floatingips, p)
 	}
 	resp := floatingIPListResponse{
@@ -74,3 +105,73 @@ func (m *MockClient) listFloatingIPs(w http.ResponseWriter, vals url.Values) {
 		panic("failed to write body")
 	}
 }
+
+func (m *MockClient) getFloatingIP(w http.ResponseWriter, floatingIPID string) {
+	if fip, ok := m.floatingips[floatingIPID]; ok {
+		m.writeFloatingIP(w, http.StatusOK, fip)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *MockClient) createFloatingIP(w http.ResponseWriter, r *http.Request) {
+	var create floatingIPCreateRequest
+	err := json.NewDecoder(r.Body).Decode(&create)
+	if err != nil {
+		panic("error decoding create floating ip request")
+	}
+
+	fip := floatingips.FloatingIP{
+		ID:                uuid.New().String(),
+		FloatingNetworkID: create.FloatingIP.FloatingNetworkID,
+		FloatingIP:        create.FloatingIP.FloatingIP,
+		PortID:            create.FloatingIP.PortID,
+		Description:       create.FloatingIP.Description,
+	}
+	if fip.FloatingIP == "" {
+		fip.FloatingIP = fmt.Sprintf("203.0.113.%d", len(m.floatingips)+1)
+	}
+	m.floatingips[fip.ID] = fip
+
+	m.writeFloatingIP(w, http.StatusCreated, fip)
+}
+
+func (m *MockClient) updateFloatingIP(w http.ResponseWriter, r *http.Request, floatingIPID string) {
+	fip, ok := m.floatingips[floatingIPID]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var update floatingIPUpdateRequest
+	err := json.NewDecoder(r.Body).Decode(&update)
+	if err != nil {
+		panic("error decoding update floating ip request")
+	}
+
+	if update.FloatingIP.PortID != nil {
+		fip.PortID = *update.FloatingIP.PortID
+	}
+	if update.FloatingIP.Description != nil {
+		fip.Description = *update.FloatingIP.Description
+	}
+	m.floatingips[fip.ID] = fip
+
+	m.writeFloatingIP(w, http.StatusOK, fip)
+}
+
+func (m *MockClient) writeFloatingIP(w http.ResponseWriter, status int, fip floatingips.FloatingIP) {
+	w.WriteHeader(status)
+
+	resp := floatingIPGetResponse{
+		FloatingIP: fip,
+	}
+	respB, err := json.Marshal(resp)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal %+v", resp))
+	}
+	_, err = w.Write(respB)
+	if err != nil {
+		panic("failed to write body")
+	}
+}
@@ -112,6 +112,18 @@ func (r *LegacyNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	labels := nodelabels.BuildNodeLabels(cluster, ig)
 
+	// The pre-provisioned hosts of an instance group can carry labels of their own
+	if ig.Spec.Metal != nil {
+		for _, host := range ig.Spec.Metal.Hosts {
+			if host.Name != node.Name {
+				continue
+			}
+			for k, v := range host.Labels {
+				labels[k] = v
+			}
+		}
+	}
+
 	lifecycle, err := r.getInstanceLifecycle(ctx, node)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("unable to get instance lifecycle %s: %v", node.Name, err)
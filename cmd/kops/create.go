@@ -21,20 +21,28 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/text"
 	"k8s.io/kops/util/pkg/vfs"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 )
 
 type CreateOptions struct {
@@ -47,7 +55,8 @@ var (
 		`
 	Create a cluster, instancegroup or secret using command line parameters,
 	YAML configuration specification files, or stdin.
-	(Note: secrets cannot be created from YAML config files yet).
+	A single YAML file can hold a cluster together with its instancegroups,
+	SSHCredentials and Keysets.
 	`))
 
 	createExample = templates.Examples(i18n.T(`
@@ -58,6 +67,9 @@ var (
 	# Create secret from secret spec file
 	kops create -f secret.yaml
 
+	# Create a cluster with its instancegroups and secrets from a multi-document YAML file
+	kops create -f my-cluster-with-secrets.yaml
+
 	# Create an instancegroup based on the YAML passed into stdin.
 	cat instancegroup.yaml | kops create -f -
 
@@ -121,105 +133,97 @@ func RunCreate(ctx context.Context, f *util.Factory, out io.Writer, c *CreateOpt
 	//var cSpec = false
 	var sb bytes.Buffer
 	fmt.Fprintf(&sb, "\n")
-	for _, f := range c.Filenames {
-		var contents []byte
-		if f == "-" {
-			contents, err = ConsumeStdin()
+
+	objects, err := readManifestObjects(c.Filenames)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		switch v := object.obj.(type) {
+		case *kopsapi.Cluster:
+			cloud, err := cloudup.BuildCloud(v)
 			if err != nil {
 				return err
 			}
-		} else {
-			contents, err = vfs.Context.ReadFile(f)
+
+			// Adding a PerformAssignments() call here as the user might be trying to use
+			// the new `-f` feature, with an old cluster definition.
+			err = cloudup.PerformAssignments(v, cloud)
 			if err != nil {
-				return fmt.Errorf("error reading file %q: %v", f, err)
+				return fmt.Errorf("error populating configuration: %v", err)
 			}
-		}
-		// TODO: this does not support a JSON array
-		sections := text.SplitContentToSections(contents)
-		for _, section := range sections {
-			o, gvk, err := kopscodecs.Decode(section, nil)
+			_, err = clientset.CreateCluster(ctx, v)
 			if err != nil {
-				return fmt.Errorf("error parsing file %q: %v", f, err)
-			}
-
-			switch v := o.(type) {
-			case *kopsapi.Cluster:
-				cloud, err := cloudup.BuildCloud(v)
-				if err != nil {
-					return err
-				}
-
-				// Adding a PerformAssignments() call here as the user might be trying to use
-				// the new `-f` feature, with an old cluster definition.
-				err = cloudup.PerformAssignments(v, cloud)
-				if err != nil {
-					return fmt.Errorf("error populating configuration: %v", err)
-				}
-				_, err = clientset.CreateCluster(ctx, v)
-				if err != nil {
-					if apierrors.IsAlreadyExists(err) {
-						return fmt.Errorf("cluster %q already exists", v.ObjectMeta.Name)
-					}
-					return fmt.Errorf("error creating cluster: %v", err)
-				}
-				fmt.Fprintf(&sb, "Created cluster/%s\n", v.ObjectMeta.Name)
-				//cSpec = true
-
-			case *kopsapi.InstanceGroup:
-				clusterName = v.ObjectMeta.Labels[kopsapi.LabelClusterName]
-				if clusterName == "" {
-					return fmt.Errorf("must specify %q label with cluster name to create instanceGroup", kopsapi.LabelClusterName)
-				}
-				cluster, err := clientset.GetCluster(ctx, clusterName)
-				if err != nil {
-					return fmt.Errorf("error querying cluster %q: %v", clusterName, err)
+				if apierrors.IsAlreadyExists(err) {
+					return fmt.Errorf("cluster %q already exists", v.ObjectMeta.Name)
 				}
+				return fmt.Errorf("error creating cluster: %v", err)
+			}
+			fmt.Fprintf(&sb, "Created cluster/%s\n", v.ObjectMeta.Name)
+			//cSpec = true
 
-				if cluster == nil {
-					return fmt.Errorf("cluster %q not found", clusterName)
-				}
+		case *kopsapi.InstanceGroup:
+			clusterName = v.ObjectMeta.Labels[kopsapi.LabelClusterName]
+			if clusterName == "" {
+				return fmt.Errorf("must specify %q label with cluster name to create instanceGroup", kopsapi.LabelClusterName)
+			}
+			cluster, err := clientset.GetCluster(ctx, clusterName)
+			if err != nil {
+				return fmt.Errorf("error querying cluster %q: %v", clusterName, err)
+			}
 
-				_, err = clientset.InstanceGroupsFor(cluster).Create(ctx, v, metav1.CreateOptions{})
-				if err != nil {
-					if apierrors.IsAlreadyExists(err) {
-						return fmt.Errorf("instanceGroup %q already exists", v.ObjectMeta.Name)
-					}
-					return fmt.Errorf("error creating instanceGroup: %v", err)
-				}
-				fmt.Fprintf(&sb, "Created instancegroup/%s\n", v.ObjectMeta.Name)
+			if cluster == nil {
+				return fmt.Errorf("cluster %q not found", clusterName)
+			}
 
-			case *kopsapi.SSHCredential:
-				clusterName = v.ObjectMeta.Labels[kopsapi.LabelClusterName]
-				if clusterName == "" {
-					return fmt.Errorf("must specify %q label with cluster name to create SSHCredential", kopsapi.LabelClusterName)
-				}
-				if v.Spec.PublicKey == "" {
-					return fmt.Errorf("spec.PublicKey is required")
+			err = commands.WithClusterLock(ctx, clientset, cluster, "create", func(ctx context.Context) error {
+				_, err := clientset.InstanceGroupsFor(cluster).Create(ctx, v, metav1.CreateOptions{})
+				return err
+			})
+			if err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					return fmt.Errorf("instanceGroup %q already exists", v.ObjectMeta.Name)
 				}
+				return fmt.Errorf("error creating instanceGroup: %v", err)
+			}
+			fmt.Fprintf(&sb, "Created instancegroup/%s\n", v.ObjectMeta.Name)
 
-				cluster, err := clientset.GetCluster(ctx, clusterName)
-				if err != nil {
-					return err
-				}
+		case *kopsapi.SSHCredential:
+			cluster, err := commands.ClusterForManifestObject(ctx, clientset, "SSHCredential", v.ObjectMeta.Labels)
+			if err != nil {
+				return err
+			}
+			clusterName = cluster.ObjectMeta.Name
 
-				sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
-				if err != nil {
-					return err
-				}
+			var name string
+			err = commands.WithClusterLock(ctx, clientset, cluster, "create", func(ctx context.Context) error {
+				name, err = commands.StoreSSHCredential(clientset, cluster, v)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&sb, "Added ssh credential/%s\n", name)
 
-				sshKeyArr := []byte(v.Spec.PublicKey)
-				err = sshCredentialStore.AddSSHPublicKey("admin", sshKeyArr)
-				if err != nil {
-					return err
-				}
-				fmt.Fprintf(&sb, "Added ssh credential\n")
+		case *kopsapi.Keyset:
+			cluster, err := commands.ClusterForManifestObject(ctx, clientset, "Keyset", v.ObjectMeta.Labels)
+			if err != nil {
+				return err
+			}
+			clusterName = cluster.ObjectMeta.Name
 
-			default:
-				klog.V(2).Infof("Type of object was %T", v)
-				return fmt.Errorf("Unhandled kind %q in %s", gvk, f)
+			err = commands.WithClusterLock(ctx, clientset, cluster, "create", func(ctx context.Context) error {
+				return commands.StoreKeyset(clientset, cluster, v, &commands.ManifestSecretOptions{})
+			})
+			if err != nil {
+				return err
 			}
-		}
+			fmt.Fprintf(&sb, "Created keyset/%s\n", v.ObjectMeta.Name)
 
+		default:
+			klog.V(2).Infof("Type of object was %T", v)
+			return fmt.Errorf("Unhandled kind %q in %s", object.gvk, object.filename)
+		}
 	}
 	{
 		// If there is a value in this sb, this should mean that we have something to deploy
@@ -236,3 +240,124 @@ func RunCreate(ctx context.Context, f *util.Factory, out io.Writer, c *CreateOpt
 	}
 	return nil
 }
+
+// manifestObject is an object decoded from a manifest file
+type manifestObject struct {
+	filename string
+	obj      runtime.Object
+	gvk      *schema.GroupVersionKind
+}
+
+// readManifestObjects decodes the objects in the given manifest files.
+// Clusters are returned first and instance groups second, so that the other
+// objects in a file can refer to a cluster defined later in the same file.
+func readManifestObjects(filenames []string) ([]*manifestObject, error) {
+	var objects []*manifestObject
+	for _, f := range filenames {
+		var contents []byte
+		var err error
+		if f == "-" {
+			contents, err = ConsumeStdin()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			contents, err = vfs.Context.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("error reading file %q: %v", f, err)
+			}
+		}
+		// TODO: this does not support a JSON array
+		sections := text.SplitContentToSections(contents)
+		for _, section := range sections {
+			section, err = resolveKeysetMaterialFiles(section, manifestBaseDir(f))
+			if err != nil {
+				return nil, fmt.Errorf("error parsing file %q: %v", f, err)
+			}
+			o, gvk, err := kopscodecs.Decode(section, nil)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing file %q: %v", f, err)
+			}
+			objects = append(objects, &manifestObject{filename: f, obj: o, gvk: gvk})
+		}
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		return manifestObjectOrder(objects[i].obj) < manifestObjectOrder(objects[j].obj)
+	})
+	return objects, nil
+}
+
+func manifestObjectOrder(o runtime.Object) int {
+	switch o.(type) {
+	case *kopsapi.Cluster:
+		return 0
+	case *kopsapi.InstanceGroup:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// manifestBaseDir returns the directory that relative file references in a manifest are resolved against
+func manifestBaseDir(filename string) string {
+	if filename == "-" || strings.Contains(filename, "://") {
+		return ""
+	}
+	return filepath.Dir(filename)
+}
+
+// keysetMaterialFiles maps the fields of a Keyset key that reference a file to the fields that hold the material
+var keysetMaterialFiles = map[string]string{
+	"publicMaterialFile":  "publicMaterial",
+	"privateMaterialFile": "privateMaterial",
+}
+
+// resolveKeysetMaterialFiles replaces the file references in the keys of a Keyset with the contents of the files.
+// File references are only understood in manifests, and are not part of the Keyset API.
+func resolveKeysetMaterialFiles(section []byte, baseDir string) ([]byte, error) {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal(section, &obj); err != nil {
+		// Leave the error to the decoder
+		return section, nil
+	}
+	if obj["kind"] != "Keyset" {
+		return section, nil
+	}
+
+	spec, _ := obj["spec"].(map[string]interface{})
+	keys, _ := spec["keys"].([]interface{})
+	resolved := false
+	for _, k := range keys {
+		key, ok := k.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for fileField, materialField := range keysetMaterialFiles {
+			file, ok := key[fileField].(string)
+			if !ok {
+				continue
+			}
+			if _, found := key[materialField]; found {
+				return nil, fmt.Errorf("cannot set both %s and %s", materialField, fileField)
+			}
+
+			p := utils.ExpandPath(file)
+			if !filepath.IsAbs(p) && !strings.Contains(p, "://") && baseDir != "" {
+				p = filepath.Join(baseDir, p)
+			}
+			data, err := vfs.Context.ReadFile(p)
+			if err != nil {
+				return nil, fmt.Errorf("error reading file %q: %v", p, err)
+			}
+
+			delete(key, fileField)
+			key[materialField] = data
+			resolved = true
+		}
+	}
+	if !resolved {
+		return section, nil
+	}
+	return yaml.Marshal(obj)
+}
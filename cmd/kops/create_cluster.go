@@ -208,7 +208,7 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to immediately create the cluster")
-	cmd.Flags().StringVar(&options.Target, "target", options.Target, fmt.Sprintf("Valid targets: %s, %s, %s. Set this flag to %s if you want kOps to generate terraform", cloudup.TargetDirect, cloudup.TargetTerraform, cloudup.TargetCloudformation, cloudup.TargetTerraform))
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, fmt.Sprintf("Valid targets: %s, %s, %s, %s. Set this flag to %s if you want kOps to generate terraform", cloudup.TargetDirect, cloudup.TargetTerraform, cloudup.TargetCloudformation, cloudup.TargetResourceGraph, cloudup.TargetTerraform))
 
 	// Configuration / state location
 	if featureflag.EnableSeparateConfigBase.Enabled() {
@@ -356,6 +356,8 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 			c.OutDir = "out/terraform"
 		} else if c.Target == cloudup.TargetCloudformation {
 			c.OutDir = "out/cloudformation"
+		} else if c.Target == cloudup.TargetResourceGraph {
+			c.OutDir = "out/resourcegraph"
 		} else {
 			c.OutDir = "out"
 		}
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/testutils"
+)
+
+// TestCreateClusterWithSecrets creates a cluster and its secrets from a single manifest,
+// with the secrets listed before the cluster they belong to
+func TestCreateClusterWithSecrets(t *testing.T) {
+	ctx := context.Background()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.SetupMockAWS()
+
+	srcDir := updateClusterTestBase + "minimal"
+	clusterName := "minimal.example.com"
+
+	clusterManifest, err := ioutil.ReadFile(path.Join(srcDir, "in-v1alpha2.yaml"))
+	if err != nil {
+		t.Fatalf("error reading cluster manifest: %v", err)
+	}
+	publicKey, err := ioutil.ReadFile(path.Join(srcDir, "id_rsa.pub"))
+	if err != nil {
+		t.Fatalf("error reading public key: %v", err)
+	}
+
+	dir := t.TempDir()
+	dockerConfig := `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "docker-config.json"), []byte(dockerConfig), 0600); err != nil {
+		t.Fatalf("error writing docker config: %v", err)
+	}
+
+	secrets := `apiVersion: kops.k8s.io/v1alpha2
+kind: SSHCredential
+metadata:
+  name: admin
+  labels:
+    kops.k8s.io/cluster: ` + clusterName + `
+spec:
+  publicKey: "` + strings.TrimSpace(string(publicKey)) + `"
+---
+apiVersion: kops.k8s.io/v1alpha2
+kind: Keyset
+metadata:
+  name: dockerconfig
+  labels:
+    kops.k8s.io/cluster: ` + clusterName + `
+spec:
+  type: Secret
+  keys:
+  - privateMaterialFile: docker-config.json
+`
+	manifest := filepath.Join(dir, "cluster.yaml")
+	if err := ioutil.WriteFile(manifest, []byte(secrets+"---\n"+string(clusterManifest)), 0600); err != nil {
+		t.Fatalf("error writing manifest: %v", err)
+	}
+
+	factory := util.NewFactory(&util.FactoryOptions{RegistryPath: "memfs://tests"})
+
+	var stdout bytes.Buffer
+	options := &CreateOptions{}
+	options.Filenames = []string{manifest}
+	if err := RunCreate(ctx, factory, &stdout, options); err != nil {
+		t.Fatalf("error running create: %v", err)
+	}
+
+	for _, expected := range []string{"Created cluster/" + clusterName, "Added ssh credential/admin", "Created keyset/dockerconfig"} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Errorf("expected output to contain %q, got %q", expected, stdout.String())
+		}
+	}
+
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error getting clientset: %v", err)
+	}
+	cluster, err := clientset.GetCluster(ctx, clusterName)
+	if err != nil {
+		t.Fatalf("error getting cluster: %v", err)
+	}
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		t.Fatalf("error getting secret store: %v", err)
+	}
+	secret, err := secretStore.FindSecret("dockerconfig")
+	if err != nil {
+		t.Fatalf("error reading docker config: %v", err)
+	}
+	if secret == nil || string(secret.Data) != dockerConfig {
+		t.Errorf("unexpected docker config secret %v", secret)
+	}
+}
+
+func TestResolveKeysetMaterialFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "ca.crt"), []byte("certificate"), 0600); err != nil {
+		t.Fatalf("error writing certificate: %v", err)
+	}
+
+	keyset := `apiVersion: kops.k8s.io/v1alpha2
+kind: Keyset
+metadata:
+  name: ca
+spec:
+  type: Keypair
+  keys:
+  - publicMaterialFile: ca.crt
+`
+	resolved, err := resolveKeysetMaterialFiles([]byte(keyset), dir)
+	if err != nil {
+		t.Fatalf("error resolving keyset files: %v", err)
+	}
+	if strings.Contains(string(resolved), "publicMaterialFile") {
+		t.Errorf("expected file reference to be removed, got %q", resolved)
+	}
+	// base64 of "certificate"
+	if !strings.Contains(string(resolved), "publicMaterial: Y2VydGlmaWNhdGU=") {
+		t.Errorf("expected file contents in publicMaterial, got %q", resolved)
+	}
+
+	if _, err := resolveKeysetMaterialFiles([]byte(keyset+"    publicMaterial: Y2VydGlmaWNhdGU=\n"), dir); err == nil {
+		t.Errorf("expected error setting both publicMaterial and publicMaterialFile")
+	}
+
+	instanceGroup := "kind: InstanceGroup\nspec:\n  keys:\n  - publicMaterialFile: missing\n"
+	resolved, err = resolveKeysetMaterialFiles([]byte(instanceGroup), dir)
+	if err != nil || string(resolved) != instanceGroup {
+		t.Errorf("expected other kinds to be unchanged, got %q, %v", resolved, err)
+	}
+}
@@ -94,6 +94,7 @@ func NewCmdDelete(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.AddCommand(NewCmdDeleteInstanceGroup(f, out))
 	cmd.AddCommand(NewCmdDeleteSecret(f, out))
 	cmd.AddCommand(NewCmdDeleteInstance(f, out))
+	cmd.AddCommand(NewCmdDeleteLock(f, out))
 
 	return cmd
 }
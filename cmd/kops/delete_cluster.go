@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/resources"
 	resourceops "k8s.io/kops/pkg/resources/ops"
@@ -95,7 +96,7 @@ func NewCmdDeleteCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	return cmd
 }
 
-func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, options *DeleteClusterOptions) error {
+func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, options *DeleteClusterOptions) (err error) {
 	clusterName := options.ClusterName
 	if clusterName == "" {
 		return fmt.Errorf("--name is required (for safety)")
@@ -103,7 +104,7 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 
 	var cloud fi.Cloud
 	var cluster *kopsapi.Cluster
-	var err error
+	var lock *commands.ClusterLock
 
 	if options.External {
 		region := options.Region
@@ -121,6 +122,20 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 		if err != nil {
 			return err
 		}
+
+		if options.Yes {
+			clientset, err := f.Clientset()
+			if err != nil {
+				return err
+			}
+			lock, err = commands.LockCluster(ctx, clientset, cluster, "delete cluster")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if lock != nil {
+		defer lock.Unlock(&err)
 	}
 
 	wouldDeleteCloudResources := false
@@ -178,6 +193,12 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 
 			fmt.Fprintf(out, "\n")
 
+			if lock != nil {
+				if err := lock.Err(); err != nil {
+					return err
+				}
+			}
+
 			err = resourceops.DeleteResources(cloud, clusterResources)
 			if err != nil {
 				return err
@@ -198,6 +219,10 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 		if err != nil {
 			return err
 		}
+		// The lock is kept in the state store, so we release it before removing the cluster state
+		if err := lock.Release(); err != nil {
+			return err
+		}
 		err = clientset.DeleteCluster(ctx, cluster)
 		if err != nil {
 			return fmt.Errorf("error removing cluster from state store: %v", err)
@@ -32,6 +32,7 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/instancegroups"
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
@@ -157,7 +158,7 @@ func NewCmdDeleteInstance(f *util.Factory, out io.Writer) *cobra.Command {
 	return cmd
 }
 
-func RunDeleteInstance(ctx context.Context, f *util.Factory, out io.Writer, options *deleteInstanceOptions) error {
+func RunDeleteInstance(ctx context.Context, f *util.Factory, out io.Writer, options *deleteInstanceOptions) (err error) {
 
 	clientset, err := f.Clientset()
 	if err != nil {
@@ -241,6 +242,13 @@ func RunDeleteInstance(ctx context.Context, f *util.Factory, out io.Writer, opti
 		return nil
 	}
 
+	lock, err := commands.LockCluster(ctx, clientset, cluster, "delete instance")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock(&err)
+	ctx = lock.Context()
+
 	d := &instancegroups.RollingUpdateCluster{
 		Cluster:           cluster,
 		Ctx:               ctx,
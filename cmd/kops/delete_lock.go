@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/clusterlock"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	deleteLockLong = templates.LongDesc(i18n.T(`
+	Remove the state store lock on a cluster.
+
+	Locks are released automatically when the command holding them finishes, and expire
+	if they are not renewed.  Removing a lock held by a running command allows concurrent
+	changes to the cluster, so --force is required unless the lock has already expired.`))
+
+	deleteLockExample = templates.Examples(i18n.T(`
+	# Remove a lock left behind by an interrupted command
+	kops delete lock --name k8s-cluster.example.com --force
+	`))
+
+	deleteLockShort = i18n.T(`Remove the state store lock on a cluster.`)
+)
+
+type DeleteLockOptions struct {
+	ClusterName string
+	Force       bool
+}
+
+func NewCmdDeleteLock(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &DeleteLockOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "lock",
+		Short:   deleteLockShort,
+		Long:    deleteLockLong,
+		Example: deleteLockExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunDeleteLock(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.Force, "force", options.Force, "Remove the lock even if it has not expired")
+
+	return cmd
+}
+
+func RunDeleteLock(ctx context.Context, f *util.Factory, out io.Writer, options *DeleteLockOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	lock, err := clusterlock.Get(configBase)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		fmt.Fprintf(out, "Cluster %q is not locked\n", options.ClusterName)
+		return nil
+	}
+
+	if !lock.IsExpired(time.Now()) && !options.Force {
+		return fmt.Errorf("cluster %q is locked by %s running %q until %s; specify --force to remove the lock",
+			options.ClusterName, lock.Holder, lock.Command, lock.Expires.Format(time.RFC3339))
+	}
+
+	if err := clusterlock.Delete(configBase); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Removed lock on cluster %q held by %s running %q\n", options.ClusterName, lock.Holder, lock.Command)
+	return nil
+}
@@ -82,8 +82,8 @@ func NewCmdEditCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	return cmd
 }
 
-func RunEditCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, args []string, out io.Writer, options *EditClusterOptions) error {
-	err := rootCommand.ProcessArgs(args)
+func RunEditCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, args []string, out io.Writer, options *EditClusterOptions) (err error) {
+	err = rootCommand.ProcessArgs(args)
 	if err != nil {
 		return err
 	}
@@ -103,6 +103,12 @@ func RunEditCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, ar
 		return err
 	}
 
+	lock, err := commands.LockCluster(ctx, clientset, oldCluster, "edit cluster")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock(&err)
+
 	instanceGroups, err := commands.ReadAllInstanceGroups(ctx, clientset, oldCluster)
 	if err != nil {
 		return err
@@ -185,6 +191,9 @@ func RunEditCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, ar
 			continue
 		}
 
+		// Only save the changes if the cluster has not been changed since it was read
+		newCluster.ResourceVersion = oldCluster.ResourceVersion
+
 		extraFields, err := edit.HasExtraFields(string(edited), newObj)
 		if err != nil {
 			results = editResults{
@@ -249,6 +258,10 @@ func RunEditCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, ar
 			return err
 		}
 
+		if err := lock.Err(); err != nil {
+			return preservedFile(err, file, out)
+		}
+
 		// Note we perform as much validation as we can, before writing a bad config
 		_, err = clientset.UpdateCluster(ctx, newCluster, status)
 		if err != nil {
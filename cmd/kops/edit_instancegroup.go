@@ -31,6 +31,7 @@ import (
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/validation"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/try"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
@@ -82,7 +83,7 @@ func NewCmdEditInstanceGroup(f *util.Factory, out io.Writer) *cobra.Command {
 	return cmd
 }
 
-func RunEditInstanceGroup(ctx context.Context, f *util.Factory, cmd *cobra.Command, args []string, out io.Writer, options *EditInstanceGroupOptions) error {
+func RunEditInstanceGroup(ctx context.Context, f *util.Factory, cmd *cobra.Command, args []string, out io.Writer, options *EditInstanceGroupOptions) (err error) {
 	if len(args) == 0 {
 		return fmt.Errorf("Specify name of instance group to edit")
 	}
@@ -111,6 +112,12 @@ func RunEditInstanceGroup(ctx context.Context, f *util.Factory, cmd *cobra.Comma
 		return fmt.Errorf("name is required")
 	}
 
+	lock, err := commands.LockCluster(ctx, clientset, cluster, "edit instancegroup")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock(&err)
+
 	oldGroup, err := clientset.InstanceGroupsFor(cluster).Get(ctx, groupName, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("error reading InstanceGroup %q: %v", groupName, err)
@@ -155,6 +162,9 @@ func RunEditInstanceGroup(ctx context.Context, f *util.Factory, cmd *cobra.Comma
 		return fmt.Errorf("object was not of expected type: %T", newObj)
 	}
 
+	// Only save the changes if the instance group has not been changed since it was read
+	newGroup.ResourceVersion = oldGroup.ResourceVersion
+
 	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
 		return err
@@ -182,6 +192,10 @@ func RunEditInstanceGroup(ctx context.Context, f *util.Factory, cmd *cobra.Comma
 		return err
 	}
 
+	if err := lock.Err(); err != nil {
+		return err
+	}
+
 	// Note we perform as much validation as we can, before writing a bad config
 	_, err = clientset.InstanceGroupsFor(cluster).Update(ctx, fullGroup, metav1.UpdateOptions{})
 	if err != nil {
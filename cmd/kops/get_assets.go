@@ -0,0 +1,255 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getAssetsLong = templates.LongDesc(i18n.T(`
+	Display the files and container images that the cluster uses.
+
+	Files are listed with their canonical URL, the URL that nodes download them from,
+	their hash, and the roles and architectures of the nodes that download them.
+	Container images are listed with their canonical name and the name that the cluster runs,
+	which differ when spec.assets.containerRegistry or spec.assets.containerProxy is set.
+
+	The YAML and JSON output can be used to drive tooling that mirrors the assets.`))
+
+	getAssetsExample = templates.Examples(i18n.T(`
+	# Display the assets of a cluster
+	kops get assets --name k8s-cluster.example.com
+
+	# Display the assets of a cluster in YAML format
+	kops get assets --name k8s-cluster.example.com -o yaml
+	`))
+
+	getAssetsShort = i18n.T(`Display the files and container images that a cluster uses.`)
+)
+
+type GetAssetsOptions struct {
+	*GetOptions
+}
+
+// AssetResult lists the assets of a cluster, for output
+type AssetResult struct {
+	// Files are the files that the cluster uses
+	Files []*FileAssetResult `json:"files,omitempty"`
+	// Images are the container images that the cluster uses
+	Images []*ImageAssetResult `json:"images,omitempty"`
+}
+
+// FileAssetResult is a file that the cluster uses
+type FileAssetResult struct {
+	// CanonicalURL is the original location of the file
+	CanonicalURL string `json:"canonicalURL"`
+	// DownloadURL is the location that the file is downloaded from, which is in spec.assets.fileRepository if it is set
+	DownloadURL string `json:"downloadURL"`
+	// SHA is the hex-encoded hash of the file
+	SHA string `json:"sha"`
+	// Roles are the roles of the nodes that download the file; it is empty for files that nodes do not download
+	Roles []kopsapi.InstanceGroupRole `json:"roles,omitempty"`
+	// Architectures are the architectures of the nodes that download the file
+	Architectures []architectures.Architecture `json:"architectures,omitempty"`
+}
+
+// ImageAssetResult is a container image that the cluster uses
+type ImageAssetResult struct {
+	// CanonicalImage is the original name of the image
+	CanonicalImage string `json:"canonicalImage"`
+	// Image is the name of the image that the cluster runs, which is in spec.assets.containerRegistry if it is set
+	Image string `json:"image"`
+}
+
+func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetAssetsOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "assets",
+		Aliases: []string{"asset"},
+		Short:   getAssetsShort,
+		Long:    getAssetsLong,
+		Example: getAssetsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.clusterName = rootCommand.ClusterName()
+
+			err := RunGetAssets(ctx, f, out, &options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	return cmd
+}
+
+// GetAssets discovers the assets of a cluster by building its model, without making any changes
+func GetAssets(ctx context.Context, f *util.Factory, clusterName string, out io.Writer) (*AssetResult, error) {
+	updateClusterResults, err := RunUpdateCluster(ctx, f, clusterName, out, &UpdateClusterOptions{
+		Target:    cloudup.TargetDryRun,
+		Phase:     string(cloudup.PhaseStageAssets),
+		GetAssets: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AssetResult{}
+
+	seenFiles := make(map[string]bool)
+	for _, asset := range updateClusterResults.FileAssets {
+		if asset.DownloadURL == nil || seenFiles[asset.DownloadURL.String()] {
+			continue
+		}
+		seenFiles[asset.DownloadURL.String()] = true
+
+		file := &FileAssetResult{
+			CanonicalURL: asset.DownloadURL.String(),
+			DownloadURL:  asset.DownloadURL.String(),
+			SHA:          asset.SHAValue,
+		}
+		if asset.CanonicalURL != nil {
+			file.CanonicalURL = asset.CanonicalURL.String()
+		}
+		if usage := updateClusterResults.FileAssetUsage[file.DownloadURL]; usage != nil {
+			file.Roles = usage.Roles
+			file.Architectures = usage.Architectures
+		}
+		result.Files = append(result.Files, file)
+	}
+
+	seenImages := make(map[string]bool)
+	for _, asset := range updateClusterResults.ContainerAssets {
+		image := &ImageAssetResult{
+			CanonicalImage: asset.CanonicalLocation,
+			Image:          asset.DockerImage,
+		}
+		if image.CanonicalImage == "" {
+			image.CanonicalImage = image.Image
+		}
+		key := image.CanonicalImage + " " + image.Image
+		if seenImages[key] {
+			continue
+		}
+		seenImages[key] = true
+		result.Images = append(result.Images, image)
+	}
+
+	sort.Slice(result.Files, func(i, j int) bool { return result.Files[i].CanonicalURL < result.Files[j].CanonicalURL })
+	sort.Slice(result.Images, func(i, j int) bool { return result.Images[i].CanonicalImage < result.Images[j].CanonicalImage })
+
+	return result, nil
+}
+
+func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *GetAssetsOptions) error {
+	if options.clusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	result, err := GetAssets(ctx, f, options.clusterName, out)
+	if err != nil {
+		return err
+	}
+
+	switch options.output {
+	case OutputTable:
+		if err := fileAssetOutputTable(result.Files, out); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\n")
+		return imageAssetOutputTable(result.Images, out)
+	case OutputYaml:
+		b, err := yaml.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("error marshaling yaml: %v", err)
+		}
+		_, err = out.Write(b)
+		return err
+	case OutputJSON:
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling json: %v", err)
+		}
+		_, err = fmt.Fprintf(out, "%s\n", b)
+		return err
+	default:
+		return fmt.Errorf("Unknown output format: %q", options.output)
+	}
+}
+
+func fileAssetOutputTable(files []*FileAssetResult, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("FILE", func(f *FileAssetResult) string {
+		return f.CanonicalURL
+	})
+	t.AddColumn("DOWNLOAD URL", func(f *FileAssetResult) string {
+		return f.DownloadURL
+	})
+	t.AddColumn("SHA", func(f *FileAssetResult) string {
+		return f.SHA
+	})
+	t.AddColumn("ROLES", func(f *FileAssetResult) string {
+		var roles []string
+		for _, r := range f.Roles {
+			roles = append(roles, string(r))
+		}
+		return strings.Join(roles, ",")
+	})
+	t.AddColumn("ARCHITECTURES", func(f *FileAssetResult) string {
+		var archs []string
+		for _, a := range f.Architectures {
+			archs = append(archs, string(a))
+		}
+		return strings.Join(archs, ",")
+	})
+	return t.Render(files, out, "FILE", "DOWNLOAD URL", "SHA", "ROLES", "ARCHITECTURES")
+}
+
+func imageAssetOutputTable(images []*ImageAssetResult, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("IMAGE", func(i *ImageAssetResult) string {
+		return i.CanonicalImage
+	})
+	t.AddColumn("REMAPPED IMAGE", func(i *ImageAssetResult) string {
+		return i.Image
+	})
+	return t.Render(images, out, "IMAGE", "REMAPPED IMAGE")
+}
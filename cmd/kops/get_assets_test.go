@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+func TestGetAssets(t *testing.T) {
+	ctx := context.Background()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.MockKopsVersion("1.21.0-alpha.1")
+	h.SetupMockAWS()
+
+	srcDir := updateClusterTestBase + "minimal"
+	clusterName := "minimal.example.com"
+
+	factory := util.NewFactory(&util.FactoryOptions{RegistryPath: "memfs://tests"})
+
+	var stdout bytes.Buffer
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(srcDir, "in-v1alpha2.yaml")}
+		if err := RunCreate(ctx, factory, &stdout, options); err != nil {
+			t.Fatalf("error running create: %v", err)
+		}
+	}
+	{
+		options := &CreateSecretPublickeyOptions{}
+		options.ClusterName = clusterName
+		options.Name = "admin"
+		options.PublicKeyPath = path.Join(srcDir, "id_rsa.pub")
+		if err := RunCreateSecretPublicKey(ctx, factory, &stdout, options); err != nil {
+			t.Fatalf("error creating public key: %v", err)
+		}
+	}
+
+	result, err := GetAssets(ctx, factory, clusterName, &stdout)
+	if err != nil {
+		t.Fatalf("error getting assets: %v", err)
+	}
+
+	var kubelet *FileAssetResult
+	for _, f := range result.Files {
+		if strings.HasSuffix(f.CanonicalURL, "/bin/linux/amd64/kubelet") {
+			kubelet = f
+		}
+	}
+	if kubelet == nil {
+		t.Fatalf("kubelet not found in file assets: %v", result.Files)
+	}
+	if kubelet.SHA == "" {
+		t.Errorf("kubelet has no SHA")
+	}
+	if kubelet.DownloadURL != kubelet.CanonicalURL {
+		t.Errorf("expected kubelet to be downloaded from %q, got %q", kubelet.CanonicalURL, kubelet.DownloadURL)
+	}
+	if expected := []kops.InstanceGroupRole{kops.InstanceGroupRoleMaster, kops.InstanceGroupRoleNode}; !reflect.DeepEqual(kubelet.Roles, expected) {
+		t.Errorf("expected kubelet roles %v, got %v", expected, kubelet.Roles)
+	}
+	if expected := []architectures.Architecture{architectures.ArchitectureAmd64}; !reflect.DeepEqual(kubelet.Architectures, expected) {
+		t.Errorf("expected kubelet architectures %v, got %v", expected, kubelet.Architectures)
+	}
+
+	found := false
+	for _, image := range result.Images {
+		if strings.HasPrefix(image.CanonicalImage, "k8s.gcr.io/kube-apiserver:") {
+			found = true
+			if image.Image != image.CanonicalImage {
+				t.Errorf("expected %q not to be remapped, got %q", image.CanonicalImage, image.Image)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("kube-apiserver not found in image assets: %v", result.Images)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/etcdbackup"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getEtcdBackupsLong = templates.LongDesc(i18n.T(`
+	Display the etcd backups taken by etcd-manager.
+
+	Backups are read from the backupStore of each etcd cluster, which defaults to
+	backups/etcd/<etcd cluster> under the cluster's configBase.`))
+
+	getEtcdBackupsExample = templates.Examples(i18n.T(`
+	# Display the backups of all etcd clusters
+	kops get etcd-backups --name k8s-cluster.example.com
+
+	# Display the backups of the main etcd cluster
+	kops get etcd-backups --name k8s-cluster.example.com --cluster main
+	`))
+
+	getEtcdBackupsShort = i18n.T(`Display etcd backups.`)
+)
+
+type GetEtcdBackupsOptions struct {
+	*GetOptions
+	EtcdCluster string
+}
+
+// etcdBackup is a backup along with the etcd cluster it belongs to, for output
+type etcdBackup struct {
+	EtcdCluster string `json:"etcdCluster"`
+	etcdbackup.Backup
+}
+
+func NewCmdGetEtcdBackups(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetEtcdBackupsOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "etcd-backups",
+		Aliases: []string{"etcd-backup"},
+		Short:   getEtcdBackupsShort,
+		Long:    getEtcdBackupsLong,
+		Example: getEtcdBackupsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			options.clusterName = rootCommand.ClusterName()
+
+			err := RunGetEtcdBackups(ctx, f, out, &options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.EtcdCluster, "cluster", options.EtcdCluster, "Name of the etcd cluster to display backups for (default all etcd clusters)")
+
+	return cmd
+}
+
+func RunGetEtcdBackups(ctx context.Context, f *util.Factory, out io.Writer, options *GetEtcdBackupsOptions) error {
+	if options.clusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.clusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", options.clusterName)
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	var etcdClusters []string
+	if options.EtcdCluster != "" {
+		etcdClusters = append(etcdClusters, options.EtcdCluster)
+	} else {
+		for _, etcdCluster := range cluster.Spec.EtcdClusters {
+			if etcdCluster.Provider != "" && etcdCluster.Provider != kopsapi.EtcdProviderTypeManager {
+				continue
+			}
+			etcdClusters = append(etcdClusters, etcdCluster.Name)
+		}
+	}
+
+	var backups []*etcdBackup
+	for _, etcdCluster := range etcdClusters {
+		store, err := etcdbackup.NewStore(cluster, configBase, etcdCluster)
+		if err != nil {
+			return err
+		}
+		list, err := store.ListBackups()
+		if err != nil {
+			return err
+		}
+		for _, backup := range list {
+			backups = append(backups, &etcdBackup{EtcdCluster: etcdCluster, Backup: *backup})
+		}
+	}
+
+	switch options.output {
+	case OutputTable:
+		if len(backups) == 0 {
+			fmt.Fprintf(out, "No etcd backups found\n")
+			return nil
+		}
+		return etcdBackupOutputTable(backups, out)
+	case OutputYaml:
+		b, err := yaml.Marshal(backups)
+		if err != nil {
+			return fmt.Errorf("error marshaling yaml: %v", err)
+		}
+		_, err = out.Write(b)
+		return err
+	case OutputJSON:
+		b, err := json.MarshalIndent(backups, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling json: %v", err)
+		}
+		_, err = fmt.Fprintf(out, "%s\n", b)
+		return err
+	default:
+		return fmt.Errorf("Unknown output format: %q", options.output)
+	}
+}
+
+func etcdBackupOutputTable(backups []*etcdBackup, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("CLUSTER", func(b *etcdBackup) string {
+		return b.EtcdCluster
+	})
+	t.AddColumn("NAME", func(b *etcdBackup) string {
+		return b.Name
+	})
+	t.AddColumn("TIMESTAMP", func(b *etcdBackup) string {
+		if b.Timestamp.IsZero() {
+			return ""
+		}
+		return b.Timestamp.Format(time.RFC3339)
+	})
+	t.AddColumn("ETCD VERSION", func(b *etcdBackup) string {
+		return b.EtcdVersion
+	})
+	return t.Render(backups, out, "CLUSTER", "NAME", "TIMESTAMP", "ETCD VERSION")
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/clusterlock"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getLocksLong = templates.LongDesc(i18n.T(`
+	Display the state store locks held by running kops commands.
+
+	Mutating commands such as update cluster and rolling-update cluster take a lock on the
+	cluster in the state store while they run.  A lock that is no longer renewed expires,
+	and can then be taken over by the next command.`))
+
+	getLocksExample = templates.Examples(i18n.T(`
+	# Display the locks on all clusters in the state store
+	kops get locks
+
+	# Display the lock on a cluster
+	kops get locks --name k8s-cluster.example.com
+	`))
+
+	getLocksShort = i18n.T(`Display state store locks.`)
+)
+
+// clusterLock is a lock along with the cluster it applies to, for output
+type clusterLock struct {
+	Cluster string `json:"cluster"`
+	clusterlock.Lock
+}
+
+func NewCmdGetLocks(f *util.Factory, out io.Writer, options *GetOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "locks",
+		Aliases: []string{"lock"},
+		Short:   getLocksShort,
+		Long:    getLocksLong,
+		Example: getLocksExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			options.clusterName = rootCommand.ClusterName()
+
+			err := RunGetLocks(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	return cmd
+}
+
+func RunGetLocks(ctx context.Context, f *util.Factory, out io.Writer, options *GetOptions) error {
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	var clusters []*kopsapi.Cluster
+	if options.clusterName != "" {
+		cluster, err := clientset.GetCluster(ctx, options.clusterName)
+		if err != nil {
+			return err
+		}
+		if cluster == nil {
+			return fmt.Errorf("cluster not found %q", options.clusterName)
+		}
+		clusters = append(clusters, cluster)
+	} else {
+		list, err := clientset.ListClusters(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			clusters = append(clusters, &list.Items[i])
+		}
+	}
+
+	var locks []*clusterLock
+	for _, cluster := range clusters {
+		configBase, err := clientset.ConfigBaseFor(cluster)
+		if err != nil {
+			return err
+		}
+		lock, err := clusterlock.Get(configBase)
+		if err != nil {
+			return err
+		}
+		if lock != nil {
+			locks = append(locks, &clusterLock{Cluster: cluster.ObjectMeta.Name, Lock: *lock})
+		}
+	}
+
+	switch options.output {
+	case OutputTable:
+		if len(locks) == 0 {
+			fmt.Fprintf(out, "No locks found\n")
+			return nil
+		}
+		return lockOutputTable(locks, out)
+	case OutputYaml:
+		b, err := yaml.Marshal(locks)
+		if err != nil {
+			return fmt.Errorf("error marshaling yaml: %v", err)
+		}
+		_, err = out.Write(b)
+		return err
+	case OutputJSON:
+		b, err := json.MarshalIndent(locks, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling json: %v", err)
+		}
+		_, err = fmt.Fprintf(out, "%s\n", b)
+		return err
+	default:
+		return fmt.Errorf("Unknown output format: %q", options.output)
+	}
+}
+
+func lockOutputTable(locks []*clusterLock, out io.Writer) error {
+	now := time.Now()
+
+	t := &tables.Table{}
+	t.AddColumn("CLUSTER", func(l *clusterLock) string {
+		return l.Cluster
+	})
+	t.AddColumn("HOLDER", func(l *clusterLock) string {
+		return l.Holder
+	})
+	t.AddColumn("COMMAND", func(l *clusterLock) string {
+		return l.Command
+	})
+	t.AddColumn("ACQUIRED", func(l *clusterLock) string {
+		return l.Acquired.Format(time.RFC3339)
+	})
+	t.AddColumn("EXPIRES", func(l *clusterLock) string {
+		if l.IsExpired(now) {
+			return "expired"
+		}
+		return l.Expires.Format(time.RFC3339)
+	})
+	return t.Render(locks, out, "CLUSTER", "HOLDER", "COMMAND", "ACQUIRED", "EXPIRES")
+}
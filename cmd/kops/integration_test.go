@@ -29,6 +29,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
@@ -66,6 +67,10 @@ type integrationTest struct {
 	caKey           bool
 	jsonOutput      bool
 	bastionUserData bool
+	// terraformModule is true if the terraform output should be a module
+	terraformModule bool
+	// cloudformationNestedStacks is true if the cloudformation output should be split into nested stacks
+	cloudformationNestedStacks bool
 }
 
 func newIntegrationTest(clusterName, srcDir string) *integrationTest {
@@ -109,6 +114,16 @@ func (i *integrationTest) withJSONOutput() *integrationTest {
 	return i
 }
 
+func (i *integrationTest) withTerraformModule() *integrationTest {
+	i.terraformModule = true
+	return i
+}
+
+func (i *integrationTest) withCloudformationNestedStacks() *integrationTest {
+	i.cloudformationNestedStacks = true
+	return i
+}
+
 func (i *integrationTest) withPrivate() *integrationTest {
 	i.private = true
 	return i
@@ -124,6 +139,12 @@ func TestMinimal(t *testing.T) {
 	newIntegrationTest("minimal.example.com", "minimal").runTestTerraformAWS(t)
 }
 
+// TestMinimalIPv6 runs the test on a minimal dual-stack configuration, with IPv6 pod and service networks
+func TestMinimalIPv6(t *testing.T) {
+	newIntegrationTest("minimal-ipv6.example.com", "minimal-ipv6").runTestTerraformAWS(t)
+	newIntegrationTest("minimal-ipv6.example.com", "minimal-ipv6").runTestCloudformation(t)
+}
+
 // TestMinimalGCE runs tests on a minimal GCE configuration
 func TestMinimalGCE(t *testing.T) {
 	newIntegrationTest("minimal-gce.example.com", "minimal_gce").runTestTerraformGCE(t)
@@ -162,6 +183,11 @@ func TestMinimalCloudformation(t *testing.T) {
 	newIntegrationTest("minimal.example.com", "minimal-cloudformation").runTestCloudformation(t)
 }
 
+// TestMinimalCloudformationNestedStacks runs the test on a minimum configuration, with a nested stack for each instance group
+func TestMinimalCloudformationNestedStacks(t *testing.T) {
+	newIntegrationTest("minimal.example.com", "minimal-cloudformation-nested").withCloudformationNestedStacks().runTestCloudformation(t)
+}
+
 // TestMinimalGp3 runs the test on a minimum configuration using gp3 volumes, similar to kops create cluster minimal.example.com --zones us-west-1a
 func TestMinimalGp3(t *testing.T) {
 	newIntegrationTest("minimal.example.com", "minimal-gp3").runTestTerraformAWS(t)
@@ -195,6 +221,11 @@ func TestMinimalJSON(t *testing.T) {
 	newIntegrationTest("minimal-json.example.com", "minimal-json").withJSONOutput().runTestTerraformAWS(t)
 }
 
+// TestMinimalTerraformModule runs the test on a minimum configuration, writing the terraform output as a module
+func TestMinimalTerraformModule(t *testing.T) {
+	newIntegrationTest("minimal-tf-module.example.com", "minimal-tf-module").withTerraformModule().runTestTerraformAWS(t)
+}
+
 // TestPrivateWeave runs the test on a configuration with private topology, weave networking
 func TestPrivateWeave(t *testing.T) {
 	newIntegrationTest("privateweave.example.com", "privateweave").withPrivate().runTestTerraformAWS(t)
@@ -390,6 +421,7 @@ func (i *integrationTest) runTest(t *testing.T, h *testutils.IntegrationTestHarn
 		options.InitDefaults()
 		options.Target = "terraform"
 		options.OutDir = path.Join(h.TempDir, "out")
+		options.TerraformModule = i.terraformModule
 		options.RunTasksOptions.MaxTaskDuration = 30 * time.Second
 		if phase != nil {
 			options.Phase = string(*phase)
@@ -419,23 +451,40 @@ func (i *integrationTest) runTest(t *testing.T, h *testutils.IntegrationTestHarn
 		}
 		sort.Strings(fileNames)
 
+		// The expected output file for each actual output file
+		tfFiles := map[string]string{actualTFPath: testDataTFPath}
+		if i.terraformModule {
+			tfFiles = map[string]string{}
+			for _, f := range []string{"main.tf", "outputs.tf", "variables.tf", "versions.tf"} {
+				tfFiles[f] = f
+			}
+		}
+
+		var tfFileNames []string
+		for f := range tfFiles {
+			tfFileNames = append(tfFileNames, f)
+		}
+		sort.Strings(tfFileNames)
+
 		actualFilenames := strings.Join(fileNames, ",")
-		expectedFilenames := actualTFPath
+		expectedFilenames := strings.Join(tfFileNames, ",")
 
 		if len(expectedDataFilenames) > 0 {
-			expectedFilenames = "data," + actualTFPath
+			expectedFilenames = "data," + expectedFilenames
 		}
 
 		if actualFilenames != expectedFilenames {
 			t.Fatalf("unexpected files.  actual=%q, expected=%q, test=%q", actualFilenames, expectedFilenames, testDataTFPath)
 		}
 
-		actualTF, err := ioutil.ReadFile(path.Join(h.TempDir, "out", actualTFPath))
-		if err != nil {
-			t.Fatalf("unexpected error reading actual terraform output: %v", err)
-		}
+		for _, f := range tfFileNames {
+			actualTF, err := ioutil.ReadFile(path.Join(h.TempDir, "out", f))
+			if err != nil {
+				t.Fatalf("unexpected error reading actual terraform output: %v", err)
+			}
 
-		golden.AssertMatchesFile(t, string(actualTF), path.Join(i.srcDir, testDataTFPath))
+			golden.AssertMatchesFile(t, string(actualTF), path.Join(i.srcDir, tfFiles[f]))
+		}
 	}
 
 	// Compare data files if they are provided
@@ -645,6 +694,7 @@ func (i *integrationTest) runTestCloudformation(t *testing.T) {
 		options := &UpdateClusterOptions{}
 		options.InitDefaults()
 		options.Target = "cloudformation"
+		options.CloudformationNestedStacks = i.cloudformationNestedStacks
 		options.OutDir = path.Join(h.TempDir, "out")
 		options.RunTasksOptions.MaxTaskDuration = 30 * time.Second
 
@@ -660,86 +710,113 @@ func (i *integrationTest) runTestCloudformation(t *testing.T) {
 
 	// Compare main files
 	{
-		files, err := ioutil.ReadDir(path.Join(h.TempDir, "out"))
+		var fileNames []string
+		err := filepath.Walk(path.Join(h.TempDir, "out"), func(p string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			relativePath, err := filepath.Rel(path.Join(h.TempDir, "out"), p)
+			if err != nil {
+				return err
+			}
+			fileNames = append(fileNames, relativePath)
+			return nil
+		})
 		if err != nil {
 			t.Fatalf("failed to read dir: %v", err)
 		}
+		sort.Strings(fileNames)
 
-		var fileNames []string
-		for _, f := range files {
-			fileNames = append(fileNames, f.Name())
+		expectedFilenames := []string{"kubernetes.json"}
+		if i.cloudformationNestedStacks {
+			// The nested stacks are compared with the files at the same paths in the test directory
+			nestedStacks, err := filepath.Glob(path.Join(i.srcDir, "stacks", "*.json"))
+			if err != nil {
+				t.Fatalf("failed to list expected nested stacks: %v", err)
+			}
+			for _, p := range nestedStacks {
+				expectedFilenames = append(expectedFilenames, path.Join("stacks", filepath.Base(p)))
+			}
+		}
+		sort.Strings(expectedFilenames)
+		if actualFilenames := strings.Join(fileNames, ","); actualFilenames != strings.Join(expectedFilenames, ",") {
+			t.Fatalf("unexpected files.  actual=%q, expected=%q", actualFilenames, strings.Join(expectedFilenames, ","))
 		}
-		sort.Strings(fileNames)
 
-		actualFilenames := strings.Join(fileNames, ",")
-		expectedFilenames := "kubernetes.json"
-		if actualFilenames != expectedFilenames {
-			t.Fatalf("unexpected files.  actual=%q, expected=%q", actualFilenames, expectedFilenames)
+		for _, fileName := range fileNames {
+			expectedPath := path.Join(i.srcDir, fileName)
+			if fileName == "kubernetes.json" {
+				expectedPath = path.Join(i.srcDir, expectedCfPath)
+			}
+			assertCloudformationMatchesFile(t, path.Join(h.TempDir, "out", fileName), expectedPath)
 		}
+	}
+}
+
+// assertCloudformationMatchesFile compares a cloudformation template with the expected file,
+// comparing the UserData with a separate .extracted.yaml file
+func assertCloudformationMatchesFile(t *testing.T, actualPath string, expectedPath string) {
+	actualCF, err := ioutil.ReadFile(actualPath)
+	if err != nil {
+		t.Fatalf("unexpected error reading actual cloudformation output: %v", err)
+	}
 
-		actualPath := path.Join(h.TempDir, "out", "kubernetes.json")
-		actualCF, err := ioutil.ReadFile(actualPath)
+	// Expand out the UserData base64 blob, as otherwise testing is painful
+	extracted := make(map[string]string)
+	var buf bytes.Buffer
+	out := jsonutils.NewJSONStreamWriter(&buf)
+	in := json.NewDecoder(bytes.NewReader(actualCF))
+	for {
+		token, err := in.Token()
 		if err != nil {
-			t.Fatalf("unexpected error reading actual cloudformation output: %v", err)
+			if err == io.EOF {
+				break
+			} else {
+				t.Fatalf("unexpected error parsing cloudformation output: %v", err)
+			}
 		}
 
-		// Expand out the UserData base64 blob, as otherwise testing is painful
-		extracted := make(map[string]string)
-		var buf bytes.Buffer
-		out := jsonutils.NewJSONStreamWriter(&buf)
-		in := json.NewDecoder(bytes.NewReader(actualCF))
-		for {
-			token, err := in.Token()
-			if err != nil {
-				if err == io.EOF {
-					break
+		if strings.HasSuffix(out.Path(), ".UserData") {
+			if s, ok := token.(string); ok {
+				vBytes, err := base64.StdEncoding.DecodeString(s)
+				if err != nil {
+					t.Fatalf("error decoding UserData: %v", err)
 				} else {
-					t.Fatalf("unexpected error parsing cloudformation output: %v", err)
+					extracted[out.Path()] = string(vBytes)
+					token = json.Token("extracted")
 				}
 			}
-
-			if strings.HasSuffix(out.Path(), ".UserData") {
-				if s, ok := token.(string); ok {
-					vBytes, err := base64.StdEncoding.DecodeString(s)
-					if err != nil {
-						t.Fatalf("error decoding UserData: %v", err)
-					} else {
-						extracted[out.Path()] = string(vBytes)
-						token = json.Token("extracted")
-					}
-				}
-			}
-
-			if err := out.WriteToken(token); err != nil {
-				t.Fatalf("error writing json: %v", err)
-			}
 		}
-		actualCF = buf.Bytes()
 
-		golden.AssertMatchesFile(t, string(actualCF), path.Join(i.srcDir, expectedCfPath))
+		if err := out.WriteToken(token); err != nil {
+			t.Fatalf("error writing json: %v", err)
+		}
+	}
+	actualCF = buf.Bytes()
 
-		// test extracted values
-		{
-			actual := make(map[string]string)
+	golden.AssertMatchesFile(t, string(actualCF), expectedPath)
 
-			for k, v := range extracted {
-				// Strip carriage return as expectedValue is stored in a yaml string literal
-				// and yaml block quoting doesn't seem to support \r in a string
-				v = strings.Replace(v, "\r", "", -1)
+	// test extracted values
+	{
+		actual := make(map[string]string)
 
-				actual[k] = v
-			}
+		for k, v := range extracted {
+			// Strip carriage return as expectedValue is stored in a yaml string literal
+			// and yaml block quoting doesn't seem to support \r in a string
+			v = strings.Replace(v, "\r", "", -1)
 
-			actualExtracted, err := yaml.Marshal(actual)
-			if err != nil {
-				t.Fatalf("error serializing yaml: %v", err)
-			}
+			actual[k] = v
+		}
 
-			golden.AssertMatchesFile(t, string(actualExtracted), path.Join(i.srcDir, expectedCfPath+".extracted.yaml"))
+		actualExtracted, err := yaml.Marshal(actual)
+		if err != nil {
+			t.Fatalf("error serializing yaml: %v", err)
 		}
 
-		golden.AssertMatchesFile(t, string(actualCF), path.Join(i.srcDir, expectedCfPath))
+		golden.AssertMatchesFile(t, string(actualExtracted), expectedPath+".extracted.yaml")
 	}
+
+	golden.AssertMatchesFile(t, string(actualCF), expectedPath)
 }
 
 func MakeSSHKeyPair(publicKeyPath string, privateKeyPath string) error {
@@ -37,6 +37,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 )
 
@@ -85,6 +87,22 @@ func TestLifecycleFloatingIPOpenstack(t *testing.T) {
 	})
 }
 
+func TestLifecycleMinimalHetzner(t *testing.T) {
+	runLifecycleTestHetzner(&LifecycleTestOptions{
+		t:           t,
+		SrcDir:      "minimal_hetzner",
+		ClusterName: "minimal-hetzner.k8s.local",
+	})
+}
+
+func TestLifecycleMinimalMetal(t *testing.T) {
+	runLifecycleTestMetal(&LifecycleTestOptions{
+		t:           t,
+		SrcDir:      "minimal_metal",
+		ClusterName: "minimal-metal.k8s.local",
+	})
+}
+
 // TestLifecyclePrivateCalico runs the test on a private topology
 func TestLifecyclePrivateCalico(t *testing.T) {
 	runLifecycleTestAWS(&LifecycleTestOptions{
@@ -342,6 +360,11 @@ func AllOpenstackResources(c *openstack.MockCloud) map[string]interface{} {
 	return all
 }
 
+// AllHetznerResources returns all resources
+func AllHetznerResources(c *hetzner.MockCloud) map[string]interface{} {
+	return c.MockClient.All()
+}
+
 func runLifecycleTestAWS(o *LifecycleTestOptions) {
 	o.AddDefaults()
 
@@ -454,6 +477,157 @@ func runLifecycleTestOpenstack(o *LifecycleTestOptions) {
 	}
 }
 
+func runLifecycleTestHetzner(o *LifecycleTestOptions) {
+	o.AddDefaults()
+
+	t := o.t
+
+	h := testutils.NewIntegrationTestHarness(o.t)
+	defer h.Close()
+
+	featureflag.ParseFlags("+Hetzner")
+	defer featureflag.ParseFlags("-Hetzner")
+
+	h.MockKopsVersion("1.21.0-alpha.1")
+	cloud := testutils.SetupMockHetzner()
+	defer cloud.MockClient.TeardownHTTP()
+
+	var beforeIds []string
+	for id := range AllHetznerResources(cloud) {
+		beforeIds = append(beforeIds, id)
+	}
+	sort.Strings(beforeIds)
+
+	ctx := context.Background()
+
+	t.Logf("running lifecycle test for cluster %s", o.ClusterName)
+
+	var stdout bytes.Buffer
+
+	inputYAML := "in-" + o.Version + ".yaml"
+
+	factoryOptions := &util.FactoryOptions{}
+	factoryOptions.RegistryPath = "memfs://tests"
+
+	factory := util.NewFactory(factoryOptions)
+
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(o.SrcDir, inputYAML)}
+
+		err := RunCreate(ctx, factory, &stdout, options)
+		if err != nil {
+			t.Fatalf("error running %q create: %v", inputYAML, err)
+		}
+	}
+
+	{
+		options := &CreateSecretPublickeyOptions{}
+		options.ClusterName = o.ClusterName
+		options.Name = "admin"
+		options.PublicKeyPath = path.Join(o.SrcDir, "id_rsa.pub")
+
+		err := RunCreateSecretPublicKey(ctx, factory, &stdout, options)
+		if err != nil {
+			t.Fatalf("error running %q create: %v", inputYAML, err)
+		}
+	}
+
+	updateEnsureNoChanges(ctx, t, factory, o.ClusterName, stdout)
+
+	{
+		options := &DeleteClusterOptions{}
+		options.Yes = true
+		options.ClusterName = o.ClusterName
+		if err := RunDeleteCluster(ctx, factory, &stdout, options); err != nil {
+			t.Fatalf("error running delete cluster %q: %v", o.ClusterName, err)
+		}
+	}
+
+	{
+		var afterIds []string
+		for id := range AllHetznerResources(cloud) {
+			afterIds = append(afterIds, id)
+		}
+		sort.Strings(afterIds)
+
+		if !reflect.DeepEqual(beforeIds, afterIds) {
+			t.Fatalf("resources changed by cluster create / destroy: %v -> %v", beforeIds, afterIds)
+		}
+	}
+}
+
+func runLifecycleTestMetal(o *LifecycleTestOptions) {
+	o.AddDefaults()
+
+	t := o.t
+
+	h := testutils.NewIntegrationTestHarness(o.t)
+	defer h.Close()
+
+	featureflag.ParseFlags("+Metal")
+	defer featureflag.ParseFlags("-Metal")
+
+	h.MockKopsVersion("1.21.0-alpha.1")
+	cloud := testutils.SetupMockMetal()
+	hosts := map[string]*metal.MockHost{
+		"master-1": cloud.AddHost("192.168.1.10"),
+		"node-1":   cloud.AddHost("192.168.1.11"),
+		"node-2":   cloud.AddHost("192.168.1.12"),
+	}
+
+	ctx := context.Background()
+
+	t.Logf("running lifecycle test for cluster %s", o.ClusterName)
+
+	var stdout bytes.Buffer
+
+	inputYAML := "in-" + o.Version + ".yaml"
+
+	factoryOptions := &util.FactoryOptions{}
+	factoryOptions.RegistryPath = "memfs://tests"
+
+	factory := util.NewFactory(factoryOptions)
+
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(o.SrcDir, inputYAML)}
+
+		err := RunCreate(ctx, factory, &stdout, options)
+		if err != nil {
+			t.Fatalf("error running %q create: %v", inputYAML, err)
+		}
+	}
+
+	updateEnsureNoChanges(ctx, t, factory, o.ClusterName, stdout)
+
+	// Each host must have been enrolled exactly once
+	for name, host := range hosts {
+		if host.Files[metal.AppliedBootstrapScriptPath] == nil {
+			t.Errorf("host %q was not enrolled", name)
+		}
+		if len(host.Commands) != 1 {
+			t.Errorf("expected the bootstrap script to be run once on host %q, ran %v", name, host.Commands)
+		}
+	}
+
+	{
+		options := &DeleteClusterOptions{}
+		options.Yes = true
+		options.ClusterName = o.ClusterName
+		if err := RunDeleteCluster(ctx, factory, &stdout, options); err != nil {
+			t.Fatalf("error running delete cluster %q: %v", o.ClusterName, err)
+		}
+	}
+
+	// kops does not own the hosts, deleting the cluster must leave them alone
+	for name, host := range hosts {
+		if len(host.Commands) != 1 {
+			t.Errorf("expected no commands to be run on host %q by delete cluster, ran %v", name, host.Commands)
+		}
+	}
+}
+
 func updateEnsureNoChanges(ctx context.Context, t *testing.T, factory *util.Factory, clusterName string, stdout bytes.Buffer) {
 	t.Helper()
 	options := &UpdateClusterOptions{}
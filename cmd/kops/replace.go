@@ -28,9 +28,6 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/commands"
-	"k8s.io/kops/pkg/kopscodecs"
-	"k8s.io/kops/util/pkg/text"
-	"k8s.io/kops/util/pkg/vfs"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -44,6 +41,9 @@ var (
 		# Replace a cluster desired configuration using a YAML file
 		kops replace -f my-cluster.yaml
 
+		# Replace a cluster together with its instancegroups, SSHCredentials and Keysets
+		kops replace -f my-cluster-with-secrets.yaml
+
 		# Replace an instancegroup using YAML passed into stdin.
 		cat instancegroup.yaml | kops replace -f -
 
@@ -95,75 +95,63 @@ func RunReplace(ctx context.Context, f *util.Factory, cmd *cobra.Command, out io
 		return err
 	}
 
-	for _, f := range c.Filenames {
-		var contents []byte
-		if f == "-" {
-			contents, err = ConsumeStdin()
-			if err != nil {
-				return err
-			}
-		} else {
-			contents, err = vfs.Context.ReadFile(f)
-			if err != nil {
-				return fmt.Errorf("error reading file %q: %v", f, err)
-			}
-		}
-		sections := text.SplitContentToSections(contents)
-
-		for _, section := range sections {
-			o, gvk, err := kopscodecs.Decode(section, nil)
-			if err != nil {
-				return fmt.Errorf("error parsing file %q: %v", f, err)
-			}
+	objects, err := readManifestObjects(c.Filenames)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		switch v := object.obj.(type) {
+		case *kopsapi.Cluster:
+			{
+				// Retrieve the current status of the cluster.  This will eventually be part of the cluster object.
+				statusDiscovery := &commands.CloudDiscoveryStatusStore{}
+				status, err := statusDiscovery.FindClusterStatus(v)
+				if err != nil {
+					return err
+				}
 
-			switch v := o.(type) {
-			case *kopsapi.Cluster:
-				{
-					// Retrieve the current status of the cluster.  This will eventually be part of the cluster object.
-					statusDiscovery := &commands.CloudDiscoveryStatusStore{}
-					status, err := statusDiscovery.FindClusterStatus(v)
-					if err != nil {
-						return err
+				// Check if the cluster exists already
+				clusterName := v.Name
+				cluster, err := clientset.GetCluster(ctx, clusterName)
+				if err != nil {
+					if errors.IsNotFound(err) {
+						cluster = nil
+					} else {
+						return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
 					}
-
-					// Check if the cluster exists already
-					clusterName := v.Name
-					cluster, err := clientset.GetCluster(ctx, clusterName)
+				}
+				if cluster == nil {
+					if !c.force {
+						return fmt.Errorf("cluster %v does not exist (try adding --force flag)", clusterName)
+					}
+					_, err = clientset.CreateCluster(ctx, v)
 					if err != nil {
-						if errors.IsNotFound(err) {
-							cluster = nil
-						} else {
-							return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
-						}
+						return fmt.Errorf("error creating cluster: %v", err)
 					}
-					if cluster == nil {
-						if !c.force {
-							return fmt.Errorf("cluster %v does not exist (try adding --force flag)", clusterName)
-						}
-						_, err = clientset.CreateCluster(ctx, v)
-						if err != nil {
-							return fmt.Errorf("error creating cluster: %v", err)
-						}
-					} else {
-						_, err = clientset.UpdateCluster(ctx, v, status)
-						if err != nil {
-							return fmt.Errorf("error replacing cluster: %v", err)
-						}
+				} else {
+					err = commands.WithClusterLock(ctx, clientset, cluster, "replace", func(ctx context.Context) error {
+						_, err := clientset.UpdateCluster(ctx, v, status)
+						return err
+					})
+					if err != nil {
+						return fmt.Errorf("error replacing cluster: %v", err)
 					}
 				}
+			}
 
-			case *kopsapi.InstanceGroup:
-				clusterName := v.ObjectMeta.Labels[kopsapi.LabelClusterName]
-				if clusterName == "" {
-					return fmt.Errorf("must specify %q label with cluster name to replace instanceGroup", kopsapi.LabelClusterName)
-				}
-				cluster, err := clientset.GetCluster(ctx, clusterName)
-				if err != nil {
-					if errors.IsNotFound(err) {
-						return fmt.Errorf("cluster %q not found", clusterName)
-					}
-					return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
+		case *kopsapi.InstanceGroup:
+			clusterName := v.ObjectMeta.Labels[kopsapi.LabelClusterName]
+			if clusterName == "" {
+				return fmt.Errorf("must specify %q label with cluster name to replace instanceGroup", kopsapi.LabelClusterName)
+			}
+			cluster, err := clientset.GetCluster(ctx, clusterName)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					return fmt.Errorf("cluster %q not found", clusterName)
 				}
+				return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
+			}
+			err = commands.WithClusterLock(ctx, clientset, cluster, "replace", func(ctx context.Context) error {
 				// check if the instancegroup exists already
 				igName := v.ObjectMeta.Name
 				ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, igName, metav1.GetOptions{})
@@ -189,34 +177,40 @@ func RunReplace(ctx context.Context, f *util.Factory, cmd *cobra.Command, out io
 						return fmt.Errorf("error replacing instanceGroup: %v", err)
 					}
 				}
-			case *kopsapi.SSHCredential:
-				clusterName := v.ObjectMeta.Labels[kopsapi.LabelClusterName]
-				if clusterName == "" {
-					return fmt.Errorf("must specify %q label with cluster name to replace SSHCredential", kopsapi.LabelClusterName)
-				}
-				if v.Spec.PublicKey == "" {
-					return fmt.Errorf("spec.PublicKey is required")
-				}
-
-				cluster, err := clientset.GetCluster(ctx, clusterName)
-				if err != nil {
-					return err
-				}
-
-				sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
-				if err != nil {
-					return err
-				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		case *kopsapi.SSHCredential:
+			cluster, err := commands.ClusterForManifestObject(ctx, clientset, "SSHCredential", v.ObjectMeta.Labels)
+			if err != nil {
+				return err
+			}
+			err = commands.WithClusterLock(ctx, clientset, cluster, "replace", func(ctx context.Context) error {
+				_, err := commands.StoreSSHCredential(clientset, cluster, v)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error replacing SSHCredential: %v", err)
+			}
 
-				sshKeyArr := []byte(v.Spec.PublicKey)
-				err = sshCredentialStore.AddSSHPublicKey("admin", sshKeyArr)
-				if err != nil {
-					return fmt.Errorf("error replacing SSHCredential: %v", err)
-				}
-			default:
-				klog.V(2).Infof("Type of object was %T", v)
-				return fmt.Errorf("unhandled kind %q in %q", gvk, f)
+		case *kopsapi.Keyset:
+			cluster, err := commands.ClusterForManifestObject(ctx, clientset, "Keyset", v.ObjectMeta.Labels)
+			if err != nil {
+				return err
+			}
+			options := &commands.ManifestSecretOptions{Replace: true}
+			err = commands.WithClusterLock(ctx, clientset, cluster, "replace", func(ctx context.Context) error {
+				return commands.StoreKeyset(clientset, cluster, v, options)
+			})
+			if err != nil {
+				return fmt.Errorf("error replacing Keyset: %v", err)
 			}
+
+		default:
+			klog.V(2).Infof("Type of object was %T", v)
+			return fmt.Errorf("unhandled kind %q in %q", object.gvk, object.filename)
 		}
 	}
 
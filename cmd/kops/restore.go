@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	restoreLong = templates.LongDesc(i18n.T(`
+	Restore a cluster component from a backup.`))
+
+	restoreExample = templates.Examples(i18n.T(`
+	# Restore the main etcd cluster from a backup
+	kops restore etcd --name k8s-cluster.example.com \
+	  --cluster main --backup 2021-01-15T12:00:00Z-000001 --yes
+	`))
+
+	restoreShort = i18n.T(`Restore a cluster component from a backup.`)
+)
+
+func NewCmdRestore(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "restore",
+		Short:   restoreShort,
+		Long:    restoreLong,
+		Example: restoreExample,
+	}
+
+	cmd.AddCommand(NewCmdRestoreEtcd(f, out))
+
+	return cmd
+}
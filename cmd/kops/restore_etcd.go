@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/etcdbackup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	restoreEtcdLong = templates.LongDesc(i18n.T(`
+	Restore an etcd cluster from a backup taken by etcd-manager.
+
+	The backup is checked to be complete, and a restore command is written to the
+	control directory of the backup store.  etcd-manager picks up the command and
+	restores the backup, which replaces all data in the etcd cluster.  The
+	Kubernetes control plane should be restarted once the restore has completed.
+
+	Use kops get etcd-backups to list the available backups.`))
+
+	restoreEtcdExample = templates.Examples(i18n.T(`
+	# Preview restoring the main etcd cluster from a backup
+	kops restore etcd --name k8s-cluster.example.com \
+	  --cluster main --backup 2021-01-15T12:00:00Z-000001
+
+	# Restore the main etcd cluster from a backup
+	kops restore etcd --name k8s-cluster.example.com \
+	  --cluster main --backup 2021-01-15T12:00:00Z-000001 --yes
+	`))
+
+	restoreEtcdShort = i18n.T(`Restore an etcd cluster from a backup.`)
+)
+
+type RestoreEtcdOptions struct {
+	ClusterName string
+	EtcdCluster string
+	Backup      string
+	Yes         bool
+}
+
+func NewCmdRestoreEtcd(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RestoreEtcdOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "etcd",
+		Short:   restoreEtcdShort,
+		Long:    restoreEtcdLong,
+		Example: restoreEtcdExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunRestoreEtcd(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.EtcdCluster, "cluster", options.EtcdCluster, "Name of the etcd cluster to restore, such as main or events")
+	cmd.Flags().StringVar(&options.Backup, "backup", options.Backup, "Name of the backup to restore")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Restore the backup (otherwise only the backup is validated)")
+
+	return cmd
+}
+
+func RunRestoreEtcd(ctx context.Context, f *util.Factory, out io.Writer, options *RestoreEtcdOptions) (err error) {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+	if options.EtcdCluster == "" {
+		return fmt.Errorf("--cluster is required")
+	}
+	if options.Backup == "" {
+		return fmt.Errorf("--backup is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster %q not found", options.ClusterName)
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	store, err := etcdbackup.NewStore(cluster, configBase, options.EtcdCluster)
+	if err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		backup, err := store.GetBackup(options.Backup)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Backup %q of etcd cluster %q is complete", backup.Name, options.EtcdCluster)
+		if backup.EtcdVersion != "" {
+			fmt.Fprintf(out, " (etcd %s)", backup.EtcdVersion)
+		}
+		fmt.Fprintf(out, ".\n\nMust specify --yes to restore it; this replaces all data in the etcd cluster.\n")
+		return nil
+	}
+
+	lock, err := commands.LockCluster(ctx, clientset, cluster, "restore etcd")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock(&err)
+
+	p, err := store.RestoreBackup(options.Backup, time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Wrote restore command to %s\n", p)
+	fmt.Fprintf(out, "etcd-manager will restore backup %q of etcd cluster %q; once it has finished, restart the control plane components.\n", options.Backup, options.EtcdCluster)
+	return nil
+}
@@ -34,6 +34,7 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/instancegroups"
 	"k8s.io/kops/pkg/pretty"
 	"k8s.io/kops/pkg/validation"
@@ -222,7 +223,7 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	return cmd
 }
 
-func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, options *RollingUpdateOptions) error {
+func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, options *RollingUpdateOptions) (err error) {
 
 	clientset, err := f.Clientset()
 	if err != nil {
@@ -424,6 +425,13 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		return nil
 	}
 
+	lock, err := commands.LockCluster(ctx, clientset, cluster, "rolling-update cluster")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock(&err)
+	d.Ctx = lock.Context()
+
 	var clusterValidator validation.ClusterValidator
 	if !options.CloudOnly {
 		clusterValidator, err = validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient)
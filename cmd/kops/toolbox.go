@@ -49,6 +49,8 @@ func NewCmdToolbox(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.AddCommand(NewCmdToolboxDump(f, out))
 	cmd.AddCommand(NewCmdToolboxTemplate(f, out))
 	cmd.AddCommand(NewCmdToolboxInstanceSelector(f, out))
+	cmd.AddCommand(NewCmdToolboxMigrateState(f, out))
+	cmd.AddCommand(NewCmdToolboxBundle(f, out))
 
 	return cmd
 }
@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxBundleLong = templates.LongDesc(i18n.T(`
+	Create and import bundles of the files and container images that a cluster uses,
+	for building clusters in environments without internet access.`))
+
+	toolboxBundleExample = templates.Examples(i18n.T(`
+	# Collect the assets of a cluster into a bundle, on a machine with internet access
+	kops toolbox bundle create --name k8s-cluster.example.com --out bundle.tar
+
+	# Import the bundle into the air-gapped environment
+	kops toolbox bundle import --file bundle.tar \
+		--file-repository s3://my-assets/kops \
+		--container-registry registry.example.com
+	`))
+
+	toolboxBundleShort = i18n.T(`Create and import bundles of cluster assets.`)
+)
+
+func NewCmdToolboxBundle(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "bundle",
+		Short:   toolboxBundleShort,
+		Long:    toolboxBundleLong,
+		Example: toolboxBundleExample,
+	}
+
+	cmd.AddCommand(NewCmdToolboxBundleCreate(f, out))
+	cmd.AddCommand(NewCmdToolboxBundleImport(f, out))
+
+	return cmd
+}
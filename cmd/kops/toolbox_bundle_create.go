@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/bundle"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/ociregistry"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxBundleCreateLong = templates.LongDesc(i18n.T(`
+	Collect every file and container image that the cluster uses into a single tarball.
+
+	Files are verified against their hashes as they are downloaded.  Container images are
+	stored in the OCI image layout format, including every architecture of multi-architecture images.
+	Credentials for container registries are read from the docker config file.
+
+	Set spec.assets in the cluster spec before creating the bundle, so that it includes
+	all of the kops binaries that nodes download.`))
+
+	toolboxBundleCreateExample = templates.Examples(i18n.T(`
+	# Collect the assets of a cluster into a bundle
+	kops toolbox bundle create --name k8s-cluster.example.com --out bundle.tar
+	`))
+
+	toolboxBundleCreateShort = i18n.T(`Collect the files and container images that a cluster uses into a bundle.`)
+)
+
+type ToolboxBundleCreateOptions struct {
+	ClusterName string
+	Out         string
+}
+
+func NewCmdToolboxBundleCreate(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBundleCreateOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "create",
+		Short:   toolboxBundleCreateShort,
+		Long:    toolboxBundleCreateLong,
+		Example: toolboxBundleCreateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			if err := RunToolboxBundleCreate(ctx, f, out, options); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Out, "out", options.Out, "Path to write the bundle to (default kops-bundle-<cluster name>.tar)")
+
+	return cmd
+}
+
+func RunToolboxBundleCreate(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxBundleCreateOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+
+	updateClusterResults, err := RunUpdateCluster(ctx, f, options.ClusterName, out, &UpdateClusterOptions{
+		Target:    cloudup.TargetDryRun,
+		Phase:     string(cloudup.PhaseStageAssets),
+		GetAssets: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	outPath := options.Out
+	if outPath == "" {
+		outPath = "kops-bundle-" + options.ClusterName + ".tar"
+	}
+
+	file, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("error creating %q: %v", outPath, err)
+	}
+
+	manifest, err := bundle.Create(ctx, file, updateClusterResults.FileAssets, updateClusterResults.ContainerAssets, ociregistry.NewClient())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(outPath)
+		return err
+	}
+
+	fmt.Fprintf(out, "Wrote %d files and %d container images to %s\n", len(manifest.Files), len(manifest.Images), outPath)
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/bundle"
+	"k8s.io/kops/util/pkg/ociregistry"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxBundleImportLong = templates.LongDesc(i18n.T(`
+	Upload the files and container images from a bundle, so that clusters can use them
+	through spec.assets.fileRepository and spec.assets.containerRegistry.
+
+	Files are written to the file repository under the path of their original URL, along with
+	a hash file.  Container images are pushed to the container registry under the names that
+	kops uses when spec.assets.containerRegistry is set.  Credentials for the container registry
+	are read from the docker config file.`))
+
+	toolboxBundleImportExample = templates.Examples(i18n.T(`
+	# Import a bundle into an S3 bucket and a private registry
+	kops toolbox bundle import --file bundle.tar \
+		--file-repository s3://my-assets/kops \
+		--container-registry registry.example.com
+	`))
+
+	toolboxBundleImportShort = i18n.T(`Upload the files and container images in a bundle.`)
+)
+
+type ToolboxBundleImportOptions struct {
+	File              string
+	FileRepository    string
+	ContainerRegistry string
+}
+
+func NewCmdToolboxBundleImport(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBundleImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "import",
+		Short:   toolboxBundleImportShort,
+		Long:    toolboxBundleImportLong,
+		Example: toolboxBundleImportExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := RunToolboxBundleImport(ctx, out, options); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.File, "file", "f", options.File, "Path of the bundle to import")
+	cmd.Flags().StringVar(&options.FileRepository, "file-repository", options.FileRepository, "Path to upload files to, for example s3://my-assets/kops")
+	cmd.Flags().StringVar(&options.ContainerRegistry, "container-registry", options.ContainerRegistry, "Registry to push container images to, for example registry.example.com")
+
+	return cmd
+}
+
+func RunToolboxBundleImport(ctx context.Context, out io.Writer, options *ToolboxBundleImportOptions) error {
+	if options.File == "" {
+		return fmt.Errorf("--file is required")
+	}
+
+	importOptions := &bundle.ImportOptions{
+		ContainerRegistry: strings.TrimSuffix(options.ContainerRegistry, "/"),
+	}
+	if options.FileRepository != "" {
+		p, err := vfs.Context.BuildVfsPath(options.FileRepository)
+		if err != nil {
+			return fmt.Errorf("error parsing file repository %q: %v", options.FileRepository, err)
+		}
+		importOptions.FileRepository = p
+	}
+
+	file, err := os.Open(options.File)
+	if err != nil {
+		return fmt.Errorf("error opening bundle: %v", err)
+	}
+	defer file.Close()
+
+	manifest, err := bundle.Import(ctx, file, importOptions, ociregistry.NewClient())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Imported %d files and %d container images\n", len(manifest.Files), len(manifest.Images))
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "To use them, set spec.assets in the cluster spec with kops edit cluster:\n")
+	fmt.Fprintf(out, "  assets:\n")
+	if len(manifest.Files) != 0 {
+		fmt.Fprintf(out, "    fileRepository: <the URL from which nodes can download %s>\n", importOptions.FileRepository)
+	}
+	if len(manifest.Images) != 0 {
+		fmt.Fprintf(out, "    containerRegistry: %s\n", importOptions.ContainerRegistry)
+	}
+	return nil
+}
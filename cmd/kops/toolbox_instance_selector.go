@@ -29,6 +29,8 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/instanceselector"
+	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -93,7 +95,11 @@ type InstanceSelectorOptions struct {
 
 var (
 	toolboxInstanceSelectorLong = templates.LongDesc(i18n.T(`
-	Generate AWS EC2 on-demand or spot instance-groups by providing resource specs like vcpus and memory rather than instance types.`))
+	Generate on-demand or spot instance-groups by providing resource specs like vcpus and memory rather than instance types.
+
+	On AWS, instance types are selected with the EC2 API and the instance-group uses a MixedInstancesPolicy.
+	On GCE and Azure, machine types are selected from an offline catalog shipped with kops and the instance-group
+	uses the smallest matching machine type. Filters that only apply to EC2, like spot or ENA support, are rejected.`))
 
 	toolboxInstanceSelectorExample = templates.Examples(i18n.T(`
 
@@ -105,6 +111,9 @@ var (
 
 	## Create a best-practices on-demand instance-group with custom vcpus and memory range filters
 	kops toolbox instance-selector ondemand-ig --vcpus-min=2 --vcpus-max=4 --memory-min 2gb --memory-max 4gb
+
+	## Create a GCE or Azure instance-group with a machine type similar to n1-standard-4
+	kops toolbox instance-selector similar-ig --base-instance-type n1-standard-4
 	`))
 
 	toolboxInstanceSelectorShort = i18n.T(`Generate on-demand or spot instance-group specs by providing resource specs like vcpus and memory.`)
@@ -203,13 +212,6 @@ func RunToolboxInstanceSelector(ctx context.Context, f *util.Factory, args []str
 		return err
 	}
 
-	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
-		return fmt.Errorf("cannot select instance types from non-aws cluster")
-	}
-
-	firstClusterSubnet := strings.ReplaceAll(cluster.Spec.Subnets[0].Name, "utility-", "")
-	region := firstClusterSubnet[:len(firstClusterSubnet)-1]
-
 	igSubnets := []string{}
 	for _, clusterSubnet := range cluster.Spec.Subnets {
 		igSubnets = append(igSubnets, clusterSubnet.Name)
@@ -225,26 +227,56 @@ func RunToolboxInstanceSelector(ctx context.Context, f *util.Factory, args []str
 		igSubnets = userSubnets
 	}
 
-	zones := []string{}
-	for _, igSubnet := range igSubnets {
-		zones = append(zones, strings.ReplaceAll(igSubnet, "utility-", ""))
-	}
+	var cloud fi.Cloud
+	var filters selector.Filters
+	var filterInstanceTypes func(selector.Filters) ([]string, error)
 
-	tags := map[string]string{"KubernetesCluster": clusterName}
-	cloud, err := awsup.NewAWSCloud(region, tags)
-	if err != nil {
-		return fmt.Errorf("error initializing AWS client: %v", err)
-	}
+	cloudProvider := kops.CloudProviderID(cluster.Spec.CloudProvider)
+	switch cloudProvider {
+	case kops.CloudProviderAWS:
+		firstClusterSubnet := strings.ReplaceAll(cluster.Spec.Subnets[0].Name, "utility-", "")
+		region := firstClusterSubnet[:len(firstClusterSubnet)-1]
+
+		zones := []string{}
+		for _, igSubnet := range igSubnets {
+			zones = append(zones, strings.ReplaceAll(igSubnet, "utility-", ""))
+		}
+
+		tags := map[string]string{"KubernetesCluster": clusterName}
+		awsCloud, err := awsup.NewAWSCloud(region, tags)
+		if err != nil {
+			return fmt.Errorf("error initializing AWS client: %v", err)
+		}
+		cloud = awsCloud
+
+		instanceSelector := selector.Selector{
+			EC2: awsCloud.EC2(),
+		}
+		filterInstanceTypes = instanceSelector.Filter
+		filters = getFilters(commandline, region, zones)
+
+	case kops.CloudProviderGCE, kops.CloudProviderAzure:
+		cloud, err = cloudup.BuildCloud(cluster)
+		if err != nil {
+			return err
+		}
+
+		// GCE and Azure have no API to filter machine types by their specs, so we filter an offline catalog
+		catalog := instanceselector.NewGCECatalog()
+		if cloudProvider == kops.CloudProviderAzure {
+			catalog = instanceselector.NewAzureCatalog()
+		}
+		filterInstanceTypes = catalog.Filter
+		filters = getFilters(commandline, "", nil)
 
-	instanceSelector := selector.Selector{
-		EC2: cloud.EC2(),
+	default:
+		return fmt.Errorf("cannot select instance types from %s cluster, only aws, gce and azure are supported", cloudProvider)
 	}
 
 	igCount := instanceSelectorOpts.InstanceGroupCount
 	if flags[instanceGroupCount] == nil {
 		igCount = 1
 	}
-	filters := getFilters(commandline, region, zones)
 	mutatedFilters := filters
 	if flags[instanceGroupCount] != nil || filters.Flexible != nil {
 		if filters.VCpusToMemoryRatio == nil {
@@ -260,7 +292,7 @@ func RunToolboxInstanceSelector(ctx context.Context, f *util.Factory, args []str
 		if igCount != 1 {
 			igNameForRun = fmt.Sprintf("%s%d", igName, i+1)
 		}
-		selectedInstanceTypes, err := instanceSelector.Filter(mutatedFilters)
+		selectedInstanceTypes, err := filterInstanceTypes(mutatedFilters)
 		if err != nil {
 			return fmt.Errorf("error finding matching instance types: %w", err)
 		}
@@ -271,9 +303,13 @@ func RunToolboxInstanceSelector(ctx context.Context, f *util.Factory, args []str
 
 		ig := createInstanceGroup(igNameForRun, clusterName, igSubnets)
 		ig = decorateWithInstanceGroupSpecs(ig, instanceSelectorOpts)
-		ig, err = decorateWithMixedInstancesPolicy(ig, usageClass, selectedInstanceTypes)
-		if err != nil {
-			return err
+		if cloudProvider == kops.CloudProviderAWS {
+			ig, err = decorateWithMixedInstancesPolicy(ig, usageClass, selectedInstanceTypes)
+			if err != nil {
+				return err
+			}
+		} else {
+			ig = decorateWithMachineType(ig, selectedInstanceTypes)
 		}
 		if instanceSelectorOpts.ClusterAutoscaler {
 			ig = decorateWithClusterAutoscalerLabels(ig, clusterName)
@@ -500,13 +536,28 @@ func decorateWithMixedInstancesPolicy(instanceGroup *kops.InstanceGroup, usageCl
 		return nil, fmt.Errorf("error node usage class not supported")
 	}
 
+	ig = decorateWithInstanceSelectorLabel(ig)
+
+	return ig, nil
+}
+
+// decorateWithMachineType sets the smallest selected machine type on the instance-group,
+// for clouds where an instance-group only runs a single machine type
+func decorateWithMachineType(instanceGroup *kops.InstanceGroup, instanceSelections []string) *kops.InstanceGroup {
+	ig := instanceGroup
+	ig.Spec.MachineType = instanceSelections[0]
+	return decorateWithInstanceSelectorLabel(ig)
+}
+
+// decorateWithInstanceSelectorLabel marks the instance-group as generated by the instance-selector
+func decorateWithInstanceSelectorLabel(instanceGroup *kops.InstanceGroup) *kops.InstanceGroup {
+	ig := instanceGroup
 	generatedWithLabelKey := "kops.k8s.io/instance-selector"
 	if ig.Spec.CloudLabels == nil {
 		ig.Spec.CloudLabels = make(map[string]string)
 	}
 	ig.Spec.CloudLabels[generatedWithLabelKey] = "1"
-
-	return ig, nil
+	return ig
 }
 
 // decorateWithClusterAutoscalerLabels adds cluster-autoscaler discovery tags to the cloudlabels slice
@@ -192,6 +192,20 @@ func TestDecorateWithMixedInstancesPolicy(t *testing.T) {
 	}
 }
 
+func TestDecorateWithMachineType(t *testing.T) {
+	selectedMachineTypes := []string{"n1-standard-2", "n2-standard-2", "e2-standard-2"}
+	actualIG := decorateWithMachineType(&kops.InstanceGroup{}, selectedMachineTypes)
+	if actualIG.Spec.MachineType != "n1-standard-2" {
+		t.Fatalf("MachineType should be the first selected machine type, got %q", actualIG.Spec.MachineType)
+	}
+	if actualIG.Spec.MixedInstancesPolicy != nil {
+		t.Fatal("MixedInstancesPolicy should be nil")
+	}
+	if _, ok := actualIG.Spec.CloudLabels["kops.k8s.io/instance-selector"]; !ok {
+		t.Fatal("instance-selector cloudLabel should have been added to the instance group spec")
+	}
+}
+
 func TestDecorateWithClusterAutoscalerLabels(t *testing.T) {
 	initialIG := kops.InstanceGroup{}
 	clusterName := "testClusterName"
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxMigrateStateLong = templates.LongDesc(i18n.T(`
+	Copy the state of a cluster to another state store.
+
+	Every object under the cluster's configBase, keyStore and secretStore is copied
+	to the new state store and verified, and the cluster spec is rewritten to refer
+	to the new locations. The existing state is not removed.`))
+
+	toolboxMigrateStateExample = templates.Examples(i18n.T(`
+	# Preview moving a cluster's state from S3 to GCS
+	kops toolbox migrate-state --name k8s-cluster.example.com \
+	  --state=s3://kops-state-1234 --to=gs://kops-state-5678
+
+	# Move the cluster's state
+	kops toolbox migrate-state --name k8s-cluster.example.com \
+	  --state=s3://kops-state-1234 --to=gs://kops-state-5678 --yes
+	`))
+
+	toolboxMigrateStateShort = i18n.T(`Copy the state of a cluster to another state store.`)
+)
+
+type ToolboxMigrateStateOptions struct {
+	ClusterName string
+	To          string
+	Yes         bool
+}
+
+func NewCmdToolboxMigrateState(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxMigrateStateOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "migrate-state",
+		Short:   toolboxMigrateStateShort,
+		Long:    toolboxMigrateStateLong,
+		Example: toolboxMigrateStateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxMigrateState(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.To, "to", options.To, "VFS path of the state store to copy the cluster state to")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Copy the cluster state (otherwise only the changes are shown)")
+
+	return cmd
+}
+
+func RunToolboxMigrateState(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxMigrateStateOptions) (err error) {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+	if options.To == "" {
+		return fmt.Errorf("--to is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if cluster == nil {
+		return fmt.Errorf("cluster %q not found", options.ClusterName)
+	}
+
+	if options.Yes {
+		var lock *commands.ClusterLock
+		lock, err = commands.LockCluster(ctx, clientset, cluster, "toolbox migrate-state")
+		if err != nil {
+			return err
+		}
+		defer lock.Unlock(&err)
+		ctx = lock.Context()
+	}
+
+	return commands.MigrateState(ctx, out, cluster, &commands.MigrateStateOptions{
+		To:     options.To,
+		DryRun: !options.Yes,
+	})
+}
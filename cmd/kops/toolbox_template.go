@@ -27,6 +27,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"helm.sh/helm/v3/pkg/strvals"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 	"sigs.k8s.io/yaml"
@@ -35,6 +36,8 @@ import (
 
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/validation"
+	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/try"
 	"k8s.io/kops/pkg/util/templater"
 	"k8s.io/kops/upup/pkg/fi/utils"
@@ -43,6 +46,11 @@ import (
 var (
 	toolboxTemplatingLong = templates.LongDesc(i18n.T(`
 	Generate cluster.yaml from values input yaml file and apply template.
+
+	The values can be validated against a JSON-schema before rendering, either with
+	--values-schema or by shipping a values.schema.json file in a template directory.
+	Partials are files of named templates ({{ define "name" }}) that can be used by all
+	the templates. With --validate the rendered clusters and instancegroups are validated.
 	`))
 
 	toolboxTemplatingExample = templates.Examples(i18n.T(`
@@ -54,6 +62,13 @@ var (
 		--snippets file_or_directory --snippets=another.dir \
 		--template file_or_directory --template=directory  \
 		--output cluster.yaml
+
+	# validate the values against a schema and the rendered cluster spec
+
+	kops toolbox template \
+		--values values.yaml --values-schema values.schema.json \
+		--partials partials/ --template templates/ \
+		--validate --output cluster.yaml
 	`))
 
 	toolboxTemplatingShort = i18n.T(`Generate cluster.yaml from template`)
@@ -72,6 +87,10 @@ type toolboxTemplateOption struct {
 	values        []string
 	stringValues  []string
 	channel       string
+
+	partialsPath     []string
+	valuesSchemaPath []string
+	validate         bool
 }
 
 // NewCmdToolboxTemplate returns a new templating command
@@ -105,6 +124,9 @@ func NewCmdToolboxTemplate(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().StringVar(&options.configValue, "config-value", "", "Show the value of a specific configuration value")
 	cmd.Flags().BoolVar(&options.failOnMissing, "fail-on-missing", true, "Fail on referencing unset variables in templates")
 	cmd.Flags().BoolVar(&options.formatYAML, "format-yaml", false, "Attempt to format the generated yaml content before output")
+	cmd.Flags().StringSliceVar(&options.partialsPath, "partials", options.partialsPath, "Path to file or directory of partials defining named templates shared by all templates")
+	cmd.Flags().StringSliceVar(&options.valuesSchemaPath, "values-schema", options.valuesSchemaPath, "Path to a JSON-schema the values must match before rendering")
+	cmd.Flags().BoolVar(&options.validate, "validate", false, "Validate the rendered clusters and instancegroups")
 
 	return cmd
 }
@@ -137,14 +159,33 @@ func runToolBoxTemplate(f *util.Factory, out io.Writer, options *toolboxTemplate
 		return nil
 	}
 
-	// @step: expand the list of templates into a list of files to render
+	// @step: expand the list of templates into a list of files to render, a schema shipped
+	// with the templates is used to validate the values rather than rendered
 	var templates []string
+	schemas := options.valuesSchemaPath
 	for _, x := range options.templatePath {
 		list, err := expandFiles(utils.ExpandPath(x))
 		if err != nil {
 			return fmt.Errorf("unable to expand the template: %s, error: %s", x, err)
 		}
-		templates = append(templates, list...)
+		for _, j := range list {
+			if filepath.Base(j) == templater.ValuesSchemaFile {
+				schemas = append(schemas, j)
+				continue
+			}
+			templates = append(templates, j)
+		}
+	}
+
+	// @step: validate the values against the schemas
+	for _, x := range schemas {
+		schema, err := ioutil.ReadFile(utils.ExpandPath(x))
+		if err != nil {
+			return fmt.Errorf("unable to read values schema: %s, error: %s", x, err)
+		}
+		if err := templater.ValidateValues(context, schema); err != nil {
+			return fmt.Errorf("invalid values for schema: %s, error: %s", x, err)
+		}
 	}
 
 	snippets := make(map[string]string)
@@ -173,8 +214,25 @@ func runToolBoxTemplate(f *util.Factory, out io.Writer, options *toolboxTemplate
 		return fmt.Errorf("error loading channel %q: %v", channelLocation, err)
 	}
 
-	// @step: render each of the templates, splitting on the documents
 	r := templater.NewTemplater(channel)
+	for _, x := range options.partialsPath {
+		list, err := expandFiles(utils.ExpandPath(x))
+		if err != nil {
+			return fmt.Errorf("unable to expand the partials: %s, error: %s", x, err)
+		}
+
+		for _, j := range list {
+			content, err := ioutil.ReadFile(j)
+			if err != nil {
+				return fmt.Errorf("unable to read partial: %s, error: %s", j, err)
+			}
+			if err := r.AddPartial(j, string(content)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// @step: render each of the templates, splitting on the documents
 	var documents []string
 	for _, x := range templates {
 		content, err := ioutil.ReadFile(x)
@@ -211,6 +269,14 @@ func runToolBoxTemplate(f *util.Factory, out io.Writer, options *toolboxTemplate
 			documents = append(documents, string(formatted))
 		}
 	}
+
+	// @check if the rendered clusters and instancegroups should be validated
+	if options.validate {
+		if err := validateTemplateDocuments(documents); err != nil {
+			return err
+		}
+	}
+
 	// join in harmony all the YAML documents back together
 	content := strings.Join(documents, "---\n")
 
@@ -232,6 +298,41 @@ func runToolBoxTemplate(f *util.Factory, out io.Writer, options *toolboxTemplate
 	return nil
 }
 
+// validateTemplateDocuments validates the clusters and instancegroups in the rendered documents,
+// other kinds of document are left alone
+func validateTemplateDocuments(documents []string) error {
+	var errs []string
+	for _, x := range documents {
+		if strings.TrimSpace(x) == "" {
+			continue
+		}
+		o, _, err := kopscodecs.Decode([]byte(x), nil)
+		if err != nil {
+			// @check this is not a kops object
+			if runtime.IsNotRegisteredError(err) {
+				continue
+			}
+			return fmt.Errorf("unable to decode rendered document, error: %s", err)
+		}
+
+		switch v := o.(type) {
+		case *kopsapi.Cluster:
+			if err := validation.ValidateCluster(v, false).ToAggregate(); err != nil {
+				errs = append(errs, fmt.Sprintf("cluster %q: %s", v.ObjectMeta.Name, err))
+			}
+		case *kopsapi.InstanceGroup:
+			if err := validation.ValidateInstanceGroup(v, nil).ToAggregate(); err != nil {
+				errs = append(errs, fmt.Sprintf("instancegroup %q: %s", v.ObjectMeta.Name, err))
+			}
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("rendered templates are invalid:\n%s", strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
+
 // newTemplateContext is responsible for loading the --values and build a context for the template
 func newTemplateContext(files []string, values []string, stringValues []string) (map[string]interface{}, error) {
 	context := make(map[string]interface{})
@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -26,3 +27,35 @@ func TestNewTemplateContext(t *testing.T) {
 		t.Errorf("Got %v, expected baz", context["foo"])
 	}
 }
+
+func TestValidateTemplateDocuments(t *testing.T) {
+	cluster := `apiVersion: kops.k8s.io/v1alpha2
+kind: Cluster
+metadata:
+  name: minimal.example.com
+spec:
+  kubernetesVersion: v1.20.0
+`
+	instanceGroup := `apiVersion: kops.k8s.io/v1alpha2
+kind: InstanceGroup
+metadata:
+  name: nodes
+spec:
+  role: %s
+`
+	other := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+`
+
+	if err := validateTemplateDocuments([]string{fmt.Sprintf(instanceGroup, "Node"), other, ""}); err != nil {
+		t.Errorf("unexpected error validating documents: %v", err)
+	}
+	if err := validateTemplateDocuments([]string{fmt.Sprintf(instanceGroup, "Worker")}); err == nil {
+		t.Errorf("expected an error validating an instancegroup with an invalid role")
+	}
+	if err := validateTemplateDocuments([]string{cluster}); err == nil {
+		t.Errorf("expected an error validating an incomplete cluster")
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -32,10 +33,13 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/resourcegraph"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/upup/pkg/kutil"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -63,6 +67,8 @@ type UpdateClusterOptions struct {
 	Yes                bool
 	Target             string
 	OutDir             string
+	TerraformModule    bool
+	TerraformImport    bool
 	SSHPublicKey       string
 	RunTasksOptions    fi.RunTasksOptions
 	AllowKopsDowngrade bool
@@ -77,6 +83,12 @@ type UpdateClusterOptions struct {
 	// LifecycleOverrides is a slice of taskName=lifecycle name values.  This slice is used
 	// to populate the LifecycleOverrides struct member in ApplyClusterCmd struct.
 	LifecycleOverrides []string
+
+	// GetAssets only discovers the assets that the cluster uses, without applying any changes.
+	GetAssets bool
+
+	// CloudformationNestedStacks splits the cloudformation output into a nested stack per instance group
+	CloudformationNestedStacks bool
 }
 
 func (o *UpdateClusterOptions) InitDefaults() {
@@ -117,9 +129,12 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Create cloud resources, without --yes update is in dry run mode")
-	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Target - direct, terraform, cloudformation")
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Target - direct, terraform, cloudformation, resourcegraph")
 	cmd.Flags().StringVar(&options.SSHPublicKey, "ssh-public-key", options.SSHPublicKey, "SSH public key to use (deprecated: use kops create secret instead)")
 	cmd.Flags().StringVar(&options.OutDir, "out", options.OutDir, "Path to write any local output")
+	cmd.Flags().BoolVar(&options.TerraformModule, "terraform-module", options.TerraformModule, "Write the terraform output as a module, with variables for instance group sizes, images and machine types")
+	cmd.Flags().BoolVar(&options.TerraformImport, "terraform-import", options.TerraformImport, "Also write import.sh, which imports the existing cloud resources of the cluster into terraform state")
+	cmd.Flags().BoolVar(&options.CloudformationNestedStacks, "cloudformation-nested-stacks", options.CloudformationNestedStacks, "Split the cloudformation output into a nested stack for each instance group")
 	cmd.Flags().BoolVar(&options.CreateKubecfg, "create-kube-config", options.CreateKubecfg, "Will control automatically creating the kube config file on your local filesystem")
 	cmd.Flags().DurationVar(&options.admin, "admin", options.admin, "Also export a cluster admin user credential with the specified lifetime and add it to the cluster context")
 	cmd.Flags().Lookup("admin").NoOptDefVal = kubeconfig.DefaultKubecfgAdminLifetime.String()
@@ -140,9 +155,18 @@ type UpdateClusterResults struct {
 
 	// TaskMap is the map of tasks that we built (output)
 	TaskMap map[string]fi.Task
+
+	// ContainerAssets are the container images the cluster uses (output)
+	ContainerAssets []*assets.ContainerAsset
+
+	// FileAssets are the files the cluster uses (output)
+	FileAssets []*assets.FileAsset
+
+	// FileAssetUsage records which nodes download each file, keyed by the URL it is downloaded from (output)
+	FileAssetUsage map[string]*cloudup.FileAssetUsage
 }
 
-func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string, out io.Writer, c *UpdateClusterOptions) (*UpdateClusterResults, error) {
+func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string, out io.Writer, c *UpdateClusterOptions) (_ *UpdateClusterResults, err error) {
 	results := &UpdateClusterResults{}
 
 	isDryrun := false
@@ -179,11 +203,23 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 		targetName = cloudup.TargetDryRun
 	}
 
+	if c.TerraformModule && c.Target != cloudup.TargetTerraform {
+		return results, fmt.Errorf("--terraform-module can only be used with --target=%s", cloudup.TargetTerraform)
+	}
+	if c.TerraformImport && c.Target != cloudup.TargetTerraform {
+		return results, fmt.Errorf("--terraform-import can only be used with --target=%s", cloudup.TargetTerraform)
+	}
+	if c.CloudformationNestedStacks && c.Target != cloudup.TargetCloudformation {
+		return results, fmt.Errorf("--cloudformation-nested-stacks can only be used with --target=%s", cloudup.TargetCloudformation)
+	}
+
 	if c.OutDir == "" {
 		if c.Target == cloudup.TargetTerraform {
 			c.OutDir = "out/terraform"
 		} else if c.Target == cloudup.TargetCloudformation {
 			c.OutDir = "out/cloudformation"
+		} else if c.Target == cloudup.TargetResourceGraph {
+			c.OutDir = "out/resourcegraph"
 		} else {
 			c.OutDir = "out"
 		}
@@ -199,6 +235,16 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 		return results, err
 	}
 
+	if !isDryrun {
+		var lock *commands.ClusterLock
+		lock, err = commands.LockCluster(ctx, clientset, cluster, "update cluster")
+		if err != nil {
+			return results, err
+		}
+		defer lock.Unlock(&err)
+		ctx = lock.Context()
+	}
+
 	keyStore, err := clientset.KeyStore(cluster)
 	if err != nil {
 		return results, err
@@ -278,15 +324,27 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 		AllowKopsDowngrade: c.AllowKopsDowngrade,
 		RunTasksOptions:    &c.RunTasksOptions,
 		OutDir:             c.OutDir,
+		TerraformModule:    c.TerraformModule,
+		TerraformImport:    c.TerraformImport,
 		Phase:              phase,
 		TargetName:         targetName,
 		LifecycleOverrides: lifecycleOverrideMap,
+		GetAssets:          c.GetAssets,
+
+		CloudformationNestedStacks: c.CloudformationNestedStacks,
 	}
 
 	if err := applyCmd.Run(ctx); err != nil {
 		return results, err
 	}
 
+	if c.GetAssets {
+		results.ContainerAssets = applyCmd.ContainerAssets
+		results.FileAssets = applyCmd.FileAssets
+		results.FileAssetUsage = applyCmd.FileAssetUsage
+		return results, nil
+	}
+
 	results.Target = applyCmd.Target
 	results.TaskMap = applyCmd.TaskMap
 
@@ -359,10 +417,25 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 			if firstRun {
 				cfName := "kubernetes-" + strings.Replace(clusterName, ".", "-", -1)
 				cfPath := filepath.Join(c.OutDir, "kubernetes.json")
-				fmt.Fprintf(sb, "Run this command to apply the configuration:\n")
-				fmt.Fprintf(sb, "   aws cloudformation create-stack --capabilities CAPABILITY_NAMED_IAM --stack-name %s --template-body file://%s\n", cfName, cfPath)
+				if c.CloudformationNestedStacks {
+					packagedPath := filepath.Join(c.OutDir, "packaged.json")
+					fmt.Fprintf(sb, "Run these commands to upload the nested stacks and apply the configuration:\n")
+					fmt.Fprintf(sb, "   aws cloudformation package --template-file %s --s3-bucket <bucket> --output-template-file %s\n", cfPath, packagedPath)
+					fmt.Fprintf(sb, "   aws cloudformation deploy --capabilities CAPABILITY_NAMED_IAM --stack-name %s --template-file %s\n", cfName, packagedPath)
+				} else if info, err := os.Stat(cfPath); err == nil && info.Size() > cloudformation.MaxTemplateBodySize {
+					// CloudFormation rejects larger templates passed with --template-body, so they are uploaded to S3
+					fmt.Fprintf(sb, "Run this command to upload the template and apply the configuration:\n")
+					fmt.Fprintf(sb, "   aws cloudformation deploy --capabilities CAPABILITY_NAMED_IAM --stack-name %s --template-file %s --s3-bucket <bucket>\n", cfName, cfPath)
+				} else {
+					fmt.Fprintf(sb, "Run this command to apply the configuration:\n")
+					fmt.Fprintf(sb, "   aws cloudformation create-stack --capabilities CAPABILITY_NAMED_IAM --stack-name %s --template-body file://%s\n", cfName, cfPath)
+				}
 				fmt.Fprintf(sb, "\n")
 			}
+		} else if c.Target == cloudup.TargetResourceGraph {
+			fmt.Fprintf(sb, "\n")
+			fmt.Fprintf(sb, "The resource graph has been placed into %s\n", filepath.Join(c.OutDir, resourcegraph.OutputFile))
+			fmt.Fprintf(sb, "\n")
 		} else if firstRun {
 			fmt.Fprintf(sb, "\n")
 			fmt.Fprintf(sb, "Cluster is starting.  It should be ready in a few minutes.\n")
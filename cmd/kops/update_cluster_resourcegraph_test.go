@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path"
+	"testing"
+	"time"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/pkg/testutils/golden"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/resourcegraph"
+)
+
+// TestMinimalResourceGraph runs the resource graph target on a minimum configuration
+func TestMinimalResourceGraph(t *testing.T) {
+	ctx := context.Background()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.MockKopsVersion("1.21.0-alpha.1")
+	h.SetupMockAWS()
+
+	srcDir := updateClusterTestBase + "minimal"
+	clusterName := "minimal.example.com"
+
+	factory := util.NewFactory(&util.FactoryOptions{RegistryPath: "memfs://tests"})
+
+	var stdout bytes.Buffer
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(srcDir, "in-v1alpha2.yaml")}
+		if err := RunCreate(ctx, factory, &stdout, options); err != nil {
+			t.Fatalf("error running create: %v", err)
+		}
+	}
+	{
+		options := &CreateSecretPublickeyOptions{}
+		options.ClusterName = clusterName
+		options.Name = "admin"
+		options.PublicKeyPath = path.Join(srcDir, "id_rsa.pub")
+		if err := RunCreateSecretPublicKey(ctx, factory, &stdout, options); err != nil {
+			t.Fatalf("error creating public key: %v", err)
+		}
+	}
+
+	outDir := path.Join(h.TempDir, "out")
+	{
+		options := &UpdateClusterOptions{}
+		options.InitDefaults()
+		options.Target = cloudup.TargetResourceGraph
+		options.OutDir = outDir
+		options.RunTasksOptions.MaxTaskDuration = 30 * time.Second
+		options.CreateKubecfg = false
+		if _, err := RunUpdateCluster(ctx, factory, clusterName, &stdout, options); err != nil {
+			t.Fatalf("error running update cluster: %v", err)
+		}
+	}
+
+	actual, err := ioutil.ReadFile(path.Join(outDir, resourcegraph.OutputFile))
+	if err != nil {
+		t.Fatalf("error reading resource graph: %v", err)
+	}
+	golden.AssertMatchesFile(t, string(actual), path.Join(srcDir, "resourcegraph.json"))
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"testing"
+	"time"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/pkg/testutils/golden"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+)
+
+// TestTerraformImport creates a cluster with the direct target, then checks that the terraform output
+// is unchanged and that the import script covers the resources that were created
+func TestTerraformImport(t *testing.T) {
+	runTerraformImportTest(t, "minimal.example.com", "minimal")
+}
+
+// TestTerraformImportComplex covers the network load balancer and additional VPC CIDR blocks
+func TestTerraformImportComplex(t *testing.T) {
+	runTerraformImportTest(t, "complex.example.com", "complex")
+}
+
+func runTerraformImportTest(t *testing.T, clusterName string, srcDir string) {
+	ctx := context.Background()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.MockKopsVersion("1.21.0-alpha.1")
+	h.SetupMockAWS()
+
+	srcDir = updateClusterTestBase + srcDir
+
+	factory := util.NewFactory(&util.FactoryOptions{RegistryPath: "memfs://tests"})
+
+	var stdout bytes.Buffer
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(srcDir, "in-v1alpha2.yaml")}
+		if err := RunCreate(ctx, factory, &stdout, options); err != nil {
+			t.Fatalf("error running create: %v", err)
+		}
+	}
+	{
+		options := &CreateSecretPublickeyOptions{}
+		options.ClusterName = clusterName
+		options.Name = "admin"
+		options.PublicKeyPath = path.Join(srcDir, "id_rsa.pub")
+		if err := RunCreateSecretPublicKey(ctx, factory, &stdout, options); err != nil {
+			t.Fatalf("error creating public key: %v", err)
+		}
+	}
+	{
+		options := &UpdateClusterOptions{}
+		options.InitDefaults()
+		options.Yes = true
+		options.RunTasksOptions.MaxTaskDuration = 10 * time.Second
+		options.CreateKubecfg = false
+		if _, err := RunUpdateCluster(ctx, factory, clusterName, &stdout, options); err != nil {
+			t.Fatalf("error running update cluster: %v", err)
+		}
+	}
+
+	outDir := path.Join(h.TempDir, "out")
+	{
+		options := &UpdateClusterOptions{}
+		options.InitDefaults()
+		options.Target = cloudup.TargetTerraform
+		options.TerraformImport = true
+		options.OutDir = outDir
+		options.RunTasksOptions.MaxTaskDuration = 10 * time.Second
+		options.CreateKubecfg = false
+		if _, err := RunUpdateCluster(ctx, factory, clusterName, &stdout, options); err != nil {
+			t.Fatalf("error running update cluster: %v", err)
+		}
+	}
+
+	actualTF, err := ioutil.ReadFile(path.Join(outDir, "kubernetes.tf"))
+	if err != nil {
+		t.Fatalf("error reading terraform output: %v", err)
+	}
+	golden.AssertMatchesFile(t, string(actualTF), path.Join(srcDir, "kubernetes.tf"))
+
+	actualImport, err := ioutil.ReadFile(path.Join(outDir, "import.sh"))
+	if err != nil {
+		t.Fatalf("error reading import script: %v", err)
+	}
+	// The mock cloud numbers resources in the order that tasks create them, which varies
+	normalized := mockIDs.ReplaceAllString(string(actualImport), "${1}${2}-x")
+	normalized = mockARNs.ReplaceAllStringFunc(normalized, func(arn string) string {
+		return mockARNNumbers.ReplaceAllString(arn, "/x")
+	})
+	golden.AssertMatchesFile(t, normalized, path.Join(srcDir, "import.sh"))
+}
+
+var (
+	mockIDs        = regexp.MustCompile(`([^a-z])(dopt|igw|lt|rtb|sg|subnet|vol|vpc)-[0-9]+(-[0-9]+)?`)
+	mockARNs       = regexp.MustCompile(`arn:aws:elasticloadbalancing:[^']*`)
+	mockARNNumbers = regexp.MustCompile(`/[0-9]+`)
+)
@@ -73,8 +73,8 @@ type upgradeAction struct {
 	apply func()
 }
 
-func (c *UpgradeClusterCmd) Run(ctx context.Context, args []string) error {
-	err := rootCommand.ProcessArgs(args)
+func (c *UpgradeClusterCmd) Run(ctx context.Context, args []string) (err error) {
+	err = rootCommand.ProcessArgs(args)
 	if err != nil {
 		return err
 	}
@@ -257,10 +257,22 @@ func (c *UpgradeClusterCmd) Run(ctx context.Context, args []string) error {
 		fmt.Printf("\nMust specify --yes to perform upgrade\n")
 		return nil
 	}
+
+	lock, err := commands.LockCluster(ctx, clientset, cluster, "upgrade cluster")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock(&err)
+	ctx = lock.Context()
+
 	for _, action := range actions {
 		action.apply()
 	}
 
+	if err := lock.Err(); err != nil {
+		return err
+	}
+
 	if err := commands.UpdateCluster(ctx, clientset, cluster, instanceGroups); err != nil {
 		return err
 	}
@@ -46,7 +46,7 @@ require (
 	github.com/Masterminds/sprig/v3 v3.2.2
 	github.com/aliyun/alibaba-cloud-sdk-go v1.61.979
 	github.com/aws/amazon-ec2-instance-selector/v2 v2.0.2
-	github.com/aws/aws-sdk-go v1.37.30
+	github.com/aws/aws-sdk-go v1.38.16
 	github.com/blang/semver/v4 v4.0.0
 	github.com/denverdino/aliyungo v0.0.0-20210222084345-ddfe3452f5e8
 	github.com/digitalocean/godo v1.58.0
@@ -54,12 +54,14 @@ require (
 	github.com/go-bindata/go-bindata/v3 v3.1.3
 	github.com/go-ini/ini v1.62.0
 	github.com/go-logr/logr v0.4.0
+	github.com/gofrs/uuid v4.0.0+incompatible
 	github.com/gogo/protobuf v1.3.2
 	github.com/google/go-cmp v0.5.5
 	github.com/google/uuid v1.2.0
 	github.com/gophercloud/gophercloud v0.16.0
 	github.com/hashicorp/hcl/v2 v2.9.1
 	github.com/hashicorp/vault/api v1.0.4
+	github.com/hetznercloud/hcloud-go v1.30.0
 	github.com/jacksontj/memberlistmesh v0.0.0-20190905163944-93462b9d2bb7
 	github.com/jetstack/cert-manager v1.2.0
 	github.com/mitchellh/mapstructure v1.4.1
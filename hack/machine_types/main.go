@@ -0,0 +1,233 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// machine_types generates the offline machine type catalogs used for GCE and Azure.
+//
+// It reads the output of
+//   gcloud compute machine-types list --format=json
+// or
+//   az vm list-skus --resource-type virtualMachines --output json
+// from stdin, and writes the catalog as go source.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const header = `/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by hack/machine_types; DO NOT EDIT.
+
+`
+
+// machineType is the cloud-neutral form of a catalog entry
+type machineType struct {
+	Name      string
+	Cores     int
+	MemoryGB  float64
+	GPUs      int
+	Arm64     bool
+	Burstable bool
+}
+
+// gceMachineType is an entry of `gcloud compute machine-types list --format=json`
+type gceMachineType struct {
+	Name         string `json:"name"`
+	GuestCpus    int    `json:"guestCpus"`
+	MemoryMb     int    `json:"memoryMb"`
+	IsSharedCpu  bool   `json:"isSharedCpu"`
+	Accelerators []struct {
+		GuestAcceleratorCount int `json:"guestAcceleratorCount"`
+	} `json:"accelerators"`
+}
+
+// azureSKU is an entry of `az vm list-skus --resource-type virtualMachines --output json`
+type azureSKU struct {
+	Name         string `json:"name"`
+	ResourceType string `json:"resourceType"`
+	Capabilities []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"capabilities"`
+}
+
+func main() {
+	cloud := flag.String("cloud", "", "Cloud of the machine types read from stdin: gce or azure")
+	out := flag.String("out", "", "Path of the go file to write")
+	flag.Parse()
+
+	if err := run(*cloud, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(cloud string, out string) error {
+	if out == "" {
+		return fmt.Errorf("--out is required")
+	}
+
+	data, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("error reading stdin: %v", err)
+	}
+
+	var machineTypes []*machineType
+	var pkg string
+	switch cloud {
+	case "gce":
+		pkg = "gce"
+		machineTypes, err = parseGCE(data)
+	case "azure":
+		pkg = "azure"
+		machineTypes, err = parseAzure(data)
+	default:
+		return fmt.Errorf("unknown cloud %q, must be gce or azure", cloud)
+	}
+	if err != nil {
+		return err
+	}
+
+	src, err := render(pkg, machineTypes)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0644)
+}
+
+func parseGCE(data []byte) ([]*machineType, error) {
+	var list []gceMachineType
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("error parsing gce machine types: %v", err)
+	}
+
+	// The list contains an entry for each zone
+	byName := make(map[string]*machineType)
+	for _, x := range list {
+		m := &machineType{
+			Name:      x.Name,
+			Cores:     x.GuestCpus,
+			MemoryGB:  roundGB(float64(x.MemoryMb) / 1024),
+			Burstable: x.IsSharedCpu,
+			Arm64:     strings.HasPrefix(x.Name, "t2a-"),
+		}
+		for _, a := range x.Accelerators {
+			m.GPUs += a.GuestAcceleratorCount
+		}
+		byName[m.Name] = m
+	}
+	return sortedMachineTypes(byName), nil
+}
+
+func parseAzure(data []byte) ([]*machineType, error) {
+	var list []azureSKU
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("error parsing azure skus: %v", err)
+	}
+
+	// The list contains an entry for each location
+	byName := make(map[string]*machineType)
+	for _, x := range list {
+		if x.ResourceType != "virtualMachines" {
+			continue
+		}
+		m := &machineType{
+			Name:      x.Name,
+			Burstable: strings.HasPrefix(x.Name, "Standard_B"),
+		}
+		for _, c := range x.Capabilities {
+			switch c.Name {
+			case "vCPUs":
+				m.Cores, _ = strconv.Atoi(c.Value)
+			case "MemoryGB":
+				memoryGB, _ := strconv.ParseFloat(c.Value, 64)
+				m.MemoryGB = roundGB(memoryGB)
+			case "GPUs":
+				m.GPUs, _ = strconv.Atoi(c.Value)
+			case "CpuArchitectureType":
+				m.Arm64 = strings.EqualFold(c.Value, "Arm64")
+			}
+		}
+		if m.Cores == 0 {
+			continue
+		}
+		byName[m.Name] = m
+	}
+	return sortedMachineTypes(byName), nil
+}
+
+func roundGB(gb float64) float64 {
+	return math.Round(gb*100) / 100
+}
+
+func sortedMachineTypes(byName map[string]*machineType) []*machineType {
+	var machineTypes []*machineType
+	for _, m := range byName {
+		machineTypes = append(machineTypes, m)
+	}
+	sort.Slice(machineTypes, func(i, j int) bool {
+		return machineTypes[i].Name < machineTypes[j].Name
+	})
+	return machineTypes
+}
+
+func render(pkg string, machineTypes []*machineType) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(header)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import \"k8s.io/kops/util/pkg/architectures\"\n\n")
+	b.WriteString("// machineTypeCatalog is the offline list of machine types, regenerate it with `make update-machine-types`\n")
+	b.WriteString("var machineTypeCatalog = []*MachineTypeInfo{\n")
+	for _, m := range machineTypes {
+		arch := "architectures.ArchitectureAmd64"
+		if m.Arm64 {
+			arch = "architectures.ArchitectureArm64"
+		}
+		fmt.Fprintf(&b, "\t{Name: %q, Cores: %d, MemoryGB: %s, GPUs: %d, Architecture: %s, Burstable: %t},\n",
+			m.Name, m.Cores, strconv.FormatFloat(m.MemoryGB, 'f', -1, 64), m.GPUs, arch, m.Burstable)
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error formatting catalog: %v", err)
+	}
+	return src, nil
+}
@@ -24,6 +24,7 @@ import (
 
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 )
 
@@ -53,6 +54,8 @@ type azureCloudConfig struct {
 	RouteTableName string `json:"routeTableName,omitempty"`
 	// VnetName is the name of the virtual network that the cluster is deployed in.
 	VnetName string `json:"vnetName"`
+	// SecurityGroupName is the name of the network security group to which rules for LoadBalancer services are added.
+	SecurityGroupName string `json:"securityGroupName,omitempty"`
 
 	// UseInstanceMetadata specifies where instance metadata service is used where possible.
 	UseInstanceMetadata bool `json:"useInstanceMetadata,omitempty"`
@@ -187,6 +190,8 @@ func (b *CloudConfigBuilder) Build(c *fi.ModelBuilderContext) error {
 			vnetName = b.Cluster.Name
 		}
 
+		securityGroupName := azure.NetworkSecurityGroupName(b.Cluster.Name, kops.InstanceGroupRoleNode)
+
 		az := b.Cluster.Spec.CloudConfig.Azure
 		c := &azureCloudConfig{
 			CloudConfigType:             "file",
@@ -197,6 +202,7 @@ func (b *CloudConfigBuilder) Build(c *fi.ModelBuilderContext) error {
 			ResourceGroup:               b.Cluster.AzureResourceGroupName(),
 			RouteTableName:              az.RouteTableName,
 			VnetName:                    vnetName,
+			SecurityGroupName:           securityGroupName,
 			UseInstanceMetadata:         true,
 			UseManagedIdentityExtension: true,
 			// Disable availability set nodes as we currently use VMSS.
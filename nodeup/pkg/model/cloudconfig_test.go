@@ -102,6 +102,7 @@ func TestBuildAzure(t *testing.T) {
 		ResourceGroup:               resourceGroupName,
 		RouteTableName:              routeTableName,
 		VnetName:                    vnetName,
+		SecurityGroupName:           "nodes.testcluster.test.com",
 		UseInstanceMetadata:         true,
 		UseManagedIdentityExtension: true,
 		DisableAvailabilitySetNodes: true,
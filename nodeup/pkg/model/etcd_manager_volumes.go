@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"path/filepath"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// EtcdManagerVolumesBuilder creates the directories holding the etcd data on pre-provisioned masters,
+// which etcd-manager discovers in place of cloud volumes
+type EtcdManagerVolumesBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &EtcdManagerVolumesBuilder{}
+
+// Build is responsible for creating the etcd data directories of the members running on this host
+func (b *EtcdManagerVolumesBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !b.IsMaster || kops.CloudProviderID(b.Cluster.Spec.CloudProvider) != kops.CloudProviderMetal {
+		return nil
+	}
+
+	for _, etcdCluster := range b.Cluster.Spec.EtcdClusters {
+		for _, member := range etcdCluster.Members {
+			if fi.StringValue(member.InstanceGroup) != b.NodeupConfig.InstanceGroupName {
+				continue
+			}
+
+			dir := metal.EtcdVolumeDirectory(b.Cluster.Name, etcdCluster.Name, member.Name)
+			c.EnsureTask(&nodetasks.File{
+				Path: dir,
+				Type: nodetasks.FileType_Directory,
+				Mode: s("0700"),
+			})
+			c.EnsureTask(&nodetasks.File{
+				Path: filepath.Join(dir, "mnt"),
+				Type: nodetasks.FileType_Directory,
+				Mode: s("0700"),
+			})
+		}
+	}
+
+	return nil
+}
@@ -35,6 +35,7 @@ import (
 	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/util/pkg/distributions"
 )
@@ -212,6 +213,15 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 		flags += " --node-ip=" + localIpv4
 	}
 
+	if kops.CloudProviderID(b.Cluster.Spec.CloudProvider) == kops.CloudProviderMetal {
+		// Without a cloud provider the node gets no provider ID, which kops needs to match nodes to hosts
+		nodeName, err := b.NodeName()
+		if err != nil {
+			return nil, err
+		}
+		flags += " --provider-id=" + metal.ProviderID(b.NodeupConfig.InstanceGroupName, nodeName)
+	}
+
 	if b.usesContainerizedMounter() {
 		// We don't want to expose this in the model while it is experimental, but it is needed on COS
 		flags += " --experimental-mounter-path=" + path.Join(containerizedMounterHome, "mounter")
@@ -181,6 +181,7 @@ type ProtokubeFlags struct {
 	ApplyTaints               *bool    `json:"applyTaints,omitempty" flag:"apply-taints"`
 	Channels                  []string `json:"channels,omitempty" flag:"channels"`
 	Cloud                     *string  `json:"cloud,omitempty" flag:"cloud"`
+	ClusterID                 *string  `json:"cluster-id,omitempty" flag:"cluster-id"`
 	Containerized             *bool    `json:"containerized,omitempty" flag:"containerized"`
 	DNSInternalSuffix         *string  `json:"dnsInternalSuffix,omitempty" flag:"dns-internal-suffix"`
 	DNSProvider               *string  `json:"dnsProvider,omitempty" flag:"dns"`
@@ -224,6 +225,9 @@ type ProtokubeFlags struct {
 	GossipProtocolSecondary *string `json:"gossip-protocol-secondary" flag:"gossip-protocol-secondary" flag-include-empty:"true"`
 	GossipListenSecondary   *string `json:"gossip-listen-secondary" flag:"gossip-listen-secondary"`
 	GossipSecretSecondary   *string `json:"gossip-secret-secondary" flag:"gossip-secret-secondary"`
+
+	// GossipSeeds are the static addresses of the gossip peers, for clouds where protokube cannot discover them
+	GossipSeeds []string `json:"gossip-seed,omitempty" flag:"gossip-seed"`
 }
 
 // ProtokubeFlags is responsible for building the command line flags for protokube
@@ -361,6 +365,12 @@ func (t *ProtokubeBuilder) ProtokubeFlags(k8sVersion semver.Version) (*Protokube
 		}
 	}
 
+	if kops.CloudProviderID(t.Cluster.Spec.CloudProvider) == kops.CloudProviderMetal {
+		// Pre-provisioned hosts have no cloud metadata to discover the cluster and the gossip peers from
+		f.ClusterID = fi.String(t.Cluster.ObjectMeta.Name)
+		f.GossipSeeds = t.NodeupConfig.GossipSeeds
+	}
+
 	if f.DNSInternalSuffix == nil {
 		f.DNSInternalSuffix = fi.String(".internal." + t.Cluster.ObjectMeta.Name)
 	}
@@ -444,6 +454,10 @@ func (t *ProtokubeBuilder) buildEnvFile() (*nodetasks.File, error) {
 		envVars["DIGITALOCEAN_ACCESS_TOKEN"] = os.Getenv("DIGITALOCEAN_ACCESS_TOKEN")
 	}
 
+	if kops.CloudProviderID(t.Cluster.Spec.CloudProvider) == kops.CloudProviderHetzner && os.Getenv("HCLOUD_TOKEN") != "" {
+		envVars["HCLOUD_TOKEN"] = os.Getenv("HCLOUD_TOKEN")
+	}
+
 	if os.Getenv("OSS_REGION") != "" {
 		envVars["OSS_REGION"] = os.Getenv("OSS_REGION")
 	}
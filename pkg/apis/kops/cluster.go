@@ -18,6 +18,7 @@ package kops
 
 import (
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -83,6 +84,10 @@ type ClusterSpec struct {
 	AdditionalNetworkCIDRs []string `json:"additionalNetworkCIDRs,omitempty"`
 	// NetworkID is an identifier of a network, if we want to reuse/share an existing network (e.g. an AWS VPC)
 	NetworkID string `json:"networkID,omitempty"`
+	// VPCEndpoints are the VPC endpoints kops creates in the VPC of the cluster, so that AWS APIs are reached without a NAT gateway (AWS only)
+	VPCEndpoints []VPCEndpointSpec `json:"vpcEndpoints,omitempty"`
+	// FlowLogs configures the flow logs of the VPC of the cluster (AWS only)
+	FlowLogs *FlowLogsSpec `json:"flowLogs,omitempty"`
 	// Topology defines the type of network topology to use on the cluster - default public
 	// This is heavily weighted towards AWS for the time being, but should also be agnostic enough
 	// to port out to GCE later if needed
@@ -115,6 +120,17 @@ type ClusterSpec struct {
 	// NonMasqueradeCIDR is the CIDR for the internal k8s network (on which pods & services live)
 	// It cannot overlap ServiceClusterIPRange
 	NonMasqueradeCIDR string `json:"nonMasqueradeCIDR,omitempty"`
+	// IPFamilies lists the IP families of the pod and service networks, in order of preference.
+	// Valid values are [IPv4] (the default), [IPv4, IPv6] or [IPv6, IPv4] for dual-stack, and [IPv6] for IPv6-only.
+	// The first family is used for the cluster DNS service address.
+	IPFamilies []IPFamily `json:"ipFamilies,omitempty"`
+	// NetworkIPv6CIDR is the IPv6 CIDR of an existing (shared) AWS VPC.
+	// VPCs created by kops are assigned an Amazon-provided /56 block instead.
+	NetworkIPv6CIDR string `json:"networkIPv6CIDR,omitempty"`
+	// PodIPv6CIDR is the IPv6 CIDR from which we allocate IPs for pods, when the cluster uses IPv6
+	PodIPv6CIDR string `json:"podIPv6CIDR,omitempty"`
+	// ServiceClusterIPv6Range is the IPv6 CIDR where we allocate IPs for services, when the cluster uses IPv6
+	ServiceClusterIPv6Range string `json:"serviceClusterIPv6Range,omitempty"`
 	// SSHAccess is a list of the CIDRs that can access SSH.
 	SSHAccess []string `json:"sshAccess,omitempty"`
 	// NodePortAccess is a list of the CIDRs that can access the node ports range (30000-32767).
@@ -410,6 +426,34 @@ type LoadBalancerAccessSpec struct {
 	CrossZoneLoadBalancing *bool `json:"crossZoneLoadBalancing,omitempty"`
 	// Subnets allows you to specify the subnets that must be used for the load balancer
 	Subnets []LoadBalancerSubnetSpec `json:"subnets,omitempty"`
+	// AccessLog configures the access logs of the load balancer (AWS only)
+	AccessLog *AccessLogSpec `json:"accessLog,omitempty"`
+	// HealthMonitor configures the health monitor of the load balancer pool (OpenStack only)
+	HealthMonitor *LoadBalancerHealthMonitorSpec `json:"healthMonitor,omitempty"`
+	// FloatingIP is an existing floating IP address to associate with the load balancer instead of allocating a new one (OpenStack only)
+	FloatingIP string `json:"floatingIP,omitempty"`
+	// TLSPassthrough makes the listener and pool use the HTTPS protocol, so that members are health checked with a TLS handshake (OpenStack only)
+	TLSPassthrough bool `json:"tlsPassthrough,omitempty"`
+}
+
+// AccessLogSpec configures the access logs of a load balancer
+type AccessLogSpec struct {
+	// Interval is the publishing interval of the access logs in minutes: 5 or 60. Only supported by Classic load balancers, defaults to 60.
+	Interval int `json:"interval,omitempty"`
+	// Bucket is the S3 bucket the access logs are published to.
+	Bucket string `json:"bucket,omitempty"`
+	// BucketPrefix is the prefix of the access logs in the S3 bucket.
+	BucketPrefix string `json:"bucketPrefix,omitempty"`
+}
+
+// LoadBalancerHealthMonitorSpec configures how a load balancer checks the health of its members
+type LoadBalancerHealthMonitorSpec struct {
+	// DelaySeconds is the interval between health checks. Defaults to 10.
+	DelaySeconds int `json:"delaySeconds,omitempty"`
+	// TimeoutSeconds is the time to wait for a health check to succeed, which must be less than the delay. Defaults to 5.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+	// MaxRetries is the number of successful checks before a member is considered healthy again: 1 to 10. Defaults to 3.
+	MaxRetries *int `json:"maxRetries,omitempty"`
 }
 
 // KubeDNSConfig defines the kube dns configuration
@@ -591,6 +635,9 @@ type ClusterSubnetSpec struct {
 	Name string `json:"name,omitempty"`
 	// CIDR is the network cidr of the subnet
 	CIDR string `json:"cidr,omitempty"`
+	// IPv6CIDR is the IPv6 CIDR of the subnet.
+	// It is either a full CIDR, or /64#N to use the Nth /64 block (counting from 0) of the VPC's IPv6 CIDR.
+	IPv6CIDR string `json:"ipv6CIDR,omitempty"`
 	// Zone is the zone the subnet is in, set for subnets that are zonally scoped
 	Zone string `json:"zone,omitempty"`
 	// Region is the region the subnet is in, set for subnets that are regionally scoped
@@ -605,6 +652,79 @@ type ClusterSubnetSpec struct {
 	PublicIP string `json:"publicIP,omitempty"`
 }
 
+// IPFamily is an IP protocol family used by the cluster networking
+type IPFamily string
+
+const (
+	// IPFamilyIPv4 is the IPv4 family
+	IPFamilyIPv4 IPFamily = "IPv4"
+	// IPFamilyIPv6 is the IPv6 family
+	IPFamilyIPv6 IPFamily = "IPv6"
+)
+
+// VPCEndpointSpec defines an AWS VPC endpoint of the cluster
+type VPCEndpointSpec struct {
+	// Service is the AWS service reached through the endpoint, e.g. s3, ecr.api, ecr.dkr, sts, ec2, autoscaling or elasticloadbalancing
+	Service string `json:"service,omitempty"`
+	// Type is the type of the endpoint: Gateway or Interface. Defaults to Gateway for s3 and dynamodb, and Interface for other services.
+	Type string `json:"type,omitempty"`
+	// PrivateDNSEnabled resolves the default DNS name of the service to an interface endpoint. Defaults to true, except for s3.
+	PrivateDNSEnabled *bool `json:"privateDNSEnabled,omitempty"`
+}
+
+const (
+	// VPCEndpointTypeGateway is a gateway endpoint, which routes traffic through the route tables of the cluster
+	VPCEndpointTypeGateway = "Gateway"
+	// VPCEndpointTypeInterface is an interface endpoint, which places network interfaces in the subnets of the cluster
+	VPCEndpointTypeInterface = "Interface"
+)
+
+// VPCEndpointTypes is a collection of supported VPC endpoint types
+var VPCEndpointTypes = []string{VPCEndpointTypeGateway, VPCEndpointTypeInterface}
+
+// VPCGatewayEndpointServices are the services supporting gateway endpoints
+var VPCGatewayEndpointServices = []string{"dynamodb", "s3"}
+
+// EndpointType returns the type of the endpoint, defaulting to a gateway endpoint for the services supporting them
+func (e *VPCEndpointSpec) EndpointType() string {
+	if e.Type != "" {
+		return e.Type
+	}
+	for _, service := range VPCGatewayEndpointServices {
+		if e.Service == service {
+			return VPCEndpointTypeGateway
+		}
+	}
+	return VPCEndpointTypeInterface
+}
+
+// FlowLogsSpec configures the flow logs of the VPC of the cluster
+type FlowLogsSpec struct {
+	// Destination is where the flow logs are published: CloudWatchLogs or S3. Defaults to CloudWatchLogs.
+	Destination string `json:"destination,omitempty"`
+	// TrafficType is the type of traffic to log: ACCEPT, REJECT or ALL. Defaults to ALL.
+	TrafficType string `json:"trafficType,omitempty"`
+	// RetentionInDays is the number of days the CloudWatch Logs log group keeps the flow logs. Defaults to never expiring.
+	RetentionInDays *int64 `json:"retentionInDays,omitempty"`
+	// Bucket is the S3 bucket the flow logs are published to, required for the S3 destination.
+	Bucket string `json:"bucket,omitempty"`
+	// BucketPrefix is the prefix of the flow logs in the S3 bucket.
+	BucketPrefix string `json:"bucketPrefix,omitempty"`
+}
+
+const (
+	// FlowLogsDestinationCloudWatchLogs publishes the flow logs to a CloudWatch Logs log group created by kops
+	FlowLogsDestinationCloudWatchLogs = "CloudWatchLogs"
+	// FlowLogsDestinationS3 publishes the flow logs to an existing S3 bucket
+	FlowLogsDestinationS3 = "S3"
+)
+
+// FlowLogsDestinations is a collection of supported flow logs destinations
+var FlowLogsDestinations = []string{FlowLogsDestinationCloudWatchLogs, FlowLogsDestinationS3}
+
+// FlowLogsTrafficTypes is a collection of supported flow logs traffic types
+var FlowLogsTrafficTypes = []string{"ACCEPT", "REJECT", "ALL"}
+
 type EgressProxySpec struct {
 	HTTPProxy     HTTPProxy `json:"httpProxy,omitempty"`
 	ProxyExcludes string    `json:"excludes,omitempty"`
@@ -711,6 +831,82 @@ func (c *Cluster) SharedVPC() bool {
 	return c.Spec.NetworkID != ""
 }
 
+// UsesIPv4 returns true if the pod and service networks of the cluster have IPv4 addresses
+func (c *ClusterSpec) UsesIPv4() bool {
+	if len(c.IPFamilies) == 0 {
+		return true
+	}
+	for _, family := range c.IPFamilies {
+		if family == IPFamilyIPv4 {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesIPv6 returns true if the pod and service networks of the cluster have IPv6 addresses
+func (c *ClusterSpec) UsesIPv6() bool {
+	for _, family := range c.IPFamilies {
+		if family == IPFamilyIPv6 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDualStack returns true if the cluster uses both IPv4 and IPv6
+func (c *ClusterSpec) IsDualStack() bool {
+	return c.UsesIPv4() && c.UsesIPv6()
+}
+
+// IsIPv6Only returns true if the pod and service networks of the cluster only use IPv6
+func (c *ClusterSpec) IsIPv6Only() bool {
+	return c.UsesIPv6() && !c.UsesIPv4()
+}
+
+// PodCIDRs returns the pod CIDRs of the cluster, in the order of the IP families
+func (c *ClusterSpec) PodCIDRs() []string {
+	return c.cidrsByFamily(c.PodIPv4CIDR(), c.PodIPv6CIDR)
+}
+
+// PodIPv4CIDR returns the IPv4 pod CIDR of the cluster, which is taken from the kube-controller-manager
+// cluster CIDR.  On IPv6 clusters the kube-controller-manager cluster CIDR lists the CIDRs of each family.
+func (c *ClusterSpec) PodIPv4CIDR() string {
+	if c.KubeControllerManager == nil {
+		return ""
+	}
+	for _, cidr := range strings.Split(c.KubeControllerManager.ClusterCIDR, ",") {
+		if cidr != "" && !strings.Contains(cidr, ":") {
+			return cidr
+		}
+	}
+	return ""
+}
+
+// ServiceClusterIPRanges returns the service CIDRs of the cluster, in the order of the IP families
+func (c *ClusterSpec) ServiceClusterIPRanges() []string {
+	return c.cidrsByFamily(c.ServiceClusterIPRange, c.ServiceClusterIPv6Range)
+}
+
+func (c *ClusterSpec) cidrsByFamily(ipv4, ipv6 string) []string {
+	families := c.IPFamilies
+	if len(families) == 0 {
+		families = []IPFamily{IPFamilyIPv4}
+	}
+
+	var cidrs []string
+	for _, family := range families {
+		cidr := ipv4
+		if family == IPFamilyIPv6 {
+			cidr = ipv6
+		}
+		if cidr != "" {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
 // IsKubernetesGTE checks if the version is >= the specified version.
 // It panics if the kubernetes version in the cluster is invalid, or if the version is invalid.
 func (c *Cluster) IsKubernetesGTE(version string) bool {
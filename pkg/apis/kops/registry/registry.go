@@ -31,6 +31,8 @@ const (
 	PathClusterCompleted = "cluster.spec"
 	// PathKopsVersionUpdated is the path for the version of kops last used to apply the cluster.
 	PathKopsVersionUpdated = "kops-version.txt"
+	// PathLock is the path for the lock held by mutating commands
+	PathLock = "lock"
 )
 
 func ConfigBase(c *api.Cluster) (vfs.Path, error) {
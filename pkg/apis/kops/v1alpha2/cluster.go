@@ -79,6 +79,10 @@ type ClusterSpec struct {
 	AdditionalNetworkCIDRs []string `json:"additionalNetworkCIDRs,omitempty"`
 	// NetworkID is an identifier of a network, if we want to reuse/share an existing network (e.g. an AWS VPC)
 	NetworkID string `json:"networkID,omitempty"`
+	// VPCEndpoints are the VPC endpoints kops creates in the VPC of the cluster, so that AWS APIs are reached without a NAT gateway (AWS only)
+	VPCEndpoints []VPCEndpointSpec `json:"vpcEndpoints,omitempty"`
+	// FlowLogs configures the flow logs of the VPC of the cluster (AWS only)
+	FlowLogs *FlowLogsSpec `json:"flowLogs,omitempty"`
 	// Topology defines the type of network topology to use on the cluster - default public
 	// This is heavily weighted towards AWS for the time being, but should also be agnostic enough
 	// to port out to GCE later if needed
@@ -112,6 +116,17 @@ type ClusterSpec struct {
 	// NonMasqueradeCIDR is the CIDR for the internal k8s network (on which pods & services live)
 	// It cannot overlap ServiceClusterIPRange
 	NonMasqueradeCIDR string `json:"nonMasqueradeCIDR,omitempty"`
+	// IPFamilies lists the IP families of the pod and service networks, in order of preference.
+	// Valid values are [IPv4] (the default), [IPv4, IPv6] or [IPv6, IPv4] for dual-stack, and [IPv6] for IPv6-only.
+	// The first family is used for the cluster DNS service address.
+	IPFamilies []IPFamily `json:"ipFamilies,omitempty"`
+	// NetworkIPv6CIDR is the IPv6 CIDR of an existing (shared) AWS VPC.
+	// VPCs created by kops are assigned an Amazon-provided /56 block instead.
+	NetworkIPv6CIDR string `json:"networkIPv6CIDR,omitempty"`
+	// PodIPv6CIDR is the IPv6 CIDR from which we allocate IPs for pods, when the cluster uses IPv6
+	PodIPv6CIDR string `json:"podIPv6CIDR,omitempty"`
+	// ServiceClusterIPv6Range is the IPv6 CIDR where we allocate IPs for services, when the cluster uses IPv6
+	ServiceClusterIPv6Range string `json:"serviceClusterIPv6Range,omitempty"`
 	// SSHAccess determines the permitted access to SSH
 	// Currently only a single CIDR is supported (though a richer grammar could be added in future)
 	SSHAccess []string `json:"sshAccess,omitempty"`
@@ -412,6 +427,34 @@ type LoadBalancerAccessSpec struct {
 	CrossZoneLoadBalancing *bool `json:"crossZoneLoadBalancing,omitempty"`
 	// Subnets allows you to specify the subnets that must be used for the load balancer
 	Subnets []LoadBalancerSubnetSpec `json:"subnets,omitempty"`
+	// AccessLog configures the access logs of the load balancer (AWS only)
+	AccessLog *AccessLogSpec `json:"accessLog,omitempty"`
+	// HealthMonitor configures the health monitor of the load balancer pool (OpenStack only)
+	HealthMonitor *LoadBalancerHealthMonitorSpec `json:"healthMonitor,omitempty"`
+	// FloatingIP is an existing floating IP address to associate with the load balancer instead of allocating a new one (OpenStack only)
+	FloatingIP string `json:"floatingIP,omitempty"`
+	// TLSPassthrough makes the listener and pool use the HTTPS protocol, so that members are health checked with a TLS handshake (OpenStack only)
+	TLSPassthrough bool `json:"tlsPassthrough,omitempty"`
+}
+
+// AccessLogSpec configures the access logs of a load balancer
+type AccessLogSpec struct {
+	// Interval is the publishing interval of the access logs in minutes: 5 or 60. Only supported by Classic load balancers, defaults to 60.
+	Interval int `json:"interval,omitempty"`
+	// Bucket is the S3 bucket the access logs are published to.
+	Bucket string `json:"bucket,omitempty"`
+	// BucketPrefix is the prefix of the access logs in the S3 bucket.
+	BucketPrefix string `json:"bucketPrefix,omitempty"`
+}
+
+// LoadBalancerHealthMonitorSpec configures how a load balancer checks the health of its members
+type LoadBalancerHealthMonitorSpec struct {
+	// DelaySeconds is the interval between health checks. Defaults to 10.
+	DelaySeconds int `json:"delaySeconds,omitempty"`
+	// TimeoutSeconds is the time to wait for a health check to succeed, which must be less than the delay. Defaults to 5.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+	// MaxRetries is the number of successful checks before a member is considered healthy again: 1 to 10. Defaults to 3.
+	MaxRetries *int `json:"maxRetries,omitempty"`
 }
 
 // KubeDNSConfig defines the kube dns configuration
@@ -575,6 +618,9 @@ type ClusterSubnetSpec struct {
 	Region string `json:"region,omitempty"`
 
 	CIDR string `json:"cidr,omitempty"`
+	// IPv6CIDR is the IPv6 CIDR of the subnet.
+	// It is either a full CIDR, or /64#N to use the Nth /64 block (counting from 0) of the VPC's IPv6 CIDR.
+	IPv6CIDR string `json:"ipv6CIDR,omitempty"`
 
 	// ProviderID is the cloud provider id for the objects associated with the zone (the subnet on AWS)
 	ProviderID string `json:"id,omitempty"`
@@ -587,6 +633,38 @@ type ClusterSubnetSpec struct {
 	PublicIP string `json:"publicIP,omitempty"`
 }
 
+// IPFamily is an IP protocol family used by the cluster networking
+type IPFamily string
+
+const (
+	IPFamilyIPv4 IPFamily = "IPv4"
+	IPFamilyIPv6 IPFamily = "IPv6"
+)
+
+// VPCEndpointSpec defines an AWS VPC endpoint of the cluster
+type VPCEndpointSpec struct {
+	// Service is the AWS service reached through the endpoint, e.g. s3, ecr.api, ecr.dkr, sts, ec2, autoscaling or elasticloadbalancing
+	Service string `json:"service,omitempty"`
+	// Type is the type of the endpoint: Gateway or Interface. Defaults to Gateway for s3 and dynamodb, and Interface for other services.
+	Type string `json:"type,omitempty"`
+	// PrivateDNSEnabled resolves the default DNS name of the service to an interface endpoint. Defaults to true, except for s3.
+	PrivateDNSEnabled *bool `json:"privateDNSEnabled,omitempty"`
+}
+
+// FlowLogsSpec configures the flow logs of the VPC of the cluster
+type FlowLogsSpec struct {
+	// Destination is where the flow logs are published: CloudWatchLogs or S3. Defaults to CloudWatchLogs.
+	Destination string `json:"destination,omitempty"`
+	// TrafficType is the type of traffic to log: ACCEPT, REJECT or ALL. Defaults to ALL.
+	TrafficType string `json:"trafficType,omitempty"`
+	// RetentionInDays is the number of days the CloudWatch Logs log group keeps the flow logs. Defaults to never expiring.
+	RetentionInDays *int64 `json:"retentionInDays,omitempty"`
+	// Bucket is the S3 bucket the flow logs are published to, required for the S3 destination.
+	Bucket string `json:"bucket,omitempty"`
+	// BucketPrefix is the prefix of the flow logs in the S3 bucket.
+	BucketPrefix string `json:"bucketPrefix,omitempty"`
+}
+
 type EgressProxySpec struct {
 	HTTPProxy     HTTPProxy `json:"httpProxy,omitempty"`
 	ProxyExcludes string    `json:"excludes,omitempty"`
@@ -178,6 +178,14 @@ type InstanceGroupSpec struct {
 	//   'automatic' (default): apply updates automatically (apply OS security upgrades, avoiding rebooting when possible)
 	//   'external': do not apply updates automatically; they are applied manually or by an external system
 	UpdatePolicy *string `json:"updatePolicy,omitempty"`
+	// WarmPool configures an AWS Auto Scaling warm pool of pre-initialized instances (AWS only)
+	WarmPool *WarmPoolSpec `json:"warmPool,omitempty"`
+	// PlacementGroup launches the instances into an EC2 placement group (AWS only)
+	PlacementGroup *PlacementGroupSpec `json:"placementGroup,omitempty"`
+	// CapacityReservation configures how the instances use EC2 capacity reservations (AWS only)
+	CapacityReservation *CapacityReservationSpec `json:"capacityReservation,omitempty"`
+	// Metal lists the pre-provisioned hosts that make up the instance group (metal only)
+	Metal *MetalSpec `json:"metal,omitempty"`
 }
 
 const (
@@ -202,6 +210,60 @@ type InstanceMetadataOptions struct {
 	HTTPTokens *string `json:"httpTokens,omitempty"`
 }
 
+// WarmPoolSpec defines the warm pool of an instance group (AWS only)
+type WarmPoolSpec struct {
+	// MinSize is the minimum number of instances kept in the warm pool
+	MinSize int64 `json:"minSize,omitempty"`
+	// MaxPreparedCapacity is the maximum number of instances in the warm pool and the instance group together.
+	// Defaults to the maximum size of the instance group.
+	MaxPreparedCapacity *int64 `json:"maxPreparedCapacity,omitempty"`
+	// PoolState is the state of the instances in the warm pool: Stopped (default) or Running
+	PoolState *string `json:"poolState,omitempty"`
+}
+
+// PlacementGroupSpec defines the EC2 placement group of an instance group (AWS only)
+type PlacementGroupSpec struct {
+	// Strategy is the placement strategy of the group: cluster, spread or partition
+	Strategy string `json:"strategy,omitempty"`
+	// PartitionCount is the number of partitions of a placement group with the partition strategy, from 1 to 7
+	PartitionCount *int64 `json:"partitionCount,omitempty"`
+}
+
+// CapacityReservationSpec defines the use of EC2 capacity reservations by an instance group (AWS only)
+type CapacityReservationSpec struct {
+	// Preference is the capacity reservation preference of the instances: open or none
+	Preference *string `json:"preference,omitempty"`
+	// ID is the ID of a capacity reservation to launch the instances into
+	ID *string `json:"id,omitempty"`
+	// ResourceGroupARN is the ARN of a capacity reservation resource group to launch the instances into
+	ResourceGroupARN *string `json:"resourceGroupARN,omitempty"`
+}
+
+// MetalSpec defines the static inventory of pre-provisioned hosts of an instance group (metal only)
+type MetalSpec struct {
+	// Hosts are the machines that kops enrolls into the instance group over SSH
+	Hosts []MetalHostSpec `json:"hosts,omitempty"`
+}
+
+// MetalHostSpec defines a pre-provisioned host, and how kops connects to it
+type MetalHostSpec struct {
+	// Name is the hostname of the machine, which is also the name of its Kubernetes node
+	Name string `json:"name,omitempty"`
+	// Address is the IP address kops connects to over SSH; the addresses of the masters are also used as gossip seeds
+	Address string `json:"address,omitempty"`
+	// SSHPort is the port of the SSH server of the host. Defaults to 22.
+	SSHPort *int32 `json:"sshPort,omitempty"`
+	// SSHUser is the user kops logs in as; it must be root or be allowed to use sudo without a password. Defaults to root.
+	SSHUser string `json:"sshUser,omitempty"`
+	// SSHPrivateKeyFile is the path, on the machine running kops, of the private key used to log in. Defaults to ~/.ssh/id_rsa.
+	SSHPrivateKeyFile string `json:"sshPrivateKeyFile,omitempty"`
+	// SSHHostKey is the public key of the SSH server of the host, in authorized_keys format, such as the content of
+	// /etc/ssh/ssh_host_ed25519_key.pub. kops refuses to connect to a host presenting any other key.
+	SSHHostKey string `json:"sshHostKey,omitempty"`
+	// Labels are additional Kubernetes labels applied to the node of this host
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
 // MixedInstancesPolicySpec defines the specification for an autoscaling group backed by a ec2 fleet
 type MixedInstancesPolicySpec struct {
 	// Instances is a list of instance types which we are willing to run in the EC2 fleet
@@ -53,6 +53,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AccessLogSpec)(nil), (*kops.AccessLogSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AccessLogSpec_To_kops_AccessLogSpec(a.(*AccessLogSpec), b.(*kops.AccessLogSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AccessLogSpec)(nil), (*AccessLogSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AccessLogSpec_To_v1alpha2_AccessLogSpec(a.(*kops.AccessLogSpec), b.(*AccessLogSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AccessSpec)(nil), (*kops.AccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AccessSpec_To_kops_AccessSpec(a.(*AccessSpec), b.(*kops.AccessSpec), scope)
 	}); err != nil {
@@ -193,6 +203,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CapacityReservationSpec)(nil), (*kops.CapacityReservationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CapacityReservationSpec_To_kops_CapacityReservationSpec(a.(*CapacityReservationSpec), b.(*kops.CapacityReservationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.CapacityReservationSpec)(nil), (*CapacityReservationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_CapacityReservationSpec_To_v1alpha2_CapacityReservationSpec(a.(*kops.CapacityReservationSpec), b.(*CapacityReservationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CertManagerConfig)(nil), (*kops.CertManagerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_CertManagerConfig_To_kops_CertManagerConfig(a.(*CertManagerConfig), b.(*kops.CertManagerConfig), scope)
 	}); err != nil {
@@ -463,6 +483,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*FlowLogsSpec)(nil), (*kops.FlowLogsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_FlowLogsSpec_To_kops_FlowLogsSpec(a.(*FlowLogsSpec), b.(*kops.FlowLogsSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.FlowLogsSpec)(nil), (*FlowLogsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_FlowLogsSpec_To_v1alpha2_FlowLogsSpec(a.(*kops.FlowLogsSpec), b.(*FlowLogsSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*GCENetworkingSpec)(nil), (*kops.GCENetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_GCENetworkingSpec_To_kops_GCENetworkingSpec(a.(*GCENetworkingSpec), b.(*kops.GCENetworkingSpec), scope)
 	}); err != nil {
@@ -753,6 +783,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LoadBalancerHealthMonitorSpec)(nil), (*kops.LoadBalancerHealthMonitorSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_LoadBalancerHealthMonitorSpec_To_kops_LoadBalancerHealthMonitorSpec(a.(*LoadBalancerHealthMonitorSpec), b.(*kops.LoadBalancerHealthMonitorSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.LoadBalancerHealthMonitorSpec)(nil), (*LoadBalancerHealthMonitorSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_LoadBalancerHealthMonitorSpec_To_v1alpha2_LoadBalancerHealthMonitorSpec(a.(*kops.LoadBalancerHealthMonitorSpec), b.(*LoadBalancerHealthMonitorSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LoadBalancerSubnetSpec)(nil), (*kops.LoadBalancerSubnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_LoadBalancerSubnetSpec_To_kops_LoadBalancerSubnetSpec(a.(*LoadBalancerSubnetSpec), b.(*kops.LoadBalancerSubnetSpec), scope)
 	}); err != nil {
@@ -773,6 +813,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MetalHostSpec)(nil), (*kops.MetalHostSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_MetalHostSpec_To_kops_MetalHostSpec(a.(*MetalHostSpec), b.(*kops.MetalHostSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.MetalHostSpec)(nil), (*MetalHostSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_MetalHostSpec_To_v1alpha2_MetalHostSpec(a.(*kops.MetalHostSpec), b.(*MetalHostSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MetalSpec)(nil), (*kops.MetalSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_MetalSpec_To_kops_MetalSpec(a.(*MetalSpec), b.(*kops.MetalSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.MetalSpec)(nil), (*MetalSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_MetalSpec_To_v1alpha2_MetalSpec(a.(*kops.MetalSpec), b.(*MetalSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MetricsServerConfig)(nil), (*kops.MetricsServerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_MetricsServerConfig_To_kops_MetricsServerConfig(a.(*MetricsServerConfig), b.(*kops.MetricsServerConfig), scope)
 	}); err != nil {
@@ -913,6 +973,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PlacementGroupSpec)(nil), (*kops.PlacementGroupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PlacementGroupSpec_To_kops_PlacementGroupSpec(a.(*PlacementGroupSpec), b.(*kops.PlacementGroupSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.PlacementGroupSpec)(nil), (*PlacementGroupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PlacementGroupSpec_To_v1alpha2_PlacementGroupSpec(a.(*kops.PlacementGroupSpec), b.(*PlacementGroupSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RBACAuthorizationSpec)(nil), (*kops.RBACAuthorizationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(a.(*RBACAuthorizationSpec), b.(*kops.RBACAuthorizationSpec), scope)
 	}); err != nil {
@@ -1013,6 +1083,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*VPCEndpointSpec)(nil), (*kops.VPCEndpointSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_VPCEndpointSpec_To_kops_VPCEndpointSpec(a.(*VPCEndpointSpec), b.(*kops.VPCEndpointSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.VPCEndpointSpec)(nil), (*VPCEndpointSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_VPCEndpointSpec_To_v1alpha2_VPCEndpointSpec(a.(*kops.VPCEndpointSpec), b.(*VPCEndpointSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VolumeMountSpec)(nil), (*kops.VolumeMountSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(a.(*VolumeMountSpec), b.(*kops.VolumeMountSpec), scope)
 	}); err != nil {
@@ -1033,6 +1113,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*WarmPoolSpec)(nil), (*kops.WarmPoolSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_WarmPoolSpec_To_kops_WarmPoolSpec(a.(*WarmPoolSpec), b.(*kops.WarmPoolSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.WarmPoolSpec)(nil), (*WarmPoolSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_WarmPoolSpec_To_v1alpha2_WarmPoolSpec(a.(*kops.WarmPoolSpec), b.(*WarmPoolSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*WeaveNetworkingSpec)(nil), (*kops.WeaveNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_WeaveNetworkingSpec_To_kops_WeaveNetworkingSpec(a.(*WeaveNetworkingSpec), b.(*kops.WeaveNetworkingSpec), scope)
 	}); err != nil {
@@ -1088,6 +1178,30 @@ func Convert_kops_AWSLoadBalancerControllerConfig_To_v1alpha2_AWSLoadBalancerCon
 	return autoConvert_kops_AWSLoadBalancerControllerConfig_To_v1alpha2_AWSLoadBalancerControllerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_AccessLogSpec_To_kops_AccessLogSpec(in *AccessLogSpec, out *kops.AccessLogSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Bucket = in.Bucket
+	out.BucketPrefix = in.BucketPrefix
+	return nil
+}
+
+// Convert_v1alpha2_AccessLogSpec_To_kops_AccessLogSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AccessLogSpec_To_kops_AccessLogSpec(in *AccessLogSpec, out *kops.AccessLogSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AccessLogSpec_To_kops_AccessLogSpec(in, out, s)
+}
+
+func autoConvert_kops_AccessLogSpec_To_v1alpha2_AccessLogSpec(in *kops.AccessLogSpec, out *AccessLogSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Bucket = in.Bucket
+	out.BucketPrefix = in.BucketPrefix
+	return nil
+}
+
+// Convert_kops_AccessLogSpec_To_v1alpha2_AccessLogSpec is an autogenerated conversion function.
+func Convert_kops_AccessLogSpec_To_v1alpha2_AccessLogSpec(in *kops.AccessLogSpec, out *AccessLogSpec, s conversion.Scope) error {
+	return autoConvert_kops_AccessLogSpec_To_v1alpha2_AccessLogSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_AccessSpec_To_kops_AccessSpec(in *AccessSpec, out *kops.AccessSpec, s conversion.Scope) error {
 	if in.DNS != nil {
 		in, out := &in.DNS, &out.DNS
@@ -1612,6 +1726,30 @@ func Convert_kops_CanalNetworkingSpec_To_v1alpha2_CanalNetworkingSpec(in *kops.C
 	return autoConvert_kops_CanalNetworkingSpec_To_v1alpha2_CanalNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_CapacityReservationSpec_To_kops_CapacityReservationSpec(in *CapacityReservationSpec, out *kops.CapacityReservationSpec, s conversion.Scope) error {
+	out.Preference = in.Preference
+	out.ID = in.ID
+	out.ResourceGroupARN = in.ResourceGroupARN
+	return nil
+}
+
+// Convert_v1alpha2_CapacityReservationSpec_To_kops_CapacityReservationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_CapacityReservationSpec_To_kops_CapacityReservationSpec(in *CapacityReservationSpec, out *kops.CapacityReservationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CapacityReservationSpec_To_kops_CapacityReservationSpec(in, out, s)
+}
+
+func autoConvert_kops_CapacityReservationSpec_To_v1alpha2_CapacityReservationSpec(in *kops.CapacityReservationSpec, out *CapacityReservationSpec, s conversion.Scope) error {
+	out.Preference = in.Preference
+	out.ID = in.ID
+	out.ResourceGroupARN = in.ResourceGroupARN
+	return nil
+}
+
+// Convert_kops_CapacityReservationSpec_To_v1alpha2_CapacityReservationSpec is an autogenerated conversion function.
+func Convert_kops_CapacityReservationSpec_To_v1alpha2_CapacityReservationSpec(in *kops.CapacityReservationSpec, out *CapacityReservationSpec, s conversion.Scope) error {
+	return autoConvert_kops_CapacityReservationSpec_To_v1alpha2_CapacityReservationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_CertManagerConfig_To_kops_CertManagerConfig(in *CertManagerConfig, out *kops.CertManagerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Image = in.Image
@@ -2145,6 +2283,26 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	out.NetworkCIDR = in.NetworkCIDR
 	out.AdditionalNetworkCIDRs = in.AdditionalNetworkCIDRs
 	out.NetworkID = in.NetworkID
+	if in.VPCEndpoints != nil {
+		in, out := &in.VPCEndpoints, &out.VPCEndpoints
+		*out = make([]kops.VPCEndpointSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_VPCEndpointSpec_To_kops_VPCEndpointSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.VPCEndpoints = nil
+	}
+	if in.FlowLogs != nil {
+		in, out := &in.FlowLogs, &out.FlowLogs
+		*out = new(kops.FlowLogsSpec)
+		if err := Convert_v1alpha2_FlowLogsSpec_To_kops_FlowLogsSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.FlowLogs = nil
+	}
 	if in.Topology != nil {
 		in, out := &in.Topology, &out.Topology
 		*out = new(kops.TopologySpec)
@@ -2173,6 +2331,18 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	out.ServiceClusterIPRange = in.ServiceClusterIPRange
 	out.PodCIDR = in.PodCIDR
 	out.NonMasqueradeCIDR = in.NonMasqueradeCIDR
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]kops.IPFamily, len(*in))
+		for i := range *in {
+			(*out)[i] = kops.IPFamily((*in)[i])
+		}
+	} else {
+		out.IPFamilies = nil
+	}
+	out.NetworkIPv6CIDR = in.NetworkIPv6CIDR
+	out.PodIPv6CIDR = in.PodIPv6CIDR
+	out.ServiceClusterIPv6Range = in.ServiceClusterIPv6Range
 	out.SSHAccess = in.SSHAccess
 	out.NodePortAccess = in.NodePortAccess
 	if in.EgressProxy != nil {
@@ -2513,6 +2683,26 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	out.NetworkCIDR = in.NetworkCIDR
 	out.AdditionalNetworkCIDRs = in.AdditionalNetworkCIDRs
 	out.NetworkID = in.NetworkID
+	if in.VPCEndpoints != nil {
+		in, out := &in.VPCEndpoints, &out.VPCEndpoints
+		*out = make([]VPCEndpointSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_VPCEndpointSpec_To_v1alpha2_VPCEndpointSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.VPCEndpoints = nil
+	}
+	if in.FlowLogs != nil {
+		in, out := &in.FlowLogs, &out.FlowLogs
+		*out = new(FlowLogsSpec)
+		if err := Convert_kops_FlowLogsSpec_To_v1alpha2_FlowLogsSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.FlowLogs = nil
+	}
 	if in.Topology != nil {
 		in, out := &in.Topology, &out.Topology
 		*out = new(TopologySpec)
@@ -2541,6 +2731,18 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	out.ServiceClusterIPRange = in.ServiceClusterIPRange
 	out.PodCIDR = in.PodCIDR
 	out.NonMasqueradeCIDR = in.NonMasqueradeCIDR
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]IPFamily, len(*in))
+		for i := range *in {
+			(*out)[i] = IPFamily((*in)[i])
+		}
+	} else {
+		out.IPFamilies = nil
+	}
+	out.NetworkIPv6CIDR = in.NetworkIPv6CIDR
+	out.PodIPv6CIDR = in.PodIPv6CIDR
+	out.ServiceClusterIPv6Range = in.ServiceClusterIPv6Range
 	out.SSHAccess = in.SSHAccess
 	out.NodePortAccess = in.NodePortAccess
 	if in.EgressProxy != nil {
@@ -2843,6 +3045,7 @@ func autoConvert_v1alpha2_ClusterSubnetSpec_To_kops_ClusterSubnetSpec(in *Cluste
 	out.Zone = in.Zone
 	out.Region = in.Region
 	out.CIDR = in.CIDR
+	out.IPv6CIDR = in.IPv6CIDR
 	out.ProviderID = in.ProviderID
 	out.Egress = in.Egress
 	out.Type = kops.SubnetType(in.Type)
@@ -2858,6 +3061,7 @@ func Convert_v1alpha2_ClusterSubnetSpec_To_kops_ClusterSubnetSpec(in *ClusterSub
 func autoConvert_kops_ClusterSubnetSpec_To_v1alpha2_ClusterSubnetSpec(in *kops.ClusterSubnetSpec, out *ClusterSubnetSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.CIDR = in.CIDR
+	out.IPv6CIDR = in.IPv6CIDR
 	out.Zone = in.Zone
 	out.Region = in.Region
 	out.ProviderID = in.ProviderID
@@ -3506,6 +3710,34 @@ func Convert_kops_FlannelNetworkingSpec_To_v1alpha2_FlannelNetworkingSpec(in *ko
 	return autoConvert_kops_FlannelNetworkingSpec_To_v1alpha2_FlannelNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_FlowLogsSpec_To_kops_FlowLogsSpec(in *FlowLogsSpec, out *kops.FlowLogsSpec, s conversion.Scope) error {
+	out.Destination = in.Destination
+	out.TrafficType = in.TrafficType
+	out.RetentionInDays = in.RetentionInDays
+	out.Bucket = in.Bucket
+	out.BucketPrefix = in.BucketPrefix
+	return nil
+}
+
+// Convert_v1alpha2_FlowLogsSpec_To_kops_FlowLogsSpec is an autogenerated conversion function.
+func Convert_v1alpha2_FlowLogsSpec_To_kops_FlowLogsSpec(in *FlowLogsSpec, out *kops.FlowLogsSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_FlowLogsSpec_To_kops_FlowLogsSpec(in, out, s)
+}
+
+func autoConvert_kops_FlowLogsSpec_To_v1alpha2_FlowLogsSpec(in *kops.FlowLogsSpec, out *FlowLogsSpec, s conversion.Scope) error {
+	out.Destination = in.Destination
+	out.TrafficType = in.TrafficType
+	out.RetentionInDays = in.RetentionInDays
+	out.Bucket = in.Bucket
+	out.BucketPrefix = in.BucketPrefix
+	return nil
+}
+
+// Convert_kops_FlowLogsSpec_To_v1alpha2_FlowLogsSpec is an autogenerated conversion function.
+func Convert_kops_FlowLogsSpec_To_v1alpha2_FlowLogsSpec(in *kops.FlowLogsSpec, out *FlowLogsSpec, s conversion.Scope) error {
+	return autoConvert_kops_FlowLogsSpec_To_v1alpha2_FlowLogsSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_GCENetworkingSpec_To_kops_GCENetworkingSpec(in *GCENetworkingSpec, out *kops.GCENetworkingSpec, s conversion.Scope) error {
 	return nil
 }
@@ -3957,6 +4189,42 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 		out.InstanceMetadata = nil
 	}
 	out.UpdatePolicy = in.UpdatePolicy
+	if in.WarmPool != nil {
+		in, out := &in.WarmPool, &out.WarmPool
+		*out = new(kops.WarmPoolSpec)
+		if err := Convert_v1alpha2_WarmPoolSpec_To_kops_WarmPoolSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.WarmPool = nil
+	}
+	if in.PlacementGroup != nil {
+		in, out := &in.PlacementGroup, &out.PlacementGroup
+		*out = new(kops.PlacementGroupSpec)
+		if err := Convert_v1alpha2_PlacementGroupSpec_To_kops_PlacementGroupSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PlacementGroup = nil
+	}
+	if in.CapacityReservation != nil {
+		in, out := &in.CapacityReservation, &out.CapacityReservation
+		*out = new(kops.CapacityReservationSpec)
+		if err := Convert_v1alpha2_CapacityReservationSpec_To_kops_CapacityReservationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CapacityReservation = nil
+	}
+	if in.Metal != nil {
+		in, out := &in.Metal, &out.Metal
+		*out = new(kops.MetalSpec)
+		if err := Convert_v1alpha2_MetalSpec_To_kops_MetalSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Metal = nil
+	}
 	return nil
 }
 
@@ -4110,6 +4378,42 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 		out.InstanceMetadata = nil
 	}
 	out.UpdatePolicy = in.UpdatePolicy
+	if in.WarmPool != nil {
+		in, out := &in.WarmPool, &out.WarmPool
+		*out = new(WarmPoolSpec)
+		if err := Convert_kops_WarmPoolSpec_To_v1alpha2_WarmPoolSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.WarmPool = nil
+	}
+	if in.PlacementGroup != nil {
+		in, out := &in.PlacementGroup, &out.PlacementGroup
+		*out = new(PlacementGroupSpec)
+		if err := Convert_kops_PlacementGroupSpec_To_v1alpha2_PlacementGroupSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PlacementGroup = nil
+	}
+	if in.CapacityReservation != nil {
+		in, out := &in.CapacityReservation, &out.CapacityReservation
+		*out = new(CapacityReservationSpec)
+		if err := Convert_kops_CapacityReservationSpec_To_v1alpha2_CapacityReservationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CapacityReservation = nil
+	}
+	if in.Metal != nil {
+		in, out := &in.Metal, &out.Metal
+		*out = new(MetalSpec)
+		if err := Convert_kops_MetalSpec_To_v1alpha2_MetalSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Metal = nil
+	}
 	return nil
 }
 
@@ -5153,6 +5457,26 @@ func autoConvert_v1alpha2_LoadBalancerAccessSpec_To_kops_LoadBalancerAccessSpec(
 	} else {
 		out.Subnets = nil
 	}
+	if in.AccessLog != nil {
+		in, out := &in.AccessLog, &out.AccessLog
+		*out = new(kops.AccessLogSpec)
+		if err := Convert_v1alpha2_AccessLogSpec_To_kops_AccessLogSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AccessLog = nil
+	}
+	if in.HealthMonitor != nil {
+		in, out := &in.HealthMonitor, &out.HealthMonitor
+		*out = new(kops.LoadBalancerHealthMonitorSpec)
+		if err := Convert_v1alpha2_LoadBalancerHealthMonitorSpec_To_kops_LoadBalancerHealthMonitorSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HealthMonitor = nil
+	}
+	out.FloatingIP = in.FloatingIP
+	out.TLSPassthrough = in.TLSPassthrough
 	return nil
 }
 
@@ -5182,6 +5506,26 @@ func autoConvert_kops_LoadBalancerAccessSpec_To_v1alpha2_LoadBalancerAccessSpec(
 	} else {
 		out.Subnets = nil
 	}
+	if in.AccessLog != nil {
+		in, out := &in.AccessLog, &out.AccessLog
+		*out = new(AccessLogSpec)
+		if err := Convert_kops_AccessLogSpec_To_v1alpha2_AccessLogSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AccessLog = nil
+	}
+	if in.HealthMonitor != nil {
+		in, out := &in.HealthMonitor, &out.HealthMonitor
+		*out = new(LoadBalancerHealthMonitorSpec)
+		if err := Convert_kops_LoadBalancerHealthMonitorSpec_To_v1alpha2_LoadBalancerHealthMonitorSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HealthMonitor = nil
+	}
+	out.FloatingIP = in.FloatingIP
+	out.TLSPassthrough = in.TLSPassthrough
 	return nil
 }
 
@@ -5190,6 +5534,30 @@ func Convert_kops_LoadBalancerAccessSpec_To_v1alpha2_LoadBalancerAccessSpec(in *
 	return autoConvert_kops_LoadBalancerAccessSpec_To_v1alpha2_LoadBalancerAccessSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_LoadBalancerHealthMonitorSpec_To_kops_LoadBalancerHealthMonitorSpec(in *LoadBalancerHealthMonitorSpec, out *kops.LoadBalancerHealthMonitorSpec, s conversion.Scope) error {
+	out.DelaySeconds = in.DelaySeconds
+	out.TimeoutSeconds = in.TimeoutSeconds
+	out.MaxRetries = in.MaxRetries
+	return nil
+}
+
+// Convert_v1alpha2_LoadBalancerHealthMonitorSpec_To_kops_LoadBalancerHealthMonitorSpec is an autogenerated conversion function.
+func Convert_v1alpha2_LoadBalancerHealthMonitorSpec_To_kops_LoadBalancerHealthMonitorSpec(in *LoadBalancerHealthMonitorSpec, out *kops.LoadBalancerHealthMonitorSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_LoadBalancerHealthMonitorSpec_To_kops_LoadBalancerHealthMonitorSpec(in, out, s)
+}
+
+func autoConvert_kops_LoadBalancerHealthMonitorSpec_To_v1alpha2_LoadBalancerHealthMonitorSpec(in *kops.LoadBalancerHealthMonitorSpec, out *LoadBalancerHealthMonitorSpec, s conversion.Scope) error {
+	out.DelaySeconds = in.DelaySeconds
+	out.TimeoutSeconds = in.TimeoutSeconds
+	out.MaxRetries = in.MaxRetries
+	return nil
+}
+
+// Convert_kops_LoadBalancerHealthMonitorSpec_To_v1alpha2_LoadBalancerHealthMonitorSpec is an autogenerated conversion function.
+func Convert_kops_LoadBalancerHealthMonitorSpec_To_v1alpha2_LoadBalancerHealthMonitorSpec(in *kops.LoadBalancerHealthMonitorSpec, out *LoadBalancerHealthMonitorSpec, s conversion.Scope) error {
+	return autoConvert_kops_LoadBalancerHealthMonitorSpec_To_v1alpha2_LoadBalancerHealthMonitorSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_LoadBalancerSubnetSpec_To_kops_LoadBalancerSubnetSpec(in *LoadBalancerSubnetSpec, out *kops.LoadBalancerSubnetSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.PrivateIPv4Address = in.PrivateIPv4Address
@@ -5234,6 +5602,78 @@ func Convert_kops_LyftVPCNetworkingSpec_To_v1alpha2_LyftVPCNetworkingSpec(in *ko
 	return autoConvert_kops_LyftVPCNetworkingSpec_To_v1alpha2_LyftVPCNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_MetalHostSpec_To_kops_MetalHostSpec(in *MetalHostSpec, out *kops.MetalHostSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Address = in.Address
+	out.SSHPort = in.SSHPort
+	out.SSHUser = in.SSHUser
+	out.SSHPrivateKeyFile = in.SSHPrivateKeyFile
+	out.SSHHostKey = in.SSHHostKey
+	out.Labels = in.Labels
+	return nil
+}
+
+// Convert_v1alpha2_MetalHostSpec_To_kops_MetalHostSpec is an autogenerated conversion function.
+func Convert_v1alpha2_MetalHostSpec_To_kops_MetalHostSpec(in *MetalHostSpec, out *kops.MetalHostSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_MetalHostSpec_To_kops_MetalHostSpec(in, out, s)
+}
+
+func autoConvert_kops_MetalHostSpec_To_v1alpha2_MetalHostSpec(in *kops.MetalHostSpec, out *MetalHostSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Address = in.Address
+	out.SSHPort = in.SSHPort
+	out.SSHUser = in.SSHUser
+	out.SSHPrivateKeyFile = in.SSHPrivateKeyFile
+	out.SSHHostKey = in.SSHHostKey
+	out.Labels = in.Labels
+	return nil
+}
+
+// Convert_kops_MetalHostSpec_To_v1alpha2_MetalHostSpec is an autogenerated conversion function.
+func Convert_kops_MetalHostSpec_To_v1alpha2_MetalHostSpec(in *kops.MetalHostSpec, out *MetalHostSpec, s conversion.Scope) error {
+	return autoConvert_kops_MetalHostSpec_To_v1alpha2_MetalHostSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_MetalSpec_To_kops_MetalSpec(in *MetalSpec, out *kops.MetalSpec, s conversion.Scope) error {
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]kops.MetalHostSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_MetalHostSpec_To_kops_MetalHostSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Hosts = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_MetalSpec_To_kops_MetalSpec is an autogenerated conversion function.
+func Convert_v1alpha2_MetalSpec_To_kops_MetalSpec(in *MetalSpec, out *kops.MetalSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_MetalSpec_To_kops_MetalSpec(in, out, s)
+}
+
+func autoConvert_kops_MetalSpec_To_v1alpha2_MetalSpec(in *kops.MetalSpec, out *MetalSpec, s conversion.Scope) error {
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]MetalHostSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_MetalHostSpec_To_v1alpha2_MetalHostSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Hosts = nil
+	}
+	return nil
+}
+
+// Convert_kops_MetalSpec_To_v1alpha2_MetalSpec is an autogenerated conversion function.
+func Convert_kops_MetalSpec_To_v1alpha2_MetalSpec(in *kops.MetalSpec, out *MetalSpec, s conversion.Scope) error {
+	return autoConvert_kops_MetalSpec_To_v1alpha2_MetalSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_MetricsServerConfig_To_kops_MetricsServerConfig(in *MetricsServerConfig, out *kops.MetricsServerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Image = in.Image
@@ -5970,6 +6410,28 @@ func Convert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(in *kops.PackagesCon
 	return autoConvert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_PlacementGroupSpec_To_kops_PlacementGroupSpec(in *PlacementGroupSpec, out *kops.PlacementGroupSpec, s conversion.Scope) error {
+	out.Strategy = in.Strategy
+	out.PartitionCount = in.PartitionCount
+	return nil
+}
+
+// Convert_v1alpha2_PlacementGroupSpec_To_kops_PlacementGroupSpec is an autogenerated conversion function.
+func Convert_v1alpha2_PlacementGroupSpec_To_kops_PlacementGroupSpec(in *PlacementGroupSpec, out *kops.PlacementGroupSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PlacementGroupSpec_To_kops_PlacementGroupSpec(in, out, s)
+}
+
+func autoConvert_kops_PlacementGroupSpec_To_v1alpha2_PlacementGroupSpec(in *kops.PlacementGroupSpec, out *PlacementGroupSpec, s conversion.Scope) error {
+	out.Strategy = in.Strategy
+	out.PartitionCount = in.PartitionCount
+	return nil
+}
+
+// Convert_kops_PlacementGroupSpec_To_v1alpha2_PlacementGroupSpec is an autogenerated conversion function.
+func Convert_kops_PlacementGroupSpec_To_v1alpha2_PlacementGroupSpec(in *kops.PlacementGroupSpec, out *PlacementGroupSpec, s conversion.Scope) error {
+	return autoConvert_kops_PlacementGroupSpec_To_v1alpha2_PlacementGroupSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(in *RBACAuthorizationSpec, out *kops.RBACAuthorizationSpec, s conversion.Scope) error {
 	return nil
 }
@@ -6260,6 +6722,30 @@ func Convert_kops_UserData_To_v1alpha2_UserData(in *kops.UserData, out *UserData
 	return autoConvert_kops_UserData_To_v1alpha2_UserData(in, out, s)
 }
 
+func autoConvert_v1alpha2_VPCEndpointSpec_To_kops_VPCEndpointSpec(in *VPCEndpointSpec, out *kops.VPCEndpointSpec, s conversion.Scope) error {
+	out.Service = in.Service
+	out.Type = in.Type
+	out.PrivateDNSEnabled = in.PrivateDNSEnabled
+	return nil
+}
+
+// Convert_v1alpha2_VPCEndpointSpec_To_kops_VPCEndpointSpec is an autogenerated conversion function.
+func Convert_v1alpha2_VPCEndpointSpec_To_kops_VPCEndpointSpec(in *VPCEndpointSpec, out *kops.VPCEndpointSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_VPCEndpointSpec_To_kops_VPCEndpointSpec(in, out, s)
+}
+
+func autoConvert_kops_VPCEndpointSpec_To_v1alpha2_VPCEndpointSpec(in *kops.VPCEndpointSpec, out *VPCEndpointSpec, s conversion.Scope) error {
+	out.Service = in.Service
+	out.Type = in.Type
+	out.PrivateDNSEnabled = in.PrivateDNSEnabled
+	return nil
+}
+
+// Convert_kops_VPCEndpointSpec_To_v1alpha2_VPCEndpointSpec is an autogenerated conversion function.
+func Convert_kops_VPCEndpointSpec_To_v1alpha2_VPCEndpointSpec(in *kops.VPCEndpointSpec, out *VPCEndpointSpec, s conversion.Scope) error {
+	return autoConvert_kops_VPCEndpointSpec_To_v1alpha2_VPCEndpointSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(in *VolumeMountSpec, out *kops.VolumeMountSpec, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
@@ -6322,6 +6808,30 @@ func Convert_kops_VolumeSpec_To_v1alpha2_VolumeSpec(in *kops.VolumeSpec, out *Vo
 	return autoConvert_kops_VolumeSpec_To_v1alpha2_VolumeSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_WarmPoolSpec_To_kops_WarmPoolSpec(in *WarmPoolSpec, out *kops.WarmPoolSpec, s conversion.Scope) error {
+	out.MinSize = in.MinSize
+	out.MaxPreparedCapacity = in.MaxPreparedCapacity
+	out.PoolState = in.PoolState
+	return nil
+}
+
+// Convert_v1alpha2_WarmPoolSpec_To_kops_WarmPoolSpec is an autogenerated conversion function.
+func Convert_v1alpha2_WarmPoolSpec_To_kops_WarmPoolSpec(in *WarmPoolSpec, out *kops.WarmPoolSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_WarmPoolSpec_To_kops_WarmPoolSpec(in, out, s)
+}
+
+func autoConvert_kops_WarmPoolSpec_To_v1alpha2_WarmPoolSpec(in *kops.WarmPoolSpec, out *WarmPoolSpec, s conversion.Scope) error {
+	out.MinSize = in.MinSize
+	out.MaxPreparedCapacity = in.MaxPreparedCapacity
+	out.PoolState = in.PoolState
+	return nil
+}
+
+// Convert_kops_WarmPoolSpec_To_v1alpha2_WarmPoolSpec is an autogenerated conversion function.
+func Convert_kops_WarmPoolSpec_To_v1alpha2_WarmPoolSpec(in *kops.WarmPoolSpec, out *WarmPoolSpec, s conversion.Scope) error {
+	return autoConvert_kops_WarmPoolSpec_To_v1alpha2_WarmPoolSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_WeaveNetworkingSpec_To_kops_WeaveNetworkingSpec(in *WeaveNetworkingSpec, out *kops.WeaveNetworkingSpec, s conversion.Scope) error {
 	out.MTU = in.MTU
 	out.ConnLimit = in.ConnLimit
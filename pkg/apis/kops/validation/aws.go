@@ -38,11 +38,117 @@ func awsValidateCluster(c *kops.Cluster) field.ErrorList {
 			allErrs = append(allErrs, awsValidateAdditionalSecurityGroups(field.NewPath("spec", "api", "loadBalancer", "additionalSecurityGroups"), c.Spec.API.LoadBalancer.AdditionalSecurityGroups)...)
 			allErrs = append(allErrs, awsValidateSSLPolicy(field.NewPath("spec", "api", "loadBalancer", "sslPolicy"), c.Spec.API.LoadBalancer)...)
 			allErrs = append(allErrs, awsValidateLoadBalancerSubnets(field.NewPath("spec", "api", "loadBalancer", "subnets"), c.Spec)...)
+			allErrs = append(allErrs, awsValidateAccessLog(field.NewPath("spec", "api", "loadBalancer", "accessLog"), c.Spec.API.LoadBalancer)...)
 		}
 	}
 
 	allErrs = append(allErrs, awsValidateExternalCloudControllerManager(c.Spec)...)
 
+	allErrs = append(allErrs, awsValidateVPCEndpoints(field.NewPath("spec", "vpcEndpoints"), c.Spec.VPCEndpoints)...)
+
+	if c.Spec.FlowLogs != nil {
+		allErrs = append(allErrs, awsValidateFlowLogs(field.NewPath("spec", "flowLogs"), c.Spec.FlowLogs)...)
+	}
+
+	return allErrs
+}
+
+// awsValidateVPCEndpoints checks the services and types of the VPC endpoints of the cluster
+func awsValidateVPCEndpoints(fieldPath *field.Path, endpoints []kops.VPCEndpointSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	services := sets.NewString()
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		path := fieldPath.Index(i)
+
+		if endpoint.Service == "" {
+			allErrs = append(allErrs, field.Required(path.Child("service"), "service must be set"))
+			continue
+		}
+		if services.Has(endpoint.Service) {
+			allErrs = append(allErrs, field.Duplicate(path.Child("service"), endpoint.Service))
+		}
+		services.Insert(endpoint.Service)
+
+		if endpoint.Type != "" {
+			allErrs = append(allErrs, IsValidValue(path.Child("type"), &endpoint.Type, kops.VPCEndpointTypes)...)
+		}
+		switch endpoint.EndpointType() {
+		case kops.VPCEndpointTypeGateway:
+			if !sets.NewString(kops.VPCGatewayEndpointServices...).Has(endpoint.Service) {
+				allErrs = append(allErrs, field.Forbidden(path.Child("type"), fmt.Sprintf("gateway endpoints are only supported for %s", strings.Join(kops.VPCGatewayEndpointServices, ", "))))
+			}
+			if endpoint.PrivateDNSEnabled != nil {
+				allErrs = append(allErrs, field.Forbidden(path.Child("privateDNSEnabled"), "privateDNSEnabled can only be set for interface endpoints"))
+			}
+		case kops.VPCEndpointTypeInterface:
+			if endpoint.Service == "s3" && fi.BoolValue(endpoint.PrivateDNSEnabled) {
+				allErrs = append(allErrs, field.Forbidden(path.Child("privateDNSEnabled"), "interface endpoints for s3 do not support private DNS"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// awsValidateFlowLogs checks the destination and traffic type of the VPC flow logs
+func awsValidateFlowLogs(fieldPath *field.Path, spec *kops.FlowLogsSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Destination != "" {
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("destination"), &spec.Destination, kops.FlowLogsDestinations)...)
+	}
+	if spec.TrafficType != "" {
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("trafficType"), &spec.TrafficType, kops.FlowLogsTrafficTypes)...)
+	}
+
+	if spec.Destination == kops.FlowLogsDestinationS3 {
+		if spec.Bucket == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("bucket"), "bucket must be set for the S3 destination"))
+		}
+		if spec.RetentionInDays != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("retentionInDays"), "retentionInDays can only be set for the CloudWatchLogs destination"))
+		}
+	} else {
+		if spec.Bucket != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("bucket"), "bucket can only be set for the S3 destination"))
+		}
+		if spec.BucketPrefix != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("bucketPrefix"), "bucketPrefix can only be set for the S3 destination"))
+		}
+		if spec.RetentionInDays != nil {
+			retention := strconv.FormatInt(*spec.RetentionInDays, 10)
+			allErrs = append(allErrs, IsValidValue(fieldPath.Child("retentionInDays"), &retention, awsLogRetentionDays)...)
+		}
+	}
+
+	return allErrs
+}
+
+// awsLogRetentionDays are the retention periods supported by CloudWatch Logs
+var awsLogRetentionDays = []string{"1", "3", "5", "7", "14", "30", "60", "90", "120", "150", "180", "365", "400", "545", "731", "1827", "3653"}
+
+// awsValidateAccessLog checks the access logs of the API load balancer
+func awsValidateAccessLog(fieldPath *field.Path, lbSpec *kops.LoadBalancerAccessSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if lbSpec.AccessLog == nil {
+		return allErrs
+	}
+
+	if lbSpec.AccessLog.Bucket == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("bucket"), "bucket must be set"))
+	}
+
+	if lbSpec.AccessLog.Interval != 0 {
+		if lbSpec.Class == kops.LoadBalancerClassNetwork {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("interval"), "interval is not supported by Network load balancers"))
+		} else if lbSpec.AccessLog.Interval != 5 && lbSpec.AccessLog.Interval != 60 {
+			allErrs = append(allErrs, field.NotSupported(fieldPath.Child("interval"), lbSpec.AccessLog.Interval, []string{"5", "60"}))
+		}
+	}
+
 	return allErrs
 }
 
@@ -83,6 +189,98 @@ func awsValidateInstanceGroup(ig *kops.InstanceGroup, cloud awsup.AWSCloud) fiel
 		allErrs = append(allErrs, awsValidateCPUCredits(field.NewPath("spec"), &ig.Spec, cloud)...)
 	}
 
+	if ig.Spec.WarmPool != nil {
+		allErrs = append(allErrs, awsValidateWarmPool(field.NewPath("spec", "warmPool"), ig.Spec.WarmPool, ig)...)
+	}
+
+	if ig.Spec.PlacementGroup != nil {
+		allErrs = append(allErrs, awsValidatePlacementGroup(field.NewPath("spec", "placementGroup"), ig.Spec.PlacementGroup, ig)...)
+	}
+
+	if ig.Spec.CapacityReservation != nil {
+		allErrs = append(allErrs, awsValidateCapacityReservation(field.NewPath("spec", "capacityReservation"), ig.Spec.CapacityReservation, ig)...)
+	}
+
+	return allErrs
+}
+
+// awsValidateWarmPool checks the warm pool can be created for the autoscaling group of the instance group
+func awsValidateWarmPool(fieldPath *field.Path, warmPool *kops.WarmPoolSpec, ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ig.Spec.Role != kops.InstanceGroupRoleNode {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "warm pools are only supported for instance groups with the Node role"))
+	}
+	if ig.Spec.MixedInstancesPolicy != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "warm pools cannot be used with a mixed instances policy"))
+	}
+	if ig.Spec.MaxPrice != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "warm pools cannot be used with spot instances"))
+	}
+
+	if warmPool.MinSize < 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("minSize"), warmPool.MinSize, "cannot be less than zero"))
+	}
+	if warmPool.MaxPreparedCapacity != nil {
+		maxPreparedCapacity := fi.Int64Value(warmPool.MaxPreparedCapacity)
+		if maxPreparedCapacity < warmPool.MinSize {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxPreparedCapacity"), maxPreparedCapacity, "cannot be less than the minimum size of the warm pool"))
+		}
+		if maxPreparedCapacity < int64(fi.Int32Value(ig.Spec.MinSize)) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxPreparedCapacity"), maxPreparedCapacity, "cannot be less than the minimum size of the instance group"))
+		}
+	}
+
+	allErrs = append(allErrs, IsValidValue(fieldPath.Child("poolState"), warmPool.PoolState, kops.WarmPoolStates)...)
+
+	return allErrs
+}
+
+// awsValidatePlacementGroup checks the strategy and partitions of the placement group of the instance group
+func awsValidatePlacementGroup(fieldPath *field.Path, placementGroup *kops.PlacementGroupSpec, ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, IsValidValue(fieldPath.Child("strategy"), &placementGroup.Strategy, kops.PlacementStrategies)...)
+
+	if placementGroup.PartitionCount != nil {
+		partitionCount := fi.Int64Value(placementGroup.PartitionCount)
+		if placementGroup.Strategy != kops.PlacementStrategyPartition {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("partitionCount"), "partitionCount can only be set for the partition strategy"))
+		} else if partitionCount < 1 || partitionCount > 7 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("partitionCount"), partitionCount, "must be between 1 and 7"))
+		}
+	}
+
+	if placementGroup.Strategy == kops.PlacementStrategyCluster && len(ig.Spec.Subnets) > 1 {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("strategy"), "cluster placement groups require the instance group to use a single subnet"))
+	}
+
+	return allErrs
+}
+
+// awsValidateCapacityReservation checks the capacity reservation preference or target of the instance group
+func awsValidateCapacityReservation(fieldPath *field.Path, capacityReservation *kops.CapacityReservationSpec, ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ig.Spec.MaxPrice != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "capacity reservations cannot be used with spot instances"))
+	}
+
+	if capacityReservation.Preference != nil {
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("preference"), capacityReservation.Preference, kops.CapacityReservationPreferences)...)
+		if capacityReservation.ID != nil || capacityReservation.ResourceGroupARN != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("preference"), "preference cannot be combined with a capacity reservation target"))
+		}
+	}
+
+	if capacityReservation.ID != nil && capacityReservation.ResourceGroupARN != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("resourceGroupARN"), "only one of id or resourceGroupARN can be set"))
+	}
+
+	if capacityReservation.ResourceGroupARN != nil && !strings.HasPrefix(fi.StringValue(capacityReservation.ResourceGroupARN), "arn:") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("resourceGroupARN"), fi.StringValue(capacityReservation.ResourceGroupARN), "must be an ARN"))
+	}
+
 	return allErrs
 }
 
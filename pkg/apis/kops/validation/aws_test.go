@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
 )
 
@@ -357,6 +358,338 @@ func TestInstanceMetadataOptions(t *testing.T) {
 	}
 }
 
+func TestWarmPool(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+
+	mockEC2 := &mockec2.MockEC2{}
+	cloud.MockEC2 = mockEC2
+
+	mockEC2.Images = append(mockEC2.Images, &ec2.Image{
+		CreationDate:   aws.String("2016-10-21T20:07:19.000Z"),
+		ImageId:        aws.String("ami-073c8c0760395aab8"),
+		Name:           aws.String("focal"),
+		OwnerId:        aws.String(awsup.WellKnownAccountUbuntu),
+		RootDeviceName: aws.String("/dev/xvda"),
+		Architecture:   aws.String("x86_64"),
+	})
+
+	tests := []struct {
+		role     kops.InstanceGroupRole
+		minSize  int32
+		warmPool *kops.WarmPoolSpec
+		expected []string
+	}{
+		{
+			role: kops.InstanceGroupRoleNode,
+			warmPool: &kops.WarmPoolSpec{
+				MinSize:             1,
+				MaxPreparedCapacity: fi.Int64(5),
+				PoolState:           fi.String(kops.WarmPoolStateRunning),
+			},
+		},
+		{
+			role:     kops.InstanceGroupRoleMaster,
+			warmPool: &kops.WarmPoolSpec{},
+			expected: []string{"Forbidden::spec.warmPool"},
+		},
+		{
+			role:     kops.InstanceGroupRoleNode,
+			warmPool: &kops.WarmPoolSpec{MinSize: -1},
+			expected: []string{"Invalid value::spec.warmPool.minSize"},
+		},
+		{
+			role:     kops.InstanceGroupRoleNode,
+			minSize:  3,
+			warmPool: &kops.WarmPoolSpec{MaxPreparedCapacity: fi.Int64(2)},
+			expected: []string{"Invalid value::spec.warmPool.maxPreparedCapacity"},
+		},
+		{
+			role:     kops.InstanceGroupRoleNode,
+			warmPool: &kops.WarmPoolSpec{PoolState: fi.String("Hibernated")},
+			expected: []string{"Unsupported value::spec.warmPool.poolState"},
+		},
+	}
+
+	for _, test := range tests {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:        test.role,
+				Image:       "ami-073c8c0760395aab8",
+				MachineType: "t3.medium",
+				MinSize:     fi.Int32(test.minSize),
+				MaxSize:     fi.Int32(10),
+				Subnets:     []string{"us-east-1a"},
+				WarmPool:    test.warmPool,
+			},
+		}
+		errs := ValidateInstanceGroup(ig, cloud)
+		testErrors(t, ig.ObjectMeta.Name, errs, test.expected)
+	}
+}
+
+func TestPlacementGroup(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+
+	mockEC2 := &mockec2.MockEC2{}
+	cloud.MockEC2 = mockEC2
+
+	mockEC2.Images = append(mockEC2.Images, &ec2.Image{
+		CreationDate:   aws.String("2016-10-21T20:07:19.000Z"),
+		ImageId:        aws.String("ami-073c8c0760395aab8"),
+		Name:           aws.String("focal"),
+		OwnerId:        aws.String(awsup.WellKnownAccountUbuntu),
+		RootDeviceName: aws.String("/dev/xvda"),
+		Architecture:   aws.String("x86_64"),
+	})
+
+	tests := []struct {
+		subnets        []string
+		placementGroup *kops.PlacementGroupSpec
+		expected       []string
+	}{
+		{
+			subnets:        []string{"us-east-1a"},
+			placementGroup: &kops.PlacementGroupSpec{Strategy: kops.PlacementStrategyCluster},
+		},
+		{
+			subnets:        []string{"us-east-1a", "us-east-1b"},
+			placementGroup: &kops.PlacementGroupSpec{Strategy: kops.PlacementStrategySpread},
+		},
+		{
+			subnets:        []string{"us-east-1a", "us-east-1b"},
+			placementGroup: &kops.PlacementGroupSpec{Strategy: kops.PlacementStrategyPartition, PartitionCount: fi.Int64(3)},
+		},
+		{
+			subnets:        []string{"us-east-1a"},
+			placementGroup: &kops.PlacementGroupSpec{Strategy: "rack"},
+			expected:       []string{"Unsupported value::spec.placementGroup.strategy"},
+		},
+		{
+			subnets:        []string{"us-east-1a", "us-east-1b"},
+			placementGroup: &kops.PlacementGroupSpec{Strategy: kops.PlacementStrategyCluster},
+			expected:       []string{"Forbidden::spec.placementGroup.strategy"},
+		},
+		{
+			subnets:        []string{"us-east-1a"},
+			placementGroup: &kops.PlacementGroupSpec{Strategy: kops.PlacementStrategySpread, PartitionCount: fi.Int64(2)},
+			expected:       []string{"Forbidden::spec.placementGroup.partitionCount"},
+		},
+		{
+			subnets:        []string{"us-east-1a"},
+			placementGroup: &kops.PlacementGroupSpec{Strategy: kops.PlacementStrategyPartition, PartitionCount: fi.Int64(8)},
+			expected:       []string{"Invalid value::spec.placementGroup.partitionCount"},
+		},
+	}
+
+	for _, test := range tests {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:           kops.InstanceGroupRoleNode,
+				Image:          "ami-073c8c0760395aab8",
+				MachineType:    "t3.medium",
+				MinSize:        fi.Int32(1),
+				MaxSize:        fi.Int32(10),
+				Subnets:        test.subnets,
+				PlacementGroup: test.placementGroup,
+			},
+		}
+		errs := ValidateInstanceGroup(ig, cloud)
+		testErrors(t, ig.ObjectMeta.Name, errs, test.expected)
+	}
+}
+
+func TestCapacityReservation(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+
+	mockEC2 := &mockec2.MockEC2{}
+	cloud.MockEC2 = mockEC2
+
+	mockEC2.Images = append(mockEC2.Images, &ec2.Image{
+		CreationDate:   aws.String("2016-10-21T20:07:19.000Z"),
+		ImageId:        aws.String("ami-073c8c0760395aab8"),
+		Name:           aws.String("focal"),
+		OwnerId:        aws.String(awsup.WellKnownAccountUbuntu),
+		RootDeviceName: aws.String("/dev/xvda"),
+		Architecture:   aws.String("x86_64"),
+	})
+
+	tests := []struct {
+		maxPrice            *string
+		capacityReservation *kops.CapacityReservationSpec
+		expected            []string
+	}{
+		{
+			capacityReservation: &kops.CapacityReservationSpec{Preference: fi.String(kops.CapacityReservationPreferenceNone)},
+		},
+		{
+			capacityReservation: &kops.CapacityReservationSpec{ID: fi.String("cr-0123456789abcdef0")},
+		},
+		{
+			capacityReservation: &kops.CapacityReservationSpec{ResourceGroupARN: fi.String("arn:aws:resource-groups:us-east-1:123456789012:group/my-reservations")},
+		},
+		{
+			capacityReservation: &kops.CapacityReservationSpec{Preference: fi.String("targeted")},
+			expected:            []string{"Unsupported value::spec.capacityReservation.preference"},
+		},
+		{
+			capacityReservation: &kops.CapacityReservationSpec{Preference: fi.String(kops.CapacityReservationPreferenceOpen), ID: fi.String("cr-0123456789abcdef0")},
+			expected:            []string{"Forbidden::spec.capacityReservation.preference"},
+		},
+		{
+			capacityReservation: &kops.CapacityReservationSpec{ID: fi.String("cr-0123456789abcdef0"), ResourceGroupARN: fi.String("arn:aws:resource-groups:us-east-1:123456789012:group/my-reservations")},
+			expected:            []string{"Forbidden::spec.capacityReservation.resourceGroupARN"},
+		},
+		{
+			capacityReservation: &kops.CapacityReservationSpec{ResourceGroupARN: fi.String("my-reservations")},
+			expected:            []string{"Invalid value::spec.capacityReservation.resourceGroupARN"},
+		},
+		{
+			maxPrice:            fi.String("0.1"),
+			capacityReservation: &kops.CapacityReservationSpec{Preference: fi.String(kops.CapacityReservationPreferenceOpen)},
+			expected:            []string{"Forbidden::spec.capacityReservation"},
+		},
+	}
+
+	for _, test := range tests {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:                kops.InstanceGroupRoleNode,
+				Image:               "ami-073c8c0760395aab8",
+				MachineType:         "t3.medium",
+				MinSize:             fi.Int32(1),
+				MaxSize:             fi.Int32(10),
+				MaxPrice:            test.maxPrice,
+				Subnets:             []string{"us-east-1a"},
+				CapacityReservation: test.capacityReservation,
+			},
+		}
+		errs := ValidateInstanceGroup(ig, cloud)
+		testErrors(t, ig.ObjectMeta.Name, errs, test.expected)
+	}
+}
+
+func TestAWSValidateVPCEndpoints(t *testing.T) {
+	tests := []struct {
+		endpoints []kops.VPCEndpointSpec
+		expected  []string
+	}{
+		{
+			endpoints: []kops.VPCEndpointSpec{
+				{Service: "s3"},
+				{Service: "ecr.api"},
+				{Service: "ecr.dkr", PrivateDNSEnabled: fi.Bool(true)},
+				{Service: "dynamodb", Type: kops.VPCEndpointTypeInterface},
+			},
+		},
+		{
+			endpoints: []kops.VPCEndpointSpec{{}},
+			expected:  []string{"Required value::spec.vpcEndpoints[0].service"},
+		},
+		{
+			endpoints: []kops.VPCEndpointSpec{{Service: "sts"}, {Service: "sts"}},
+			expected:  []string{"Duplicate value::spec.vpcEndpoints[1].service"},
+		},
+		{
+			endpoints: []kops.VPCEndpointSpec{{Service: "sts", Type: "Peering"}},
+			expected:  []string{"Unsupported value::spec.vpcEndpoints[0].type"},
+		},
+		{
+			endpoints: []kops.VPCEndpointSpec{{Service: "ec2", Type: kops.VPCEndpointTypeGateway}},
+			expected:  []string{"Forbidden::spec.vpcEndpoints[0].type"},
+		},
+		{
+			endpoints: []kops.VPCEndpointSpec{{Service: "s3", PrivateDNSEnabled: fi.Bool(false)}},
+			expected:  []string{"Forbidden::spec.vpcEndpoints[0].privateDNSEnabled"},
+		},
+		{
+			endpoints: []kops.VPCEndpointSpec{{Service: "s3", Type: kops.VPCEndpointTypeInterface, PrivateDNSEnabled: fi.Bool(true)}},
+			expected:  []string{"Forbidden::spec.vpcEndpoints[0].privateDNSEnabled"},
+		},
+	}
+
+	for _, test := range tests {
+		errs := awsValidateVPCEndpoints(field.NewPath("spec", "vpcEndpoints"), test.endpoints)
+		testErrors(t, test.endpoints, errs, test.expected)
+	}
+}
+
+func TestAWSValidateFlowLogs(t *testing.T) {
+	tests := []struct {
+		spec     kops.FlowLogsSpec
+		expected []string
+	}{
+		{
+			spec: kops.FlowLogsSpec{},
+		},
+		{
+			spec: kops.FlowLogsSpec{Destination: kops.FlowLogsDestinationCloudWatchLogs, TrafficType: "REJECT", RetentionInDays: fi.Int64(30)},
+		},
+		{
+			spec: kops.FlowLogsSpec{Destination: kops.FlowLogsDestinationS3, Bucket: "flow-logs", BucketPrefix: "example.com"},
+		},
+		{
+			spec:     kops.FlowLogsSpec{Destination: "Kinesis", TrafficType: "SOME"},
+			expected: []string{"Unsupported value::spec.flowLogs.destination", "Unsupported value::spec.flowLogs.trafficType"},
+		},
+		{
+			spec:     kops.FlowLogsSpec{Destination: kops.FlowLogsDestinationS3, RetentionInDays: fi.Int64(30)},
+			expected: []string{"Required value::spec.flowLogs.bucket", "Forbidden::spec.flowLogs.retentionInDays"},
+		},
+		{
+			spec:     kops.FlowLogsSpec{Bucket: "flow-logs", BucketPrefix: "example.com"},
+			expected: []string{"Forbidden::spec.flowLogs.bucket", "Forbidden::spec.flowLogs.bucketPrefix"},
+		},
+		{
+			spec:     kops.FlowLogsSpec{RetentionInDays: fi.Int64(42)},
+			expected: []string{"Unsupported value::spec.flowLogs.retentionInDays"},
+		},
+	}
+
+	for _, test := range tests {
+		errs := awsValidateFlowLogs(field.NewPath("spec", "flowLogs"), &test.spec)
+		testErrors(t, test.spec, errs, test.expected)
+	}
+}
+
+func TestAWSValidateAccessLog(t *testing.T) {
+	tests := []struct {
+		lbSpec   kops.LoadBalancerAccessSpec
+		expected []string
+	}{
+		{
+			lbSpec: kops.LoadBalancerAccessSpec{},
+		},
+		{
+			lbSpec: kops.LoadBalancerAccessSpec{AccessLog: &kops.AccessLogSpec{Bucket: "access-logs", Interval: 5}},
+		},
+		{
+			lbSpec: kops.LoadBalancerAccessSpec{Class: kops.LoadBalancerClassNetwork, AccessLog: &kops.AccessLogSpec{Bucket: "access-logs", BucketPrefix: "api"}},
+		},
+		{
+			lbSpec:   kops.LoadBalancerAccessSpec{AccessLog: &kops.AccessLogSpec{Interval: 10}},
+			expected: []string{"Required value::spec.api.loadBalancer.accessLog.bucket", "Unsupported value::spec.api.loadBalancer.accessLog.interval"},
+		},
+		{
+			lbSpec:   kops.LoadBalancerAccessSpec{Class: kops.LoadBalancerClassNetwork, AccessLog: &kops.AccessLogSpec{Bucket: "access-logs", Interval: 5}},
+			expected: []string{"Forbidden::spec.api.loadBalancer.accessLog.interval"},
+		},
+	}
+
+	for _, test := range tests {
+		errs := awsValidateAccessLog(field.NewPath("spec", "api", "loadBalancer", "accessLog"), &test.lbSpec)
+		testErrors(t, test.lbSpec, errs, test.expected)
+	}
+}
+
 func TestLoadBalancerSubnets(t *testing.T) {
 	cidr := "10.0.0.0/24"
 	tests := []struct {
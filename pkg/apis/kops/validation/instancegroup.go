@@ -24,6 +24,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"golang.org/x/crypto/ssh"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
@@ -142,6 +143,65 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud) field.ErrorLis
 
 	allErrs = append(allErrs, IsValidValue(field.NewPath("spec", "updatePolicy"), g.Spec.UpdatePolicy, []string{kops.UpdatePolicyAutomatic, kops.UpdatePolicyExternal})...)
 
+	if g.Spec.Metal != nil {
+		allErrs = append(allErrs, validateMetal(g, field.NewPath("spec", "metal"))...)
+	}
+
+	return allErrs
+}
+
+// validateMetal checks the inventory of pre-provisioned hosts of the instance group
+func validateMetal(g *kops.InstanceGroup, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	hosts := g.Spec.Metal.Hosts
+	if len(hosts) == 0 {
+		return append(allErrs, field.Required(fldPath.Child("hosts"), "at least one host must be specified"))
+	}
+
+	names := make(map[string]bool)
+	for i, host := range hosts {
+		hostPath := fldPath.Child("hosts").Index(i)
+
+		if host.Name == "" {
+			allErrs = append(allErrs, field.Required(hostPath.Child("name"), "the hostname of the host must be specified"))
+		} else if names[host.Name] {
+			allErrs = append(allErrs, field.Duplicate(hostPath.Child("name"), host.Name))
+		}
+		names[host.Name] = true
+
+		if host.Address == "" {
+			allErrs = append(allErrs, field.Required(hostPath.Child("address"), "the address of the host must be specified"))
+		}
+
+		if host.SSHPort != nil && (*host.SSHPort < 1 || *host.SSHPort > 65535) {
+			allErrs = append(allErrs, field.Invalid(hostPath.Child("sshPort"), *host.SSHPort, "must be a valid port"))
+		}
+
+		if host.SSHHostKey == "" {
+			allErrs = append(allErrs, field.Required(hostPath.Child("sshHostKey"), "the public key of the SSH server of the host must be specified"))
+		} else if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(host.SSHHostKey)); err != nil {
+			allErrs = append(allErrs, field.Invalid(hostPath.Child("sshHostKey"), host.SSHHostKey, fmt.Sprintf("must be a public key in authorized_keys format: %v", err)))
+		}
+
+		if host.Labels != nil {
+			allErrs = append(allErrs, validateNodeLabels(host.Labels, hostPath.Child("labels"))...)
+		}
+	}
+
+	// The instance group can't be scaled beyond its inventory
+	if g.Spec.MinSize != nil && int(*g.Spec.MinSize) != len(hosts) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "minSize"), *g.Spec.MinSize, "must match the number of metal hosts"))
+	}
+	if g.Spec.MaxSize != nil && int(*g.Spec.MaxSize) != len(hosts) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "maxSize"), *g.Spec.MaxSize, "must match the number of metal hosts"))
+	}
+
+	// etcd members are assigned to master instance groups, so each must map to a single host
+	if g.IsMaster() && len(hosts) != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("hosts"), len(hosts), "master InstanceGroups must have exactly one metal host"))
+	}
+
 	return allErrs
 }
 
@@ -201,6 +261,26 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 		}
 	}
 
+	if g.Spec.WarmPool != nil && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "warmPool"), "warm pools are only supported on AWS"))
+	}
+
+	if g.Spec.PlacementGroup != nil && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "placementGroup"), "placement groups are only supported on AWS"))
+	}
+
+	if g.Spec.CapacityReservation != nil && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "capacityReservation"), "capacity reservations are only supported on AWS"))
+	}
+
+	if g.Spec.Metal != nil && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderMetal {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "metal"), "metal hosts are only supported on the metal cloud provider"))
+	}
+
+	if g.Spec.Metal == nil && kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderMetal {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "metal"), "InstanceGroups of metal clusters must list their hosts"))
+	}
+
 	if g.Spec.RootVolumeType != nil && kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderAWS {
 		allErrs = append(allErrs, IsValidValue(field.NewPath("spec", "rootVolumeType"), g.Spec.RootVolumeType, []string{"standard", "gp3", "gp2", "io1", "io2"})...)
 	}
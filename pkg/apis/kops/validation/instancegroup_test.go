@@ -384,3 +384,107 @@ func TestIGUpdatePolicy(t *testing.T) {
 		})
 	}
 }
+
+const testSSHHostKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHTWBt76gKaKZHuDR3iehTSGral8ZMFJcV12hOnFYOwc"
+
+func TestValidateMetal(t *testing.T) {
+	for _, test := range []struct {
+		label    string
+		role     kops.InstanceGroupRole
+		hosts    []kops.MetalHostSpec
+		size     *int32
+		expected []string
+	}{
+		{
+			label: "valid",
+			role:  kops.InstanceGroupRoleNode,
+			hosts: []kops.MetalHostSpec{
+				{Name: "node-1", Address: "192.168.1.11", SSHHostKey: testSSHHostKey},
+				{Name: "node-2", Address: "192.168.1.12", SSHHostKey: testSSHHostKey, SSHPort: fi.Int32(2222)},
+			},
+			size: fi.Int32(2),
+		},
+		{
+			label:    "no hosts",
+			role:     kops.InstanceGroupRoleNode,
+			expected: []string{"Required value::spec.metal.hosts"},
+		},
+		{
+			label: "missing fields",
+			role:  kops.InstanceGroupRoleNode,
+			hosts: []kops.MetalHostSpec{
+				{},
+			},
+			expected: []string{
+				"Required value::spec.metal.hosts[0].name",
+				"Required value::spec.metal.hosts[0].address",
+				"Required value::spec.metal.hosts[0].sshHostKey",
+			},
+		},
+		{
+			label: "duplicate name",
+			role:  kops.InstanceGroupRoleNode,
+			hosts: []kops.MetalHostSpec{
+				{Name: "node-1", Address: "192.168.1.11", SSHHostKey: testSSHHostKey},
+				{Name: "node-1", Address: "192.168.1.12", SSHHostKey: testSSHHostKey},
+			},
+			expected: []string{"Duplicate value::spec.metal.hosts[1].name"},
+		},
+		{
+			label: "invalid port",
+			role:  kops.InstanceGroupRoleNode,
+			hosts: []kops.MetalHostSpec{
+				{Name: "node-1", Address: "192.168.1.11", SSHHostKey: testSSHHostKey, SSHPort: fi.Int32(0)},
+			},
+			expected: []string{"Invalid value::spec.metal.hosts[0].sshPort"},
+		},
+		{
+			label: "invalid host key",
+			role:  kops.InstanceGroupRoleNode,
+			hosts: []kops.MetalHostSpec{
+				{Name: "node-1", Address: "192.168.1.11", SSHHostKey: "not a key"},
+			},
+			expected: []string{"Invalid value::spec.metal.hosts[0].sshHostKey"},
+		},
+		{
+			label: "size mismatch",
+			role:  kops.InstanceGroupRoleNode,
+			hosts: []kops.MetalHostSpec{
+				{Name: "node-1", Address: "192.168.1.11", SSHHostKey: testSSHHostKey},
+			},
+			size: fi.Int32(2),
+			expected: []string{
+				"Invalid value::spec.minSize",
+				"Invalid value::spec.maxSize",
+			},
+		},
+		{
+			label: "master with several hosts",
+			role:  kops.InstanceGroupRoleMaster,
+			hosts: []kops.MetalHostSpec{
+				{Name: "master-1", Address: "192.168.1.1", SSHHostKey: testSSHHostKey},
+				{Name: "master-2", Address: "192.168.1.2", SSHHostKey: testSSHHostKey},
+			},
+			expected: []string{"Invalid value::spec.metal.hosts"},
+		},
+	} {
+		ig := kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:    test.role,
+				Subnets: []string{"metal"},
+				MinSize: test.size,
+				MaxSize: test.size,
+				Metal: &kops.MetalSpec{
+					Hosts: test.hosts,
+				},
+			},
+		}
+		t.Run(test.label, func(t *testing.T) {
+			errs := ValidateInstanceGroup(&ig, nil)
+			testErrors(t, test.label, errs, test.expected)
+		})
+	}
+}
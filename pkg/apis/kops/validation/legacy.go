@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/dns"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/util/subnet"
 	"k8s.io/kops/upup/pkg/fi"
@@ -84,6 +85,16 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 	case kops.CloudProviderOpenstack:
 		requiresNetworkCIDR = false
 		requiresSubnetCIDR = false
+	case kops.CloudProviderHetzner:
+		if !dns.IsGossipHostname(c.ObjectMeta.Name) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), c.ObjectMeta.Name, "Hetzner clusters must use gossip DNS, the cluster name must end with .k8s.local"))
+		}
+	case kops.CloudProviderMetal:
+		requiresNetworkCIDR = false
+		requiresSubnetCIDR = false
+		if !dns.IsGossipHostname(c.ObjectMeta.Name) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), c.ObjectMeta.Name, "metal clusters must use gossip DNS, the cluster name must end with .k8s.local"))
+		}
 
 	default:
 		allErrs = append(allErrs, field.NotSupported(fieldSpec.Child("cloudProvider"), c.Spec.CloudProvider, []string{
@@ -93,6 +104,8 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 			string(kops.CloudProviderAzure),
 			string(kops.CloudProviderAWS),
 			string(kops.CloudProviderOpenstack),
+			string(kops.CloudProviderHetzner),
+			string(kops.CloudProviderMetal),
 		}))
 	}
 
@@ -214,6 +227,11 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 					allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("serviceClusterIPRange"), fmt.Sprintf("serviceClusterIPRange %q must be a subnet of nonMasqueradeCIDR %q", serviceClusterIPRangeString, c.Spec.NonMasqueradeCIDR)))
 				}
 
+				// On dual-stack and IPv6 clusters, the apiserver is configured with the ranges of all the IP families
+				if c.Spec.UsesIPv6() {
+					serviceClusterIPRangeString = strings.Join(c.Spec.ServiceClusterIPRanges(), ",")
+				}
+
 				if c.Spec.KubeAPIServer != nil && c.Spec.KubeAPIServer.ServiceClusterIPRange != serviceClusterIPRangeString {
 					if strict || c.Spec.KubeAPIServer.ServiceClusterIPRange != "" {
 						allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("kubeAPIServer", "serviceClusterIPRange"), "kubeAPIServer serviceClusterIPRange did not match cluster serviceClusterIPRange"))
@@ -224,13 +242,16 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 	}
 
 	// Check ClusterCIDR
-	if c.Spec.KubeControllerManager != nil {
-		var clusterCIDR *net.IPNet
-		clusterCIDRString := c.Spec.KubeControllerManager.ClusterCIDR
-		if clusterCIDRString != "" {
-			_, clusterCIDR, err = net.ParseCIDR(clusterCIDRString)
+	if c.Spec.KubeControllerManager != nil && c.Spec.KubeControllerManager.ClusterCIDR != "" {
+		// Dual-stack clusters have a comma-separated CIDR for each IP family
+		for _, clusterCIDRString := range strings.Split(c.Spec.KubeControllerManager.ClusterCIDR, ",") {
+			ip, clusterCIDR, err := net.ParseCIDR(clusterCIDRString)
 			if err != nil {
 				allErrs = append(allErrs, field.Invalid(fieldSpec.Child("kubeControllerManager", "clusterCIDR"), clusterCIDRString, "cluster had an invalid kubeControllerManager.clusterCIDR"))
+			} else if ip.To4() == nil {
+				if clusterCIDRString != c.Spec.PodIPv6CIDR {
+					allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("kubeControllerManager", "clusterCIDR"), fmt.Sprintf("kubeControllerManager.clusterCIDR %q did not match cluster podIPv6CIDR %q", clusterCIDRString, c.Spec.PodIPv6CIDR)))
+				}
 			} else if nonMasqueradeCIDR != nil && !subnet.BelongsTo(nonMasqueradeCIDR, clusterCIDR) {
 				allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("kubeControllerManager", "clusterCIDR"), fmt.Sprintf("kubeControllerManager.clusterCIDR %q must be a subnet of nonMasqueradeCIDR %q", clusterCIDRString, c.Spec.NonMasqueradeCIDR)))
 			}
@@ -245,7 +266,11 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 			if ip == nil {
 				allErrs = append(allErrs, field.Invalid(fieldSpec.Child("kubeDNS", "serverIP"), address, "Cluster had an invalid kubeDNS.serverIP"))
 			} else {
-				if serviceClusterIPRange != nil && !serviceClusterIPRange.Contains(ip) {
+				if ip.To4() == nil {
+					if _, serviceClusterIPv6Range, err := net.ParseCIDR(c.Spec.ServiceClusterIPv6Range); err == nil && !serviceClusterIPv6Range.Contains(ip) {
+						allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("kubeDNS", "serverIP"), fmt.Sprintf("ServiceClusterIPv6Range %q must contain the DNS Server IP %q", c.Spec.ServiceClusterIPv6Range, address)))
+					}
+				} else if serviceClusterIPRange != nil && !serviceClusterIPRange.Contains(ip) {
 					allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("kubeDNS", "serverIP"), fmt.Sprintf("ServiceClusterIPRange %q must contain the DNS Server IP %q", c.Spec.ServiceClusterIPRange, address)))
 				}
 				if !featureflag.ExperimentalClusterDNS.Enabled() {
@@ -305,6 +330,11 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 			k8sCloudProvider = "alicloud"
 		case kops.CloudProviderAzure:
 			k8sCloudProvider = "azure"
+		case kops.CloudProviderHetzner:
+			k8sCloudProvider = "external"
+		case kops.CloudProviderMetal:
+			// Pre-provisioned hosts have no cloud provider integration
+			k8sCloudProvider = ""
 		default:
 			// We already added an error above
 			k8sCloudProvider = "ignore"
@@ -430,6 +460,20 @@ func DeepValidate(c *kops.Cluster, groups []*kops.InstanceGroup, strict bool, cl
 		}
 	}
 
+	// The hostnames of pre-provisioned hosts are their node names, so they must be unique across the cluster
+	hostnames := make(map[string]string)
+	for _, g := range groups {
+		if g.Spec.Metal == nil {
+			continue
+		}
+		for _, host := range g.Spec.Metal.Hosts {
+			if other, found := hostnames[host.Name]; found {
+				return fmt.Errorf("metal host %q is listed in both InstanceGroups %q and %q", host.Name, other, g.ObjectMeta.Name)
+			}
+			hostnames[host.Name] = g.ObjectMeta.Name
+		}
+	}
+
 	return nil
 }
 
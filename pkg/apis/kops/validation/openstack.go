@@ -17,6 +17,9 @@ limitations under the License.
 package validation
 
 import (
+	"fmt"
+	"net"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
 )
@@ -34,5 +37,45 @@ func openstackValidateCluster(c *kops.Cluster) (errList field.ErrorList) {
 			errList = append(errList, field.Forbidden(field.NewPath("spec", "topology", "masters"), "Public topology requires an external network"))
 		}
 	}
+	if c.Spec.API != nil && c.Spec.API.LoadBalancer != nil {
+		errList = append(errList, openstackValidateLoadBalancer(field.NewPath("spec", "api", "loadBalancer"), c.Spec.API.LoadBalancer)...)
+	}
+	return errList
+}
+
+// openstackValidateLoadBalancer checks the health monitor and floating IP of the API load balancer
+func openstackValidateLoadBalancer(fieldPath *field.Path, lbSpec *kops.LoadBalancerAccessSpec) (errList field.ErrorList) {
+	if lbSpec.FloatingIP != "" {
+		if ip := net.ParseIP(lbSpec.FloatingIP); ip == nil || ip.To4() == nil {
+			errList = append(errList, field.Invalid(fieldPath.Child("floatingIP"), lbSpec.FloatingIP, "must be an IPv4 address"))
+		}
+	}
+
+	monitor := lbSpec.HealthMonitor
+	if monitor == nil {
+		return errList
+	}
+	fieldPath = fieldPath.Child("healthMonitor")
+	if monitor.DelaySeconds < 0 {
+		errList = append(errList, field.Invalid(fieldPath.Child("delaySeconds"), monitor.DelaySeconds, "must not be negative"))
+	}
+	if monitor.TimeoutSeconds < 0 {
+		errList = append(errList, field.Invalid(fieldPath.Child("timeoutSeconds"), monitor.TimeoutSeconds, "must not be negative"))
+	} else {
+		// Octavia requires the timeout to be less than the delay, so compare them including their defaults
+		delay, timeout := monitor.DelaySeconds, monitor.TimeoutSeconds
+		if delay == 0 {
+			delay = 10
+		}
+		if timeout == 0 {
+			timeout = 5
+		}
+		if delay > 0 && timeout >= delay {
+			errList = append(errList, field.Invalid(fieldPath.Child("timeoutSeconds"), timeout, fmt.Sprintf("must be less than delaySeconds (%d)", delay)))
+		}
+	}
+	if monitor.MaxRetries != nil && (*monitor.MaxRetries < 1 || *monitor.MaxRetries > 10) {
+		errList = append(errList, field.Invalid(fieldPath.Child("maxRetries"), *monitor.MaxRetries, "must be between 1 and 10"))
+	}
 	return errList
 }
@@ -19,6 +19,7 @@ package validation
 import (
 	"testing"
 
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/upup/pkg/fi"
 
 	"k8s.io/kops/pkg/apis/kops"
@@ -88,3 +89,61 @@ func Test_ValidateTopology(t *testing.T) {
 		testErrors(t, g.Input, errs, g.ExpectedErrors)
 	}
 }
+
+func TestOpenstackValidateLoadBalancer(t *testing.T) {
+	tests := []struct {
+		lbSpec   kops.LoadBalancerAccessSpec
+		expected []string
+	}{
+		{
+			lbSpec: kops.LoadBalancerAccessSpec{},
+		},
+		{
+			lbSpec: kops.LoadBalancerAccessSpec{
+				FloatingIP:    "192.0.2.10",
+				HealthMonitor: &kops.LoadBalancerHealthMonitorSpec{},
+			},
+		},
+		{
+			lbSpec: kops.LoadBalancerAccessSpec{
+				HealthMonitor: &kops.LoadBalancerHealthMonitorSpec{DelaySeconds: 5, TimeoutSeconds: 3, MaxRetries: fi.Int(10)},
+			},
+		},
+		{
+			lbSpec:   kops.LoadBalancerAccessSpec{FloatingIP: "2001:db8::1"},
+			expected: []string{"Invalid value::spec.api.loadBalancer.floatingIP"},
+		},
+		{
+			lbSpec: kops.LoadBalancerAccessSpec{
+				HealthMonitor: &kops.LoadBalancerHealthMonitorSpec{DelaySeconds: 5, TimeoutSeconds: 5, MaxRetries: fi.Int(11)},
+			},
+			expected: []string{
+				"Invalid value::spec.api.loadBalancer.healthMonitor.timeoutSeconds",
+				"Invalid value::spec.api.loadBalancer.healthMonitor.maxRetries",
+			},
+		},
+		{
+			lbSpec: kops.LoadBalancerAccessSpec{
+				HealthMonitor: &kops.LoadBalancerHealthMonitorSpec{MaxRetries: fi.Int(0)},
+			},
+			expected: []string{"Invalid value::spec.api.loadBalancer.healthMonitor.maxRetries"},
+		},
+		{
+			lbSpec: kops.LoadBalancerAccessSpec{
+				HealthMonitor: &kops.LoadBalancerHealthMonitorSpec{DelaySeconds: -1},
+			},
+			expected: []string{"Invalid value::spec.api.loadBalancer.healthMonitor.delaySeconds"},
+		},
+		{
+			lbSpec: kops.LoadBalancerAccessSpec{
+				HealthMonitor: &kops.LoadBalancerHealthMonitorSpec{DelaySeconds: 4},
+			},
+			expected: []string{"Invalid value::spec.api.loadBalancer.healthMonitor.timeoutSeconds"},
+		},
+	}
+
+	for _, test := range tests {
+		errs := openstackValidateLoadBalancer(field.NewPath("spec", "api", "loadBalancer"), &test.lbSpec)
+		testErrors(t, test.lbSpec, errs, test.expected)
+	}
+}
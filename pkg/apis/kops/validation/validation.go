@@ -38,6 +38,7 @@ import (
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
+	utilsubnet "k8s.io/kops/pkg/util/subnet"
 	"k8s.io/kops/upup/pkg/fi"
 )
 
@@ -100,10 +101,24 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		allErrs = append(allErrs, validateCIDR(cidr, fieldPath.Child("additionalNetworkCIDRs").Index(i))...)
 	}
 
+	if len(spec.VPCEndpoints) > 0 && kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("vpcEndpoints"), "VPC endpoints are only supported on AWS"))
+	}
+
+	if spec.FlowLogs != nil && kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("flowLogs"), "flow logs are only supported on AWS"))
+	}
+
+	if spec.API != nil && spec.API.LoadBalancer != nil && spec.API.LoadBalancer.AccessLog != nil && kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("api", "loadBalancer", "accessLog"), "load balancer access logs are only supported on AWS"))
+	}
+
 	if spec.Topology != nil {
 		allErrs = append(allErrs, validateTopology(spec.Topology, fieldPath.Child("topology"))...)
 	}
 
+	allErrs = append(allErrs, validateIPFamilies(c, spec, fieldPath)...)
+
 	// UpdatePolicy
 	allErrs = append(allErrs, IsValidValue(fieldPath.Child("updatePolicy"), spec.UpdatePolicy, []string{kops.UpdatePolicyAutomatic, kops.UpdatePolicyExternal})...)
 
@@ -270,6 +285,108 @@ func validateCIDR(cidr string, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+func validateIPv6CIDR(cidr string, fieldPath *field.Path) field.ErrorList {
+	allErrs := validateCIDR(cidr, fieldPath)
+	if len(allErrs) == 0 {
+		ip, _, _ := net.ParseCIDR(cidr)
+		if ip.To4() != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath, cidr, "Must be an IPv6 CIDR"))
+		}
+	}
+	return allErrs
+}
+
+func validateIPFamilies(c *kops.Cluster, spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	families := sets.NewString()
+	for i, family := range spec.IPFamilies {
+		value := string(family)
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("ipFamilies").Index(i), &value, []string{string(kops.IPFamilyIPv4), string(kops.IPFamilyIPv6)})...)
+		if families.Has(value) {
+			allErrs = append(allErrs, field.Duplicate(fieldPath.Child("ipFamilies").Index(i), value))
+		}
+		families.Insert(value)
+	}
+
+	if !spec.UsesIPv6() {
+		if spec.NetworkIPv6CIDR != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("networkIPv6CIDR"), "networkIPv6CIDR requires ipFamilies to include IPv6"))
+		}
+		if spec.PodIPv6CIDR != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("podIPv6CIDR"), "podIPv6CIDR requires ipFamilies to include IPv6"))
+		}
+		if spec.ServiceClusterIPv6Range != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("serviceClusterIPv6Range"), "serviceClusterIPv6Range requires ipFamilies to include IPv6"))
+		}
+		for i, subnet := range spec.Subnets {
+			if subnet.IPv6CIDR != "" {
+				allErrs = append(allErrs, field.Forbidden(fieldPath.Child("subnets").Index(i).Child("ipv6CIDR"), "ipv6CIDR requires ipFamilies to include IPv6"))
+			}
+		}
+		return allErrs
+	}
+
+	if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("ipFamilies"), "IPv6 is only supported on AWS"))
+	}
+
+	if spec.KubernetesVersion != "" && c.IsKubernetesLT("1.20") {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("ipFamilies"), "IPv6 requires Kubernetes 1.20 or later"))
+	}
+
+	if spec.Networking != nil && spec.Networking.Calico == nil && spec.Networking.CNI == nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("networking"), "IPv6 is only supported with calico or cni networking"))
+	}
+
+	if spec.NetworkIPv6CIDR != "" {
+		allErrs = append(allErrs, validateIPv6CIDR(spec.NetworkIPv6CIDR, fieldPath.Child("networkIPv6CIDR"))...)
+		if kops.CloudProviderID(spec.CloudProvider) == kops.CloudProviderAWS && spec.NetworkID == "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("networkIPv6CIDR"), "networkIPv6CIDR can only be set for a shared VPC; VPCs created by kops are assigned an Amazon-provided IPv6 CIDR"))
+		}
+	}
+
+	if spec.PodIPv6CIDR != "" {
+		allErrs = append(allErrs, validateIPv6CIDR(spec.PodIPv6CIDR, fieldPath.Child("podIPv6CIDR"))...)
+	}
+
+	if spec.ServiceClusterIPv6Range != "" {
+		errs := validateIPv6CIDR(spec.ServiceClusterIPv6Range, fieldPath.Child("serviceClusterIPv6Range"))
+		if len(errs) == 0 {
+			// kube-apiserver limits the size of the service range, so that it can track allocations in a bitmap
+			_, cidr, _ := net.ParseCIDR(spec.ServiceClusterIPv6Range)
+			if ones, _ := cidr.Mask.Size(); ones < 108 {
+				errs = append(errs, field.Invalid(fieldPath.Child("serviceClusterIPv6Range"), spec.ServiceClusterIPv6Range, "IPv6 service range must be /108 or smaller"))
+			}
+		}
+		allErrs = append(allErrs, errs...)
+	}
+
+	if spec.PodIPv6CIDR != "" && spec.ServiceClusterIPv6Range != "" {
+		_, podCIDR, err1 := net.ParseCIDR(spec.PodIPv6CIDR)
+		_, serviceCIDR, err2 := net.ParseCIDR(spec.ServiceClusterIPv6Range)
+		if err1 == nil && err2 == nil && utilsubnet.Overlap(podCIDR, serviceCIDR) {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("serviceClusterIPv6Range"), fmt.Sprintf("serviceClusterIPv6Range %q cannot overlap with podIPv6CIDR %q", spec.ServiceClusterIPv6Range, spec.PodIPv6CIDR)))
+		}
+	}
+
+	if spec.IsIPv6Only() {
+		// Private subnets only have IPv4 egress, through the NAT gateways
+		for i, subnet := range spec.Subnets {
+			if subnet.Type == kops.SubnetTypePrivate {
+				allErrs = append(allErrs, field.Forbidden(fieldPath.Child("subnets").Index(i).Child("type"), "IPv6-only clusters do not support private subnets"))
+			}
+		}
+	}
+
+	if spec.IsDualStack() && spec.KubeControllerManager != nil && spec.KubeControllerManager.NodeCIDRMaskSize != nil {
+		// kube-controller-manager only accepts the per-family mask sizes on dual-stack clusters
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("kubeControllerManager", "nodeCIDRMaskSize"), "nodeCIDRMaskSize cannot be set on dual-stack clusters"))
+	}
+
+	return allErrs
+}
+
 func validateTopology(topology *kops.TopologySpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -357,6 +474,19 @@ func validateSubnet(subnet *kops.ClusterSubnetSpec, fieldPath *field.Path) field
 		allErrs = append(allErrs, validateCIDR(subnet.CIDR, fieldPath.Child("cidr"))...)
 	}
 
+	// IPv6CIDR
+	if subnet.IPv6CIDR != "" {
+		if utilsubnet.IsSubnetIndex(subnet.IPv6CIDR) {
+			if prefixLength, _, err := utilsubnet.ParseSubnetIndex(subnet.IPv6CIDR); err != nil {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("ipv6CIDR"), subnet.IPv6CIDR, err.Error()))
+			} else if prefixLength != 64 {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("ipv6CIDR"), subnet.IPv6CIDR, "IPv6 subnets must be /64"))
+			}
+		} else {
+			allErrs = append(allErrs, validateIPv6CIDR(subnet.IPv6CIDR, fieldPath.Child("ipv6CIDR"))...)
+		}
+	}
+
 	if subnet.Egress != "" {
 		egressType := strings.Split(subnet.Egress, "-")[0]
 		if egressType != kops.EgressNatGateway && egressType != kops.EgressElasticIP && egressType != kops.EgressNatInstance && egressType != kops.EgressExternal && egressType != kops.EgressTransitGateway {
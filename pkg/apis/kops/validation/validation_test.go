@@ -163,6 +163,76 @@ func TestValidateSubnets(t *testing.T) {
 	}
 }
 
+func TestValidateIPFamilies(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				IPFamilies:    []kops.IPFamily{kops.IPFamilyIPv4, kops.IPFamilyIPv6},
+				PodIPv6CIDR:   "fd00:10:244::/48",
+				Networking:    &kops.NetworkingSpec{Calico: &kops.CalicoNetworkingSpec{}},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				PodIPv6CIDR:   "fd00:10:244::/48",
+			},
+			ExpectedErrors: []string{"Forbidden::spec.podIPv6CIDR"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				IPFamilies:    []kops.IPFamily{kops.IPFamilyIPv6, kops.IPFamilyIPv6, "IPv5"},
+			},
+			ExpectedErrors: []string{"Duplicate value::spec.ipFamilies[1]", "Unsupported value::spec.ipFamilies[2]"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				IPFamilies:    []kops.IPFamily{kops.IPFamilyIPv6},
+				Networking:    &kops.NetworkingSpec{Kubenet: &kops.KubenetNetworkingSpec{}},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.ipFamilies", "Forbidden::spec.networking"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider:   "aws",
+				IPFamilies:      []kops.IPFamily{kops.IPFamilyIPv4, kops.IPFamilyIPv6},
+				NetworkIPv6CIDR: "2001:db8::/56",
+			},
+			ExpectedErrors: []string{"Forbidden::spec.networkIPv6CIDR"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider:           "aws",
+				IPFamilies:              []kops.IPFamily{kops.IPFamilyIPv6},
+				PodIPv6CIDR:             "fd00:10::/32",
+				ServiceClusterIPv6Range: "fd00:10:96::/64",
+			},
+			ExpectedErrors: []string{"Invalid value::spec.serviceClusterIPv6Range", "Forbidden::spec.serviceClusterIPv6Range"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				IPFamilies:    []kops.IPFamily{kops.IPFamilyIPv6},
+				Subnets: []kops.ClusterSubnetSpec{
+					{Name: "private", Type: kops.SubnetTypePrivate},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.subnets[0].type"},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{Spec: g.Input}
+		errs := validateIPFamilies(cluster, &cluster.Spec, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
 func TestValidateKubeAPIServer(t *testing.T) {
 	str := "foobar"
 	authzMode := "RBAC,Webhook"
@@ -47,6 +47,8 @@ type Config struct {
 	Channels []string `json:"channels,omitempty"`
 	// ApiserverAdditionalIPs are additional IP address to put in the apiserver server cert.
 	ApiserverAdditionalIPs []string `json:",omitempty"`
+	// GossipSeeds are static addresses of gossip peers, for clouds where protokube cannot discover them.
+	GossipSeeds []string `json:",omitempty"`
 
 	// Manifests for running etcd
 	EtcdManifests []string `json:"etcdManifests,omitempty"`
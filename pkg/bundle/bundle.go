@@ -0,0 +1,376 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle collects the files and container images that a cluster uses into a single tarball,
+// so that they can be carried into an environment without internet access and imported into
+// the file repository and container registry configured in the cluster's spec.assets.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/mirrors"
+	"k8s.io/kops/util/pkg/ociregistry"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ManifestFile is the path of the manifest within the bundle
+	ManifestFile = "bundle.yaml"
+
+	filesDir  = "files"
+	imagesDir = "images"
+)
+
+// Manifest lists the contents of a bundle
+type Manifest struct {
+	// Files are the files in the bundle
+	Files []*File `json:"files,omitempty"`
+	// Images are the container images in the bundle
+	Images []*Image `json:"images,omitempty"`
+}
+
+// File is a file in the bundle
+type File struct {
+	// URL is the canonical location of the file
+	URL string `json:"url"`
+	// Hash is the hex-encoded SHA of the file
+	Hash string `json:"hash"`
+	// Path is the location of the file within the bundle
+	Path string `json:"path"`
+}
+
+// Image is a container image in the bundle
+type Image struct {
+	// Image is the canonical name of the image; the image is recorded under this name in the bundle's OCI image layout
+	Image string `json:"image"`
+}
+
+// Create downloads the files and container images, verifying the files against their hashes,
+// and writes them to w as a tar archive.  Images are stored in the OCI image layout format.
+func Create(ctx context.Context, w io.Writer, fileAssets []*assets.FileAsset, containerAssets []*assets.ContainerAsset, registry *ociregistry.Client) (*Manifest, error) {
+	dir, err := ioutil.TempDir("", "kops-bundle")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := &Manifest{}
+
+	seenFiles := make(map[string]bool)
+	for _, asset := range fileAssets {
+		u := asset.CanonicalURL
+		if u == nil {
+			u = asset.DownloadURL
+		}
+		if u == nil || seenFiles[u.String()] {
+			continue
+		}
+		seenFiles[u.String()] = true
+
+		file := &File{
+			URL:  u.String(),
+			Hash: asset.SHAValue,
+			Path: path.Join(filesDir, u.Host, u.Path),
+		}
+		if err := downloadFile(file, filepath.Join(dir, filepath.FromSlash(file.Path))); err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, file)
+	}
+
+	seenImages := make(map[string]bool)
+	var layout *ociregistry.Layout
+	for _, asset := range containerAssets {
+		image := asset.CanonicalLocation
+		if image == "" {
+			image = asset.DockerImage
+		}
+		if seenImages[image] {
+			continue
+		}
+		seenImages[image] = true
+
+		if layout == nil {
+			layout, err = ociregistry.OpenLayout(filepath.Join(dir, imagesDir))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		klog.Infof("Pulling image %q", image)
+		if err := registry.Pull(ctx, image, layout); err != nil {
+			return nil, fmt.Errorf("error pulling image %q: %v", image, err)
+		}
+		manifest.Images = append(manifest.Images, &Image{Image: image})
+	}
+
+	sort.Slice(manifest.Files, func(i, j int) bool { return manifest.Files[i].URL < manifest.Files[j].URL })
+	sort.Slice(manifest.Images, func(i, j int) bool { return manifest.Images[i].Image < manifest.Images[j].Image })
+
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing bundle manifest: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ManifestFile), data, 0644); err != nil {
+		return nil, err
+	}
+
+	if err := writeTar(w, dir); err != nil {
+		return nil, fmt.Errorf("error writing bundle: %v", err)
+	}
+	return manifest, nil
+}
+
+// downloadFile downloads a file to dest, trying each of its mirrors, and verifies it against its hash
+func downloadFile(file *File, dest string) error {
+	hash, err := hashing.FromString(file.Hash)
+	if err != nil {
+		return fmt.Errorf("unable to parse hash for %q: %v", file.URL, err)
+	}
+
+	var lastErr error
+	for _, mirror := range mirrors.FindUrlMirrors(file.URL) {
+		if _, err := fi.DownloadURL(mirror, dest, hash); err != nil {
+			klog.Warningf("error downloading %q: %v", mirror, err)
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("unable to download %q: %v", file.URL, lastErr)
+}
+
+// ImportOptions configures where the contents of a bundle are imported to
+type ImportOptions struct {
+	// FileRepository is the path that files are uploaded to; each file is written under the path of its canonical URL.
+	FileRepository vfs.Path
+	// ContainerRegistry is the registry that images are pushed to, named as kops names them for spec.assets.containerRegistry.
+	ContainerRegistry string
+}
+
+// Import reads a bundle from r, and uploads its files to the file repository and its images to the container registry
+func Import(ctx context.Context, r io.Reader, options *ImportOptions, registry *ociregistry.Client) (*Manifest, error) {
+	dir, err := ioutil.TempDir("", "kops-bundle")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := readTar(r, dir); err != nil {
+		return nil, fmt.Errorf("error reading bundle: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle manifest: %v", err)
+	}
+	manifest := &Manifest{}
+	if err := yaml.UnmarshalStrict(data, manifest); err != nil {
+		return nil, fmt.Errorf("error parsing bundle manifest: %v", err)
+	}
+
+	if len(manifest.Files) != 0 && options.FileRepository == nil {
+		return nil, fmt.Errorf("the bundle contains files, but no file repository was specified")
+	}
+	if len(manifest.Images) != 0 && options.ContainerRegistry == "" {
+		return nil, fmt.Errorf("the bundle contains images, but no container registry was specified")
+	}
+
+	for _, file := range manifest.Files {
+		if err := importFile(dir, file, options.FileRepository); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(manifest.Images) != 0 {
+		layout, err := ociregistry.OpenLayout(filepath.Join(dir, imagesDir))
+		if err != nil {
+			return nil, err
+		}
+
+		// Name the images the same way as the cluster will when spec.assets.containerRegistry is set
+		assetBuilder := &assets.AssetBuilder{
+			AssetsLocation: &kops.Assets{ContainerRegistry: fi.String(options.ContainerRegistry)},
+		}
+		for _, image := range manifest.Images {
+			target, err := assetBuilder.RemapImage(image.Image)
+			if err != nil {
+				return nil, err
+			}
+			klog.Infof("Pushing image %q to %q", image.Image, target)
+			if err := registry.Push(ctx, layout, image.Image, target); err != nil {
+				return nil, fmt.Errorf("error pushing image %q: %v", target, err)
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+// importFile verifies a file from the bundle and uploads it to the file repository,
+// along with a hash file, matching the layout that kops expects of spec.assets.fileRepository
+func importFile(dir string, file *File, fileRepository vfs.Path) error {
+	hash, err := hashing.FromString(file.Hash)
+	if err != nil {
+		return fmt.Errorf("unable to parse hash for %q: %v", file.URL, err)
+	}
+	u, err := url.Parse(file.URL)
+	if err != nil {
+		return fmt.Errorf("unable to parse %q: %v", file.URL, err)
+	}
+
+	p, err := localPath(dir, file.Path)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return fmt.Errorf("error reading %q from bundle: %v", file.Path, err)
+	}
+	actual, err := hash.Algorithm.Hash(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if !actual.Equal(hash) {
+		return fmt.Errorf("%q in bundle has hash %s, expected %s", file.Path, actual.Hex(), hash.Hex())
+	}
+
+	target := fileRepository.Join(strings.TrimPrefix(u.Path, "/"))
+	klog.Infof("Uploading %q to %q", file.URL, target)
+	if err := target.WriteFile(bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("error writing %q: %v", target, err)
+	}
+
+	var extension string
+	switch hash.Algorithm {
+	case hashing.HashAlgorithmSHA256:
+		extension = ".sha256"
+	case hashing.HashAlgorithmSHA1:
+		extension = ".sha1"
+	default:
+		return fmt.Errorf("unhandled hash algorithm %q for %q", hash.Algorithm, file.URL)
+	}
+	hashFile := fileRepository.Join(strings.TrimPrefix(u.Path, "/") + extension)
+	if err := hashFile.WriteFile(strings.NewReader(hash.Hex()), nil); err != nil {
+		return fmt.Errorf("error writing %q: %v", hashFile, err)
+	}
+	return nil
+}
+
+// writeTar writes the regular files under dir to w as a tar archive
+func writeTar(w io.Writer, dir string) error {
+	tw := tar.NewWriter(w)
+
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		header := &tar.Header{
+			Name:    filepath.ToSlash(rel),
+			Mode:    0644,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// readTar extracts the regular files in the tar archive read from r into dir
+func readTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		p, err := localPath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return err
+		}
+		f, err := os.Create(p)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+}
+
+// localPath returns where a path within the bundle is extracted to under dir, rejecting paths that would escape dir
+func localPath(dir string, name string) (string, error) {
+	clean := path.Clean(name)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid path %q in bundle", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
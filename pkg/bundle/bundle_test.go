@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func newFileServer(files map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, found := files[r.URL.Path]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(data))
+	}))
+}
+
+func fileAsset(t *testing.T, server *httptest.Server, p string, data string) *assets.FileAsset {
+	u, err := url.Parse(server.URL + p)
+	if err != nil {
+		t.Fatalf("error parsing url: %v", err)
+	}
+	hash, err := hashing.HashAlgorithmSHA256.Hash(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("error hashing: %v", err)
+	}
+	return &assets.FileAsset{DownloadURL: u, SHAValue: hash.Hex()}
+}
+
+func TestCreateAndImportFiles(t *testing.T) {
+	ctx := context.TODO()
+
+	files := map[string]string{
+		"/release/v1.21.0/bin/linux/amd64/kubelet": "kubelet-amd64",
+		"/release/v1.21.0/bin/linux/arm64/kubelet": "kubelet-arm64",
+	}
+	server := newFileServer(files)
+	defer server.Close()
+
+	var fileAssets []*assets.FileAsset
+	for p, data := range files {
+		fileAssets = append(fileAssets, fileAsset(t, server, p, data))
+	}
+	// Duplicates are only included once
+	fileAssets = append(fileAssets, fileAssets[0])
+
+	var bundle bytes.Buffer
+	manifest, err := Create(ctx, &bundle, fileAssets, nil, nil)
+	if err != nil {
+		t.Fatalf("error creating bundle: %v", err)
+	}
+	if len(manifest.Files) != 2 {
+		t.Fatalf("expected 2 files in bundle, got %d", len(manifest.Files))
+	}
+
+	vfs.Context.ResetMemfsContext(true)
+	repository, err := vfs.Context.BuildVfsPath("memfs://assets/kops")
+	if err != nil {
+		t.Fatalf("error building path: %v", err)
+	}
+
+	if _, err := Import(ctx, bytes.NewReader(bundle.Bytes()), &ImportOptions{}, nil); err == nil {
+		t.Errorf("expected error importing files without a file repository")
+	}
+
+	imported, err := Import(ctx, bytes.NewReader(bundle.Bytes()), &ImportOptions{FileRepository: repository}, nil)
+	if err != nil {
+		t.Fatalf("error importing bundle: %v", err)
+	}
+	if len(imported.Files) != 2 {
+		t.Fatalf("expected 2 files to be imported, got %d", len(imported.Files))
+	}
+
+	for p, data := range files {
+		actual, err := repository.Join(strings.TrimPrefix(p, "/")).ReadFile()
+		if err != nil {
+			t.Fatalf("error reading imported file %q: %v", p, err)
+		}
+		if string(actual) != data {
+			t.Errorf("expected %q to contain %q, got %q", p, data, actual)
+		}
+
+		hash, err := repository.Join(strings.TrimPrefix(p, "/") + ".sha256").ReadFile()
+		if err != nil {
+			t.Fatalf("error reading imported hash file for %q: %v", p, err)
+		}
+		expected, _ := hashing.HashAlgorithmSHA256.Hash(strings.NewReader(data))
+		if string(hash) != expected.Hex() {
+			t.Errorf("expected hash %q for %q, got %q", expected.Hex(), p, hash)
+		}
+	}
+}
+
+func TestCreateVerifiesHash(t *testing.T) {
+	server := newFileServer(map[string]string{
+		"/release/v1.21.0/bin/linux/amd64/kubelet": "tampered",
+	})
+	defer server.Close()
+
+	asset := fileAsset(t, server, "/release/v1.21.0/bin/linux/amd64/kubelet", "kubelet-amd64")
+
+	var bundle bytes.Buffer
+	if _, err := Create(context.TODO(), &bundle, []*assets.FileAsset{asset}, nil, nil); err == nil {
+		t.Fatalf("expected error creating bundle with a file that does not match its hash")
+	}
+}
+
+func TestLocalPath(t *testing.T) {
+	for _, name := range []string{"../etc/passwd", "/etc/passwd", "files/../../etc/passwd"} {
+		if _, err := localPath("/tmp/bundle", name); err == nil {
+			t.Errorf("expected error for path %q", name)
+		}
+	}
+	p, err := localPath("/tmp/bundle", "files/./example.com/kubelet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p != "/tmp/bundle/files/example.com/kubelet" {
+		t.Errorf("unexpected path %q", p)
+	}
+}
@@ -143,7 +143,7 @@ func DeleteAllClusterState(basePath vfs.Path) error {
 			continue
 		}
 
-		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == registry.PathKopsVersionUpdated {
+		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == registry.PathKopsVersionUpdated || relativePath == registry.PathLock {
 			continue
 		}
 		if strings.HasPrefix(relativePath, "addons/") {
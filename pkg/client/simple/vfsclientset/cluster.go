@@ -134,6 +134,10 @@ func (r *ClusterVFS) Update(c *api.Cluster, status *api.ClusterStatus) (*api.Clu
 		return nil, errors.NewNotFound(schema.GroupResource{Group: api.GroupName, Resource: "Cluster"}, clusterName)
 	}
 
+	if c.ResourceVersion != "" && c.ResourceVersion != old.ResourceVersion {
+		return nil, errors.NewConflict(schema.GroupResource{Group: api.GroupName, Resource: "Cluster"}, clusterName, fmt.Errorf(conflictMessage))
+	}
+
 	if err := validation.ValidateClusterUpdate(c, status, old).ToAggregate(); err != nil {
 		return nil, err
 	}
@@ -143,7 +147,7 @@ func (r *ClusterVFS) Update(c *api.Cluster, status *api.ClusterStatus) (*api.Clu
 	}
 
 	if err := r.writeConfig(c, r.basePath.Join(clusterName, registry.PathCluster), c, vfs.WriteOptionOnlyIfExists); err != nil {
-		if os.IsNotExist(err) {
+		if os.IsNotExist(err) || errors.IsConflict(err) {
 			return nil, err
 		}
 		return nil, fmt.Errorf("error writing Cluster: %v", err)
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestConcurrentUpdatesConflict(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	basePath, err := vfs.Context.BuildVfsPath("memfs://state-store")
+	if err != nil {
+		t.Fatalf("error building path: %v", err)
+	}
+	clientset := NewVFSClientset(basePath)
+
+	cluster := testutils.BuildMinimalCluster("minimal.example.com")
+	cluster.Spec.ConfigBase = "memfs://state-store/minimal.example.com"
+	if _, err := clientset.CreateCluster(ctx, cluster); err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+
+	first, err := clientset.GetCluster(ctx, cluster.Name)
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+	second, err := clientset.GetCluster(ctx, cluster.Name)
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+	if first.ResourceVersion == "" {
+		t.Fatalf("expected cluster to have a resourceVersion")
+	}
+
+	first.Spec.KubernetesVersion = "1.15.0"
+	updated, err := clientset.UpdateCluster(ctx, first, nil)
+	if err != nil {
+		t.Fatalf("error updating cluster: %v", err)
+	}
+
+	second.Spec.KubernetesVersion = "1.16.0"
+	if _, err := clientset.UpdateCluster(ctx, second, nil); !errors.IsConflict(err) {
+		t.Errorf("expected conflict updating stale cluster, got %v", err)
+	}
+
+	// The version returned by the update can be used for the next update
+	updated.Spec.KubernetesVersion = "1.17.0"
+	if _, err := clientset.UpdateCluster(ctx, updated, nil); err != nil {
+		t.Errorf("error updating cluster at returned version: %v", err)
+	}
+
+	data, err := basePath.Join(cluster.Name, "config").ReadFile()
+	if err != nil {
+		t.Fatalf("error reading cluster config: %v", err)
+	}
+	if bytes.Contains(data, []byte("resourceVersion")) {
+		t.Errorf("resourceVersion should not be persisted in the state store:\n%s", data)
+	}
+
+	// Objects without a resourceVersion are written unconditionally
+	second.ResourceVersion = ""
+	if _, err := clientset.UpdateCluster(ctx, second, nil); err != nil {
+		t.Errorf("error updating cluster without resourceVersion: %v", err)
+	}
+
+	ig := testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-mock-1a")
+	igs := clientset.InstanceGroupsFor(cluster)
+	if _, err := igs.Create(ctx, &ig, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+	firstIG, err := igs.Get(ctx, ig.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error reading instance group: %v", err)
+	}
+	secondIG := firstIG.DeepCopy()
+
+	firstIG.Spec.MachineType = "m5.large"
+	if _, err := igs.Update(ctx, firstIG, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error updating instance group: %v", err)
+	}
+	secondIG.Spec.MachineType = "m5.xlarge"
+	if _, err := igs.Update(ctx, secondIG, metav1.UpdateOptions{}); !errors.IsConflict(err) {
+		t.Errorf("expected conflict updating stale instance group, got %v", err)
+	}
+}
@@ -25,9 +25,11 @@ import (
 	"sort"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
@@ -113,8 +115,10 @@ func (c *commonVFS) serialize(o runtime.Object) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// readConfig reads and decodes the object stored at configPath.
+// The version of the file is recorded as the resourceVersion of the object, so that later updates can detect concurrent changes.
 func (c *commonVFS) readConfig(configPath vfs.Path) (runtime.Object, error) {
-	data, err := configPath.ReadFile()
+	data, version, err := configPath.ReadFileWithVersion()
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, err
@@ -126,21 +130,54 @@ func (c *commonVFS) readConfig(configPath vfs.Path) (runtime.Object, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error parsing %s: %v", configPath, err)
 	}
+
+	objectMeta, err := meta.Accessor(object)
+	if err != nil {
+		return nil, err
+	}
+	objectMeta.SetResourceVersion(version)
+
 	return object, nil
 }
 
+// writeConfig serializes and writes the object to configPath.
+// When updating (WriteOptionOnlyIfExists) an object that carries a resourceVersion, the write only succeeds
+// if the file has not been changed since that version was read; otherwise a Conflict error is returned.
 func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o runtime.Object, writeOptions ...vfs.WriteOption) error {
-	data, err := c.serialize(o)
+	objectMeta, err := meta.Accessor(o)
+	if err != nil {
+		return err
+	}
+
+	// The resourceVersion is the version of the file in the state store, so we don't store it in the file itself
+	version := objectMeta.GetResourceVersion()
+	stored := o
+	if version != "" {
+		stored = o.DeepCopyObject()
+		storedMeta, err := meta.Accessor(stored)
+		if err != nil {
+			return err
+		}
+		storedMeta.SetResourceVersion("")
+	}
+
+	data, err := c.serialize(stored)
 	if err != nil {
 		return fmt.Errorf("error marshaling object: %v", err)
 	}
 
 	create := false
+	conditional := false
 	for _, writeOption := range writeOptions {
 		switch writeOption {
 		case vfs.WriteOptionCreate:
 			create = true
 		case vfs.WriteOptionOnlyIfExists:
+			if version != "" {
+				// The conditional write fails if the file does not exist
+				conditional = true
+				continue
+			}
 			_, err = configPath.ReadFile()
 			if err != nil {
 				if os.IsNotExist(err) {
@@ -159,8 +196,11 @@ func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o ru
 	}
 
 	rs := bytes.NewReader(data)
+	newVersion := ""
 	if create {
 		err = configPath.CreateFile(rs, acl)
+	} else if conditional {
+		newVersion, err = configPath.WriteFileIfVersion(rs, acl, version)
 	} else {
 		err = configPath.WriteFile(rs, acl)
 	}
@@ -169,11 +209,18 @@ func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o ru
 			klog.Warningf("failed to create file as already exists: %v", configPath)
 			return err
 		}
+		if conditional && (vfs.IsConflict(err) || os.IsNotExist(err)) {
+			return errors.NewConflict(schema.GroupResource{Group: kops.GroupName, Resource: c.kind}, objectMeta.GetName(), fmt.Errorf(conflictMessage))
+		}
 		return fmt.Errorf("error writing configuration file %s: %v", configPath, err)
 	}
+
+	objectMeta.SetResourceVersion(newVersion)
 	return nil
 }
 
+const conflictMessage = "the object has been modified in the state store; please apply your changes to the latest version and try again"
+
 func (c *commonVFS) update(ctx context.Context, cluster *kops.Cluster, i runtime.Object) error {
 	objectMeta, err := meta.Accessor(i)
 	if err != nil {
@@ -194,6 +241,9 @@ func (c *commonVFS) update(ctx context.Context, cluster *kops.Cluster, i runtime
 
 	err = c.writeConfig(cluster, c.basePath.Join(objectMeta.GetName()), i, vfs.WriteOptionOnlyIfExists)
 	if err != nil {
+		if errors.IsConflict(err) {
+			return err
+		}
 		return fmt.Errorf("error writing %s: %v", c.kind, err)
 	}
 
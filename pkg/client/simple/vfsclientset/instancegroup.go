@@ -140,6 +140,10 @@ func (c *InstanceGroupVFS) Update(ctx context.Context, g *kopsapi.InstanceGroup,
 		return nil, err
 	}
 
+	if g.ResourceVersion != "" && g.ResourceVersion != old.ResourceVersion {
+		return nil, errors.NewConflict(schema.GroupResource{Group: kopsapi.GroupName, Resource: "InstanceGroup"}, g.Name, fmt.Errorf(conflictMessage))
+	}
+
 	if !apiequality.Semantic.DeepEqual(old.Spec, g.Spec) {
 		g.SetGeneration(old.GetGeneration() + 1)
 	}
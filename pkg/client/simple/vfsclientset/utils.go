@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -35,7 +36,12 @@ func listChildNames(ctx context.Context, vfsPath vfs.Path) ([]string, error) {
 
 	var names []string
 	for _, child := range children {
-		names = append(names, child.Base())
+		name := child.Base()
+		// Skip temporary and lock files created while writing
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		names = append(names, name)
 	}
 	return names, nil
 }
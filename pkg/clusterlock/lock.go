@@ -0,0 +1,346 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterlock
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// DefaultTTL is how long a lock is valid for without being renewed.
+// Holders renew the lock well before it expires, so this bounds how long a crashed command blocks others.
+const DefaultTTL = 5 * time.Minute
+
+// Lock is the lease record stored in the state store while a mutating command runs against a cluster
+type Lock struct {
+	// ID identifies the lease, so that a holder only ever renews or releases its own lock
+	ID string `json:"id"`
+	// Holder describes who holds the lock, as user@host
+	Holder string `json:"holder"`
+	// Command is the kops command that holds the lock
+	Command string `json:"command"`
+	// Acquired is when the lock was first taken
+	Acquired time.Time `json:"acquired"`
+	// Expires is when the lock can be taken over by another command, unless it is renewed
+	Expires time.Time `json:"expires"`
+}
+
+// IsExpired returns true if the lock is no longer valid at the specified time
+func (l *Lock) IsExpired(now time.Time) bool {
+	return !now.Before(l.Expires)
+}
+
+// LockedError is returned when the cluster is locked by another command
+type LockedError struct {
+	ClusterName string
+	Lock        *Lock
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("cluster %q is locked by %s running %q (acquired %s, expires %s); if that command is no longer running, remove the lock with `kops delete lock --name %s --force`",
+		e.ClusterName, e.Lock.Holder, e.Lock.Command, e.Lock.Acquired.Format(time.RFC3339), e.Lock.Expires.Format(time.RFC3339), e.ClusterName)
+}
+
+// Lease is a held lock, which is renewed in the background until it is released
+type Lease struct {
+	path vfs.Path
+	ttl  time.Duration
+
+	mutex   sync.Mutex
+	lock    Lock
+	version string
+
+	// ctx is cancelled once the lease is lost or released
+	ctx    context.Context
+	cancel context.CancelFunc
+	// lost records why the lease was lost, if it was
+	lost error
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// LockPath returns the location of the lock for the cluster with the specified configBase
+func LockPath(configBase vfs.Path) vfs.Path {
+	return configBase.Join(registry.PathLock)
+}
+
+// Get returns the current lock on the cluster, or nil if the cluster is not locked
+func Get(configBase vfs.Path) (*Lock, error) {
+	lock, _, err := read(LockPath(configBase))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
+// Delete removes the lock on the cluster, regardless of who holds it
+func Delete(configBase vfs.Path) error {
+	p := LockPath(configBase)
+	if err := p.Remove(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing lock %s: %v", p, err)
+	}
+	return nil
+}
+
+// Acquire takes the lock on the cluster for the specified command.
+// If the cluster is locked by another command that has not expired, a LockedError is returned.
+// The context of the returned Lease is derived from ctx, and is cancelled if the lease is lost.
+func Acquire(ctx context.Context, clusterName string, configBase vfs.Path, command string, ttl time.Duration) (*Lease, error) {
+	id, err := newLockID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	l := &Lease{
+		path: LockPath(configBase),
+		ttl:  ttl,
+		lock: Lock{
+			ID:       id,
+			Holder:   holder(),
+			Command:  command,
+			Acquired: now,
+			Expires:  now.Add(ttl),
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	data, err := json.Marshal(&l.lock)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing lock: %v", err)
+	}
+
+	err = l.path.CreateFile(bytes.NewReader(data), nil)
+	if err != nil {
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("error creating lock %s: %v", l.path, err)
+		}
+
+		existing, version, err := read(l.path)
+		if err != nil {
+			return nil, err
+		}
+		if !existing.IsExpired(now) {
+			return nil, &LockedError{ClusterName: clusterName, Lock: existing}
+		}
+
+		klog.Warningf("taking over expired lock on cluster %q held by %s running %q", clusterName, existing.Holder, existing.Command)
+		// The conditional write ensures only one of several concurrent commands takes over the expired lock
+		if _, err := l.path.WriteFileIfVersion(bytes.NewReader(data), nil, version); err != nil {
+			if vfs.IsConflict(err) {
+				current, _, readErr := read(l.path)
+				if readErr == nil {
+					return nil, &LockedError{ClusterName: clusterName, Lock: current}
+				}
+			}
+			return nil, fmt.Errorf("error taking over expired lock %s: %v", l.path, err)
+		}
+	}
+
+	current, version, err := read(l.path)
+	if err != nil {
+		return nil, err
+	}
+	if current.ID != l.lock.ID {
+		// Another command replaced the lock between our write and the read
+		return nil, &LockedError{ClusterName: clusterName, Lock: current}
+	}
+	l.version = version
+
+	l.ctx, l.cancel = context.WithCancel(ctx)
+	go l.renewLoop()
+
+	return l, nil
+}
+
+// Lock returns the lock record of the lease
+func (l *Lease) Lock() Lock {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.lock
+}
+
+// Context returns a context that is cancelled if the lease is lost, or once it is released.
+// Commands should use it for the work done under the lock.
+func (l *Lease) Context() context.Context {
+	return l.ctx
+}
+
+// Err returns a non-nil error if the lease was lost while it was held
+func (l *Lease) Err() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.lost
+}
+
+// Release stops renewing the lease and removes the lock, if it is still ours.
+// If the lease was lost while it was held, the reason is returned.
+func (l *Lease) Release() error {
+	close(l.stop)
+	<-l.done
+	defer l.cancel()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.lost != nil {
+		return l.lost
+	}
+
+	// The delete is conditional on the version we last wrote, so that we never remove a lock that was taken over
+	if err := l.path.RemoveIfVersion(l.version); err != nil {
+		if os.IsNotExist(err) {
+			// Deleting the cluster removes the lock along with the rest of the state
+			klog.V(2).Infof("lock %s was removed while held", l.path)
+			return nil
+		}
+		if vfs.IsConflict(err) {
+			return l.lostTo(l.path)
+		}
+		return fmt.Errorf("error removing lock %s: %v", l.path, err)
+	}
+	return nil
+}
+
+func (l *Lease) renewLoop() {
+	defer close(l.done)
+
+	interval := l.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			err := l.renew()
+			if err == nil {
+				continue
+			}
+
+			l.mutex.Lock()
+			switch {
+			case vfs.IsConflict(err) || os.IsNotExist(err):
+				l.lost = l.lostTo(l.path)
+			case time.Now().Add(interval).After(l.lock.Expires):
+				// We can't renew before the lock expires, so another command may take it over
+				l.lost = fmt.Errorf("lock %s could not be renewed before it expired: %v", l.path, err)
+			default:
+				klog.Warningf("error renewing lock %s, will retry: %v", l.path, err)
+			}
+			lost := l.lost
+			l.mutex.Unlock()
+
+			if lost != nil {
+				klog.Errorf("%v; aborting", lost)
+				l.cancel()
+				return
+			}
+		}
+	}
+}
+
+// lostTo builds the error reported when the lock was replaced or removed by someone else
+func (l *Lease) lostTo(p vfs.Path) error {
+	current, _, err := read(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("lock %s was removed while held", p)
+		}
+		return fmt.Errorf("lock %s was changed while held", p)
+	}
+	return fmt.Errorf("lock %s was taken over by %s running %q while held", p, current.Holder, current.Command)
+}
+
+// renew extends the expiry of the lock
+func (l *Lease) renew() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	lock := l.lock
+	lock.Expires = time.Now().UTC().Add(l.ttl)
+
+	data, err := json.Marshal(&lock)
+	if err != nil {
+		return fmt.Errorf("error serializing lock: %v", err)
+	}
+
+	version, err := l.path.WriteFileIfVersion(bytes.NewReader(data), nil, l.version)
+	if err != nil {
+		return err
+	}
+
+	l.lock = lock
+	l.version = version
+	return nil
+}
+
+func read(p vfs.Path) (*Lock, string, error) {
+	data, version, err := p.ReadFileWithVersion()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", err
+		}
+		return nil, "", fmt.Errorf("error reading lock %s: %v", p, err)
+	}
+
+	lock := &Lock{}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, "", fmt.Errorf("error parsing lock %s: %v", p, err)
+	}
+	return lock, version, nil
+}
+
+func newLockID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating lock id: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func holder() string {
+	username := "unknown"
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return username + "@" + hostname
+}
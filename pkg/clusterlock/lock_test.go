@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterlock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "/state/minimal.example.com")
+
+	lease, err := Acquire(context.Background(), "minimal.example.com", configBase, "update cluster", DefaultTTL)
+	if err != nil {
+		t.Fatalf("error acquiring lock: %v", err)
+	}
+
+	lock, err := Get(configBase)
+	if err != nil {
+		t.Fatalf("error reading lock: %v", err)
+	}
+	if lock == nil || lock.Command != "update cluster" {
+		t.Fatalf("expected lock held by update cluster, got %+v", lock)
+	}
+
+	if _, err := Acquire(context.Background(), "minimal.example.com", configBase, "rolling-update cluster", DefaultTTL); err == nil {
+		t.Fatalf("expected error acquiring held lock")
+	} else if lockedErr, ok := err.(*LockedError); !ok {
+		t.Fatalf("expected LockedError, got %v", err)
+	} else if lockedErr.Lock.ID != lease.Lock().ID {
+		t.Errorf("expected LockedError to report the held lock, got %+v", lockedErr.Lock)
+	}
+
+	if err := lease.Release(); err != nil {
+		t.Fatalf("error releasing lock: %v", err)
+	}
+	if lock, err := Get(configBase); err != nil || lock != nil {
+		t.Fatalf("expected lock to be removed, got %+v, %v", lock, err)
+	}
+
+	lease, err = Acquire(context.Background(), "minimal.example.com", configBase, "rolling-update cluster", DefaultTTL)
+	if err != nil {
+		t.Fatalf("error acquiring released lock: %v", err)
+	}
+	if err := lease.Release(); err != nil {
+		t.Fatalf("error releasing lock: %v", err)
+	}
+}
+
+func TestAcquireExpired(t *testing.T) {
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "/state/minimal.example.com")
+
+	stale := &Lock{
+		ID:       "stale",
+		Holder:   "someone@somewhere",
+		Command:  "update cluster",
+		Acquired: time.Now().Add(-time.Hour),
+		Expires:  time.Now().Add(-time.Minute),
+	}
+	data, err := json.Marshal(stale)
+	if err != nil {
+		t.Fatalf("error serializing lock: %v", err)
+	}
+	if err := LockPath(configBase).WriteFile(bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("error writing lock: %v", err)
+	}
+
+	lease, err := Acquire(context.Background(), "minimal.example.com", configBase, "rolling-update cluster", DefaultTTL)
+	if err != nil {
+		t.Fatalf("error taking over expired lock: %v", err)
+	}
+
+	lock, err := Get(configBase)
+	if err != nil {
+		t.Fatalf("error reading lock: %v", err)
+	}
+	if lock.ID != lease.Lock().ID {
+		t.Errorf("expected lock to be taken over, got %+v", lock)
+	}
+
+	// A lease that was taken over must not remove the new holder's lock
+	if err := LockPath(configBase).WriteFile(bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("error writing lock: %v", err)
+	}
+	if err := lease.Release(); err == nil {
+		t.Errorf("expected error releasing lock that was taken over")
+	}
+	if lock, err := Get(configBase); err != nil || lock == nil || lock.ID != "stale" {
+		t.Errorf("expected other holder's lock to remain, got %+v, %v", lock, err)
+	}
+}
+
+func TestRenew(t *testing.T) {
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "/state/minimal.example.com")
+
+	lease, err := Acquire(context.Background(), "minimal.example.com", configBase, "update cluster", DefaultTTL)
+	if err != nil {
+		t.Fatalf("error acquiring lock: %v", err)
+	}
+	defer lease.Release()
+
+	before := lease.Lock().Expires
+	time.Sleep(10 * time.Millisecond)
+	if err := lease.renew(); err != nil {
+		t.Fatalf("error renewing lock: %v", err)
+	}
+
+	lock, err := Get(configBase)
+	if err != nil {
+		t.Fatalf("error reading lock: %v", err)
+	}
+	if !lock.Expires.After(before) {
+		t.Errorf("expected expiry to be extended past %s, got %s", before, lock.Expires)
+	}
+}
+
+func TestLeaseLost(t *testing.T) {
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "/state/minimal.example.com")
+
+	lease, err := Acquire(context.Background(), "minimal.example.com", configBase, "update cluster", 30*time.Millisecond)
+	if err != nil {
+		t.Fatalf("error acquiring lock: %v", err)
+	}
+
+	// Simulate another command taking over the lock
+	other := &Lock{
+		ID:       "other",
+		Holder:   "someone@somewhere",
+		Command:  "rolling-update cluster",
+		Acquired: time.Now(),
+		Expires:  time.Now().Add(time.Hour),
+	}
+	data, err := json.Marshal(other)
+	if err != nil {
+		t.Fatalf("error serializing lock: %v", err)
+	}
+	if err := LockPath(configBase).WriteFile(bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("error writing lock: %v", err)
+	}
+
+	select {
+	case <-lease.Context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected lease context to be cancelled after the lock was taken over")
+	}
+	if lease.Err() == nil {
+		t.Errorf("expected lease to report the lock was lost")
+	}
+	if err := lease.Release(); err == nil {
+		t.Errorf("expected error releasing lost lease")
+	}
+	if lock, err := Get(configBase); err != nil || lock == nil || lock.ID != "other" {
+		t.Errorf("expected other holder's lock to remain, got %+v, %v", lock, err)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/clusterlock"
+)
+
+// ClusterLock is the state store lock held on a cluster by a mutating command
+type ClusterLock struct {
+	clusterName string
+	lease       *clusterlock.Lease
+	released    bool
+}
+
+// LockCluster takes the state store lock on the cluster for a mutating command.
+// The caller should defer Unlock, and use Context for the work done under the lock.
+func LockCluster(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster, command string) (*ClusterLock, error) {
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	lease, err := clusterlock.Acquire(ctx, cluster.ObjectMeta.Name, configBase, command, clusterlock.DefaultTTL)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(2).Infof("acquired lock on cluster %q for %q", cluster.ObjectMeta.Name, command)
+
+	return &ClusterLock{
+		clusterName: cluster.ObjectMeta.Name,
+		lease:       lease,
+	}, nil
+}
+
+// WithClusterLock runs fn while holding the state store lock on the cluster.
+// fn is passed the context of the lock, which is cancelled if the lock is lost.
+func WithClusterLock(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster, command string, fn func(ctx context.Context) error) (err error) {
+	lock, err := LockCluster(ctx, clientset, cluster, command)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock(&err)
+
+	return fn(lock.Context())
+}
+
+// Context returns a context that is cancelled if the lock is lost while the command is running
+func (l *ClusterLock) Context() context.Context {
+	return l.lease.Context()
+}
+
+// Err returns a non-nil error if the lock was lost; commands should check it before writing changes
+func (l *ClusterLock) Err() error {
+	if err := l.lease.Err(); err != nil {
+		return fmt.Errorf("lost lock on cluster %q: %v", l.clusterName, err)
+	}
+	return nil
+}
+
+// Release releases the lock, returning an error if the lock was lost while the command was running.
+// Releasing a lock more than once has no effect.
+func (l *ClusterLock) Release() error {
+	if l.released {
+		return nil
+	}
+	l.released = true
+
+	if err := l.lease.Release(); err != nil {
+		return fmt.Errorf("error releasing lock on cluster %q: %v", l.clusterName, err)
+	}
+	return nil
+}
+
+// Unlock releases the lock, and is intended to be deferred.
+// If the lock was lost, the command was not protected against concurrent changes,
+// so the error is reported through errp unless the command has already failed.
+func (l *ClusterLock) Unlock(errp *error) {
+	err := l.Release()
+	if err == nil {
+		return
+	}
+	if *errp == nil {
+		*errp = err
+	} else {
+		klog.Warning(err)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// DefaultSSHCredentialName is the name of the SSHCredential used by the cluster, and the name used for an SSHCredential in a manifest that does not set one
+const DefaultSSHCredentialName = "admin"
+
+// dockerConfigSecretName is the name of the secret holding the docker config for the nodes
+const dockerConfigSecretName = "dockerconfig"
+
+// ManifestSecretOptions controls how secrets read from a manifest are stored
+type ManifestSecretOptions struct {
+	// Replace overwrites existing secrets, rather than failing if they already exist
+	Replace bool
+}
+
+// ClusterForManifestObject returns the cluster named by the cluster name label of an object read from a manifest
+func ClusterForManifestObject(ctx context.Context, clientset simple.Clientset, kind string, labels map[string]string) (*kops.Cluster, error) {
+	clusterName := labels[kops.LabelClusterName]
+	if clusterName == "" {
+		return nil, fmt.Errorf("must specify %q label with cluster name for %s", kops.LabelClusterName, kind)
+	}
+	cluster, err := clientset.GetCluster(ctx, clusterName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("cluster %q not found", clusterName)
+		}
+		return nil, fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
+	}
+	if cluster == nil {
+		return nil, fmt.Errorf("cluster %q not found", clusterName)
+	}
+	return cluster, nil
+}
+
+// StoreSSHCredential adds the public key of an SSHCredential read from a manifest to the cluster.
+// It returns the name the key was stored under.
+func StoreSSHCredential(clientset simple.Clientset, cluster *kops.Cluster, sshCredential *kops.SSHCredential) (string, error) {
+	if sshCredential.Spec.PublicKey == "" {
+		return "", fmt.Errorf("spec.publicKey is required for SSHCredential")
+	}
+
+	name := sshCredential.ObjectMeta.Name
+	if name == "" {
+		name = DefaultSSHCredentialName
+	}
+	if name != DefaultSSHCredentialName {
+		// The cluster only uses the SSH key with this name
+		return "", fmt.Errorf("SSHCredential must be named %q, got %q", DefaultSSHCredentialName, name)
+	}
+
+	sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
+	if err != nil {
+		return "", err
+	}
+	if err := sshCredentialStore.AddSSHPublicKey(name, []byte(sshCredential.Spec.PublicKey)); err != nil {
+		return "", fmt.Errorf("error adding SSHCredential %q: %v", name, err)
+	}
+	return name, nil
+}
+
+// StoreKeyset stores a Keyset read from a manifest.
+// Keypair keysets are added to the cluster's keystore; Secret keysets are written to its secret store.
+func StoreKeyset(clientset simple.Clientset, cluster *kops.Cluster, keyset *kops.Keyset, options *ManifestSecretOptions) error {
+	name := keyset.ObjectMeta.Name
+	if name == "" {
+		return fmt.Errorf("metadata.name is required for Keyset")
+	}
+	if len(keyset.Spec.Keys) == 0 {
+		return fmt.Errorf("keyset %q must contain at least one key", name)
+	}
+
+	switch keyset.Spec.Type {
+	case kops.SecretTypeKeypair:
+		return storeKeypairKeyset(clientset, cluster, keyset, options)
+	case kops.SecretTypeSecret:
+		return storeSecretKeyset(clientset, cluster, keyset, options)
+	default:
+		return fmt.Errorf("keyset %q has unsupported type %q (must be %q or %q)", name, keyset.Spec.Type, kops.SecretTypeKeypair, kops.SecretTypeSecret)
+	}
+}
+
+func storeKeypairKeyset(clientset simple.Clientset, cluster *kops.Cluster, keyset *kops.Keyset, options *ManifestSecretOptions) error {
+	name := keyset.ObjectMeta.Name
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return fmt.Errorf("error getting keystore: %v", err)
+	}
+
+	if !options.Replace {
+		existing, err := keyStore.FindCert(name)
+		if err != nil {
+			return fmt.Errorf("error checking for keyset %q: %v", name, err)
+		}
+		if existing != nil {
+			return fmt.Errorf("keyset %q already exists", name)
+		}
+	}
+
+	// Parse all the items before storing any, so that an invalid keyset is not partially stored
+	certs := make([]*pki.Certificate, len(keyset.Spec.Keys))
+	privateKeys := make([]*pki.PrivateKey, len(keyset.Spec.Keys))
+	certOnly := 0
+	for i, item := range keyset.Spec.Keys {
+		if len(item.PublicMaterial) == 0 {
+			return fmt.Errorf("keyset %q key %d must set publicMaterial", name, i)
+		}
+		cert, err := pki.ParsePEMCertificate(item.PublicMaterial)
+		if err != nil {
+			return fmt.Errorf("error parsing certificate for keyset %q: %v", name, err)
+		}
+		certs[i] = cert
+
+		if len(item.PrivateMaterial) == 0 {
+			certOnly++
+			continue
+		}
+		privateKeys[i], err = pki.ParsePEMPrivateKey(item.PrivateMaterial)
+		if err != nil {
+			return fmt.Errorf("error parsing private key for keyset %q: %v", name, err)
+		}
+	}
+	// The keystore adds every certificate without a private key under the same id, so only one of them would be kept
+	if certOnly > 1 {
+		return fmt.Errorf("keyset %q can contain at most one key without privateMaterial, got %d", name, certOnly)
+	}
+
+	for i, cert := range certs {
+		if privateKeys[i] == nil {
+			// A certificate without a private key is added as an alternative certificate, e.g. during CA rotation
+			if err := keyStore.AddCert(name, cert); err != nil {
+				return fmt.Errorf("error storing certificate for keyset %q: %v", name, err)
+			}
+			continue
+		}
+		if err := keyStore.StoreKeypair(name, cert, privateKeys[i]); err != nil {
+			return fmt.Errorf("error storing keypair for keyset %q: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+func storeSecretKeyset(clientset simple.Clientset, cluster *kops.Cluster, keyset *kops.Keyset, options *ManifestSecretOptions) error {
+	name := keyset.ObjectMeta.Name
+	if len(keyset.Spec.Keys) != 1 {
+		return fmt.Errorf("secret keyset %q must contain exactly one key", name)
+	}
+
+	data := keyset.Spec.Keys[0].PrivateMaterial
+	if len(data) == 0 {
+		return fmt.Errorf("secret keyset %q must set privateMaterial", name)
+	}
+
+	if name == dockerConfigSecretName {
+		var parsed map[string]interface{}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return fmt.Errorf("error parsing docker config for secret %q: %v", name, err)
+		}
+	}
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	secret := &fi.Secret{Data: data}
+	if options.Replace {
+		if _, err := secretStore.ReplaceSecret(name, secret); err != nil {
+			return fmt.Errorf("error replacing secret %q: %v", name, err)
+		}
+		return nil
+	}
+
+	_, created, err := secretStore.GetOrCreateSecret(name, secret)
+	if err != nil {
+		return fmt.Errorf("error creating secret %q: %v", name, err)
+	}
+	if !created {
+		return fmt.Errorf("secret %q already exists", name)
+	}
+	return nil
+}
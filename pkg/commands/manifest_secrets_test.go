@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func setupManifestSecretsTest(t *testing.T) (simple.Clientset, *kops.Cluster) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	store, err := vfs.Context.BuildVfsPath("memfs://state-store")
+	if err != nil {
+		t.Fatalf("error building path: %v", err)
+	}
+	clientset := vfsclientset.NewVFSClientset(store)
+
+	cluster := testutils.BuildMinimalCluster("minimal.example.com")
+	cluster.Spec.ConfigBase = "memfs://state-store/minimal.example.com"
+	cluster.Spec.KeyStore = "memfs://state-store/minimal.example.com/pki"
+	cluster.Spec.SecretStore = "memfs://state-store/minimal.example.com/secrets"
+	if _, err := clientset.CreateCluster(ctx, cluster); err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+
+	labels := map[string]string{kops.LabelClusterName: "minimal.example.com"}
+	cluster, err = ClusterForManifestObject(ctx, clientset, "Keyset", labels)
+	if err != nil {
+		t.Fatalf("error finding cluster: %v", err)
+	}
+	return clientset, cluster
+}
+
+func TestStoreKeysetKeypair(t *testing.T) {
+	clientset, cluster := setupManifestSecretsTest(t)
+
+	cert, key, _, err := pki.IssueCert(&pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "custom-ca"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("error issuing certificate: %v", err)
+	}
+	certBytes, _ := cert.AsBytes()
+	keyBytes, _ := key.AsBytes()
+
+	keyset := &kops.Keyset{}
+	keyset.ObjectMeta.Name = "ca"
+	keyset.Spec.Type = kops.SecretTypeKeypair
+	keyset.Spec.Keys = []kops.KeysetItem{
+		{PublicMaterial: certBytes, PrivateMaterial: keyBytes},
+	}
+
+	if err := StoreKeyset(clientset, cluster, keyset, &ManifestSecretOptions{}); err != nil {
+		t.Fatalf("error storing keyset: %v", err)
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		t.Fatalf("error getting keystore: %v", err)
+	}
+	storedCert, storedKey, _, err := keyStore.FindKeypair("ca")
+	if err != nil {
+		t.Fatalf("error reading keypair: %v", err)
+	}
+	if storedCert == nil || storedKey == nil {
+		t.Fatalf("keypair was not stored")
+	}
+	if storedCert.Subject.CommonName != "custom-ca" {
+		t.Errorf("unexpected certificate subject %q", storedCert.Subject.CommonName)
+	}
+
+	if err := StoreKeyset(clientset, cluster, keyset, &ManifestSecretOptions{}); err == nil {
+		t.Errorf("expected error creating a keyset that already exists")
+	}
+	if err := StoreKeyset(clientset, cluster, keyset, &ManifestSecretOptions{Replace: true}); err != nil {
+		t.Errorf("error replacing keyset: %v", err)
+	}
+}
+
+func TestStoreKeysetCertificateOnly(t *testing.T) {
+	clientset, cluster := setupManifestSecretsTest(t)
+
+	var certs [][]byte
+	for _, cn := range []string{"old-ca", "new-ca"} {
+		cert, _, _, err := pki.IssueCert(&pki.IssueCertRequest{
+			Type:    "ca",
+			Subject: pkix.Name{CommonName: cn},
+		}, nil)
+		if err != nil {
+			t.Fatalf("error issuing certificate: %v", err)
+		}
+		certBytes, _ := cert.AsBytes()
+		certs = append(certs, certBytes)
+	}
+
+	keyset := &kops.Keyset{}
+	keyset.ObjectMeta.Name = "ca"
+	keyset.Spec.Type = kops.SecretTypeKeypair
+	keyset.Spec.Keys = []kops.KeysetItem{
+		{PublicMaterial: certs[0]},
+		{PublicMaterial: certs[1]},
+	}
+
+	if err := StoreKeyset(clientset, cluster, keyset, &ManifestSecretOptions{}); err == nil {
+		t.Fatalf("expected error storing a keyset with two certificates without private keys")
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		t.Fatalf("error getting keystore: %v", err)
+	}
+	if existing, err := keyStore.FindCert("ca"); err != nil {
+		t.Fatalf("error reading certificate: %v", err)
+	} else if existing != nil {
+		t.Fatalf("expected the rejected keyset not to be stored, found %q", existing.Subject.CommonName)
+	}
+
+	keyset.Spec.Keys = keyset.Spec.Keys[1:]
+	if err := StoreKeyset(clientset, cluster, keyset, &ManifestSecretOptions{}); err != nil {
+		t.Fatalf("error storing keyset: %v", err)
+	}
+	stored, err := keyStore.FindCert("ca")
+	if err != nil {
+		t.Fatalf("error reading certificate: %v", err)
+	}
+	if stored == nil || stored.Subject.CommonName != "new-ca" {
+		t.Errorf("expected certificate new-ca to be stored, got %v", stored)
+	}
+}
+
+func TestStoreKeysetDockerConfig(t *testing.T) {
+	clientset, cluster := setupManifestSecretsTest(t)
+
+	dockerConfig := `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`
+
+	keyset := &kops.Keyset{}
+	keyset.ObjectMeta.Name = "dockerconfig"
+	keyset.Spec.Type = kops.SecretTypeSecret
+	keyset.Spec.Keys = []kops.KeysetItem{
+		{PrivateMaterial: []byte("not json")},
+	}
+	if err := StoreKeyset(clientset, cluster, keyset, &ManifestSecretOptions{}); err == nil {
+		t.Errorf("expected error storing an invalid docker config")
+	}
+
+	keyset.Spec.Keys[0].PrivateMaterial = []byte(dockerConfig)
+	if err := StoreKeyset(clientset, cluster, keyset, &ManifestSecretOptions{}); err != nil {
+		t.Fatalf("error storing keyset: %v", err)
+	}
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		t.Fatalf("error getting secret store: %v", err)
+	}
+	secret, err := secretStore.FindSecret("dockerconfig")
+	if err != nil {
+		t.Fatalf("error reading secret: %v", err)
+	}
+	if secret == nil || string(secret.Data) != dockerConfig {
+		t.Fatalf("unexpected docker config secret %v", secret)
+	}
+
+	if err := StoreKeyset(clientset, cluster, keyset, &ManifestSecretOptions{}); err == nil {
+		t.Errorf("expected error creating a secret that already exists")
+	}
+
+	keyset.Spec.Keys[0] = kops.KeysetItem{PrivateMaterial: []byte(`{"auths":{}}`)}
+	if err := StoreKeyset(clientset, cluster, keyset, &ManifestSecretOptions{Replace: true}); err != nil {
+		t.Fatalf("error replacing secret: %v", err)
+	}
+	secret, err = secretStore.FindSecret("dockerconfig")
+	if err != nil {
+		t.Fatalf("error reading secret: %v", err)
+	}
+	if string(secret.Data) != `{"auths":{}}` {
+		t.Errorf("secret was not replaced, got %q", secret.Data)
+	}
+}
+
+func TestStoreSSHCredential(t *testing.T) {
+	clientset, cluster := setupManifestSecretsTest(t)
+
+	publicKey, err := ioutil.ReadFile("../../tests/integration/update_cluster/minimal/id_rsa.pub")
+	if err != nil {
+		t.Fatalf("error reading public key: %v", err)
+	}
+
+	sshCredential := &kops.SSHCredential{}
+	sshCredential.Spec.PublicKey = string(publicKey)
+	name, err := StoreSSHCredential(clientset, cluster, sshCredential)
+	if err != nil {
+		t.Fatalf("error storing ssh credential: %v", err)
+	}
+	if name != DefaultSSHCredentialName {
+		t.Errorf("expected ssh credential to be named %q, got %q", DefaultSSHCredentialName, name)
+	}
+
+	sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
+	if err != nil {
+		t.Fatalf("error getting ssh credential store: %v", err)
+	}
+	keys, err := sshCredentialStore.FindSSHPublicKeys(DefaultSSHCredentialName)
+	if err != nil {
+		t.Fatalf("error reading ssh credentials: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("expected one ssh credential, got %d", len(keys))
+	}
+
+	sshCredential.ObjectMeta.Name = "other"
+	if _, err := StoreSSHCredential(clientset, cluster, sshCredential); err == nil {
+		t.Errorf("expected error storing an ssh credential that is not named %q", DefaultSSHCredentialName)
+	}
+}
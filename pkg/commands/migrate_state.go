@@ -0,0 +1,250 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// MigrateStateOptions holds the options for moving a cluster's state to another state store
+type MigrateStateOptions struct {
+	// To is the VFS path of the state store to move the cluster to
+	To string
+	// DryRun only reports the objects that would be copied
+	DryRun bool
+}
+
+// stateTree is a subtree of the state store that is copied as a unit
+type stateTree struct {
+	source vfs.Path
+	target vfs.Path
+}
+
+// MigrateState copies the state of the cluster into the state store at options.To,
+// rewriting the paths in the cluster spec to point to the new location.
+// The source state is left untouched, so that it can be removed once the migration has been verified.
+func MigrateState(ctx context.Context, out io.Writer, cluster *kops.Cluster, options *MigrateStateOptions) error {
+	if options.To == "" {
+		return fmt.Errorf("target state store is required")
+	}
+	targetStore, err := vfs.Context.BuildVfsPath(options.To)
+	if err != nil {
+		return fmt.Errorf("error parsing target state store %q: %v", options.To, err)
+	}
+
+	oldConfigBase, err := registry.ConfigBase(cluster)
+	if err != nil {
+		return err
+	}
+	newConfigBase := targetStore.Join(cluster.ObjectMeta.Name)
+	if newConfigBase.Path() == oldConfigBase.Path() {
+		return fmt.Errorf("cluster %q is already stored in %q", cluster.ObjectMeta.Name, options.To)
+	}
+
+	if _, err := newConfigBase.Join(registry.PathCluster).ReadFile(); err == nil {
+		return fmt.Errorf("cluster %q already exists in %q", cluster.ObjectMeta.Name, options.To)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("error checking for cluster in %q: %v", options.To, err)
+	}
+
+	migrated := cluster.DeepCopy()
+	migrated.Spec.ConfigBase = newConfigBase.Path()
+
+	trees := []*stateTree{
+		{source: oldConfigBase, target: newConfigBase},
+	}
+
+	keyStore, err := rebaseStore(cluster.Spec.KeyStore, oldConfigBase, newConfigBase, "pki", &trees)
+	if err != nil {
+		return fmt.Errorf("error migrating keyStore: %v", err)
+	}
+	migrated.Spec.KeyStore = keyStore
+
+	secretStore, err := rebaseStore(cluster.Spec.SecretStore, oldConfigBase, newConfigBase, "secrets", &trees)
+	if err != nil {
+		return fmt.Errorf("error migrating secretStore: %v", err)
+	}
+	migrated.Spec.SecretStore = secretStore
+
+	for _, tree := range trees {
+		if err := copyStateTree(out, migrated, tree, oldConfigBase, options.DryRun); err != nil {
+			return err
+		}
+	}
+
+	printPathChange(out, "configBase", cluster.Spec.ConfigBase, migrated.Spec.ConfigBase)
+	printPathChange(out, "keyStore", cluster.Spec.KeyStore, migrated.Spec.KeyStore)
+	printPathChange(out, "secretStore", cluster.Spec.SecretStore, migrated.Spec.SecretStore)
+
+	if options.DryRun {
+		fmt.Fprintf(out, "\nMust specify --yes to migrate the cluster state\n")
+		return nil
+	}
+
+	// The context is cancelled if the command loses the cluster lock while copying
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("not writing cluster %q to %q: %v", cluster.ObjectMeta.Name, options.To, err)
+	}
+
+	// The cluster config is written last, so that the cluster only becomes visible
+	// in the new state store once everything it references has been copied.
+	clientset := vfsclientset.NewVFSClientset(targetStore)
+	if _, err := clientset.CreateCluster(ctx, migrated); err != nil {
+		return fmt.Errorf("error writing cluster %q to %q: %v", cluster.ObjectMeta.Name, options.To, err)
+	}
+
+	fmt.Fprintf(out, "\nCluster state migrated to %s\n", newConfigBase.Path())
+	fmt.Fprintf(out, "Run `kops update cluster --state=%s --yes` to point the cluster at the new state store.\n", options.To)
+	return nil
+}
+
+// rebaseStore computes the new location of a key or secret store.
+// Stores inside the old configBase keep their relative location;
+// stores elsewhere are copied to the default location under the new configBase.
+func rebaseStore(store string, oldConfigBase, newConfigBase vfs.Path, defaultName string, trees *[]*stateTree) (string, error) {
+	if store == "" {
+		return "", nil
+	}
+
+	storePath, err := vfs.Context.BuildVfsPath(store)
+	if err != nil {
+		return "", fmt.Errorf("error parsing %q: %v", store, err)
+	}
+
+	if storePath.Path() == oldConfigBase.Path() {
+		return newConfigBase.Path(), nil
+	}
+	if relativePath, err := vfs.RelativePath(oldConfigBase, storePath); err == nil {
+		return newConfigBase.Join(relativePath).Path(), nil
+	}
+
+	for _, tree := range *trees {
+		if tree.source.Path() == storePath.Path() {
+			return tree.target.Path(), nil
+		}
+	}
+
+	target := newConfigBase.Join(defaultName)
+	*trees = append(*trees, &stateTree{source: storePath, target: target})
+	return target.Path(), nil
+}
+
+// copyStateTree copies every file in the tree, verifying the contents after each write
+func copyStateTree(out io.Writer, cluster *kops.Cluster, tree *stateTree, oldConfigBase vfs.Path, dryRun bool) error {
+	files, err := tree.source.ReadTree()
+	if err != nil {
+		return fmt.Errorf("error listing files in %s: %v", tree.source, err)
+	}
+
+	for _, file := range files {
+		relativePath, err := vfs.RelativePath(tree.source, file)
+		if err != nil {
+			return err
+		}
+
+		// The cluster config is rewritten with the new paths rather than copied, and locks are not carried over.
+		// The completed cluster spec still references the old paths; the next `kops update cluster` regenerates it.
+		if tree.source.Path() == oldConfigBase.Path() && (relativePath == registry.PathCluster || relativePath == registry.PathClusterCompleted || relativePath == registry.PathLock) {
+			continue
+		}
+
+		target := tree.target.Join(relativePath)
+		if dryRun {
+			fmt.Fprintf(out, "Will copy %s to %s\n", file.Path(), target.Path())
+			continue
+		}
+
+		if err := copyStateFile(cluster, file, target); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Copied %s to %s\n", file.Path(), target.Path())
+	}
+
+	return nil
+}
+
+func copyStateFile(cluster *kops.Cluster, source, target vfs.Path) error {
+	data, err := source.ReadFile()
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", source, err)
+	}
+
+	acl, err := acls.GetACL(target, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := target.WriteFile(bytes.NewReader(data), acl); err != nil {
+		return fmt.Errorf("error writing %s: %v", target, err)
+	}
+
+	return verifyStateFile(data, target)
+}
+
+// verifyStateFile checks that the contents of target match data.
+// We prefer the hash reported by the target store, and fall back to reading the file back.
+func verifyStateFile(data []byte, target vfs.Path) error {
+	var actual *hashing.Hash
+	if hasHash, ok := target.(vfs.HasHash); ok {
+		h, err := hasHash.PreferredHash()
+		if err != nil {
+			klog.Warningf("unable to get hash of %s, will read file back: %v", target, err)
+		} else {
+			actual = h
+		}
+	}
+
+	if actual == nil {
+		written, err := target.ReadFile()
+		if err != nil {
+			return fmt.Errorf("error reading back %s: %v", target, err)
+		}
+		h, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(written))
+		if err != nil {
+			return err
+		}
+		actual = h
+	}
+
+	expected, err := actual.Algorithm.Hash(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if !expected.Equal(actual) {
+		return fmt.Errorf("hash mismatch after copying to %s: expected %s, got %s", target, expected, actual)
+	}
+	return nil
+}
+
+func printPathChange(out io.Writer, field string, oldPath, newPath string) {
+	if oldPath == newPath {
+		return
+	}
+	fmt.Fprintf(out, "Will change spec.%s from %q to %q\n", field, oldPath, newPath)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func writeTestFiles(t *testing.T, files map[string]string) {
+	for location, contents := range files {
+		p, err := vfs.Context.BuildVfsPath(location)
+		if err != nil {
+			t.Fatalf("error building path %q: %v", location, err)
+		}
+		if err := p.WriteFile(bytes.NewReader([]byte(contents)), nil); err != nil {
+			t.Fatalf("error writing %q: %v", location, err)
+		}
+	}
+}
+
+func setupMigrateStateTest(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	oldStore, err := vfs.Context.BuildVfsPath("memfs://old-store")
+	if err != nil {
+		t.Fatalf("error building path: %v", err)
+	}
+
+	cluster := testutils.BuildMinimalCluster("minimal.example.com")
+	cluster.Spec.ConfigBase = "memfs://old-store/minimal.example.com"
+	cluster.Spec.KeyStore = "memfs://old-store/minimal.example.com/pki"
+	cluster.Spec.SecretStore = "memfs://secrets-store/minimal.example.com"
+	if _, err := vfsclientset.NewVFSClientset(oldStore).CreateCluster(ctx, cluster); err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+
+	writeTestFiles(t, map[string]string{
+		"memfs://old-store/minimal.example.com/instancegroup/nodes":         "nodes",
+		"memfs://old-store/minimal.example.com/cluster.spec":                "configBase: memfs://old-store/minimal.example.com",
+		"memfs://old-store/minimal.example.com/pki/private/ca/keyset.yaml":  "ca",
+		"memfs://secrets-store/minimal.example.com/admin":                   "admin",
+		"memfs://secrets-store/minimal.example.com/dockerconfig":            "dockerconfig",
+		"memfs://old-store/minimal.example.com/backups/etcd/main/control/x": "control",
+	})
+}
+
+func TestMigrateState(t *testing.T) {
+	ctx := context.TODO()
+	setupMigrateStateTest(t)
+
+	oldStore, _ := vfs.Context.BuildVfsPath("memfs://old-store")
+	cluster, err := vfsclientset.NewVFSClientset(oldStore).GetCluster(ctx, "minimal.example.com")
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := MigrateState(ctx, &out, cluster, &MigrateStateOptions{To: "memfs://new-store"}); err != nil {
+		t.Fatalf("error migrating state: %v", err)
+	}
+
+	for location, expected := range map[string]string{
+		"memfs://new-store/minimal.example.com/instancegroup/nodes":         "nodes",
+		"memfs://new-store/minimal.example.com/pki/private/ca/keyset.yaml":  "ca",
+		"memfs://new-store/minimal.example.com/secrets/admin":               "admin",
+		"memfs://new-store/minimal.example.com/secrets/dockerconfig":        "dockerconfig",
+		"memfs://new-store/minimal.example.com/backups/etcd/main/control/x": "control",
+	} {
+		p, _ := vfs.Context.BuildVfsPath(location)
+		actual, err := p.ReadFile()
+		if err != nil {
+			t.Errorf("error reading %q: %v", location, err)
+			continue
+		}
+		if string(actual) != expected {
+			t.Errorf("unexpected contents of %q: expected %q, got %q", location, expected, actual)
+		}
+	}
+
+	newStore, _ := vfs.Context.BuildVfsPath("memfs://new-store")
+	migrated, err := vfsclientset.NewVFSClientset(newStore).GetCluster(ctx, "minimal.example.com")
+	if err != nil {
+		t.Fatalf("error reading migrated cluster: %v", err)
+	}
+	if migrated.Spec.ConfigBase != "memfs://new-store/minimal.example.com" {
+		t.Errorf("unexpected configBase %q", migrated.Spec.ConfigBase)
+	}
+	if migrated.Spec.KeyStore != "memfs://new-store/minimal.example.com/pki" {
+		t.Errorf("unexpected keyStore %q", migrated.Spec.KeyStore)
+	}
+	if migrated.Spec.SecretStore != "memfs://new-store/minimal.example.com/secrets" {
+		t.Errorf("unexpected secretStore %q", migrated.Spec.SecretStore)
+	}
+
+	// The completed cluster spec references the old state store, so it is not copied
+	if _, err := newStore.Join("minimal.example.com", "cluster.spec").ReadFile(); !os.IsNotExist(err) {
+		t.Errorf("expected cluster.spec not to be copied, got err=%v", err)
+	}
+
+	// The original state must be left in place
+	if _, err := oldStore.Join("minimal.example.com", "config").ReadFile(); err != nil {
+		t.Errorf("original cluster config was removed: %v", err)
+	}
+
+	// A second migration must not overwrite the cluster
+	if err := MigrateState(ctx, &out, cluster, &MigrateStateOptions{To: "memfs://new-store"}); err == nil {
+		t.Errorf("expected error migrating to a state store that already contains the cluster")
+	}
+}
+
+func TestMigrateStateDryRun(t *testing.T) {
+	ctx := context.TODO()
+	setupMigrateStateTest(t)
+
+	oldStore, _ := vfs.Context.BuildVfsPath("memfs://old-store")
+	cluster, err := vfsclientset.NewVFSClientset(oldStore).GetCluster(ctx, "minimal.example.com")
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := MigrateState(ctx, &out, cluster, &MigrateStateOptions{To: "memfs://new-store", DryRun: true}); err != nil {
+		t.Fatalf("error migrating state: %v", err)
+	}
+
+	newStore, _ := vfs.Context.BuildVfsPath("memfs://new-store")
+	for _, p := range []vfs.Path{
+		newStore.Join("minimal.example.com", "config"),
+		newStore.Join("minimal.example.com", "instancegroup", "nodes"),
+	} {
+		if _, err := p.ReadFile(); !os.IsNotExist(err) {
+			t.Errorf("expected %q not to be written in dry-run, got err=%v", p, err)
+		}
+	}
+
+	if !bytes.Contains(out.Bytes(), []byte("Will copy memfs://secrets-store/minimal.example.com/admin to memfs://new-store/minimal.example.com/secrets/admin")) {
+		t.Errorf("unexpected dry-run output: %s", out.String())
+	}
+}
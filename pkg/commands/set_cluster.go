@@ -37,7 +37,7 @@ type SetClusterOptions struct {
 }
 
 // RunSetCluster implements the set cluster command logic
-func RunSetCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, out io.Writer, options *SetClusterOptions) error {
+func RunSetCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, out io.Writer, options *SetClusterOptions) (err error) {
 	if !featureflag.SpecOverrideFlag.Enabled() {
 		return fmt.Errorf("set cluster command is current feature gated; set `export KOPS_FEATURE_FLAGS=SpecOverrideFlag`")
 	}
@@ -56,6 +56,13 @@ func RunSetCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, out
 		return err
 	}
 
+	lock, err := LockCluster(ctx, clientset, cluster, "set cluster")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock(&err)
+	ctx = lock.Context()
+
 	instanceGroups, err := ReadAllInstanceGroups(ctx, clientset, cluster)
 	if err != nil {
 		return err
@@ -40,7 +40,7 @@ type SetInstanceGroupOptions struct {
 }
 
 // RunSetInstancegroup implements the set instancegroup command logic.
-func RunSetInstancegroup(ctx context.Context, f *util.Factory, cmd *cobra.Command, out io.Writer, options *SetInstanceGroupOptions) error {
+func RunSetInstancegroup(ctx context.Context, f *util.Factory, cmd *cobra.Command, out io.Writer, options *SetInstanceGroupOptions) (err error) {
 	if !featureflag.SpecOverrideFlag.Enabled() {
 		return fmt.Errorf("set instancegroup is currently feature gated; set `export KOPS_FEATURE_FLAGS=SpecOverrideFlag`")
 	}
@@ -62,6 +62,13 @@ func RunSetInstancegroup(ctx context.Context, f *util.Factory, cmd *cobra.Comman
 		return err
 	}
 
+	lock, err := LockCluster(ctx, clientset, cluster, "set instancegroup")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock(&err)
+	ctx = lock.Context()
+
 	instanceGroups, err := ReadAllInstanceGroups(ctx, clientset, cluster)
 	if err != nil {
 		return err
@@ -27,6 +27,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 )
 
@@ -75,6 +77,15 @@ func (s *CloudDiscoveryStatusStore) GetApiIngressStatus(cluster *kops.Cluster) (
 		return doCloud.GetApiIngressStatus(cluster)
 	}
 
+	if hetznerCloud, ok := cloud.(hetzner.HetznerCloud); ok {
+		return hetznerCloud.GetApiIngressStatus(cluster)
+	}
+
+	if _, ok := cloud.(metal.MetalCloud); ok {
+		// Pre-provisioned hosts have no load balancer; the API is reached through spec.masterPublicName
+		return nil, nil
+	}
+
 	return nil, fmt.Errorf("API Ingress Status not implemented for %T", cloud)
 }
 
@@ -108,5 +119,14 @@ func (s *CloudDiscoveryStatusStore) FindClusterStatus(cluster *kops.Cluster) (*k
 	if doCloud, ok := cloud.(*digitalocean.Cloud); ok {
 		return doCloud.FindClusterStatus(cluster)
 	}
+
+	if hetznerCloud, ok := cloud.(hetzner.HetznerCloud); ok {
+		return hetznerCloud.FindClusterStatus(cluster)
+	}
+
+	if _, ok := cloud.(metal.MetalCloud); ok {
+		// The etcd data of pre-provisioned hosts is on their local disks, there are no volumes to inspect
+		return &kops.ClusterStatus{}, nil
+	}
 	return nil, fmt.Errorf("etcd Status not implemented for %T", cloud)
 }
@@ -0,0 +1,278 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	// MetaFilename is the file in each backup directory that describes the backup
+	MetaFilename = "_etcd_backup.meta"
+	// DataFilename is the file in each backup directory that holds the etcd snapshot
+	DataFilename = "etcd.backup.gz"
+	// CommandFilename is the file in each command directory that holds the command
+	CommandFilename = "_command.json"
+	// ClusterSpecFilename is the file in the control directory that holds the desired etcd cluster spec
+	ClusterSpecFilename = "etcd-cluster-spec"
+
+	controlDir = "control"
+)
+
+// ClusterSpec is the etcd cluster spec that etcd-manager reads from the control directory
+type ClusterSpec struct {
+	MemberCount int32  `json:"memberCount,omitempty"`
+	EtcdVersion string `json:"etcdVersion,omitempty"`
+}
+
+// Backup is a backup written by etcd-manager to the backup store
+type Backup struct {
+	// Name is the name of the backup, which is its directory in the backup store
+	Name string `json:"name"`
+	// Timestamp is when the backup was taken
+	Timestamp time.Time `json:"timestamp"`
+	// EtcdVersion is the version of etcd that took the backup
+	EtcdVersion string `json:"etcdVersion,omitempty"`
+	// ClusterSpec is the etcd cluster spec at the time of the backup
+	ClusterSpec *ClusterSpec `json:"clusterSpec,omitempty"`
+}
+
+// backupMeta is the serialized form of _etcd_backup.meta.
+// etcd-manager writes int64 fields as strings, as is usual for protobuf JSON.
+type backupMeta struct {
+	EtcdVersion string       `json:"etcdVersion,omitempty"`
+	Timestamp   int64        `json:"timestamp,string,omitempty"`
+	ClusterSpec *ClusterSpec `json:"clusterSpec,omitempty"`
+}
+
+// command is the serialized form of a command in the control directory
+type command struct {
+	Timestamp     int64                 `json:"timestamp,string,omitempty"`
+	RestoreBackup *restoreBackupCommand `json:"restoreBackup,omitempty"`
+}
+
+type restoreBackupCommand struct {
+	ClusterSpec *ClusterSpec `json:"clusterSpec,omitempty"`
+	Backup      string       `json:"backup,omitempty"`
+}
+
+// Store is the backup store of a single etcd cluster
+type Store struct {
+	base vfs.Path
+}
+
+// NewStore returns the backup store for the named etcd cluster.
+// configBase is used to build the default location when the cluster does not set a backupStore.
+func NewStore(cluster *kops.Cluster, configBase vfs.Path, etcdClusterName string) (*Store, error) {
+	var etcdCluster *kops.EtcdClusterSpec
+	for i := range cluster.Spec.EtcdClusters {
+		if cluster.Spec.EtcdClusters[i].Name == etcdClusterName {
+			etcdCluster = &cluster.Spec.EtcdClusters[i]
+		}
+	}
+	if etcdCluster == nil {
+		var names []string
+		for _, e := range cluster.Spec.EtcdClusters {
+			names = append(names, e.Name)
+		}
+		return nil, fmt.Errorf("etcd cluster %q not found in cluster %q (valid values: %s)", etcdClusterName, cluster.ObjectMeta.Name, strings.Join(names, ","))
+	}
+	if etcdCluster.Provider != "" && etcdCluster.Provider != kops.EtcdProviderTypeManager {
+		return nil, fmt.Errorf("etcd cluster %q is not managed by etcd-manager", etcdClusterName)
+	}
+
+	if etcdCluster.Backups != nil && etcdCluster.Backups.BackupStore != "" {
+		base, err := vfs.Context.BuildVfsPath(etcdCluster.Backups.BackupStore)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing backupStore %q: %v", etcdCluster.Backups.BackupStore, err)
+		}
+		return &Store{base: base}, nil
+	}
+
+	// Matches the default set in the etcdmanager options builder
+	return &Store{base: configBase.Join("backups", "etcd", etcdClusterName)}, nil
+}
+
+// NewStoreAt returns the backup store at the specified path
+func NewStoreAt(base vfs.Path) *Store {
+	return &Store{base: base}
+}
+
+// Path returns the location of the backup store
+func (s *Store) Path() vfs.Path {
+	return s.base
+}
+
+// ListBackups returns the backups in the store, oldest first
+func (s *Store) ListBackups() ([]*Backup, error) {
+	files, err := s.base.ReadTree()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing backups in %s: %v", s.base, err)
+	}
+
+	prefix := strings.TrimSuffix(s.base.Path(), "/") + "/"
+	var backups []*Backup
+	for _, f := range files {
+		if f.Base() != MetaFilename {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(f.Path(), prefix), "/"+MetaFilename)
+		if name == "" || strings.Contains(name, "/") {
+			klog.V(4).Infof("ignoring backup metadata outside of the backup store root: %s", f)
+			continue
+		}
+
+		backup, err := s.readBackup(name)
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name < backups[j].Name
+	})
+	return backups, nil
+}
+
+// GetBackup returns the named backup, verifying that both its metadata and its data are present
+func (s *Store) GetBackup(name string) (*Backup, error) {
+	if name == "" || name == controlDir || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid backup name %q", name)
+	}
+
+	backup, err := s.readBackup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// List the directory rather than reading the snapshot, which can be large
+	files, err := s.base.Join(name).ReadDir()
+	if err != nil {
+		return nil, fmt.Errorf("error listing backup %q: %v", name, err)
+	}
+	for _, f := range files {
+		if f.Base() == DataFilename {
+			return backup, nil
+		}
+	}
+	return nil, fmt.Errorf("backup %q is incomplete: %s not found", name, s.base.Join(name, DataFilename))
+}
+
+func (s *Store) readBackup(name string) (*Backup, error) {
+	p := s.base.Join(name, MetaFilename)
+	b, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("backup %q not found in %s", name, s.base)
+		}
+		return nil, fmt.Errorf("error reading %s: %v", p, err)
+	}
+
+	meta := &backupMeta{}
+	if err := json.Unmarshal(b, meta); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", p, err)
+	}
+
+	backup := &Backup{
+		Name:        name,
+		EtcdVersion: meta.EtcdVersion,
+		ClusterSpec: meta.ClusterSpec,
+	}
+	if meta.Timestamp != 0 {
+		backup.Timestamp = timestampToTime(meta.Timestamp)
+	}
+	return backup, nil
+}
+
+// timestampToTime converts a backup timestamp, which older etcd-manager versions record in seconds, to a time
+func timestampToTime(ts int64) time.Time {
+	if ts < 1e12 {
+		return time.Unix(ts, 0).UTC()
+	}
+	return time.Unix(0, ts).UTC()
+}
+
+// RestoreBackup validates the named backup and queues a command for etcd-manager to restore it.
+// etcd-manager picks up the command on its next reconciliation, and removes it once the restore is done.
+// It returns the path of the command that was written.
+func (s *Store) RestoreBackup(name string, now time.Time) (vfs.Path, error) {
+	backup, err := s.GetBackup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterSpec, err := s.readClusterSpec()
+	if err != nil {
+		return nil, err
+	}
+	if clusterSpec == nil {
+		clusterSpec = backup.ClusterSpec
+	}
+	if clusterSpec == nil {
+		return nil, fmt.Errorf("cannot determine etcd cluster spec: %s not found and backup %q does not record one", s.base.Join(controlDir, ClusterSpecFilename), name)
+	}
+
+	cmd := &command{
+		Timestamp: now.UnixNano(),
+		RestoreBackup: &restoreBackupCommand{
+			ClusterSpec: clusterSpec,
+			Backup:      name,
+		},
+	}
+	b, err := json.MarshalIndent(cmd, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing command: %v", err)
+	}
+
+	p := s.base.Join(controlDir, now.UTC().Format(time.RFC3339Nano), CommandFilename)
+	if err := p.WriteFile(bytes.NewReader(b), nil); err != nil {
+		return nil, fmt.Errorf("error writing %s: %v", p, err)
+	}
+	return p, nil
+}
+
+// readClusterSpec returns the cluster spec from the control directory, or nil if it has not been written
+func (s *Store) readClusterSpec() (*ClusterSpec, error) {
+	p := s.base.Join(controlDir, ClusterSpecFilename)
+	b, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %v", p, err)
+	}
+
+	spec := &ClusterSpec{}
+	if err := json.Unmarshal(b, spec); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", p, err)
+	}
+	return spec, nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func writeFile(t *testing.T, p vfs.Path, data string) {
+	if err := p.WriteFile(bytes.NewReader([]byte(data)), nil); err != nil {
+		t.Fatalf("error writing %s: %v", p, err)
+	}
+}
+
+func newTestStore(t *testing.T) *Store {
+	base := vfs.NewMemFSPath(vfs.NewMemFSContext(), "/state/minimal.example.com/backups/etcd/main")
+
+	writeFile(t, base.Join("control", ClusterSpecFilename), `{"memberCount": 3, "etcdVersion": "3.4.13"}`)
+
+	writeFile(t, base.Join("2021-01-15T12:00:00Z-000001", MetaFilename), `{"etcdVersion": "3.4.13", "timestamp": "1610712000", "clusterSpec": {"memberCount": 3, "etcdVersion": "3.4.13"}}`)
+	writeFile(t, base.Join("2021-01-15T12:00:00Z-000001", DataFilename), "data")
+
+	writeFile(t, base.Join("2021-01-15T11:00:00Z-000002", MetaFilename), `{"etcdVersion": "3.4.13", "timestamp": "1610708400"}`)
+	writeFile(t, base.Join("2021-01-15T11:00:00Z-000002", DataFilename), "data")
+
+	// A backup that was interrupted before the snapshot was written
+	writeFile(t, base.Join("2021-01-15T13:00:00Z-000003", MetaFilename), `{"etcdVersion": "3.4.13", "timestamp": "1610715600"}`)
+
+	return NewStoreAt(base)
+}
+
+func TestNewStore(t *testing.T) {
+	vfs.Context.ResetMemfsContext(true)
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "state/minimal.example.com")
+
+	cluster := &kops.Cluster{}
+	cluster.ObjectMeta.Name = "minimal.example.com"
+	cluster.Spec.EtcdClusters = []kops.EtcdClusterSpec{
+		{Name: "main"},
+		{Name: "events", Backups: &kops.EtcdBackupSpec{BackupStore: "memfs://backups/events"}},
+		{Name: "legacy", Provider: kops.EtcdProviderTypeLegacy},
+	}
+
+	grid := []struct {
+		name     string
+		expected string
+		err      bool
+	}{
+		{name: "main", expected: "memfs://state/minimal.example.com/backups/etcd/main"},
+		{name: "events", expected: "memfs://backups/events"},
+		{name: "legacy", err: true},
+		{name: "cilium", err: true},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			store, err := NewStore(cluster, configBase, g.name)
+			if g.err {
+				if err == nil {
+					t.Fatalf("expected error, got store %s", store.Path())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if store.Path().Path() != g.expected {
+				t.Errorf("expected store %q, got %q", g.expected, store.Path().Path())
+			}
+		})
+	}
+}
+
+func TestListBackups(t *testing.T) {
+	store := newTestStore(t)
+
+	backups, err := store.ListBackups()
+	if err != nil {
+		t.Fatalf("error listing backups: %v", err)
+	}
+
+	var names []string
+	for _, b := range backups {
+		names = append(names, b.Name)
+	}
+	expected := []string{"2021-01-15T11:00:00Z-000002", "2021-01-15T12:00:00Z-000001", "2021-01-15T13:00:00Z-000003"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected backups %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expected backups %v, got %v", expected, names)
+		}
+	}
+
+	if ts := backups[1].Timestamp; !ts.Equal(time.Date(2021, 1, 15, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected timestamp %v", ts)
+	}
+	if backups[1].EtcdVersion != "3.4.13" {
+		t.Errorf("unexpected etcd version %q", backups[1].EtcdVersion)
+	}
+
+	empty := NewStoreAt(vfs.NewMemFSPath(vfs.NewMemFSContext(), "/empty"))
+	if backups, err := empty.ListBackups(); err != nil || len(backups) != 0 {
+		t.Errorf("expected no backups in empty store, got %v, %v", backups, err)
+	}
+}
+
+func TestGetBackup(t *testing.T) {
+	store := newTestStore(t)
+
+	if _, err := store.GetBackup("2021-01-15T12:00:00Z-000001"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"", "control", "../main", "2021-01-15T13:00:00Z-000003", "2021-01-15T14:00:00Z-000004"} {
+		if _, err := store.GetBackup(name); err == nil {
+			t.Errorf("expected error getting backup %q", name)
+		}
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	store := newTestStore(t)
+	now := time.Date(2021, 1, 16, 9, 30, 0, 5, time.UTC)
+
+	p, err := store.RestoreBackup("2021-01-15T11:00:00Z-000002", now)
+	if err != nil {
+		t.Fatalf("error restoring backup: %v", err)
+	}
+	if expected := store.Path().Join("control", "2021-01-16T09:30:00.000000005Z", CommandFilename).Path(); p.Path() != expected {
+		t.Errorf("expected command at %q, got %q", expected, p.Path())
+	}
+
+	b, err := p.ReadFile()
+	if err != nil {
+		t.Fatalf("error reading command: %v", err)
+	}
+	var actual map[string]interface{}
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatalf("error parsing command: %v", err)
+	}
+	var expected map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"timestamp": "1610789400000000005",
+		"restoreBackup": {
+			"clusterSpec": {"memberCount": 3, "etcdVersion": "3.4.13"},
+			"backup": "2021-01-15T11:00:00Z-000002"
+		}
+	}`), &expected); err != nil {
+		t.Fatalf("error parsing expected command: %v", err)
+	}
+	actualJSON, _ := json.Marshal(actual)
+	expectedJSON, _ := json.Marshal(expected)
+	if string(actualJSON) != string(expectedJSON) {
+		t.Errorf("unexpected command\nexpected: %s\nactual: %s", expectedJSON, actualJSON)
+	}
+
+	if _, err := store.RestoreBackup("2021-01-15T13:00:00Z-000003", now); err == nil {
+		t.Errorf("expected error restoring incomplete backup")
+	}
+}
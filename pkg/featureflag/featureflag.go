@@ -97,6 +97,10 @@ var (
 	PublicJWKS = New("PublicJWKS", Bool(false))
 	// Azure toggles the Azure support.
 	Azure = New("Azure", Bool(false))
+	// Hetzner toggles the Hetzner Cloud support.
+	Hetzner = New("Hetzner", Bool(false))
+	// Metal toggles the support for pre-provisioned (bare-metal) hosts.
+	Metal = New("Metal", Bool(false))
 	// KopsControllerStateStore enables fetching the kops state from kops-controller, instead of requiring access to S3/GCS/etc.
 	KopsControllerStateStore = New("KopsControllerStateStore", Bool(false))
 )
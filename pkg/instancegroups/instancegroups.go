@@ -332,6 +332,11 @@ func (c *RollingUpdateCluster) patchTaint(node *corev1.Node) error {
 func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInstance, sleepAfterTerminate time.Duration) error {
 	instanceID := u.ID
 
+	// The context is cancelled if we lose the cluster lock, in which case we must not start on another instance
+	if err := c.Ctx.Err(); err != nil {
+		return fmt.Errorf("not updating instance %q: %v", instanceID, err)
+	}
+
 	nodeName := ""
 	if u.Node != nil {
 		nodeName = u.Node.Name
@@ -393,7 +398,8 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 
 func (c *RollingUpdateCluster) reconcileInstanceGroup() error {
 	if api.CloudProviderID(c.Cluster.Spec.CloudProvider) != api.CloudProviderOpenstack &&
-		api.CloudProviderID(c.Cluster.Spec.CloudProvider) != api.CloudProviderDO {
+		api.CloudProviderID(c.Cluster.Spec.CloudProvider) != api.CloudProviderDO &&
+		api.CloudProviderID(c.Cluster.Spec.CloudProvider) != api.CloudProviderHetzner {
 		return nil
 	}
 	rto := fi.RunTasksOptions{}
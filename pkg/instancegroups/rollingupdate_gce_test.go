@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	compute "google.golang.org/api/compute/v1"
+	v1 "k8s.io/api/core/v1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/validation"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+)
+
+const testZoneGCE = "us-test1-a"
+
+func getTestSetupGCE(t *testing.T) (*RollingUpdateCluster, gce.GCECloud) {
+	k8sClient := fake.NewSimpleClientset()
+
+	cloud := gce.InstallMockGCECloud("us-test1", "testproject")
+	t.Cleanup(cloud.MockCompute.TeardownHTTP)
+
+	cluster := &kopsapi.Cluster{}
+	cluster.Name = "test.k8s.local"
+
+	c := &RollingUpdateCluster{
+		Ctx:                     context.Background(),
+		Cluster:                 cluster,
+		Cloud:                   cloud,
+		MasterInterval:          1 * time.Millisecond,
+		NodeInterval:            1 * time.Millisecond,
+		BastionInterval:         1 * time.Millisecond,
+		Force:                   false,
+		K8sClient:               k8sClient,
+		ClusterValidator:        &successfulClusterValidator{},
+		FailOnValidate:          true,
+		ValidateTickDuration:    1 * time.Millisecond,
+		ValidateSuccessDuration: 5 * time.Millisecond,
+		ValidateCount:           2,
+	}
+
+	return c, cloud
+}
+
+// makeGroupGCE creates a MIG with count instances, of which needUpdate are running an outdated template,
+// and returns the cloud groups kops finds for it
+func makeGroupGCE(t *testing.T, c *RollingUpdateCluster, cloud gce.GCECloud, name string, count int, needUpdate int) map[string]*cloudinstances.CloudInstanceGroup {
+	ig := &kopsapi.InstanceGroup{
+		ObjectMeta: v1meta.ObjectMeta{
+			Name: name,
+		},
+		Spec: kopsapi.InstanceGroupSpec{
+			Role: kopsapi.InstanceGroupRoleNode,
+		},
+	}
+
+	oldTemplate := createInstanceTemplateGCE(t, cloud, c.Cluster, name+"-1")
+	newTemplate := createInstanceTemplateGCE(t, cloud, c.Cluster, name+"-2")
+
+	migName := gce.NameForInstanceGroupManager(c.Cluster, ig, testZoneGCE)
+	mig := &compute.InstanceGroupManager{
+		Name:             migName,
+		BaseInstanceName: name,
+		InstanceTemplate: oldTemplate,
+		TargetSize:       int64(needUpdate),
+		ForceSendFields:  []string{"TargetSize"},
+	}
+	doOpGCE(t, cloud)(cloud.Compute().InstanceGroupManagers.Insert(cloud.Project(), testZoneGCE, mig).Do())
+	doOpGCE(t, cloud)(cloud.Compute().InstanceGroupManagers.SetInstanceTemplate(cloud.Project(), testZoneGCE, migName, &compute.InstanceGroupManagersSetInstanceTemplateRequest{
+		InstanceTemplate: newTemplate,
+	}).Do())
+	doOpGCE(t, cloud)(cloud.Compute().InstanceGroupManagers.Resize(cloud.Project(), testZoneGCE, migName, int64(count)).Do())
+
+	fakeClient := c.K8sClient.(*fake.Clientset)
+	var nodes []v1.Node
+	for _, instance := range listInstancesGCE(t, cloud) {
+		node := v1.Node{
+			ObjectMeta: v1meta.ObjectMeta{Name: instance.Name + ".local"},
+			Spec: v1.NodeSpec{
+				ProviderID: "gce://" + cloud.Project() + "/" + testZoneGCE + "/" + instance.Name,
+			},
+		}
+		_ = fakeClient.Tracker().Add(&node)
+		nodes = append(nodes, node)
+	}
+
+	groups, err := cloud.GetCloudGroups(c.Cluster, []*kopsapi.InstanceGroup{ig}, false, nodes)
+	if err != nil {
+		t.Fatalf("error getting cloud groups: %v", err)
+	}
+	return groups
+}
+
+func createInstanceTemplateGCE(t *testing.T, cloud gce.GCECloud, cluster *kopsapi.Cluster, name string) string {
+	template := &compute.InstanceTemplate{
+		Name: name,
+		Properties: &compute.InstanceProperties{
+			Metadata: &compute.Metadata{
+				Items: []*compute.MetadataItems{
+					{Key: "cluster-name", Value: fi.String(cluster.Name)},
+				},
+			},
+		},
+	}
+	doOpGCE(t, cloud)(cloud.Compute().InstanceTemplates.Insert(cloud.Project(), template).Do())
+
+	return "https://www.googleapis.com/compute/v1/projects/" + cloud.Project() + "/global/instanceTemplates/" + name
+}
+
+func doOpGCE(t *testing.T, cloud gce.GCECloud) func(op *compute.Operation, err error) {
+	return func(op *compute.Operation, err error) {
+		if err == nil {
+			err = cloud.WaitForOp(op)
+		}
+		if err != nil {
+			t.Fatalf("error calling mock compute API: %v", err)
+		}
+	}
+}
+
+func listInstancesGCE(t *testing.T, cloud gce.GCECloud) []*compute.Instance {
+	instances, err := cloud.Compute().Instances.List(cloud.Project(), testZoneGCE).Do()
+	if err != nil {
+		t.Fatalf("error listing instances: %v", err)
+	}
+	return instances.Items
+}
+
+// assertGroupUpToDateGCE checks that the group has the expected number of instances, all running its current template,
+// and that no other instance is left running
+func assertGroupUpToDateGCE(t *testing.T, cloud gce.GCECloud, group *cloudinstances.CloudInstanceGroup, expected int) {
+	migName := group.Raw.(*compute.InstanceGroupManager).Name
+	mig, err := cloud.Compute().InstanceGroupManagers.Get(cloud.Project(), testZoneGCE, migName).Do()
+	if err != nil {
+		t.Fatalf("error getting MIG: %v", err)
+	}
+	assert.Equal(t, int64(expected), mig.TargetSize, "target size")
+
+	managed, err := gce.ListManagedInstances(cloud, mig)
+	if err != nil {
+		t.Fatalf("error listing managed instances: %v", err)
+	}
+	assert.Len(t, managed, expected, "managed instances")
+	for _, i := range managed {
+		assert.Equal(t, mig.InstanceTemplate, i.Version.InstanceTemplate, "template of instance %s", i.Instance)
+	}
+
+	assert.Len(t, listInstancesGCE(t, cloud), expected, "instances")
+}
+
+func TestRollingUpdateAllNeedUpdateGCE(t *testing.T) {
+	c, cloud := getTestSetupGCE(t)
+
+	groups := makeGroupGCE(t, c, cloud, "nodes", 3, 3)
+	group := groups[gce.NameForInstanceGroupManager(c.Cluster, &kopsapi.InstanceGroup{ObjectMeta: v1meta.ObjectMeta{Name: "nodes"}}, testZoneGCE)]
+	if assert.NotNil(t, group, "cloud group") {
+		assert.Len(t, group.NeedUpdate, 3, "instances needing update")
+	}
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupUpToDateGCE(t, cloud, group, 3)
+}
+
+// surgeCountingValidator records the largest number of instances seen while validating
+type surgeCountingValidator struct {
+	t     *testing.T
+	cloud gce.GCECloud
+	max   int
+}
+
+func (v *surgeCountingValidator) Validate() (*validation.ValidationCluster, error) {
+	if n := len(listInstancesGCE(v.t, v.cloud)); n > v.max {
+		v.max = n
+	}
+	return &validation.ValidationCluster{}, nil
+}
+
+func TestRollingUpdateMaxSurgeGCE(t *testing.T) {
+	c, cloud := getTestSetupGCE(t)
+
+	validator := &surgeCountingValidator{t: t, cloud: cloud}
+	c.ClusterValidator = validator
+
+	two := intstr.FromInt(2)
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxSurge: &two,
+	}
+
+	groups := makeGroupGCE(t, c, cloud, "nodes", 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	for _, group := range groups {
+		assertGroupUpToDateGCE(t, cloud, group, 3)
+	}
+	assert.Equal(t, 5, validator.max, "instances while surging")
+}
+
+func TestRollingUpdateMaxSurgeGreaterThanNeedUpdateGCE(t *testing.T) {
+	c, cloud := getTestSetupGCE(t)
+
+	validator := &surgeCountingValidator{t: t, cloud: cloud}
+	c.ClusterValidator = validator
+
+	ten := intstr.FromInt(10)
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxSurge: &ten,
+	}
+
+	groups := makeGroupGCE(t, c, cloud, "nodes", 3, 2)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	for _, group := range groups {
+		assertGroupUpToDateGCE(t, cloud, group, 3)
+	}
+	assert.Equal(t, 5, validator.max, "instances while surging")
+}
+
+func TestRollingUpdateDetachedInstanceGCE(t *testing.T) {
+	c, cloud := getTestSetupGCE(t)
+
+	groups := makeGroupGCE(t, c, cloud, "nodes", 3, 3)
+	for _, group := range groups {
+		// Simulate a previous rolling update that was interrupted after surging
+		if err := cloud.DetachInstance(group.NeedUpdate[0]); err != nil {
+			t.Fatalf("error detaching instance: %v", err)
+		}
+	}
+
+	ig := &kopsapi.InstanceGroup{
+		ObjectMeta: v1meta.ObjectMeta{Name: "nodes"},
+		Spec:       kopsapi.InstanceGroupSpec{Role: kopsapi.InstanceGroupRoleNode},
+	}
+	groups, err := cloud.GetCloudGroups(c.Cluster, []*kopsapi.InstanceGroup{ig}, false, nil)
+	if err != nil {
+		t.Fatalf("error getting cloud groups: %v", err)
+	}
+	for _, group := range groups {
+		assert.Len(t, group.Ready, 1, "ready instances")
+		if assert.Len(t, group.NeedUpdate, 3, "instances needing update") {
+			assert.Equal(t, cloudinstances.CloudInstanceStatusDetached, group.NeedUpdate[2].Status, "status of detached instance")
+		}
+	}
+
+	one := intstr.FromInt(1)
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxSurge: &one,
+	}
+
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	for _, group := range groups {
+		assertGroupUpToDateGCE(t, cloud, group, 3)
+	}
+}
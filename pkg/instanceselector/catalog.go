@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instanceselector
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/aws/amazon-ec2-instance-selector/v2/pkg/selector"
+)
+
+const (
+	// ArchitectureX8664 is the x86_64 value of the cpu-architecture filter
+	ArchitectureX8664 = "x86_64"
+	// ArchitectureArm64 is the arm64 value of the cpu-architecture filter
+	ArchitectureArm64 = "arm64"
+
+	// flexibleDefaultVCPUs is the number of vcpus selected by the flexible filter when no resources are given
+	flexibleDefaultVCPUs = 4
+)
+
+// MachineType is an entry of an offline machine type catalog
+type MachineType struct {
+	Name      string
+	VCPUs     int
+	MemoryMiB uint64
+	GPUs      int
+	// Architecture is ArchitectureX8664 or ArchitectureArm64
+	Architecture string
+	Burstable    bool
+}
+
+// Catalog selects machine types from an offline list, for clouds without an API like the EC2 instance selector
+type Catalog struct {
+	MachineTypes []MachineType
+	// FlexibleAllowList limits the flexible filter to general purpose machine types, unless an allow list is given
+	FlexibleAllowList *regexp.Regexp
+}
+
+// Filter returns the names of the machine types matching the filters, ordered from the smallest machine type.
+// Filters relying on EC2-only features are rejected.
+func (c *Catalog) Filter(filters selector.Filters) ([]string, error) {
+	if err := checkSupportedFilters(filters); err != nil {
+		return nil, err
+	}
+
+	filters, err := c.transformBaseInstanceType(filters)
+	if err != nil {
+		return nil, err
+	}
+	filters = c.transformFlexible(filters)
+
+	architecture := ""
+	if filters.CPUArchitecture != nil {
+		architecture = normalizeArchitecture(*filters.CPUArchitecture)
+	}
+
+	var matches []MachineType
+	for _, m := range c.MachineTypes {
+		if !inIntRange(m.VCPUs, filters.VCpusRange) {
+			continue
+		}
+		if filters.MemoryRange != nil && (m.MemoryMiB < filters.MemoryRange.LowerBound.Quantity || m.MemoryMiB > filters.MemoryRange.UpperBound.Quantity) {
+			continue
+		}
+		if filters.VCpusToMemoryRatio != nil && vcpusToMemoryRatio(m) != *filters.VCpusToMemoryRatio {
+			continue
+		}
+		if !inIntRange(m.GPUs, filters.GpusRange) {
+			continue
+		}
+		if architecture != "" && m.Architecture != architecture {
+			continue
+		}
+		if filters.Burstable != nil && m.Burstable != *filters.Burstable {
+			continue
+		}
+		if filters.AllowList != nil && !filters.AllowList.MatchString(m.Name) {
+			continue
+		}
+		if filters.DenyList != nil && filters.DenyList.MatchString(m.Name) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].VCPUs != matches[j].VCPUs {
+			return matches[i].VCPUs < matches[j].VCPUs
+		}
+		if matches[i].MemoryMiB != matches[j].MemoryMiB {
+			return matches[i].MemoryMiB < matches[j].MemoryMiB
+		}
+		return matches[i].Name < matches[j].Name
+	})
+
+	var names []string
+	for _, m := range matches {
+		if filters.MaxResults != nil && len(names) >= *filters.MaxResults {
+			break
+		}
+		names = append(names, m.Name)
+	}
+	return names, nil
+}
+
+// checkSupportedFilters returns an error for filters that only apply to EC2
+func checkSupportedFilters(filters selector.Filters) error {
+	if filters.UsageClass != nil && *filters.UsageClass == "spot" {
+		return fmt.Errorf("usage class spot is only supported on AWS")
+	}
+	unsupported := map[string]bool{
+		"placement-group-strategy": filters.PlacementGroupStrategy != nil,
+		"ena-support":              filters.EnaSupport != nil,
+		"network-interfaces":       filters.NetworkInterfaces != nil,
+		"network-performance":      filters.NetworkPerformance != nil,
+		"gpu-memory":               filters.GpuMemoryRange != nil,
+	}
+	var names []string
+	for name, set := range unsupported {
+		if set {
+			names = append(names, name)
+		}
+	}
+	if len(names) != 0 {
+		sort.Strings(names)
+		return fmt.Errorf("filters %v are only supported on AWS", names)
+	}
+	return nil
+}
+
+// transformBaseInstanceType replaces the base instance type with resource filters around its specs,
+// in the same way as the EC2 instance selector
+func (c *Catalog) transformBaseInstanceType(filters selector.Filters) (selector.Filters, error) {
+	if filters.InstanceTypeBase == nil {
+		return filters, nil
+	}
+
+	var base *MachineType
+	for i := range c.MachineTypes {
+		if c.MachineTypes[i].Name == *filters.InstanceTypeBase {
+			base = &c.MachineTypes[i]
+		}
+	}
+	if base == nil {
+		return filters, fmt.Errorf("error instance type %s is not a valid instance type", *filters.InstanceTypeBase)
+	}
+
+	if filters.CPUArchitecture == nil {
+		filters.CPUArchitecture = &base.Architecture
+	}
+	if filters.GpusRange == nil {
+		filters.GpusRange = &selector.IntRangeFilter{LowerBound: base.GPUs, UpperBound: base.GPUs}
+	}
+	if filters.MemoryRange == nil {
+		filters.MemoryRange = &selector.ByteQuantityRangeFilter{}
+		filters.MemoryRange.LowerBound.Quantity = uint64(float64(base.MemoryMiB) * selector.AggregateLowPercentile)
+		filters.MemoryRange.UpperBound.Quantity = uint64(float64(base.MemoryMiB) * selector.AggregateHighPercentile)
+	}
+	if filters.VCpusRange == nil {
+		filters.VCpusRange = &selector.IntRangeFilter{
+			LowerBound: int(float64(base.VCPUs) * selector.AggregateLowPercentile),
+			UpperBound: int(float64(base.VCPUs) * selector.AggregateHighPercentile),
+		}
+	}
+	filters.InstanceTypeBase = nil
+
+	return filters, nil
+}
+
+// transformFlexible applies the opinionated defaults of the flexible filter
+func (c *Catalog) transformFlexible(filters selector.Filters) selector.Filters {
+	if filters.Flexible == nil || !*filters.Flexible {
+		return filters
+	}
+	if filters.CPUArchitecture == nil {
+		architecture := ArchitectureX8664
+		filters.CPUArchitecture = &architecture
+	}
+	if filters.AllowList == nil {
+		filters.AllowList = c.FlexibleAllowList
+	}
+	if filters.VCpusRange == nil && filters.MemoryRange == nil {
+		filters.VCpusRange = &selector.IntRangeFilter{LowerBound: flexibleDefaultVCPUs, UpperBound: flexibleDefaultVCPUs}
+	}
+	return filters
+}
+
+func inIntRange(value int, target *selector.IntRangeFilter) bool {
+	if target == nil {
+		return true
+	}
+	return value >= target.LowerBound && value <= target.UpperBound
+}
+
+// vcpusToMemoryRatio computes the ratio of vcpus to GiB of memory, in the same way as the EC2 instance selector
+func vcpusToMemoryRatio(m MachineType) float64 {
+	if m.VCPUs == 0 {
+		return 0
+	}
+	return math.Ceil(float64(m.MemoryMiB) / float64(m.VCPUs*1024))
+}
+
+func normalizeArchitecture(architecture string) string {
+	switch architecture {
+	case "amd64":
+		return ArchitectureX8664
+	default:
+		return architecture
+	}
+}
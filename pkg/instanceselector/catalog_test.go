@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instanceselector
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/aws/amazon-ec2-instance-selector/v2/pkg/bytequantity"
+	"github.com/aws/amazon-ec2-instance-selector/v2/pkg/selector"
+)
+
+func testCatalog() *Catalog {
+	return &Catalog{
+		MachineTypes: []MachineType{
+			{Name: "small-2", VCPUs: 2, MemoryMiB: 2048, Architecture: ArchitectureX8664, Burstable: true},
+			{Name: "standard-2", VCPUs: 2, MemoryMiB: 8192, Architecture: ArchitectureX8664},
+			{Name: "standard-4", VCPUs: 4, MemoryMiB: 16384, Architecture: ArchitectureX8664},
+			{Name: "highmem-4", VCPUs: 4, MemoryMiB: 32768, Architecture: ArchitectureX8664},
+			{Name: "arm-4", VCPUs: 4, MemoryMiB: 16384, Architecture: ArchitectureArm64},
+			{Name: "gpu-8", VCPUs: 8, MemoryMiB: 61440, GPUs: 1, Architecture: ArchitectureX8664},
+		},
+		FlexibleAllowList: regexp.MustCompile(`^standard-`),
+	}
+}
+
+func TestCatalogFilter(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	stringPtr := func(s string) *string { return &s }
+	float64Ptr := func(f float64) *float64 { return &f }
+	intPtr := func(i int) *int { return &i }
+
+	memoryRange := &selector.ByteQuantityRangeFilter{
+		LowerBound: bytequantity.FromGiB(8),
+		UpperBound: bytequantity.FromGiB(16),
+	}
+
+	grid := []struct {
+		name     string
+		filters  selector.Filters
+		expected []string
+	}{
+		{
+			name:     "no filters",
+			filters:  selector.Filters{},
+			expected: []string{"small-2", "standard-2", "arm-4", "standard-4", "highmem-4", "gpu-8"},
+		},
+		{
+			name:     "vcpus",
+			filters:  selector.Filters{VCpusRange: &selector.IntRangeFilter{LowerBound: 4, UpperBound: 4}, CPUArchitecture: stringPtr("amd64")},
+			expected: []string{"standard-4", "highmem-4"},
+		},
+		{
+			name:     "memory",
+			filters:  selector.Filters{MemoryRange: memoryRange},
+			expected: []string{"standard-2", "arm-4", "standard-4"},
+		},
+		{
+			name:     "ratio",
+			filters:  selector.Filters{VCpusToMemoryRatio: float64Ptr(8)},
+			expected: []string{"highmem-4", "gpu-8"},
+		},
+		{
+			name:     "arm64",
+			filters:  selector.Filters{CPUArchitecture: stringPtr(ArchitectureArm64)},
+			expected: []string{"arm-4"},
+		},
+		{
+			name:     "gpus",
+			filters:  selector.Filters{GpusRange: &selector.IntRangeFilter{LowerBound: 1, UpperBound: 8}},
+			expected: []string{"gpu-8"},
+		},
+		{
+			name:     "burstable",
+			filters:  selector.Filters{Burstable: boolPtr(false), DenyList: regexp.MustCompile(`^highmem-`)},
+			expected: []string{"standard-2", "arm-4", "standard-4", "gpu-8"},
+		},
+		{
+			name:     "base instance type",
+			filters:  selector.Filters{InstanceTypeBase: stringPtr("standard-4")},
+			expected: []string{"standard-4"},
+		},
+		{
+			name:     "flexible",
+			filters:  selector.Filters{Flexible: boolPtr(true)},
+			expected: []string{"standard-4"},
+		},
+		{
+			name:     "flexible with memory",
+			filters:  selector.Filters{Flexible: boolPtr(true), MemoryRange: memoryRange},
+			expected: []string{"standard-2", "standard-4"},
+		},
+		{
+			name:     "max results",
+			filters:  selector.Filters{MaxResults: intPtr(2)},
+			expected: []string{"small-2", "standard-2"},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			actual, err := testCatalog().Filter(g.filters)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, g.expected) {
+				t.Errorf("expected %v, got %v", g.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCatalogFilterErrors(t *testing.T) {
+	spot := "spot"
+	unknown := "unknown-8"
+	enaSupport := true
+
+	grid := []selector.Filters{
+		{UsageClass: &spot},
+		{EnaSupport: &enaSupport},
+		{InstanceTypeBase: &unknown},
+	}
+	for _, filters := range grid {
+		if _, err := testCatalog().Filter(filters); err == nil {
+			t.Errorf("expected error for filters %+v", filters)
+		}
+	}
+}
+
+func TestCloudCatalogs(t *testing.T) {
+	for name, catalog := range map[string]*Catalog{"gce": NewGCECatalog(), "azure": NewAzureCatalog()} {
+		flexible := true
+		selected, err := catalog.Filter(selector.Filters{Flexible: &flexible})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if len(selected) == 0 {
+			t.Errorf("%s: expected the flexible filter to select machine types", name)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instanceselector
+
+import (
+	"regexp"
+
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+var (
+	// gceFlexibleAllowList matches the general purpose GCE machine types
+	gceFlexibleAllowList = regexp.MustCompile(`^(e2|n1|n2|n2d)-standard-[0-9]+$`)
+	// azureFlexibleAllowList matches the general purpose Azure VM sizes
+	azureFlexibleAllowList = regexp.MustCompile(`^Standard_D[0-9]+a?s?_v[3-5]$`)
+)
+
+// NewGCECatalog returns a catalog of the GCE machine types known to kops
+func NewGCECatalog() *Catalog {
+	c := &Catalog{FlexibleAllowList: gceFlexibleAllowList}
+	for _, m := range gce.MachineTypes() {
+		c.MachineTypes = append(c.MachineTypes, MachineType{
+			Name:         m.Name,
+			VCPUs:        m.Cores,
+			MemoryMiB:    memoryMiB(m.MemoryGB),
+			GPUs:         m.GPUs,
+			Architecture: selectorArchitecture(m.Architecture),
+			Burstable:    m.Burstable,
+		})
+	}
+	return c
+}
+
+// NewAzureCatalog returns a catalog of the Azure VM sizes known to kops
+func NewAzureCatalog() *Catalog {
+	c := &Catalog{FlexibleAllowList: azureFlexibleAllowList}
+	for _, m := range azure.MachineTypes() {
+		c.MachineTypes = append(c.MachineTypes, MachineType{
+			Name:         m.Name,
+			VCPUs:        m.Cores,
+			MemoryMiB:    memoryMiB(m.MemoryGB),
+			GPUs:         m.GPUs,
+			Architecture: selectorArchitecture(m.Architecture),
+			Burstable:    m.Burstable,
+		})
+	}
+	return c
+}
+
+func memoryMiB(memoryGB float32) uint64 {
+	return uint64(float64(memoryGB)*1024 + 0.5)
+}
+
+func selectorArchitecture(architecture architectures.Architecture) string {
+	if architecture == architectures.ArchitectureArm64 {
+		return ArchitectureArm64
+	}
+	return ArchitectureX8664
+}
@@ -219,6 +219,25 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 
 		nlb.CrossZoneLoadBalancing = lbSpec.CrossZoneLoadBalancing
 
+		if lbSpec.AccessLog != nil {
+			clb.AccessLog = &awstasks.ClassicLoadBalancerAccessLog{
+				Enabled:      fi.Bool(true),
+				S3BucketName: fi.String(lbSpec.AccessLog.Bucket),
+				EmitInterval: fi.Int64(60),
+			}
+			if lbSpec.AccessLog.Interval != 0 {
+				clb.AccessLog.EmitInterval = fi.Int64(int64(lbSpec.AccessLog.Interval))
+			}
+			nlb.AccessLog = &awstasks.NetworkLoadBalancerAccessLog{
+				Enabled:      fi.Bool(true),
+				S3BucketName: fi.String(lbSpec.AccessLog.Bucket),
+			}
+			if lbSpec.AccessLog.BucketPrefix != "" {
+				clb.AccessLog.S3BucketPrefix = fi.String(lbSpec.AccessLog.BucketPrefix)
+				nlb.AccessLog.S3BucketPrefix = fi.String(lbSpec.AccessLog.BucketPrefix)
+			}
+		}
+
 		switch lbSpec.Type {
 		case kops.LoadBalancerTypeInternal:
 			clb.Scheme = fi.String("internal")
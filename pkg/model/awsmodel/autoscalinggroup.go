@@ -166,6 +166,26 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.ModelBuilde
 		lt.CPUCredits = fi.String("")
 	}
 
+	if ig.Spec.PlacementGroup != nil {
+		pg := &awstasks.PlacementGroup{
+			Name:      fi.String(name),
+			Lifecycle: b.Lifecycle,
+			Strategy:  fi.String(ig.Spec.PlacementGroup.Strategy),
+			Tags:      b.CloudTags(name, false),
+		}
+		if ig.Spec.PlacementGroup.Strategy == kops.PlacementStrategyPartition {
+			pg.PartitionCount = ig.Spec.PlacementGroup.PartitionCount
+		}
+		c.AddTask(pg)
+		lt.PlacementGroup = pg
+	}
+
+	if cr := ig.Spec.CapacityReservation; cr != nil {
+		lt.CapacityReservationPreference = cr.Preference
+		lt.CapacityReservationID = cr.ID
+		lt.CapacityReservationResourceGroupARN = cr.ResourceGroupARN
+	}
+
 	return lt, nil
 }
 
@@ -467,5 +487,15 @@ func (b *AutoscalingGroupModelBuilder) buildAutoScalingGroupTask(c *fi.ModelBuil
 		t.MixedSpotMaxPrice = ig.Spec.MaxPrice
 	}
 
+	t.WarmPoolEnabled = fi.Bool(ig.Spec.WarmPool != nil)
+	if warmPool := ig.Spec.WarmPool; warmPool != nil {
+		t.WarmPoolMinSize = fi.Int64(warmPool.MinSize)
+		t.WarmPoolMaxPreparedCapacity = warmPool.MaxPreparedCapacity
+		t.WarmPoolState = fi.String(kops.WarmPoolStateStopped)
+		if warmPool.PoolState != nil {
+			t.WarmPoolState = warmPool.PoolState
+		}
+	}
+
 	return t, nil
 }
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsmodel
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/util/stringorslice"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+// FlowLogsModelBuilder configures the flow logs of the VPC of the cluster
+type FlowLogsModelBuilder struct {
+	*AWSModelContext
+
+	Lifecycle         *fi.Lifecycle
+	SecurityLifecycle *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &FlowLogsModelBuilder{}
+
+// Build is responsible for creating the flow log, and the log group and IAM role of the CloudWatchLogs destination
+func (b *FlowLogsModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	spec := b.Cluster.Spec.FlowLogs
+	if spec == nil {
+		return nil
+	}
+
+	name := "flowlogs." + b.ClusterName()
+
+	flowLog := &awstasks.FlowLog{
+		Name:        fi.String(name),
+		Lifecycle:   b.Lifecycle,
+		VPC:         b.LinkToVPC(),
+		TrafficType: fi.String("ALL"),
+		Tags:        b.CloudTags(name, false),
+	}
+	if spec.TrafficType != "" {
+		flowLog.TrafficType = fi.String(spec.TrafficType)
+	}
+
+	if spec.Destination == kops.FlowLogsDestinationS3 {
+		destination := "arn:" + b.AWSPartition + ":s3:::" + spec.Bucket
+		if spec.BucketPrefix != "" {
+			destination += "/" + spec.BucketPrefix
+		}
+		flowLog.LogDestinationType = fi.String(ec2.LogDestinationTypeS3)
+		flowLog.LogDestination = fi.String(destination)
+		c.AddTask(flowLog)
+		return nil
+	}
+
+	logGroup := &awstasks.LogGroup{
+		Name:            fi.String(name),
+		Lifecycle:       b.Lifecycle,
+		RetentionInDays: spec.RetentionInDays,
+		Tags:            b.CloudTags(name, false),
+	}
+	c.AddTask(logGroup)
+
+	iamRole, err := b.buildFlowLogsIAMRole(c, name)
+	if err != nil {
+		return err
+	}
+
+	flowLog.LogDestinationType = fi.String(ec2.LogDestinationTypeCloudWatchLogs)
+	flowLog.LogGroup = logGroup
+	flowLog.IAMRole = iamRole
+	c.AddTask(flowLog)
+
+	return nil
+}
+
+// buildFlowLogsIAMRole creates the IAM role assumed by the flow logs service to publish to the log group
+func (b *FlowLogsModelBuilder) buildFlowLogsIAMRole(c *fi.ModelBuilderContext, name string) (*awstasks.IAMRole, error) {
+	assumeRolePolicy := &iam.Policy{
+		Version: iam.PolicyDefaultVersion,
+		Statement: []*iam.Statement{
+			{
+				Effect:    iam.StatementEffectAllow,
+				Principal: iam.Principal{Service: "vpc-flow-logs.amazonaws.com"},
+				Action:    stringorslice.Of("sts:AssumeRole"),
+			},
+		},
+	}
+	assumeRolePolicyJSON, err := assumeRolePolicy.AsJSON()
+	if err != nil {
+		return nil, fmt.Errorf("error building IAM policy: %v", err)
+	}
+
+	iamRole := &awstasks.IAMRole{
+		Name:               fi.String(name),
+		Lifecycle:          b.SecurityLifecycle,
+		RolePolicyDocument: fi.NewStringResource(assumeRolePolicyJSON),
+		Tags:               b.CloudTags(name, false),
+	}
+	if b.Cluster.Spec.IAM != nil && b.Cluster.Spec.IAM.PermissionsBoundary != nil {
+		iamRole.PermissionsBoundary = b.Cluster.Spec.IAM.PermissionsBoundary
+	}
+	c.AddTask(iamRole)
+
+	logGroupARN := fmt.Sprintf("arn:%s:logs:%s:%s:log-group:%s", b.AWSPartition, b.Region, b.AWSAccountID, name)
+	policy := &iam.Policy{
+		Version: iam.PolicyDefaultVersion,
+		Statement: []*iam.Statement{
+			{
+				Effect: iam.StatementEffectAllow,
+				Action: stringorslice.Of(
+					"logs:CreateLogStream",
+					"logs:DescribeLogStreams",
+					"logs:PutLogEvents",
+				),
+				Resource: stringorslice.Of(logGroupARN, logGroupARN+":*"),
+			},
+		},
+	}
+	policyJSON, err := policy.AsJSON()
+	if err != nil {
+		return nil, fmt.Errorf("error building IAM policy: %v", err)
+	}
+
+	c.AddTask(&awstasks.IAMRolePolicy{
+		Name:           fi.String(name),
+		Lifecycle:      b.SecurityLifecycle,
+		Role:           iamRole,
+		PolicyDocument: fi.NewStringResource(policyJSON),
+	})
+
+	return iamRole, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsmodel
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+// VPCEndpointModelBuilder configures the VPC endpoints of the cluster
+type VPCEndpointModelBuilder struct {
+	*AWSModelContext
+
+	Lifecycle         *fi.Lifecycle
+	SecurityLifecycle *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &VPCEndpointModelBuilder{}
+
+// Build is responsible for creating the VPC endpoints, and the security group of the interface endpoints
+func (b *VPCEndpointModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	if len(b.Cluster.Spec.VPCEndpoints) == 0 {
+		return nil
+	}
+
+	var securityGroup *awstasks.SecurityGroup
+	for i := range b.Cluster.Spec.VPCEndpoints {
+		endpoint := &b.Cluster.Spec.VPCEndpoints[i]
+		name := endpoint.Service + "." + b.ClusterName()
+
+		t := &awstasks.VPCEndpoint{
+			Name:            fi.String(name),
+			Lifecycle:       b.Lifecycle,
+			VPC:             b.LinkToVPC(),
+			ServiceName:     fi.String("com.amazonaws." + b.Region + "." + endpoint.Service),
+			VPCEndpointType: fi.String(endpoint.EndpointType()),
+			Tags:            b.CloudTags(name, false),
+		}
+
+		switch endpoint.EndpointType() {
+		case kops.VPCEndpointTypeGateway:
+			t.RouteTables = b.vpcEndpointRouteTables()
+
+		case kops.VPCEndpointTypeInterface:
+			if securityGroup == nil {
+				var err error
+				securityGroup, err = b.buildVPCEndpointSecurityGroup(c)
+				if err != nil {
+					return err
+				}
+			}
+			t.SecurityGroups = []*awstasks.SecurityGroup{securityGroup}
+			t.Subnets = b.vpcEndpointSubnets()
+
+			t.PrivateDNSEnabled = endpoint.PrivateDNSEnabled
+			if t.PrivateDNSEnabled == nil {
+				// Interface endpoints of s3 don't support private DNS
+				t.PrivateDNSEnabled = fi.Bool(endpoint.Service != "s3")
+			}
+		}
+
+		c.AddTask(t)
+	}
+
+	return nil
+}
+
+// vpcEndpointRouteTables returns the route tables managed by kops, which gateway endpoints are attached to
+func (b *VPCEndpointModelBuilder) vpcEndpointRouteTables() []*awstasks.RouteTable {
+	var routeTables []*awstasks.RouteTable
+	seen := make(map[string]bool)
+	for i := range b.Cluster.Spec.Subnets {
+		subnet := &b.Cluster.Spec.Subnets[i]
+		if subnet.ProviderID != "" || subnet.Egress == kops.EgressExternal {
+			continue
+		}
+
+		var rt *awstasks.RouteTable
+		switch subnet.Type {
+		case kops.SubnetTypePublic, kops.SubnetTypeUtility:
+			rt = &awstasks.RouteTable{Name: fi.String(b.ClusterName())}
+		case kops.SubnetTypePrivate:
+			rt = b.LinkToPrivateRouteTableInZone(subnet.Zone)
+		default:
+			continue
+		}
+
+		if seen[fi.StringValue(rt.Name)] {
+			continue
+		}
+		seen[fi.StringValue(rt.Name)] = true
+		routeTables = append(routeTables, rt)
+	}
+	return routeTables
+}
+
+// vpcEndpointSubnets returns one subnet per zone for the network interfaces of interface endpoints,
+// preferring private subnets over utility and public subnets
+func (b *VPCEndpointModelBuilder) vpcEndpointSubnets() []*awstasks.Subnet {
+	preference := map[kops.SubnetType]int{
+		kops.SubnetTypePrivate: 3,
+		kops.SubnetTypeUtility: 2,
+		kops.SubnetTypePublic:  1,
+	}
+
+	var zones []string
+	subnetsByZone := make(map[string]*kops.ClusterSubnetSpec)
+	for i := range b.Cluster.Spec.Subnets {
+		subnet := &b.Cluster.Spec.Subnets[i]
+		current := subnetsByZone[subnet.Zone]
+		if current == nil {
+			zones = append(zones, subnet.Zone)
+		}
+		if current == nil || preference[subnet.Type] > preference[current.Type] {
+			subnetsByZone[subnet.Zone] = subnet
+		}
+	}
+
+	var subnets []*awstasks.Subnet
+	for _, zone := range zones {
+		subnets = append(subnets, b.LinkToSubnet(subnetsByZone[zone]))
+	}
+	return subnets
+}
+
+// buildVPCEndpointSecurityGroup creates the security group of the interface endpoints, allowing HTTPS from the masters and nodes
+func (b *VPCEndpointModelBuilder) buildVPCEndpointSecurityGroup(c *fi.ModelBuilderContext) (*awstasks.SecurityGroup, error) {
+	name := "vpc-endpoints." + b.ClusterName()
+	securityGroup := &awstasks.SecurityGroup{
+		Name:             fi.String(name),
+		Lifecycle:        b.SecurityLifecycle,
+		VPC:              b.LinkToVPC(),
+		Description:      fi.String("Security group for VPC endpoints"),
+		RemoveExtraRules: []string{"port=443"},
+		Tags:             b.CloudTags(name, false),
+	}
+	c.AddTask(securityGroup)
+
+	for _, role := range []kops.InstanceGroupRole{kops.InstanceGroupRoleMaster, kops.InstanceGroupRoleNode} {
+		groups, err := b.GetSecurityGroups(role)
+		if err != nil {
+			return nil, err
+		}
+		for _, src := range groups {
+			b.AddDirectionalGroupRule(c, &awstasks.SecurityGroupRule{
+				Lifecycle:     b.SecurityLifecycle,
+				SecurityGroup: securityGroup,
+				SourceGroup:   src.Task,
+				Protocol:      fi.String("tcp"),
+				FromPort:      fi.Int64(443),
+				ToPort:        fi.Int64(443),
+			})
+		}
+	}
+
+	return securityGroup, nil
+}
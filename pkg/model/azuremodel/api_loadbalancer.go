@@ -64,6 +64,7 @@ func (b *APILoadBalancerModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			return err
 		}
 		lb.Subnet = b.LinkToAzureSubnet(subnet)
+		lb.Subnet.VirtualNetwork = b.LinkToVirtualNetwork()
 	case kops.LoadBalancerTypePublic:
 		lb.External = to.BoolPtr(true)
 
@@ -88,8 +89,11 @@ func (b *APILoadBalancerModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
-// subnetForLoadBalancer returns the subnet the loadbalancer will use.
+// subnetForLoadBalancer returns the subnet the internal loadbalancer will use.
+// A private subnet of the masters is preferred, but any subnet of the masters will do
+// as the frontend of an internal loadbalancer is only reachable within the Virtual Network.
 func (c *AzureModelContext) subnetForLoadBalancer() (*kops.ClusterSubnetSpec, error) {
+	var candidate *kops.ClusterSubnetSpec
 	// Get all master instance group subnets
 	for _, ig := range c.MasterInstanceGroups() {
 		subnets, err := c.GatherSubnets(ig)
@@ -99,11 +103,16 @@ func (c *AzureModelContext) subnetForLoadBalancer() (*kops.ClusterSubnetSpec, er
 		if len(subnets) != 1 {
 			return nil, fmt.Errorf("expected exactly one subnet for InstanceGroup %q; subnets was %s", ig.Name, ig.Spec.Subnets)
 		}
-		if subnets[0].Type != kops.SubnetTypePrivate {
-			continue
+		if subnets[0].Type == kops.SubnetTypePrivate {
+			return subnets[0], nil
+		}
+		if candidate == nil {
+			candidate = subnets[0]
 		}
-		return subnets[0], nil
 	}
 
-	return nil, fmt.Errorf("no suitable subnets found")
+	if candidate == nil {
+		return nil, fmt.Errorf("no suitable subnets found")
+	}
+	return candidate, nil
 }
@@ -72,5 +72,32 @@ func TestSubnetForLoadbalancer(t *testing.T) {
 	if !reflect.DeepEqual(actual, expected) {
 		t.Errorf("expected subnet %+v, but got %+v", expected, actual)
 	}
+}
+
+func TestSubnetForLoadbalancer_NoPrivateSubnet(t *testing.T) {
+	b := APILoadBalancerModelBuilder{
+		AzureModelContext: newTestAzureModelContext(),
+	}
+	b.Cluster.Spec.Subnets = []kops.ClusterSubnetSpec{
+		{
+			Name: "master",
+			Type: kops.SubnetTypePublic,
+		},
+	}
+	b.InstanceGroups[0].Spec.Role = kops.InstanceGroupRoleMaster
+	b.InstanceGroups[0].Spec.Subnets = []string{
+		"master",
+	}
 
+	actual, err := b.subnetForLoadBalancer()
+	if err != nil {
+		t.Error(err)
+	}
+	expected := &kops.ClusterSubnetSpec{
+		Name: "master",
+		Type: kops.SubnetTypePublic,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected subnet %+v, but got %+v", expected, actual)
+	}
 }
@@ -77,6 +77,27 @@ func (c *AzureModelContext) NameForLoadBalancer() string {
 	return "api-" + c.ClusterName()
 }
 
+// LinkToManagedIdentity returns the Managed Identity object assigned to the VM Scale Sets of the given role.
+func (c *AzureModelContext) LinkToManagedIdentity(role kops.InstanceGroupRole) *azuretasks.ManagedIdentity {
+	return &azuretasks.ManagedIdentity{Name: fi.String(c.NameForManagedIdentity(role))}
+}
+
+// NameForManagedIdentity returns the name of the Managed Identity object assigned to the VM Scale Sets of the given role.
+// Managed Identity names cannot contain periods, so they are replaced with hyphens.
+func (c *AzureModelContext) NameForManagedIdentity(role kops.InstanceGroupRole) string {
+	return azure.ManagedIdentityName(c.ClusterName(), role)
+}
+
+// LinkToNetworkSecurityGroup returns the Network Security Group object attached to the VM Scale Sets of the given role.
+func (c *AzureModelContext) LinkToNetworkSecurityGroup(role kops.InstanceGroupRole) *azuretasks.NetworkSecurityGroup {
+	return &azuretasks.NetworkSecurityGroup{Name: fi.String(c.NameForNetworkSecurityGroup(role))}
+}
+
+// NameForNetworkSecurityGroup returns the name of the Network Security Group object attached to the VM Scale Sets of the given role.
+func (c *AzureModelContext) NameForNetworkSecurityGroup(role kops.InstanceGroupRole) string {
+	return azure.NetworkSecurityGroupName(c.ClusterName(), role)
+}
+
 // CloudTagsForInstanceGroup computes the tags to apply to instances in the specified InstanceGroup
 // Mostly copied from pkg/model/context.go, but "/" in tag keys are replaced with "_" as Azure
 // doesn't allow "/" in tag keys.
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuremodel
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-06-01/network"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
+)
+
+// NetworkSecurityGroupModelBuilder configures the Network Security Groups of the masters and nodes.
+// Traffic within the Virtual Network is allowed by the default rules of the Network Security Groups.
+type NetworkSecurityGroupModelBuilder struct {
+	*AzureModelContext
+	Lifecycle *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &NetworkSecurityGroupModelBuilder{}
+
+// Build builds tasks for creating Network Security Groups derived from the access specs of the cluster.
+func (b *NetworkSecurityGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	nodePortRange, err := b.NodePortRange()
+	if err != nil {
+		return err
+	}
+
+	masters := b.buildNetworkSecurityGroupTask(kops.InstanceGroupRoleMaster)
+	if err := masters.appendRules("ssh", network.SecurityRuleProtocolTCP, "22", b.Cluster.Spec.SSHAccess); err != nil {
+		return err
+	}
+	if err := masters.appendRules("https-api", network.SecurityRuleProtocolTCP, "443", b.Cluster.Spec.KubernetesAPIAccess); err != nil {
+		return err
+	}
+	c.AddTask(masters.NetworkSecurityGroup)
+
+	nodes := b.buildNetworkSecurityGroupTask(kops.InstanceGroupRoleNode)
+	if err := nodes.appendRules("ssh", network.SecurityRuleProtocolTCP, "22", b.Cluster.Spec.SSHAccess); err != nil {
+		return err
+	}
+	nodePorts := fmt.Sprintf("%d-%d", nodePortRange.Base, nodePortRange.Base+nodePortRange.Size-1)
+	if err := nodes.appendRules("nodeport", network.SecurityRuleProtocolAsterisk, nodePorts, b.Cluster.Spec.NodePortAccess); err != nil {
+		return err
+	}
+	c.AddTask(nodes.NetworkSecurityGroup)
+
+	return nil
+}
+
+// networkSecurityGroupBuilder assigns increasing priorities to the rules of a Network Security Group.
+type networkSecurityGroupBuilder struct {
+	*azuretasks.NetworkSecurityGroup
+	priority int32
+}
+
+func (b *NetworkSecurityGroupModelBuilder) buildNetworkSecurityGroupTask(role kops.InstanceGroupRole) *networkSecurityGroupBuilder {
+	return &networkSecurityGroupBuilder{
+		NetworkSecurityGroup: &azuretasks.NetworkSecurityGroup{
+			Name:          fi.String(b.NameForNetworkSecurityGroup(role)),
+			Lifecycle:     b.Lifecycle,
+			ResourceGroup: b.LinkToResourceGroup(),
+			Tags:          map[string]*string{},
+		},
+		priority: 100,
+	}
+}
+
+// appendRules adds rules allowing traffic from the given CIDRs to the given ports, unless no source is allowed.
+// Azure doesn't allow mixing IPv4 and IPv6 prefixes in a rule, so a rule is added for each family.
+func (n *networkSecurityGroupBuilder) appendRules(name string, protocol network.SecurityRuleProtocol, ports string, cidrs []string) error {
+	var ipv4, ipv6 []string
+	for _, cidr := range cidrs {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("error parsing CIDR %q: %v", cidr, err)
+		}
+		if ip.To4() != nil {
+			ipv4 = append(ipv4, cidr)
+		} else {
+			ipv6 = append(ipv6, cidr)
+		}
+	}
+
+	for _, r := range []struct {
+		suffix   string
+		prefixes []string
+	}{
+		{"ipv4", ipv4},
+		{"ipv6", ipv6},
+	} {
+		if len(r.prefixes) == 0 {
+			continue
+		}
+		n.SecurityRules = append(n.SecurityRules, &azuretasks.NetworkSecurityRule{
+			Name:                  fi.String(name + "-" + r.suffix),
+			Priority:              fi.Int32(n.priority),
+			Access:                network.SecurityRuleAccessAllow,
+			Protocol:              protocol,
+			SourceAddressPrefixes: r.prefixes,
+			DestinationPortRange:  fi.String(ports),
+		})
+		n.priority++
+	}
+	return nil
+}
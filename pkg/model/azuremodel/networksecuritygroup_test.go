@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuremodel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-06-01/network"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
+)
+
+func TestNetworkSecurityGroupModelBuilder_Build(t *testing.T) {
+	b := NetworkSecurityGroupModelBuilder{
+		AzureModelContext: newTestAzureModelContext(),
+	}
+	b.Cluster.Spec.SSHAccess = []string{"10.0.0.0/8", "2001:db8::/32"}
+	b.Cluster.Spec.KubernetesAPIAccess = []string{"0.0.0.0/0"}
+	b.Cluster.Spec.KubeAPIServer = &kops.KubeAPIServerConfig{
+		ServiceNodePortRange: "28000-28999",
+	}
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	if err := b.Build(c); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	masters := c.Tasks["NetworkSecurityGroup/masters.testcluster.test.com"].(*azuretasks.NetworkSecurityGroup)
+	expectedMasterRules := []*azuretasks.NetworkSecurityRule{
+		{
+			Name:                  fi.String("ssh-ipv4"),
+			Priority:              fi.Int32(100),
+			Access:                network.SecurityRuleAccessAllow,
+			Protocol:              network.SecurityRuleProtocolTCP,
+			SourceAddressPrefixes: []string{"10.0.0.0/8"},
+			DestinationPortRange:  fi.String("22"),
+		},
+		{
+			Name:                  fi.String("ssh-ipv6"),
+			Priority:              fi.Int32(101),
+			Access:                network.SecurityRuleAccessAllow,
+			Protocol:              network.SecurityRuleProtocolTCP,
+			SourceAddressPrefixes: []string{"2001:db8::/32"},
+			DestinationPortRange:  fi.String("22"),
+		},
+		{
+			Name:                  fi.String("https-api-ipv4"),
+			Priority:              fi.Int32(102),
+			Access:                network.SecurityRuleAccessAllow,
+			Protocol:              network.SecurityRuleProtocolTCP,
+			SourceAddressPrefixes: []string{"0.0.0.0/0"},
+			DestinationPortRange:  fi.String("443"),
+		},
+	}
+	if a, e := masters.SecurityRules, expectedMasterRules; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected master rules: expected %+v, but got %+v", e, a)
+	}
+
+	// No NodePort rule is added as NodePortAccess is empty.
+	nodes := c.Tasks["NetworkSecurityGroup/nodes.testcluster.test.com"].(*azuretasks.NetworkSecurityGroup)
+	if a, e := len(nodes.SecurityRules), 2; a != e {
+		t.Fatalf("unexpected number of node rules: expected %d, but got %d", e, a)
+	}
+
+	b.Cluster.Spec.NodePortAccess = []string{"192.168.0.0/16"}
+	c = &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	if err := b.Build(c); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	nodes = c.Tasks["NetworkSecurityGroup/nodes.testcluster.test.com"].(*azuretasks.NetworkSecurityGroup)
+	expectedNodePortRule := &azuretasks.NetworkSecurityRule{
+		Name:                  fi.String("nodeport-ipv4"),
+		Priority:              fi.Int32(102),
+		Access:                network.SecurityRuleAccessAllow,
+		Protocol:              network.SecurityRuleProtocolAsterisk,
+		SourceAddressPrefixes: []string{"192.168.0.0/16"},
+		DestinationPortRange:  fi.String("28000-28999"),
+	}
+	if a, e := nodes.SecurityRules[len(nodes.SecurityRules)-1], expectedNodePortRule; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected node port rule: expected %+v, but got %+v", e, a)
+	}
+}
+
+func TestNetworkSecurityGroupModelBuilder_InvalidCIDR(t *testing.T) {
+	b := NetworkSecurityGroupModelBuilder{
+		AzureModelContext: newTestAzureModelContext(),
+	}
+	b.Cluster.Spec.SSHAccess = []string{"10.0.0.0"}
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	if err := b.Build(c); err == nil {
+		t.Errorf("expected error for invalid CIDR")
+	}
+}
@@ -48,17 +48,40 @@ func (b *VMScaleSetModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		}
 		c.AddTask(vmss)
 
-		// Create tasks for assigning built-in roles to VM Scale Sets.
+		// The VM Scale Sets of a role share a Managed Identity, which is created along with the first of them.
+		identityName := b.NameForManagedIdentity(ig.Spec.Role)
+		if _, found := c.Tasks["ManagedIdentity/"+identityName]; found {
+			continue
+		}
+		identity := &azuretasks.ManagedIdentity{
+			Name:          fi.String(identityName),
+			Lifecycle:     b.Lifecycle,
+			ResourceGroup: b.LinkToResourceGroup(),
+			Tags:          map[string]*string{},
+		}
+		c.AddTask(identity)
+
+		// Create tasks for assigning built-in roles to the Managed Identity.
 		// See https://docs.microsoft.com/en-us/azure/role-based-access-control/built-in-roles
 		// for the ID definitions.
-		roleDefIDs := map[string]string{
-			// Owner
-			"owner": "8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
-			// Storage Blob Data Contributor
-			"blob": "ba92f5b4-2d11-453d-a403-e96b0029c9fe",
+		var roleDefIDs map[string]string
+		if ig.Spec.Role == kops.InstanceGroupRoleMaster {
+			roleDefIDs = map[string]string{
+				// Contributor
+				"contributor": "b24988ac-6180-42a0-ab88-20f7382dd24c",
+				// Storage Blob Data Contributor
+				"blob": "ba92f5b4-2d11-453d-a403-e96b0029c9fe",
+			}
+		} else {
+			roleDefIDs = map[string]string{
+				// Reader
+				"reader": "acdd72a7-3385-48ef-bd42-f606fba81ae7",
+				// Storage Blob Data Reader
+				"blob": "2a2b9908-6ea1-4ae2-8e65-a410df84e7d1",
+			}
 		}
 		for k, roleDefID := range roleDefIDs {
-			c.AddTask(b.buildRoleAssignmentTask(vmss, k, roleDefID))
+			c.AddTask(b.buildRoleAssignmentTask(identity, k, roleDefID))
 		}
 	}
 
@@ -132,6 +155,9 @@ func (b *VMScaleSetModelBuilder) buildVMScaleSetTask(
 		}
 	}
 
+	t.ManagedIdentity = b.LinkToManagedIdentity(ig.Spec.Role)
+	t.NetworkSecurityGroup = b.LinkToNetworkSecurityGroup(ig.Spec.Role)
+
 	t.Tags = b.CloudTagsForInstanceGroup(ig)
 
 	return t, nil
@@ -215,13 +241,13 @@ func parseImage(image string) (*compute.ImageReference, error) {
 	}, nil
 }
 
-func (b *VMScaleSetModelBuilder) buildRoleAssignmentTask(vmss *azuretasks.VMScaleSet, roleKey, roleDefID string) *azuretasks.RoleAssignment {
-	name := fmt.Sprintf("%s-%s", *vmss.Name, roleKey)
+func (b *VMScaleSetModelBuilder) buildRoleAssignmentTask(identity *azuretasks.ManagedIdentity, roleKey, roleDefID string) *azuretasks.RoleAssignment {
+	name := fmt.Sprintf("%s-%s", *identity.Name, roleKey)
 	return &azuretasks.RoleAssignment{
-		Name:          to.StringPtr(name),
-		Lifecycle:     b.Lifecycle,
-		ResourceGroup: b.LinkToResourceGroup(),
-		VMScaleSet:    vmss,
-		RoleDefID:     to.StringPtr(roleDefID),
+		Name:            to.StringPtr(name),
+		Lifecycle:       b.Lifecycle,
+		ResourceGroup:   b.LinkToResourceGroup(),
+		ManagedIdentity: identity,
+		RoleDefID:       to.StringPtr(roleDefID),
 	}
 }
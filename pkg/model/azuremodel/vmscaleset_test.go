@@ -19,6 +19,7 @@ package azuremodel
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
@@ -26,6 +27,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/model/defaults"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
 	"k8s.io/kops/upup/pkg/fi/fitasks"
 )
 
@@ -50,6 +52,71 @@ func TestVMScaleSetModelBuilder_Build(t *testing.T) {
 	}
 }
 
+func TestVMScaleSetModelBuilder_BuildManagedIdentities(t *testing.T) {
+	b := VMScaleSetModelBuilder{
+		AzureModelContext: newTestAzureModelContext(),
+	}
+	master := newTestInstanceGroup()
+	master.Name = "master"
+	master.Spec.Role = kops.InstanceGroupRoleMaster
+	nodes := newTestInstanceGroup()
+	nodes.Name = "nodes2"
+	b.InstanceGroups = append(b.InstanceGroups, master, nodes)
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+
+	caTask := &fitasks.Keypair{
+		Name:    fi.String(fi.CertificateIDCA),
+		Subject: "cn=kubernetes",
+		Type:    "ca",
+	}
+	c.AddTask(caTask)
+
+	if err := b.Build(c); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	// The VM Scale Sets of a role share a Managed Identity and a Network Security Group.
+	for name, expected := range map[string][2]string{
+		"nodes.testcluster.test.com":          {"nodes-testcluster-test-com", "nodes.testcluster.test.com"},
+		"nodes2.testcluster.test.com":         {"nodes-testcluster-test-com", "nodes.testcluster.test.com"},
+		"master.masters.testcluster.test.com": {"masters-testcluster-test-com", "masters.testcluster.test.com"},
+	} {
+		vmss := c.Tasks["VMScaleSet/"+name].(*azuretasks.VMScaleSet)
+		if a, e := *vmss.ManagedIdentity.Name, expected[0]; a != e {
+			t.Errorf("unexpected Managed Identity of %s: expected %s, but got %s", name, e, a)
+		}
+		if a, e := *vmss.NetworkSecurityGroup.Name, expected[1]; a != e {
+			t.Errorf("unexpected Network Security Group of %s: expected %s, but got %s", name, e, a)
+		}
+	}
+
+	var identities []string
+	roleDefIDs := map[string]string{}
+	for _, task := range c.Tasks {
+		switch task := task.(type) {
+		case *azuretasks.ManagedIdentity:
+			identities = append(identities, *task.Name)
+		case *azuretasks.RoleAssignment:
+			roleDefIDs[*task.Name] = *task.RoleDefID
+		}
+	}
+	sort.Strings(identities)
+	if a, e := identities, []string{"masters-testcluster-test-com", "nodes-testcluster-test-com"}; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected Managed Identities: expected %v, but got %v", e, a)
+	}
+	expectedRoleDefIDs := map[string]string{
+		"masters-testcluster-test-com-contributor": "b24988ac-6180-42a0-ab88-20f7382dd24c",
+		"masters-testcluster-test-com-blob":        "ba92f5b4-2d11-453d-a403-e96b0029c9fe",
+		"nodes-testcluster-test-com-reader":        "acdd72a7-3385-48ef-bd42-f606fba81ae7",
+		"nodes-testcluster-test-com-blob":          "2a2b9908-6ea1-4ae2-8e65-a410df84e7d1",
+	}
+	if a, e := roleDefIDs, expectedRoleDefIDs; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected Role Assignments: expected %v, but got %v", e, a)
+	}
+}
+
 func TestGetCapacity(t *testing.T) {
 	testCases := []struct {
 		spec     kops.InstanceGroupSpec
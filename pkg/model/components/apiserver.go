@@ -118,6 +118,10 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 		c.CloudProvider = "alicloud"
 	case kops.CloudProviderAzure:
 		c.CloudProvider = "azure"
+	case kops.CloudProviderHetzner:
+		c.CloudProvider = "external"
+	case kops.CloudProviderMetal:
+		// Pre-provisioned hosts have no cloud provider integration
 	default:
 		return fmt.Errorf("unknown cloudprovider %q", clusterSpec.CloudProvider)
 	}
@@ -132,7 +136,15 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 	c.BindAddress = "0.0.0.0"
 
 	c.AllowPrivileged = fi.Bool(true)
-	c.ServiceClusterIPRange = clusterSpec.ServiceClusterIPRange
+	c.ServiceClusterIPRange = strings.Join(clusterSpec.ServiceClusterIPRanges(), ",")
+	if clusterSpec.IsDualStack() && b.IsKubernetesLT("1.21") {
+		if c.FeatureGates == nil {
+			c.FeatureGates = make(map[string]string)
+		}
+		if _, found := c.FeatureGates["IPv6DualStack"]; !found {
+			c.FeatureGates["IPv6DualStack"] = "true"
+		}
+	}
 	c.EtcdServers = []string{"http://127.0.0.1:4001"}
 	c.EtcdServersOverrides = []string{"/events#http://127.0.0.1:4002"}
 
@@ -81,9 +81,15 @@ func UsesCNI(networking *kops.NetworkingSpec) bool {
 }
 
 func WellKnownServiceIP(clusterSpec *kops.ClusterSpec, id int) (net.IP, error) {
-	_, cidr, err := net.ParseCIDR(clusterSpec.ServiceClusterIPRange)
+	// Well-known services such as the API server and DNS are allocated from the primary service range
+	serviceClusterIPRange := clusterSpec.ServiceClusterIPRange
+	if ranges := clusterSpec.ServiceClusterIPRanges(); len(ranges) != 0 {
+		serviceClusterIPRange = ranges[0]
+	}
+
+	_, cidr, err := net.ParseCIDR(serviceClusterIPRange)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing ServiceClusterIPRange %q: %v", clusterSpec.ServiceClusterIPRange, err)
+		return nil, fmt.Errorf("error parsing ServiceClusterIPRange %q: %v", serviceClusterIPRange, err)
 	}
 
 	ip4 := cidr.IP.To4()
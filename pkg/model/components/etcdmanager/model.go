@@ -38,6 +38,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 	"k8s.io/kops/upup/pkg/fi/fitasks"
 	"k8s.io/kops/util/pkg/env"
@@ -417,6 +419,23 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec) (*v1.Pod
 			}
 			config.VolumeNameTag = do.TagNameEtcdClusterPrefix + etcdCluster.Name
 
+		case kops.CloudProviderHetzner:
+			config.VolumeProvider = "hetzner"
+
+			config.VolumeTag = []string{
+				fmt.Sprintf("%s=%s", hetzner.TagKubernetesClusterName, b.Cluster.Name),
+				fmt.Sprintf("%s=%s", hetzner.TagKubernetesVolumeRole, etcdCluster.Name),
+			}
+			config.VolumeNameTag = hetzner.TagKubernetesEtcdMember
+
+		case kops.CloudProviderMetal:
+			// The etcd data lives in directories on the local disks of the master hosts, created by nodeup
+			config.VolumeProvider = "external"
+
+			config.VolumeTag = []string{
+				metal.EtcdVolumePrefix(b.Cluster.Name, etcdCluster.Name),
+			}
+
 		case kops.CloudProviderOpenstack:
 			config.VolumeProvider = "openstack"
 
@@ -95,6 +95,12 @@ func (b *KubeControllerManagerOptionsBuilder) BuildOptions(o interface{}) error
 	case kops.CloudProviderAzure:
 		kcm.CloudProvider = "azure"
 
+	case kops.CloudProviderHetzner:
+		kcm.CloudProvider = "external"
+
+	case kops.CloudProviderMetal:
+		// Pre-provisioned hosts have no cloud provider integration
+
 	default:
 		return fmt.Errorf("unknown cloudprovider %q", clusterSpec.CloudProvider)
 	}
@@ -140,6 +146,15 @@ func (b *KubeControllerManagerOptionsBuilder) BuildOptions(o interface{}) error
 		return fmt.Errorf("no networking mode set")
 	}
 
+	if clusterSpec.IsDualStack() && b.IsKubernetesLT("1.21") {
+		if kcm.FeatureGates == nil {
+			kcm.FeatureGates = make(map[string]string)
+		}
+		if _, found := kcm.FeatureGates["IPv6DualStack"]; !found {
+			kcm.FeatureGates["IPv6DualStack"] = "true"
+		}
+	}
+
 	if kcm.UseServiceAccountCredentials == nil {
 		kcm.UseServiceAccountCredentials = fi.Bool(true)
 	}
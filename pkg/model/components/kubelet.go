@@ -139,6 +139,11 @@ func (b *KubeletOptionsBuilder) BuildOptions(o interface{}) error {
 		clusterSpec.Kubelet.HostnameOverride = "@digitalocean"
 	}
 
+	if cloudProvider == kops.CloudProviderHetzner {
+		// The server names are the hostnames, as expected by the hcloud cloud controller manager
+		clusterSpec.Kubelet.CloudProvider = "external"
+	}
+
 	if cloudProvider == kops.CloudProviderGCE {
 		clusterSpec.Kubelet.CloudProvider = "gce"
 		clusterSpec.Kubelet.HairpinMode = "promiscuous-bridge"
@@ -219,6 +224,11 @@ func (b *KubeletOptionsBuilder) BuildOptions(o interface{}) error {
 			}
 		}
 	}
+	if clusterSpec.IsDualStack() && b.IsKubernetesLT("1.21") {
+		if _, found := clusterSpec.Kubelet.FeatureGates["IPv6DualStack"]; !found {
+			clusterSpec.Kubelet.FeatureGates["IPv6DualStack"] = "true"
+		}
+	}
 	if _, found := clusterSpec.Kubelet.FeatureGates["ExperimentalCriticalPodAnnotation"]; !found {
 		if b.IsKubernetesLT("1.16") {
 			clusterSpec.Kubelet.FeatureGates["ExperimentalCriticalPodAnnotation"] = "true"
@@ -100,5 +100,14 @@ func (b *KubeProxyOptionsBuilder) BuildOptions(o interface{}) error {
 		}
 	}
 
+	if clusterSpec.IsDualStack() && b.Context.IsKubernetesLT("1.21") {
+		if config.FeatureGates == nil {
+			config.FeatureGates = make(map[string]string)
+		}
+		if _, found := config.FeatureGates["IPv6DualStack"]; !found {
+			config.FeatureGates["IPv6DualStack"] = "true"
+		}
+	}
+
 	return nil
 }
@@ -57,14 +57,14 @@ func (b *APILoadBalancerModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	}
 
 	clusterName := strings.Replace(b.ClusterName(), ".", "-", -1)
-	loadbalancerName := "api-" + clusterName
 	clusterMasterTag := do.TagKubernetesClusterMasterPrefix + ":" + clusterName
 
 	// Create LoadBalancer for API LB
 	loadbalancer := &dotasks.LoadBalancer{
-		Name:       fi.String(loadbalancerName),
+		Name:       fi.String(b.APILoadBalancerName()),
 		Region:     fi.String(b.Cluster.Spec.Subnets[0].Region),
 		DropletTag: fi.String(clusterMasterTag),
+		VPC:        b.LinkToVPC(),
 		Lifecycle:  b.Lifecycle,
 	}
 	c.AddTask(loadbalancer)
@@ -16,9 +16,34 @@ limitations under the License.
 
 package domodel
 
-import "k8s.io/kops/pkg/model"
+import (
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/do"
+	"k8s.io/kops/upup/pkg/fi/cloudup/dotasks"
+)
 
 // DigitalOcean Model Context
 type DOModelContext struct {
 	*model.KopsModelContext
 }
+
+// VPCName returns the name of the VPC the droplets of the cluster are launched into
+func (c *DOModelContext) VPCName() string {
+	return do.SafeClusterName(c.ClusterName())
+}
+
+// LinkToVPC returns the VPC object the cluster is located in
+func (c *DOModelContext) LinkToVPC() *dotasks.VPC {
+	return &dotasks.VPC{Name: fi.String(c.VPCName())}
+}
+
+// APILoadBalancerName returns the name of the load balancer fronting the API
+func (c *DOModelContext) APILoadBalancerName() string {
+	return "api-" + do.SafeClusterName(c.ClusterName())
+}
+
+// LinkToAPILoadBalancer returns the load balancer fronting the API
+func (c *DOModelContext) LinkToAPILoadBalancer() *dotasks.LoadBalancer {
+	return &dotasks.LoadBalancer{Name: fi.String(c.APILoadBalancerName())}
+}
@@ -66,6 +66,7 @@ func (d *DropletBuilder) Build(c *fi.ModelBuilderContext) error {
 		droplet.Size = fi.String(ig.Spec.MachineType)
 		droplet.Image = fi.String(ig.Spec.Image)
 		droplet.SSHKey = fi.String(sshKeyFingerPrint)
+		droplet.VPC = d.LinkToVPC()
 
 		droplet.Tags = []string{clusterTag}
 
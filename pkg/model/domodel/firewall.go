@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domodel
+
+import (
+	"github.com/digitalocean/godo"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/do"
+	"k8s.io/kops/upup/pkg/fi/cloudup/dotasks"
+)
+
+// FirewallModelBuilder configures the Cloud Firewalls restricting access to the droplets
+type FirewallModelBuilder struct {
+	*DOModelContext
+	Lifecycle *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &FirewallModelBuilder{}
+
+func (b *FirewallModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	// Cloud Firewalls only filter TCP, UDP and ICMP, so IP-in-IP traffic between droplets would be dropped
+	if networking := b.Cluster.Spec.Networking; networking != nil && networking.Calico != nil && networking.Calico.EncapsulationMode == "ipip" {
+		klog.Warningf("not creating DigitalOcean firewalls, calico IP-in-IP encapsulation is not supported by Cloud Firewalls; use vxlan encapsulation instead")
+		return nil
+	}
+
+	clusterName := do.SafeClusterName(b.ClusterName())
+	clusterTag := do.TagKubernetesClusterNamePrefix + ":" + clusterName
+	clusterMasterTag := do.TagKubernetesClusterMasterPrefix + ":" + clusterName
+
+	// Allow all traffic between the droplets of the cluster, and any outbound traffic
+	{
+		firewall := &dotasks.Firewall{
+			Name:        fi.String("k8s-" + clusterName),
+			Lifecycle:   b.Lifecycle,
+			DropletTags: []string{clusterTag},
+		}
+
+		clusterSources := &godo.Sources{Tags: []string{clusterTag}}
+		firewall.InboundRules = append(firewall.InboundRules,
+			godo.InboundRule{Protocol: "tcp", PortRange: "all", Sources: clusterSources},
+			godo.InboundRule{Protocol: "udp", PortRange: "all", Sources: clusterSources},
+			godo.InboundRule{Protocol: "icmp", Sources: clusterSources},
+		)
+
+		if len(b.Cluster.Spec.SSHAccess) != 0 {
+			firewall.InboundRules = append(firewall.InboundRules, godo.InboundRule{
+				Protocol:  "tcp",
+				PortRange: "22",
+				Sources:   &godo.Sources{Addresses: b.Cluster.Spec.SSHAccess},
+			})
+		}
+
+		if len(b.Cluster.Spec.NodePortAccess) != 0 {
+			nodePortRange, err := b.NodePortRange()
+			if err != nil {
+				return err
+			}
+			portRange := nodePortRange.String()
+			firewall.InboundRules = append(firewall.InboundRules,
+				godo.InboundRule{Protocol: "tcp", PortRange: portRange, Sources: &godo.Sources{Addresses: b.Cluster.Spec.NodePortAccess}},
+				godo.InboundRule{Protocol: "udp", PortRange: portRange, Sources: &godo.Sources{Addresses: b.Cluster.Spec.NodePortAccess}},
+			)
+		}
+
+		anywhere := &godo.Destinations{Addresses: []string{"0.0.0.0/0", "::/0"}}
+		firewall.OutboundRules = []godo.OutboundRule{
+			{Protocol: "tcp", PortRange: "all", Destinations: anywhere},
+			{Protocol: "udp", PortRange: "all", Destinations: anywhere},
+			{Protocol: "icmp", Destinations: anywhere},
+		}
+
+		c.AddTask(firewall)
+	}
+
+	// Allow access to the API on the masters
+	{
+		firewall := &dotasks.Firewall{
+			Name:        fi.String("masters-" + clusterName),
+			Lifecycle:   b.Lifecycle,
+			DropletTags: []string{clusterMasterTag},
+		}
+
+		if len(b.Cluster.Spec.KubernetesAPIAccess) != 0 {
+			firewall.InboundRules = append(firewall.InboundRules, godo.InboundRule{
+				Protocol:  "tcp",
+				PortRange: "443",
+				Sources:   &godo.Sources{Addresses: b.Cluster.Spec.KubernetesAPIAccess},
+			})
+		}
+
+		if b.UseLoadBalancerForAPI() && b.Cluster.Spec.API.LoadBalancer != nil {
+			firewall.APILoadBalancer = b.LinkToAPILoadBalancer()
+		}
+
+		if len(firewall.InboundRules) != 0 || firewall.APILoadBalancer != nil {
+			c.AddTask(firewall)
+		}
+	}
+
+	return nil
+}
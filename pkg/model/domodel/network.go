@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domodel
+
+import (
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/do"
+	"k8s.io/kops/upup/pkg/fi/cloudup/dotasks"
+)
+
+// NetworkModelBuilder configures the VPC for the cluster
+type NetworkModelBuilder struct {
+	*DOModelContext
+	Lifecycle *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &NetworkModelBuilder{}
+
+func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	// during alpha support we only allow 1 region
+	vpc := &dotasks.VPC{
+		Name:       fi.String(b.VPCName()),
+		Lifecycle:  b.Lifecycle,
+		Region:     fi.String(b.Cluster.Spec.Subnets[0].Region),
+		DropletTag: fi.String(do.TagKubernetesClusterNamePrefix + ":" + do.SafeClusterName(b.ClusterName())),
+	}
+
+	if b.Cluster.Spec.NetworkID != "" {
+		vpc.ID = fi.String(b.Cluster.Spec.NetworkID)
+		vpc.Shared = fi.Bool(true)
+	} else {
+		vpc.Shared = fi.Bool(false)
+	}
+
+	c.AddTask(vpc)
+
+	return nil
+}
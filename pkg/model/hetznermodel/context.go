@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznermodel
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetznertasks"
+)
+
+// HetznerModelContext is the Hetzner Cloud model context
+type HetznerModelContext struct {
+	*model.KopsModelContext
+}
+
+// Location returns the Hetzner location of the cluster.
+// All the servers of a cluster live in a single location, the one of the first subnet.
+func (c *HetznerModelContext) Location() string {
+	return c.Cluster.Spec.Subnets[0].Zone
+}
+
+// ClusterLabels returns the labels identifying the resources owned by the cluster
+func (c *HetznerModelContext) ClusterLabels() map[string]string {
+	return map[string]string{
+		hetzner.TagKubernetesClusterName: c.ClusterName(),
+	}
+}
+
+// RoleLabelSelector returns the label selector matching the servers of the cluster with the given role
+func (c *HetznerModelContext) RoleLabelSelector(role kops.InstanceGroupRole) string {
+	return hetzner.ClusterLabelSelector(c.ClusterName()) + "," + hetzner.TagKubernetesInstanceRole + "=" + string(role)
+}
+
+func (c *HetznerModelContext) LinkToNetwork() *hetznertasks.Network {
+	return &hetznertasks.Network{Name: fi.String(c.ClusterName())}
+}
+
+func (c *HetznerModelContext) LinkToSSHKey() (*hetznertasks.SSHKey, error) {
+	name, err := c.SSHKeyName()
+	if err != nil {
+		return nil, err
+	}
+	return &hetznertasks.SSHKey{Name: fi.String(name)}, nil
+}
+
+func (c *HetznerModelContext) LinkToMasterPlacementGroup() *hetznertasks.PlacementGroup {
+	return &hetznertasks.PlacementGroup{Name: fi.String("masters." + c.ClusterName())}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznermodel
+
+import (
+	"fmt"
+	"net"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetznertasks"
+)
+
+// FirewallModelBuilder configures the firewalls of the masters and nodes.
+// Hetzner firewalls only filter the public interfaces, traffic on the private network is always allowed.
+type FirewallModelBuilder struct {
+	*HetznerModelContext
+	Lifecycle *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &FirewallModelBuilder{}
+
+func (b *FirewallModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	sshAccess, err := normalizeCIDRs(b.Cluster.Spec.SSHAccess)
+	if err != nil {
+		return err
+	}
+	apiAccess, err := normalizeCIDRs(b.Cluster.Spec.KubernetesAPIAccess)
+	if err != nil {
+		return err
+	}
+	nodePortAccess, err := normalizeCIDRs(b.Cluster.Spec.NodePortAccess)
+	if err != nil {
+		return err
+	}
+
+	masters := &hetznertasks.Firewall{
+		Name:      fi.String("masters." + b.ClusterName()),
+		Lifecycle: b.Lifecycle,
+		Selector:  b.RoleLabelSelector(kops.InstanceGroupRoleMaster),
+		Labels:    b.ClusterLabels(),
+	}
+	masters.Rules = appendRule(masters.Rules, "22", sshAccess)
+	masters.Rules = appendRule(masters.Rules, "443", apiAccess)
+	c.AddTask(masters)
+
+	nodes := &hetznertasks.Firewall{
+		Name:      fi.String("nodes." + b.ClusterName()),
+		Lifecycle: b.Lifecycle,
+		Selector:  b.RoleLabelSelector(kops.InstanceGroupRoleNode),
+		Labels:    b.ClusterLabels(),
+	}
+	nodes.Rules = appendRule(nodes.Rules, "22", sshAccess)
+	nodes.Rules = appendRule(nodes.Rules, "30000-32767", nodePortAccess)
+	c.AddTask(nodes)
+
+	return nil
+}
+
+// appendRule adds a TCP rule for the given port, unless no source is allowed
+func appendRule(rules []*hetznertasks.FirewallRule, port string, sourceIPs []string) []*hetznertasks.FirewallRule {
+	if len(sourceIPs) == 0 {
+		return rules
+	}
+	return append(rules, &hetznertasks.FirewallRule{
+		Protocol:  "tcp",
+		Port:      fi.String(port),
+		SourceIPs: sourceIPs,
+	})
+}
+
+// normalizeCIDRs returns the CIDRs in the canonical form returned by the Hetzner API
+func normalizeCIDRs(cidrs []string) ([]string, error) {
+	var normalized []string
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing CIDR %q: %v", cidr, err)
+		}
+		normalized = append(normalized, ipNet.String())
+	}
+	return normalized, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznermodel
+
+import (
+	"fmt"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetznertasks"
+)
+
+// ServerGroupModelBuilder configures the servers of the instance groups
+type ServerGroupModelBuilder struct {
+	*HetznerModelContext
+
+	BootstrapScriptBuilder *model.BootstrapScriptBuilder
+	Lifecycle              *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &ServerGroupModelBuilder{}
+
+func (b *ServerGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	sshKey, err := b.LinkToSSHKey()
+	if err != nil {
+		return err
+	}
+
+	addedPlacementGroup := false
+	for _, ig := range b.InstanceGroups {
+		labels := b.ClusterLabels()
+		labels[hetzner.TagKubernetesInstanceGroup] = ig.Name
+		labels[hetzner.TagKubernetesInstanceRole] = string(ig.Spec.Role)
+
+		location, err := b.instanceGroupLocation(ig)
+		if err != nil {
+			return err
+		}
+
+		userData, err := b.BootstrapScriptBuilder.ResourceNodeUp(c, ig)
+		if err != nil {
+			return err
+		}
+
+		serverGroup := &hetznertasks.ServerGroup{
+			Name:      fi.String(b.AutoscalingGroupName(ig)),
+			Lifecycle: b.Lifecycle,
+			Count:     int(fi.Int32Value(ig.Spec.MinSize)),
+			Location:  location,
+			Size:      ig.Spec.MachineType,
+			Image:     ig.Spec.Image,
+			SSHKeys:   []*hetznertasks.SSHKey{sshKey},
+			Network:   b.LinkToNetwork(),
+			UserData:  userData,
+			Labels:    labels,
+		}
+
+		// Spread the masters across physical hosts, so that a host failure cannot take down the control plane
+		if ig.IsMaster() {
+			if !addedPlacementGroup {
+				placementGroup := b.LinkToMasterPlacementGroup()
+				placementGroup.Lifecycle = b.Lifecycle
+				placementGroup.Type = "spread"
+				placementGroup.Labels = b.ClusterLabels()
+				c.AddTask(placementGroup)
+				addedPlacementGroup = true
+			}
+			serverGroup.PlacementGroup = b.LinkToMasterPlacementGroup()
+		}
+
+		c.AddTask(serverGroup)
+	}
+
+	return nil
+}
+
+// instanceGroupLocation returns the Hetzner location of the servers of the instance group
+func (b *ServerGroupModelBuilder) instanceGroupLocation(ig *kops.InstanceGroup) (string, error) {
+	if len(ig.Spec.Subnets) == 0 {
+		return b.Location(), nil
+	}
+	for _, subnet := range b.Cluster.Spec.Subnets {
+		if subnet.Name == ig.Spec.Subnets[0] {
+			return subnet.Zone, nil
+		}
+	}
+	return "", fmt.Errorf("subnet %q of instance group %q not found", ig.Spec.Subnets[0], ig.Name)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznermodel
+
+import (
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetznertasks"
+)
+
+// SSHKeyModelBuilder registers the admin SSH key of the cluster
+type SSHKeyModelBuilder struct {
+	*HetznerModelContext
+	Lifecycle *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &SSHKeyModelBuilder{}
+
+func (b *SSHKeyModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	name, err := b.SSHKeyName()
+	if err != nil {
+		return err
+	}
+	c.AddTask(&hetznertasks.SSHKey{
+		Name:      fi.String(name),
+		Lifecycle: b.Lifecycle,
+		PublicKey: string(b.SSHPublicKeys[0]),
+		Labels:    b.ClusterLabels(),
+	})
+
+	return nil
+}
@@ -164,6 +164,7 @@ func (b *IAMModelBuilder) buildIAMRolePolicy(role iam.Subject, iamName string, i
 			Role:                 role,
 			Region:               b.Region,
 			UseServiceAccountIAM: b.UseServiceAccountIAM(),
+			UseWarmPools:         b.useWarmPools(role),
 		},
 	}
 
@@ -188,6 +189,19 @@ func (b *IAMModelBuilder) buildIAMRolePolicy(role iam.Subject, iamName string, i
 	return nil
 }
 
+// useWarmPools returns true if any instance group of the node role has a warm pool
+func (b *IAMModelBuilder) useWarmPools(role iam.Subject) bool {
+	if _, ok := role.(*iam.NodeRoleNode); !ok {
+		return false
+	}
+	for _, ig := range b.InstanceGroups {
+		if ig.Spec.Role == kops.InstanceGroupRoleNode && ig.Spec.WarmPool != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // roleKey builds a string to represent the role uniquely.  It returns true if this is a service account role.
 func (b *IAMModelBuilder) roleKey(role iam.Subject) (string, bool) {
 	serviceAccount, ok := role.ServiceAccount()
@@ -257,6 +271,8 @@ func (b *IAMModelBuilder) buildIAMTasks(role iam.Subject, iamName string, c *fi.
 			}
 			sort.Strings(externalPolicies)
 
+			iamRole.ExternalPolicies = &externalPolicies
+
 			name := fmt.Sprintf("%s-policyoverride", roleKey)
 			t := &awstasks.IAMRolePolicy{
 				Name:             s(name),
@@ -213,6 +213,8 @@ type PolicyBuilder struct {
 	ResourceARN          *string
 	Role                 Subject
 	UseServiceAccountIAM bool
+	// UseWarmPools is true if instance groups of the role have a warm pool
+	UseWarmPools bool
 }
 
 // BuildAWSPolicy builds a set of IAM policy statements based on the
@@ -325,6 +327,10 @@ func (r *NodeRoleNode) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		addCalicoSrcDstCheckPermissions(p)
 	}
 
+	if b.UseWarmPools {
+		addWarmPoolPermissions(p, b.Cluster.GetName())
+	}
+
 	return p, nil
 }
 
@@ -787,6 +793,20 @@ func addNodeEC2Policies(p *Policy, resource stringorslice.StringOrSlice) {
 	})
 }
 
+// addWarmPoolPermissions allows nodeup to complete the lifecycle hook of autoscaling groups with a warm pool
+func addWarmPoolPermissions(p *Policy, clusterName string) {
+	p.Statement = append(p.Statement, &Statement{
+		Effect:   StatementEffectAllow,
+		Action:   stringorslice.Of("autoscaling:CompleteLifecycleAction"),
+		Resource: stringorslice.Slice([]string{"*"}),
+		Condition: Condition{
+			"StringEquals": map[string]string{
+				"autoscaling:ResourceTag/KubernetesCluster": clusterName,
+			},
+		},
+	})
+}
+
 func addMasterEC2Policies(p *Policy, resource stringorslice.StringOrSlice, legacyIAM bool, clusterName string) {
 	// The legacy IAM policy grants full ec2 API access
 	if legacyIAM {
@@ -97,6 +97,7 @@ func TestPolicyGeneration(t *testing.T) {
 		Role                   Subject
 		LegacyIAM              bool
 		AllowContainerRegistry bool
+		UseWarmPools           bool
 		Policy                 string
 	}{
 		{
@@ -135,6 +136,13 @@ func TestPolicyGeneration(t *testing.T) {
 			AllowContainerRegistry: true,
 			Policy:                 "tests/iam_builder_node_strict_ecr.json",
 		},
+		{
+			Role:                   &NodeRoleNode{},
+			LegacyIAM:              false,
+			AllowContainerRegistry: false,
+			UseWarmPools:           true,
+			Policy:                 "tests/iam_builder_node_strict_warmpool.json",
+		},
 		{
 			Role:                   &NodeRoleBastion{},
 			LegacyIAM:              true,
@@ -188,7 +196,8 @@ func TestPolicyGeneration(t *testing.T) {
 					},
 				},
 			},
-			Role: x.Role,
+			Role:         x.Role,
+			UseWarmPools: x.UseWarmPools,
 		}
 		b.Cluster.SetName("iam-builder-test.k8s.local")
 
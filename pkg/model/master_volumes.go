@@ -34,6 +34,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/dotasks"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gcetasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetznertasks"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstacktasks"
 )
@@ -113,6 +115,10 @@ func (b *MasterVolumeBuilder) Build(c *fi.ModelBuilderContext) error {
 				b.addALIVolume(c, name, volumeSize, zone, etcd, m, allMembers)
 			case kops.CloudProviderAzure:
 				b.addAzureVolume(c, name, volumeSize, zone, etcd, m, allMembers)
+			case kops.CloudProviderHetzner:
+				b.addHetznerVolume(c, name, volumeSize, zone, etcd, m, allMembers)
+			case kops.CloudProviderMetal:
+				// The etcd data lives on the local disks of the master hosts
 			default:
 				return fmt.Errorf("unknown cloudprovider %q", b.Cluster.Spec.CloudProvider)
 			}
@@ -382,3 +388,21 @@ func (b *MasterVolumeBuilder) addAzureVolume(
 	}
 	c.AddTask(t)
 }
+
+func (b *MasterVolumeBuilder) addHetznerVolume(c *fi.ModelBuilderContext, name string, volumeSize int32, zone string, etcd kops.EtcdClusterSpec, m kops.EtcdMemberSpec, allMembers []string) {
+	// The labels are used by protokube to find and attach the volume
+	labels := map[string]string{
+		hetzner.TagKubernetesClusterName: b.Cluster.ObjectMeta.Name,
+		hetzner.TagKubernetesVolumeRole:  etcd.Name,
+		hetzner.TagKubernetesEtcdMember:  m.Name,
+	}
+
+	t := &hetznertasks.Volume{
+		Name:      fi.String(strings.Replace(name, ".", "-", -1)),
+		Lifecycle: b.Lifecycle,
+		Size:      int(volumeSize),
+		Location:  zone,
+		Labels:    labels,
+	}
+	c.AddTask(t)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metalmodel
+
+import (
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metaltasks"
+)
+
+// HostModelBuilder enrolls the pre-provisioned hosts of the instance groups
+type HostModelBuilder struct {
+	*model.KopsModelContext
+
+	BootstrapScriptBuilder *model.BootstrapScriptBuilder
+	Lifecycle              *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &HostModelBuilder{}
+
+func (b *HostModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	for _, ig := range b.InstanceGroups {
+		if ig.Spec.Metal == nil {
+			continue
+		}
+
+		// All the hosts of an instance group share its bootstrap script; their own labels are applied by kops-controller
+		userData, err := b.BootstrapScriptBuilder.ResourceNodeUp(c, ig)
+		if err != nil {
+			return err
+		}
+
+		for _, host := range ig.Spec.Metal.Hosts {
+			t := &metaltasks.Host{
+				Name:       fi.String(host.Name),
+				Lifecycle:  b.Lifecycle,
+				Address:    fi.String(host.Address),
+				SSHPort:    host.SSHPort,
+				SSHHostKey: fi.String(host.SSHHostKey),
+				UserData:   userData,
+			}
+			if host.SSHUser != "" {
+				t.SSHUser = fi.String(host.SSHUser)
+			}
+			if host.SSHPrivateKeyFile != "" {
+				t.SSHPrivateKeyFile = fi.String(host.SSHPrivateKeyFile)
+			}
+			c.AddTask(t)
+		}
+	}
+
+	return nil
+}
@@ -74,6 +74,14 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 
 			t.EnableDNSHostnames = fi.Bool(true)
 			t.AssociateExtraCIDRBlocks = b.Cluster.Spec.AdditionalNetworkCIDRs
+
+			if b.Cluster.Spec.UsesIPv6() {
+				t.AmazonIPv6 = fi.Bool(true)
+			}
+		}
+
+		if b.Cluster.Spec.NetworkIPv6CIDR != "" {
+			t.IPv6CIDR = s(b.Cluster.Spec.NetworkIPv6CIDR)
 		}
 
 		if b.Cluster.Spec.NetworkID != "" {
@@ -184,6 +192,16 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 				RouteTable:      publicRouteTable,
 				InternetGateway: igw,
 			})
+
+			if b.Cluster.Spec.UsesIPv6() {
+				c.AddTask(&awstasks.Route{
+					Name:            s("::/0"),
+					Lifecycle:       b.Lifecycle,
+					IPv6CIDR:        s("::/0"),
+					RouteTable:      publicRouteTable,
+					InternetGateway: igw,
+				})
+			}
 		}
 	}
 
@@ -229,6 +247,9 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		if subnetSpec.ProviderID != "" {
 			subnet.ID = s(subnetSpec.ProviderID)
 		}
+		if subnetSpec.IPv6CIDR != "" {
+			subnet.IPv6CIDR = s(subnetSpec.IPv6CIDR)
+		}
 		c.AddTask(subnet)
 
 		switch subnetSpec.Type {
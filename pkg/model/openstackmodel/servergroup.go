@@ -239,6 +239,11 @@ func (b *ServerGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	}
 
 	if b.Cluster.Spec.CloudConfig.Openstack.Loadbalancer != nil {
+		lbSpec := &kops.LoadBalancerAccessSpec{}
+		if b.Cluster.Spec.API != nil && b.Cluster.Spec.API.LoadBalancer != nil {
+			lbSpec = b.Cluster.Spec.API.LoadBalancer
+		}
+
 		var lbSubnetName string
 		var err error
 		for _, sp := range b.Cluster.Spec.Subnets {
@@ -271,15 +276,26 @@ func (b *ServerGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			LB:        lbTask,
 			Lifecycle: b.Lifecycle,
 		}
+		if lbSpec.FloatingIP != "" {
+			lbfipTask.IP = fi.String(lbSpec.FloatingIP)
+			lbfipTask.Shared = fi.Bool(true)
+		}
 		c.AddTask(lbfipTask)
 
 		if dns.IsGossipHostname(b.Cluster.Name) || b.UsePrivateDNS() {
 			b.associateFIPToKeypair(lbfipTask)
 		}
 
+		// The TCP protocol forwards TLS as is too, but with HTTPS Octavia can also check the members with a TLS handshake
+		protocol, monitorType := "TCP", "TCP"
+		if lbSpec.TLSPassthrough {
+			protocol, monitorType = "HTTPS", "TLS-HELLO"
+		}
+
 		poolTask := &openstacktasks.LBPool{
 			Name:         fi.String(fmt.Sprintf("%s-https", fi.StringValue(lbTask.Name))),
 			Loadbalancer: lbTask,
+			Protocol:     fi.String(protocol),
 			Lifecycle:    b.Lifecycle,
 		}
 		c.AddTask(poolTask)
@@ -288,14 +304,42 @@ func (b *ServerGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			Name:      lbTask.Name,
 			Lifecycle: b.Lifecycle,
 			Pool:      poolTask,
+			Protocol:  fi.String(protocol),
 		}
 		if useVIPACL {
 			// sort for consistent comparison
 			sort.Strings(b.Cluster.Spec.KubernetesAPIAccess)
 			listenerTask.AllowedCIDRs = b.Cluster.Spec.KubernetesAPIAccess
 		}
+		if lbSpec.IdleTimeoutSeconds != nil {
+			timeout := int(*lbSpec.IdleTimeoutSeconds * 1000)
+			listenerTask.TimeoutClientData = fi.Int(timeout)
+			listenerTask.TimeoutMemberData = fi.Int(timeout)
+		}
 		c.AddTask(listenerTask)
 
+		if lbSpec.HealthMonitor != nil {
+			monitorTask := &openstacktasks.LBHealthMonitor{
+				Name:       poolTask.Name,
+				Pool:       poolTask,
+				Delay:      fi.Int(10),
+				Timeout:    fi.Int(5),
+				MaxRetries: fi.Int(3),
+				Type:       fi.String(monitorType),
+				Lifecycle:  b.Lifecycle,
+			}
+			if lbSpec.HealthMonitor.DelaySeconds != 0 {
+				monitorTask.Delay = fi.Int(lbSpec.HealthMonitor.DelaySeconds)
+			}
+			if lbSpec.HealthMonitor.TimeoutSeconds != 0 {
+				monitorTask.Timeout = fi.Int(lbSpec.HealthMonitor.TimeoutSeconds)
+			}
+			if lbSpec.HealthMonitor.MaxRetries != nil {
+				monitorTask.MaxRetries = fi.Int(*lbSpec.HealthMonitor.MaxRetries)
+			}
+			c.AddTask(monitorTask)
+		}
+
 		ifName, err := b.GetNetworkName()
 		if err != nil {
 			return err
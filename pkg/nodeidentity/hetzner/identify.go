@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	corev1 "k8s.io/api/core/v1"
+	kopsroot "k8s.io/kops"
+	"k8s.io/kops/pkg/nodeidentity"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+)
+
+// nodeIdentifier identifies a node from Hetzner Cloud
+type nodeIdentifier struct {
+	client *hcloud.Client
+}
+
+// New creates and returns a nodeidentity.LegacyIdentifier for Nodes running on Hetzner Cloud
+func New() (nodeidentity.LegacyIdentifier, error) {
+	token := os.Getenv("HCLOUD_TOKEN")
+	if token == "" {
+		return nil, errors.New("HCLOUD_TOKEN is required")
+	}
+
+	client := hcloud.NewClient(
+		hcloud.WithToken(token),
+		hcloud.WithApplication("kops", kopsroot.Version),
+	)
+
+	return &nodeIdentifier{
+		client: client,
+	}, nil
+}
+
+// IdentifyNode queries Hetzner Cloud for the node identity information
+func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*nodeidentity.LegacyInfo, error) {
+	providerID := node.Spec.ProviderID
+	if providerID == "" {
+		return nil, fmt.Errorf("providerID was not set for node %s", node.Name)
+	}
+
+	const prefix = "hcloud://"
+	if !strings.HasPrefix(providerID, prefix) {
+		return nil, fmt.Errorf("provider ID %q is missing prefix %q", providerID, prefix)
+	}
+
+	serverID, err := strconv.Atoi(strings.TrimPrefix(providerID, prefix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server ID from provider ID %q: %v", providerID, err)
+	}
+
+	server, _, err := i.client.Server.GetByID(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server %d: %v", serverID, err)
+	}
+	if server == nil {
+		return nil, fmt.Errorf("server %d not found", serverID)
+	}
+
+	instanceGroup := server.Labels[hetzner.TagKubernetesInstanceGroup]
+	if instanceGroup == "" {
+		return nil, fmt.Errorf("could not find label %q on server %q", hetzner.TagKubernetesInstanceGroup, server.Name)
+	}
+
+	info := &nodeidentity.LegacyInfo{
+		InstanceID:    strconv.Itoa(server.ID),
+		InstanceGroup: instanceGroup,
+	}
+
+	return info, nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kops/pkg/nodeidentity"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+)
+
+// nodeIdentifier identifies a node running on a pre-provisioned host
+type nodeIdentifier struct {
+}
+
+// New creates and returns a nodeidentity.LegacyIdentifier for Nodes running on pre-provisioned hosts.
+// There is no cloud API to query; the instance group is encoded in the provider ID set by nodeup.
+func New() (nodeidentity.LegacyIdentifier, error) {
+	return &nodeIdentifier{}, nil
+}
+
+// IdentifyNode parses the instance group from the provider ID of the node
+func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*nodeidentity.LegacyInfo, error) {
+	providerID := node.Spec.ProviderID
+	if providerID == "" {
+		return nil, errors.New("provider ID cannot be empty")
+	}
+
+	instanceGroup, _, err := metal.ParseProviderID(providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &nodeidentity.LegacyInfo{}
+	info.InstanceGroup = instanceGroup
+
+	return info, nil
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elbv2"
@@ -46,6 +47,8 @@ const (
 	TypeElasticIp               = "elastic-ip"
 	TypeLoadBalancer            = "load-balancer"
 	TypeTargetGroup             = "target-group"
+	TypeVPCEndpoint             = "vpc-endpoint"
+	TypeLogGroup                = "log-group"
 )
 
 type listFn func(fi.Cloud, string) ([]*resources.Resource, error)
@@ -64,6 +67,7 @@ func ListResourcesAWS(cloud awsup.AWSCloud, clusterName string) (map[string]*res
 		ListAutoScalingGroups,
 		ListInstances,
 		ListKeypairs,
+		ListPlacementGroups,
 		ListSecurityGroups,
 		ListVolumes,
 		// EC2 VPC
@@ -72,11 +76,16 @@ func ListResourcesAWS(cloud awsup.AWSCloud, clusterName string) (map[string]*res
 		ListRouteTables,
 		ListSubnets,
 		ListVPCs,
+		ListVPCEndpoints,
+		ListFlowLogs,
 		// ELBs
 		ListELBs,
 		ListELBV2s,
 		ListTargetGroups,
 
+		// CloudWatch Logs
+		ListLogGroups,
+
 		// Route 53
 		ListRoute53Records,
 		// IAM
@@ -446,6 +455,9 @@ func ListInstances(cloud fi.Cloud, clusterName string) ([]*resources.Resource, e
 				for _, sg := range instance.SecurityGroups {
 					blocks = append(blocks, "security-group:"+aws.StringValue(sg.GroupId))
 				}
+				if instance.Placement != nil && aws.StringValue(instance.Placement.GroupName) != "" {
+					blocks = append(blocks, ec2.ResourceTypePlacementGroup+":"+aws.StringValue(instance.Placement.GroupName))
+				}
 
 				resourceTracker.Blocks = blocks
 
@@ -742,6 +754,271 @@ func ListKeypairs(cloud fi.Cloud, clusterName string) ([]*resources.Resource, er
 	return resourceTrackers, nil
 }
 
+func DeletePlacementGroup(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(awsup.AWSCloud)
+
+	name := r.ID
+
+	klog.V(2).Infof("Deleting EC2 PlacementGroup %q", name)
+	request := &ec2.DeletePlacementGroupInput{
+		GroupName: &name,
+	}
+	_, err := c.EC2().DeletePlacementGroup(request)
+	if err != nil {
+		if awsup.AWSErrorCode(err) == "InvalidPlacementGroup.Unknown" {
+			klog.V(2).Infof("Got InvalidPlacementGroup.Unknown error deleting placement group %q; will treat as already-deleted", name)
+			return nil
+		} else if IsDependencyViolation(err) {
+			return err
+		}
+		return fmt.Errorf("error deleting PlacementGroup %q: %v", name, err)
+	}
+	return nil
+}
+
+func ListPlacementGroups(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	klog.V(2).Infof("Listing EC2 PlacementGroups")
+	request := &ec2.DescribePlacementGroupsInput{
+		Filters: BuildEC2Filters(c),
+	}
+	response, err := c.EC2().DescribePlacementGroups(request)
+	if err != nil {
+		return nil, fmt.Errorf("error listing PlacementGroups: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+
+	for _, pg := range response.PlacementGroups {
+		state := aws.StringValue(pg.State)
+		if state == ec2.PlacementGroupStateDeleting || state == ec2.PlacementGroupStateDeleted {
+			continue
+		}
+
+		name := aws.StringValue(pg.GroupName)
+		resourceTracker := &resources.Resource{
+			Name:    name,
+			ID:      name,
+			Type:    ec2.ResourceTypePlacementGroup,
+			Deleter: DeletePlacementGroup,
+			Shared:  HasSharedTag(ec2.ResourceTypePlacementGroup+":"+name, pg.Tags, clusterName),
+		}
+
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+func DeleteVPCEndpoint(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(awsup.AWSCloud)
+
+	id := r.ID
+
+	klog.V(2).Infof("Deleting EC2 VPCEndpoint %q", id)
+	request := &ec2.DeleteVpcEndpointsInput{
+		VpcEndpointIds: []*string{&id},
+	}
+	response, err := c.EC2().DeleteVpcEndpoints(request)
+	if err != nil {
+		if awsup.AWSErrorCode(err) == "InvalidVpcEndpointId.NotFound" {
+			klog.V(2).Infof("Got InvalidVpcEndpointId.NotFound error deleting VPC endpoint %q; will treat as already-deleted", id)
+			return nil
+		} else if IsDependencyViolation(err) {
+			return err
+		}
+		return fmt.Errorf("error deleting VPCEndpoint %q: %v", id, err)
+	}
+	for _, item := range response.Unsuccessful {
+		if item.Error != nil && aws.StringValue(item.Error.Code) != "InvalidVpcEndpointId.NotFound" {
+			return fmt.Errorf("error deleting VPCEndpoint %q: %s", id, aws.StringValue(item.Error.Message))
+		}
+	}
+	return nil
+}
+
+func ListVPCEndpoints(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	klog.V(2).Infof("Listing EC2 VPCEndpoints")
+	request := &ec2.DescribeVpcEndpointsInput{
+		Filters: BuildEC2Filters(c),
+	}
+	response, err := c.EC2().DescribeVpcEndpoints(request)
+	if err != nil {
+		return nil, fmt.Errorf("error listing VPCEndpoints: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+
+	for _, endpoint := range response.VpcEndpoints {
+		state := strings.ToLower(aws.StringValue(endpoint.State))
+		if state == "deleting" || state == "deleted" {
+			continue
+		}
+
+		id := aws.StringValue(endpoint.VpcEndpointId)
+		resourceTracker := &resources.Resource{
+			Name:    FindName(endpoint.Tags),
+			ID:      id,
+			Type:    TypeVPCEndpoint,
+			Deleter: DeleteVPCEndpoint,
+			Shared:  HasSharedTag(TypeVPCEndpoint+":"+id, endpoint.Tags, clusterName),
+		}
+		if resourceTracker.Name == "" {
+			resourceTracker.Name = id
+		}
+
+		// The endpoint blocks deletion of its VPC, subnets, security groups and route tables
+		var blocks []string
+		blocks = append(blocks, "vpc:"+aws.StringValue(endpoint.VpcId))
+		for _, subnetID := range endpoint.SubnetIds {
+			blocks = append(blocks, "subnet:"+aws.StringValue(subnetID))
+		}
+		for _, group := range endpoint.Groups {
+			blocks = append(blocks, "security-group:"+aws.StringValue(group.GroupId))
+		}
+		for _, routeTableID := range endpoint.RouteTableIds {
+			blocks = append(blocks, ec2.ResourceTypeRouteTable+":"+aws.StringValue(routeTableID))
+		}
+		resourceTracker.Blocks = blocks
+
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+func DeleteFlowLog(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(awsup.AWSCloud)
+
+	id := r.ID
+
+	klog.V(2).Infof("Deleting EC2 FlowLog %q", id)
+	request := &ec2.DeleteFlowLogsInput{
+		FlowLogIds: []*string{&id},
+	}
+	response, err := c.EC2().DeleteFlowLogs(request)
+	if err != nil {
+		if awsup.AWSErrorCode(err) == "InvalidFlowLogId.NotFound" {
+			klog.V(2).Infof("Got InvalidFlowLogId.NotFound error deleting flow log %q; will treat as already-deleted", id)
+			return nil
+		}
+		return fmt.Errorf("error deleting FlowLog %q: %v", id, err)
+	}
+	for _, item := range response.Unsuccessful {
+		if item.Error != nil && aws.StringValue(item.Error.Code) != "InvalidFlowLogId.NotFound" {
+			return fmt.Errorf("error deleting FlowLog %q: %s", id, aws.StringValue(item.Error.Message))
+		}
+	}
+	return nil
+}
+
+func ListFlowLogs(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	klog.V(2).Infof("Listing EC2 FlowLogs")
+	request := &ec2.DescribeFlowLogsInput{
+		Filter: BuildEC2Filters(c),
+	}
+	response, err := c.EC2().DescribeFlowLogs(request)
+	if err != nil {
+		return nil, fmt.Errorf("error listing FlowLogs: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+
+	for _, flowLog := range response.FlowLogs {
+		id := aws.StringValue(flowLog.FlowLogId)
+		resourceTracker := &resources.Resource{
+			Name:    FindName(flowLog.Tags),
+			ID:      id,
+			Type:    ec2.ResourceTypeVpcFlowLog,
+			Deleter: DeleteFlowLog,
+			Shared:  HasSharedTag(ec2.ResourceTypeVpcFlowLog+":"+id, flowLog.Tags, clusterName),
+		}
+		if resourceTracker.Name == "" {
+			resourceTracker.Name = id
+		}
+
+		// The flow log blocks deletion of its VPC
+		if strings.HasPrefix(aws.StringValue(flowLog.ResourceId), "vpc-") {
+			resourceTracker.Blocks = append(resourceTracker.Blocks, "vpc:"+aws.StringValue(flowLog.ResourceId))
+		}
+
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+func DeleteLogGroup(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(awsup.AWSCloud)
+
+	name := r.ID
+
+	klog.V(2).Infof("Deleting CloudWatch Logs LogGroup %q", name)
+	request := &cloudwatchlogs.DeleteLogGroupInput{
+		LogGroupName: aws.String(name),
+	}
+	if _, err := c.CloudWatchLogs().DeleteLogGroup(request); err != nil {
+		if awsup.AWSErrorCode(err) == cloudwatchlogs.ErrCodeResourceNotFoundException {
+			klog.V(2).Infof("Got ResourceNotFoundException deleting log group %q; will treat as already-deleted", name)
+			return nil
+		}
+		return fmt.Errorf("error deleting LogGroup %q: %v", name, err)
+	}
+	return nil
+}
+
+// ListLogGroups lists the log groups of the flow logs of the cluster, which are owned by the cluster
+func ListLogGroups(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	name := "flowlogs." + clusterName
+
+	klog.V(2).Infof("Listing CloudWatch Logs LogGroups")
+	request := &cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(name),
+	}
+	var logGroups []*cloudwatchlogs.LogGroup
+	err := c.CloudWatchLogs().DescribeLogGroupsPages(request, func(page *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
+		for _, logGroup := range page.LogGroups {
+			if aws.StringValue(logGroup.LogGroupName) == name {
+				logGroups = append(logGroups, logGroup)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing LogGroups: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+
+	for _, logGroup := range logGroups {
+		response, err := c.CloudWatchLogs().ListTagsLogGroup(&cloudwatchlogs.ListTagsLogGroupInput{LogGroupName: logGroup.LogGroupName})
+		if err != nil {
+			return nil, fmt.Errorf("error listing tags of LogGroup %q: %v", aws.StringValue(logGroup.LogGroupName), err)
+		}
+		if aws.StringValue(response.Tags["kubernetes.io/cluster/"+clusterName]) != "owned" {
+			klog.Warningf("LogGroup %q is not owned by the cluster; skipping", aws.StringValue(logGroup.LogGroupName))
+			continue
+		}
+
+		resourceTracker := &resources.Resource{
+			Name:    aws.StringValue(logGroup.LogGroupName),
+			ID:      aws.StringValue(logGroup.LogGroupName),
+			Type:    TypeLogGroup,
+			Deleter: DeleteLogGroup,
+		}
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
 func DeleteSubnet(cloud fi.Cloud, tracker *resources.Resource) error {
 	c := cloud.(awsup.AWSCloud)
 
@@ -1937,6 +2214,7 @@ func ListIAMRoles(cloud fi.Cloud, clusterName string) ([]*resources.Resource, er
 	remove["masters."+clusterName] = true
 	remove["nodes."+clusterName] = true
 	remove["bastions."+clusterName] = true
+	remove["flowlogs."+clusterName] = true
 
 	var roles []*iam.Role
 	// Find roles matching remove map
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-06-01/network"
 	authz "github.com/Azure/azure-sdk-for-go/services/preview/authorization/mgmt/2018-01-01-preview/authorization"
 	azureresources "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2020-06-01/resources"
@@ -32,15 +33,17 @@ import (
 )
 
 const (
-	typeResourceGroup   = "ResourceGroup"
-	typeVirtualNetwork  = "VirtualNetwork"
-	typeSubnet          = "Subnet"
-	typeRouteTable      = "RouteTable"
-	typeVMScaleSet      = "VMScaleSet"
-	typeDisk            = "Disk"
-	typeRoleAssignment  = "RoleAssignment"
-	typeLoadBalancer    = "LoadBalancer"
-	typePublicIPAddress = "PublicIPAddress"
+	typeResourceGroup        = "ResourceGroup"
+	typeVirtualNetwork       = "VirtualNetwork"
+	typeSubnet               = "Subnet"
+	typeRouteTable           = "RouteTable"
+	typeVMScaleSet           = "VMScaleSet"
+	typeDisk                 = "Disk"
+	typeRoleAssignment       = "RoleAssignment"
+	typeLoadBalancer         = "LoadBalancer"
+	typePublicIPAddress      = "PublicIPAddress"
+	typeNetworkSecurityGroup = "NetworkSecurityGroup"
+	typeManagedIdentity      = "ManagedIdentity"
 )
 
 // ListResourcesAzure lists all resources for the cluster by quering Azure.
@@ -91,6 +94,7 @@ func (g *resourceGetter) listAll() ([]*resources.Resource, error) {
 		g.listDisks,
 		g.listLoadBalancers,
 		g.listPublicIPAddresses,
+		g.listNetworkSecurityGroups,
 	}
 
 	var resources []*resources.Resource
@@ -250,7 +254,8 @@ func (g *resourceGetter) listVMScaleSetsAndRoleAssignments(ctx context.Context)
 	}
 
 	var rs []*resources.Resource
-	principalIDs := map[string]*compute.VirtualMachineScaleSet{}
+	// principalIDs maps the principal IDs of identities to the keys of the resources they belong to.
+	principalIDs := map[string]string{}
 	for i := range vmsses {
 		vmss := &vmsses[i]
 		if !g.isOwnedByCluster(vmss.Tags) {
@@ -268,8 +273,23 @@ func (g *resourceGetter) listVMScaleSetsAndRoleAssignments(ctx context.Context)
 		}
 		rs = append(rs, r)
 
-		principalIDs[*vmss.Identity.PrincipalID] = vmss
+		// VM Scale Sets created by older versions of kops have system-assigned identities.
+		if vmss.Identity != nil && vmss.Identity.PrincipalID != nil {
+			principalIDs[*vmss.Identity.PrincipalID] = toKey(r.Type, r.ID)
+		}
+	}
+
+	identities, err := g.listManagedIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range identities {
+		identity := r.Obj.(*msi.Identity)
+		if identity.UserAssignedIdentityProperties != nil && identity.PrincipalID != nil {
+			principalIDs[identity.PrincipalID.String()] = toKey(r.Type, r.ID)
+		}
 	}
+	rs = append(rs, identities...)
 
 	ras, err := g.listRoleAssignments(ctx, principalIDs)
 	if err != nil {
@@ -287,7 +307,15 @@ func (g *resourceGetter) toVMScaleSetResource(vmss *compute.VirtualMachineScaleS
 
 	vnets := map[string]struct{}{}
 	subnets := map[string]struct{}{}
+	nsgs := map[string]struct{}{}
 	for _, iface := range *vmss.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations {
+		if nsg := iface.NetworkSecurityGroup; nsg != nil && nsg.ID != nil {
+			nsgID, err := azuretasks.ParseNetworkSecurityGroupID(*nsg.ID)
+			if err != nil {
+				return nil, fmt.Errorf("error on parsing network security group ID: %s", err)
+			}
+			nsgs[nsgID.NetworkSecurityGroupName] = struct{}{}
+		}
 		for _, ip := range *iface.IPConfigurations {
 			subnetID, err := azuretasks.ParseSubnetID(*ip.Subnet.ID)
 			if err != nil {
@@ -303,6 +331,18 @@ func (g *resourceGetter) toVMScaleSetResource(vmss *compute.VirtualMachineScaleS
 	for subnet := range subnets {
 		blocks = append(blocks, toKey(typeSubnet, subnet))
 	}
+	for nsg := range nsgs {
+		blocks = append(blocks, toKey(typeNetworkSecurityGroup, nsg))
+	}
+	if vmss.Identity != nil {
+		for id := range vmss.Identity.UserAssignedIdentities {
+			identityID, err := azuretasks.ParseManagedIdentityID(id)
+			if err != nil {
+				return nil, fmt.Errorf("error on parsing managed identity ID: %s", err)
+			}
+			blocks = append(blocks, toKey(typeManagedIdentity, identityID.ManagedIdentityName))
+		}
+	}
 
 	for _, vm := range vms {
 		if disks := vm.StorageProfile.DataDisks; disks != nil {
@@ -358,7 +398,39 @@ func (g *resourceGetter) deleteDisk(_ fi.Cloud, r *resources.Resource) error {
 	return g.cloud.Disk().Delete(context.TODO(), g.resourceGroupName(), r.Name)
 }
 
-func (g *resourceGetter) listRoleAssignments(ctx context.Context, principalIDs map[string]*compute.VirtualMachineScaleSet) ([]*resources.Resource, error) {
+func (g *resourceGetter) listManagedIdentities(ctx context.Context) ([]*resources.Resource, error) {
+	identities, err := g.cloud.ManagedIdentity().List(ctx, g.resourceGroupName())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range identities {
+		identity := &identities[i]
+		if !g.isOwnedByCluster(identity.Tags) {
+			continue
+		}
+		rs = append(rs, g.toManagedIdentityResource(identity))
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) toManagedIdentityResource(identity *msi.Identity) *resources.Resource {
+	return &resources.Resource{
+		Obj:     identity,
+		Type:    typeManagedIdentity,
+		ID:      *identity.Name,
+		Name:    *identity.Name,
+		Deleter: g.deleteManagedIdentity,
+		Blocks:  []string{toKey(typeResourceGroup, g.resourceGroupName())},
+	}
+}
+
+func (g *resourceGetter) deleteManagedIdentity(_ fi.Cloud, r *resources.Resource) error {
+	return g.cloud.ManagedIdentity().Delete(context.TODO(), g.resourceGroupName(), r.Name)
+}
+
+func (g *resourceGetter) listRoleAssignments(ctx context.Context, principalIDs map[string]string) ([]*resources.Resource, error) {
 	ras, err := g.cloud.RoleAssignment().List(ctx, g.resourceGroupName())
 	if err != nil {
 		return nil, err
@@ -366,21 +438,21 @@ func (g *resourceGetter) listRoleAssignments(ctx context.Context, principalIDs m
 
 	var rs []*resources.Resource
 	for i := range ras {
-		// Add a Role Assignment to the slice if its principal ID is that of one of the VM Scale Sets.
+		// Add a Role Assignment to the slice if its principal ID is that of one of the identities of the cluster.
 		ra := &ras[i]
 		if ra.PrincipalID == nil {
 			continue
 		}
-		vmss, ok := principalIDs[*ra.PrincipalID]
+		owner, ok := principalIDs[*ra.PrincipalID]
 		if !ok {
 			continue
 		}
-		rs = append(rs, g.toRoleAssignmentResource(ra, vmss))
+		rs = append(rs, g.toRoleAssignmentResource(ra, owner))
 	}
 	return rs, nil
 }
 
-func (g *resourceGetter) toRoleAssignmentResource(ra *authz.RoleAssignment, vmss *compute.VirtualMachineScaleSet) *resources.Resource {
+func (g *resourceGetter) toRoleAssignmentResource(ra *authz.RoleAssignment, owner string) *resources.Resource {
 	return &resources.Resource{
 		Obj:     ra,
 		Type:    typeRoleAssignment,
@@ -389,7 +461,7 @@ func (g *resourceGetter) toRoleAssignmentResource(ra *authz.RoleAssignment, vmss
 		Deleter: g.deleteRoleAssignment,
 		Blocks: []string{
 			toKey(typeResourceGroup, g.resourceGroupName()),
-			toKey(typeVMScaleSet, *vmss.Name),
+			owner,
 		},
 	}
 }
@@ -466,6 +538,38 @@ func (g *resourceGetter) deletePublicIPAddress(_ fi.Cloud, r *resources.Resource
 	return g.cloud.PublicIPAddress().Delete(context.TODO(), g.resourceGroupName(), r.Name)
 }
 
+func (g *resourceGetter) listNetworkSecurityGroups(ctx context.Context) ([]*resources.Resource, error) {
+	nsgs, err := g.cloud.NetworkSecurityGroup().List(ctx, g.resourceGroupName())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range nsgs {
+		nsg := &nsgs[i]
+		if !g.isOwnedByCluster(nsg.Tags) {
+			continue
+		}
+		rs = append(rs, g.toNetworkSecurityGroupResource(nsg))
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) toNetworkSecurityGroupResource(nsg *network.SecurityGroup) *resources.Resource {
+	return &resources.Resource{
+		Obj:     nsg,
+		Type:    typeNetworkSecurityGroup,
+		ID:      *nsg.Name,
+		Name:    *nsg.Name,
+		Deleter: g.deleteNetworkSecurityGroup,
+		Blocks:  []string{toKey(typeResourceGroup, g.resourceGroupName())},
+	}
+}
+
+func (g *resourceGetter) deleteNetworkSecurityGroup(_ fi.Cloud, r *resources.Resource) error {
+	return g.cloud.NetworkSecurityGroup().Delete(context.TODO(), g.resourceGroupName(), r.Name)
+}
+
 // isOwnedByCluster returns true if the resource is owned by the cluster.
 func (g *resourceGetter) isOwnedByCluster(tags map[string]*string) bool {
 	for k, v := range tags {
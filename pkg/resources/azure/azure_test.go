@@ -17,11 +17,13 @@ limitations under the License.
 package azure
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-06-01/network"
 	authz "github.com/Azure/azure-sdk-for-go/services/preview/authorization/mgmt/2018-01-01-preview/authorization"
 	azureresources "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2020-06-01/resources"
@@ -47,6 +49,9 @@ func TestListResourcesAzure(t *testing.T) {
 		irrelevantName = "irrelevant"
 		principalID    = "pid"
 		lbName         = "lb"
+		nsgName        = "nsg"
+		identityName   = "mi"
+		identityRAName = "ra-mi"
 	)
 	clusterTags := map[string]*string{
 		azure.TagClusterName: to.StringPtr(clusterName),
@@ -96,6 +101,16 @@ func TestListResourcesAzure(t *testing.T) {
 		VirtualNetworkName: vnetName,
 		SubnetName:         subnetName,
 	}
+	nsgID := azuretasks.NetworkSecurityGroupID{
+		SubscriptionID:           "sid",
+		ResourceGroupName:        rgName,
+		NetworkSecurityGroupName: nsgName,
+	}
+	identityID := azuretasks.ManagedIdentityID{
+		SubscriptionID:      "sid",
+		ResourceGroupName:   rgName,
+		ManagedIdentityName: identityName,
+	}
 	networkConfig := compute.VirtualMachineScaleSetNetworkConfiguration{
 		VirtualMachineScaleSetNetworkConfigurationProperties: &compute.VirtualMachineScaleSetNetworkConfigurationProperties{
 			IPConfigurations: &[]compute.VirtualMachineScaleSetIPConfiguration{
@@ -107,6 +122,9 @@ func TestListResourcesAzure(t *testing.T) {
 					},
 				},
 			},
+			NetworkSecurityGroup: &compute.SubResource{
+				ID: to.StringPtr(nsgID.String()),
+			},
 		},
 	}
 	vmsses[vmssName] = compute.VirtualMachineScaleSet{
@@ -121,9 +139,14 @@ func TestListResourcesAzure(t *testing.T) {
 				},
 			},
 		},
+		// The VM Scale Set has both the system-assigned identity created by older
+		// versions of kops and a user-assigned identity.
 		Identity: &compute.VirtualMachineScaleSetIdentity{
-			Type:        compute.ResourceIdentityTypeSystemAssigned,
+			Type:        compute.ResourceIdentityTypeSystemAssignedUserAssigned,
 			PrincipalID: to.StringPtr(principalID),
+			UserAssignedIdentities: map[string]*compute.VirtualMachineScaleSetIdentityUserAssignedIdentitiesValue{
+				identityID.String(): {},
+			},
 		},
 	}
 	vmsses[irrelevantName] = compute.VirtualMachineScaleSet{
@@ -164,6 +187,32 @@ func TestListResourcesAzure(t *testing.T) {
 		Name: to.StringPtr(irrelevantName),
 	}
 
+	identity, err := cloud.ManagedIdentity().CreateOrUpdate(context.TODO(), rgName, identityName, msi.Identity{
+		Tags: clusterTags,
+	})
+	if err != nil {
+		t.Fatalf("failed to create: %s", err)
+	}
+	if _, err := cloud.ManagedIdentity().CreateOrUpdate(context.TODO(), rgName, irrelevantName, msi.Identity{}); err != nil {
+		t.Fatalf("failed to create: %s", err)
+	}
+	ras[identityRAName] = authz.RoleAssignment{
+		Name: to.StringPtr(identityRAName),
+		RoleAssignmentPropertiesWithScope: &authz.RoleAssignmentPropertiesWithScope{
+			Scope:       to.StringPtr("scope"),
+			PrincipalID: to.StringPtr(identity.PrincipalID.String()),
+		},
+	}
+
+	nsgs := cloud.NetworkSecurityGroupsClient.NSGs
+	nsgs[nsgName] = network.SecurityGroup{
+		Name: to.StringPtr(nsgName),
+		Tags: clusterTags,
+	}
+	nsgs[irrelevantName] = network.SecurityGroup{
+		Name: to.StringPtr(irrelevantName),
+	}
+
 	lbs := cloud.LoadBalancersClient.LBs
 	lbs[lbName] = network.LoadBalancer{
 		Name: to.StringPtr(lbName),
@@ -247,6 +296,8 @@ func TestListResourcesAzure(t *testing.T) {
 				toKey(typeResourceGroup, rgName),
 				toKey(typeVirtualNetwork, vnetName),
 				toKey(typeSubnet, subnetName),
+				toKey(typeNetworkSecurityGroup, nsgName),
+				toKey(typeManagedIdentity, identityName),
 				toKey(typeDisk, diskName),
 			},
 		},
@@ -263,6 +314,24 @@ func TestListResourcesAzure(t *testing.T) {
 				toKey(typeVMScaleSet, vmssName),
 			},
 		},
+		toKey(typeManagedIdentity, identityName): {
+			rtype:  typeManagedIdentity,
+			name:   identityName,
+			blocks: []string{toKey(typeResourceGroup, rgName)},
+		},
+		toKey(typeRoleAssignment, identityRAName): {
+			rtype: typeRoleAssignment,
+			name:  identityRAName,
+			blocks: []string{
+				toKey(typeResourceGroup, rgName),
+				toKey(typeManagedIdentity, identityName),
+			},
+		},
+		toKey(typeNetworkSecurityGroup, nsgName): {
+			rtype:  typeNetworkSecurityGroup,
+			name:   nsgName,
+			blocks: []string{toKey(typeResourceGroup, rgName)},
+		},
 		toKey(typeLoadBalancer, lbName): {
 			rtype:  typeLoadBalancer,
 			name:   lbName,
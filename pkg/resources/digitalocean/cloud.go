@@ -194,6 +194,24 @@ func (c *Cloud) GetAllLoadBalancers() ([]godo.LoadBalancer, error) {
 	return getAllLoadBalancers(c)
 }
 
+// VPCs returns an implementation of godo.VPCsService
+func (c *Cloud) VPCs() godo.VPCsService {
+	return c.Client.VPCs
+}
+
+func (c *Cloud) GetAllVPCs() ([]*godo.VPC, error) {
+	return getAllVPCs(c)
+}
+
+// Firewalls returns an implementation of godo.FirewallsService
+func (c *Cloud) Firewalls() godo.FirewallsService {
+	return c.Client.Firewalls
+}
+
+func (c *Cloud) GetAllFirewalls() ([]godo.Firewall, error) {
+	return getAllFirewalls(c)
+}
+
 // FindVPCInfo is not implemented, it's only here to satisfy the fi.Cloud interface
 func (c *Cloud) FindVPCInfo(id string) (*fi.VPCInfo, error) {
 	return nil, errors.New("not implemented")
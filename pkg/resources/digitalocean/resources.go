@@ -37,6 +37,8 @@ const (
 	resourceTypeVolume       = "volume"
 	resourceTypeDNSRecord    = "dns-record"
 	resourceTypeLoadBalancer = "loadbalancer"
+	resourceTypeVPC          = "vpc"
+	resourceTypeFirewall     = "firewall"
 )
 
 type listFn func(fi.Cloud, string) ([]*resources.Resource, error)
@@ -49,6 +51,8 @@ func ListResources(cloud *Cloud, clusterName string) (map[string]*resources.Reso
 		listDroplets,
 		listDNS,
 		listLoadBalancers,
+		listFirewalls,
+		listVPCs,
 	}
 
 	for _, fn := range listFunctions {
@@ -84,6 +88,10 @@ func listDroplets(cloud fi.Cloud, clusterName string) ([]*resources.Resource, er
 			Obj:     droplet,
 		}
 
+		if droplet.VPCUUID != "" {
+			resourceTracker.Blocks = append(resourceTracker.Blocks, resourceTypeVPC+":"+droplet.VPCUUID)
+		}
+
 		resourceTrackers = append(resourceTrackers, resourceTracker)
 	}
 
@@ -292,6 +300,9 @@ func listLoadBalancers(cloud fi.Cloud, clusterName string) ([]*resources.Resourc
 			for _, dropletID := range lb.DropletIDs {
 				blocks = append(blocks, "droplet:"+strconv.Itoa(dropletID))
 			}
+			if lb.VPCUUID != "" {
+				blocks = append(blocks, resourceTypeVPC+":"+lb.VPCUUID)
+			}
 
 			resourceTracker.Blocks = blocks
 			resourceTrackers = append(resourceTrackers, resourceTracker)
@@ -328,6 +339,126 @@ func getAllLoadBalancers(cloud *Cloud) ([]godo.LoadBalancer, error) {
 	return allLoadBalancers, nil
 }
 
+func listFirewalls(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(*Cloud)
+	var resourceTrackers []*resources.Resource
+
+	// firewall names must match those created by the DigitalOcean model
+	safeClusterName := strings.Replace(clusterName, ".", "-", -1)
+	firewallNames := map[string]bool{
+		"k8s-" + safeClusterName:     true,
+		"masters-" + safeClusterName: true,
+	}
+
+	firewalls, err := getAllFirewalls(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list firewalls: %v", err)
+	}
+
+	for _, firewall := range firewalls {
+		if !firewallNames[firewall.Name] {
+			continue
+		}
+
+		resourceTracker := &resources.Resource{
+			Name:    firewall.Name,
+			ID:      firewall.ID,
+			Type:    resourceTypeFirewall,
+			Deleter: deleteFirewall,
+			Obj:     firewall,
+		}
+
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+func getAllFirewalls(cloud *Cloud) ([]godo.Firewall, error) {
+	allFirewalls := []godo.Firewall{}
+
+	opt := &godo.ListOptions{}
+	for {
+		firewalls, resp, err := cloud.Firewalls().List(context.TODO(), opt)
+		if err != nil {
+			return nil, err
+		}
+
+		allFirewalls = append(allFirewalls, firewalls...)
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+
+		opt.Page = page + 1
+	}
+
+	return allFirewalls, nil
+}
+
+func listVPCs(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(*Cloud)
+	var resourceTrackers []*resources.Resource
+
+	// only the VPC created for the cluster is deleted, a shared VPC is named by its owner
+	vpcName := strings.Replace(clusterName, ".", "-", -1)
+
+	vpcs, err := getAllVPCs(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vpcs: %v", err)
+	}
+
+	for _, vpc := range vpcs {
+		if vpc.Name != vpcName || vpc.Default {
+			continue
+		}
+
+		resourceTracker := &resources.Resource{
+			Name:    vpc.Name,
+			ID:      vpc.ID,
+			Type:    resourceTypeVPC,
+			Deleter: deleteVPC,
+			Obj:     vpc,
+		}
+
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+func getAllVPCs(cloud *Cloud) ([]*godo.VPC, error) {
+	allVPCs := []*godo.VPC{}
+
+	opt := &godo.ListOptions{}
+	for {
+		vpcs, resp, err := cloud.VPCs().List(context.TODO(), opt)
+		if err != nil {
+			return nil, err
+		}
+
+		allVPCs = append(allVPCs, vpcs...)
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+
+		opt.Page = page + 1
+	}
+
+	return allVPCs, nil
+}
+
 func deleteDroplet(cloud fi.Cloud, t *resources.Resource) error {
 	c := cloud.(*Cloud)
 
@@ -390,6 +521,30 @@ func deleteLoadBalancer(cloud fi.Cloud, t *resources.Resource) error {
 	return nil
 }
 
+func deleteFirewall(cloud fi.Cloud, t *resources.Resource) error {
+	c := cloud.(*Cloud)
+	firewall := t.Obj.(godo.Firewall)
+
+	_, err := c.Firewalls().Delete(context.TODO(), firewall.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete firewall with name %s %v", firewall.Name, err)
+	}
+
+	return nil
+}
+
+func deleteVPC(cloud fi.Cloud, t *resources.Resource) error {
+	c := cloud.(*Cloud)
+	vpc := t.Obj.(*godo.VPC)
+
+	_, err := c.VPCs().Delete(context.TODO(), vpc.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete vpc with name %s %v", vpc.Name, err)
+	}
+
+	return nil
+}
+
 func waitForDetach(cloud *Cloud, action *godo.Action) error {
 	timeout := time.After(10 * time.Second)
 	ticker := time.NewTicker(500 * time.Millisecond)
@@ -0,0 +1,327 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+)
+
+const (
+	resourceTypeServer         = "server"
+	resourceTypeVolume         = "volume"
+	resourceTypeLoadBalancer   = "load-balancer"
+	resourceTypeFirewall       = "firewall"
+	resourceTypePlacementGroup = "placement-group"
+	resourceTypeNetwork        = "network"
+	resourceTypeSSHKey         = "ssh-key"
+)
+
+type listFn func(hetzner.HetznerCloud, string) ([]*resources.Resource, error)
+
+// ListResources returns all the resources labelled as belonging to the cluster
+func ListResources(cloud hetzner.HetznerCloud, clusterName string) (map[string]*resources.Resource, error) {
+	resourceTrackers := make(map[string]*resources.Resource)
+
+	listFunctions := []listFn{
+		listServers,
+		listVolumes,
+		listLoadBalancers,
+		listFirewalls,
+		listPlacementGroups,
+		listNetworks,
+		listSSHKeys,
+	}
+
+	for _, fn := range listFunctions {
+		rt, err := fn(cloud, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range rt {
+			resourceTrackers[t.Type+":"+t.ID] = t
+		}
+	}
+
+	return resourceTrackers, nil
+}
+
+func listOpts(clusterName string) hcloud.ListOpts {
+	return hcloud.ListOpts{
+		LabelSelector: hetzner.ClusterLabelSelector(clusterName),
+	}
+}
+
+func listServers(cloud hetzner.HetznerCloud, clusterName string) ([]*resources.Resource, error) {
+	servers, err := cloud.ServerClient().AllWithOpts(context.TODO(), hcloud.ServerListOpts{ListOpts: listOpts(clusterName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, server := range servers {
+		resourceTracker := &resources.Resource{
+			Name:    server.Name,
+			ID:      strconv.Itoa(server.ID),
+			Type:    resourceTypeServer,
+			Deleter: deleteServer,
+			Obj:     server,
+		}
+
+		var blocks []string
+		for _, volume := range server.Volumes {
+			blocks = append(blocks, resourceTypeVolume+":"+strconv.Itoa(volume.ID))
+		}
+		for _, privateNet := range server.PrivateNet {
+			if privateNet.Network != nil {
+				blocks = append(blocks, resourceTypeNetwork+":"+strconv.Itoa(privateNet.Network.ID))
+			}
+		}
+		if server.PlacementGroup != nil {
+			blocks = append(blocks, resourceTypePlacementGroup+":"+strconv.Itoa(server.PlacementGroup.ID))
+		}
+		resourceTracker.Blocks = blocks
+
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+func listVolumes(cloud hetzner.HetznerCloud, clusterName string) ([]*resources.Resource, error) {
+	volumes, err := cloud.VolumeClient().AllWithOpts(context.TODO(), hcloud.VolumeListOpts{ListOpts: listOpts(clusterName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, volume := range volumes {
+		resourceTrackers = append(resourceTrackers, &resources.Resource{
+			Name:    volume.Name,
+			ID:      strconv.Itoa(volume.ID),
+			Type:    resourceTypeVolume,
+			Deleter: deleteVolume,
+			Obj:     volume,
+		})
+	}
+
+	return resourceTrackers, nil
+}
+
+func listLoadBalancers(cloud hetzner.HetznerCloud, clusterName string) ([]*resources.Resource, error) {
+	loadBalancers, err := cloud.LoadBalancerClient().AllWithOpts(context.TODO(), hcloud.LoadBalancerListOpts{ListOpts: listOpts(clusterName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list load balancers: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, loadBalancer := range loadBalancers {
+		resourceTracker := &resources.Resource{
+			Name:    loadBalancer.Name,
+			ID:      strconv.Itoa(loadBalancer.ID),
+			Type:    resourceTypeLoadBalancer,
+			Deleter: deleteLoadBalancer,
+			Obj:     loadBalancer,
+		}
+
+		var blocks []string
+		for _, privateNet := range loadBalancer.PrivateNet {
+			if privateNet.Network != nil {
+				blocks = append(blocks, resourceTypeNetwork+":"+strconv.Itoa(privateNet.Network.ID))
+			}
+		}
+		resourceTracker.Blocks = blocks
+
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+func listFirewalls(cloud hetzner.HetznerCloud, clusterName string) ([]*resources.Resource, error) {
+	firewalls, err := cloud.FirewallClient().AllWithOpts(context.TODO(), hcloud.FirewallListOpts{ListOpts: listOpts(clusterName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list firewalls: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, firewall := range firewalls {
+		resourceTrackers = append(resourceTrackers, &resources.Resource{
+			Name:    firewall.Name,
+			ID:      strconv.Itoa(firewall.ID),
+			Type:    resourceTypeFirewall,
+			Deleter: deleteFirewall,
+			Obj:     firewall,
+		})
+	}
+
+	return resourceTrackers, nil
+}
+
+func listPlacementGroups(cloud hetzner.HetznerCloud, clusterName string) ([]*resources.Resource, error) {
+	placementGroups, err := cloud.PlacementGroupClient().AllWithOpts(context.TODO(), hcloud.PlacementGroupListOpts{ListOpts: listOpts(clusterName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list placement groups: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, placementGroup := range placementGroups {
+		resourceTrackers = append(resourceTrackers, &resources.Resource{
+			Name:    placementGroup.Name,
+			ID:      strconv.Itoa(placementGroup.ID),
+			Type:    resourceTypePlacementGroup,
+			Deleter: deletePlacementGroup,
+			Obj:     placementGroup,
+		})
+	}
+
+	return resourceTrackers, nil
+}
+
+func listNetworks(cloud hetzner.HetznerCloud, clusterName string) ([]*resources.Resource, error) {
+	networks, err := cloud.NetworkClient().AllWithOpts(context.TODO(), hcloud.NetworkListOpts{ListOpts: listOpts(clusterName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list networks: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, network := range networks {
+		resourceTrackers = append(resourceTrackers, &resources.Resource{
+			Name:    network.Name,
+			ID:      strconv.Itoa(network.ID),
+			Type:    resourceTypeNetwork,
+			Deleter: deleteNetwork,
+			Obj:     network,
+		})
+	}
+
+	return resourceTrackers, nil
+}
+
+func listSSHKeys(cloud hetzner.HetznerCloud, clusterName string) ([]*resources.Resource, error) {
+	sshKeys, err := cloud.SSHKeyClient().AllWithOpts(context.TODO(), hcloud.SSHKeyListOpts{ListOpts: listOpts(clusterName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSH keys: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, sshKey := range sshKeys {
+		resourceTrackers = append(resourceTrackers, &resources.Resource{
+			Name:    sshKey.Name,
+			ID:      strconv.Itoa(sshKey.ID),
+			Type:    resourceTypeSSHKey,
+			Deleter: deleteSSHKey,
+			Obj:     sshKey,
+		})
+	}
+
+	return resourceTrackers, nil
+}
+
+func deleteServer(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(hetzner.HetznerCloud)
+	server := r.Obj.(*hcloud.Server)
+
+	if _, err := c.ServerClient().Delete(context.TODO(), server); err != nil {
+		return fmt.Errorf("failed to delete server %q: %v", server.Name, err)
+	}
+
+	return nil
+}
+
+func deleteVolume(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(hetzner.HetznerCloud)
+	volume := r.Obj.(*hcloud.Volume)
+
+	if _, err := c.VolumeClient().Delete(context.TODO(), volume); err != nil {
+		return fmt.Errorf("failed to delete volume %q: %v", volume.Name, err)
+	}
+
+	return nil
+}
+
+func deleteLoadBalancer(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(hetzner.HetznerCloud)
+	loadBalancer := r.Obj.(*hcloud.LoadBalancer)
+
+	if _, err := c.LoadBalancerClient().Delete(context.TODO(), loadBalancer); err != nil {
+		return fmt.Errorf("failed to delete load balancer %q: %v", loadBalancer.Name, err)
+	}
+
+	return nil
+}
+
+func deleteFirewall(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(hetzner.HetznerCloud)
+	firewall := r.Obj.(*hcloud.Firewall)
+
+	// A firewall cannot be deleted while it is still applied to resources
+	if len(firewall.AppliedTo) > 0 {
+		actions, _, err := c.FirewallClient().RemoveResources(context.TODO(), firewall, firewall.AppliedTo)
+		if err != nil {
+			return fmt.Errorf("failed to remove firewall %q from resources: %v", firewall.Name, err)
+		}
+		if err := c.WaitForAction(actions...); err != nil {
+			return fmt.Errorf("error waiting for firewall %q: %v", firewall.Name, err)
+		}
+	}
+
+	if _, err := c.FirewallClient().Delete(context.TODO(), firewall); err != nil {
+		return fmt.Errorf("failed to delete firewall %q: %v", firewall.Name, err)
+	}
+
+	return nil
+}
+
+func deletePlacementGroup(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(hetzner.HetznerCloud)
+	placementGroup := r.Obj.(*hcloud.PlacementGroup)
+
+	if _, err := c.PlacementGroupClient().Delete(context.TODO(), placementGroup); err != nil {
+		return fmt.Errorf("failed to delete placement group %q: %v", placementGroup.Name, err)
+	}
+
+	return nil
+}
+
+func deleteNetwork(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(hetzner.HetznerCloud)
+	network := r.Obj.(*hcloud.Network)
+
+	if _, err := c.NetworkClient().Delete(context.TODO(), network); err != nil {
+		return fmt.Errorf("failed to delete network %q: %v", network.Name, err)
+	}
+
+	return nil
+}
+
+func deleteSSHKey(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(hetzner.HetznerCloud)
+	sshKey := r.Obj.(*hcloud.SSHKey)
+
+	if _, err := c.SSHKeyClient().Delete(context.TODO(), sshKey); err != nil {
+		return fmt.Errorf("failed to delete SSH key %q: %v", sshKey.Name, err)
+	}
+
+	return nil
+}
@@ -19,6 +19,7 @@ package openstack
 import (
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	l3floatingip "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/resources"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
@@ -44,6 +45,10 @@ func (os *clusterDiscoveryOS) listL3FloatingIPs(routerID string) ([]*resources.R
 	}
 	for _, floatingIP := range floatingIPs {
 		if floatingIP.RouterID == routerID {
+			if os.sharedFloatingIP != "" && floatingIP.FloatingIP == os.sharedFloatingIP {
+				klog.V(2).Infof("Skipping shared floating ip %s of the API load balancer", floatingIP.FloatingIP)
+				continue
+			}
 			resourceTracker := &resources.Resource{
 				Name:    floatingIP.FloatingIP,
 				ID:      floatingIP.ID,
@@ -17,6 +17,7 @@ limitations under the License.
 package openstack
 
 import (
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/resources"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
@@ -28,16 +29,21 @@ type clusterDiscoveryOS struct {
 	cloud       fi.Cloud
 	osCloud     openstack.OpenstackCloud
 	clusterName string
+	// sharedFloatingIP is an existing floating IP reused for the API load balancer, which must not be deleted
+	sharedFloatingIP string
 }
 
 // ListResources lists the OpenStack resources kops manages
-func ListResources(cloud openstack.OpenstackCloud, clusterName string) (map[string]*resources.Resource, error) {
+func ListResources(cloud openstack.OpenstackCloud, cluster *kops.Cluster) (map[string]*resources.Resource, error) {
 	resources := make(map[string]*resources.Resource)
 
 	os := &clusterDiscoveryOS{
 		cloud:       cloud,
 		osCloud:     cloud,
-		clusterName: clusterName,
+		clusterName: cluster.Name,
+	}
+	if cluster.Spec.API != nil && cluster.Spec.API.LoadBalancer != nil {
+		os.sharedFloatingIP = cluster.Spec.API.LoadBalancer.FloatingIP
 	}
 
 	listFunctions := []openstackListFn{
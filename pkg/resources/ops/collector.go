@@ -26,12 +26,14 @@ import (
 	"k8s.io/kops/pkg/resources/azure"
 	"k8s.io/kops/pkg/resources/digitalocean"
 	"k8s.io/kops/pkg/resources/gce"
+	"k8s.io/kops/pkg/resources/hetzner"
 	"k8s.io/kops/pkg/resources/openstack"
 	"k8s.io/kops/upup/pkg/fi"
 	cloudali "k8s.io/kops/upup/pkg/fi/cloudup/aliup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	cloudazure "k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	cloudgce "k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	cloudhetzner "k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
 	cloudopenstack "k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 )
 
@@ -46,11 +48,16 @@ func ListResources(cloud fi.Cloud, cluster *kops.Cluster, region string) (map[st
 	case kops.CloudProviderGCE:
 		return gce.ListResourcesGCE(cloud.(cloudgce.GCECloud), clusterName, region)
 	case kops.CloudProviderOpenstack:
-		return openstack.ListResources(cloud.(cloudopenstack.OpenstackCloud), clusterName)
+		return openstack.ListResources(cloud.(cloudopenstack.OpenstackCloud), cluster)
 	case kops.CloudProviderALI:
 		return ali.ListResourcesALI(cloud.(cloudali.ALICloud), clusterName, region)
 	case kops.CloudProviderAzure:
 		return azure.ListResourcesAzure(cloud.(cloudazure.AzureCloud), cluster)
+	case kops.CloudProviderHetzner:
+		return hetzner.ListResources(cloud.(cloudhetzner.HetznerCloud), clusterName)
+	case kops.CloudProviderMetal:
+		// kops does not own pre-provisioned hosts, so there is nothing to delete
+		return make(map[string]*resources.Resource), nil
 	default:
 		return nil, fmt.Errorf("delete on clusters on %q not (yet) supported", cloud.ProviderID())
 	}
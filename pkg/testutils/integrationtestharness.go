@@ -37,11 +37,13 @@ import (
 	"k8s.io/klog/v2"
 	kopsroot "k8s.io/kops"
 	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	"k8s.io/kops/cloudmock/aws/mockcloudwatchlogs"
 	"k8s.io/kops/cloudmock/aws/mockec2"
 	"k8s.io/kops/cloudmock/aws/mockelb"
 	"k8s.io/kops/cloudmock/aws/mockelbv2"
 	"k8s.io/kops/cloudmock/aws/mockiam"
 	"k8s.io/kops/cloudmock/aws/mockroute53"
+	gcemockcompute "k8s.io/kops/cloudmock/gce/mockcompute"
 	"k8s.io/kops/cloudmock/openstack/mockblockstorage"
 	"k8s.io/kops/cloudmock/openstack/mockcompute"
 	"k8s.io/kops/cloudmock/openstack/mockdns"
@@ -53,6 +55,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -67,6 +71,9 @@ type IntegrationTestHarness struct {
 	// originalKopsVersion is the original kops.Version value, restored on Close
 	originalKopsVersion string
 
+	// mockGCECompute is the mock GCE compute API installed by SetupMockGCE, torn down on Close
+	mockGCECompute *gcemockcompute.MockClient
+
 	// originalPKIDefaultPrivateKeySize is the saved pki.DefaultPrivateKeySize value, restored on Close
 	originalPKIDefaultPrivateKeySize int
 }
@@ -121,6 +128,10 @@ func (h *IntegrationTestHarness) Close() {
 		kops.DefaultChannelBase = h.originalDefaultChannelBase
 	}
 
+	if h.mockGCECompute != nil {
+		h.mockGCECompute.TeardownHTTP()
+	}
+
 	if h.originalPKIDefaultPrivateKeySize != 0 {
 		pki.DefaultPrivateKeySize = h.originalPKIDefaultPrivateKeySize
 	}
@@ -140,6 +151,8 @@ func (h *IntegrationTestHarness) SetupMockAWS() *awsup.MockAWSCloud {
 	cloud.MockIAM = mockIAM
 	mockAutoscaling := &mockautoscaling.MockAutoscaling{}
 	cloud.MockAutoscaling = mockAutoscaling
+	mockCloudWatchLogs := &mockcloudwatchlogs.MockCloudWatchLogs{}
+	cloud.MockCloudWatchLogs = mockCloudWatchLogs
 
 	mockRoute53.MockCreateZone(&route53.HostedZone{
 		Id:   aws.String("/hostedzone/Z1AFAKE1ZON3YO"),
@@ -276,7 +289,16 @@ func (h *IntegrationTestHarness) SetupMockAWS() *awsup.MockAWSCloud {
 
 // SetupMockGCE configures a mock GCE cloud provider
 func (h *IntegrationTestHarness) SetupMockGCE() {
-	gce.InstallMockGCECloud("us-test1", "testproject")
+	h.mockGCECompute = gce.InstallMockGCECloud("us-test1", "testproject").MockCompute
+}
+
+// SetupMockHetzner configures a mock Hetzner Cloud provider
+func SetupMockHetzner() *hetzner.MockCloud {
+	return hetzner.InstallMockHetznerCloud("fsn1")
+}
+
+func SetupMockMetal() *metal.MockCloud {
+	return metal.InstallMockMetalCloud()
 }
 
 func SetupMockOpenstack() *openstack.MockCloud {
@@ -19,7 +19,10 @@ package subnet
 import (
 	"encoding/binary"
 	"fmt"
+	"math/big"
 	"net"
+	"strconv"
+	"strings"
 )
 
 // Overlap checks if two subnets overlap
@@ -67,3 +70,56 @@ func SplitInto8(parent *net.IPNet) ([]*net.IPNet, error) {
 
 	return subnets, nil
 }
+
+// IsSubnetIndex returns true if s is a relative subnet of the form /<prefix>#<index>,
+// which selects the index'th block with the prefix length from a parent CIDR.
+// This is used for IPv6 subnets, whose parent CIDR is allocated by the cloud.
+func IsSubnetIndex(s string) bool {
+	return strings.HasPrefix(s, "/")
+}
+
+// ParseSubnetIndex parses a relative subnet of the form /<prefix>#<index>
+func ParseSubnetIndex(s string) (int, uint64, error) {
+	tokens := strings.Split(strings.TrimPrefix(s, "/"), "#")
+	if !IsSubnetIndex(s) || len(tokens) != 2 {
+		return 0, 0, fmt.Errorf("subnet %q is not of the form /<prefix>#<index>", s)
+	}
+	prefixLength, err := strconv.Atoi(tokens[0])
+	if err != nil || prefixLength <= 0 || prefixLength > 128 {
+		return 0, 0, fmt.Errorf("subnet %q has an invalid prefix length", s)
+	}
+	index, err := strconv.ParseUint(tokens[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("subnet %q has an invalid index", s)
+	}
+	return prefixLength, index, nil
+}
+
+// ResolveSubnetIndex returns the subnet of parent selected by the relative subnet s, of the form /<prefix>#<index>
+func ResolveSubnetIndex(parent *net.IPNet, s string) (*net.IPNet, error) {
+	prefixLength, index, err := ParseSubnetIndex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	parentOnes, bits := parent.Mask.Size()
+	if prefixLength < parentOnes || prefixLength > bits {
+		return nil, fmt.Errorf("subnet %q does not fit in %s", s, parent)
+	}
+	if prefixLength-parentOnes < 64 && index >= uint64(1)<<uint(prefixLength-parentOnes) {
+		return nil, fmt.Errorf("subnet %q is out of range for %s", s, parent)
+	}
+
+	ip := parent.IP.Mask(parent.Mask)
+	n := new(big.Int).SetBytes(ip)
+	n.Add(n, new(big.Int).Lsh(new(big.Int).SetUint64(index), uint(bits-prefixLength)))
+
+	subnetIP := make(net.IP, len(ip))
+	b := n.Bytes()
+	copy(subnetIP[len(subnetIP)-len(b):], b)
+
+	return &net.IPNet{
+		IP:   subnetIP,
+		Mask: net.CIDRMask(prefixLength, bits),
+	}, nil
+}
@@ -147,3 +147,63 @@ func Test_SplitInto8(t *testing.T) {
 		}
 	}
 }
+
+func Test_ResolveSubnetIndex(t *testing.T) {
+	tests := []struct {
+		parent   string
+		subnet   string
+		expected string
+	}{
+		{
+			parent:   "2001:db8:1234:1a00::/56",
+			subnet:   "/64#0",
+			expected: "2001:db8:1234:1a00::/64",
+		},
+		{
+			parent:   "2001:db8:1234:1a00::/56",
+			subnet:   "/64#10",
+			expected: "2001:db8:1234:1a0a::/64",
+		},
+		{
+			parent:   "2001:db8:1234:1a00::/56",
+			subnet:   "/64#256",
+			expected: "",
+		},
+		{
+			parent:   "2001:db8:1234:1a00::/56",
+			subnet:   "/48#0",
+			expected: "",
+		},
+		{
+			parent:   "10.0.0.0/16",
+			subnet:   "/24#3",
+			expected: "10.0.3.0/24",
+		},
+		{
+			parent:   "2001:db8:1234:1a00::/56",
+			subnet:   "64#1",
+			expected: "",
+		},
+	}
+	for _, test := range tests {
+		_, parent, err := net.ParseCIDR(test.parent)
+		if err != nil {
+			t.Fatalf("error parsing parent cidr %q: %v", test.parent, err)
+		}
+
+		actual, err := ResolveSubnetIndex(parent, test.subnet)
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("expected error resolving %q in %q, got %s", test.subnet, test.parent, actual)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("error resolving %q in %q: %v", test.subnet, test.parent, err)
+			continue
+		}
+		if actual.String() != test.expected {
+			t.Errorf("unexpected result resolving %q in %q: actual=%s, expected=%s", test.subnet, test.parent, actual, test.expected)
+		}
+	}
+}
@@ -27,20 +27,57 @@ import (
 
 const (
 	templateName = "mainTemplate"
+	// partialPrefix is prepended to the filename of a partial, so it does not clash with snippets
+	partialPrefix = "partial:"
 )
 
 // Templater is golang template renders
 type Templater struct {
 	channel *kops.Channel
+	// partials is a map of partial filename to content
+	partials map[string]string
+	// partialNames is a map of named template to the partial file defining it
+	partialNames map[string]string
 }
 
 // NewTemplater returns a new renderer implementation
 func NewTemplater(channel *kops.Channel) *Templater {
 	return &Templater{
-		channel: channel,
+		channel:      channel,
+		partials:     make(map[string]string),
+		partialNames: make(map[string]string),
 	}
 }
 
+// AddPartial adds a file of named templates ({{ define "name" }}) which are available to
+// every template rendered afterwards; a name can only be defined by one partial
+func (r *Templater) AddPartial(filename string, content string) error {
+	tm := template.New(partialPrefix + filename)
+	if _, err := tm.Funcs(r.templateFuncsMap(tm)).Parse(content); err != nil {
+		return fmt.Errorf("unable to parse partial: %s, error: %s", filename, err)
+	}
+
+	var names []string
+	for _, x := range tm.Templates() {
+		if x.Name() == tm.Name() {
+			continue
+		}
+		if existing, found := r.partialNames[x.Name()]; found {
+			return fmt.Errorf("partial %q in %s is already defined in %s", x.Name(), filename, existing)
+		}
+		if x.Name() == templateName {
+			return fmt.Errorf("partial cannot be named %s", templateName)
+		}
+		names = append(names, x.Name())
+	}
+	for _, name := range names {
+		r.partialNames[name] = filename
+	}
+	r.partials[filename] = content
+
+	return nil
+}
+
 // Render is responsible for actually rendering the template
 func (r *Templater) Render(content string, context map[string]interface{}, snippets map[string]string, failOnMissing bool) (rendered string, err error) {
 	// @step: create the template
@@ -52,6 +89,13 @@ func (r *Templater) Render(content string, context map[string]interface{}, snipp
 		tm.Option("missingkey=error")
 	}
 
+	// @step: add the partials shared by all the templates
+	for filename, partial := range r.partials {
+		if _, err = tm.New(partialPrefix + filename).Parse(partial); err != nil {
+			return rendered, fmt.Errorf("unable to parse partial: %s, error: %s", filename, err)
+		}
+	}
+
 	// @step: add the snippits into the mix
 	for filename, snippet := range snippets {
 		if filename == templateName {
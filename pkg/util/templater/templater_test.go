@@ -187,6 +187,36 @@ func TestAllowForMissingVars(t *testing.T) {
 	makeRenderTests(t, cases)
 }
 
+func TestRenderPartials(t *testing.T) {
+	channel, err := simple.NewMockChannel("../../../tests/integration/channel/simple/channel.yaml")
+	if err != nil {
+		t.Fatalf("could not load channel: %v", err)
+	}
+
+	r := NewTemplater(channel)
+	if err := r.AddPartial("labels.tpl", `{{ define "labels" }}team: {{ .team }}{{ end }}`); err != nil {
+		t.Fatalf("unable to add partial: %s", err)
+	}
+	if err := r.AddPartial("other.tpl", `{{ define "labels" }}other{{ end }}`); err == nil {
+		t.Errorf("expected an error adding a partial that is already defined")
+	}
+
+	context := map[string]interface{}{"team": "example"}
+	for template, expected := range map[string]string{
+		`{{ template "labels" . }}`:        "team: example",
+		`{{ include "labels" . | upper }}`: "TEAM: EXAMPLE",
+	} {
+		render, err := r.Render(template, context, nil, true)
+		if err != nil {
+			t.Errorf("failed to render template %q, error: %s", template, err)
+			continue
+		}
+		if render != expected {
+			t.Errorf("template %q: expected %q, got %q", template, expected, render)
+		}
+	}
+}
+
 func TestRenderIntegration(t *testing.T) {
 	var cases []renderTest
 	content, err := ioutil.ReadFile("integration_tests.yml")
@@ -0,0 +1,41 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templater
+
+import (
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"sigs.k8s.io/yaml"
+)
+
+// ValuesSchemaFile is the name of the JSON-schema file shipped in a template directory
+const ValuesSchemaFile = "values.schema.json"
+
+// ValidateValues checks the values against a JSON-schema, which may be written as JSON or YAML
+func ValidateValues(values map[string]interface{}, schema []byte) error {
+	schemaJSON, err := yaml.YAMLToJSON(schema)
+	if err != nil {
+		return fmt.Errorf("unable to parse the values schema, error: %s", err)
+	}
+
+	if err := chartutil.ValidateAgainstSingleSchema(values, schemaJSON); err != nil {
+		return fmt.Errorf("values do not match the schema: %s", err)
+	}
+
+	return nil
+}
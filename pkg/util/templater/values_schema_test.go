@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templater
+
+import (
+	"testing"
+)
+
+func TestValidateValues(t *testing.T) {
+	schema := `
+type: object
+required: [clusterName]
+properties:
+  clusterName:
+    type: string
+  nodeCount:
+    type: integer
+    minimum: 1
+additionalProperties: false
+`
+	cases := []struct {
+		Values map[string]interface{}
+		NotOK  bool
+	}{
+		{
+			Values: map[string]interface{}{"clusterName": "example.com", "nodeCount": 3},
+		},
+		{
+			Values: map[string]interface{}{"nodeCount": 3},
+			NotOK:  true,
+		},
+		{
+			Values: map[string]interface{}{"clusterName": "example.com", "nodeCount": 0},
+			NotOK:  true,
+		},
+		{
+			Values: map[string]interface{}{"clusterName": "example.com", "nodeCont": 3},
+			NotOK:  true,
+		},
+	}
+	for i, x := range cases {
+		err := ValidateValues(x.Values, []byte(schema))
+		if x.NotOK && err == nil {
+			t.Errorf("case %d: expected values to be invalid", i)
+		}
+		if !x.NotOK && err != nil {
+			t.Errorf("case %d: unexpected error: %s", i, err)
+		}
+	}
+}
@@ -61,7 +61,7 @@ func main() {
 
 // run is responsible for running the protokube service controller
 func run() error {
-	var zones []string
+	var zones, flagGossipSeeds []string
 	var applyTaints, initializeRBAC, containerized, master, tlsAuth bool
 	var cloud, clusterID, dnsServer, dnsProviderID, dnsInternalSuffix, gossipSecret, gossipListen, gossipProtocol, gossipSecretSecondary, gossipListenSecondary, gossipProtocolSecondary string
 	var flagChannels, tlsCert, tlsKey, tlsCA, peerCert, peerKey, peerCA string
@@ -72,7 +72,7 @@ func run() error {
 	flag.BoolVar(&containerized, "containerized", containerized, "Set if we are running containerized.")
 	flag.BoolVar(&initializeRBAC, "initialize-rbac", initializeRBAC, "Set if we should initialize RBAC")
 	flag.BoolVar(&master, "master", master, "Whether or not this node is a master")
-	flag.StringVar(&cloud, "cloud", "aws", "CloudProvider we are using (aws,digitalocean,gce,openstack)")
+	flag.StringVar(&cloud, "cloud", "aws", "CloudProvider we are using (aws,digitalocean,gce,hetzner,metal,openstack)")
 	flag.StringVar(&clusterID, "cluster-id", clusterID, "Cluster ID")
 	flag.StringVar(&dnsInternalSuffix, "dns-internal-suffix", dnsInternalSuffix, "DNS suffix for internal domain names")
 	flag.StringVar(&dnsServer, "dns-server", dnsServer, "DNS Server")
@@ -84,6 +84,7 @@ func run() error {
 	flag.StringVar(&gossipProtocolSecondary, "gossip-protocol-secondary", "memberlist", "mesh/memberlist")
 	flag.StringVar(&gossipListenSecondary, "gossip-listen-secondary", fmt.Sprintf("0.0.0.0:%d", wellknownports.ProtokubeGossipMemberlist), "address:port on which to bind for gossip")
 	flags.StringVar(&gossipSecretSecondary, "gossip-secret-secondary", gossipSecret, "Secret to use to secure gossip")
+	flags.StringSliceVar(&flagGossipSeeds, "gossip-seed", flagGossipSeeds, "Static addresses of gossip peers, for clouds where they cannot be discovered (metal)")
 	flag.StringVar(&peerCA, "peer-ca", peerCA, "Path to a file containing the peer ca in PEM format")
 	flag.StringVar(&peerCert, "peer-cert", peerCert, "Path to a file containing the peer certificate")
 	flag.StringVar(&peerKey, "peer-key", peerKey, "Path to a file containing the private key for the peers")
@@ -219,6 +220,31 @@ func run() error {
 		if internalIP == nil {
 			internalIP = azureVolumes.InternalIP()
 		}
+	} else if cloud == "hetzner" {
+		hetznerVolumes, err := protokube.NewHetznerVolumes()
+		if err != nil {
+			klog.Errorf("Error initializing Hetzner: %q", err)
+			os.Exit(1)
+		}
+		volumes = hetznerVolumes
+
+		if clusterID == "" {
+			clusterID = hetznerVolumes.ClusterID()
+		}
+		if internalIP == nil {
+			internalIP = hetznerVolumes.InternalIP()
+		}
+	} else if cloud == "metal" {
+		metalVolumes, err := protokube.NewMetalVolumes(flagGossipSeeds)
+		if err != nil {
+			klog.Errorf("Error initializing metal: %q", err)
+			os.Exit(1)
+		}
+		volumes = metalVolumes
+
+		if internalIP == nil {
+			internalIP = metalVolumes.InternalIP()
+		}
 	} else {
 		klog.Errorf("Unknown cloud %q", cloud)
 		os.Exit(1)
@@ -299,6 +325,18 @@ func run() error {
 				return err
 			}
 			gossipName = volumes.(*protokube.AzureVolumes).InstanceID()
+		} else if cloud == "hetzner" {
+			gossipSeeds, err = volumes.(*protokube.HetznerVolumes).GossipSeeds()
+			if err != nil {
+				return err
+			}
+			gossipName = volumes.(*protokube.HetznerVolumes).InstanceName()
+		} else if cloud == "metal" {
+			gossipSeeds, err = volumes.(*protokube.MetalVolumes).GossipSeeds()
+			if err != nil {
+				return err
+			}
+			gossipName = volumes.(*protokube.MetalVolumes).InstanceName()
 		} else {
 			klog.Fatalf("seed provider for %q not yet implemented", cloud)
 		}
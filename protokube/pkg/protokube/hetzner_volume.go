@@ -0,0 +1,247 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protokube
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"k8s.io/klog/v2"
+	"k8s.io/kops"
+	"k8s.io/kops/protokube/pkg/etcd"
+	"k8s.io/kops/protokube/pkg/gossip"
+	gossiphetzner "k8s.io/kops/protokube/pkg/gossip/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+)
+
+const hetznerMetadataURL = "http://169.254.169.254/hetzner/v1/metadata/"
+
+// HetznerVolumes implements the Volumes interface for Hetzner Cloud.
+type HetznerVolumes struct {
+	client *hcloud.Client
+
+	clusterName  string
+	serverID     int
+	instanceName string
+	location     string
+	internalIP   net.IP
+}
+
+var _ Volumes = &HetznerVolumes{}
+
+// NewHetznerVolumes returns a new HetznerVolumes, using the instance metadata to identify the local server
+func NewHetznerVolumes() (*HetznerVolumes, error) {
+	token := os.Getenv("HCLOUD_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("HCLOUD_TOKEN is required")
+	}
+	client := hcloud.NewClient(
+		hcloud.WithToken(token),
+		hcloud.WithApplication("kops", kops.Version),
+	)
+
+	instanceID, err := getHetznerMetadata("instance-id")
+	if err != nil {
+		return nil, err
+	}
+	serverID, err := strconv.Atoi(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing instance ID %q: %v", instanceID, err)
+	}
+
+	server, _, err := client.Server.GetByID(context.TODO(), serverID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying server %d: %v", serverID, err)
+	}
+	if server == nil {
+		return nil, fmt.Errorf("server %d not found", serverID)
+	}
+
+	clusterName := server.Labels[hetzner.TagKubernetesClusterName]
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster label %q not found on server %q", hetzner.TagKubernetesClusterName, server.Name)
+	}
+	if len(server.PrivateNet) == 0 || server.PrivateNet[0].IP == nil {
+		return nil, fmt.Errorf("server %q is not attached to a private network", server.Name)
+	}
+
+	h := &HetznerVolumes{
+		client:       client,
+		clusterName:  clusterName,
+		serverID:     server.ID,
+		instanceName: server.Name,
+		internalIP:   server.PrivateNet[0].IP,
+	}
+	if server.Datacenter != nil && server.Datacenter.Location != nil {
+		h.location = server.Datacenter.Location.Name
+	}
+
+	return h, nil
+}
+
+// ClusterID implements Volumes ClusterID.
+func (h *HetznerVolumes) ClusterID() string {
+	return h.clusterName
+}
+
+// InstanceName returns the name of the local server, used as the gossip name.
+func (h *HetznerVolumes) InstanceName() string {
+	return h.instanceName
+}
+
+// InternalIP implements Volumes InternalIP.
+func (h *HetznerVolumes) InternalIP() net.IP {
+	return h.internalIP
+}
+
+func (h *HetznerVolumes) GossipSeeds() (gossip.SeedProvider, error) {
+	return gossiphetzner.NewSeedProvider(h.client, h.clusterName)
+}
+
+func (h *HetznerVolumes) AttachVolume(volume *Volume) error {
+	volumeID, err := strconv.Atoi(volume.ID)
+	if err != nil {
+		return fmt.Errorf("error parsing volume ID %q: %v", volume.ID, err)
+	}
+
+	hetznerVolume := &hcloud.Volume{ID: volumeID}
+	action, _, err := h.client.Volume.Attach(context.TODO(), hetznerVolume, &hcloud.Server{ID: h.serverID})
+	if err != nil {
+		return fmt.Errorf("error attaching volume %q: %v", volume.ID, err)
+	}
+	_, errCh := h.client.Action.WatchProgress(context.TODO(), action)
+	if err := <-errCh; err != nil {
+		return fmt.Errorf("error waiting for volume %q to attach: %v", volume.ID, err)
+	}
+
+	hetznerVolume, _, err = h.client.Volume.GetByID(context.TODO(), volumeID)
+	if err != nil {
+		return fmt.Errorf("error querying volume %q: %v", volume.ID, err)
+	}
+	if hetznerVolume == nil || hetznerVolume.Server == nil || hetznerVolume.Server.ID != h.serverID {
+		return fmt.Errorf("volume %q was not attached to server %q", volume.ID, h.instanceName)
+	}
+
+	volume.AttachedTo = strconv.Itoa(h.serverID)
+	volume.LocalDevice = hetznerVolume.LinuxDevice
+
+	return nil
+}
+
+func (h *HetznerVolumes) FindVolumes() ([]*Volume, error) {
+	opts := hcloud.VolumeListOpts{
+		ListOpts: hcloud.ListOpts{
+			LabelSelector: hetzner.ClusterLabelSelector(h.clusterName),
+		},
+	}
+	hetznerVolumes, err := h.client.Volume.AllWithOpts(context.TODO(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing volumes of cluster %q: %v", h.clusterName, err)
+	}
+
+	// All the members of an etcd cluster share the volume role label
+	nodeNames := make(map[string][]string)
+	for _, hetznerVolume := range hetznerVolumes {
+		role := hetznerVolume.Labels[hetzner.TagKubernetesVolumeRole]
+		member := hetznerVolume.Labels[hetzner.TagKubernetesEtcdMember]
+		if role != "" && member != "" {
+			nodeNames[role] = append(nodeNames[role], member)
+		}
+	}
+
+	var volumes []*Volume
+	for _, hetznerVolume := range hetznerVolumes {
+		// Volumes can only be attached to servers in the same location
+		if hetznerVolume.Location != nil && h.location != "" && hetznerVolume.Location.Name != h.location {
+			continue
+		}
+
+		role := hetznerVolume.Labels[hetzner.TagKubernetesVolumeRole]
+		member := hetznerVolume.Labels[hetzner.TagKubernetesEtcdMember]
+		if role == "" || member == "" {
+			klog.Warningf("ignoring volume %q without labels %q and %q", hetznerVolume.Name, hetzner.TagKubernetesVolumeRole, hetzner.TagKubernetesEtcdMember)
+			continue
+		}
+
+		volume := &Volume{
+			ID: strconv.Itoa(hetznerVolume.ID),
+			Info: VolumeInfo{
+				Description: hetznerVolume.Name,
+			},
+		}
+		if hetznerVolume.Server != nil {
+			volume.AttachedTo = strconv.Itoa(hetznerVolume.Server.ID)
+			if hetznerVolume.Server.ID == h.serverID {
+				volume.LocalDevice = hetznerVolume.LinuxDevice
+			}
+		}
+
+		volume.Info.EtcdClusters = append(volume.Info.EtcdClusters, &etcd.EtcdClusterSpec{
+			ClusterKey: role,
+			NodeName:   member,
+			NodeNames:  nodeNames[role],
+		})
+		volumes = append(volumes, volume)
+	}
+
+	return volumes, nil
+}
+
+func (h *HetznerVolumes) FindMountedVolume(volume *Volume) (string, error) {
+	device := volume.LocalDevice
+	if device == "" {
+		return "", nil
+	}
+
+	_, err := os.Stat(pathFor(device))
+	if err == nil {
+		return device, nil
+	}
+
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("error checking for device %q: %v", device, err)
+	}
+
+	return "", nil
+}
+
+// getHetznerMetadata returns a single value from the instance metadata service
+func getHetznerMetadata(key string) (string, error) {
+	resp, err := http.Get(hetznerMetadataURL + key)
+	if err != nil {
+		return "", fmt.Errorf("error querying metadata %q: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d querying metadata %q", resp.StatusCode, key)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading metadata %q: %v", key, err)
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protokube
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"k8s.io/kops/protokube/pkg/gossip"
+)
+
+// MetalVolumes implements the Volumes interface for pre-provisioned hosts.
+// The hosts have no cloud volumes; etcd-manager keeps the etcd data in directories on their local disks.
+type MetalVolumes struct {
+	instanceName string
+	internalIP   net.IP
+	gossipSeeds  []string
+}
+
+var _ Volumes = &MetalVolumes{}
+
+// NewMetalVolumes returns a new MetalVolumes, using the static addresses of the masters as gossip seeds
+func NewMetalVolumes(gossipSeeds []string) (*MetalVolumes, error) {
+	if len(gossipSeeds) == 0 {
+		return nil, fmt.Errorf("gossip-seed is required for metal")
+	}
+
+	instanceName, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("error getting hostname: %v", err)
+	}
+
+	internalIP, err := localAddressTowards(gossipSeeds[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &MetalVolumes{
+		instanceName: instanceName,
+		internalIP:   internalIP,
+		gossipSeeds:  gossipSeeds,
+	}, nil
+}
+
+// InstanceName returns the hostname of the local host, used as the gossip name.
+func (m *MetalVolumes) InstanceName() string {
+	return m.instanceName
+}
+
+// InternalIP implements Volumes InternalIP.
+func (m *MetalVolumes) InternalIP() net.IP {
+	return m.internalIP
+}
+
+func (m *MetalVolumes) GossipSeeds() (gossip.SeedProvider, error) {
+	return gossip.NewStaticSeedProvider(m.gossipSeeds), nil
+}
+
+func (m *MetalVolumes) AttachVolume(volume *Volume) error {
+	return fmt.Errorf("volumes are not supported on metal")
+}
+
+func (m *MetalVolumes) FindVolumes() ([]*Volume, error) {
+	return nil, nil
+}
+
+func (m *MetalVolumes) FindMountedVolume(volume *Volume) (string, error) {
+	return "", nil
+}
+
+// localAddressTowards returns the local address the host uses to reach the given peer.
+// Dialing UDP does not send any packet, it only selects the route.
+func localAddressTowards(peer string) (net.IP, error) {
+	conn, err := net.Dial("udp", net.JoinHostPort(peer, "1"))
+	if err != nil {
+		return nil, fmt.Errorf("error finding the local address towards %q: %v", peer, err)
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
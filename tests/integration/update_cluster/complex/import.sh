@@ -0,0 +1,68 @@
+#!/bin/sh
+# Imports the existing cloud resources of the cluster into terraform state.
+# If the configuration is used as a module, pass the address of the module, for example module.kubernetes
+set -e
+prefix="${1:+$1.}"
+terraform import "${prefix}"'aws_autoscaling_group.master-us-test-1a-masters-complex-example-com' 'master-us-test-1a.masters.complex.example.com'
+terraform import "${prefix}"'aws_autoscaling_group.nodes-complex-example-com' 'nodes.complex.example.com'
+terraform import "${prefix}"'aws_ebs_volume.a-etcd-events-complex-example-com' 'vol-x'
+terraform import "${prefix}"'aws_ebs_volume.a-etcd-main-complex-example-com' 'vol-x'
+terraform import "${prefix}"'aws_iam_instance_profile.masters-complex-example-com' 'masters.complex.example.com'
+terraform import "${prefix}"'aws_iam_instance_profile.nodes-complex-example-com' 'nodes.complex.example.com'
+terraform import "${prefix}"'aws_iam_role.masters-complex-example-com' 'masters.complex.example.com'
+terraform import "${prefix}"'aws_iam_role.nodes-complex-example-com' 'nodes.complex.example.com'
+terraform import "${prefix}"'aws_iam_role_policy.masters-complex-example-com' 'masters.complex.example.com:masters.complex.example.com'
+terraform import "${prefix}"'aws_iam_role_policy.nodes-complex-example-com' 'nodes.complex.example.com:nodes.complex.example.com'
+terraform import "${prefix}"'aws_internet_gateway.complex-example-com' 'igw-x'
+terraform import "${prefix}"'aws_launch_template.master-us-test-1a-masters-complex-example-com' 'lt-x'
+terraform import "${prefix}"'aws_launch_template.nodes-complex-example-com' 'lt-x'
+terraform import "${prefix}"'aws_lb.api-complex-example-com' 'arn:aws:elasticloadbalancing:us-test-1:000000000000:loadbalancer/net/api-complex-example-com-vd3t5n/x'
+terraform import "${prefix}"'aws_lb_listener.api-complex-example-com-443' 'arn:aws:elasticloadbalancing:us-test-1:000000000000:listener/net/api-complex-example-com-vd3t5n/x/x'
+terraform import "${prefix}"'aws_lb_listener.api-complex-example-com-8443' 'arn:aws:elasticloadbalancing:us-test-1:000000000000:listener/net/api-complex-example-com-vd3t5n/x/x'
+terraform import "${prefix}"'aws_lb_target_group.tcp-complex-example-com-vpjolq' 'arn:aws:elasticloadbalancing:us-test-1:000000000000:targetgroup/tcp-complex-example-com-vpjolq/x'
+terraform import "${prefix}"'aws_lb_target_group.tls-complex-example-com-5nursn' 'arn:aws:elasticloadbalancing:us-test-1:000000000000:targetgroup/tls-complex-example-com-5nursn/x'
+terraform import "${prefix}"'aws_route.route-0-0-0-0--0' 'rtb-x_0.0.0.0/0'
+terraform import "${prefix}"'aws_route.route-private-us-test-1a-0-0-0-0--0' 'rtb-x_0.0.0.0/0'
+terraform import "${prefix}"'aws_route53_record.api-complex-example-com' 'Z1AFAKE1ZON3YO_api.complex.example.com_A'
+terraform import "${prefix}"'aws_route_table.complex-example-com' 'rtb-x'
+terraform import "${prefix}"'aws_route_table.private-us-test-1a-complex-example-com' 'rtb-x'
+terraform import "${prefix}"'aws_route_table_association.private-us-east-1a-private-complex-example-com' 'subnet-x/rtb-x'
+terraform import "${prefix}"'aws_route_table_association.us-east-1a-utility-complex-example-com' 'subnet-x/rtb-x'
+terraform import "${prefix}"'aws_route_table_association.us-test-1a-complex-example-com' 'subnet-x/rtb-x'
+terraform import "${prefix}"'aws_security_group.api-elb-complex-example-com' 'sg-x'
+terraform import "${prefix}"'aws_security_group.masters-complex-example-com' 'sg-x'
+terraform import "${prefix}"'aws_security_group.nodes-complex-example-com' 'sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-1-1-1-0--24-ingress-tcp-443to443-masters-complex-example-com' 'sg-x_ingress_tcp_443_443_1.1.1.0/24'
+terraform import "${prefix}"'aws_security_group_rule.from-1-1-1-1--32-ingress-tcp-22to22-masters-complex-example-com' 'sg-x_ingress_tcp_22_22_1.1.1.1/32'
+terraform import "${prefix}"'aws_security_group_rule.from-1-1-1-1--32-ingress-tcp-22to22-nodes-complex-example-com' 'sg-x_ingress_tcp_22_22_1.1.1.1/32'
+terraform import "${prefix}"'aws_security_group_rule.from-2001_0_8500__--40-ingress-tcp-443to443-masters-complex-example-com' 'sg-x_ingress_tcp_443_443_2001:0:8500::/40'
+terraform import "${prefix}"'aws_security_group_rule.from-2001_0_85a3__--48-ingress-tcp-22to22-masters-complex-example-com' 'sg-x_ingress_tcp_22_22_2001:0:85a3::/48'
+terraform import "${prefix}"'aws_security_group_rule.from-2001_0_85a3__--48-ingress-tcp-22to22-nodes-complex-example-com' 'sg-x_ingress_tcp_22_22_2001:0:85a3::/48'
+terraform import "${prefix}"'aws_security_group_rule.from-masters-complex-example-com-egress-all-0to0-0-0-0-0--0' 'sg-x_egress_all_0_65536_0.0.0.0/0'
+terraform import "${prefix}"'aws_security_group_rule.from-masters-complex-example-com-ingress-all-0to0-masters-complex-example-com' 'sg-x_ingress_all_0_65536_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-masters-complex-example-com-ingress-all-0to0-nodes-complex-example-com' 'sg-x_ingress_all_0_65536_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-complex-example-com-egress-all-0to0-0-0-0-0--0' 'sg-x_egress_all_0_65536_0.0.0.0/0'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-complex-example-com-ingress-all-0to0-nodes-complex-example-com' 'sg-x_ingress_all_0_65536_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-complex-example-com-ingress-tcp-1to2379-masters-complex-example-com' 'sg-x_ingress_tcp_1_2379_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-complex-example-com-ingress-tcp-2382to4000-masters-complex-example-com' 'sg-x_ingress_tcp_2382_4000_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-complex-example-com-ingress-tcp-4003to65535-masters-complex-example-com' 'sg-x_ingress_tcp_4003_65535_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-complex-example-com-ingress-udp-1to65535-masters-complex-example-com' 'sg-x_ingress_udp_1_65535_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.https-elb-to-master' 'sg-x_ingress_tcp_443_443_172.20.0.0/16'
+terraform import "${prefix}"'aws_security_group_rule.https-lb-to-master-10-1-0-0--16' 'sg-x_ingress_tcp_443_443_10.1.0.0/16'
+terraform import "${prefix}"'aws_security_group_rule.https-lb-to-master-10-2-0-0--16' 'sg-x_ingress_tcp_443_443_10.2.0.0/16'
+terraform import "${prefix}"'aws_security_group_rule.icmp-pmtu-api-elb-1-1-1-0--24' 'sg-x_ingress_icmp_3_4_1.1.1.0/24'
+terraform import "${prefix}"'aws_security_group_rule.icmp-pmtu-api-elb-2001_0_8500__--40' 'sg-x_ingress_icmp_3_4_2001:0:8500::/40'
+terraform import "${prefix}"'aws_security_group_rule.nodeport-tcp-external-to-node-1-2-3-4--32' 'sg-x_ingress_tcp_28000_32767_1.2.3.4/32'
+terraform import "${prefix}"'aws_security_group_rule.nodeport-tcp-external-to-node-10-20-30-0--24' 'sg-x_ingress_tcp_28000_32767_10.20.30.0/24'
+terraform import "${prefix}"'aws_security_group_rule.nodeport-udp-external-to-node-1-2-3-4--32' 'sg-x_ingress_udp_28000_32767_1.2.3.4/32'
+terraform import "${prefix}"'aws_security_group_rule.nodeport-udp-external-to-node-10-20-30-0--24' 'sg-x_ingress_udp_28000_32767_10.20.30.0/24'
+terraform import "${prefix}"'aws_security_group_rule.tcp-api-1-1-1-0--24' 'sg-x_ingress_tcp_8443_8443_1.1.1.0/24'
+terraform import "${prefix}"'aws_security_group_rule.tcp-api-2001_0_8500__--40' 'sg-x_ingress_tcp_8443_8443_2001:0:8500::/40'
+terraform import "${prefix}"'aws_subnet.us-east-1a-private-complex-example-com' 'subnet-x'
+terraform import "${prefix}"'aws_subnet.us-east-1a-utility-complex-example-com' 'subnet-x'
+terraform import "${prefix}"'aws_subnet.us-test-1a-complex-example-com' 'subnet-x'
+terraform import "${prefix}"'aws_vpc.complex-example-com' 'vpc-x'
+terraform import "${prefix}"'aws_vpc_dhcp_options.complex-example-com' 'dopt-x'
+terraform import "${prefix}"'aws_vpc_dhcp_options_association.complex-example-com' 'vpc-x'
+terraform import "${prefix}"'aws_vpc_ipv4_cidr_block_association.cidr-10-1-0-0--16' 'vpc-x'
+terraform import "${prefix}"'aws_vpc_ipv4_cidr_block_association.cidr-10-2-0-0--16' 'vpc-x'
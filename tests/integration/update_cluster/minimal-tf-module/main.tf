@@ -0,0 +1,542 @@
+resource "aws_autoscaling_group" "master-us-test-1a-masters-minimal-tf-module-example-com" {
+  enabled_metrics = ["GroupDesiredCapacity", "GroupInServiceInstances", "GroupMaxSize", "GroupMinSize", "GroupPendingInstances", "GroupStandbyInstances", "GroupTerminatingInstances", "GroupTotalInstances"]
+  launch_template {
+    id      = aws_launch_template.master-us-test-1a-masters-minimal-tf-module-example-com.id
+    version = aws_launch_template.master-us-test-1a-masters-minimal-tf-module-example-com.latest_version
+  }
+  max_size            = var.master-us-test-1a_max_size
+  metrics_granularity = "1Minute"
+  min_size            = var.master-us-test-1a_min_size
+  name                = "master-us-test-1a.masters.minimal-tf-module.example.com"
+  tag {
+    key                 = "KubernetesCluster"
+    propagate_at_launch = true
+    value               = "minimal-tf-module.example.com"
+  }
+  tag {
+    key                 = "Name"
+    propagate_at_launch = true
+    value               = "master-us-test-1a.masters.minimal-tf-module.example.com"
+  }
+  tag {
+    key                 = "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"
+    propagate_at_launch = true
+    value               = ""
+  }
+  tag {
+    key                 = "k8s.io/cluster-autoscaler/node-template/label/kubernetes.io/role"
+    propagate_at_launch = true
+    value               = "master"
+  }
+  tag {
+    key                 = "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"
+    propagate_at_launch = true
+    value               = ""
+  }
+  tag {
+    key                 = "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/master"
+    propagate_at_launch = true
+    value               = ""
+  }
+  tag {
+    key                 = "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers"
+    propagate_at_launch = true
+    value               = ""
+  }
+  tag {
+    key                 = "k8s.io/role/master"
+    propagate_at_launch = true
+    value               = "1"
+  }
+  tag {
+    key                 = "kops.k8s.io/instancegroup"
+    propagate_at_launch = true
+    value               = "master-us-test-1a"
+  }
+  tag {
+    key                 = "kubernetes.io/cluster/minimal-tf-module.example.com"
+    propagate_at_launch = true
+    value               = "owned"
+  }
+  vpc_zone_identifier = [aws_subnet.us-test-1a-minimal-tf-module-example-com.id]
+}
+
+resource "aws_autoscaling_group" "nodes-minimal-tf-module-example-com" {
+  enabled_metrics = ["GroupDesiredCapacity", "GroupInServiceInstances", "GroupMaxSize", "GroupMinSize", "GroupPendingInstances", "GroupStandbyInstances", "GroupTerminatingInstances", "GroupTotalInstances"]
+  launch_template {
+    id      = aws_launch_template.nodes-minimal-tf-module-example-com.id
+    version = aws_launch_template.nodes-minimal-tf-module-example-com.latest_version
+  }
+  max_size            = var.nodes_max_size
+  metrics_granularity = "1Minute"
+  min_size            = var.nodes_min_size
+  name                = "nodes.minimal-tf-module.example.com"
+  tag {
+    key                 = "KubernetesCluster"
+    propagate_at_launch = true
+    value               = "minimal-tf-module.example.com"
+  }
+  tag {
+    key                 = "Name"
+    propagate_at_launch = true
+    value               = "nodes.minimal-tf-module.example.com"
+  }
+  tag {
+    key                 = "k8s.io/cluster-autoscaler/node-template/label/kubernetes.io/role"
+    propagate_at_launch = true
+    value               = "node"
+  }
+  tag {
+    key                 = "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node"
+    propagate_at_launch = true
+    value               = ""
+  }
+  tag {
+    key                 = "k8s.io/role/node"
+    propagate_at_launch = true
+    value               = "1"
+  }
+  tag {
+    key                 = "kops.k8s.io/instancegroup"
+    propagate_at_launch = true
+    value               = "nodes"
+  }
+  tag {
+    key                 = "kubernetes.io/cluster/minimal-tf-module.example.com"
+    propagate_at_launch = true
+    value               = "owned"
+  }
+  vpc_zone_identifier = [aws_subnet.us-test-1a-minimal-tf-module-example-com.id]
+}
+
+resource "aws_ebs_volume" "us-test-1a-etcd-events-minimal-tf-module-example-com" {
+  availability_zone = "us-test-1a"
+  encrypted         = false
+  iops              = 3000
+  size              = 20
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "us-test-1a.etcd-events.minimal-tf-module.example.com"
+    "k8s.io/etcd/events"                                  = "us-test-1a/us-test-1a"
+    "k8s.io/role/master"                                  = "1"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+  }
+  throughput = 125
+  type       = "gp3"
+}
+
+resource "aws_ebs_volume" "us-test-1a-etcd-main-minimal-tf-module-example-com" {
+  availability_zone = "us-test-1a"
+  encrypted         = false
+  iops              = 3000
+  size              = 20
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "us-test-1a.etcd-main.minimal-tf-module.example.com"
+    "k8s.io/etcd/main"                                    = "us-test-1a/us-test-1a"
+    "k8s.io/role/master"                                  = "1"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+  }
+  throughput = 125
+  type       = "gp3"
+}
+
+resource "aws_iam_instance_profile" "masters-minimal-tf-module-example-com" {
+  name = "masters.minimal-tf-module.example.com"
+  role = aws_iam_role.masters-minimal-tf-module-example-com.name
+}
+
+resource "aws_iam_instance_profile" "nodes-minimal-tf-module-example-com" {
+  name = "nodes.minimal-tf-module.example.com"
+  role = aws_iam_role.nodes-minimal-tf-module-example-com.name
+}
+
+resource "aws_iam_role_policy" "masters-minimal-tf-module-example-com" {
+  name   = "masters.minimal-tf-module.example.com"
+  policy = file("${path.module}/data/aws_iam_role_policy_masters.minimal-tf-module.example.com_policy")
+  role   = aws_iam_role.masters-minimal-tf-module-example-com.name
+}
+
+resource "aws_iam_role_policy" "nodes-minimal-tf-module-example-com" {
+  name   = "nodes.minimal-tf-module.example.com"
+  policy = file("${path.module}/data/aws_iam_role_policy_nodes.minimal-tf-module.example.com_policy")
+  role   = aws_iam_role.nodes-minimal-tf-module-example-com.name
+}
+
+resource "aws_iam_role" "masters-minimal-tf-module-example-com" {
+  assume_role_policy = file("${path.module}/data/aws_iam_role_masters.minimal-tf-module.example.com_policy")
+  name               = "masters.minimal-tf-module.example.com"
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "masters.minimal-tf-module.example.com"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+  }
+}
+
+resource "aws_iam_role" "nodes-minimal-tf-module-example-com" {
+  assume_role_policy = file("${path.module}/data/aws_iam_role_nodes.minimal-tf-module.example.com_policy")
+  name               = "nodes.minimal-tf-module.example.com"
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "nodes.minimal-tf-module.example.com"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+  }
+}
+
+resource "aws_internet_gateway" "minimal-tf-module-example-com" {
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "minimal-tf-module.example.com"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+  }
+  vpc_id = aws_vpc.minimal-tf-module-example-com.id
+}
+
+resource "aws_key_pair" "kubernetes-minimal-tf-module-example-com-c4a6ed9aa889b9e2c39cd663eb9c7157" {
+  key_name   = "kubernetes.minimal-tf-module.example.com-c4:a6:ed:9a:a8:89:b9:e2:c3:9c:d6:63:eb:9c:71:57"
+  public_key = file("${path.module}/data/aws_key_pair_kubernetes.minimal-tf-module.example.com-c4a6ed9aa889b9e2c39cd663eb9c7157_public_key")
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "minimal-tf-module.example.com"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+  }
+}
+
+resource "aws_launch_template" "master-us-test-1a-masters-minimal-tf-module-example-com" {
+  block_device_mappings {
+    device_name = "/dev/xvda"
+    ebs {
+      delete_on_termination = true
+      encrypted             = true
+      iops                  = 3000
+      throughput            = 125
+      volume_size           = 64
+      volume_type           = "gp3"
+    }
+  }
+  block_device_mappings {
+    device_name  = "/dev/sdc"
+    virtual_name = "ephemeral0"
+  }
+  iam_instance_profile {
+    name = aws_iam_instance_profile.masters-minimal-tf-module-example-com.id
+  }
+  image_id      = var.master-us-test-1a_image_id
+  instance_type = var.master-us-test-1a_instance_type
+  key_name      = aws_key_pair.kubernetes-minimal-tf-module-example-com-c4a6ed9aa889b9e2c39cd663eb9c7157.id
+  lifecycle {
+    create_before_destroy = true
+  }
+  metadata_options {
+    http_endpoint               = "enabled"
+    http_put_response_hop_limit = 1
+    http_tokens                 = "optional"
+  }
+  name = "master-us-test-1a.masters.minimal-tf-module.example.com"
+  network_interfaces {
+    associate_public_ip_address = true
+    delete_on_termination       = true
+    security_groups             = [aws_security_group.masters-minimal-tf-module-example-com.id]
+  }
+  tag_specifications {
+    resource_type = "instance"
+    tags = {
+      "KubernetesCluster"                                                                                     = "minimal-tf-module.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.minimal-tf-module.example.com"
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kubernetes.io/role"                                      = "master"
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/master"                          = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/minimal-tf-module.example.com"                                                   = "owned"
+    }
+  }
+  tag_specifications {
+    resource_type = "volume"
+    tags = {
+      "KubernetesCluster"                                                                                     = "minimal-tf-module.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.minimal-tf-module.example.com"
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kubernetes.io/role"                                      = "master"
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/master"                          = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/minimal-tf-module.example.com"                                                   = "owned"
+    }
+  }
+  tags = {
+    "KubernetesCluster"                                                                                     = "minimal-tf-module.example.com"
+    "Name"                                                                                                  = "master-us-test-1a.masters.minimal-tf-module.example.com"
+    "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+    "k8s.io/cluster-autoscaler/node-template/label/kubernetes.io/role"                                      = "master"
+    "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+    "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/master"                          = ""
+    "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+    "k8s.io/role/master"                                                                                    = "1"
+    "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+    "kubernetes.io/cluster/minimal-tf-module.example.com"                                                   = "owned"
+  }
+  user_data = filebase64("${path.module}/data/aws_launch_template_master-us-test-1a.masters.minimal-tf-module.example.com_user_data")
+}
+
+resource "aws_launch_template" "nodes-minimal-tf-module-example-com" {
+  block_device_mappings {
+    device_name = "/dev/xvda"
+    ebs {
+      delete_on_termination = true
+      encrypted             = true
+      iops                  = 3000
+      throughput            = 125
+      volume_size           = 128
+      volume_type           = "gp3"
+    }
+  }
+  iam_instance_profile {
+    name = aws_iam_instance_profile.nodes-minimal-tf-module-example-com.id
+  }
+  image_id      = var.nodes_image_id
+  instance_type = var.nodes_instance_type
+  key_name      = aws_key_pair.kubernetes-minimal-tf-module-example-com-c4a6ed9aa889b9e2c39cd663eb9c7157.id
+  lifecycle {
+    create_before_destroy = true
+  }
+  metadata_options {
+    http_endpoint               = "enabled"
+    http_put_response_hop_limit = 1
+    http_tokens                 = "optional"
+  }
+  name = "nodes.minimal-tf-module.example.com"
+  network_interfaces {
+    associate_public_ip_address = true
+    delete_on_termination       = true
+    security_groups             = [aws_security_group.nodes-minimal-tf-module-example-com.id]
+  }
+  tag_specifications {
+    resource_type = "instance"
+    tags = {
+      "KubernetesCluster"                                                          = "minimal-tf-module.example.com"
+      "Name"                                                                       = "nodes.minimal-tf-module.example.com"
+      "k8s.io/cluster-autoscaler/node-template/label/kubernetes.io/role"           = "node"
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/minimal-tf-module.example.com"                        = "owned"
+    }
+  }
+  tag_specifications {
+    resource_type = "volume"
+    tags = {
+      "KubernetesCluster"                                                          = "minimal-tf-module.example.com"
+      "Name"                                                                       = "nodes.minimal-tf-module.example.com"
+      "k8s.io/cluster-autoscaler/node-template/label/kubernetes.io/role"           = "node"
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/minimal-tf-module.example.com"                        = "owned"
+    }
+  }
+  tags = {
+    "KubernetesCluster"                                                          = "minimal-tf-module.example.com"
+    "Name"                                                                       = "nodes.minimal-tf-module.example.com"
+    "k8s.io/cluster-autoscaler/node-template/label/kubernetes.io/role"           = "node"
+    "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+    "k8s.io/role/node"                                                           = "1"
+    "kops.k8s.io/instancegroup"                                                  = "nodes"
+    "kubernetes.io/cluster/minimal-tf-module.example.com"                        = "owned"
+  }
+  user_data = filebase64("${path.module}/data/aws_launch_template_nodes.minimal-tf-module.example.com_user_data")
+}
+
+resource "aws_route_table_association" "us-test-1a-minimal-tf-module-example-com" {
+  route_table_id = aws_route_table.minimal-tf-module-example-com.id
+  subnet_id      = aws_subnet.us-test-1a-minimal-tf-module-example-com.id
+}
+
+resource "aws_route_table" "minimal-tf-module-example-com" {
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "minimal-tf-module.example.com"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+    "kubernetes.io/kops/role"                             = "public"
+  }
+  vpc_id = aws_vpc.minimal-tf-module-example-com.id
+}
+
+resource "aws_route" "route-0-0-0-0--0" {
+  destination_cidr_block = "0.0.0.0/0"
+  gateway_id             = aws_internet_gateway.minimal-tf-module-example-com.id
+  route_table_id         = aws_route_table.minimal-tf-module-example-com.id
+}
+
+resource "aws_security_group_rule" "from-0-0-0-0--0-ingress-tcp-22to22-masters-minimal-tf-module-example-com" {
+  cidr_blocks       = ["0.0.0.0/0"]
+  from_port         = 22
+  protocol          = "tcp"
+  security_group_id = aws_security_group.masters-minimal-tf-module-example-com.id
+  to_port           = 22
+  type              = "ingress"
+}
+
+resource "aws_security_group_rule" "from-0-0-0-0--0-ingress-tcp-22to22-nodes-minimal-tf-module-example-com" {
+  cidr_blocks       = ["0.0.0.0/0"]
+  from_port         = 22
+  protocol          = "tcp"
+  security_group_id = aws_security_group.nodes-minimal-tf-module-example-com.id
+  to_port           = 22
+  type              = "ingress"
+}
+
+resource "aws_security_group_rule" "from-0-0-0-0--0-ingress-tcp-443to443-masters-minimal-tf-module-example-com" {
+  cidr_blocks       = ["0.0.0.0/0"]
+  from_port         = 443
+  protocol          = "tcp"
+  security_group_id = aws_security_group.masters-minimal-tf-module-example-com.id
+  to_port           = 443
+  type              = "ingress"
+}
+
+resource "aws_security_group_rule" "from-masters-minimal-tf-module-example-com-egress-all-0to0-0-0-0-0--0" {
+  cidr_blocks       = ["0.0.0.0/0"]
+  from_port         = 0
+  protocol          = "-1"
+  security_group_id = aws_security_group.masters-minimal-tf-module-example-com.id
+  to_port           = 0
+  type              = "egress"
+}
+
+resource "aws_security_group_rule" "from-masters-minimal-tf-module-example-com-ingress-all-0to0-masters-minimal-tf-module-example-com" {
+  from_port                = 0
+  protocol                 = "-1"
+  security_group_id        = aws_security_group.masters-minimal-tf-module-example-com.id
+  source_security_group_id = aws_security_group.masters-minimal-tf-module-example-com.id
+  to_port                  = 0
+  type                     = "ingress"
+}
+
+resource "aws_security_group_rule" "from-masters-minimal-tf-module-example-com-ingress-all-0to0-nodes-minimal-tf-module-example-com" {
+  from_port                = 0
+  protocol                 = "-1"
+  security_group_id        = aws_security_group.nodes-minimal-tf-module-example-com.id
+  source_security_group_id = aws_security_group.masters-minimal-tf-module-example-com.id
+  to_port                  = 0
+  type                     = "ingress"
+}
+
+resource "aws_security_group_rule" "from-nodes-minimal-tf-module-example-com-egress-all-0to0-0-0-0-0--0" {
+  cidr_blocks       = ["0.0.0.0/0"]
+  from_port         = 0
+  protocol          = "-1"
+  security_group_id = aws_security_group.nodes-minimal-tf-module-example-com.id
+  to_port           = 0
+  type              = "egress"
+}
+
+resource "aws_security_group_rule" "from-nodes-minimal-tf-module-example-com-ingress-all-0to0-nodes-minimal-tf-module-example-com" {
+  from_port                = 0
+  protocol                 = "-1"
+  security_group_id        = aws_security_group.nodes-minimal-tf-module-example-com.id
+  source_security_group_id = aws_security_group.nodes-minimal-tf-module-example-com.id
+  to_port                  = 0
+  type                     = "ingress"
+}
+
+resource "aws_security_group_rule" "from-nodes-minimal-tf-module-example-com-ingress-tcp-1to2379-masters-minimal-tf-module-example-com" {
+  from_port                = 1
+  protocol                 = "tcp"
+  security_group_id        = aws_security_group.masters-minimal-tf-module-example-com.id
+  source_security_group_id = aws_security_group.nodes-minimal-tf-module-example-com.id
+  to_port                  = 2379
+  type                     = "ingress"
+}
+
+resource "aws_security_group_rule" "from-nodes-minimal-tf-module-example-com-ingress-tcp-2382to4000-masters-minimal-tf-module-example-com" {
+  from_port                = 2382
+  protocol                 = "tcp"
+  security_group_id        = aws_security_group.masters-minimal-tf-module-example-com.id
+  source_security_group_id = aws_security_group.nodes-minimal-tf-module-example-com.id
+  to_port                  = 4000
+  type                     = "ingress"
+}
+
+resource "aws_security_group_rule" "from-nodes-minimal-tf-module-example-com-ingress-tcp-4003to65535-masters-minimal-tf-module-example-com" {
+  from_port                = 4003
+  protocol                 = "tcp"
+  security_group_id        = aws_security_group.masters-minimal-tf-module-example-com.id
+  source_security_group_id = aws_security_group.nodes-minimal-tf-module-example-com.id
+  to_port                  = 65535
+  type                     = "ingress"
+}
+
+resource "aws_security_group_rule" "from-nodes-minimal-tf-module-example-com-ingress-udp-1to65535-masters-minimal-tf-module-example-com" {
+  from_port                = 1
+  protocol                 = "udp"
+  security_group_id        = aws_security_group.masters-minimal-tf-module-example-com.id
+  source_security_group_id = aws_security_group.nodes-minimal-tf-module-example-com.id
+  to_port                  = 65535
+  type                     = "ingress"
+}
+
+resource "aws_security_group" "masters-minimal-tf-module-example-com" {
+  description = "Security group for masters"
+  name        = "masters.minimal-tf-module.example.com"
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "masters.minimal-tf-module.example.com"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+  }
+  vpc_id = aws_vpc.minimal-tf-module-example-com.id
+}
+
+resource "aws_security_group" "nodes-minimal-tf-module-example-com" {
+  description = "Security group for nodes"
+  name        = "nodes.minimal-tf-module.example.com"
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "nodes.minimal-tf-module.example.com"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+  }
+  vpc_id = aws_vpc.minimal-tf-module-example-com.id
+}
+
+resource "aws_subnet" "us-test-1a-minimal-tf-module-example-com" {
+  availability_zone = "us-test-1a"
+  cidr_block        = "172.20.32.0/19"
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "us-test-1a.minimal-tf-module.example.com"
+    "SubnetType"                                          = "Public"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+    "kubernetes.io/role/elb"                              = "1"
+  }
+  vpc_id = aws_vpc.minimal-tf-module-example-com.id
+}
+
+resource "aws_vpc_dhcp_options_association" "minimal-tf-module-example-com" {
+  dhcp_options_id = aws_vpc_dhcp_options.minimal-tf-module-example-com.id
+  vpc_id          = aws_vpc.minimal-tf-module-example-com.id
+}
+
+resource "aws_vpc_dhcp_options" "minimal-tf-module-example-com" {
+  domain_name         = "us-test-1.compute.internal"
+  domain_name_servers = ["AmazonProvidedDNS"]
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "minimal-tf-module.example.com"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+  }
+}
+
+resource "aws_vpc" "minimal-tf-module-example-com" {
+  cidr_block           = "172.20.0.0/16"
+  enable_dns_hostnames = true
+  enable_dns_support   = true
+  tags = {
+    "KubernetesCluster"                                   = "minimal-tf-module.example.com"
+    "Name"                                                = "minimal-tf-module.example.com"
+    "kubernetes.io/cluster/minimal-tf-module.example.com" = "owned"
+  }
+}
@@ -0,0 +1,77 @@
+locals {
+  cluster_name                 = "minimal-tf-module.example.com"
+  master_autoscaling_group_ids = [aws_autoscaling_group.master-us-test-1a-masters-minimal-tf-module-example-com.id]
+  master_security_group_ids    = [aws_security_group.masters-minimal-tf-module-example-com.id]
+  masters_role_arn             = aws_iam_role.masters-minimal-tf-module-example-com.arn
+  masters_role_name            = aws_iam_role.masters-minimal-tf-module-example-com.name
+  node_autoscaling_group_ids   = [aws_autoscaling_group.nodes-minimal-tf-module-example-com.id]
+  node_security_group_ids      = [aws_security_group.nodes-minimal-tf-module-example-com.id]
+  node_subnet_ids              = [aws_subnet.us-test-1a-minimal-tf-module-example-com.id]
+  nodes_role_arn               = aws_iam_role.nodes-minimal-tf-module-example-com.arn
+  nodes_role_name              = aws_iam_role.nodes-minimal-tf-module-example-com.name
+  region                       = "us-test-1"
+  route_table_public_id        = aws_route_table.minimal-tf-module-example-com.id
+  subnet_us-test-1a_id         = aws_subnet.us-test-1a-minimal-tf-module-example-com.id
+  vpc_cidr_block               = aws_vpc.minimal-tf-module-example-com.cidr_block
+  vpc_id                       = aws_vpc.minimal-tf-module-example-com.id
+}
+
+output "cluster_name" {
+  value = "minimal-tf-module.example.com"
+}
+
+output "master_autoscaling_group_ids" {
+  value = [aws_autoscaling_group.master-us-test-1a-masters-minimal-tf-module-example-com.id]
+}
+
+output "master_security_group_ids" {
+  value = [aws_security_group.masters-minimal-tf-module-example-com.id]
+}
+
+output "masters_role_arn" {
+  value = aws_iam_role.masters-minimal-tf-module-example-com.arn
+}
+
+output "masters_role_name" {
+  value = aws_iam_role.masters-minimal-tf-module-example-com.name
+}
+
+output "node_autoscaling_group_ids" {
+  value = [aws_autoscaling_group.nodes-minimal-tf-module-example-com.id]
+}
+
+output "node_security_group_ids" {
+  value = [aws_security_group.nodes-minimal-tf-module-example-com.id]
+}
+
+output "node_subnet_ids" {
+  value = [aws_subnet.us-test-1a-minimal-tf-module-example-com.id]
+}
+
+output "nodes_role_arn" {
+  value = aws_iam_role.nodes-minimal-tf-module-example-com.arn
+}
+
+output "nodes_role_name" {
+  value = aws_iam_role.nodes-minimal-tf-module-example-com.name
+}
+
+output "region" {
+  value = "us-test-1"
+}
+
+output "route_table_public_id" {
+  value = aws_route_table.minimal-tf-module-example-com.id
+}
+
+output "subnet_us-test-1a_id" {
+  value = aws_subnet.us-test-1a-minimal-tf-module-example-com.id
+}
+
+output "vpc_cidr_block" {
+  value = aws_vpc.minimal-tf-module-example-com.cidr_block
+}
+
+output "vpc_id" {
+  value = aws_vpc.minimal-tf-module-example-com.id
+}
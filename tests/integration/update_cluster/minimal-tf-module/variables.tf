@@ -0,0 +1,47 @@
+variable "master-us-test-1a_image_id" {
+  description = "Image of the instances in instance group master-us-test-1a"
+  type        = string
+  default     = "ami-12345678"
+}
+
+variable "master-us-test-1a_instance_type" {
+  description = "Instance type of the instances in instance group master-us-test-1a"
+  type        = string
+  default     = "m3.medium"
+}
+
+variable "master-us-test-1a_max_size" {
+  description = "Maximum number of instances in instance group master-us-test-1a"
+  type        = number
+  default     = 1
+}
+
+variable "master-us-test-1a_min_size" {
+  description = "Minimum number of instances in instance group master-us-test-1a"
+  type        = number
+  default     = 1
+}
+
+variable "nodes_image_id" {
+  description = "Image of the instances in instance group nodes"
+  type        = string
+  default     = "ami-12345678"
+}
+
+variable "nodes_instance_type" {
+  description = "Instance type of the instances in instance group nodes"
+  type        = string
+  default     = "t2.medium"
+}
+
+variable "nodes_max_size" {
+  description = "Maximum number of instances in instance group nodes"
+  type        = number
+  default     = 2
+}
+
+variable "nodes_min_size" {
+  description = "Minimum number of instances in instance group nodes"
+  type        = number
+  default     = 2
+}
@@ -0,0 +1,9 @@
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.39.0"
+    }
+  }
+}
@@ -0,0 +1,40 @@
+#!/bin/sh
+# Imports the existing cloud resources of the cluster into terraform state.
+# If the configuration is used as a module, pass the address of the module, for example module.kubernetes
+set -e
+prefix="${1:+$1.}"
+terraform import "${prefix}"'aws_autoscaling_group.master-us-test-1a-masters-minimal-example-com' 'master-us-test-1a.masters.minimal.example.com'
+terraform import "${prefix}"'aws_autoscaling_group.nodes-minimal-example-com' 'nodes.minimal.example.com'
+terraform import "${prefix}"'aws_ebs_volume.us-test-1a-etcd-events-minimal-example-com' 'vol-x'
+terraform import "${prefix}"'aws_ebs_volume.us-test-1a-etcd-main-minimal-example-com' 'vol-x'
+terraform import "${prefix}"'aws_iam_instance_profile.masters-minimal-example-com' 'masters.minimal.example.com'
+terraform import "${prefix}"'aws_iam_instance_profile.nodes-minimal-example-com' 'nodes.minimal.example.com'
+terraform import "${prefix}"'aws_iam_role.masters-minimal-example-com' 'masters.minimal.example.com'
+terraform import "${prefix}"'aws_iam_role.nodes-minimal-example-com' 'nodes.minimal.example.com'
+terraform import "${prefix}"'aws_iam_role_policy.masters-minimal-example-com' 'masters.minimal.example.com:masters.minimal.example.com'
+terraform import "${prefix}"'aws_iam_role_policy.nodes-minimal-example-com' 'nodes.minimal.example.com:nodes.minimal.example.com'
+terraform import "${prefix}"'aws_internet_gateway.minimal-example-com' 'igw-x'
+terraform import "${prefix}"'aws_key_pair.kubernetes-minimal-example-com-c4a6ed9aa889b9e2c39cd663eb9c7157' 'kubernetes.minimal.example.com-c4:a6:ed:9a:a8:89:b9:e2:c3:9c:d6:63:eb:9c:71:57'
+terraform import "${prefix}"'aws_launch_template.master-us-test-1a-masters-minimal-example-com' 'lt-x'
+terraform import "${prefix}"'aws_launch_template.nodes-minimal-example-com' 'lt-x'
+terraform import "${prefix}"'aws_route.route-0-0-0-0--0' 'rtb-x_0.0.0.0/0'
+terraform import "${prefix}"'aws_route_table.minimal-example-com' 'rtb-x'
+terraform import "${prefix}"'aws_route_table_association.us-test-1a-minimal-example-com' 'subnet-x/rtb-x'
+terraform import "${prefix}"'aws_security_group.masters-minimal-example-com' 'sg-x'
+terraform import "${prefix}"'aws_security_group.nodes-minimal-example-com' 'sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-0-0-0-0--0-ingress-tcp-22to22-masters-minimal-example-com' 'sg-x_ingress_tcp_22_22_0.0.0.0/0'
+terraform import "${prefix}"'aws_security_group_rule.from-0-0-0-0--0-ingress-tcp-22to22-nodes-minimal-example-com' 'sg-x_ingress_tcp_22_22_0.0.0.0/0'
+terraform import "${prefix}"'aws_security_group_rule.from-0-0-0-0--0-ingress-tcp-443to443-masters-minimal-example-com' 'sg-x_ingress_tcp_443_443_0.0.0.0/0'
+terraform import "${prefix}"'aws_security_group_rule.from-masters-minimal-example-com-egress-all-0to0-0-0-0-0--0' 'sg-x_egress_all_0_65536_0.0.0.0/0'
+terraform import "${prefix}"'aws_security_group_rule.from-masters-minimal-example-com-ingress-all-0to0-masters-minimal-example-com' 'sg-x_ingress_all_0_65536_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-masters-minimal-example-com-ingress-all-0to0-nodes-minimal-example-com' 'sg-x_ingress_all_0_65536_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-minimal-example-com-egress-all-0to0-0-0-0-0--0' 'sg-x_egress_all_0_65536_0.0.0.0/0'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-minimal-example-com-ingress-all-0to0-nodes-minimal-example-com' 'sg-x_ingress_all_0_65536_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-minimal-example-com-ingress-tcp-1to2379-masters-minimal-example-com' 'sg-x_ingress_tcp_1_2379_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-minimal-example-com-ingress-tcp-2382to4000-masters-minimal-example-com' 'sg-x_ingress_tcp_2382_4000_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-minimal-example-com-ingress-tcp-4003to65535-masters-minimal-example-com' 'sg-x_ingress_tcp_4003_65535_sg-x'
+terraform import "${prefix}"'aws_security_group_rule.from-nodes-minimal-example-com-ingress-udp-1to65535-masters-minimal-example-com' 'sg-x_ingress_udp_1_65535_sg-x'
+terraform import "${prefix}"'aws_subnet.us-test-1a-minimal-example-com' 'subnet-x'
+terraform import "${prefix}"'aws_vpc.minimal-example-com' 'vpc-x'
+terraform import "${prefix}"'aws_vpc_dhcp_options.minimal-example-com' 'dopt-x'
+terraform import "${prefix}"'aws_vpc_dhcp_options_association.minimal-example-com' 'vpc-x'
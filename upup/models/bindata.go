@@ -1,6 +1,6 @@
 // Code generated by go-bindata. (@generated) DO NOT EDIT.
 
-//Package models generated by go-bindata.// sources:
+// Package models generated by go-bindata.// sources:
 // upup/models/cloudup/resources/addons/OWNERS
 // upup/models/cloudup/resources/addons/anonymous-issuer-discovery.addons.k8s.io/k8s-1.16.yaml.template
 // upup/models/cloudup/resources/addons/authentication.aws/k8s-1.12.yaml.template
@@ -18,6 +18,7 @@
 // upup/models/cloudup/resources/addons/dns-controller.addons.k8s.io/k8s-1.12.yaml.template
 // upup/models/cloudup/resources/addons/external-dns.addons.k8s.io/README.md
 // upup/models/cloudup/resources/addons/external-dns.addons.k8s.io/k8s-1.12.yaml.template
+// upup/models/cloudup/resources/addons/hcloud-cloud-controller.addons.k8s.io/k8s-1.19.yaml.template
 // upup/models/cloudup/resources/addons/kops-controller.addons.k8s.io/k8s-1.16.yaml.template
 // upup/models/cloudup/resources/addons/kube-dns.addons.k8s.io/k8s-1.12.yaml.template
 // upup/models/cloudup/resources/addons/kubelet-api.rbac.addons.k8s.io/k8s-1.9.yaml
@@ -29503,6 +29504,206 @@ func cloudupResourcesAddonsExternalDnsAddonsK8sIoK8s112YamlTemplate() (*asset, e
 	return a, nil
 }
 
+var _cloudupResourcesAddonsHcloudCloudControllerAddonsK8sIoK8s119YamlTemplate = []byte(`---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: hcloud
+  namespace: kube-system
+stringData:
+  token: {{ HCLOUD_TOKEN }}
+  network: {{ ClusterName }}
+
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: hcloud-cloud-controller-manager
+  namespace: kube-system
+spec:
+  selector:
+    matchLabels:
+      k8s-app: hcloud-cloud-controller-manager
+  template:
+    metadata:
+      labels:
+        k8s-app: hcloud-cloud-controller-manager
+    spec:
+      nodeSelector:
+        node-role.kubernetes.io/master: ""
+      serviceAccountName: cloud-controller-manager
+      dnsPolicy: Default
+      hostNetwork: true
+      priorityClassName: system-node-critical
+      tolerations:
+        - key: "node.cloudprovider.kubernetes.io/uninitialized"
+          value: "true"
+          effect: "NoSchedule"
+        - key: "CriticalAddonsOnly"
+          operator: "Exists"
+        - key: "node-role.kubernetes.io/master"
+          effect: NoSchedule
+        - effect: NoExecute
+          key: node.kubernetes.io/not-ready
+          operator: Exists
+          tolerationSeconds: 300
+        - effect: NoExecute
+          key: node.kubernetes.io/unreachable
+          operator: Exists
+          tolerationSeconds: 300
+      containers:
+      - image: hetznercloud/hcloud-cloud-controller-manager:v1.10.0
+        name: hcloud-cloud-controller-manager
+        command:
+          - "/bin/hcloud-cloud-controller-manager"
+          - "--cloud-provider=hcloud"
+          - "--leader-elect=true"
+          - "--allow-untagged-cloud"
+          - "--configure-cloud-routes=false"
+        resources:
+          requests:
+            cpu: 100m
+            memory: 50Mi
+        env:
+          - name: KUBERNETES_SERVICE_HOST
+            value: "127.0.0.1"
+          - name: KUBERNETES_SERVICE_PORT
+            value: "443"
+          - name: NODE_NAME
+            valueFrom:
+              fieldRef:
+                fieldPath: spec.nodeName
+          - name: HCLOUD_TOKEN
+            valueFrom:
+              secretKeyRef:
+                name: hcloud
+                key: token
+          - name: HCLOUD_NETWORK
+            valueFrom:
+              secretKeyRef:
+                name: hcloud
+                key: network
+          - name: HCLOUD_LOAD_BALANCERS_USE_PRIVATE_IP
+            value: "true"
+
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: cloud-controller-manager
+  namespace: kube-system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  annotations:
+    rbac.authorization.kubernetes.io/autoupdate: "true"
+  name: system:cloud-controller-manager
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - events
+  verbs:
+  - create
+  - patch
+  - update
+- apiGroups:
+  - ""
+  resources:
+  - nodes
+  verbs:
+  - '*'
+- apiGroups:
+  - ""
+  resources:
+  - nodes/status
+  verbs:
+  - patch
+- apiGroups:
+  - ""
+  resources:
+  - services
+  verbs:
+  - list
+  - patch
+  - update
+  - watch
+- apiGroups:
+  - ""
+  resources:
+  - services/status
+  verbs:
+  - list
+  - patch
+  - update
+  - watch
+- apiGroups:
+  - ""
+  resources:
+  - serviceaccounts
+  verbs:
+  - create
+- apiGroups:
+  - ""
+  resources:
+  - persistentvolumes
+  verbs:
+  - get
+  - list
+  - update
+  - watch
+- apiGroups:
+  - ""
+  resources:
+  - endpoints
+  verbs:
+  - create
+  - get
+  - list
+  - watch
+  - update
+- apiGroups:
+  - coordination.k8s.io
+  resources:
+  - leases
+  verbs:
+  - get
+  - watch
+  - list
+  - create
+  - update
+  - delete
+---
+kind: ClusterRoleBinding
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: system:cloud-controller-manager
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:cloud-controller-manager
+subjects:
+- kind: ServiceAccount
+  name: cloud-controller-manager
+  namespace: kube-system
+`)
+
+func cloudupResourcesAddonsHcloudCloudControllerAddonsK8sIoK8s119YamlTemplateBytes() ([]byte, error) {
+	return _cloudupResourcesAddonsHcloudCloudControllerAddonsK8sIoK8s119YamlTemplate, nil
+}
+
+func cloudupResourcesAddonsHcloudCloudControllerAddonsK8sIoK8s119YamlTemplate() (*asset, error) {
+	bytes, err := cloudupResourcesAddonsHcloudCloudControllerAddonsK8sIoK8s119YamlTemplateBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "cloudup/resources/addons/hcloud-cloud-controller.addons.k8s.io/k8s-1.19.yaml.template", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _cloudupResourcesAddonsKopsControllerAddonsK8sIoK8s116YamlTemplate = []byte(`apiVersion: v1
 kind: ConfigMap
 metadata:
@@ -35408,7 +35609,9 @@ data:
           "nodename": "__KUBERNETES_NODE_NAME__",
           "mtu": __CNI_MTU__,
           "ipam": {
-              "type": "calico-ipam"
+              "type": "calico-ipam"{{ if UsesIPv6 }},
+              "assign_ipv4": "{{ UsesIPv4 }}",
+              "assign_ipv6": "true"{{ end }}
           },
           "policy": {
               "type": "k8s"
@@ -39170,7 +39373,16 @@ spec:
               value: "kops,bgp"
             # Auto-detect the BGP IP address.
             - name: IP
+              value: "{{ if UsesIPv4 }}autodetect{{ else }}none{{ end }}"
+            {{- if UsesIPv6 }}
+            - name: IP6
               value: "autodetect"
+            {{- end }}
+            {{- if IsIPv6Only }}
+            # IPv6-only nodes have no IPv4 address to use as the BGP router ID
+            - name: CALICO_ROUTER_ID
+              value: "hash"
+            {{- end }}
             - name: IP_AUTODETECTION_METHOD
               value: "{{- or .Networking.Calico.IPv4AutoDetectionMethod "first-found" }}"
             - name: IP6_AUTODETECTION_METHOD
@@ -39203,16 +39415,24 @@ spec:
             # chosen from this range. Changing this value after installation will have
             # no effect. This should fall within ` + "`" + `--cluster-cidr` + "`" + `.
             - name: CALICO_IPV4POOL_CIDR
-              value: "{{ .KubeControllerManager.ClusterCIDR }}"
+              value: "{{ PodIPv4CIDR }}"
+            {{- if UsesIPv6 }}
+            # The default IPv6 pool to create on startup if none exists.  Pod IPs are
+            # unique local addresses, so traffic leaving the cluster is masqueraded.
+            - name: CALICO_IPV6POOL_CIDR
+              value: "{{ .PodIPv6CIDR }}"
+            - name: CALICO_IPV6POOL_NAT_OUTGOING
+              value: "true"
+            {{- end }}
             # Disable file logging so ` + "`" + `kubectl logs` + "`" + ` works.
             - name: CALICO_DISABLE_FILE_LOGGING
               value: "true"
             # Set Felix endpoint to host default action to ACCEPT.
             - name: FELIX_DEFAULTENDPOINTTOHOSTACTION
               value: "ACCEPT"
-            # Disable IPv6 on Kubernetes.
+            # Enable IPv6 on Kubernetes if the cluster uses it.
             - name: FELIX_IPV6SUPPORT
-              value: "false"
+              value: "{{ UsesIPv6 }}"
             # Set Felix logging to "info"
             - name: FELIX_LOGSEVERITYSCREEN
               value: "{{- or .Networking.Calico.LogSeverityScreen "info" }}"
@@ -44253,6 +44473,7 @@ var _bindata = map[string]func() (*asset, error){
 	"cloudup/resources/addons/dns-controller.addons.k8s.io/k8s-1.12.yaml.template":                        cloudupResourcesAddonsDnsControllerAddonsK8sIoK8s112YamlTemplate,
 	"cloudup/resources/addons/external-dns.addons.k8s.io/README.md":                                       cloudupResourcesAddonsExternalDnsAddonsK8sIoReadmeMd,
 	"cloudup/resources/addons/external-dns.addons.k8s.io/k8s-1.12.yaml.template":                          cloudupResourcesAddonsExternalDnsAddonsK8sIoK8s112YamlTemplate,
+	"cloudup/resources/addons/hcloud-cloud-controller.addons.k8s.io/k8s-1.19.yaml.template":               cloudupResourcesAddonsHcloudCloudControllerAddonsK8sIoK8s119YamlTemplate,
 	"cloudup/resources/addons/kops-controller.addons.k8s.io/k8s-1.16.yaml.template":                       cloudupResourcesAddonsKopsControllerAddonsK8sIoK8s116YamlTemplate,
 	"cloudup/resources/addons/kube-dns.addons.k8s.io/k8s-1.12.yaml.template":                              cloudupResourcesAddonsKubeDnsAddonsK8sIoK8s112YamlTemplate,
 	"cloudup/resources/addons/kubelet-api.rbac.addons.k8s.io/k8s-1.9.yaml":                                cloudupResourcesAddonsKubeletApiRbacAddonsK8sIoK8s19Yaml,
@@ -44293,11 +44514,13 @@ var _bindata = map[string]func() (*asset, error){
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"}
 // AssetDir("data/img") would return []string{"a.png", "b.png"}
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error
@@ -44376,6 +44599,9 @@ var _bintree = &bintree{nil, map[string]*bintree{
 					"README.md":              {cloudupResourcesAddonsExternalDnsAddonsK8sIoReadmeMd, map[string]*bintree{}},
 					"k8s-1.12.yaml.template": {cloudupResourcesAddonsExternalDnsAddonsK8sIoK8s112YamlTemplate, map[string]*bintree{}},
 				}},
+				"hcloud-cloud-controller.addons.k8s.io": {nil, map[string]*bintree{
+					"k8s-1.19.yaml.template": {cloudupResourcesAddonsHcloudCloudControllerAddonsK8sIoK8s119YamlTemplate, map[string]*bintree{}},
+				}},
 				"kops-controller.addons.k8s.io": {nil, map[string]*bintree{
 					"k8s-1.16.yaml.template": {cloudupResourcesAddonsKopsControllerAddonsK8sIoK8s116YamlTemplate, map[string]*bintree{}},
 				}},
@@ -17,12 +17,14 @@ limitations under the License.
 package models
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"os"
 	"path"
 	"strings"
 
+	"k8s.io/kops/util/pkg/hashing"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -56,6 +58,28 @@ func (p *AssetPath) CreateFile(data io.ReadSeeker, acl vfs.ACL) error {
 	return ReadOnlyError
 }
 
+func (p *AssetPath) WriteFileIfVersion(data io.ReadSeeker, acl vfs.ACL, version string) (string, error) {
+	return "", ReadOnlyError
+}
+
+func (p *AssetPath) RemoveIfVersion(version string) error {
+	return ReadOnlyError
+}
+
+// ReadFileWithVersion implements Path::ReadFileWithVersion
+// Assets never change, so the version is the hash of the contents.
+func (p *AssetPath) ReadFileWithVersion() ([]byte, string, error) {
+	data, err := p.ReadFile()
+	if err != nil {
+		return nil, "", err
+	}
+	h, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	return data, h.Hex(), nil
+}
+
 // WriteTo implements io.WriterTo
 func (p *AssetPath) WriteTo(out io.Writer) (int64, error) {
 	data, err := p.ReadFile()
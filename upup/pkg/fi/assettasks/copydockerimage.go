@@ -17,10 +17,12 @@ limitations under the License.
 package assettasks
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/ociregistry"
 )
 
 // CopyDockerImage copies a docker image from a source registry, to a target registry,
@@ -40,59 +42,42 @@ func (e *CopyDockerImage) CompareWithID() *string {
 }
 
 func (e *CopyDockerImage) Find(c *fi.Context) (*CopyDockerImage, error) {
-	return nil, nil
-
-	// The problem here is that we can tag a local image with the remote tag, but there is no way to know
-	// if that has actually been pushed to the remote registry without doing a docker push
-
-	// The solution is probably to query the registries directly, but that is a little bit more code...
-
-	// For now, we just always do the copy; it isn't _too_ slow when things have already been pushed
-
-	//d, err := newDocker()
-	//if err != nil {
-	//	return nil, err
-	//}
-	//
-	//source := fi.StringValue(e.SourceImage)
-	//target := fi.StringValue(e.TargetImage)
-	//
-	//targetImage, err := d.findImage(target)
-	//if err != nil {
-	//	return nil, err
-	//}
-	//if targetImage == nil {
-	//	klog.V(4).Infof("target image %q not found", target)
-	//	return nil, nil
-	//}
-	//
-	//// We want to verify that the target image matches
-	//if err := d.pullImage(source); err != nil {
-	//	return nil, err
-	//}
-	//
-	//sourceImage, err := d.findImage(source)
-	//if err != nil {
-	//	return nil, err
-	//}
-	//if sourceImage == nil {
-	//	return nil, fmt.Errorf("source image %q not found", source)
-	//}
-	//
-	//if sourceImage.ID == targetImage.ID {
-	//	actual := &CopyDockerImage{}
-	//	actual.Name = e.Name
-	//	actual.SourceImage = e.SourceImage
-	//	actual.TargetImage = e.TargetImage
-	//	klog.Infof("Found image %q = %s", target, sourceImage.ID)
-	//	return actual, nil
-	//}
-	//
-	//klog.V(2).Infof("Target image %q does not match source %q: %q vs %q",
-	//	target, source,
-	//	targetImage.ID, sourceImage.ID)
-	//
-	//return nil, nil
+	ctx := context.TODO()
+
+	source := fi.StringValue(e.SourceImage)
+	target := fi.StringValue(e.TargetImage)
+
+	registry := ociregistry.NewClient()
+
+	targetDigest, err := registry.Digest(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if targetDigest == "" {
+		klog.V(4).Infof("target image %q not found", target)
+		return nil, nil
+	}
+
+	sourceDigest, err := registry.Digest(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if sourceDigest == "" {
+		return nil, fmt.Errorf("source image %q not found", source)
+	}
+
+	if sourceDigest != targetDigest {
+		klog.V(2).Infof("Target image %q does not match source %q: %q vs %q", target, source, targetDigest, sourceDigest)
+		return nil, nil
+	}
+
+	klog.V(2).Infof("Found image %q = %s", target, targetDigest)
+	actual := &CopyDockerImage{}
+	actual.Name = e.Name
+	actual.SourceImage = e.SourceImage
+	actual.TargetImage = e.TargetImage
+	actual.Lifecycle = e.Lifecycle
+	return actual, nil
 }
 
 func (e *CopyDockerImage) Run(c *fi.Context) error {
@@ -113,42 +98,16 @@ func (s *CopyDockerImage) CheckChanges(a, e, changes *CopyDockerImage) error {
 }
 
 func (_ *CopyDockerImage) Render(c *fi.Context, a, e, changes *CopyDockerImage) error {
-	api, err := newDockerAPI()
-	if err != nil {
-		return err
-	}
-
-	cli, err := newDockerCLI()
-	if err != nil {
-		return err
-
-	}
+	ctx := context.TODO()
 
 	source := fi.StringValue(e.SourceImage)
 	target := fi.StringValue(e.TargetImage)
 
 	klog.Infof("copying docker image from %q to %q", source, target)
 
-	err = cli.pullImage(source)
-	if err != nil {
-		return fmt.Errorf("error pulling image %q: %v", source, err)
-	}
-	sourceImage, err := api.findImage(source)
-	if err != nil {
-		return fmt.Errorf("error finding image %q: %v", source, err)
-	}
-	if sourceImage == nil {
-		return fmt.Errorf("source image %q not found", source)
-	}
-
-	err = api.tagImage(sourceImage.ID, target)
-	if err != nil {
-		return fmt.Errorf("error tagging image %q: %v", source, err)
-	}
-
-	err = cli.pushImage(target)
-	if err != nil {
-		return fmt.Errorf("error pushing image %q: %v", target, err)
+	// Images are copied registry-to-registry, so no docker daemon is needed
+	if err := ociregistry.NewClient().Copy(ctx, source, target); err != nil {
+		return fmt.Errorf("error copying image %q to %q: %v", source, target, err)
 	}
 
 	return nil
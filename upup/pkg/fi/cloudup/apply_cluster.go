@@ -51,7 +51,9 @@ import (
 	"k8s.io/kops/pkg/model/components/kubeapiserver"
 	"k8s.io/kops/pkg/model/domodel"
 	"k8s.io/kops/pkg/model/gcemodel"
+	"k8s.io/kops/pkg/model/hetznermodel"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/model/metalmodel"
 	"k8s.io/kops/pkg/model/openstackmodel"
 	"k8s.io/kops/pkg/resources/digitalocean"
 	"k8s.io/kops/pkg/templates"
@@ -65,7 +67,10 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+	"k8s.io/kops/upup/pkg/fi/cloudup/resourcegraph"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/hashing"
@@ -106,6 +111,15 @@ type ApplyClusterCmd struct {
 	// OutDir is a local directory in which we place output, can cache files etc
 	OutDir string
 
+	// TerraformModule writes the terraform output as a reusable module
+	TerraformModule bool
+
+	// TerraformImport writes a script that imports the existing cloud resources into terraform state
+	TerraformImport bool
+
+	// CloudformationNestedStacks splits the cloudformation output into a nested stack per instance group
+	CloudformationNestedStacks bool
+
 	// Assets is a list of sources for files (primarily when not using everything containerized)
 	// Formats:
 	//  raw url: http://... or https://...
@@ -136,6 +150,18 @@ type ApplyClusterCmd struct {
 
 	// TaskMap is the map of tasks that we built (output)
 	TaskMap map[string]fi.Task
+
+	// GetAssets stops once the tasks are built, recording the assets that the cluster uses rather than applying any changes.
+	GetAssets bool
+
+	// ContainerAssets are the container images the cluster uses (output)
+	ContainerAssets []*assets.ContainerAsset
+
+	// FileAssets are the files the cluster uses (output)
+	FileAssets []*assets.FileAsset
+
+	// FileAssetUsage records which nodes download each file, keyed by the URL it is downloaded from (output)
+	FileAssetUsage map[string]*FileAssetUsage
 }
 
 func (c *ApplyClusterCmd) Run(ctx context.Context) error {
@@ -317,7 +343,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			warn = true
 		}
 
-		if warn {
+		if warn && !c.GetAssets {
 			fmt.Println("")
 			fmt.Printf("%s\n", starline)
 			fmt.Println("")
@@ -461,6 +487,26 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 				return fmt.Errorf("exactly one 'admin' SSH public key can be specified when running with AzureCloud; please delete a key using `kops delete secret`")
 			}
 		}
+	case kops.CloudProviderHetzner:
+		{
+			if !featureflag.Hetzner.Enabled() {
+				return fmt.Errorf("hetzner support is currently alpha, and is feature-gated. Please export KOPS_FEATURE_FLAGS=Hetzner")
+			}
+
+			if len(sshPublicKeys) == 0 {
+				return fmt.Errorf("SSH public key must be specified when running with Hetzner (create with `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub`)", cluster.ObjectMeta.Name)
+			}
+
+			if len(sshPublicKeys) != 1 {
+				return fmt.Errorf("exactly one 'admin' SSH public key can be specified when running with Hetzner; please delete a key using `kops delete secret`")
+			}
+		}
+	case kops.CloudProviderMetal:
+		{
+			if !featureflag.Metal.Enabled() {
+				return fmt.Errorf("metal support is currently alpha, and is feature-gated. Please export KOPS_FEATURE_FLAGS=Metal")
+			}
+		}
 	case kops.CloudProviderOpenstack:
 		{
 			if len(sshPublicKeys) == 0 {
@@ -492,7 +538,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		cloud:            cloud,
 	}
 
-	configBuilder, err := newNodeUpConfigBuilder(cluster, assetBuilder, c.Assets)
+	configBuilder, err := newNodeUpConfigBuilder(cluster, c.InstanceGroups, assetBuilder, c.Assets)
 	if err != nil {
 		return err
 	}
@@ -556,6 +602,8 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 				&model.NetworkModelBuilder{KopsModelContext: modelContext, Lifecycle: &networkLifecycle},
 				&model.IAMModelBuilder{KopsModelContext: modelContext, Lifecycle: &securityLifecycle},
 				&awsmodel.OIDCProviderBuilder{KopsModelContext: modelContext, Lifecycle: &securityLifecycle, KeyStore: keyStore},
+				&awsmodel.VPCEndpointModelBuilder{AWSModelContext: awsModelContext, Lifecycle: &networkLifecycle, SecurityLifecycle: &securityLifecycle},
+				&awsmodel.FlowLogsModelBuilder{AWSModelContext: awsModelContext, Lifecycle: &networkLifecycle, SecurityLifecycle: &securityLifecycle},
 			)
 
 			awsModelBuilder := &awsmodel.AutoscalingGroupModelBuilder{
@@ -585,7 +633,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 				KopsModelContext: modelContext,
 			}
 			l.Builders = append(l.Builders,
+				&domodel.NetworkModelBuilder{DOModelContext: doModelContext, Lifecycle: &networkLifecycle},
 				&domodel.APILoadBalancerModelBuilder{DOModelContext: doModelContext, Lifecycle: &securityLifecycle},
+				&domodel.FirewallModelBuilder{DOModelContext: doModelContext, Lifecycle: &securityLifecycle},
 				&domodel.DropletBuilder{DOModelContext: doModelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: &clusterLifecycle},
 			)
 		case kops.CloudProviderGCE:
@@ -629,6 +679,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			l.Builders = append(l.Builders,
 				&azuremodel.APILoadBalancerModelBuilder{AzureModelContext: azureModelContext, Lifecycle: &clusterLifecycle},
 				&azuremodel.NetworkModelBuilder{AzureModelContext: azureModelContext, Lifecycle: &clusterLifecycle},
+				&azuremodel.NetworkSecurityGroupModelBuilder{AzureModelContext: azureModelContext, Lifecycle: &securityLifecycle},
 				&azuremodel.ResourceGroupModelBuilder{AzureModelContext: azureModelContext, Lifecycle: &clusterLifecycle},
 
 				&azuremodel.VMScaleSetModelBuilder{AzureModelContext: azureModelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: &clusterLifecycle},
@@ -645,6 +696,24 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 				&openstackmodel.ServerGroupModelBuilder{OpenstackModelContext: openstackModelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: &clusterLifecycle},
 			)
 
+		case kops.CloudProviderHetzner:
+			hetznerModelContext := &hetznermodel.HetznerModelContext{
+				KopsModelContext: modelContext,
+			}
+
+			l.Builders = append(l.Builders,
+				&hetznermodel.NetworkModelBuilder{HetznerModelContext: hetznerModelContext, Lifecycle: &networkLifecycle},
+				&hetznermodel.SSHKeyModelBuilder{HetznerModelContext: hetznerModelContext, Lifecycle: &securityLifecycle},
+				&hetznermodel.FirewallModelBuilder{HetznerModelContext: hetznerModelContext, Lifecycle: &securityLifecycle},
+				&hetznermodel.APILoadBalancerModelBuilder{HetznerModelContext: hetznerModelContext, Lifecycle: &clusterLifecycle},
+				&hetznermodel.ServerGroupModelBuilder{HetznerModelContext: hetznerModelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: &clusterLifecycle},
+			)
+
+		case kops.CloudProviderMetal:
+			l.Builders = append(l.Builders,
+				&metalmodel.HostModelBuilder{KopsModelContext: modelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: &clusterLifecycle},
+			)
+
 		default:
 			return fmt.Errorf("unknown cloudprovider %q", cluster.Spec.CloudProvider)
 		}
@@ -656,6 +725,15 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 
 	c.TaskMap = taskMap
 
+	if c.GetAssets {
+		c.ContainerAssets = assetBuilder.ContainerAssets
+		c.FileAssets = assetBuilder.FileAssets
+		if n, ok := configBuilder.(*nodeUpConfigBuilder); ok {
+			c.FileAssetUsage = c.fileAssetUsage(n)
+		}
+		return nil
+	}
+
 	var target fi.Target
 	dryRun := false
 	shouldPrecreateDNS := true
@@ -675,6 +753,10 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			target = aliup.NewALIAPITarget(cloud.(aliup.ALICloud))
 		case kops.CloudProviderAzure:
 			target = azure.NewAzureAPITarget(cloud.(azure.AzureCloud))
+		case kops.CloudProviderHetzner:
+			target = hetzner.NewHetznerAPITarget(cloud.(hetzner.HetznerCloud))
+		case kops.CloudProviderMetal:
+			target = metal.NewMetalAPITarget(cloud.(metal.MetalCloud))
 		default:
 			return fmt.Errorf("direct configuration not supported with CloudProvider:%q", cluster.Spec.CloudProvider)
 		}
@@ -683,6 +765,8 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		checkExisting = false
 		outDir := c.OutDir
 		tf := terraform.NewTerraformTarget(cloud, project, outDir, cluster.Spec.Target)
+		tf.Module = c.TerraformModule
+		tf.ImportExisting = c.TerraformImport
 
 		// We include a few "util" variables in the TF output
 		if err := tf.AddOutputVariable("region", terraform.LiteralFromStringValue(cloud.Region())); err != nil {
@@ -707,11 +791,30 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	case TargetCloudformation:
 		checkExisting = false
 		outDir := c.OutDir
-		target = cloudformation.NewCloudformationTarget(cloud, project, outDir)
+		cf := cloudformation.NewCloudformationTarget(cloud, project, outDir)
+		cf.NestedStacks = c.CloudformationNestedStacks
+
+		// We include the same "util" outputs as in the TF output
+		if err := cf.AddOutputVariable("region", cloudformation.LiteralString(cloud.Region())); err != nil {
+			return err
+		}
+
+		if err := cf.AddOutputVariable("cluster_name", cloudformation.LiteralString(cluster.ObjectMeta.Name)); err != nil {
+			return err
+		}
+
+		target = cf
 
 		// Can cause conflicts with cloudformation management
 		shouldPrecreateDNS = false
 
+	case TargetResourceGraph:
+		checkExisting = false
+		target = resourcegraph.NewResourceGraphTarget(cloud, c.OutDir)
+
+		// Can cause conflicts with the tool that applies the resources
+		shouldPrecreateDNS = false
+
 	case TargetDryRun:
 		target = fi.NewDryRunTarget(assetBuilder, os.Stdout)
 		dryRun = true
@@ -725,6 +828,11 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	c.Target = target
 
 	if !dryRun {
+		// The context is cancelled if the command loses the cluster lock
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("not writing cluster state: %v", err)
+		}
+
 		acl, err := acls.GetACL(configBase, cluster)
 		if err != nil {
 			return err
@@ -768,6 +876,10 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		options.InitDefaults()
 	}
 
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("not running tasks: %v", err)
+	}
+
 	err = context.RunTasks(options)
 	if err != nil {
 		return fmt.Errorf("error running tasks: %v", err)
@@ -1116,9 +1228,10 @@ type nodeUpConfigBuilder struct {
 	images         map[kops.InstanceGroupRole]map[architectures.Architecture][]*nodeup.Image
 	protokubeAsset map[architectures.Architecture][]*mirrors.MirroredAsset
 	channelsAsset  map[architectures.Architecture][]*mirrors.MirroredAsset
+	gossipSeeds    []string
 }
 
-func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBuilder, assets map[architectures.Architecture][]*mirrors.MirroredAsset) (model.NodeUpConfigBuilder, error) {
+func newNodeUpConfigBuilder(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, assetBuilder *assets.AssetBuilder, assets map[architectures.Architecture][]*mirrors.MirroredAsset) (model.NodeUpConfigBuilder, error) {
 	configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigBase)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing config base %q: %v", cluster.Spec.ConfigBase, err)
@@ -1235,6 +1348,11 @@ func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 		channelsAsset:  channelsAsset,
 	}
 
+	// Pre-provisioned hosts cannot discover each other through a cloud API, so they find the masters at their inventory addresses
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderMetal {
+		configBuilder.gossipSeeds = metal.GossipSeeds(instanceGroups)
+	}
+
 	return &configBuilder, nil
 }
 
@@ -1308,6 +1426,8 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 		config.ApiserverAdditionalIPs = apiserverAdditionalIPs
 	}
 
+	config.GossipSeeds = n.gossipSeeds
+
 	for _, manifest := range n.assetBuilder.StaticManifests {
 		match := false
 		for _, r := range manifest.Roles {
@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/dns"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kops/util/pkg/mirrors"
+)
+
+// FileAssetUsage records which nodes download a file asset
+type FileAssetUsage struct {
+	// Roles are the roles of the instance groups whose nodes download the file
+	Roles []kops.InstanceGroupRole
+	// Architectures are the architectures of the nodes that download the file
+	Architectures []architectures.Architecture
+}
+
+func (u *FileAssetUsage) add(role kops.InstanceGroupRole, arch architectures.Architecture) {
+	hasRole := false
+	for _, r := range u.Roles {
+		if r == role {
+			hasRole = true
+		}
+	}
+	if !hasRole {
+		u.Roles = append(u.Roles, role)
+	}
+
+	hasArch := false
+	for _, a := range u.Architectures {
+		if a == arch {
+			hasArch = true
+		}
+	}
+	if !hasArch {
+		u.Architectures = append(u.Architectures, arch)
+	}
+}
+
+// fileAssetUsage works out which nodes download each file asset, keyed by the URL that the file is downloaded from.
+// It mirrors the way BuildConfig assembles the assets for each instance group, for the role and architecture of the group.
+func (c *ApplyClusterCmd) fileAssetUsage(n *nodeUpConfigBuilder) map[string]*FileAssetUsage {
+	usage := make(map[string]*FileAssetUsage)
+	record := func(location string, role kops.InstanceGroupRole, arch architectures.Architecture) {
+		u := usage[location]
+		if u == nil {
+			u = &FileAssetUsage{}
+			usage[location] = u
+		}
+		u.add(role, arch)
+	}
+	recordAsset := func(asset *mirrors.MirroredAsset, role kops.InstanceGroupRole, arch architectures.Architecture) {
+		if asset == nil || len(asset.Locations) == 0 {
+			return
+		}
+		// The original URL is the last of the locations; any others are mirrors of it
+		record(asset.Locations[len(asset.Locations)-1], role, arch)
+	}
+
+	useGossip := dns.IsGossipHostname(c.Cluster.Spec.MasterInternalName)
+
+	for _, ig := range c.InstanceGroups {
+		role := ig.Spec.Role
+		// Bastions do not run nodeup, so do not download any files
+		if role == kops.InstanceGroupRoleBastion {
+			continue
+		}
+		isMaster := role == kops.InstanceGroupRoleMaster
+
+		for _, arch := range instanceGroupArchitectures(c.Cloud, ig) {
+			recordAsset(c.NodeUpAssets[arch], role, arch)
+
+			for _, a := range c.Assets[arch] {
+				recordAsset(a, role, arch)
+			}
+
+			if isMaster || useGossip {
+				for _, a := range n.protokubeAsset[arch] {
+					recordAsset(a, role, arch)
+				}
+				for _, a := range n.channelsAsset[arch] {
+					recordAsset(a, role, arch)
+				}
+			}
+
+			for _, image := range n.images[role][arch] {
+				for _, source := range image.Sources {
+					record(source, role, arch)
+				}
+			}
+		}
+	}
+
+	// Keep the output stable, regardless of the order of the instance groups
+	for _, u := range usage {
+		sort.Slice(u.Roles, func(i, j int) bool { return u.Roles[i] < u.Roles[j] })
+		sort.Slice(u.Architectures, func(i, j int) bool { return u.Architectures[i] < u.Architectures[j] })
+	}
+
+	return usage
+}
+
+// instanceGroupArchitectures returns the architectures that the nodes of the instance group can run on.
+// If the architecture can't be determined, all supported architectures are returned.
+func instanceGroupArchitectures(cloud fi.Cloud, ig *kops.InstanceGroup) []architectures.Architecture {
+	arch, err := instanceGroupArchitecture(cloud, ig)
+	if err != nil {
+		klog.Warningf("unable to determine architecture of instance group %q, assuming all architectures: %v", ig.ObjectMeta.Name, err)
+		return architectures.GetSupported()
+	}
+	if arch == "" {
+		return architectures.GetSupported()
+	}
+	for _, supported := range architectures.GetSupported() {
+		if arch == supported {
+			return []architectures.Architecture{arch}
+		}
+	}
+	return nil
+}
+
+// instanceGroupArchitecture returns the architecture of the image (AWS) or machine type (GCE, Azure) of the instance group,
+// or "" if the cloud provider does not tell us.
+func instanceGroupArchitecture(cloud fi.Cloud, ig *kops.InstanceGroup) (architectures.Architecture, error) {
+	switch c := cloud.(type) {
+	case awsup.AWSCloud:
+		image, err := c.ResolveImage(ig.Spec.Image)
+		if err != nil {
+			return "", err
+		}
+		switch fi.StringValue(image.Architecture) {
+		case "x86_64":
+			return architectures.ArchitectureAmd64, nil
+		case "arm64":
+			return architectures.ArchitectureArm64, nil
+		default:
+			return "", fmt.Errorf("unsupported architecture %q of image %q", fi.StringValue(image.Architecture), ig.Spec.Image)
+		}
+
+	case gce.GCECloud:
+		info, err := gce.GetMachineTypeInfo(ig.Spec.MachineType)
+		if err != nil {
+			return "", err
+		}
+		return info.Architecture, nil
+
+	case azure.AzureCloud:
+		info, err := azure.GetMachineTypeInfo(ig.Spec.MachineType)
+		if err != nil {
+			return "", err
+		}
+		return info.Architecture, nil
+
+	default:
+		return "", nil
+	}
+}
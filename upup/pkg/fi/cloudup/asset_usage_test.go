@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+func TestInstanceGroupArchitectures(t *testing.T) {
+	awsCloud := awsup.BuildMockAWSCloud("us-test-1", "a")
+	mockEC2 := &mockec2.MockEC2{}
+	awsCloud.MockEC2 = mockEC2
+	mockEC2.Images = append(mockEC2.Images, &ec2.Image{
+		CreationDate: aws.String("2021-01-01T00:00:00.000Z"),
+		ImageId:      aws.String("ami-amd64"),
+		Name:         aws.String("image-amd64"),
+		Architecture: aws.String("x86_64"),
+	}, &ec2.Image{
+		CreationDate: aws.String("2021-01-01T00:00:00.000Z"),
+		ImageId:      aws.String("ami-arm64"),
+		Name:         aws.String("image-arm64"),
+		Architecture: aws.String("arm64"),
+	})
+
+	grid := []struct {
+		cloud    fi.Cloud
+		image    string
+		expected []architectures.Architecture
+	}{
+		{
+			cloud:    awsCloud,
+			image:    "image-amd64",
+			expected: []architectures.Architecture{architectures.ArchitectureAmd64},
+		},
+		{
+			cloud:    awsCloud,
+			image:    "image-arm64",
+			expected: []architectures.Architecture{architectures.ArchitectureArm64},
+		},
+		{
+			// An image we can't resolve doesn't tell us the architecture
+			cloud:    awsCloud,
+			image:    "image-missing",
+			expected: architectures.GetSupported(),
+		},
+		{
+			// Neither does a cloud that doesn't report architectures
+			cloud:    nil,
+			image:    "image-amd64",
+			expected: architectures.GetSupported(),
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+			Spec: kops.InstanceGroupSpec{
+				Role:  kops.InstanceGroupRoleNode,
+				Image: g.image,
+			},
+		}
+		actual := instanceGroupArchitectures(g.cloud, ig)
+		if !reflect.DeepEqual(actual, g.expected) {
+			t.Errorf("image %q: expected architectures %v, got %v", g.image, g.expected, actual)
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
@@ -35,6 +36,9 @@ import (
 // CloudTagInstanceGroupRolePrefix is a cloud tag that defines the instance role
 const CloudTagInstanceGroupRolePrefix = "k8s.io/role/"
 
+// warmPoolLifecycleHookHeartbeatTimeout is the number of seconds an instance waits for nodeup before it continues launching
+const warmPoolLifecycleHookHeartbeatTimeout = 600
+
 // AutoscalingGroup provdes the definition for a autoscaling group in aws
 // +kops:fitask
 type AutoscalingGroup struct {
@@ -84,6 +88,14 @@ type AutoscalingGroup struct {
 	Tags map[string]string
 	// TargetGroups is a list of ALB/NLB target group ARNs to add to the autoscaling group
 	TargetGroups []*TargetGroup
+	// WarmPoolEnabled indicates the autoscaling group has a warm pool of pre-initialized instances
+	WarmPoolEnabled *bool
+	// WarmPoolMinSize is the minimum number of instances to maintain in the warm pool
+	WarmPoolMinSize *int64
+	// WarmPoolMaxPreparedCapacity is the maximum number of instances allowed in the warm pool and the autoscaling group
+	WarmPoolMaxPreparedCapacity *int64
+	// WarmPoolState is the state of the instances in the warm pool
+	WarmPoolState *string
 }
 
 var _ fi.CompareWithID = &AutoscalingGroup{}
@@ -248,6 +260,29 @@ func (e *AutoscalingGroup) Find(c *fi.Context) (*AutoscalingGroup, error) {
 		actual.InstanceProtection = g.NewInstancesProtectedFromScaleIn
 	}
 
+	warmPool, err := cloud.Autoscaling().DescribeWarmPool(&autoscaling.DescribeWarmPoolInput{
+		AutoScalingGroupName: g.AutoScalingGroupName,
+	})
+	if err != nil {
+		// Warm pools may be unsupported in the region, or not permitted by the IAM policy,
+		// in which case the group cannot have one, so we only fail if we need the warm pool
+		code := awsup.AWSErrorCode(err)
+		if fi.BoolValue(e.WarmPoolEnabled) || (code != "AccessDenied" && code != "ValidationError") {
+			return nil, fmt.Errorf("error describing warm pool of AutoScalingGroup %q: %v", aws.StringValue(g.AutoScalingGroupName), err)
+		}
+		klog.V(2).Infof("unable to describe warm pool of AutoScalingGroup %q, assuming it has none: %v", aws.StringValue(g.AutoScalingGroupName), err)
+		warmPool = &autoscaling.DescribeWarmPoolOutput{}
+	}
+	actual.WarmPoolEnabled = fi.Bool(warmPool.WarmPoolConfiguration != nil)
+	if wp := warmPool.WarmPoolConfiguration; wp != nil {
+		actual.WarmPoolMinSize = wp.MinSize
+		actual.WarmPoolState = wp.PoolState
+		// A negative value means the max prepared capacity defaults to the max size of the group
+		if aws.Int64Value(wp.MaxGroupPreparedCapacity) >= 0 {
+			actual.WarmPoolMaxPreparedCapacity = wp.MaxGroupPreparedCapacity
+		}
+	}
+
 	return actual, nil
 }
 
@@ -383,6 +418,11 @@ func (v *AutoscalingGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Autos
 			return fmt.Errorf("could not find one of launch template or mixed instances policy")
 		}
 
+		if fi.BoolValue(e.WarmPoolEnabled) {
+			// The lifecycle hook must exist before the first instances launch
+			request.LifecycleHookSpecificationList = []*autoscaling.LifecycleHookSpecification{warmPoolLifecycleHook()}
+		}
+
 		// @step: attempt to create the autoscaling group for us
 		if _, err := t.Cloud.Autoscaling().CreateAutoScalingGroup(request); err != nil {
 			code := awsup.AWSErrorCode(err)
@@ -421,6 +461,12 @@ func (v *AutoscalingGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Autos
 		if e.InstanceProtection != nil {
 			request.NewInstancesProtectedFromScaleIn = e.InstanceProtection
 		}
+
+		if fi.BoolValue(e.WarmPoolEnabled) {
+			if err := e.putWarmPool(t); err != nil {
+				return err
+			}
+		}
 	} else {
 		// @logic: else we have found a autoscaling group and we need to evaluate the difference
 		request := &autoscaling.UpdateAutoScalingGroupInput{
@@ -598,6 +644,12 @@ func (v *AutoscalingGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Autos
 			changes.InstanceProtection = nil
 		}
 
+		warmPoolChanged := changes.WarmPoolEnabled != nil || changes.WarmPoolMinSize != nil || changes.WarmPoolMaxPreparedCapacity != nil || changes.WarmPoolState != nil
+		changes.WarmPoolEnabled = nil
+		changes.WarmPoolMinSize = nil
+		changes.WarmPoolMaxPreparedCapacity = nil
+		changes.WarmPoolState = nil
+
 		empty := &AutoscalingGroup{}
 		if !reflect.DeepEqual(empty, changes) {
 			klog.Warningf("cannot apply changes to AutoScalingGroup: %v", changes)
@@ -640,6 +692,83 @@ func (v *AutoscalingGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Autos
 				return fmt.Errorf("error attaching TargetGroups: %v", err)
 			}
 		}
+
+		if warmPoolChanged {
+			if fi.BoolValue(e.WarmPoolEnabled) {
+				hook := warmPoolLifecycleHook()
+				if _, err := t.Cloud.Autoscaling().PutLifecycleHook(&autoscaling.PutLifecycleHookInput{
+					AutoScalingGroupName: e.Name,
+					DefaultResult:        hook.DefaultResult,
+					HeartbeatTimeout:     hook.HeartbeatTimeout,
+					LifecycleHookName:    hook.LifecycleHookName,
+					LifecycleTransition:  hook.LifecycleTransition,
+				}); err != nil {
+					return fmt.Errorf("error creating warm pool lifecycle hook for AutoscalingGroup: %v", err)
+				}
+				if err := e.putWarmPool(t); err != nil {
+					return err
+				}
+			} else if fi.BoolValue(a.WarmPoolEnabled) {
+				if err := e.deleteWarmPool(t); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// warmPoolLifecycleHook returns the lifecycle hook holding new instances of an autoscaling group with a warm pool
+// until nodeup has configured them
+func warmPoolLifecycleHook() *autoscaling.LifecycleHookSpecification {
+	return &autoscaling.LifecycleHookSpecification{
+		DefaultResult:       aws.String("CONTINUE"),
+		HeartbeatTimeout:    aws.Int64(warmPoolLifecycleHookHeartbeatTimeout),
+		LifecycleHookName:   aws.String(awsup.WarmPoolLifecycleHookName),
+		LifecycleTransition: aws.String("autoscaling:EC2_INSTANCE_LAUNCHING"),
+	}
+}
+
+// putWarmPool creates or updates the warm pool of the autoscaling group
+func (e *AutoscalingGroup) putWarmPool(t *awsup.AWSAPITarget) error {
+	klog.V(2).Infof("Updating warm pool of autoscaling group %s", fi.StringValue(e.Name))
+
+	request := &autoscaling.PutWarmPoolInput{
+		AutoScalingGroupName:     e.Name,
+		MaxGroupPreparedCapacity: e.WarmPoolMaxPreparedCapacity,
+		MinSize:                  e.WarmPoolMinSize,
+		PoolState:                e.WarmPoolState,
+	}
+	if request.MaxGroupPreparedCapacity == nil {
+		// Resets the max prepared capacity to the max size of the group
+		request.MaxGroupPreparedCapacity = aws.Int64(-1)
+	}
+	if _, err := t.Cloud.Autoscaling().PutWarmPool(request); err != nil {
+		return fmt.Errorf("error updating warm pool for AutoscalingGroup: %v", err)
+	}
+
+	return nil
+}
+
+// deleteWarmPool deletes the warm pool of the autoscaling group and its lifecycle hook
+func (e *AutoscalingGroup) deleteWarmPool(t *awsup.AWSAPITarget) error {
+	klog.V(2).Infof("Deleting warm pool of autoscaling group %s", fi.StringValue(e.Name))
+
+	if _, err := t.Cloud.Autoscaling().DeleteWarmPool(&autoscaling.DeleteWarmPoolInput{
+		AutoScalingGroupName: e.Name,
+		ForceDelete:          aws.Bool(true),
+	}); err != nil {
+		return fmt.Errorf("error deleting warm pool for AutoscalingGroup: %v", err)
+	}
+
+	if _, err := t.Cloud.Autoscaling().DeleteLifecycleHook(&autoscaling.DeleteLifecycleHookInput{
+		AutoScalingGroupName: e.Name,
+		LifecycleHookName:    aws.String(awsup.WarmPoolLifecycleHookName),
+	}); err != nil {
+		if awsup.AWSErrorCode(err) != "ValidationError" {
+			return fmt.Errorf("error deleting warm pool lifecycle hook for AutoscalingGroup: %v", err)
+		}
 	}
 
 	return nil
@@ -865,6 +994,25 @@ type terraformAutoscalingGroup struct {
 	InstanceProtection      *bool                                            `json:"protect_from_scale_in,omitempty" cty:"protect_from_scale_in"`
 	LoadBalancers           []*terraform.Literal                             `json:"load_balancers,omitempty" cty:"load_balancers"`
 	TargetGroupARNs         []*terraform.Literal                             `json:"target_group_arns,omitempty" cty:"target_group_arns"`
+
+	WarmPool              *terraformWarmPool                          `json:"warm_pool,omitempty" cty:"warm_pool"`
+	InitialLifecycleHooks []*terraformAutoscalingInitialLifecycleHook `json:"initial_lifecycle_hook,omitempty" cty:"initial_lifecycle_hook"`
+}
+
+type terraformWarmPool struct {
+	// PoolState is the state of the instances in the warm pool
+	PoolState *string `json:"pool_state,omitempty" cty:"pool_state"`
+	// MinSize is the minimum number of instances to maintain in the warm pool
+	MinSize *int64 `json:"min_size,omitempty" cty:"min_size"`
+	// MaxGroupPreparedCapacity is the maximum number of instances allowed in the warm pool and the autoscaling group
+	MaxGroupPreparedCapacity *int64 `json:"max_group_prepared_capacity,omitempty" cty:"max_group_prepared_capacity"`
+}
+
+type terraformAutoscalingInitialLifecycleHook struct {
+	Name                *string `json:"name,omitempty" cty:"name"`
+	DefaultResult       *string `json:"default_result,omitempty" cty:"default_result"`
+	HeartbeatTimeout    *int64  `json:"heartbeat_timeout,omitempty" cty:"heartbeat_timeout"`
+	LifecycleTransition *string `json:"lifecycle_transition,omitempty" cty:"lifecycle_transition"`
 }
 
 // RenderTerraform is responsible for rendering the terraform codebase
@@ -944,15 +1092,9 @@ func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 		return fmt.Errorf("could not find one of launch configuration, mixed instances policy, or launch template")
 	}
 
-	role := ""
-	for k := range e.Tags {
-		if strings.HasPrefix(k, CloudTagInstanceGroupRolePrefix) {
-			suffix := strings.TrimPrefix(k, CloudTagInstanceGroupRolePrefix)
-			if role != "" && role != suffix {
-				return fmt.Errorf("Found multiple role tags: %q vs %q", role, suffix)
-			}
-			role = suffix
-		}
+	role, err := e.instanceGroupRole()
+	if err != nil {
+		return err
 	}
 
 	if e.LaunchTemplate != nil && role != "" {
@@ -983,9 +1125,37 @@ func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 	}
 	tf.SuspendedProcesses = processes
 
+	if ig := e.Tags[kops.NodeLabelInstanceGroup]; ig != "" {
+		t.AddInputVariable(ig+"_min_size", "Minimum number of instances in instance group "+ig, "aws_autoscaling_group", *e.Name, "min_size")
+		t.AddInputVariable(ig+"_max_size", "Maximum number of instances in instance group "+ig, "aws_autoscaling_group", *e.Name, "max_size")
+	}
+
+	if fi.BoolValue(e.WarmPoolEnabled) {
+		tf.WarmPool = &terraformWarmPool{
+			PoolState:                e.WarmPoolState,
+			MinSize:                  e.WarmPoolMinSize,
+			MaxGroupPreparedCapacity: e.WarmPoolMaxPreparedCapacity,
+		}
+
+		// The hook is created with the group, so that the first warm instances wait for nodeup
+		hook := warmPoolLifecycleHook()
+		tf.InitialLifecycleHooks = append(tf.InitialLifecycleHooks, &terraformAutoscalingInitialLifecycleHook{
+			Name:                hook.LifecycleHookName,
+			DefaultResult:       hook.DefaultResult,
+			HeartbeatTimeout:    hook.HeartbeatTimeout,
+			LifecycleTransition: hook.LifecycleTransition,
+		})
+	}
+
 	return t.RenderResource("aws_autoscaling_group", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *AutoscalingGroup) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*AutoscalingGroup)
+	return []*terraform.Import{{ResourceType: "aws_autoscaling_group", ResourceName: *e.Name, ID: fi.StringValue(a.Name)}}
+}
+
 // TerraformLink fills in the property
 func (e *AutoscalingGroup) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_autoscaling_group", fi.StringValue(e.Name), "id")
@@ -1055,6 +1225,21 @@ type cloudformationAutoscalingGroup struct {
 	MixedInstancesPolicy    *cloudformationMixedInstancesPolicy                   `json:"MixedInstancesPolicy,omitempty"`
 	LoadBalancerNames       []*cloudformation.Literal                             `json:"LoadBalancerNames,omitempty"`
 	TargetGroupARNs         []*cloudformation.Literal                             `json:"TargetGroupARNs,omitempty"`
+	LifecycleHooks          []*cloudformationLifecycleHookSpecification           `json:"LifecycleHookSpecificationList,omitempty"`
+}
+
+type cloudformationWarmPool struct {
+	AutoScalingGroupName     *cloudformation.Literal `json:"AutoScalingGroupName,omitempty"`
+	MaxGroupPreparedCapacity *int64                  `json:"MaxGroupPreparedCapacity,omitempty"`
+	MinSize                  *int64                  `json:"MinSize,omitempty"`
+	PoolState                *string                 `json:"PoolState,omitempty"`
+}
+
+type cloudformationLifecycleHookSpecification struct {
+	DefaultResult       *string `json:"DefaultResult,omitempty"`
+	HeartbeatTimeout    *int64  `json:"HeartbeatTimeout,omitempty"`
+	LifecycleHookName   *string `json:"LifecycleHookName,omitempty"`
+	LifecycleTransition *string `json:"LifecycleTransition,omitempty"`
 }
 
 // RenderCloudformation is responsible for generating the cloudformation template
@@ -1122,9 +1307,78 @@ func (_ *AutoscalingGroup) RenderCloudformation(t *cloudformation.Cloudformation
 		cf.TargetGroupARNs = append(cf.TargetGroupARNs, tg.CloudformationLink())
 	}
 
+	role, err := e.instanceGroupRole()
+	if err != nil {
+		return err
+	}
+
+	if e.LaunchTemplate != nil && role != "" {
+		for _, sg := range e.LaunchTemplate.SecurityGroups {
+			if err := t.AddOutputVariableArray(role+"_security_group_ids", sg.CloudformationLink()); err != nil {
+				return err
+			}
+		}
+	}
+	if role != "" {
+		if err := t.AddOutputVariableArray(role+"_autoscaling_group_ids", e.CloudformationLink()); err != nil {
+			return err
+		}
+	}
+	if role == "node" {
+		for _, s := range e.Subnets {
+			if err := t.AddOutputVariableArray(role+"_subnet_ids", s.CloudformationLink()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if ig := e.Tags[kops.NodeLabelInstanceGroup]; ig != "" {
+		t.AssignNestedStack(ig, "AWS::AutoScaling::AutoScalingGroup", fi.StringValue(e.Name))
+	}
+
+	if fi.BoolValue(e.WarmPoolEnabled) {
+		warmPool := &cloudformationWarmPool{
+			AutoScalingGroupName:     e.CloudformationLink(),
+			MaxGroupPreparedCapacity: e.WarmPoolMaxPreparedCapacity,
+			MinSize:                  e.WarmPoolMinSize,
+			PoolState:                e.WarmPoolState,
+		}
+		if err := t.RenderResource("AWS::AutoScaling::WarmPool", fi.StringValue(e.Name), warmPool); err != nil {
+			return err
+		}
+
+		// The hook is created with the group, so that the first warm instances wait for nodeup
+		hook := warmPoolLifecycleHook()
+		cf.LifecycleHooks = append(cf.LifecycleHooks, &cloudformationLifecycleHookSpecification{
+			DefaultResult:       hook.DefaultResult,
+			HeartbeatTimeout:    hook.HeartbeatTimeout,
+			LifecycleHookName:   hook.LifecycleHookName,
+			LifecycleTransition: hook.LifecycleTransition,
+		})
+
+		if ig := e.Tags[kops.NodeLabelInstanceGroup]; ig != "" {
+			t.AssignNestedStack(ig, "AWS::AutoScaling::WarmPool", fi.StringValue(e.Name))
+		}
+	}
+
 	return t.RenderResource("AWS::AutoScaling::AutoScalingGroup", fi.StringValue(e.Name), cf)
 }
 
+// instanceGroupRole returns the role of the instance group, from the role tag of the autoscaling group
+func (e *AutoscalingGroup) instanceGroupRole() (string, error) {
+	role := ""
+	for k := range e.Tags {
+		if strings.HasPrefix(k, CloudTagInstanceGroupRolePrefix) {
+			suffix := strings.TrimPrefix(k, CloudTagInstanceGroupRolePrefix)
+			if role != "" && role != suffix {
+				return "", fmt.Errorf("Found multiple role tags: %q vs %q", role, suffix)
+			}
+			role = suffix
+		}
+	}
+	return role, nil
+}
+
 // CloudformationLink is adds a reference
 func (e *AutoscalingGroup) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::AutoScaling::AutoScalingGroup", fi.StringValue(e.Name))
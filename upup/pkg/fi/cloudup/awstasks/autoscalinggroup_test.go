@@ -20,10 +20,13 @@ import (
 	"sort"
 	"testing"
 
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
 	"k8s.io/kops/pkg/diff"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"sigs.k8s.io/yaml"
 )
@@ -250,7 +253,7 @@ terraform {
   required_providers {
     aws = {
       "source"  = "hashicorp/aws"
-      "version" = ">= 2.46.0"
+      "version" = ">= 3.39.0"
     }
   }
 }
@@ -324,7 +327,54 @@ terraform {
   required_providers {
     aws = {
       "source"  = "hashicorp/aws"
-      "version" = ">= 2.46.0"
+      "version" = ">= 3.39.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &AutoscalingGroup{
+				Name:                        fi.String("test2"),
+				LaunchTemplate:              &LaunchTemplate{Name: fi.String("test_lt")},
+				MaxSize:                     fi.Int64(10),
+				MinSize:                     fi.Int64(1),
+				WarmPoolEnabled:             fi.Bool(true),
+				WarmPoolMinSize:             fi.Int64(2),
+				WarmPoolMaxPreparedCapacity: fi.Int64(5),
+				WarmPoolState:               fi.String("Stopped"),
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_autoscaling_group" "test2" {
+  initial_lifecycle_hook {
+    default_result       = "CONTINUE"
+    heartbeat_timeout    = 600
+    lifecycle_transition = "autoscaling:EC2_INSTANCE_LAUNCHING"
+    name                 = "kops-warmpool"
+  }
+  launch_template {
+    id      = aws_launch_template.test_lt.id
+    version = aws_launch_template.test_lt.latest_version
+  }
+  max_size = 10
+  min_size = 1
+  name     = "test2"
+  warm_pool {
+    max_group_prepared_capacity = 5
+    min_size                    = 2
+    pool_state                  = "Stopped"
+  }
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.39.0"
     }
   }
 }
@@ -421,9 +471,112 @@ func TestAutoscalingGroupCloudformationRender(t *testing.T) {
       }
     }
   }
+}`,
+		},
+		{
+			Resource: &AutoscalingGroup{
+				Name:                        fi.String("test2"),
+				LaunchTemplate:              &LaunchTemplate{Name: fi.String("test_lt")},
+				MaxSize:                     fi.Int64(10),
+				MinSize:                     fi.Int64(1),
+				WarmPoolEnabled:             fi.Bool(true),
+				WarmPoolMinSize:             fi.Int64(2),
+				WarmPoolMaxPreparedCapacity: fi.Int64(5),
+				WarmPoolState:               fi.String("Stopped"),
+			},
+			Expected: `{
+  "Resources": {
+    "AWSAutoScalingAutoScalingGrouptest2": {
+      "Type": "AWS::AutoScaling::AutoScalingGroup",
+      "Properties": {
+        "AutoScalingGroupName": "test2",
+        "LaunchTemplate": {
+          "LaunchTemplateId": {
+            "Ref": "AWSEC2LaunchTemplatetest_lt"
+          },
+          "Version": {
+            "Fn::GetAtt": [
+              "AWSEC2LaunchTemplatetest_lt",
+              "LatestVersionNumber"
+            ]
+          }
+        },
+        "MaxSize": "10",
+        "MinSize": "1",
+        "MetricsCollection": [
+          {
+            "Granularity": null,
+            "Metrics": []
+          }
+        ],
+        "LifecycleHookSpecificationList": [
+          {
+            "DefaultResult": "CONTINUE",
+            "HeartbeatTimeout": 600,
+            "LifecycleHookName": "kops-warmpool",
+            "LifecycleTransition": "autoscaling:EC2_INSTANCE_LAUNCHING"
+          }
+        ]
+      }
+    },
+    "AWSAutoScalingWarmPooltest2": {
+      "Type": "AWS::AutoScaling::WarmPool",
+      "Properties": {
+        "AutoScalingGroupName": {
+          "Ref": "AWSAutoScalingAutoScalingGrouptest2"
+        },
+        "MaxGroupPreparedCapacity": 5,
+        "MinSize": 2,
+        "PoolState": "Stopped"
+      }
+    }
+  }
 }`,
 		},
 	}
 
 	doRenderTests(t, "RenderCloudformation", cases)
 }
+
+// warmPoolAccessDenied is an autoscaling API whose caller may not describe warm pools
+type warmPoolAccessDenied struct {
+	*mockautoscaling.MockAutoscaling
+}
+
+func (m *warmPoolAccessDenied) DescribeWarmPool(*autoscaling.DescribeWarmPoolInput) (*autoscaling.DescribeWarmPoolOutput, error) {
+	return nil, awserr.New("AccessDenied", "not authorized to perform: autoscaling:DescribeWarmPool", nil)
+}
+
+func TestAutoscalingGroupFindWarmPoolAccessDenied(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	cloud.MockAutoscaling = &warmPoolAccessDenied{MockAutoscaling: &mockautoscaling.MockAutoscaling{}}
+
+	_, err := cloud.Autoscaling().CreateAutoScalingGroup(&autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("nodes"),
+		MaxSize:              aws.Int64(2),
+		MinSize:              aws.Int64(1),
+	})
+	if err != nil {
+		t.Fatalf("error creating AutoScalingGroup: %v", err)
+	}
+
+	c := &fi.Context{Cloud: cloud}
+
+	{
+		e := &AutoscalingGroup{Name: fi.String("nodes")}
+		actual, err := e.Find(c)
+		if err != nil {
+			t.Fatalf("unexpected error finding AutoScalingGroup without a warm pool: %v", err)
+		}
+		if actual == nil || fi.BoolValue(actual.WarmPoolEnabled) {
+			t.Errorf("expected AutoScalingGroup without a warm pool, got %+v", actual)
+		}
+	}
+
+	{
+		e := &AutoscalingGroup{Name: fi.String("nodes"), WarmPoolEnabled: fi.Bool(true)}
+		if _, err := e.Find(c); err == nil {
+			t.Errorf("expected error finding AutoScalingGroup with a warm pool")
+		}
+	}
+}
@@ -827,6 +827,12 @@ func (_ *ClassicLoadBalancer) RenderTerraform(t *terraform.TerraformTarget, a, e
 	return t.RenderResource("aws_elb", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *ClassicLoadBalancer) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*ClassicLoadBalancer)
+	return []*terraform.Import{{ResourceType: "aws_elb", ResourceName: *e.Name, ID: fi.StringValue(a.LoadBalancerName)}}
+}
+
 func (e *ClassicLoadBalancer) TerraformLink(params ...string) *terraform.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
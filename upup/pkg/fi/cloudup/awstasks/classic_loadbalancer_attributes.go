@@ -120,6 +120,8 @@ func (_ *ClassicLoadBalancer) modifyLoadBalancerAttributes(t *awsup.AWSAPITarget
 	request.LoadBalancerAttributes.AccessLog = &elb.AccessLog{}
 	if e.AccessLog == nil || e.AccessLog.Enabled == nil {
 		request.LoadBalancerAttributes.AccessLog.Enabled = fi.Bool(false)
+	} else {
+		request.LoadBalancerAttributes.AccessLog.Enabled = e.AccessLog.Enabled
 	}
 	request.LoadBalancerAttributes.ConnectionDraining = &elb.ConnectionDraining{}
 	if e.ConnectionDraining == nil || e.ConnectionDraining.Enabled == nil {
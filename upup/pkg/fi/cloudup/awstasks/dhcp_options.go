@@ -189,6 +189,12 @@ func (_ *DHCPOptions) RenderTerraform(t *terraform.TerraformTarget, a, e, change
 	return t.RenderResource("aws_vpc_dhcp_options", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *DHCPOptions) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*DHCPOptions)
+	return []*terraform.Import{{ResourceType: "aws_vpc_dhcp_options", ResourceName: *e.Name, ID: fi.StringValue(a.ID)}}
+}
+
 func (e *DHCPOptions) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_vpc_dhcp_options", *e.Name, "id")
 }
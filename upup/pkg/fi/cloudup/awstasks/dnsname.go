@@ -318,6 +318,13 @@ func (_ *DNSName) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *D
 	return t.RenderResource("aws_route53_record", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *DNSName) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*DNSName)
+	zoneID := strings.TrimPrefix(fi.StringValue(a.Zone.ZoneID), "/hostedzone/")
+	return []*terraform.Import{{ResourceType: "aws_route53_record", ResourceName: *e.Name, ID: zoneID + "_" + fi.StringValue(a.Name) + "_" + fi.StringValue(a.ResourceType)}}
+}
+
 func (e *DNSName) TerraformLink() *terraform.Literal {
 	return terraform.LiteralSelfLink("aws_route53_record", *e.Name)
 }
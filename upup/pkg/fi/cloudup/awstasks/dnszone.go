@@ -290,6 +290,17 @@ func (_ *DNSZone) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *D
 	return fmt.Errorf("Creation of Route53 hosted zones is not supported for terraform")
 }
 
+// TerraformImport implements terraform.Importable
+func (e *DNSZone) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*DNSZone)
+	zoneID := fi.StringValue(a.ZoneID)
+	imports := []*terraform.Import{{ResourceType: "aws_route53_zone", ResourceName: *e.Name, ID: zoneID}}
+	if a.PrivateVPC != nil && a.PrivateVPC.ID != nil {
+		imports = append(imports, &terraform.Import{ResourceType: "aws_route53_zone_association", ResourceName: *e.Name, ID: zoneID + ":" + *a.PrivateVPC.ID})
+	}
+	return imports
+}
+
 func (e *DNSZone) TerraformLink() *terraform.Literal {
 	if e.ZoneID != nil {
 		klog.V(4).Infof("reusing existing route53 zone with id %q", *e.ZoneID)
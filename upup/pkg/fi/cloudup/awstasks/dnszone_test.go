@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+)
+
+func TestDNSZoneTerraformImport(t *testing.T) {
+	e := &DNSZone{Name: fi.String("example-com")}
+
+	grid := []struct {
+		actual   *DNSZone
+		expected []*terraform.Import
+	}{
+		{
+			actual: &DNSZone{ZoneID: fi.String("Z1AFAKE1ZON3YO")},
+			expected: []*terraform.Import{
+				{ResourceType: "aws_route53_zone", ResourceName: "example-com", ID: "Z1AFAKE1ZON3YO"},
+			},
+		},
+		{
+			// A private zone is also associated with the cluster VPC
+			actual: &DNSZone{ZoneID: fi.String("Z1AFAKE1ZON3YO"), PrivateVPC: &VPC{ID: fi.String("vpc-12345678")}},
+			expected: []*terraform.Import{
+				{ResourceType: "aws_route53_zone", ResourceName: "example-com", ID: "Z1AFAKE1ZON3YO"},
+				{ResourceType: "aws_route53_zone_association", ResourceName: "example-com", ID: "Z1AFAKE1ZON3YO:vpc-12345678"},
+			},
+		},
+	}
+
+	for i, g := range grid {
+		actual := e.TerraformImport(g.actual)
+		if !reflect.DeepEqual(actual, g.expected) {
+			t.Errorf("case %d: expected %+v, got %+v", i, g.expected, actual)
+		}
+	}
+}
@@ -243,6 +243,13 @@ func (_ *EBSVolume) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_ebs_volume", tfName, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *EBSVolume) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*EBSVolume)
+	tfName, _ := e.TerraformName()
+	return []*terraform.Import{{ResourceType: "aws_ebs_volume", ResourceName: tfName, ID: fi.StringValue(a.ID)}}
+}
+
 func (e *EBSVolume) TerraformLink() *terraform.Literal {
 	tfName, _ := e.TerraformName()
 	return terraform.LiteralSelfLink("aws_ebs_volume", tfName)
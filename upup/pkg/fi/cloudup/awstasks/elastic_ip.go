@@ -291,6 +291,12 @@ func (_ *ElasticIP) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_eip", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *ElasticIP) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*ElasticIP)
+	return []*terraform.Import{{ResourceType: "aws_eip", ResourceName: *e.Name, ID: fi.StringValue(a.ID)}}
+}
+
 func (e *ElasticIP) TerraformLink() *terraform.Literal {
 	if fi.BoolValue(e.Shared) {
 		if e.ID == nil {
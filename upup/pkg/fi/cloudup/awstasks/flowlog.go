@@ -0,0 +1,277 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+)
+
+// FlowLog is a flow log of a VPC, published either to a CloudWatch Logs log group or to an S3 bucket
+// +kops:fitask
+type FlowLog struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	// ID is the ID of the flow log
+	ID *string
+	// VPC is the VPC of the flow log
+	VPC *VPC
+	// TrafficType is the type of traffic to log: ACCEPT, REJECT or ALL
+	TrafficType *string
+	// LogDestinationType is where the flow logs are published: cloud-watch-logs or s3
+	LogDestinationType *string
+	// LogDestination is the ARN of the S3 bucket, and optional prefix, of the s3 destination
+	LogDestination *string
+	// LogGroup is the log group of the cloud-watch-logs destination
+	LogGroup *LogGroup
+	// IAMRole is the role allowing the flow log to publish to the log group of the cloud-watch-logs destination
+	IAMRole *IAMRole
+
+	Tags map[string]string
+}
+
+var _ fi.CompareWithID = &FlowLog{}
+
+func (e *FlowLog) CompareWithID() *string {
+	return e.ID
+}
+
+// Find discovers the flow log in the cloud provider
+func (e *FlowLog) Find(c *fi.Context) (*FlowLog, error) {
+	cloud := c.Cloud.(awsup.AWSCloud)
+
+	request := &ec2.DescribeFlowLogsInput{}
+	if e.ID != nil {
+		request.FlowLogIds = []*string{e.ID}
+	} else {
+		if e.VPC == nil || e.VPC.ID == nil {
+			klog.V(4).Infof("VPC ID not set, so FlowLog %q can't exist yet", fi.StringValue(e.Name))
+			return nil, nil
+		}
+		request.Filter = cloud.BuildFilters(e.Name)
+		request.Filter = append(request.Filter, awsup.NewEC2Filter("resource-id", fi.StringValue(e.VPC.ID)))
+	}
+
+	response, err := cloud.EC2().DescribeFlowLogs(request)
+	if err != nil {
+		return nil, fmt.Errorf("error listing FlowLogs: %v", err)
+	}
+	if response == nil || len(response.FlowLogs) == 0 {
+		return nil, nil
+	}
+	if len(response.FlowLogs) != 1 {
+		return nil, fmt.Errorf("found multiple FlowLogs with name %q", fi.StringValue(e.Name))
+	}
+
+	flowLog := response.FlowLogs[0]
+	actual := &FlowLog{
+		ID:                 flowLog.FlowLogId,
+		Name:               e.Name,
+		VPC:                &VPC{ID: flowLog.ResourceId},
+		TrafficType:        flowLog.TrafficType,
+		LogDestinationType: flowLog.LogDestinationType,
+		Tags:               mapEC2TagsToMap(flowLog.Tags),
+	}
+	switch aws.StringValue(flowLog.LogDestinationType) {
+	case ec2.LogDestinationTypeS3:
+		actual.LogDestination = flowLog.LogDestination
+	case ec2.LogDestinationTypeCloudWatchLogs:
+		actual.LogGroup = &LogGroup{Name: flowLog.LogGroupName}
+		if roleARN := aws.StringValue(flowLog.DeliverLogsPermissionArn); roleARN != "" {
+			actual.IAMRole = &IAMRole{Name: aws.String(roleARN[strings.LastIndex(roleARN, "/")+1:])}
+			if e.IAMRole != nil && fi.StringValue(e.IAMRole.Name) == fi.StringValue(actual.IAMRole.Name) {
+				actual.IAMRole = e.IAMRole
+			}
+		}
+	}
+
+	klog.V(2).Infof("found matching FlowLog %q", fi.StringValue(actual.ID))
+	e.ID = actual.ID
+
+	// Avoid spurious changes
+	actual.Lifecycle = e.Lifecycle
+
+	return actual, nil
+}
+
+// Run is responsible for running the task
+func (e *FlowLog) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+// CheckChanges validates the changes to the flow log
+func (s *FlowLog) CheckChanges(a, e, changes *FlowLog) error {
+	if a == nil {
+		if e.VPC == nil {
+			return fi.RequiredField("VPC")
+		}
+		if e.TrafficType == nil {
+			return fi.RequiredField("TrafficType")
+		}
+		if e.LogDestinationType == nil {
+			return fi.RequiredField("LogDestinationType")
+		}
+	}
+	if a != nil {
+		if changes.VPC != nil {
+			return fi.CannotChangeField("VPC")
+		}
+	}
+	return nil
+}
+
+// RenderAWS creates the flow log, replacing it when its configuration changes, as flow logs can't be modified
+func (_ *FlowLog) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *FlowLog) error {
+	if a != nil && (changes.TrafficType != nil || changes.LogDestinationType != nil || changes.LogDestination != nil || changes.LogGroup != nil || changes.IAMRole != nil) {
+		klog.V(2).Infof("Replacing FlowLog %q", fi.StringValue(a.ID))
+
+		request := &ec2.DeleteFlowLogsInput{
+			FlowLogIds: []*string{a.ID},
+		}
+		if _, err := t.Cloud.EC2().DeleteFlowLogs(request); err != nil {
+			return fmt.Errorf("error deleting FlowLog %q: %v", fi.StringValue(a.ID), err)
+		}
+		a = nil
+	}
+
+	if a == nil {
+		klog.V(2).Infof("Creating FlowLog %q", fi.StringValue(e.Name))
+
+		request := &ec2.CreateFlowLogsInput{
+			ResourceIds:        []*string{e.VPC.ID},
+			ResourceType:       aws.String(ec2.FlowLogsResourceTypeVpc),
+			TrafficType:        e.TrafficType,
+			LogDestinationType: e.LogDestinationType,
+			LogDestination:     e.LogDestination,
+			TagSpecifications:  awsup.EC2TagSpecification(ec2.ResourceTypeVpcFlowLog, e.Tags),
+		}
+		if e.LogGroup != nil {
+			request.LogGroupName = e.LogGroup.Name
+		}
+		if e.IAMRole != nil {
+			response, err := t.Cloud.IAM().GetRole(&iam.GetRoleInput{RoleName: e.IAMRole.Name})
+			if err != nil {
+				return fmt.Errorf("error getting IAMRole %q of FlowLog: %v", fi.StringValue(e.IAMRole.Name), err)
+			}
+			request.DeliverLogsPermissionArn = response.Role.Arn
+		}
+
+		response, err := t.Cloud.EC2().CreateFlowLogs(request)
+		if err != nil {
+			return fmt.Errorf("error creating FlowLog: %v", err)
+		}
+		for _, item := range response.Unsuccessful {
+			if item.Error != nil {
+				return fmt.Errorf("error creating FlowLog: %s", aws.StringValue(item.Error.Message))
+			}
+		}
+		if len(response.FlowLogIds) != 1 {
+			return fmt.Errorf("expected a single FlowLog to be created, got %d", len(response.FlowLogIds))
+		}
+
+		e.ID = response.FlowLogIds[0]
+		return nil
+	}
+
+	return t.AddAWSTags(fi.StringValue(a.ID), e.Tags)
+}
+
+type terraformFlowLog struct {
+	VPCID              *terraform.Literal `json:"vpc_id" cty:"vpc_id"`
+	TrafficType        *string            `json:"traffic_type" cty:"traffic_type"`
+	LogDestinationType *string            `json:"log_destination_type" cty:"log_destination_type"`
+	LogDestination     *terraform.Literal `json:"log_destination,omitempty" cty:"log_destination"`
+	IAMRoleARN         *terraform.Literal `json:"iam_role_arn,omitempty" cty:"iam_role_arn"`
+	Tags               map[string]string  `json:"tags,omitempty" cty:"tags"`
+}
+
+// RenderTerraform renders the flow log as an aws_flow_log
+func (_ *FlowLog) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *FlowLog) error {
+	tf := &terraformFlowLog{
+		VPCID:              e.VPC.TerraformLink(),
+		TrafficType:        e.TrafficType,
+		LogDestinationType: e.LogDestinationType,
+		Tags:               e.Tags,
+	}
+	if e.LogDestination != nil {
+		tf.LogDestination = terraform.LiteralFromStringValue(fi.StringValue(e.LogDestination))
+	}
+	if e.LogGroup != nil {
+		tf.LogDestination = e.LogGroup.TerraformLink()
+	}
+	if e.IAMRole != nil {
+		tf.IAMRoleARN = terraform.LiteralProperty("aws_iam_role", fi.StringValue(e.IAMRole.Name), "arn")
+	}
+
+	return t.RenderResource("aws_flow_log", fi.StringValue(e.Name), tf)
+}
+
+// TerraformImport implements terraform.Importable
+func (e *FlowLog) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*FlowLog)
+	return []*terraform.Import{{ResourceType: "aws_flow_log", ResourceName: fi.StringValue(e.Name), ID: fi.StringValue(a.ID)}}
+}
+
+func (e *FlowLog) TerraformLink() *terraform.Literal {
+	return terraform.LiteralProperty("aws_flow_log", fi.StringValue(e.Name), "id")
+}
+
+type cloudformationFlowLog struct {
+	ResourceID               *cloudformation.Literal `json:"ResourceId"`
+	ResourceType             *string                 `json:"ResourceType"`
+	TrafficType              *string                 `json:"TrafficType"`
+	LogDestinationType       *string                 `json:"LogDestinationType"`
+	LogDestination           *string                 `json:"LogDestination,omitempty"`
+	LogGroupName             *cloudformation.Literal `json:"LogGroupName,omitempty"`
+	DeliverLogsPermissionArn *cloudformation.Literal `json:"DeliverLogsPermissionArn,omitempty"`
+	Tags                     []cloudformationTag     `json:"Tags,omitempty"`
+}
+
+// RenderCloudformation renders the flow log as an AWS::EC2::FlowLog
+func (_ *FlowLog) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *FlowLog) error {
+	cf := &cloudformationFlowLog{
+		ResourceID:         e.VPC.CloudformationLink(),
+		ResourceType:       aws.String(ec2.FlowLogsResourceTypeVpc),
+		TrafficType:        e.TrafficType,
+		LogDestinationType: e.LogDestinationType,
+		LogDestination:     e.LogDestination,
+		Tags:               buildCloudformationTags(e.Tags),
+	}
+	if e.LogGroup != nil {
+		cf.LogGroupName = e.LogGroup.CloudformationLink()
+	}
+	if e.IAMRole != nil {
+		cf.DeliverLogsPermissionArn = cloudformation.GetAtt("AWS::IAM::Role", fi.StringValue(e.IAMRole.Name), "Arn")
+	}
+
+	return t.RenderResource("AWS::EC2::FlowLog", fi.StringValue(e.Name), cf)
+}
+
+func (e *FlowLog) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::EC2::FlowLog", fi.StringValue(e.Name))
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"testing"
+
+	"k8s.io/kops/cloudmock/aws/mockcloudwatchlogs"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/cloudmock/aws/mockiam"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+func TestFlowLogCreate(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	c := &mockec2.MockEC2{}
+	cloud.MockEC2 = c
+	logs := &mockcloudwatchlogs.MockCloudWatchLogs{}
+	cloud.MockCloudWatchLogs = logs
+	cloud.MockIAM = &mockiam.MockIAM{}
+
+	// We define a function so we can rebuild the tasks, because we modify in-place when running
+	buildTasks := func() map[string]fi.Task {
+		vpc1 := &VPC{
+			Name: s("vpc1"),
+			CIDR: s("172.21.0.0/16"),
+			Tags: map[string]string{"Name": "vpc1"},
+		}
+		logGroup1 := &LogGroup{
+			Name:            s("flowlogs.example.com"),
+			RetentionInDays: fi.Int64(30),
+			Tags:            map[string]string{"Name": "flowlogs.example.com"},
+		}
+		role1 := &IAMRole{
+			Name:               s("flowlogs.example.com"),
+			RolePolicyDocument: fi.NewStringResource(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"vpc-flow-logs.amazonaws.com"},"Action":"sts:AssumeRole"}]}`),
+		}
+		flowLog1 := &FlowLog{
+			Name:               s("flowlogs.example.com"),
+			VPC:                vpc1,
+			TrafficType:        s("ALL"),
+			LogDestinationType: s("cloud-watch-logs"),
+			LogGroup:           logGroup1,
+			IAMRole:            role1,
+			Tags:               map[string]string{"Name": "flowlogs.example.com"},
+		}
+		return map[string]fi.Task{
+			"vpc1":      vpc1,
+			"logGroup1": logGroup1,
+			"role1":     role1,
+			"flowLog1":  flowLog1,
+		}
+	}
+
+	{
+		allTasks := buildTasks()
+		flowLog1 := allTasks["flowLog1"].(*FlowLog)
+
+		target := &awsup.AWSAPITarget{
+			Cloud: cloud,
+		}
+
+		context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+		if err != nil {
+			t.Fatalf("error building context: %v", err)
+		}
+		defer context.Close()
+
+		if err := context.RunTasks(testRunTasksOptions); err != nil {
+			t.Fatalf("unexpected error during Run: %v", err)
+		}
+
+		if fi.StringValue(flowLog1.ID) == "" {
+			t.Fatalf("ID not set after create")
+		}
+
+		if len(c.FlowLogs) != 1 {
+			t.Fatalf("Expected exactly one FlowLog; found %v", c.FlowLogs)
+		}
+
+		if len(logs.LogGroups) != 1 {
+			t.Fatalf("Expected exactly one LogGroup; found %v", logs.LogGroups)
+		}
+
+		actual := c.FlowLogs[fi.StringValue(flowLog1.ID)]
+		if fi.StringValue(actual.LogGroupName) != "flowlogs.example.com" {
+			t.Fatalf("Unexpected log group: %v", fi.StringValue(actual.LogGroupName))
+		}
+	}
+
+	{
+		allTasks := buildTasks()
+
+		checkNoChanges(t, cloud, allTasks)
+	}
+}
+
+func TestFlowLogTerraformRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &FlowLog{
+				Name:               fi.String("flowlogs.example.com"),
+				VPC:                &VPC{Name: fi.String("example.com")},
+				TrafficType:        fi.String("ALL"),
+				LogDestinationType: fi.String("cloud-watch-logs"),
+				LogGroup:           &LogGroup{Name: fi.String("flowlogs.example.com")},
+				IAMRole:            &IAMRole{Name: fi.String("flowlogs.example.com")},
+				Tags: map[string]string{
+					"KubernetesCluster": "example.com",
+				},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_flow_log" "flowlogs-example-com" {
+  iam_role_arn         = aws_iam_role.flowlogs-example-com.arn
+  log_destination      = aws_cloudwatch_log_group.flowlogs-example-com.arn
+  log_destination_type = "cloud-watch-logs"
+  tags = {
+    "KubernetesCluster" = "example.com"
+  }
+  traffic_type = "ALL"
+  vpc_id       = aws_vpc.example-com.id
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.39.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &LogGroup{
+				Name:            fi.String("flowlogs.example.com"),
+				RetentionInDays: fi.Int64(30),
+				Tags: map[string]string{
+					"KubernetesCluster": "example.com",
+				},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_cloudwatch_log_group" "flowlogs-example-com" {
+  name              = "flowlogs.example.com"
+  retention_in_days = 30
+  tags = {
+    "KubernetesCluster" = "example.com"
+  }
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.39.0"
+    }
+  }
+}
+`,
+		},
+	}
+	doRenderTests(t, "RenderTerraform", cases)
+}
+
+func TestFlowLogCloudformationRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &FlowLog{
+				Name:               fi.String("flowlogs.example.com"),
+				VPC:                &VPC{Name: fi.String("example.com")},
+				TrafficType:        fi.String("REJECT"),
+				LogDestinationType: fi.String("s3"),
+				LogDestination:     fi.String("arn:aws:s3:::flow-logs/example.com"),
+			},
+			Expected: `{
+  "Resources": {
+    "AWSEC2FlowLogflowlogsexamplecom": {
+      "Type": "AWS::EC2::FlowLog",
+      "Properties": {
+        "ResourceId": {
+          "Ref": "AWSEC2VPCexamplecom"
+        },
+        "ResourceType": "VPC",
+        "TrafficType": "REJECT",
+        "LogDestinationType": "s3",
+        "LogDestination": "arn:aws:s3:::flow-logs/example.com"
+      }
+    }
+  }
+}`,
+		},
+	}
+	doRenderTests(t, "RenderCloudformation", cases)
+}
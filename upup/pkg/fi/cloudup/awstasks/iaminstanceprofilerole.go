@@ -126,6 +126,12 @@ func (_ *IAMInstanceProfileRole) RenderTerraform(t *terraform.TerraformTarget, a
 	return t.RenderResource("aws_iam_instance_profile", *e.InstanceProfile.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *IAMInstanceProfileRole) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*IAMInstanceProfileRole)
+	return []*terraform.Import{{ResourceType: "aws_iam_instance_profile", ResourceName: *e.InstanceProfile.Name, ID: fi.StringValue(a.InstanceProfile.Name)}}
+}
+
 type cloudformationIAMInstanceProfile struct {
 	InstanceProfileName *string                   `json:"InstanceProfileName"`
 	Roles               []*cloudformation.Literal `json:"Roles"`
@@ -200,6 +200,12 @@ func (p *IAMOIDCProvider) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 	return t.RenderResource("aws_iam_openid_connect_provider", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *IAMOIDCProvider) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*IAMOIDCProvider)
+	return []*terraform.Import{{ResourceType: "aws_iam_openid_connect_provider", ResourceName: *e.Name, ID: fi.StringValue(a.arn)}}
+}
+
 func (e *IAMOIDCProvider) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_iam_openid_connect_provider", *e.Name, "arn")
 }
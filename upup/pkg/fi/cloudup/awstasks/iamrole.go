@@ -23,6 +23,7 @@ import (
 
 	"net/url"
 	"reflect"
+	"sort"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -48,6 +49,10 @@ type IAMRole struct {
 
 	// ExportWithId will expose the name & ARN for reuse as part of a larger system.  Only supported by terraform currently.
 	ExportWithID *string
+
+	// ExternalPolicies are the ARNs of the managed policies attached to the role.
+	// They are attached by an IAMRolePolicy task, except with cloudformation, where they are rendered on the role.
+	ExternalPolicies *[]string
 }
 
 var _ fi.CompareWithID = &IAMRole{}
@@ -119,6 +124,7 @@ func (e *IAMRole) Find(c *fi.Context) (*IAMRole, error) {
 
 	// Avoid spurious changes
 	actual.ExportWithID = e.ExportWithID
+	actual.ExternalPolicies = e.ExternalPolicies
 	actual.Lifecycle = e.Lifecycle
 
 	return actual, nil
@@ -283,6 +289,12 @@ func (_ *IAMRole) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *I
 	return t.RenderResource("aws_iam_role", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *IAMRole) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*IAMRole)
+	return []*terraform.Import{{ResourceType: "aws_iam_role", ResourceName: *e.Name, ID: fi.StringValue(a.Name)}}
+}
+
 func (e *IAMRole) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_iam_role", *e.Name, "name")
 }
@@ -291,6 +303,7 @@ type cloudformationIAMRole struct {
 	RoleName                 *string `json:"RoleName"`
 	AssumeRolePolicyDocument map[string]interface{}
 	PermissionsBoundary      *string             `json:"PermissionsBoundary,omitempty"`
+	ManagedPolicyArns        []string            `json:"ManagedPolicyArns,omitempty"`
 	Tags                     []cloudformationTag `json:"Tags,omitempty"`
 }
 
@@ -316,6 +329,21 @@ func (_ *IAMRole) RenderCloudformation(t *cloudformation.CloudformationTarget, a
 		cf.PermissionsBoundary = e.PermissionsBoundary
 	}
 
+	// CloudFormation has no resource that attaches a managed policy to a role
+	if e.ExternalPolicies != nil {
+		cf.ManagedPolicyArns = append(cf.ManagedPolicyArns, *e.ExternalPolicies...)
+		sort.Strings(cf.ManagedPolicyArns)
+	}
+
+	if fi.StringValue(e.ExportWithID) != "" {
+		if err := t.AddOutputVariable(*e.ExportWithID+"_role_arn", cloudformation.GetAtt("AWS::IAM::Role", *e.Name, "Arn")); err != nil {
+			return err
+		}
+		if err := t.AddOutputVariable(*e.ExportWithID+"_role_name", e.CloudformationLink()); err != nil {
+			return err
+		}
+	}
+
 	return t.RenderResource("AWS::IAM::Role", *e.Name, cf)
 }
 
@@ -303,11 +303,7 @@ type terraformIAMRolePolicy struct {
 func (_ *IAMRolePolicy) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *IAMRolePolicy) error {
 	if e.ExternalPolicies != nil && len(*e.ExternalPolicies) > 0 {
 		for _, policy := range *e.ExternalPolicies {
-			// create a hash of the arn
-			h := fnv.New32a()
-			h.Write([]byte(policy))
-
-			name := fmt.Sprintf("%s-%d", *e.Name, h.Sum32())
+			name := e.terraformAttachmentName(policy)
 
 			tf := &terraformIAMRolePolicy{
 				Role:      e.Role.TerraformLink(),
@@ -345,6 +341,37 @@ func (_ *IAMRolePolicy) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 	return t.RenderResource("aws_iam_role_policy", *e.Name, tf)
 }
 
+// terraformAttachmentName returns the name of the terraform resource that attaches an external policy
+func (e *IAMRolePolicy) terraformAttachmentName(policy string) string {
+	// create a hash of the arn
+	h := fnv.New32a()
+	h.Write([]byte(policy))
+
+	return fmt.Sprintf("%s-%d", *e.Name, h.Sum32())
+}
+
+// TerraformImport implements terraform.Importable
+func (e *IAMRolePolicy) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*IAMRolePolicy)
+	roleName := fi.StringValue(e.Role.Name)
+
+	if a.Managed {
+		var imports []*terraform.Import
+		if a.ExternalPolicies != nil {
+			for _, policy := range *a.ExternalPolicies {
+				imports = append(imports, &terraform.Import{
+					ResourceType: "aws_iam_role_policy_attachment",
+					ResourceName: e.terraformAttachmentName(policy),
+					ID:           roleName + "/" + policy,
+				})
+			}
+		}
+		return imports
+	}
+
+	return []*terraform.Import{{ResourceType: "aws_iam_role_policy", ResourceName: *e.Name, ID: roleName + ":" + fi.StringValue(a.Name)}}
+}
+
 func (e *IAMRolePolicy) TerraformLink() *terraform.Literal {
 	return terraform.LiteralSelfLink("aws_iam_role_policy", *e.Name)
 }
@@ -356,10 +383,9 @@ type cloudformationIAMRolePolicy struct {
 }
 
 func (_ *IAMRolePolicy) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *IAMRolePolicy) error {
-	// Currently CloudFormation does not have a reciprocal function to Terraform that allows the modification of a role
-	// after the fact. In order to make this feature complete we would have to intercept the role task and modify it.
-	if e.ExternalPolicies != nil && len(*e.ExternalPolicies) > 0 {
-		return fmt.Errorf("CloudFormation not supported for use with ExternalPolicies.")
+	// External policies are rendered in the ManagedPolicyArns of the IAMRole
+	if e.ExternalPolicies != nil {
+		return nil
 	}
 
 	policyString, err := e.policyDocumentString()
@@ -27,6 +27,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -308,3 +309,89 @@ func (e *Instance) TerraformLink() *terraform.Literal {
 
 	return terraform.LiteralSelfLink("aws_instance", *e.Name)
 }
+
+type cloudformationInstanceNetworkInterface struct {
+	AssociatePublicIPAddress *bool                     `json:"AssociatePublicIpAddress,omitempty"`
+	DeviceIndex              *string                   `json:"DeviceIndex,omitempty"`
+	SubnetID                 *cloudformation.Literal   `json:"SubnetId,omitempty"`
+	PrivateIPAddress         *string                   `json:"PrivateIpAddress,omitempty"`
+	GroupSet                 []*cloudformation.Literal `json:"GroupSet,omitempty"`
+}
+
+type cloudformationInstance struct {
+	ImageID            *string                                   `json:"ImageId,omitempty"`
+	InstanceType       *string                                   `json:"InstanceType,omitempty"`
+	KeyName            *string                                   `json:"KeyName,omitempty"`
+	IAMInstanceProfile *cloudformation.Literal                   `json:"IamInstanceProfile,omitempty"`
+	NetworkInterfaces  []*cloudformationInstanceNetworkInterface `json:"NetworkInterfaces,omitempty"`
+	UserData           *string                                   `json:"UserData,omitempty"`
+	Tags               []cloudformationTag                       `json:"Tags,omitempty"`
+}
+
+func (_ *Instance) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *Instance) error {
+	if fi.BoolValue(e.Shared) {
+		// Not cloudformation owned / managed
+		return nil
+	}
+
+	if e.ImageID == nil {
+		return fi.RequiredField("ImageID")
+	}
+	cloud := t.Cloud.(awsup.AWSCloud)
+	image, err := cloud.ResolveImage(fi.StringValue(e.ImageID))
+	if err != nil {
+		return err
+	}
+
+	cf := &cloudformationInstance{
+		ImageID:      image.ImageId,
+		InstanceType: e.InstanceType,
+		Tags:         buildCloudformationTags(e.Tags),
+	}
+
+	if e.SSHKey != nil {
+		cf.KeyName = e.SSHKey.Name
+	}
+
+	ni := &cloudformationInstanceNetworkInterface{
+		AssociatePublicIPAddress: e.AssociatePublicIP,
+		DeviceIndex:              fi.String("0"),
+		PrivateIPAddress:         e.PrivateIPAddress,
+	}
+	if e.Subnet != nil {
+		ni.SubnetID = e.Subnet.CloudformationLink()
+	}
+	for _, sg := range e.SecurityGroups {
+		ni.GroupSet = append(ni.GroupSet, sg.CloudformationLink())
+	}
+	cf.NetworkInterfaces = []*cloudformationInstanceNetworkInterface{ni}
+
+	if e.UserData != nil {
+		d, err := fi.ResourceAsBytes(e.UserData)
+		if err != nil {
+			return fmt.Errorf("error rendering Instance UserData: %v", err)
+		}
+		if len(d) > MaxUserDataSize {
+			return fmt.Errorf("Instance UserData was too large (%d bytes)", len(d))
+		}
+		cf.UserData = aws.String(base64.StdEncoding.EncodeToString(d))
+	}
+
+	if e.IAMInstanceProfile != nil {
+		cf.IAMInstanceProfile = e.IAMInstanceProfile.CloudformationLink()
+	}
+
+	return t.RenderResource("AWS::EC2::Instance", *e.Name, cf)
+}
+
+func (e *Instance) CloudformationLink() *cloudformation.Literal {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if NAT Instance is shared: %s", e)
+		}
+
+		return cloudformation.LiteralString(*e.ID)
+	}
+
+	return cloudformation.Ref("AWS::EC2::Instance", *e.Name)
+}
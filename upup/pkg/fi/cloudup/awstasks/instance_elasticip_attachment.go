@@ -23,6 +23,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 )
 
 type InstanceElasticIPAttachment struct {
@@ -96,3 +97,17 @@ func (_ *InstanceElasticIPAttachment) RenderAWS(t *awsup.AWSAPITarget, a, e, cha
 
 	return nil // no tags
 }
+
+type cloudformationEIPAssociation struct {
+	AllocationID *cloudformation.Literal `json:"AllocationId,omitempty"`
+	InstanceID   *cloudformation.Literal `json:"InstanceId,omitempty"`
+}
+
+func (_ *InstanceElasticIPAttachment) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *InstanceElasticIPAttachment) error {
+	cf := &cloudformationEIPAssociation{
+		AllocationID: e.ElasticIP.CloudformationAllocationID(),
+		InstanceID:   e.Instance.CloudformationLink(),
+	}
+
+	return t.RenderResource("AWS::EC2::EIPAssociation", fi.StringValue(e.Instance.Name)+"-"+fi.StringValue(e.ElasticIP.Name), cf)
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 )
 
 type InstanceVolumeAttachment struct {
@@ -113,3 +114,19 @@ func (_ *InstanceVolumeAttachment) RenderAWS(t *awsup.AWSAPITarget, a, e, change
 
 	return nil // no tags
 }
+
+type cloudformationVolumeAttachment struct {
+	Device     *string                 `json:"Device,omitempty"`
+	InstanceID *cloudformation.Literal `json:"InstanceId,omitempty"`
+	VolumeID   *cloudformation.Literal `json:"VolumeId,omitempty"`
+}
+
+func (_ *InstanceVolumeAttachment) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *InstanceVolumeAttachment) error {
+	cf := &cloudformationVolumeAttachment{
+		Device:     e.Device,
+		InstanceID: e.Instance.CloudformationLink(),
+		VolumeID:   e.Volume.CloudformationLink(),
+	}
+
+	return t.RenderResource("AWS::EC2::VolumeAttachment", fi.StringValue(e.Instance.Name)+"-"+fi.StringValue(e.Volume.Name), cf)
+}
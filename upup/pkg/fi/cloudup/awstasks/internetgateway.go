@@ -218,6 +218,12 @@ func (_ *InternetGateway) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 	return t.RenderResource("aws_internet_gateway", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *InternetGateway) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*InternetGateway)
+	return []*terraform.Import{{ResourceType: "aws_internet_gateway", ResourceName: *e.Name, ID: fi.StringValue(a.ID)}}
+}
+
 func (e *InternetGateway) TerraformLink() *terraform.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
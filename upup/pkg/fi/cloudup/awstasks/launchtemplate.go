@@ -39,6 +39,12 @@ type LaunchTemplate struct {
 	AssociatePublicIP *bool
 	// BlockDeviceMappings is a block device mappings
 	BlockDeviceMappings []*BlockDeviceMapping
+	// CapacityReservationID is the ID of the capacity reservation the instances are launched into
+	CapacityReservationID *string
+	// CapacityReservationPreference is the capacity reservation preference of the instances: open or none
+	CapacityReservationPreference *string
+	// CapacityReservationResourceGroupARN is the ARN of the capacity reservation resource group the instances are launched into
+	CapacityReservationResourceGroupARN *string
 	// CPUCredits is the credit option for CPU Usage on some instance types
 	CPUCredits *string
 	// HTTPPutResponseHopLimit is the desired HTTP PUT response hop limit for instance metadata requests.
@@ -56,6 +62,8 @@ type LaunchTemplate struct {
 	InstanceMonitoring *bool
 	// InstanceType is the type of instance we are using
 	InstanceType *string
+	// PlacementGroup is the placement group the instances are launched into
+	PlacementGroup *PlacementGroup
 	// RootVolumeIops is the provisioned IOPS when the volume type is io1, io2 or gp3
 	RootVolumeIops *int64
 	// RootVolumeOptimization enables EBS optimization for an instance
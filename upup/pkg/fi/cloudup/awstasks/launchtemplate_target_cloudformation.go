@@ -20,6 +20,7 @@ import (
 	"encoding/base64"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
@@ -41,13 +42,27 @@ type cloudformationLaunchTemplateMonitoring struct {
 	Enabled *bool `json:"Enabled,omitempty"`
 }
 
+type cloudformationLaunchTemplateCapacityReservationTarget struct {
+	// CapacityReservationID is the ID of the capacity reservation to launch the instances into.
+	CapacityReservationID *string `json:"CapacityReservationId,omitempty"`
+	// CapacityReservationResourceGroupARN is the ARN of the capacity reservation resource group to launch the instances into.
+	CapacityReservationResourceGroupARN *string `json:"CapacityReservationResourceGroupArn,omitempty"`
+}
+
+type cloudformationLaunchTemplateCapacityReservationSpecification struct {
+	// CapacityReservationPreference is the capacity reservation preference. Can be open or none.
+	CapacityReservationPreference *string `json:"CapacityReservationPreference,omitempty"`
+	// CapacityReservationTarget is the capacity reservation to launch the instances into.
+	CapacityReservationTarget *cloudformationLaunchTemplateCapacityReservationTarget `json:"CapacityReservationTarget,omitempty"`
+}
+
 type cloudformationLaunchTemplatePlacement struct {
 	// Affinity is he affinity setting for an instance on a Dedicated Host.
 	Affinity *string `json:"Affinity,omitempty"`
 	// AvailabilityZone is the Availability Zone for the instance.
 	AvailabilityZone *string `json:"AvailabilityZone,omitempty"`
 	// GroupName is the name of the placement group for the instance.
-	GroupName *string `json:"GroupName,omitempty"`
+	GroupName *cloudformation.Literal `json:"GroupName,omitempty"`
 	// HostID is the ID of the Dedicated Host for the instance.
 	HostID *string `json:"HostId,omitempty"`
 	// SpreadDomain are reserved for future use.
@@ -127,6 +142,8 @@ type cloudformationLaunchTemplateInstanceMetadataOptions struct {
 type cloudformationLaunchTemplateData struct {
 	// BlockDeviceMappings is the device mappings
 	BlockDeviceMappings []*cloudformationLaunchTemplateBlockDevice `json:"BlockDeviceMappings,omitempty"`
+	// CapacityReservationSpecification are the capacity reservation options
+	CapacityReservationSpecification *cloudformationLaunchTemplateCapacityReservationSpecification `json:"CapacityReservationSpecification,omitempty"`
 	// CreditSpecification is the credit option for CPU Usage on some instance types
 	CreditSpecification *cloudformationLaunchTemplateCreditSpecification `json:"CreditSpecification,omitempty"`
 	// EBSOptimized indicates if the root device is ebs optimized
@@ -233,8 +250,23 @@ func (t *LaunchTemplate) RenderCloudformation(target *cloudformation.Cloudformat
 	if e.SSHKey != nil {
 		data.KeyName = e.SSHKey.Name
 	}
-	if e.Tenancy != nil {
-		data.Placement = []*cloudformationLaunchTemplatePlacement{{Tenancy: e.Tenancy}}
+	if e.Tenancy != nil || e.PlacementGroup != nil {
+		placement := &cloudformationLaunchTemplatePlacement{Tenancy: e.Tenancy}
+		if e.PlacementGroup != nil {
+			placement.GroupName = e.PlacementGroup.CloudformationLink()
+		}
+		data.Placement = []*cloudformationLaunchTemplatePlacement{placement}
+	}
+	if e.CapacityReservationPreference != nil || e.CapacityReservationID != nil || e.CapacityReservationResourceGroupARN != nil {
+		data.CapacityReservationSpecification = &cloudformationLaunchTemplateCapacityReservationSpecification{
+			CapacityReservationPreference: e.CapacityReservationPreference,
+		}
+		if e.CapacityReservationID != nil || e.CapacityReservationResourceGroupARN != nil {
+			data.CapacityReservationSpecification.CapacityReservationTarget = &cloudformationLaunchTemplateCapacityReservationTarget{
+				CapacityReservationID:               e.CapacityReservationID,
+				CapacityReservationResourceGroupARN: e.CapacityReservationResourceGroupARN,
+			}
+		}
 	}
 	if e.InstanceMonitoring != nil {
 		data.Monitoring = &cloudformationLaunchTemplateMonitoring{
@@ -313,5 +345,9 @@ func (t *LaunchTemplate) RenderCloudformation(target *cloudformation.Cloudformat
 		})
 	}
 
+	if ig := e.Tags[kops.NodeLabelInstanceGroup]; ig != "" {
+		target.AssignNestedStack(ig, "AWS::EC2::LaunchTemplate", fi.StringValue(e.Name))
+	}
+
 	return target.RenderResource("AWS::EC2::LaunchTemplate", fi.StringValue(e.Name), cf)
 }
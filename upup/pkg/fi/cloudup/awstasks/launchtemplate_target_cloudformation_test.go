@@ -197,6 +197,50 @@ func TestLaunchTemplateCloudformationRender(t *testing.T) {
       }
     }
   }
+}`,
+		},
+		{
+			Resource: &LaunchTemplate{
+				Name:         fi.String("test"),
+				ID:           fi.String("test-11"),
+				InstanceType: fi.String("c5n.18xlarge"),
+				PlacementGroup: &PlacementGroup{
+					Name:     fi.String("test"),
+					Strategy: fi.String("cluster"),
+				},
+				CapacityReservationID: fi.String("cr-0123456789abcdef0"),
+			},
+			Expected: `{
+  "Resources": {
+    "AWSEC2LaunchTemplatetest": {
+      "Type": "AWS::EC2::LaunchTemplate",
+      "Properties": {
+        "LaunchTemplateName": "test",
+        "LaunchTemplateData": {
+          "CapacityReservationSpecification": {
+            "CapacityReservationTarget": {
+              "CapacityReservationId": "cr-0123456789abcdef0"
+            }
+          },
+          "InstanceType": "c5n.18xlarge",
+          "MetadataOptions": {},
+          "NetworkInterfaces": [
+            {
+              "DeleteOnTermination": true,
+              "DeviceIndex": 0
+            }
+          ],
+          "Placement": [
+            {
+              "GroupName": {
+                "Ref": "AWSEC2PlacementGrouptest"
+              }
+            }
+          ]
+        }
+      }
+    }
+  }
 }`,
 		},
 	}
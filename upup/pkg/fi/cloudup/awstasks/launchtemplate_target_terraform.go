@@ -19,6 +19,7 @@ package awstasks
 import (
 	"encoding/base64"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
@@ -45,7 +46,7 @@ type terraformLaunchTemplatePlacement struct {
 	// AvailabilityZone is the Availability Zone for the instance.
 	AvailabilityZone *string `json:"availability_zone,omitempty" cty:"availability_zone"`
 	// GroupName is the name of the placement group for the instance.
-	GroupName *string `json:"group_name,omitempty" cty:"group_name"`
+	GroupName *terraform.Literal `json:"group_name,omitempty" cty:"group_name"`
 	// HostID is the ID of the Dedicated Host for the instance.
 	HostID *string `json:"host_id,omitempty" cty:"host_id"`
 	// SpreadDomain are reserved for future use.
@@ -105,6 +106,20 @@ type terraformLaunchTemplateBlockDevice struct {
 	EBS []*terraformLaunchTemplateBlockDeviceEBS `json:"ebs,omitempty" cty:"ebs"`
 }
 
+type terraformLaunchTemplateCapacityReservationTarget struct {
+	// CapacityReservationID is the ID of the capacity reservation to launch the instances into.
+	CapacityReservationID *string `json:"capacity_reservation_id,omitempty" cty:"capacity_reservation_id"`
+	// CapacityReservationResourceGroupARN is the ARN of the capacity reservation resource group to launch the instances into.
+	CapacityReservationResourceGroupARN *string `json:"capacity_reservation_resource_group_arn,omitempty" cty:"capacity_reservation_resource_group_arn"`
+}
+
+type terraformLaunchTemplateCapacityReservationSpecification struct {
+	// CapacityReservationPreference is the capacity reservation preference. Can be open or none.
+	CapacityReservationPreference *string `json:"capacity_reservation_preference,omitempty" cty:"capacity_reservation_preference"`
+	// CapacityReservationTarget is the capacity reservation to launch the instances into.
+	CapacityReservationTarget []*terraformLaunchTemplateCapacityReservationTarget `json:"capacity_reservation_target,omitempty" cty:"capacity_reservation_target"`
+}
+
 type terraformLaunchTemplateCreditSpecification struct {
 	CPUCredits *string `json:"cpu_credits,omitempty" cty:"cpu_credits"`
 }
@@ -133,6 +148,8 @@ type terraformLaunchTemplate struct {
 
 	// BlockDeviceMappings is the device mappings
 	BlockDeviceMappings []*terraformLaunchTemplateBlockDevice `json:"block_device_mappings,omitempty" cty:"block_device_mappings"`
+	// CapacityReservationSpecification are the capacity reservation options
+	CapacityReservationSpecification []*terraformLaunchTemplateCapacityReservationSpecification `json:"capacity_reservation_specification,omitempty" cty:"capacity_reservation_specification"`
 	// CreditSpecification is the credit option for CPU Usage on some instance types
 	CreditSpecification *terraformLaunchTemplateCreditSpecification `json:"credit_specification,omitempty" cty:"credit_specification"`
 	// EBSOptimized indicates if the root device is ebs optimized
@@ -234,8 +251,26 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 	if e.SSHKey != nil {
 		tf.KeyName = e.SSHKey.TerraformLink()
 	}
-	if e.Tenancy != nil {
-		tf.Placement = []*terraformLaunchTemplatePlacement{{Tenancy: e.Tenancy}}
+	if e.Tenancy != nil || e.PlacementGroup != nil {
+		placement := &terraformLaunchTemplatePlacement{Tenancy: e.Tenancy}
+		if e.PlacementGroup != nil {
+			placement.GroupName = e.PlacementGroup.TerraformLink()
+		}
+		tf.Placement = []*terraformLaunchTemplatePlacement{placement}
+	}
+	if e.CapacityReservationPreference != nil || e.CapacityReservationID != nil || e.CapacityReservationResourceGroupARN != nil {
+		crs := &terraformLaunchTemplateCapacityReservationSpecification{
+			CapacityReservationPreference: e.CapacityReservationPreference,
+		}
+		if e.CapacityReservationID != nil || e.CapacityReservationResourceGroupARN != nil {
+			crs.CapacityReservationTarget = []*terraformLaunchTemplateCapacityReservationTarget{
+				{
+					CapacityReservationID:               e.CapacityReservationID,
+					CapacityReservationResourceGroupARN: e.CapacityReservationResourceGroupARN,
+				},
+			}
+		}
+		tf.CapacityReservationSpecification = []*terraformLaunchTemplateCapacityReservationSpecification{crs}
 	}
 	if e.InstanceMonitoring != nil {
 		tf.Monitoring = []*terraformLaunchTemplateMonitoring{
@@ -336,5 +371,16 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 		tf.Tags = e.Tags
 	}
 
+	if ig := e.Tags[kops.NodeLabelInstanceGroup]; ig != "" {
+		target.AddInputVariable(ig+"_image_id", "Image of the instances in instance group "+ig, "aws_launch_template", fi.StringValue(e.Name), "image_id")
+		target.AddInputVariable(ig+"_instance_type", "Instance type of the instances in instance group "+ig, "aws_launch_template", fi.StringValue(e.Name), "instance_type")
+	}
+
 	return target.RenderResource("aws_launch_template", fi.StringValue(e.Name), tf)
 }
+
+// TerraformImport implements terraform.Importable
+func (e *LaunchTemplate) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*LaunchTemplate)
+	return []*terraform.Import{{ResourceType: "aws_launch_template", ResourceName: fi.StringValue(e.Name), ID: fi.StringValue(a.ID)}}
+}
@@ -98,7 +98,7 @@ terraform {
   required_providers {
     aws = {
       "source"  = "hashicorp/aws"
-      "version" = ">= 2.46.0"
+      "version" = ">= 3.39.0"
     }
   }
 }
@@ -184,7 +184,55 @@ terraform {
   required_providers {
     aws = {
       "source"  = "hashicorp/aws"
-      "version" = ">= 2.46.0"
+      "version" = ">= 3.39.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &LaunchTemplate{
+				Name:         fi.String("test"),
+				ID:           fi.String("test-11"),
+				InstanceType: fi.String("c5n.18xlarge"),
+				PlacementGroup: &PlacementGroup{
+					Name:     fi.String("test"),
+					Strategy: fi.String("cluster"),
+				},
+				CapacityReservationID: fi.String("cr-0123456789abcdef0"),
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_launch_template" "test" {
+  capacity_reservation_specification {
+    capacity_reservation_target {
+      capacity_reservation_id = "cr-0123456789abcdef0"
+    }
+  }
+  instance_type = "c5n.18xlarge"
+  lifecycle {
+    create_before_destroy = true
+  }
+  metadata_options {
+    http_endpoint = "enabled"
+  }
+  name = "test"
+  network_interfaces {
+    delete_on_termination = true
+  }
+  placement {
+    group_name = aws_placement_group.test.id
+  }
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.39.0"
     }
   }
 }
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+)
+
+// LogGroup is a CloudWatch Logs log group
+// +kops:fitask
+type LogGroup struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	// ARN is the ARN of the log group
+	ARN *string
+	// RetentionInDays is the number of days the log events are kept; they never expire if not set
+	RetentionInDays *int64
+
+	Tags map[string]string
+}
+
+var _ fi.CompareWithID = &LogGroup{}
+
+// CompareWithID returns the name of the log group, as log groups are referenced by name
+func (e *LogGroup) CompareWithID() *string {
+	return e.Name
+}
+
+// Find discovers the log group in the cloud provider
+func (e *LogGroup) Find(c *fi.Context) (*LogGroup, error) {
+	cloud := c.Cloud.(awsup.AWSCloud)
+
+	request := &cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: e.Name,
+	}
+	var found *cloudwatchlogs.LogGroup
+	err := cloud.CloudWatchLogs().DescribeLogGroupsPages(request, func(page *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
+		for _, logGroup := range page.LogGroups {
+			if aws.StringValue(logGroup.LogGroupName) == fi.StringValue(e.Name) {
+				found = logGroup
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing LogGroups: %v", err)
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	tagsResponse, err := cloud.CloudWatchLogs().ListTagsLogGroup(&cloudwatchlogs.ListTagsLogGroupInput{LogGroupName: found.LogGroupName})
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags of LogGroup %q: %v", fi.StringValue(e.Name), err)
+	}
+
+	actual := &LogGroup{
+		Name:            found.LogGroupName,
+		ARN:             found.Arn,
+		RetentionInDays: found.RetentionInDays,
+		Tags:            aws.StringValueMap(tagsResponse.Tags),
+	}
+
+	klog.V(2).Infof("found matching LogGroup %q", fi.StringValue(actual.Name))
+	e.ARN = actual.ARN
+
+	// Avoid spurious changes
+	actual.Lifecycle = e.Lifecycle
+
+	return actual, nil
+}
+
+// Run is responsible for running the task
+func (e *LogGroup) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+// CheckChanges validates the changes to the log group
+func (s *LogGroup) CheckChanges(a, e, changes *LogGroup) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+	}
+	return nil
+}
+
+// RenderAWS creates the log group and updates its retention and tags
+func (_ *LogGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *LogGroup) error {
+	if a == nil {
+		klog.V(2).Infof("Creating LogGroup with Name:%q", fi.StringValue(e.Name))
+
+		request := &cloudwatchlogs.CreateLogGroupInput{
+			LogGroupName: e.Name,
+			Tags:         aws.StringMap(e.Tags),
+		}
+		if _, err := t.Cloud.CloudWatchLogs().CreateLogGroup(request); err != nil {
+			return fmt.Errorf("error creating LogGroup: %v", err)
+		}
+	}
+
+	if a == nil || changes.RetentionInDays != nil {
+		if e.RetentionInDays != nil {
+			request := &cloudwatchlogs.PutRetentionPolicyInput{
+				LogGroupName:    e.Name,
+				RetentionInDays: e.RetentionInDays,
+			}
+			if _, err := t.Cloud.CloudWatchLogs().PutRetentionPolicy(request); err != nil {
+				return fmt.Errorf("error setting retention of LogGroup %q: %v", fi.StringValue(e.Name), err)
+			}
+		} else if a != nil {
+			request := &cloudwatchlogs.DeleteRetentionPolicyInput{
+				LogGroupName: e.Name,
+			}
+			if _, err := t.Cloud.CloudWatchLogs().DeleteRetentionPolicy(request); err != nil {
+				return fmt.Errorf("error removing retention of LogGroup %q: %v", fi.StringValue(e.Name), err)
+			}
+		}
+	}
+
+	if a != nil && changes.Tags != nil {
+		request := &cloudwatchlogs.TagLogGroupInput{
+			LogGroupName: e.Name,
+			Tags:         aws.StringMap(e.Tags),
+		}
+		if _, err := t.Cloud.CloudWatchLogs().TagLogGroup(request); err != nil {
+			return fmt.Errorf("error tagging LogGroup %q: %v", fi.StringValue(e.Name), err)
+		}
+	}
+
+	return nil
+}
+
+type terraformLogGroup struct {
+	Name            *string           `json:"name" cty:"name"`
+	RetentionInDays *int64            `json:"retention_in_days,omitempty" cty:"retention_in_days"`
+	Tags            map[string]string `json:"tags,omitempty" cty:"tags"`
+}
+
+// RenderTerraform renders the log group as an aws_cloudwatch_log_group
+func (_ *LogGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *LogGroup) error {
+	tf := &terraformLogGroup{
+		Name:            e.Name,
+		RetentionInDays: e.RetentionInDays,
+		Tags:            e.Tags,
+	}
+
+	return t.RenderResource("aws_cloudwatch_log_group", fi.StringValue(e.Name), tf)
+}
+
+// TerraformImport implements terraform.Importable
+func (e *LogGroup) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*LogGroup)
+	return []*terraform.Import{{ResourceType: "aws_cloudwatch_log_group", ResourceName: fi.StringValue(e.Name), ID: fi.StringValue(a.Name)}}
+}
+
+// TerraformLink returns the ARN of the log group
+func (e *LogGroup) TerraformLink() *terraform.Literal {
+	return terraform.LiteralProperty("aws_cloudwatch_log_group", fi.StringValue(e.Name), "arn")
+}
+
+type cloudformationLogGroup struct {
+	LogGroupName    *string `json:"LogGroupName"`
+	RetentionInDays *int64  `json:"RetentionInDays,omitempty"`
+}
+
+// RenderCloudformation renders the log group as an AWS::Logs::LogGroup, which does not support tags
+func (_ *LogGroup) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *LogGroup) error {
+	cf := &cloudformationLogGroup{
+		LogGroupName:    e.Name,
+		RetentionInDays: e.RetentionInDays,
+	}
+
+	return t.RenderResource("AWS::Logs::LogGroup", fi.StringValue(e.Name), cf)
+}
+
+// CloudformationLink returns the name of the log group
+func (e *LogGroup) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::Logs::LogGroup", fi.StringValue(e.Name))
+}
@@ -363,6 +363,12 @@ func (_ *NatGateway) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_nat_gateway", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *NatGateway) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*NatGateway)
+	return []*terraform.Import{{ResourceType: "aws_nat_gateway", ResourceName: *e.Name, ID: fi.StringValue(a.ID)}}
+}
+
 func (e *NatGateway) TerraformLink() *terraform.Literal {
 	if fi.BoolValue(e.Shared) {
 		if e.ID == nil {
@@ -59,6 +59,8 @@ type NetworkLoadBalancer struct {
 
 	CrossZoneLoadBalancing *bool
 
+	AccessLog *NetworkLoadBalancerAccessLog
+
 	Tags         map[string]string
 	ForAPIServer bool
 
@@ -66,6 +68,11 @@ type NetworkLoadBalancer struct {
 
 	VPC          *VPC
 	TargetGroups []*TargetGroup
+
+	// arn is the ARN of the existing load balancer, as found by Find
+	arn *string
+	// listenerARNs are the ARNs of the existing listeners by port, as found by Find
+	listenerARNs map[int]string
 }
 
 var _ fi.CompareWithID = &NetworkLoadBalancer{}
@@ -338,6 +345,7 @@ func (e *NetworkLoadBalancer) Find(c *fi.Context) (*NetworkLoadBalancer, error)
 	actual.Scheme = lb.Scheme
 	actual.VPC = &VPC{ID: lb.VpcId}
 	actual.Type = lb.Type
+	actual.arn = loadBalancerArn
 
 	tagMap, err := describeNetworkLoadBalancerTags(cloud, []string{*loadBalancerArn})
 	if err != nil {
@@ -383,9 +391,11 @@ func (e *NetworkLoadBalancer) Find(c *fi.Context) (*NetworkLoadBalancer, error)
 
 		actual.Listeners = []*NetworkLoadBalancerListener{}
 		actual.TargetGroups = []*TargetGroup{}
+		actual.listenerARNs = make(map[int]string)
 		for _, l := range response.Listeners {
 			actualListener := &NetworkLoadBalancerListener{}
 			actualListener.Port = int(aws.Int64Value(l.Port))
+			actual.listenerARNs[actualListener.Port] = aws.StringValue(l.ListenerArn)
 			if len(l.Certificates) != 0 {
 				actualListener.SSLCertificateID = aws.StringValue(l.Certificates[0].CertificateArn) // What if there is more then one certificate, can we just grab the default certificate? we don't set it as default, we only set the one.
 				if l.SslPolicy != nil {
@@ -441,6 +451,29 @@ func (e *NetworkLoadBalancer) Find(c *fi.Context) (*NetworkLoadBalancer, error)
 					return nil, err
 				}
 				actual.CrossZoneLoadBalancing = fi.Bool(b)
+			case "access_logs.s3.enabled":
+				b, err := strconv.ParseBool(*value)
+				if err != nil {
+					return nil, err
+				}
+				if actual.AccessLog == nil {
+					actual.AccessLog = &NetworkLoadBalancerAccessLog{}
+				}
+				actual.AccessLog.Enabled = fi.Bool(b)
+			case "access_logs.s3.bucket":
+				if actual.AccessLog == nil {
+					actual.AccessLog = &NetworkLoadBalancerAccessLog{}
+				}
+				if *value != "" {
+					actual.AccessLog.S3BucketName = value
+				}
+			case "access_logs.s3.prefix":
+				if actual.AccessLog == nil {
+					actual.AccessLog = &NetworkLoadBalancerAccessLog{}
+				}
+				if *value != "" {
+					actual.AccessLog.S3BucketPrefix = value
+				}
 			default:
 				klog.V(2).Infof("unsupported key -- ignoring, %v.\n", key)
 			}
@@ -530,6 +563,12 @@ func (s *NetworkLoadBalancer) CheckChanges(a, e, changes *NetworkLoadBalancer) e
 				return fi.RequiredField("CrossZoneLoadBalancing")
 			}
 		}
+
+		if e.AccessLog != nil && aws.BoolValue(e.AccessLog.Enabled) {
+			if e.AccessLog.S3BucketName == nil {
+				return fi.RequiredField("AccessLog.S3BucketName")
+			}
+		}
 	} else {
 		if len(changes.SubnetMappings) > 0 {
 			expectedSubnets := make(map[string]*string)
@@ -731,6 +770,7 @@ type terraformNetworkLoadBalancer struct {
 	Type                   string                                      `json:"load_balancer_type" cty:"load_balancer_type"`
 	SubnetMappings         []terraformNetworkLoadBalancerSubnetMapping `json:"subnet_mapping" cty:"subnet_mapping"`
 	CrossZoneLoadBalancing bool                                        `json:"enable_cross_zone_load_balancing" cty:"enable_cross_zone_load_balancing"`
+	AccessLog              *terraformNetworkLoadBalancerAccessLog      `json:"access_logs,omitempty" cty:"access_logs"`
 
 	Tags map[string]string `json:"tags" cty:"tags"`
 }
@@ -772,6 +812,14 @@ func (_ *NetworkLoadBalancer) RenderTerraform(t *terraform.TerraformTarget, a, e
 		})
 	}
 
+	if e.AccessLog != nil && aws.BoolValue(e.AccessLog.Enabled) {
+		nlbTF.AccessLog = &terraformNetworkLoadBalancerAccessLog{
+			Enabled:        e.AccessLog.Enabled,
+			S3BucketName:   e.AccessLog.S3BucketName,
+			S3BucketPrefix: e.AccessLog.S3BucketPrefix,
+		}
+	}
+
 	err := t.RenderResource("aws_lb", *e.Name, nlbTF)
 	if err != nil {
 		return err
@@ -816,6 +864,20 @@ func (_ *NetworkLoadBalancer) RenderTerraform(t *terraform.TerraformTarget, a, e
 	return nil
 }
 
+// TerraformImport implements terraform.Importable
+func (e *NetworkLoadBalancer) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*NetworkLoadBalancer)
+	imports := []*terraform.Import{{ResourceType: "aws_lb", ResourceName: *e.Name, ID: fi.StringValue(a.arn)}}
+	for _, listener := range e.Listeners {
+		imports = append(imports, &terraform.Import{
+			ResourceType: "aws_lb_listener",
+			ResourceName: fmt.Sprintf("%v-%v", *e.Name, listener.Port),
+			ID:           a.listenerARNs[listener.Port],
+		})
+	}
+	return imports
+}
+
 func (e *NetworkLoadBalancer) TerraformLink(params ...string) *terraform.Literal {
 	prop := "id"
 	if len(params) > 0 {
@@ -830,6 +892,8 @@ type cloudformationNetworkLoadBalancer struct {
 	SubnetMappings []*cloudformationSubnetMapping `json:"SubnetMappings"`
 	Type           string                         `json:"Type"`
 	Tags           []cloudformationTag            `json:"Tags"`
+
+	LoadBalancerAttributes []cloudformationNetworkLoadBalancerAttribute `json:"LoadBalancerAttributes,omitempty"`
 }
 
 type cloudformationSubnetMapping struct {
@@ -874,6 +938,17 @@ func (_ *NetworkLoadBalancer) RenderCloudformation(t *cloudformation.Cloudformat
 	} else {
 		nlbCF.Scheme = elbv2.LoadBalancerSchemeEnumInternetFacing
 	}
+	if e.AccessLog != nil && aws.BoolValue(e.AccessLog.Enabled) {
+		nlbCF.LoadBalancerAttributes = append(nlbCF.LoadBalancerAttributes,
+			cloudformationNetworkLoadBalancerAttribute{Key: "access_logs.s3.enabled", Value: "true"},
+			cloudformationNetworkLoadBalancerAttribute{Key: "access_logs.s3.bucket", Value: aws.StringValue(e.AccessLog.S3BucketName)},
+		)
+		if e.AccessLog.S3BucketPrefix != nil {
+			nlbCF.LoadBalancerAttributes = append(nlbCF.LoadBalancerAttributes,
+				cloudformationNetworkLoadBalancerAttribute{Key: "access_logs.s3.prefix", Value: aws.StringValue(e.AccessLog.S3BucketPrefix)},
+			)
+		}
+	}
 	err := t.RenderResource("AWS::ElasticLoadBalancingV2::LoadBalancer", *e.Name, nlbCF)
 	if err != nil {
 		return err
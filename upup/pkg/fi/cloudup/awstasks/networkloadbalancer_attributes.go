@@ -27,6 +27,28 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 )
 
+// NetworkLoadBalancerAccessLog configures the access logs of a network load balancer
+type NetworkLoadBalancerAccessLog struct {
+	Enabled        *bool
+	S3BucketName   *string
+	S3BucketPrefix *string
+}
+
+func (_ *NetworkLoadBalancerAccessLog) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	return nil
+}
+
+type terraformNetworkLoadBalancerAccessLog struct {
+	Enabled        *bool   `json:"enabled,omitempty" cty:"enabled"`
+	S3BucketName   *string `json:"bucket" cty:"bucket"`
+	S3BucketPrefix *string `json:"prefix,omitempty" cty:"prefix"`
+}
+
+type cloudformationNetworkLoadBalancerAttribute struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
 func findNetworkLoadBalancerAttributes(cloud awsup.AWSCloud, LoadBalancerArn string) ([]*elbv2.LoadBalancerAttribute, error) {
 
 	request := &elbv2.DescribeLoadBalancerAttributesInput{
@@ -52,7 +74,7 @@ func findNetworkLoadBalancerAttributes(cloud awsup.AWSCloud, LoadBalancerArn str
 }
 
 func (_ *NetworkLoadBalancer) modifyLoadBalancerAttributes(t *awsup.AWSAPITarget, a, e, changes *NetworkLoadBalancer, loadBalancerArn string) error {
-	if changes.CrossZoneLoadBalancing == nil {
+	if changes.CrossZoneLoadBalancing == nil && changes.AccessLog == nil {
 		klog.V(4).Infof("No LoadBalancerAttribute changes; skipping update")
 		return nil
 	}
@@ -74,6 +96,24 @@ func (_ *NetworkLoadBalancer) modifyLoadBalancerAttributes(t *awsup.AWSAPITarget
 	}
 	attributes = append(attributes, attribute)
 
+	if e.AccessLog == nil || !aws.BoolValue(e.AccessLog.Enabled) {
+		attributes = append(attributes, &elbv2.LoadBalancerAttribute{
+			Key:   aws.String("access_logs.s3.enabled"),
+			Value: aws.String("false"),
+		})
+	} else {
+		attributes = append(attributes, &elbv2.LoadBalancerAttribute{
+			Key:   aws.String("access_logs.s3.enabled"),
+			Value: aws.String("true"),
+		}, &elbv2.LoadBalancerAttribute{
+			Key:   aws.String("access_logs.s3.bucket"),
+			Value: aws.String(aws.StringValue(e.AccessLog.S3BucketName)),
+		}, &elbv2.LoadBalancerAttribute{
+			Key:   aws.String("access_logs.s3.prefix"),
+			Value: aws.String(aws.StringValue(e.AccessLog.S3BucketPrefix)),
+		})
+	}
+
 	request.Attributes = attributes
 
 	klog.V(2).Infof("Configuring NLB attributes for NLB %q", loadBalancerName)
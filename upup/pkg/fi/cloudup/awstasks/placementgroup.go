@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+)
+
+// PlacementGroup is an EC2 placement group, which influences how the instances of an instance group are placed
+// on the underlying hardware
+// +kops:fitask
+type PlacementGroup struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	// ID is the ID of the placement group
+	ID *string
+	// Strategy is the placement strategy: cluster, spread or partition
+	Strategy *string
+	// PartitionCount is the number of partitions of a placement group with the partition strategy
+	PartitionCount *int64
+
+	Tags map[string]string
+}
+
+var _ fi.CompareWithID = &PlacementGroup{}
+
+// CompareWithID returns the name of the placement group, as placement groups are referenced by name
+func (e *PlacementGroup) CompareWithID() *string {
+	return e.Name
+}
+
+// Find discovers the placement group in the cloud provider
+func (e *PlacementGroup) Find(c *fi.Context) (*PlacementGroup, error) {
+	cloud := c.Cloud.(awsup.AWSCloud)
+
+	return e.find(cloud)
+}
+
+func (e *PlacementGroup) find(cloud awsup.AWSCloud) (*PlacementGroup, error) {
+	request := &ec2.DescribePlacementGroupsInput{
+		Filters: []*ec2.Filter{awsup.NewEC2Filter("group-name", fi.StringValue(e.Name))},
+	}
+	response, err := cloud.EC2().DescribePlacementGroups(request)
+	if err != nil {
+		return nil, fmt.Errorf("error listing PlacementGroups: %v", err)
+	}
+
+	var found []*ec2.PlacementGroup
+	for _, pg := range response.PlacementGroups {
+		if aws.StringValue(pg.State) == ec2.PlacementGroupStateDeleting || aws.StringValue(pg.State) == ec2.PlacementGroupStateDeleted {
+			continue
+		}
+		found = append(found, pg)
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+	if len(found) != 1 {
+		return nil, fmt.Errorf("found multiple PlacementGroups with name %q", fi.StringValue(e.Name))
+	}
+
+	pg := found[0]
+	actual := &PlacementGroup{
+		ID:       pg.GroupId,
+		Name:     pg.GroupName,
+		Strategy: pg.Strategy,
+		Tags:     mapEC2TagsToMap(pg.Tags),
+	}
+	if aws.StringValue(pg.Strategy) == ec2.PlacementStrategyPartition {
+		actual.PartitionCount = pg.PartitionCount
+	}
+
+	klog.V(2).Infof("found matching PlacementGroup %q", fi.StringValue(actual.Name))
+	e.ID = actual.ID
+
+	// Avoid spurious changes
+	actual.Lifecycle = e.Lifecycle
+
+	return actual, nil
+}
+
+// Run is responsible for running the task
+func (e *PlacementGroup) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+// CheckChanges validates the changes to the placement group
+func (s *PlacementGroup) CheckChanges(a, e, changes *PlacementGroup) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Strategy == nil {
+			return fi.RequiredField("Strategy")
+		}
+	}
+	if a != nil {
+		if changes.Strategy != nil {
+			return fi.CannotChangeField("Strategy")
+		}
+		if changes.PartitionCount != nil {
+			return fi.CannotChangeField("PartitionCount")
+		}
+	}
+	return nil
+}
+
+// RenderAWS creates the placement group and updates its tags
+func (_ *PlacementGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *PlacementGroup) error {
+	if a == nil {
+		klog.V(2).Infof("Creating PlacementGroup with Name:%q", fi.StringValue(e.Name))
+
+		request := &ec2.CreatePlacementGroupInput{
+			GroupName:         e.Name,
+			Strategy:          e.Strategy,
+			PartitionCount:    e.PartitionCount,
+			TagSpecifications: awsup.EC2TagSpecification(ec2.ResourceTypePlacementGroup, e.Tags),
+		}
+		if _, err := t.Cloud.EC2().CreatePlacementGroup(request); err != nil {
+			return fmt.Errorf("error creating PlacementGroup: %v", err)
+		}
+
+		pg, err := e.find(t.Cloud)
+		if err != nil {
+			return err
+		}
+		if pg == nil {
+			return fmt.Errorf("PlacementGroup %q was not found after creation", fi.StringValue(e.Name))
+		}
+		return nil
+	}
+
+	return t.AddAWSTags(fi.StringValue(e.ID), e.Tags)
+}
+
+type terraformPlacementGroup struct {
+	Name           *string           `json:"name" cty:"name"`
+	Strategy       *string           `json:"strategy" cty:"strategy"`
+	PartitionCount *int64            `json:"partition_count,omitempty" cty:"partition_count"`
+	Tags           map[string]string `json:"tags,omitempty" cty:"tags"`
+}
+
+// RenderTerraform renders the placement group as an aws_placement_group
+func (_ *PlacementGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *PlacementGroup) error {
+	tf := &terraformPlacementGroup{
+		Name:           e.Name,
+		Strategy:       e.Strategy,
+		PartitionCount: e.PartitionCount,
+		Tags:           e.Tags,
+	}
+
+	return t.RenderResource("aws_placement_group", fi.StringValue(e.Name), tf)
+}
+
+// TerraformImport implements terraform.Importable
+func (e *PlacementGroup) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*PlacementGroup)
+	return []*terraform.Import{{ResourceType: "aws_placement_group", ResourceName: fi.StringValue(e.Name), ID: fi.StringValue(a.Name)}}
+}
+
+// TerraformLink returns the name of the placement group
+func (e *PlacementGroup) TerraformLink() *terraform.Literal {
+	return terraform.LiteralProperty("aws_placement_group", fi.StringValue(e.Name), "id")
+}
+
+type cloudformationPlacementGroup struct {
+	Strategy *string `json:"Strategy,omitempty"`
+}
+
+// RenderCloudformation renders the placement group as an AWS::EC2::PlacementGroup.
+// CloudFormation generates the name of the placement group, and only supports its strategy.
+func (_ *PlacementGroup) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *PlacementGroup) error {
+	if e.PartitionCount != nil {
+		klog.Warningf("CloudFormation does not support the partition count of PlacementGroup %q; using the default", fi.StringValue(e.Name))
+	}
+
+	cf := &cloudformationPlacementGroup{
+		Strategy: e.Strategy,
+	}
+
+	return t.RenderResource("AWS::EC2::PlacementGroup", fi.StringValue(e.Name), cf)
+}
+
+// CloudformationLink returns the name of the placement group
+func (e *PlacementGroup) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::EC2::PlacementGroup", fi.StringValue(e.Name))
+}
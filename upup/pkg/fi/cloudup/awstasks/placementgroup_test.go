@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestPlacementGroupTerraformRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &PlacementGroup{
+				Name:           fi.String("nodes.example.com"),
+				Strategy:       fi.String("partition"),
+				PartitionCount: fi.Int64(3),
+				Tags: map[string]string{
+					"KubernetesCluster": "example.com",
+				},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_placement_group" "nodes-example-com" {
+  name            = "nodes.example.com"
+  partition_count = 3
+  strategy        = "partition"
+  tags = {
+    "KubernetesCluster" = "example.com"
+  }
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.39.0"
+    }
+  }
+}
+`,
+		},
+	}
+	doRenderTests(t, "RenderTerraform", cases)
+}
+
+func TestPlacementGroupCloudformationRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &PlacementGroup{
+				Name:     fi.String("nodes.example.com"),
+				Strategy: fi.String("spread"),
+			},
+			Expected: `{
+  "Resources": {
+    "AWSEC2PlacementGroupnodesexamplecom": {
+      "Type": "AWS::EC2::PlacementGroup",
+      "Properties": {
+        "Strategy": "spread"
+      }
+    }
+  }
+}`,
+		},
+	}
+	doRenderTests(t, "RenderCloudformation", cases)
+}
@@ -35,7 +35,10 @@ type Route struct {
 
 	RouteTable *RouteTable
 	Instance   *Instance
-	CIDR       *string
+
+	// Exactly one of CIDR and IPv6CIDR must be provided.
+	CIDR     *string
+	IPv6CIDR *string
 
 	// Exactly one of the below fields
 	// MUST be provided.
@@ -47,7 +50,7 @@ type Route struct {
 func (e *Route) Find(c *fi.Context) (*Route, error) {
 	cloud := c.Cloud.(awsup.AWSCloud)
 
-	if e.RouteTable == nil || e.CIDR == nil {
+	if e.RouteTable == nil || (e.CIDR == nil && e.IPv6CIDR == nil) {
 		// TODO: Move to validate?
 		return nil, nil
 	}
@@ -72,13 +75,17 @@ func (e *Route) Find(c *fi.Context) (*Route, error) {
 		}
 		rt := response.RouteTables[0]
 		for _, r := range rt.Routes {
-			if aws.StringValue(r.DestinationCidrBlock) != *e.CIDR {
+			if e.CIDR != nil && aws.StringValue(r.DestinationCidrBlock) != *e.CIDR {
+				continue
+			}
+			if e.IPv6CIDR != nil && aws.StringValue(r.DestinationIpv6CidrBlock) != *e.IPv6CIDR {
 				continue
 			}
 			actual := &Route{
 				Name:       e.Name,
 				RouteTable: &RouteTable{ID: rt.RouteTableId},
 				CIDR:       r.DestinationCidrBlock,
+				IPv6CIDR:   r.DestinationIpv6CidrBlock,
 			}
 			if r.GatewayId != nil {
 				actual.InternetGateway = &InternetGateway{ID: r.GatewayId}
@@ -104,7 +111,7 @@ func (e *Route) Find(c *fi.Context) (*Route, error) {
 			// Prevent spurious changes
 			actual.Lifecycle = e.Lifecycle
 
-			klog.V(2).Infof("found route matching cidr %s", *e.CIDR)
+			klog.V(2).Infof("found route matching cidr %s", e.destination())
 			return actual, nil
 		}
 	}
@@ -112,6 +119,14 @@ func (e *Route) Find(c *fi.Context) (*Route, error) {
 	return nil, nil
 }
 
+// destination returns the destination CIDR of the route, for logging
+func (e *Route) destination() string {
+	if e.IPv6CIDR != nil {
+		return *e.IPv6CIDR
+	}
+	return fi.StringValue(e.CIDR)
+}
+
 func (e *Route) Run(c *fi.Context) error {
 	return fi.DefaultDeltaRunMethod(e, c)
 }
@@ -122,9 +137,12 @@ func (s *Route) CheckChanges(a, e, changes *Route) error {
 		if e.RouteTable == nil {
 			return fi.RequiredField("RouteTable")
 		}
-		if e.CIDR == nil {
+		if e.CIDR == nil && e.IPv6CIDR == nil {
 			return fi.RequiredField("CIDR")
 		}
+		if e.CIDR != nil && e.IPv6CIDR != nil {
+			return fmt.Errorf("Cannot set both CIDR and IPv6CIDR")
+		}
 		targetCount := 0
 		if e.InternetGateway != nil {
 			targetCount++
@@ -153,6 +171,9 @@ func (s *Route) CheckChanges(a, e, changes *Route) error {
 		if changes.CIDR != nil {
 			return fi.CannotChangeField("CIDR")
 		}
+		if changes.IPv6CIDR != nil {
+			return fi.CannotChangeField("IPv6CIDR")
+		}
 	}
 	return nil
 }
@@ -161,7 +182,11 @@ func (_ *Route) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Route) error {
 	if a == nil {
 		request := &ec2.CreateRouteInput{}
 		request.RouteTableId = checkNotNil(e.RouteTable.ID)
-		request.DestinationCidrBlock = checkNotNil(e.CIDR)
+		if e.IPv6CIDR != nil {
+			request.DestinationIpv6CidrBlock = e.IPv6CIDR
+		} else {
+			request.DestinationCidrBlock = checkNotNil(e.CIDR)
+		}
 
 		if e.InternetGateway == nil && e.NatGateway == nil && e.TransitGatewayID == nil {
 			return fmt.Errorf("missing target for route")
@@ -177,7 +202,7 @@ func (_ *Route) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Route) error {
 			request.InstanceId = checkNotNil(e.Instance.ID)
 		}
 
-		klog.V(2).Infof("Creating Route with RouteTable:%q CIDR:%q", *e.RouteTable.ID, *e.CIDR)
+		klog.V(2).Infof("Creating Route with RouteTable:%q CIDR:%q", *e.RouteTable.ID, e.destination())
 
 		response, err := t.Cloud.EC2().CreateRoute(request)
 		if err != nil {
@@ -196,7 +221,11 @@ func (_ *Route) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Route) error {
 	} else {
 		request := &ec2.ReplaceRouteInput{}
 		request.RouteTableId = checkNotNil(e.RouteTable.ID)
-		request.DestinationCidrBlock = checkNotNil(e.CIDR)
+		if e.IPv6CIDR != nil {
+			request.DestinationIpv6CidrBlock = e.IPv6CIDR
+		} else {
+			request.DestinationCidrBlock = checkNotNil(e.CIDR)
+		}
 
 		if e.InternetGateway == nil && e.NatGateway == nil && e.TransitGatewayID == nil {
 			return fmt.Errorf("missing target for route")
@@ -212,7 +241,7 @@ func (_ *Route) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Route) error {
 			request.InstanceId = checkNotNil(e.Instance.ID)
 		}
 
-		klog.V(2).Infof("Updating Route with RouteTable:%q CIDR:%q", *e.RouteTable.ID, *e.CIDR)
+		klog.V(2).Infof("Updating Route with RouteTable:%q CIDR:%q", *e.RouteTable.ID, e.destination())
 
 		if _, err := t.Cloud.EC2().ReplaceRoute(request); err != nil {
 			code := awsup.AWSErrorCode(err)
@@ -238,6 +267,7 @@ func checkNotNil(s *string) *string {
 type terraformRoute struct {
 	RouteTableID      *terraform.Literal `json:"route_table_id" cty:"route_table_id"`
 	CIDR              *string            `json:"destination_cidr_block,omitempty" cty:"destination_cidr_block"`
+	IPv6CIDR          *string            `json:"destination_ipv6_cidr_block,omitempty" cty:"destination_ipv6_cidr_block"`
 	InternetGatewayID *terraform.Literal `json:"gateway_id,omitempty" cty:"gateway_id"`
 	NATGatewayID      *terraform.Literal `json:"nat_gateway_id,omitempty" cty:"nat_gateway_id"`
 	TransitGatewayID  *string            `json:"transit_gateway_id,omitempty" cty:"transit_gateway_id"`
@@ -247,6 +277,7 @@ type terraformRoute struct {
 func (_ *Route) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Route) error {
 	tf := &terraformRoute{
 		CIDR:         e.CIDR,
+		IPv6CIDR:     e.IPv6CIDR,
 		RouteTableID: e.RouteTable.TerraformLink(),
 	}
 
@@ -270,9 +301,21 @@ func (_ *Route) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Rou
 	return t.RenderResource("aws_route", name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *Route) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*Route)
+	destination := fi.StringValue(a.CIDR)
+	if destination == "" {
+		destination = fi.StringValue(a.IPv6CIDR)
+	}
+	name := fmt.Sprintf("route-%v", *e.Name)
+	return []*terraform.Import{{ResourceType: "aws_route", ResourceName: name, ID: fi.StringValue(a.RouteTable.ID) + "_" + destination}}
+}
+
 type cloudformationRoute struct {
 	RouteTableID      *cloudformation.Literal `json:"RouteTableId"`
 	CIDR              *string                 `json:"DestinationCidrBlock,omitempty"`
+	IPv6CIDR          *string                 `json:"DestinationIpv6CidrBlock,omitempty"`
 	InternetGatewayID *cloudformation.Literal `json:"GatewayId,omitempty"`
 	NATGatewayID      *cloudformation.Literal `json:"NatGatewayId,omitempty"`
 	TransitGatewayID  *string                 `json:"TransitGatewayId,omitempty"`
@@ -282,10 +325,11 @@ type cloudformationRoute struct {
 func (_ *Route) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *Route) error {
 	tf := &cloudformationRoute{
 		CIDR:         e.CIDR,
+		IPv6CIDR:     e.IPv6CIDR,
 		RouteTableID: e.RouteTable.CloudformationLink(),
 	}
 
-	if e.InternetGateway == nil && e.NatGateway == nil && e.TransitGatewayID == nil {
+	if e.InternetGateway == nil && e.NatGateway == nil && e.TransitGatewayID == nil && e.Instance == nil {
 		return fmt.Errorf("missing target for route")
 	} else if e.InternetGateway != nil {
 		tf.InternetGatewayID = e.InternetGateway.CloudformationLink()
@@ -296,8 +340,7 @@ func (_ *Route) RenderCloudformation(t *cloudformation.CloudformationTarget, a,
 	}
 
 	if e.Instance != nil {
-		return fmt.Errorf("instance cloudformation routes not yet implemented")
-		//tf.InstanceID = e.Instance.CloudformationLink()
+		tf.InstanceID = e.Instance.CloudformationLink()
 	}
 
 	return t.RenderResource("AWS::EC2::Route", *e.Name, tf)
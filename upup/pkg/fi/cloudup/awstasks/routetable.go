@@ -212,6 +212,12 @@ func (_ *RouteTable) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_route_table", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *RouteTable) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*RouteTable)
+	return []*terraform.Import{{ResourceType: "aws_route_table", ResourceName: *e.Name, ID: fi.StringValue(a.ID)}}
+}
+
 func (e *RouteTable) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_route_table", *e.Name, "id")
 }
@@ -222,6 +228,14 @@ type cloudformationRouteTable struct {
 }
 
 func (_ *RouteTable) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *RouteTable) error {
+	// We use the role tag as a concise and stable identifier
+	tag := e.Tags[awsup.TagNameKopsRole]
+	if tag != "" {
+		if err := t.AddOutputVariable("route_table_"+tag+"_id", e.CloudformationLink()); err != nil {
+			return err
+		}
+	}
+
 	cf := &cloudformationRouteTable{
 		VPCID: e.VPC.CloudformationLink(),
 		Tags:  buildCloudformationTags(e.Tags),
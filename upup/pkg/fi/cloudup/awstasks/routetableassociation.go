@@ -200,6 +200,12 @@ func (_ *RouteTableAssociation) RenderTerraform(t *terraform.TerraformTarget, a,
 	return t.RenderResource("aws_route_table_association", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *RouteTableAssociation) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*RouteTableAssociation)
+	return []*terraform.Import{{ResourceType: "aws_route_table_association", ResourceName: *e.Name, ID: fi.StringValue(a.Subnet.ID) + "/" + fi.StringValue(a.RouteTable.ID)}}
+}
+
 func (e *RouteTableAssociation) TerraformLink() *terraform.Literal {
 	return terraform.LiteralSelfLink("aws_route_table_association", *e.Name)
 }
@@ -207,6 +207,12 @@ func (_ *SecurityGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 	return t.RenderResource("aws_security_group", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *SecurityGroup) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*SecurityGroup)
+	return []*terraform.Import{{ResourceType: "aws_security_group", ResourceName: *e.Name, ID: fi.StringValue(a.ID)}}
+}
+
 func (e *SecurityGroup) TerraformLink() *terraform.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
@@ -340,6 +340,38 @@ func (_ *SecurityGroupRule) RenderTerraform(t *terraform.TerraformTarget, a, e,
 	return t.RenderResource("aws_security_group_rule", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *SecurityGroupRule) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*SecurityGroupRule)
+
+	ruleType := "ingress"
+	if fi.BoolValue(e.Egress) {
+		ruleType = "egress"
+	}
+
+	// The ports match those that RenderTerraform writes
+	protocol := fi.StringValue(a.Protocol)
+	fromPort := fi.Int64Value(a.FromPort)
+	toPort := int64(65535)
+	if a.ToPort != nil {
+		toPort = *a.ToPort
+	}
+	if a.Protocol == nil {
+		// terraform identifies rules for all protocols by the full port range
+		protocol = "all"
+		fromPort = 0
+		toPort = 65536
+	}
+
+	source := fi.StringValue(a.CIDR)
+	if a.SourceGroup != nil {
+		source = fi.StringValue(a.SourceGroup.ID)
+	}
+
+	id := fmt.Sprintf("%s_%s_%s_%d_%d_%s", fi.StringValue(a.SecurityGroup.ID), ruleType, protocol, fromPort, toPort, source)
+	return []*terraform.Import{{ResourceType: "aws_security_group_rule", ResourceName: *e.Name, ID: id}}
+}
+
 type cloudformationSecurityGroupIngress struct {
 	SecurityGroup *cloudformation.Literal `json:"GroupId,omitempty"`
 	SourceGroup   *cloudformation.Literal `json:"SourceSecurityGroupId,omitempty"`
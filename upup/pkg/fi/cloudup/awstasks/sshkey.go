@@ -207,6 +207,13 @@ func (_ *SSHKey) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *SS
 	return t.RenderResource("aws_key_pair", tfName, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *SSHKey) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*SSHKey)
+	tfName := strings.Replace(*e.Name, ":", "", -1)
+	return []*terraform.Import{{ResourceType: "aws_key_pair", ResourceName: tfName, ID: fi.StringValue(a.Name)}}
+}
+
 // IsExistingKey will be true if the task has been initialized without using a public key
 // this is when we want to use a key that is already present in AWS.
 func (e *SSHKey) IsExistingKey() bool {
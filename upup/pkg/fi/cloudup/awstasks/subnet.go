@@ -18,10 +18,13 @@ package awstasks
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
+	utilsubnet "k8s.io/kops/pkg/util/subnet"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
@@ -46,6 +49,10 @@ type Subnet struct {
 	CIDR             *string
 	Shared           *bool
 
+	// IPv6CIDR is the IPv6 CIDR block of the subnet.  It is either a full CIDR,
+	// or of the form /64#N for the Nth /64 of the VPC's IPv6 CIDR.
+	IPv6CIDR *string
+
 	Tags map[string]string
 }
 
@@ -84,9 +91,27 @@ func (e *Subnet) Find(c *fi.Context) (*Subnet, error) {
 		Tags:             intersectTags(subnet.Tags, e.Tags),
 	}
 
+	for _, association := range subnet.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState != nil && fi.StringValue(association.Ipv6CidrBlockState.State) == ec2.SubnetCidrBlockStateCodeAssociated {
+			actual.IPv6CIDR = association.Ipv6CidrBlock
+			break
+		}
+	}
+
 	klog.V(2).Infof("found matching subnet %q", *actual.ID)
 	e.ID = actual.ID
 
+	// A subnet index refers to the actual CIDR it resolves to
+	if actual.IPv6CIDR != nil && e.IPv6CIDR != nil && utilsubnet.IsSubnetIndex(*e.IPv6CIDR) {
+		ipv6CIDR, err := e.resolveIPv6CIDR()
+		if err != nil {
+			return nil, err
+		}
+		if fi.StringValue(ipv6CIDR) == fi.StringValue(actual.IPv6CIDR) {
+			actual.IPv6CIDR = e.IPv6CIDR
+		}
+	}
+
 	// Prevent spurious changes
 	actual.Lifecycle = e.Lifecycle // Not materialized in AWS
 	actual.ShortName = e.ShortName // Not materialized in AWS
@@ -121,6 +146,25 @@ func (e *Subnet) findEc2Subnet(c *fi.Context) (*ec2.Subnet, error) {
 	return subnet, nil
 }
 
+// resolveIPv6CIDR returns the IPv6 CIDR of the subnet, resolving subnet indexes against the VPC's IPv6 CIDR
+func (e *Subnet) resolveIPv6CIDR() (*string, error) {
+	if e.IPv6CIDR == nil || !utilsubnet.IsSubnetIndex(*e.IPv6CIDR) {
+		return e.IPv6CIDR, nil
+	}
+	if e.VPC == nil || e.VPC.IPv6CIDR == nil {
+		return nil, fmt.Errorf("VPC IPv6 CIDR is not known, required for subnet %q", fi.StringValue(e.Name))
+	}
+	_, vpcCIDR, err := net.ParseCIDR(*e.VPC.IPv6CIDR)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing VPC IPv6 CIDR %q: %v", *e.VPC.IPv6CIDR, err)
+	}
+	cidr, err := utilsubnet.ResolveSubnetIndex(vpcCIDR, *e.IPv6CIDR)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving IPv6 CIDR for subnet %q: %v", fi.StringValue(e.Name), err)
+	}
+	return fi.String(cidr.String()), nil
+}
+
 func (e *Subnet) Run(c *fi.Context) error {
 	return fi.DefaultDeltaRunMethod(e, c)
 }
@@ -159,6 +203,9 @@ func (s *Subnet) CheckChanges(a, e, changes *Subnet) error {
 		if changes.CIDR != nil {
 			errors = append(errors, fi.FieldIsImmutable(a.CIDR, e.CIDR, fieldPath.Child("CIDR")))
 		}
+		if changes.IPv6CIDR != nil && a.IPv6CIDR != nil {
+			errors = append(errors, fi.FieldIsImmutable(a.IPv6CIDR, e.IPv6CIDR, fieldPath.Child("IPv6CIDR")))
+		}
 	}
 
 	if len(errors) != 0 {
@@ -177,11 +224,17 @@ func (_ *Subnet) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Subnet) error {
 		}
 	}
 
+	ipv6CIDR, err := e.resolveIPv6CIDR()
+	if err != nil {
+		return err
+	}
+
 	if a == nil {
 		klog.V(2).Infof("Creating Subnet with CIDR: %q", *e.CIDR)
 
 		request := &ec2.CreateSubnetInput{
 			CidrBlock:         e.CIDR,
+			Ipv6CidrBlock:     ipv6CIDR,
 			AvailabilityZone:  e.AvailabilityZone,
 			VpcId:             e.VPC.ID,
 			TagSpecifications: awsup.EC2TagSpecification(ec2.ResourceTypeSubnet, e.Tags),
@@ -193,6 +246,28 @@ func (_ *Subnet) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Subnet) error {
 		}
 
 		e.ID = response.Subnet.SubnetId
+	} else if !shared && changes.IPv6CIDR != nil {
+		klog.V(2).Infof("Associating IPv6 CIDR %q with Subnet %q", *ipv6CIDR, *e.ID)
+
+		request := &ec2.AssociateSubnetCidrBlockInput{
+			SubnetId:      e.ID,
+			Ipv6CidrBlock: ipv6CIDR,
+		}
+
+		if _, err := t.Cloud.EC2().AssociateSubnetCidrBlock(request); err != nil {
+			return fmt.Errorf("error associating IPv6 CIDR with subnet: %v", err)
+		}
+	}
+
+	if !shared && ipv6CIDR != nil && (a == nil || changes.IPv6CIDR != nil) {
+		request := &ec2.ModifySubnetAttributeInput{
+			SubnetId:                    e.ID,
+			AssignIpv6AddressOnCreation: &ec2.AttributeBooleanValue{Value: fi.Bool(true)},
+		}
+
+		if _, err := t.Cloud.EC2().ModifySubnetAttribute(request); err != nil {
+			return fmt.Errorf("error modifying subnet attribute: %v", err)
+		}
 	}
 
 	return t.AddAWSTags(*e.ID, e.Tags)
@@ -215,10 +290,12 @@ func subnetSlicesEqualIgnoreOrder(l, r []*Subnet) bool {
 }
 
 type terraformSubnet struct {
-	VPCID            *terraform.Literal `json:"vpc_id" cty:"vpc_id"`
-	CIDR             *string            `json:"cidr_block" cty:"cidr_block"`
-	AvailabilityZone *string            `json:"availability_zone" cty:"availability_zone"`
-	Tags             map[string]string  `json:"tags,omitempty" cty:"tags"`
+	VPCID                       *terraform.Literal `json:"vpc_id" cty:"vpc_id"`
+	CIDR                        *string            `json:"cidr_block" cty:"cidr_block"`
+	IPv6CIDR                    *terraform.Literal `json:"ipv6_cidr_block,omitempty" cty:"ipv6_cidr_block"`
+	AssignIPv6AddressOnCreation *bool              `json:"assign_ipv6_address_on_creation,omitempty" cty:"assign_ipv6_address_on_creation"`
+	AvailabilityZone            *string            `json:"availability_zone" cty:"availability_zone"`
+	Tags                        map[string]string  `json:"tags,omitempty" cty:"tags"`
 }
 
 func (_ *Subnet) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Subnet) error {
@@ -247,9 +324,43 @@ func (_ *Subnet) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Su
 		Tags:             e.Tags,
 	}
 
+	if e.IPv6CIDR != nil {
+		ipv6CIDR, err := e.terraformIPv6CIDR()
+		if err != nil {
+			return err
+		}
+		tf.IPv6CIDR = ipv6CIDR
+		tf.AssignIPv6AddressOnCreation = fi.Bool(true)
+	}
+
 	return t.RenderResource("aws_subnet", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *Subnet) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*Subnet)
+	return []*terraform.Import{{ResourceType: "aws_subnet", ResourceName: *e.Name, ID: fi.StringValue(a.ID)}}
+}
+
+// terraformIPv6CIDR returns the IPv6 CIDR of the subnet; subnet indexes into the
+// Amazon-provided /56 of a VPC managed by terraform are computed by terraform
+func (e *Subnet) terraformIPv6CIDR() (*terraform.Literal, error) {
+	if !utilsubnet.IsSubnetIndex(*e.IPv6CIDR) || fi.BoolValue(e.VPC.Shared) {
+		ipv6CIDR, err := e.resolveIPv6CIDR()
+		if err != nil {
+			return nil, err
+		}
+		return terraform.LiteralFromStringValue(*ipv6CIDR), nil
+	}
+
+	newSize, netNum, err := utilsubnet.ParseSubnetIndex(*e.IPv6CIDR)
+	if err != nil {
+		return nil, err
+	}
+	vpcCIDR := terraform.LiteralProperty("aws_vpc", *e.VPC.Name, "ipv6_cidr_block")
+	return terraform.LiteralFunctionExpression("cidrsubnet", vpcCIDR.Expr(), strconv.Itoa(newSize-56), strconv.FormatUint(netNum, 10)), nil
+}
+
 func (e *Subnet) TerraformLink() *terraform.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
@@ -265,18 +376,27 @@ func (e *Subnet) TerraformLink() *terraform.Literal {
 }
 
 type cloudformationSubnet struct {
-	VPCID            *cloudformation.Literal `json:"VpcId,omitempty"`
-	CIDR             *string                 `json:"CidrBlock,omitempty"`
-	AvailabilityZone *string                 `json:"AvailabilityZone,omitempty"`
-	Tags             []cloudformationTag     `json:"Tags,omitempty"`
+	VPCID                       *cloudformation.Literal `json:"VpcId,omitempty"`
+	CIDR                        *string                 `json:"CidrBlock,omitempty"`
+	IPv6CIDR                    *cloudformation.Literal `json:"Ipv6CidrBlock,omitempty"`
+	AssignIPv6AddressOnCreation *bool                   `json:"AssignIpv6AddressOnCreation,omitempty"`
+	AvailabilityZone            *string                 `json:"AvailabilityZone,omitempty"`
+	Tags                        []cloudformationTag     `json:"Tags,omitempty"`
 }
 
 func (_ *Subnet) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *Subnet) error {
+	if fi.StringValue(e.ShortName) != "" {
+		name := fi.StringValue(e.ShortName)
+		if err := t.AddOutputVariable("subnet_"+name+"_id", e.CloudformationLink()); err != nil {
+			return err
+		}
+	}
+
 	shared := fi.BoolValue(e.Shared)
 	if shared {
 		// Not cloudformation owned / managed
 		// We won't apply changes, but our validation (kops update) will still warn
-		return nil
+		return t.AddOutputVariableArray("subnet_ids", cloudformation.LiteralString(*e.ID))
 	}
 
 	cf := &cloudformationSubnet{
@@ -286,7 +406,50 @@ func (_ *Subnet) RenderCloudformation(t *cloudformation.CloudformationTarget, a,
 		Tags:             buildCloudformationTags(e.Tags),
 	}
 
-	return t.RenderResource("AWS::EC2::Subnet", *e.Name, cf)
+	if e.IPv6CIDR != nil {
+		ipv6CIDR, err := e.cloudformationIPv6CIDR()
+		if err != nil {
+			return err
+		}
+		cf.IPv6CIDR = ipv6CIDR
+		cf.AssignIPv6AddressOnCreation = fi.Bool(true)
+	}
+
+	if err := t.RenderResource("AWS::EC2::Subnet", *e.Name, cf); err != nil {
+		return err
+	}
+
+	if e.IPv6CIDR != nil && !fi.BoolValue(e.VPC.Shared) && fi.BoolValue(e.VPC.AmazonIPv6) {
+		// The IPv6 CIDR of the subnet must be within the IPv6 CIDR of the VPC, which is associated as a separate resource
+		if err := t.AddDependency("AWS::EC2::Subnet", *e.Name, cloudformation.Ref("AWS::EC2::VPCCidrBlock", *e.VPC.Name+"-ipv6")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cloudformationIPv6CIDR returns the IPv6 CIDR of the subnet; subnet indexes into the
+// Amazon-provided /56 of a VPC managed by cloudformation are computed by cloudformation
+func (e *Subnet) cloudformationIPv6CIDR() (*cloudformation.Literal, error) {
+	if !utilsubnet.IsSubnetIndex(*e.IPv6CIDR) || fi.BoolValue(e.VPC.Shared) {
+		ipv6CIDR, err := e.resolveIPv6CIDR()
+		if err != nil {
+			return nil, err
+		}
+		return cloudformation.LiteralString(*ipv6CIDR), nil
+	}
+
+	newSize, netNum, err := utilsubnet.ParseSubnetIndex(*e.IPv6CIDR)
+	if err != nil {
+		return nil, err
+	}
+	// Fn::Cidr can allocate at most 256 CIDR blocks
+	if netNum >= 256 {
+		return nil, fmt.Errorf("IPv6 CIDR %q of subnet %q is beyond the 256 subnets supported by cloudformation", *e.IPv6CIDR, fi.StringValue(e.Name))
+	}
+	vpcCIDR := cloudformation.Select(0, cloudformation.GetAtt("AWS::EC2::VPC", *e.VPC.Name, "Ipv6CidrBlocks"))
+	return cloudformation.Select(netNum, cloudformation.Cidr(vpcCIDR, netNum+1, 128-newSize)), nil
 }
 
 func (e *Subnet) CloudformationLink() *cloudformation.Literal {
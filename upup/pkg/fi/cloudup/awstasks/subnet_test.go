@@ -17,6 +17,7 @@ limitations under the License.
 package awstasks
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
@@ -137,6 +138,120 @@ func TestSubnetCreate(t *testing.T) {
 	}
 }
 
+func TestSubnetCreateIPv6(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	c := &mockec2.MockEC2{}
+	cloud.MockEC2 = c
+
+	// We define a function so we can rebuild the tasks, because we modify in-place when running
+	buildTasks := func() map[string]fi.Task {
+		vpc1 := &VPC{
+			Name:       s("vpc1"),
+			CIDR:       s("172.20.0.0/16"),
+			AmazonIPv6: fi.Bool(true),
+			Tags:       map[string]string{"Name": "vpc1"},
+		}
+		subnet1 := &Subnet{
+			Name:     s("subnet1"),
+			VPC:      vpc1,
+			CIDR:     s("172.20.1.0/24"),
+			IPv6CIDR: s("/64#1"),
+			Tags:     map[string]string{"Name": "subnet1"},
+		}
+
+		return map[string]fi.Task{
+			"subnet1": subnet1,
+			"vpc1":    vpc1,
+		}
+	}
+
+	{
+		allTasks := buildTasks()
+		vpc1 := allTasks["vpc1"].(*VPC)
+		subnet1 := allTasks["subnet1"].(*Subnet)
+
+		target := &awsup.AWSAPITarget{
+			Cloud: cloud,
+		}
+
+		context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+		if err != nil {
+			t.Fatalf("error building context: %v", err)
+		}
+		defer context.Close()
+
+		if err := context.RunTasks(testRunTasksOptions); err != nil {
+			t.Fatalf("unexpected error during Run: %v", err)
+		}
+
+		if fi.StringValue(vpc1.IPv6CIDR) != "2001:db8:0:100::/56" {
+			t.Fatalf("unexpected VPC IPv6 CIDR %q", fi.StringValue(vpc1.IPv6CIDR))
+		}
+
+		actual := c.FindSubnet(*subnet1.ID)
+		if actual == nil {
+			t.Fatalf("Subnet created but then not found")
+		}
+		if len(actual.Ipv6CidrBlockAssociationSet) != 1 || aws.StringValue(actual.Ipv6CidrBlockAssociationSet[0].Ipv6CidrBlock) != "2001:db8:0:101::/64" {
+			t.Fatalf("unexpected Subnet IPv6 CIDRs: %v", actual.Ipv6CidrBlockAssociationSet)
+		}
+		if !aws.BoolValue(actual.AssignIpv6AddressOnCreation) {
+			t.Fatalf("expected Subnet to assign IPv6 addresses on creation")
+		}
+	}
+
+	{
+		allTasks := buildTasks()
+		checkNoChanges(t, cloud, allTasks)
+	}
+}
+
+func TestSubnetCloudformationIPv6CIDR(t *testing.T) {
+	grid := []struct {
+		Subnet   *Subnet
+		Expected string
+	}{
+		{
+			Subnet: &Subnet{
+				Name:     s("subnet1"),
+				VPC:      &VPC{Name: s("vpc1"), AmazonIPv6: fi.Bool(true)},
+				IPv6CIDR: s("/64#3"),
+			},
+			Expected: `{"Fn::Select":[3,{"Fn::Cidr":[{"Fn::Select":[0,{"Fn::GetAtt":["AWSEC2VPCvpc1","Ipv6CidrBlocks"]}]},4,64]}]}`,
+		},
+		{
+			Subnet: &Subnet{
+				Name:     s("subnet1"),
+				VPC:      &VPC{Name: s("vpc1"), Shared: fi.Bool(true), IPv6CIDR: s("2001:db8:0:100::/56")},
+				IPv6CIDR: s("/64#3"),
+			},
+			Expected: `"2001:db8:0:103::/64"`,
+		},
+		{
+			Subnet: &Subnet{
+				Name:     s("subnet1"),
+				VPC:      &VPC{Name: s("vpc1"), AmazonIPv6: fi.Bool(true)},
+				IPv6CIDR: s("2001:db8:0:105::/64"),
+			},
+			Expected: `"2001:db8:0:105::/64"`,
+		},
+	}
+
+	for _, g := range grid {
+		literal, err := g.Subnet.cloudformationIPv6CIDR()
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", fi.StringValue(g.Subnet.IPv6CIDR), err)
+		}
+		actual, err := json.Marshal(literal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != g.Expected {
+			t.Errorf("unexpected IPv6 CIDR for %q: %s, expected %s", fi.StringValue(g.Subnet.IPv6CIDR), actual, g.Expected)
+		}
+	}
+}
+
 func TestSharedSubnetCreateDoesNotCreateNew(t *testing.T) {
 	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
 	c := &mockec2.MockEC2{}
@@ -244,6 +244,12 @@ func (_ *TargetGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, change
 	return t.RenderResource("aws_lb_target_group", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *TargetGroup) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*TargetGroup)
+	return []*terraform.Import{{ResourceType: "aws_lb_target_group", ResourceName: *e.Name, ID: fi.StringValue(a.ARN)}}
+}
+
 func (e *TargetGroup) TerraformLink(params ...string) *terraform.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
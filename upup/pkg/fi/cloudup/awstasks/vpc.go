@@ -18,6 +18,7 @@ package awstasks
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -49,6 +50,11 @@ type VPC struct {
 	// associated with the VPC; any other CIDR blocks should be disassociated.
 	// The associations themselves are created through the VPCCIDRBlock awstask.
 	AssociateExtraCIDRBlocks []string
+
+	// AmazonIPv6 is set if the VPC should be assigned an Amazon-provided IPv6 CIDR block
+	AmazonIPv6 *bool
+	// IPv6CIDR is the IPv6 CIDR block of the VPC; it is allocated by AWS unless the VPC is shared
+	IPv6CIDR *string
 }
 
 var _ fi.CompareWithID = &VPC{}
@@ -88,6 +94,18 @@ func (e *VPC) Find(c *fi.Context) (*VPC, error) {
 		Tags: intersectTags(vpc.Tags, e.Tags),
 	}
 
+	for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState == nil || fi.StringValue(association.Ipv6CidrBlockState.State) != ec2.VpcCidrBlockStateCodeAssociated {
+			continue
+		}
+		if fi.StringValue(association.Ipv6Pool) == "Amazon" {
+			actual.AmazonIPv6 = fi.Bool(true)
+		}
+		if actual.IPv6CIDR == nil {
+			actual.IPv6CIDR = association.Ipv6CidrBlock
+		}
+	}
+
 	klog.V(4).Infof("found matching VPC %v", actual)
 
 	if actual.ID != nil {
@@ -116,6 +134,14 @@ func (e *VPC) Find(c *fi.Context) (*VPC, error) {
 	actual.Lifecycle = e.Lifecycle
 	actual.Name = e.Name // Name is part of Tags
 	actual.AssociateExtraCIDRBlocks = e.AssociateExtraCIDRBlocks
+	if e.IPv6CIDR == nil {
+		// The IPv6 CIDR is allocated by AWS
+		e.IPv6CIDR = actual.IPv6CIDR
+	}
+	if fi.BoolValue(e.Shared) {
+		// We don't manage the IPv6 CIDR of shared VPCs
+		actual.AmazonIPv6 = e.AmazonIPv6
+	}
 
 	return actual, nil
 }
@@ -132,6 +158,9 @@ func (s *VPC) CheckChanges(a, e, changes *VPC) error {
 			// TODO: Do we want to destroy & recreate the VPC?
 			return fi.FieldIsImmutable(e.CIDR, a.CIDR, field.NewPath("CIDR"))
 		}
+		if changes.IPv6CIDR != nil && a.IPv6CIDR != nil {
+			return fi.FieldIsImmutable(e.IPv6CIDR, a.IPv6CIDR, field.NewPath("IPv6CIDR"))
+		}
 	}
 	return nil
 }
@@ -162,8 +191,9 @@ func (_ *VPC) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *VPC) error {
 		klog.V(2).Infof("Creating VPC with CIDR: %q", *e.CIDR)
 
 		request := &ec2.CreateVpcInput{
-			CidrBlock:         e.CIDR,
-			TagSpecifications: awsup.EC2TagSpecification(ec2.ResourceTypeVpc, e.Tags),
+			CidrBlock:                   e.CIDR,
+			AmazonProvidedIpv6CidrBlock: e.AmazonIPv6,
+			TagSpecifications:           awsup.EC2TagSpecification(ec2.ResourceTypeVpc, e.Tags),
 		}
 
 		response, err := t.Cloud.EC2().CreateVpc(request)
@@ -172,6 +202,25 @@ func (_ *VPC) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *VPC) error {
 		}
 
 		e.ID = response.Vpc.VpcId
+	} else if !shared && changes.AmazonIPv6 != nil && fi.BoolValue(e.AmazonIPv6) {
+		klog.V(2).Infof("Associating Amazon-provided IPv6 CIDR with VPC %q", fi.StringValue(e.ID))
+
+		request := &ec2.AssociateVpcCidrBlockInput{
+			VpcId:                       e.ID,
+			AmazonProvidedIpv6CidrBlock: aws.Bool(true),
+		}
+
+		if _, err := t.Cloud.EC2().AssociateVpcCidrBlock(request); err != nil {
+			return fmt.Errorf("error associating IPv6 CIDR with VPC: %v", err)
+		}
+	}
+
+	if fi.BoolValue(e.AmazonIPv6) && e.IPv6CIDR == nil {
+		ipv6CIDR, err := waitForVPCIPv6CIDR(t.Cloud, e.ID)
+		if err != nil {
+			return err
+		}
+		e.IPv6CIDR = ipv6CIDR
 	}
 
 	if changes.EnableDNSSupport != nil {
@@ -248,8 +297,28 @@ func (e *VPC) FindDeletions(c *fi.Context) ([]fi.Deletion, error) {
 	return removals, nil
 }
 
+// waitForVPCIPv6CIDR waits for AWS to allocate the Amazon-provided IPv6 CIDR of a VPC
+func waitForVPCIPv6CIDR(cloud awsup.AWSCloud, vpcID *string) (*string, error) {
+	for attempt := 0; attempt < 30; attempt++ {
+		response, err := cloud.EC2().DescribeVpcs(&ec2.DescribeVpcsInput{VpcIds: []*string{vpcID}})
+		if err != nil {
+			return nil, fmt.Errorf("error describing VPC %q: %v", fi.StringValue(vpcID), err)
+		}
+		for _, vpc := range response.Vpcs {
+			for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+				if association.Ipv6CidrBlockState != nil && fi.StringValue(association.Ipv6CidrBlockState.State) == ec2.VpcCidrBlockStateCodeAssociated {
+					return association.Ipv6CidrBlock, nil
+				}
+			}
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return nil, fmt.Errorf("timeout waiting for IPv6 CIDR to be associated with VPC %q", fi.StringValue(vpcID))
+}
+
 type terraformVPC struct {
 	CIDR               *string           `json:"cidr_block,omitempty" cty:"cidr_block"`
+	AmazonIPv6         *bool             `json:"assign_generated_ipv6_cidr_block,omitempty" cty:"assign_generated_ipv6_cidr_block"`
 	EnableDNSHostnames *bool             `json:"enable_dns_hostnames,omitempty" cty:"enable_dns_hostnames"`
 	EnableDNSSupport   *bool             `json:"enable_dns_support,omitempty" cty:"enable_dns_support"`
 	Tags               map[string]string `json:"tags,omitempty" cty:"tags"`
@@ -274,6 +343,7 @@ func (_ *VPC) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPC)
 
 	tf := &terraformVPC{
 		CIDR:               e.CIDR,
+		AmazonIPv6:         e.AmazonIPv6,
 		Tags:               e.Tags,
 		EnableDNSHostnames: e.EnableDNSHostnames,
 		EnableDNSSupport:   e.EnableDNSSupport,
@@ -282,6 +352,12 @@ func (_ *VPC) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPC)
 	return t.RenderResource("aws_vpc", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *VPC) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*VPC)
+	return []*terraform.Import{{ResourceType: "aws_vpc", ResourceName: *e.Name, ID: fi.StringValue(a.ID)}}
+}
+
 func (e *VPC) TerraformLink() *terraform.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
@@ -304,6 +380,10 @@ type cloudformationVPC struct {
 }
 
 func (_ *VPC) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *VPC) error {
+	if err := t.AddOutputVariable("vpc_id", e.CloudformationLink()); err != nil {
+		return err
+	}
+
 	shared := fi.BoolValue(e.Shared)
 	if shared {
 		// Not cloudformation owned / managed
@@ -311,6 +391,10 @@ func (_ *VPC) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e,
 		return nil
 	}
 
+	if err := t.AddOutputVariable("vpc_cidr_block", cloudformation.GetAtt("AWS::EC2::VPC", *e.Name, "CidrBlock")); err != nil {
+		return err
+	}
+
 	tf := &cloudformationVPC{
 		CidrBlock:          e.CIDR,
 		EnableDnsHostnames: e.EnableDNSHostnames,
@@ -318,7 +402,27 @@ func (_ *VPC) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e,
 		Tags:               buildCloudformationTags(e.Tags),
 	}
 
-	return t.RenderResource("AWS::EC2::VPC", *e.Name, tf)
+	if err := t.RenderResource("AWS::EC2::VPC", *e.Name, tf); err != nil {
+		return err
+	}
+
+	if fi.BoolValue(e.AmazonIPv6) {
+		// IPv6 CIDRs can only be associated with the VPC as a separate resource
+		cf := &cloudformationVPCIPv6CIDRBlock{
+			VPCID:                       e.CloudformationLink(),
+			AmazonProvidedIpv6CidrBlock: e.AmazonIPv6,
+		}
+		if err := t.RenderResource("AWS::EC2::VPCCidrBlock", *e.Name+"-ipv6", cf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type cloudformationVPCIPv6CIDRBlock struct {
+	VPCID                       *cloudformation.Literal `json:"VpcId"`
+	AmazonProvidedIpv6CidrBlock *bool                   `json:"AmazonProvidedIpv6CidrBlock"`
 }
 
 func (e *VPC) CloudformationLink() *cloudformation.Literal {
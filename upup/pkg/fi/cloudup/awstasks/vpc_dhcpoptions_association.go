@@ -115,6 +115,16 @@ func (_ *VPCDHCPOptionsAssociation) RenderTerraform(t *terraform.TerraformTarget
 	return t.RenderResource("aws_vpc_dhcp_options_association", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *VPCDHCPOptionsAssociation) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*VPCDHCPOptionsAssociation)
+	// Find returns the options that the VPC is associated with, which may not be ours
+	if fi.StringValue(a.DHCPOptions.ID) != fi.StringValue(e.DHCPOptions.ID) {
+		return nil
+	}
+	return []*terraform.Import{{ResourceType: "aws_vpc_dhcp_options_association", ResourceName: *e.Name, ID: fi.StringValue(a.VPC.ID)}}
+}
+
 type cloudformationVPCDHCPOptionsAssociation struct {
 	VpcId         *cloudformation.Literal `json:"VpcId"`
 	DhcpOptionsId *cloudformation.Literal `json:"DhcpOptionsId"`
@@ -36,6 +36,9 @@ type VPCCIDRBlock struct {
 
 	// Shared is set if this is a shared VPC
 	Shared *bool
+
+	// associationID is the ID of the existing association, as found by Find
+	associationID *string
 }
 
 func (e *VPCCIDRBlock) Find(c *fi.Context) (*VPCCIDRBlock, error) {
@@ -48,21 +51,22 @@ func (e *VPCCIDRBlock) Find(c *fi.Context) (*VPCCIDRBlock, error) {
 		return nil, err
 	}
 
-	found := false
+	var association *ec2.VpcCidrBlockAssociation
 	for _, cba := range vpc.CidrBlockAssociationSet {
 		if fi.StringValue(cba.CidrBlock) == fi.StringValue(e.CIDRBlock) &&
 			cba.CidrBlockState != nil && fi.StringValue(cba.CidrBlockState.State) == ec2.VpcCidrBlockStateCodeAssociated {
-			found = true
+			association = cba
 			break
 		}
 	}
-	if !found {
+	if association == nil {
 		return nil, nil
 	}
 
 	actual := &VPCCIDRBlock{
-		CIDRBlock: e.CIDRBlock,
-		VPC:       &VPC{ID: vpc.VpcId},
+		CIDRBlock:     e.CIDRBlock,
+		VPC:           &VPC{ID: vpc.VpcId},
+		associationID: association.AssociationId,
 	}
 
 	// Prevent spurious changes
@@ -142,6 +146,13 @@ func (_ *VPCCIDRBlock) RenderTerraform(t *terraform.TerraformTarget, a, e, chang
 	return t.RenderResource("aws_vpc_ipv4_cidr_block_association", name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *VPCCIDRBlock) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*VPCCIDRBlock)
+	name := fmt.Sprintf("cidr-%v", *e.Name)
+	return []*terraform.Import{{ResourceType: "aws_vpc_ipv4_cidr_block_association", ResourceName: name, ID: fi.StringValue(a.associationID)}}
+}
+
 type cloudformationVPCCIDRBlock struct {
 	VPCID     *cloudformation.Literal `json:"VpcId"`
 	CIDRBlock *string                 `json:"CidrBlock"`
@@ -0,0 +1,374 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+)
+
+// VPCEndpoint is an AWS VPC endpoint, which reaches an AWS service from the VPC without going through
+// an internet or NAT gateway. Gateway endpoints are attached to route tables, interface endpoints
+// place network interfaces in subnets.
+// +kops:fitask
+type VPCEndpoint struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	ID  *string
+	VPC *VPC
+
+	// ServiceName is the full name of the service, e.g. com.amazonaws.us-east-1.s3
+	ServiceName *string
+	// VPCEndpointType is the type of the endpoint: Gateway or Interface
+	VPCEndpointType *string
+	// PrivateDNSEnabled associates a private hosted zone resolving the service to an interface endpoint
+	PrivateDNSEnabled *bool
+
+	// RouteTables are the route tables a gateway endpoint is attached to
+	RouteTables []*RouteTable
+	// Subnets are the subnets an interface endpoint places its network interfaces in, one per zone
+	Subnets []*Subnet
+	// SecurityGroups are the security groups of the network interfaces of an interface endpoint
+	SecurityGroups []*SecurityGroup
+
+	// Tags is a map of aws tags that are added to the VPCEndpoint
+	Tags map[string]string
+}
+
+// resourceTypeVPCEndpoint is the EC2 resource type of VPC endpoints, which is missing from the enum of the SDK
+const resourceTypeVPCEndpoint = "vpc-endpoint"
+
+var _ fi.CompareWithID = &VPCEndpoint{}
+
+func (e *VPCEndpoint) CompareWithID() *string {
+	return e.ID
+}
+
+// OrderRouteTablesById implements sort.Interface for []RouteTable, based on ID
+type OrderRouteTablesById []*RouteTable
+
+func (a OrderRouteTablesById) Len() int      { return len(a) }
+func (a OrderRouteTablesById) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a OrderRouteTablesById) Less(i, j int) bool {
+	return fi.StringValue(a[i].ID) < fi.StringValue(a[j].ID)
+}
+
+func (e *VPCEndpoint) Find(c *fi.Context) (*VPCEndpoint, error) {
+	cloud := c.Cloud.(awsup.AWSCloud)
+
+	request := &ec2.DescribeVpcEndpointsInput{}
+	if e.ID != nil {
+		request.VpcEndpointIds = []*string{e.ID}
+	} else {
+		if e.VPC == nil || e.VPC.ID == nil {
+			klog.V(4).Infof("VPC ID not set, so VPCEndpoint %q can't exist yet", fi.StringValue(e.Name))
+			return nil, nil
+		}
+		request.Filters = cloud.BuildFilters(e.Name)
+		request.Filters = append(request.Filters, awsup.NewEC2Filter("vpc-id", fi.StringValue(e.VPC.ID)))
+		request.Filters = append(request.Filters, awsup.NewEC2Filter("service-name", fi.StringValue(e.ServiceName)))
+	}
+
+	response, err := cloud.EC2().DescribeVpcEndpoints(request)
+	if err != nil {
+		return nil, fmt.Errorf("error listing VPCEndpoints: %v", err)
+	}
+
+	var endpoints []*ec2.VpcEndpoint
+	for _, endpoint := range response.VpcEndpoints {
+		switch strings.ToLower(aws.StringValue(endpoint.State)) {
+		case "deleting", "deleted", "failed", "rejected":
+			continue
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+	if len(endpoints) != 1 {
+		return nil, fmt.Errorf("found multiple VPCEndpoints matching %q", fi.StringValue(e.Name))
+	}
+	endpoint := endpoints[0]
+
+	actual := &VPCEndpoint{
+		ID:                endpoint.VpcEndpointId,
+		VPC:               &VPC{ID: endpoint.VpcId},
+		ServiceName:       endpoint.ServiceName,
+		VPCEndpointType:   endpoint.VpcEndpointType,
+		PrivateDNSEnabled: endpoint.PrivateDnsEnabled,
+		Tags:              intersectTags(endpoint.Tags, e.Tags),
+	}
+	for _, id := range endpoint.RouteTableIds {
+		actual.RouteTables = append(actual.RouteTables, &RouteTable{ID: id})
+	}
+	for _, id := range endpoint.SubnetIds {
+		actual.Subnets = append(actual.Subnets, &Subnet{ID: id})
+	}
+	for _, group := range endpoint.Groups {
+		actual.SecurityGroups = append(actual.SecurityGroups, &SecurityGroup{ID: group.GroupId})
+	}
+	actual.normalize()
+
+	if aws.StringValue(endpoint.VpcEndpointType) == ec2.VpcEndpointTypeGateway {
+		// Gateway endpoints don't support private DNS
+		actual.PrivateDNSEnabled = e.PrivateDNSEnabled
+	}
+
+	klog.V(2).Infof("found matching VPCEndpoint %q", aws.StringValue(actual.ID))
+	e.ID = actual.ID
+
+	// Prevent spurious changes
+	actual.Name = e.Name
+	actual.Lifecycle = e.Lifecycle
+
+	return actual, nil
+}
+
+func (e *VPCEndpoint) normalize() {
+	// We need to sort our arrays consistently, so we don't get spurious changes
+	sort.Stable(OrderRouteTablesById(e.RouteTables))
+	sort.Stable(OrderSubnetsById(e.Subnets))
+	sort.Stable(OrderSecurityGroupsById(e.SecurityGroups))
+}
+
+func (e *VPCEndpoint) Run(c *fi.Context) error {
+	e.normalize()
+
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (s *VPCEndpoint) CheckChanges(a, e, changes *VPCEndpoint) error {
+	if a == nil {
+		if e.VPC == nil {
+			return fi.RequiredField("VPC")
+		}
+		if e.ServiceName == nil {
+			return fi.RequiredField("ServiceName")
+		}
+		if e.VPCEndpointType == nil {
+			return fi.RequiredField("VPCEndpointType")
+		}
+	}
+	if a != nil {
+		if changes.VPC != nil && changes.VPC.ID != nil {
+			return fi.CannotChangeField("VPC")
+		}
+		if changes.ServiceName != nil {
+			return fi.CannotChangeField("ServiceName")
+		}
+		if changes.VPCEndpointType != nil {
+			return fi.CannotChangeField("VPCEndpointType")
+		}
+	}
+	return nil
+}
+
+func (_ *VPCEndpoint) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *VPCEndpoint) error {
+	var routeTableIDs, subnetIDs, securityGroupIDs []*string
+	for _, rt := range e.RouteTables {
+		routeTableIDs = append(routeTableIDs, rt.ID)
+	}
+	for _, subnet := range e.Subnets {
+		subnetIDs = append(subnetIDs, subnet.ID)
+	}
+	for _, sg := range e.SecurityGroups {
+		securityGroupIDs = append(securityGroupIDs, sg.ID)
+	}
+
+	if a == nil {
+		klog.V(2).Infof("Creating VPCEndpoint %q for service %q", fi.StringValue(e.Name), fi.StringValue(e.ServiceName))
+
+		request := &ec2.CreateVpcEndpointInput{
+			VpcId:             e.VPC.ID,
+			ServiceName:       e.ServiceName,
+			VpcEndpointType:   e.VPCEndpointType,
+			TagSpecifications: awsup.EC2TagSpecification(resourceTypeVPCEndpoint, e.Tags),
+		}
+		if fi.StringValue(e.VPCEndpointType) == ec2.VpcEndpointTypeGateway {
+			request.RouteTableIds = routeTableIDs
+		} else {
+			request.SubnetIds = subnetIDs
+			request.SecurityGroupIds = securityGroupIDs
+			request.PrivateDnsEnabled = e.PrivateDNSEnabled
+		}
+
+		response, err := t.Cloud.EC2().CreateVpcEndpoint(request)
+		if err != nil {
+			return fmt.Errorf("error creating VPCEndpoint: %v", err)
+		}
+
+		e.ID = response.VpcEndpoint.VpcEndpointId
+		return nil
+	}
+
+	if changes.RouteTables != nil || changes.Subnets != nil || changes.SecurityGroups != nil || changes.PrivateDNSEnabled != nil {
+		request := &ec2.ModifyVpcEndpointInput{
+			VpcEndpointId: a.ID,
+		}
+		if changes.RouteTables != nil {
+			request.AddRouteTableIds, request.RemoveRouteTableIds = diffIDs(a.routeTableIDs(), routeTableIDs)
+		}
+		if changes.Subnets != nil {
+			request.AddSubnetIds, request.RemoveSubnetIds = diffIDs(a.subnetIDs(), subnetIDs)
+		}
+		if changes.SecurityGroups != nil {
+			request.AddSecurityGroupIds, request.RemoveSecurityGroupIds = diffIDs(a.securityGroupIDs(), securityGroupIDs)
+		}
+		if changes.PrivateDNSEnabled != nil {
+			request.PrivateDnsEnabled = e.PrivateDNSEnabled
+		}
+
+		klog.V(2).Infof("Updating VPCEndpoint %q", fi.StringValue(a.ID))
+		if _, err := t.Cloud.EC2().ModifyVpcEndpoint(request); err != nil {
+			return fmt.Errorf("error updating VPCEndpoint %q: %v", fi.StringValue(a.ID), err)
+		}
+	}
+
+	return t.AddAWSTags(fi.StringValue(a.ID), e.Tags)
+}
+
+func (e *VPCEndpoint) routeTableIDs() []*string {
+	var ids []*string
+	for _, rt := range e.RouteTables {
+		ids = append(ids, rt.ID)
+	}
+	return ids
+}
+
+func (e *VPCEndpoint) subnetIDs() []*string {
+	var ids []*string
+	for _, subnet := range e.Subnets {
+		ids = append(ids, subnet.ID)
+	}
+	return ids
+}
+
+func (e *VPCEndpoint) securityGroupIDs() []*string {
+	var ids []*string
+	for _, sg := range e.SecurityGroups {
+		ids = append(ids, sg.ID)
+	}
+	return ids
+}
+
+// diffIDs returns the IDs to add and to remove to go from the actual to the expected IDs
+func diffIDs(actual, expected []*string) (add []*string, remove []*string) {
+	actualIDs := sets.NewString(aws.StringValueSlice(actual)...)
+	expectedIDs := sets.NewString(aws.StringValueSlice(expected)...)
+	for _, id := range expectedIDs.Difference(actualIDs).List() {
+		add = append(add, aws.String(id))
+	}
+	for _, id := range actualIDs.Difference(expectedIDs).List() {
+		remove = append(remove, aws.String(id))
+	}
+	return add, remove
+}
+
+type terraformVPCEndpoint struct {
+	VPCID             *terraform.Literal   `json:"vpc_id" cty:"vpc_id"`
+	ServiceName       *string              `json:"service_name" cty:"service_name"`
+	VPCEndpointType   *string              `json:"vpc_endpoint_type" cty:"vpc_endpoint_type"`
+	PrivateDNSEnabled *bool                `json:"private_dns_enabled,omitempty" cty:"private_dns_enabled"`
+	RouteTableIDs     []*terraform.Literal `json:"route_table_ids,omitempty" cty:"route_table_ids"`
+	SubnetIDs         []*terraform.Literal `json:"subnet_ids,omitempty" cty:"subnet_ids"`
+	SecurityGroupIDs  []*terraform.Literal `json:"security_group_ids,omitempty" cty:"security_group_ids"`
+	Tags              map[string]string    `json:"tags,omitempty" cty:"tags"`
+}
+
+func (_ *VPCEndpoint) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPCEndpoint) error {
+	tf := &terraformVPCEndpoint{
+		VPCID:           e.VPC.TerraformLink(),
+		ServiceName:     e.ServiceName,
+		VPCEndpointType: e.VPCEndpointType,
+		Tags:            e.Tags,
+	}
+	if fi.StringValue(e.VPCEndpointType) == ec2.VpcEndpointTypeGateway {
+		for _, rt := range e.RouteTables {
+			tf.RouteTableIDs = append(tf.RouteTableIDs, rt.TerraformLink())
+		}
+	} else {
+		tf.PrivateDNSEnabled = e.PrivateDNSEnabled
+		for _, subnet := range e.Subnets {
+			tf.SubnetIDs = append(tf.SubnetIDs, subnet.TerraformLink())
+		}
+		for _, sg := range e.SecurityGroups {
+			tf.SecurityGroupIDs = append(tf.SecurityGroupIDs, sg.TerraformLink())
+		}
+	}
+
+	return t.RenderResource("aws_vpc_endpoint", fi.StringValue(e.Name), tf)
+}
+
+// TerraformImport implements terraform.Importable
+func (e *VPCEndpoint) TerraformImport(actual fi.Task) []*terraform.Import {
+	a := actual.(*VPCEndpoint)
+	return []*terraform.Import{{ResourceType: "aws_vpc_endpoint", ResourceName: fi.StringValue(e.Name), ID: fi.StringValue(a.ID)}}
+}
+
+func (e *VPCEndpoint) TerraformLink() *terraform.Literal {
+	return terraform.LiteralProperty("aws_vpc_endpoint", fi.StringValue(e.Name), "id")
+}
+
+type cloudformationVPCEndpoint struct {
+	VPCID             *cloudformation.Literal   `json:"VpcId"`
+	ServiceName       *string                   `json:"ServiceName"`
+	VPCEndpointType   *string                   `json:"VpcEndpointType"`
+	PrivateDNSEnabled *bool                     `json:"PrivateDnsEnabled,omitempty"`
+	RouteTableIDs     []*cloudformation.Literal `json:"RouteTableIds,omitempty"`
+	SubnetIDs         []*cloudformation.Literal `json:"SubnetIds,omitempty"`
+	SecurityGroupIDs  []*cloudformation.Literal `json:"SecurityGroupIds,omitempty"`
+}
+
+// RenderCloudformation renders the endpoint as an AWS::EC2::VPCEndpoint, which does not support tags
+func (_ *VPCEndpoint) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *VPCEndpoint) error {
+	cf := &cloudformationVPCEndpoint{
+		VPCID:           e.VPC.CloudformationLink(),
+		ServiceName:     e.ServiceName,
+		VPCEndpointType: e.VPCEndpointType,
+	}
+	if fi.StringValue(e.VPCEndpointType) == ec2.VpcEndpointTypeGateway {
+		for _, rt := range e.RouteTables {
+			cf.RouteTableIDs = append(cf.RouteTableIDs, rt.CloudformationLink())
+		}
+	} else {
+		cf.PrivateDNSEnabled = e.PrivateDNSEnabled
+		for _, subnet := range e.Subnets {
+			cf.SubnetIDs = append(cf.SubnetIDs, subnet.CloudformationLink())
+		}
+		for _, sg := range e.SecurityGroups {
+			cf.SecurityGroupIDs = append(cf.SecurityGroupIDs, sg.CloudformationLink())
+		}
+	}
+
+	return t.RenderResource("AWS::EC2::VPCEndpoint", fi.StringValue(e.Name), cf)
+}
+
+func (e *VPCEndpoint) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::EC2::VPCEndpoint", fi.StringValue(e.Name))
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"testing"
+
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+func TestVPCEndpointCreate(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	c := &mockec2.MockEC2{}
+	cloud.MockEC2 = c
+
+	// We define a function so we can rebuild the tasks, because we modify in-place when running
+	buildTasks := func() map[string]fi.Task {
+		vpc1 := &VPC{
+			Name: s("vpc1"),
+			CIDR: s("172.21.0.0/16"),
+			Tags: map[string]string{"Name": "vpc1"},
+		}
+		rt1 := &RouteTable{
+			Name: s("rt1"),
+			VPC:  vpc1,
+			Tags: map[string]string{"Name": "rt1"},
+		}
+		endpoint1 := &VPCEndpoint{
+			Name:            s("s3.example.com"),
+			VPC:             vpc1,
+			ServiceName:     s("com.amazonaws.us-east-1.s3"),
+			VPCEndpointType: s("Gateway"),
+			RouteTables:     []*RouteTable{rt1},
+			Tags:            map[string]string{"Name": "s3.example.com"},
+		}
+		return map[string]fi.Task{
+			"vpc1":      vpc1,
+			"rt1":       rt1,
+			"endpoint1": endpoint1,
+		}
+	}
+
+	{
+		allTasks := buildTasks()
+		endpoint1 := allTasks["endpoint1"].(*VPCEndpoint)
+		rt1 := allTasks["rt1"].(*RouteTable)
+
+		target := &awsup.AWSAPITarget{
+			Cloud: cloud,
+		}
+
+		context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+		if err != nil {
+			t.Fatalf("error building context: %v", err)
+		}
+		defer context.Close()
+
+		if err := context.RunTasks(testRunTasksOptions); err != nil {
+			t.Fatalf("unexpected error during Run: %v", err)
+		}
+
+		if fi.StringValue(endpoint1.ID) == "" {
+			t.Fatalf("ID not set after create")
+		}
+
+		if len(c.VpcEndpoints) != 1 {
+			t.Fatalf("Expected exactly one VpcEndpoint; found %v", c.VpcEndpoints)
+		}
+
+		actual := c.VpcEndpoints[fi.StringValue(endpoint1.ID)]
+		if len(actual.RouteTableIds) != 1 || fi.StringValue(actual.RouteTableIds[0]) != fi.StringValue(rt1.ID) {
+			t.Fatalf("Unexpected route tables: expected=%v actual=%v", fi.StringValue(rt1.ID), actual.RouteTableIds)
+		}
+	}
+
+	{
+		allTasks := buildTasks()
+
+		checkNoChanges(t, cloud, allTasks)
+	}
+}
+
+func TestVPCEndpointTerraformRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &VPCEndpoint{
+				Name:              fi.String("ecr.api.example.com"),
+				VPC:               &VPC{Name: fi.String("example.com")},
+				ServiceName:       fi.String("com.amazonaws.eu-west-2.ecr.api"),
+				VPCEndpointType:   fi.String("Interface"),
+				PrivateDNSEnabled: fi.Bool(true),
+				Subnets:           []*Subnet{{Name: fi.String("us-test-1a.example.com")}},
+				SecurityGroups:    []*SecurityGroup{{Name: fi.String("vpc-endpoints.example.com")}},
+				Tags: map[string]string{
+					"KubernetesCluster": "example.com",
+				},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_vpc_endpoint" "ecr-api-example-com" {
+  private_dns_enabled = true
+  security_group_ids  = [aws_security_group.vpc-endpoints-example-com.id]
+  service_name        = "com.amazonaws.eu-west-2.ecr.api"
+  subnet_ids          = [aws_subnet.us-test-1a-example-com.id]
+  tags = {
+    "KubernetesCluster" = "example.com"
+  }
+  vpc_endpoint_type = "Interface"
+  vpc_id            = aws_vpc.example-com.id
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.39.0"
+    }
+  }
+}
+`,
+		},
+	}
+	doRenderTests(t, "RenderTerraform", cases)
+}
+
+func TestVPCEndpointCloudformationRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &VPCEndpoint{
+				Name:            fi.String("s3.example.com"),
+				VPC:             &VPC{Name: fi.String("example.com")},
+				ServiceName:     fi.String("com.amazonaws.eu-west-2.s3"),
+				VPCEndpointType: fi.String("Gateway"),
+				RouteTables:     []*RouteTable{{Name: fi.String("example.com")}},
+			},
+			Expected: `{
+  "Resources": {
+    "AWSEC2VPCEndpoints3examplecom": {
+      "Type": "AWS::EC2::VPCEndpoint",
+      "Properties": {
+        "VpcId": {
+          "Ref": "AWSEC2VPCexamplecom"
+        },
+        "ServiceName": "com.amazonaws.eu-west-2.s3",
+        "VpcEndpointType": "Gateway",
+        "RouteTableIds": [
+          {
+            "Ref": "AWSEC2RouteTableexamplecom"
+          }
+        ]
+      }
+    }
+  }
+}`,
+		},
+	}
+	doRenderTests(t, "RenderCloudformation", cases)
+}
@@ -31,6 +31,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elb"
@@ -90,6 +92,10 @@ const TagNameClusterOwnershipPrefix = "kubernetes.io/cluster/"
 
 const tagNameDetachedInstance = "kops.k8s.io/detached-from-asg"
 
+// WarmPoolLifecycleHookName is the name of the launch lifecycle hook completed by nodeup
+// once an instance of an autoscaling group with a warm pool is configured
+const WarmPoolLifecycleHookName = "kops-warmpool"
+
 const (
 	WellKnownAccountAmazonLinux2 = "137112412989"
 	WellKnownAccountCentOS       = "125523088429"
@@ -106,6 +112,7 @@ type AWSCloud interface {
 	fi.Cloud
 
 	CloudFormation() *cloudformation.CloudFormation
+	CloudWatchLogs() cloudwatchlogsiface.CloudWatchLogsAPI
 	EC2() ec2iface.EC2API
 	IAM() iamiface.IAMAPI
 	ELB() elbiface.ELBAPI
@@ -173,6 +180,7 @@ type AWSCloud interface {
 
 type awsCloudImplementation struct {
 	cf          *cloudformation.CloudFormation
+	logs        *cloudwatchlogs.CloudWatchLogs
 	ec2         *ec2.EC2
 	iam         *iam.IAM
 	elb         *elb.ELB
@@ -283,6 +291,14 @@ func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
 		c.elbv2.Handlers.Send.PushFront(requestLogger)
 		c.addHandlers(region, &c.elbv2.Handlers)
 
+		sess, err = session.NewSession(config)
+		if err != nil {
+			return c, err
+		}
+		c.logs = cloudwatchlogs.New(sess, config)
+		c.logs.Handlers.Send.PushFront(requestLogger)
+		c.addHandlers(region, &c.logs.Handlers)
+
 		sess, err = session.NewSession(config)
 		if err != nil {
 			return c, err
@@ -1558,6 +1574,10 @@ func (c *awsCloudImplementation) CloudFormation() *cloudformation.CloudFormation
 	return c.cf
 }
 
+func (c *awsCloudImplementation) CloudWatchLogs() cloudwatchlogsiface.CloudWatchLogsAPI {
+	return c.logs
+}
+
 func (c *awsCloudImplementation) EC2() ec2iface.EC2API {
 	return c.ec2
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
@@ -74,6 +75,7 @@ func BuildMockAWSCloud(region string, zoneLetters string) *MockAWSCloud {
 type MockCloud struct {
 	MockAutoscaling    autoscalingiface.AutoScalingAPI
 	MockCloudFormation *cloudformation.CloudFormation
+	MockCloudWatchLogs cloudwatchlogsiface.CloudWatchLogsAPI
 	MockEC2            ec2iface.EC2API
 	MockIAM            iamiface.IAMAPI
 	MockRoute53        route53iface.Route53API
@@ -212,6 +214,13 @@ func (c *MockAWSCloud) CloudFormation() *cloudformation.CloudFormation {
 	return c.MockCloudFormation
 }
 
+func (c *MockAWSCloud) CloudWatchLogs() cloudwatchlogsiface.CloudWatchLogsAPI {
+	if c.MockCloudWatchLogs == nil {
+		klog.Fatalf("MockAWSCloud MockCloudWatchLogs not set")
+	}
+	return c.MockCloudWatchLogs
+}
+
 func (c *MockAWSCloud) EC2() ec2iface.EC2API {
 	if c.MockEC2 == nil {
 		klog.Fatalf("MockAWSCloud MockEC2 not set")
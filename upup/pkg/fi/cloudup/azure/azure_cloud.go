@@ -57,23 +57,27 @@ type AzureCloud interface {
 	NetworkInterface() NetworkInterfacesClient
 	LoadBalancer() LoadBalancersClient
 	PublicIPAddress() PublicIPAddressesClient
+	NetworkSecurityGroup() NetworkSecurityGroupsClient
+	ManagedIdentity() ManagedIdentitiesClient
 }
 
 type azureCloudImplementation struct {
-	subscriptionID          string
-	location                string
-	tags                    map[string]string
-	resourceGroupsClient    ResourceGroupsClient
-	vnetsClient             VirtualNetworksClient
-	subnetsClient           SubnetsClient
-	routeTablesClient       RouteTablesClient
-	vmscaleSetsClient       VMScaleSetsClient
-	vmscaleSetVMsClient     VMScaleSetVMsClient
-	disksClient             DisksClient
-	roleAssignmentsClient   RoleAssignmentsClient
-	networkInterfacesClient NetworkInterfacesClient
-	loadBalancersClient     LoadBalancersClient
-	publicIPAddressesClient PublicIPAddressesClient
+	subscriptionID              string
+	location                    string
+	tags                        map[string]string
+	resourceGroupsClient        ResourceGroupsClient
+	vnetsClient                 VirtualNetworksClient
+	subnetsClient               SubnetsClient
+	routeTablesClient           RouteTablesClient
+	vmscaleSetsClient           VMScaleSetsClient
+	vmscaleSetVMsClient         VMScaleSetVMsClient
+	disksClient                 DisksClient
+	roleAssignmentsClient       RoleAssignmentsClient
+	networkInterfacesClient     NetworkInterfacesClient
+	loadBalancersClient         LoadBalancersClient
+	publicIPAddressesClient     PublicIPAddressesClient
+	networkSecurityGroupsClient NetworkSecurityGroupsClient
+	managedIdentitiesClient     ManagedIdentitiesClient
 }
 
 var _ fi.Cloud = &azureCloudImplementation{}
@@ -86,20 +90,22 @@ func NewAzureCloud(subscriptionID, location string, tags map[string]string) (Azu
 	}
 
 	return &azureCloudImplementation{
-		subscriptionID:          subscriptionID,
-		location:                location,
-		tags:                    tags,
-		resourceGroupsClient:    newResourceGroupsClientImpl(subscriptionID, authorizer),
-		vnetsClient:             newVirtualNetworksClientImpl(subscriptionID, authorizer),
-		subnetsClient:           newSubnetsClientImpl(subscriptionID, authorizer),
-		routeTablesClient:       newRouteTablesClientImpl(subscriptionID, authorizer),
-		vmscaleSetsClient:       newVMScaleSetsClientImpl(subscriptionID, authorizer),
-		vmscaleSetVMsClient:     newVMScaleSetVMsClientImpl(subscriptionID, authorizer),
-		disksClient:             newDisksClientImpl(subscriptionID, authorizer),
-		roleAssignmentsClient:   newRoleAssignmentsClientImpl(subscriptionID, authorizer),
-		networkInterfacesClient: newNetworkInterfacesClientImpl(subscriptionID, authorizer),
-		loadBalancersClient:     newLoadBalancersClientImpl(subscriptionID, authorizer),
-		publicIPAddressesClient: newPublicIPAddressesClientImpl(subscriptionID, authorizer),
+		subscriptionID:              subscriptionID,
+		location:                    location,
+		tags:                        tags,
+		resourceGroupsClient:        newResourceGroupsClientImpl(subscriptionID, authorizer),
+		vnetsClient:                 newVirtualNetworksClientImpl(subscriptionID, authorizer),
+		subnetsClient:               newSubnetsClientImpl(subscriptionID, authorizer),
+		routeTablesClient:           newRouteTablesClientImpl(subscriptionID, authorizer),
+		vmscaleSetsClient:           newVMScaleSetsClientImpl(subscriptionID, authorizer),
+		vmscaleSetVMsClient:         newVMScaleSetVMsClientImpl(subscriptionID, authorizer),
+		disksClient:                 newDisksClientImpl(subscriptionID, authorizer),
+		roleAssignmentsClient:       newRoleAssignmentsClientImpl(subscriptionID, authorizer),
+		networkInterfacesClient:     newNetworkInterfacesClientImpl(subscriptionID, authorizer),
+		loadBalancersClient:         newLoadBalancersClientImpl(subscriptionID, authorizer),
+		publicIPAddressesClient:     newPublicIPAddressesClientImpl(subscriptionID, authorizer),
+		networkSecurityGroupsClient: newNetworkSecurityGroupsClientImpl(subscriptionID, authorizer),
+		managedIdentitiesClient:     newManagedIdentitiesClientImpl(subscriptionID, authorizer),
 	}, nil
 }
 
@@ -273,3 +279,11 @@ func (c *azureCloudImplementation) LoadBalancer() LoadBalancersClient {
 func (c *azureCloudImplementation) PublicIPAddress() PublicIPAddressesClient {
 	return c.publicIPAddressesClient
 }
+
+func (c *azureCloudImplementation) NetworkSecurityGroup() NetworkSecurityGroupsClient {
+	return c.networkSecurityGroupsClient
+}
+
+func (c *azureCloudImplementation) ManagedIdentity() ManagedIdentitiesClient {
+	return c.managedIdentitiesClient
+}
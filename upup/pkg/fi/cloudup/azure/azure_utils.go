@@ -19,6 +19,8 @@ package azure
 import (
 	"fmt"
 	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
 )
 
 // ZoneToLocation extracts the location from a zone of the
@@ -30,3 +32,23 @@ func ZoneToLocation(zone string) (string, error) {
 	}
 	return l[0], nil
 }
+
+// ManagedIdentityName returns the name of the Managed Identity object assigned to the VM Scale Sets of the given role.
+// Managed Identity names cannot contain periods, so they are replaced with hyphens.
+func ManagedIdentityName(clusterName string, role kops.InstanceGroupRole) string {
+	return roleGroupName(role) + "-" + strings.ReplaceAll(clusterName, ".", "-")
+}
+
+// NetworkSecurityGroupName returns the name of the Network Security Group object attached to the VM Scale Sets of the given role.
+func NetworkSecurityGroupName(clusterName string, role kops.InstanceGroupRole) string {
+	return roleGroupName(role) + "." + clusterName
+}
+
+// roleGroupName returns the name of the group of instances sharing Managed Identities and Network Security Groups.
+// Bastions are grouped with the nodes.
+func roleGroupName(role kops.InstanceGroupRole) string {
+	if role == kops.InstanceGroupRoleMaster {
+		return "masters"
+	}
+	return "nodes"
+}
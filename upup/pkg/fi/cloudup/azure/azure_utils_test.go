@@ -19,6 +19,8 @@ package azure
 import (
 	"fmt"
 	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
 )
 
 func TestZoneToLocation(t *testing.T) {
@@ -61,3 +63,38 @@ func TestZoneToLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceNames(t *testing.T) {
+	testCases := []struct {
+		role                 kops.InstanceGroupRole
+		managedIdentity      string
+		networkSecurityGroup string
+	}{
+		{
+			role:                 kops.InstanceGroupRoleMaster,
+			managedIdentity:      "masters-test-k8s-local",
+			networkSecurityGroup: "masters.test.k8s.local",
+		},
+		{
+			role:                 kops.InstanceGroupRoleNode,
+			managedIdentity:      "nodes-test-k8s-local",
+			networkSecurityGroup: "nodes.test.k8s.local",
+		},
+		{
+			role:                 kops.InstanceGroupRoleBastion,
+			managedIdentity:      "nodes-test-k8s-local",
+			networkSecurityGroup: "nodes.test.k8s.local",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.role), func(t *testing.T) {
+			if actual := ManagedIdentityName("test.k8s.local", tc.role); actual != tc.managedIdentity {
+				t.Errorf("expected managed identity %s but got %s", tc.managedIdentity, actual)
+			}
+			if actual := NetworkSecurityGroupName("test.k8s.local", tc.role); actual != tc.networkSecurityGroup {
+				t.Errorf("expected network security group %s but got %s", tc.networkSecurityGroup, actual)
+			}
+		})
+	}
+}
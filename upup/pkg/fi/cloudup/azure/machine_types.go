@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+// MachineTypeInfo describes a Azure machine type in the offline catalog
+type MachineTypeInfo struct {
+	Name         string
+	Cores        int
+	MemoryGB     float32
+	GPUs         int
+	Architecture architectures.Architecture
+	// Burstable is set for machine types with shared or burstable CPUs
+	Burstable bool
+}
+
+// MachineTypes returns the machine types in the offline catalog, sorted by name
+func MachineTypes() []*MachineTypeInfo {
+	return machineTypeCatalog
+}
+
+// GetMachineTypeInfo returns the catalog entry for the named machine type
+func GetMachineTypeInfo(machineType string) (*MachineTypeInfo, error) {
+	for _, m := range machineTypeCatalog {
+		if m.Name == machineType {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("machine type %q not found in the Azure machine type catalog", machineType)
+}
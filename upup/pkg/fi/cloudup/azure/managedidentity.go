@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// ManagedIdentitiesClient is a client for managing user-assigned Managed Identities.
+type ManagedIdentitiesClient interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName, identityName string, parameters msi.Identity) (*msi.Identity, error)
+	List(ctx context.Context, resourceGroupName string) ([]msi.Identity, error)
+	Delete(ctx context.Context, resourceGroupName, identityName string) error
+}
+
+type managedIdentitiesClientImpl struct {
+	c *msi.UserAssignedIdentitiesClient
+}
+
+var _ ManagedIdentitiesClient = &managedIdentitiesClientImpl{}
+
+func (c *managedIdentitiesClientImpl) CreateOrUpdate(ctx context.Context, resourceGroupName, identityName string, parameters msi.Identity) (*msi.Identity, error) {
+	identity, err := c.c.CreateOrUpdate(ctx, resourceGroupName, identityName, parameters)
+	return &identity, err
+}
+
+func (c *managedIdentitiesClientImpl) List(ctx context.Context, resourceGroupName string) ([]msi.Identity, error) {
+	var l []msi.Identity
+	for iter, err := c.c.ListByResourceGroupComplete(ctx, resourceGroupName); iter.NotDone(); err = iter.Next() {
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, iter.Value())
+	}
+	return l, nil
+}
+
+func (c *managedIdentitiesClientImpl) Delete(ctx context.Context, resourceGroupName, identityName string) error {
+	_, err := c.c.Delete(ctx, resourceGroupName, identityName)
+	return err
+}
+
+func newManagedIdentitiesClientImpl(subscriptionID string, authorizer autorest.Authorizer) *managedIdentitiesClientImpl {
+	c := msi.NewUserAssignedIdentitiesClient(subscriptionID)
+	c.Authorizer = authorizer
+	return &managedIdentitiesClientImpl{
+		c: &c,
+	}
+}
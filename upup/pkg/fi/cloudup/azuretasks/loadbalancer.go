@@ -81,20 +81,31 @@ func (lb *LoadBalancer) Find(c *fi.Context) (*LoadBalancer, error) {
 		return nil, fmt.Errorf("unexpected number of frontend configs found for LoadBalancer %s: %d", *lb.Name, len(feConfigs))
 	}
 	feConfig := feConfigs[0]
-	subnet := feConfig.FrontendIPConfigurationPropertiesFormat.Subnet
 
-	return &LoadBalancer{
+	actual := &LoadBalancer{
 		Name:      lb.Name,
 		Lifecycle: lb.Lifecycle,
 		ResourceGroup: &ResourceGroup{
 			Name: lb.ResourceGroup.Name,
 		},
-		Subnet: &Subnet{
-			Name: subnet.Name,
-		},
 		External: to.BoolPtr(feConfig.FrontendIPConfigurationPropertiesFormat.PublicIPAddress != nil),
 		Tags:     found.Tags,
-	}, nil
+	}
+	// Only internal loadbalancers have their frontend in a subnet.
+	if subnet := feConfig.FrontendIPConfigurationPropertiesFormat.Subnet; subnet != nil {
+		actual.Subnet = &Subnet{
+			Name: subnet.Name,
+		}
+		if subnet.Name == nil && subnet.ID != nil {
+			// Azure only returns the ID of the subnet.
+			subnetID, err := ParseSubnetID(*subnet.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse subnet ID %s", *subnet.ID)
+			}
+			actual.Subnet.Name = to.StringPtr(subnetID.SubnetName)
+		}
+	}
+	return actual, nil
 }
 
 // Run implements fi.Task.Run.
@@ -134,6 +134,37 @@ func TestLoadBalancerFind(t *testing.T) {
 	}
 }
 
+// TestLoadBalancerFind_Internal verifies that Find discovers the subnet of an internal loadbalancer
+// from the ID of the subnet of its frontend.
+func TestLoadBalancerFind_Internal(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	ctx := &fi.Context{
+		Cloud: cloud,
+	}
+
+	loadbalancer := &LoadBalancer{}
+	expected := newTestLoadBalancer()
+	expected.External = to.BoolPtr(false)
+	if err := loadbalancer.RenderAzure(azure.NewAzureAPITarget(cloud), nil, expected, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	feConfig := (*cloud.LoadBalancersClient.LBs[*expected.Name].FrontendIPConfigurations)[0]
+	if feConfig.PublicIPAddress != nil {
+		t.Errorf("unexpected public IP address for internal loadbalancer: %+v", feConfig.PublicIPAddress)
+	}
+
+	actual, err := expected.Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := *actual.Subnet.Name, *expected.Subnet.Name; a != e {
+		t.Errorf("unexpected Subnet name: expected %s, but got %s", e, a)
+	}
+	if *actual.External {
+		t.Errorf("unexpected external loadbalancer")
+	}
+}
+
 func TestLoadBalancerRun(t *testing.T) {
 	cloud := NewMockAzureCloud("eastus")
 	ctx := &fi.Context{
@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
+	"github.com/Azure/go-autorest/autorest/to"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
+)
+
+// ManagedIdentityID contains the resource ID/names required to construct a user-assigned Managed Identity ID.
+type ManagedIdentityID struct {
+	SubscriptionID      string
+	ResourceGroupName   string
+	ManagedIdentityName string
+}
+
+// String returns the user-assigned Managed Identity ID in the path format.
+func (m *ManagedIdentityID) String() string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ManagedIdentity/userAssignedIdentities/%s",
+		m.SubscriptionID,
+		m.ResourceGroupName,
+		m.ManagedIdentityName,
+	)
+}
+
+// ParseManagedIdentityID parses a given user-assigned Managed Identity ID string and returns a ManagedIdentityID.
+func ParseManagedIdentityID(s string) (*ManagedIdentityID, error) {
+	l := strings.Split(s, "/")
+	if len(l) != 9 {
+		return nil, fmt.Errorf("malformed format of managed identity ID: %s, %d", s, len(l))
+	}
+	return &ManagedIdentityID{
+		SubscriptionID:      l[2],
+		ResourceGroupName:   l[4],
+		ManagedIdentityName: l[8],
+	}, nil
+}
+
+//go:generate fitask -type=ManagedIdentity
+
+// ManagedIdentity is an Azure user-assigned Managed Identity, which is assigned to the VM Scale Sets of a role.
+type ManagedIdentity struct {
+	Name          *string
+	Lifecycle     *fi.Lifecycle
+	ResourceGroup *ResourceGroup
+
+	Tags map[string]*string
+	// PrincipalID is the ID of the service principal of the identity, to which roles are assigned.
+	// It is populated by Azure when the identity is created.
+	PrincipalID *string
+}
+
+var _ fi.Task = &ManagedIdentity{}
+var _ fi.CompareWithID = &ManagedIdentity{}
+
+// CompareWithID returns the Name of the Managed Identity.
+func (m *ManagedIdentity) CompareWithID() *string {
+	return m.Name
+}
+
+// Find discovers the Managed Identity in the cloud provider.
+func (m *ManagedIdentity) Find(c *fi.Context) (*ManagedIdentity, error) {
+	cloud := c.Cloud.(azure.AzureCloud)
+	l, err := cloud.ManagedIdentity().List(context.TODO(), *m.ResourceGroup.Name)
+	if err != nil {
+		return nil, err
+	}
+	var found *msi.Identity
+	for _, v := range l {
+		if *v.Name == *m.Name {
+			found = &v
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	actual := &ManagedIdentity{
+		Name:      m.Name,
+		Lifecycle: m.Lifecycle,
+		ResourceGroup: &ResourceGroup{
+			Name: m.ResourceGroup.Name,
+		},
+		Tags:        found.Tags,
+		PrincipalID: principalIDOf(found),
+	}
+	// Role Assignments need the principal ID before the identity is rendered.
+	m.PrincipalID = actual.PrincipalID
+	return actual, nil
+}
+
+func principalIDOf(identity *msi.Identity) *string {
+	if identity.UserAssignedIdentityProperties == nil || identity.PrincipalID == nil {
+		return nil
+	}
+	return to.StringPtr(identity.PrincipalID.String())
+}
+
+// Run implements fi.Task.Run.
+func (m *ManagedIdentity) Run(c *fi.Context) error {
+	c.Cloud.(azure.AzureCloud).AddClusterTags(m.Tags)
+	return fi.DefaultDeltaRunMethod(m, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*ManagedIdentity) CheckChanges(a, e, changes *ManagedIdentity) error {
+	if a == nil {
+		// Check if required fields are set when a new resource is created.
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		return nil
+	}
+
+	// Check if unchangeable fields won't be changed.
+	if changes.Name != nil {
+		return fi.CannotChangeField("Name")
+	}
+	if changes.PrincipalID != nil {
+		return fi.CannotChangeField("PrincipalID")
+	}
+	return nil
+}
+
+// RenderAzure creates or updates a Managed Identity.
+func (*ManagedIdentity) RenderAzure(t *azure.AzureAPITarget, a, e, changes *ManagedIdentity) error {
+	if a == nil {
+		klog.Infof("Creating a new Managed Identity with name: %s", fi.StringValue(e.Name))
+	} else {
+		klog.Infof("Updating a Managed Identity with name: %s", fi.StringValue(e.Name))
+	}
+
+	identity := msi.Identity{
+		Location: to.StringPtr(t.Cloud.Region()),
+		Tags:     e.Tags,
+	}
+
+	result, err := t.Cloud.ManagedIdentity().CreateOrUpdate(
+		context.TODO(),
+		*e.ResourceGroup.Name,
+		*e.Name,
+		identity)
+	if err != nil {
+		return err
+	}
+	e.PrincipalID = principalIDOf(result)
+	return nil
+}
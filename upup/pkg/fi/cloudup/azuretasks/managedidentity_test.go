@@ -0,0 +1,196 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
+	"github.com/Azure/go-autorest/autorest/to"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
+)
+
+func newTestManagedIdentity() *ManagedIdentity {
+	return &ManagedIdentity{
+		Name: to.StringPtr("mi"),
+		ResourceGroup: &ResourceGroup{
+			Name: to.StringPtr("rg"),
+		},
+		Tags: map[string]*string{
+			testTagKey: to.StringPtr(testTagValue),
+		},
+	}
+}
+
+func TestManagedIdentityIDParse(t *testing.T) {
+	identityID := &ManagedIdentityID{
+		SubscriptionID:      "sid",
+		ResourceGroupName:   "rg",
+		ManagedIdentityName: "mi",
+	}
+	actual, err := ParseManagedIdentityID(identityID.String())
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if !reflect.DeepEqual(actual, identityID) {
+		t.Errorf("expected %+v, but got %+v", identityID, actual)
+	}
+}
+
+func TestManagedIdentityRenderAzure(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	apiTarget := azure.NewAzureAPITarget(cloud)
+	identity := &ManagedIdentity{}
+	expected := newTestManagedIdentity()
+	if err := identity.RenderAzure(apiTarget, nil, expected, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	actual := cloud.ManagedIdentitiesClient.Identities[*expected.Name]
+	if a, e := *actual.Name, *expected.Name; a != e {
+		t.Errorf("unexpected Name: expected %s, but got %s", e, a)
+	}
+	if a, e := *actual.Location, cloud.Region(); a != e {
+		t.Fatalf("unexpected location: expected %s, but got %s", e, a)
+	}
+	if expected.PrincipalID == nil {
+		t.Fatalf("principal ID must be set")
+	}
+	if a, e := actual.PrincipalID.String(), *expected.PrincipalID; a != e {
+		t.Errorf("unexpected principal ID: expected %s, but got %s", e, a)
+	}
+}
+
+func TestManagedIdentityFind(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	ctx := &fi.Context{
+		Cloud: cloud,
+	}
+
+	rg := &ResourceGroup{
+		Name: to.StringPtr("rg"),
+	}
+	identity := &ManagedIdentity{
+		Name: to.StringPtr("mi"),
+		ResourceGroup: &ResourceGroup{
+			Name: rg.Name,
+		},
+	}
+	// Find will return nothing if there is no Managed Identity created.
+	actual, err := identity.Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual != nil {
+		t.Errorf("unexpected Managed Identity found: %+v", actual)
+	}
+
+	// Create a Managed Identity.
+	created, err := cloud.ManagedIdentity().CreateOrUpdate(context.Background(), *rg.Name, *identity.Name, msi.Identity{
+		Location: to.StringPtr("eastus"),
+	})
+	if err != nil {
+		t.Fatalf("failed to create: %s", err)
+	}
+	// Find again.
+	actual, err = identity.Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := *actual.Name, *identity.Name; a != e {
+		t.Errorf("unexpected Managed Identity name: expected %s, but got %s", e, a)
+	}
+	if a, e := *actual.ResourceGroup.Name, *rg.Name; a != e {
+		t.Errorf("unexpected Resource Group name: expected %s, but got %s", e, a)
+	}
+	if a, e := *actual.PrincipalID, created.PrincipalID.String(); a != e {
+		t.Errorf("unexpected principal ID: expected %s, but got %s", e, a)
+	}
+	// Find populates the principal ID of the expected task so that Role Assignments can use it.
+	if a, e := fi.StringValue(identity.PrincipalID), created.PrincipalID.String(); a != e {
+		t.Errorf("unexpected principal ID of the expected task: expected %s, but got %s", e, a)
+	}
+}
+
+func TestManagedIdentityRun(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	ctx := &fi.Context{
+		Cloud:  cloud,
+		Target: azure.NewAzureAPITarget(cloud),
+	}
+
+	identity := newTestManagedIdentity()
+	err := identity.Run(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	e := map[string]*string{
+		azure.TagClusterName: to.StringPtr(testClusterName),
+		testTagKey:           to.StringPtr(testTagValue),
+	}
+	if a := identity.Tags; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected tags: expected %+v, but got %+v", e, a)
+	}
+}
+
+func TestManagedIdentityCheckChanges(t *testing.T) {
+	testCases := []struct {
+		a, e, changes *ManagedIdentity
+		success       bool
+	}{
+		{
+			a:       nil,
+			e:       &ManagedIdentity{Name: to.StringPtr("name")},
+			changes: nil,
+			success: true,
+		},
+		{
+			a:       nil,
+			e:       &ManagedIdentity{Name: nil},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       &ManagedIdentity{Name: to.StringPtr("name")},
+			changes: &ManagedIdentity{Name: nil},
+			success: true,
+		},
+		{
+			a:       &ManagedIdentity{Name: to.StringPtr("name")},
+			changes: &ManagedIdentity{Name: to.StringPtr("newName")},
+			success: false,
+		},
+		{
+			a:       &ManagedIdentity{Name: to.StringPtr("name")},
+			changes: &ManagedIdentity{PrincipalID: to.StringPtr("pid")},
+			success: false,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			identity := ManagedIdentity{}
+			err := identity.CheckChanges(tc.a, tc.e, tc.changes)
+			if tc.success != (err == nil) {
+				t.Errorf("expected success=%t, but got err=%v", tc.success, err)
+			}
+		})
+	}
+}
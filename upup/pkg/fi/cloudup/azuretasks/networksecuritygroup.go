@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
+)
+
+// NetworkSecurityGroupID contains the resource ID/names required to construct a Network Security Group ID.
+type NetworkSecurityGroupID struct {
+	SubscriptionID           string
+	ResourceGroupName        string
+	NetworkSecurityGroupName string
+}
+
+// String returns the Network Security Group ID in the path format.
+func (n *NetworkSecurityGroupID) String() string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkSecurityGroups/%s",
+		n.SubscriptionID,
+		n.ResourceGroupName,
+		n.NetworkSecurityGroupName,
+	)
+}
+
+// ParseNetworkSecurityGroupID parses a given Network Security Group ID string and returns a NetworkSecurityGroupID.
+func ParseNetworkSecurityGroupID(s string) (*NetworkSecurityGroupID, error) {
+	l := strings.Split(s, "/")
+	if len(l) != 9 {
+		return nil, fmt.Errorf("malformed format of network security group ID: %s, %d", s, len(l))
+	}
+	return &NetworkSecurityGroupID{
+		SubscriptionID:           l[2],
+		ResourceGroupName:        l[4],
+		NetworkSecurityGroupName: l[8],
+	}, nil
+}
+
+//go:generate fitask -type=NetworkSecurityGroup
+
+// NetworkSecurityGroup is an Azure Network Security Group, which filters the traffic to the VM Scale Sets of a role.
+type NetworkSecurityGroup struct {
+	Name          *string
+	Lifecycle     *fi.Lifecycle
+	ResourceGroup *ResourceGroup
+
+	// SecurityRules are the rules of the Network Security Group, in addition to the default rules
+	// that allow traffic within the Virtual Network and from Azure Load Balancers.
+	SecurityRules []*NetworkSecurityRule
+	Tags          map[string]*string
+}
+
+// NetworkSecurityRule is an inbound rule of a Network Security Group.
+type NetworkSecurityRule struct {
+	Name *string
+	// Priority must be unique within the Network Security Group, between 100 and 4096.
+	// Rules with lower priorities are evaluated first.
+	Priority *int32
+	Access   network.SecurityRuleAccess
+	Protocol network.SecurityRuleProtocol
+	// SourceAddressPrefixes are the CIDRs the rule applies to.
+	SourceAddressPrefixes []string
+	// DestinationPortRange is a port (e.g. "22") or a range of ports (e.g. "30000-32767").
+	DestinationPortRange *string
+}
+
+var _ fi.HasDependencies = &NetworkSecurityRule{}
+
+// GetDependencies returns a slice of tasks on which the tasks depends on.
+func (r *NetworkSecurityRule) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	return nil
+}
+
+var _ fi.Task = &NetworkSecurityGroup{}
+var _ fi.CompareWithID = &NetworkSecurityGroup{}
+
+// CompareWithID returns the Name of the Network Security Group.
+func (n *NetworkSecurityGroup) CompareWithID() *string {
+	return n.Name
+}
+
+// Find discovers the Network Security Group in the cloud provider.
+func (n *NetworkSecurityGroup) Find(c *fi.Context) (*NetworkSecurityGroup, error) {
+	cloud := c.Cloud.(azure.AzureCloud)
+	l, err := cloud.NetworkSecurityGroup().List(context.TODO(), *n.ResourceGroup.Name)
+	if err != nil {
+		return nil, err
+	}
+	var found *network.SecurityGroup
+	for _, v := range l {
+		if *v.Name == *n.Name {
+			found = &v
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	nsg := &NetworkSecurityGroup{
+		Name:      n.Name,
+		Lifecycle: n.Lifecycle,
+		ResourceGroup: &ResourceGroup{
+			Name: n.ResourceGroup.Name,
+		},
+		Tags: found.Tags,
+	}
+	if found.SecurityGroupPropertiesFormat != nil && found.SecurityRules != nil {
+		for _, rule := range *found.SecurityRules {
+			if rule.SecurityRulePropertiesFormat == nil || rule.Direction != network.SecurityRuleDirectionInbound {
+				continue
+			}
+			r := &NetworkSecurityRule{
+				Name:     rule.Name,
+				Priority: rule.Priority,
+				Access:   rule.Access,
+				Protocol: rule.Protocol,
+			}
+			// Azure may return a single prefix or port range in the singular field.
+			if rule.SourceAddressPrefixes != nil {
+				r.SourceAddressPrefixes = append(r.SourceAddressPrefixes, *rule.SourceAddressPrefixes...)
+			}
+			if p := fi.StringValue(rule.SourceAddressPrefix); p != "" {
+				r.SourceAddressPrefixes = append(r.SourceAddressPrefixes, p)
+			}
+			r.DestinationPortRange = rule.DestinationPortRange
+			if r.DestinationPortRange == nil && rule.DestinationPortRanges != nil && len(*rule.DestinationPortRanges) == 1 {
+				r.DestinationPortRange = to.StringPtr((*rule.DestinationPortRanges)[0])
+			}
+			nsg.SecurityRules = append(nsg.SecurityRules, r)
+		}
+		sort.Slice(nsg.SecurityRules, func(i, j int) bool {
+			return fi.Int32Value(nsg.SecurityRules[i].Priority) < fi.Int32Value(nsg.SecurityRules[j].Priority)
+		})
+	}
+	return nsg, nil
+}
+
+// Run implements fi.Task.Run.
+func (n *NetworkSecurityGroup) Run(c *fi.Context) error {
+	c.Cloud.(azure.AzureCloud).AddClusterTags(n.Tags)
+	return fi.DefaultDeltaRunMethod(n, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*NetworkSecurityGroup) CheckChanges(a, e, changes *NetworkSecurityGroup) error {
+	if a == nil {
+		// Check if required fields are set when a new resource is created.
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		return nil
+	}
+
+	// Check if unchangeable fields won't be changed.
+	if changes.Name != nil {
+		return fi.CannotChangeField("Name")
+	}
+	return nil
+}
+
+// RenderAzure creates or updates a Network Security Group.
+// The rules are replaced as a whole, so rules removed from the spec are removed from the group.
+func (*NetworkSecurityGroup) RenderAzure(t *azure.AzureAPITarget, a, e, changes *NetworkSecurityGroup) error {
+	if a == nil {
+		klog.Infof("Creating a new Network Security Group with name: %s", fi.StringValue(e.Name))
+	} else {
+		klog.Infof("Updating a Network Security Group with name: %s", fi.StringValue(e.Name))
+	}
+
+	var rules []network.SecurityRule
+	for _, r := range e.SecurityRules {
+		prefixes := r.SourceAddressPrefixes
+		rules = append(rules, network.SecurityRule{
+			Name: r.Name,
+			SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+				Priority:                 r.Priority,
+				Access:                   r.Access,
+				Direction:                network.SecurityRuleDirectionInbound,
+				Protocol:                 r.Protocol,
+				SourceAddressPrefixes:    &prefixes,
+				SourcePortRange:          to.StringPtr("*"),
+				DestinationAddressPrefix: to.StringPtr("*"),
+				DestinationPortRange:     r.DestinationPortRange,
+			},
+		})
+	}
+
+	nsg := network.SecurityGroup{
+		Location: to.StringPtr(t.Cloud.Region()),
+		SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+			SecurityRules: &rules,
+		},
+		Tags: e.Tags,
+	}
+
+	return t.Cloud.NetworkSecurityGroup().CreateOrUpdate(
+		context.TODO(),
+		*e.ResourceGroup.Name,
+		*e.Name,
+		nsg)
+}
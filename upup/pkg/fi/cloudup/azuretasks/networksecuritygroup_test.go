@@ -0,0 +1,253 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
+)
+
+func newTestNetworkSecurityGroup() *NetworkSecurityGroup {
+	return &NetworkSecurityGroup{
+		Name: to.StringPtr("nsg"),
+		ResourceGroup: &ResourceGroup{
+			Name: to.StringPtr("rg"),
+		},
+		SecurityRules: []*NetworkSecurityRule{
+			{
+				Name:                  to.StringPtr("ssh"),
+				Priority:              to.Int32Ptr(100),
+				Access:                network.SecurityRuleAccessAllow,
+				Protocol:              network.SecurityRuleProtocolTCP,
+				SourceAddressPrefixes: []string{"10.0.0.0/8", "192.168.0.0/16"},
+				DestinationPortRange:  to.StringPtr("22"),
+			},
+		},
+		Tags: map[string]*string{
+			testTagKey: to.StringPtr(testTagValue),
+		},
+	}
+}
+
+func TestNetworkSecurityGroupIDParse(t *testing.T) {
+	nsgID := &NetworkSecurityGroupID{
+		SubscriptionID:           "sid",
+		ResourceGroupName:        "rg",
+		NetworkSecurityGroupName: "nsg",
+	}
+	actual, err := ParseNetworkSecurityGroupID(nsgID.String())
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if !reflect.DeepEqual(actual, nsgID) {
+		t.Errorf("expected %+v, but got %+v", nsgID, actual)
+	}
+}
+
+func TestNetworkSecurityGroupRenderAzure(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	apiTarget := azure.NewAzureAPITarget(cloud)
+	nsg := &NetworkSecurityGroup{}
+	expected := newTestNetworkSecurityGroup()
+	if err := nsg.RenderAzure(apiTarget, nil, expected, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	actual := cloud.NetworkSecurityGroupsClient.NSGs[*expected.Name]
+	if a, e := *actual.Name, *expected.Name; a != e {
+		t.Errorf("unexpected Name: expected %s, but got %s", e, a)
+	}
+	if a, e := *actual.Location, cloud.Region(); a != e {
+		t.Fatalf("unexpected location: expected %s, but got %s", e, a)
+	}
+	rules := *actual.SecurityRules
+	if len(rules) != 1 {
+		t.Fatalf("unexpected number of security rules: %d", len(rules))
+	}
+	rule := rules[0]
+	if a, e := rule.Direction, network.SecurityRuleDirectionInbound; a != e {
+		t.Errorf("unexpected direction: expected %s, but got %s", e, a)
+	}
+	if a, e := *rule.SourceAddressPrefixes, expected.SecurityRules[0].SourceAddressPrefixes; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected source address prefixes: expected %+v, but got %+v", e, a)
+	}
+	if a, e := *rule.DestinationPortRange, "22"; a != e {
+		t.Errorf("unexpected destination port range: expected %s, but got %s", e, a)
+	}
+}
+
+func TestNetworkSecurityGroupFind(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	ctx := &fi.Context{
+		Cloud: cloud,
+	}
+
+	rg := &ResourceGroup{
+		Name: to.StringPtr("rg"),
+	}
+	nsg := &NetworkSecurityGroup{
+		Name: to.StringPtr("nsg"),
+		ResourceGroup: &ResourceGroup{
+			Name: rg.Name,
+		},
+	}
+	// Find will return nothing if there is no Network Security Group created.
+	actual, err := nsg.Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual != nil {
+		t.Errorf("unexpected Network Security Group found: %+v", actual)
+	}
+
+	// Create a Network Security Group. Azure returns a single prefix in SourceAddressPrefix.
+	nsgParameters := network.SecurityGroup{
+		Location: to.StringPtr("eastus"),
+		SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
+			SecurityRules: &[]network.SecurityRule{
+				{
+					Name: to.StringPtr("https"),
+					SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+						Priority:                 to.Int32Ptr(200),
+						Access:                   network.SecurityRuleAccessAllow,
+						Direction:                network.SecurityRuleDirectionInbound,
+						Protocol:                 network.SecurityRuleProtocolTCP,
+						SourceAddressPrefix:      to.StringPtr("0.0.0.0/0"),
+						SourcePortRange:          to.StringPtr("*"),
+						DestinationAddressPrefix: to.StringPtr("*"),
+						DestinationPortRange:     to.StringPtr("443"),
+					},
+				},
+				{
+					Name: to.StringPtr("ssh"),
+					SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+						Priority:                 to.Int32Ptr(100),
+						Access:                   network.SecurityRuleAccessAllow,
+						Direction:                network.SecurityRuleDirectionInbound,
+						Protocol:                 network.SecurityRuleProtocolTCP,
+						SourceAddressPrefixes:    &[]string{"10.0.0.0/8", "192.168.0.0/16"},
+						SourcePortRange:          to.StringPtr("*"),
+						DestinationAddressPrefix: to.StringPtr("*"),
+						DestinationPortRange:     to.StringPtr("22"),
+					},
+				},
+			},
+		},
+	}
+	if err := cloud.NetworkSecurityGroup().CreateOrUpdate(context.Background(), *rg.Name, *nsg.Name, nsgParameters); err != nil {
+		t.Fatalf("failed to create: %s", err)
+	}
+	// Find again.
+	actual, err = nsg.Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := *actual.Name, *nsg.Name; a != e {
+		t.Errorf("unexpected Network Security Group name: expected %s, but got %s", e, a)
+	}
+	if a, e := *actual.ResourceGroup.Name, *rg.Name; a != e {
+		t.Errorf("unexpected Resource Group name: expected %s, but got %s", e, a)
+	}
+	expectedRules := []*NetworkSecurityRule{
+		{
+			Name:                  to.StringPtr("ssh"),
+			Priority:              to.Int32Ptr(100),
+			Access:                network.SecurityRuleAccessAllow,
+			Protocol:              network.SecurityRuleProtocolTCP,
+			SourceAddressPrefixes: []string{"10.0.0.0/8", "192.168.0.0/16"},
+			DestinationPortRange:  to.StringPtr("22"),
+		},
+		{
+			Name:                  to.StringPtr("https"),
+			Priority:              to.Int32Ptr(200),
+			Access:                network.SecurityRuleAccessAllow,
+			Protocol:              network.SecurityRuleProtocolTCP,
+			SourceAddressPrefixes: []string{"0.0.0.0/0"},
+			DestinationPortRange:  to.StringPtr("443"),
+		},
+	}
+	if a, e := actual.SecurityRules, expectedRules; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected security rules: expected %+v, but got %+v", e, a)
+	}
+}
+
+func TestNetworkSecurityGroupRun(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	ctx := &fi.Context{
+		Cloud:  cloud,
+		Target: azure.NewAzureAPITarget(cloud),
+	}
+
+	nsg := newTestNetworkSecurityGroup()
+	err := nsg.Run(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	e := map[string]*string{
+		azure.TagClusterName: to.StringPtr(testClusterName),
+		testTagKey:           to.StringPtr(testTagValue),
+	}
+	if a := nsg.Tags; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected tags: expected %+v, but got %+v", e, a)
+	}
+}
+
+func TestNetworkSecurityGroupCheckChanges(t *testing.T) {
+	testCases := []struct {
+		a, e, changes *NetworkSecurityGroup
+		success       bool
+	}{
+		{
+			a:       nil,
+			e:       &NetworkSecurityGroup{Name: to.StringPtr("name")},
+			changes: nil,
+			success: true,
+		},
+		{
+			a:       nil,
+			e:       &NetworkSecurityGroup{Name: nil},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       &NetworkSecurityGroup{Name: to.StringPtr("name")},
+			changes: &NetworkSecurityGroup{Name: nil},
+			success: true,
+		},
+		{
+			a:       &NetworkSecurityGroup{Name: to.StringPtr("name")},
+			changes: &NetworkSecurityGroup{Name: to.StringPtr("newName")},
+			success: false,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			nsg := NetworkSecurityGroup{}
+			err := nsg.CheckChanges(tc.a, tc.e, tc.changes)
+			if tc.success != (err == nil) {
+				t.Errorf("expected success=%t, but got err=%v", tc.success, err)
+			}
+		})
+	}
+}
@@ -25,7 +25,6 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
 	// Use 2018-01-01-preview API as we need the version to create
 	// a role assignment with Data Actions (https://github.com/Azure/azure-sdk-for-go/issues/1895).
 	// The non-preview version of the authorization API (2015-07-01)
@@ -48,9 +47,10 @@ type RoleAssignment struct {
 	Lifecycle *fi.Lifecycle
 
 	ResourceGroup *ResourceGroup
-	VMScaleSet    *VMScaleSet
-	ID            *string
-	RoleDefID     *string
+	// ManagedIdentity is the identity the role is assigned to.
+	ManagedIdentity *ManagedIdentity
+	ID              *string
+	RoleDefID       *string
 }
 
 var _ fi.Task = &RoleAssignment{}
@@ -63,8 +63,8 @@ func (r *RoleAssignment) CompareWithID() *string {
 
 // Find discovers the RoleAssignment in the cloud provider.
 func (r *RoleAssignment) Find(c *fi.Context) (*RoleAssignment, error) {
-	if r.VMScaleSet.PrincipalID == nil {
-		// PrincipalID of the VM Scale Set hasn't yet been
+	if r.ManagedIdentity.PrincipalID == nil {
+		// PrincipalID of the Managed Identity hasn't yet been
 		// populated. No corresponding Role Assignment
 		// shouldn't exist in Cloud.
 		return nil, nil
@@ -76,10 +76,10 @@ func (r *RoleAssignment) Find(c *fi.Context) (*RoleAssignment, error) {
 		return nil, err
 	}
 
-	principalID := *r.VMScaleSet.PrincipalID
+	principalID := *r.ManagedIdentity.PrincipalID
 	var found *authz.RoleAssignment
 	for _, ra := range rs {
-		// Use a name constructed by VMSS and Role definition ID to find a Role Assignment. We cannot use ra.Name
+		// Use the Principal ID of the Managed Identity and Role definition ID to find a Role Assignment. We cannot use ra.Name
 		// as it is set to a randomly generated GUID.
 		l := strings.Split(*ra.RoleDefinitionID, "/")
 		roleDefID := l[len(l)-1]
@@ -92,36 +92,17 @@ func (r *RoleAssignment) Find(c *fi.Context) (*RoleAssignment, error) {
 		return nil, nil
 	}
 
-	// Query VM Scale Sets and find one that has matching Principal ID.
-	vs, err := cloud.VMScaleSet().List(context.TODO(), *r.ResourceGroup.Name)
-	if err != nil {
-		return nil, err
-	}
-	var foundVMSS *compute.VirtualMachineScaleSet
-	for _, v := range vs {
-		if v.Identity == nil {
-			continue
-		}
-		if *v.Identity.PrincipalID == principalID {
-			foundVMSS = &v
-			break
-		}
-	}
-	if foundVMSS == nil {
-		return nil, fmt.Errorf("corresponding VM Scale Set not found for Role Assignment: %s", *found.ID)
-	}
-
 	return &RoleAssignment{
 		Name:      r.Name,
 		Lifecycle: r.Lifecycle,
 		ResourceGroup: &ResourceGroup{
 			Name: r.ResourceGroup.Name,
 		},
-		VMScaleSet: &VMScaleSet{
-			Name: foundVMSS.Name,
+		ManagedIdentity: &ManagedIdentity{
+			Name: r.ManagedIdentity.Name,
 		},
 		ID:        found.ID,
-		RoleDefID: found.RoleDefinitionID,
+		RoleDefID: r.RoleDefID,
 	}, nil
 }
 
@@ -169,7 +150,7 @@ func createNewRoleAssignment(t *azure.AzureAPITarget, e *RoleAssignment) error {
 	roleAssignment := authz.RoleAssignmentCreateParameters{
 		RoleAssignmentProperties: &authz.RoleAssignmentProperties{
 			RoleDefinitionID: to.StringPtr(roleDefID),
-			PrincipalID:      e.VMScaleSet.PrincipalID,
+			PrincipalID:      e.ManagedIdentity.PrincipalID,
 		},
 	}
 	ra, err := t.Cloud.RoleAssignment().Create(context.TODO(), scope, roleAssignmentName, roleAssignment)
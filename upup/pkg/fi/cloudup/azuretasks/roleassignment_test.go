@@ -23,7 +23,7 @@ import (
 
 	authz "github.com/Azure/azure-sdk-for-go/services/preview/authorization/mgmt/2018-01-01-preview/authorization"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/uuid"
 	"k8s.io/kops/upup/pkg/fi"
@@ -39,8 +39,8 @@ func TestRoleAssignmentRenderAzure(t *testing.T) {
 		ResourceGroup: &ResourceGroup{
 			Name: to.StringPtr("rg"),
 		},
-		VMScaleSet: &VMScaleSet{
-			Name:        to.StringPtr("vmss"),
+		ManagedIdentity: &ManagedIdentity{
+			Name:        to.StringPtr("mi"),
 			PrincipalID: to.StringPtr("pid"),
 		},
 		RoleDefID: to.StringPtr("rdid0"),
@@ -54,8 +54,8 @@ func TestRoleAssignmentRenderAzure(t *testing.T) {
 		t.Fatalf("id must be set")
 	}
 	actual := cloud.RoleAssignmentsClient.RAs[*expected.ID]
-	if a, e := *actual.PrincipalID, *expected.VMScaleSet.PrincipalID; a != e {
-		t.Errorf("unexpected role definition ID: expected %s, but got %s", e, a)
+	if a, e := *actual.PrincipalID, *expected.ManagedIdentity.PrincipalID; a != e {
+		t.Errorf("unexpected principal ID: expected %s, but got %s", e, a)
 	}
 }
 
@@ -68,22 +68,22 @@ func TestRoleAssignmentFind(t *testing.T) {
 	rg := &ResourceGroup{
 		Name: to.StringPtr("rg"),
 	}
-	vmssName := "vmss"
-	resp, err := cloud.VMScaleSet().CreateOrUpdate(context.TODO(), *rg.Name, vmssName, newTestVMSSParameters())
+	identityName := "mi"
+	resp, err := cloud.ManagedIdentity().CreateOrUpdate(context.TODO(), *rg.Name, identityName, msi.Identity{})
 	if err != nil {
 		t.Fatalf("failed to create: %s", err)
 	}
-	vmss := &VMScaleSet{
-		Name:        to.StringPtr(vmssName),
-		PrincipalID: resp.Identity.PrincipalID,
+	identity := &ManagedIdentity{
+		Name:        to.StringPtr(identityName),
+		PrincipalID: principalIDOf(resp),
 	}
 
 	roleDefID := "rdid0"
 	ra := &RoleAssignment{
-		Name:          vmss.Name,
-		ResourceGroup: rg,
-		VMScaleSet:    vmss,
-		RoleDefID:     &roleDefID,
+		Name:            identity.Name,
+		ResourceGroup:   rg,
+		ManagedIdentity: identity,
+		RoleDefID:       &roleDefID,
 	}
 	// Find will return nothing if there is no Role Assignment created.
 	actual, err := ra.Find(ctx)
@@ -91,7 +91,7 @@ func TestRoleAssignmentFind(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 	if actual != nil {
-		t.Errorf("unexpected vmss found: %+v", actual)
+		t.Errorf("unexpected Role Assignment found: %+v", actual)
 	}
 
 	// Create Role Assignments. One of them has irrelevant (different role definition ID).
@@ -100,7 +100,7 @@ func TestRoleAssignmentFind(t *testing.T) {
 	roleAssignment := authz.RoleAssignmentCreateParameters{
 		RoleAssignmentProperties: &authz.RoleAssignmentProperties{
 			RoleDefinitionID: to.StringPtr(roleDefID),
-			PrincipalID:      vmss.PrincipalID,
+			PrincipalID:      identity.PrincipalID,
 		},
 	}
 	if _, err := cloud.RoleAssignment().Create(context.TODO(), scope, roleAssignmentName, roleAssignment); err != nil {
@@ -110,7 +110,7 @@ func TestRoleAssignmentFind(t *testing.T) {
 	irrelevant := authz.RoleAssignmentCreateParameters{
 		RoleAssignmentProperties: &authz.RoleAssignmentProperties{
 			RoleDefinitionID: to.StringPtr("irrelevant"),
-			PrincipalID:      vmss.PrincipalID,
+			PrincipalID:      identity.PrincipalID,
 		},
 	}
 	if _, err := cloud.RoleAssignment().Create(context.TODO(), scope, uuid.New().String(), irrelevant); err != nil {
@@ -131,20 +131,20 @@ func TestRoleAssignmentFind(t *testing.T) {
 }
 
 // TestRoleAssignmentFind_NoPrincipalID verifies that Find doesn't find any Role Assignment
-// when the principal ID of VM Scale Set hasn't yet been set.
+// when the principal ID of Managed Identity hasn't yet been set.
 func TestRoleAssignmentFind_NoPrincipalID(t *testing.T) {
 	cloud := NewMockAzureCloud("eastus")
 	ctx := &fi.Context{
 		Cloud: cloud,
 	}
 
-	// Create a VM Scale Set.
+	// Create a Managed Identity.
 	rg := &ResourceGroup{
 		Name: to.StringPtr("rg"),
 	}
-	vmssName := "vmss"
-	if _, err := cloud.VMScaleSet().CreateOrUpdate(context.TODO(), *rg.Name, vmssName, newTestVMSSParameters()); err != nil {
-		t.Fatalf("failed to create VM Scale Set: %s", err)
+	identityName := "mi"
+	if _, err := cloud.ManagedIdentity().CreateOrUpdate(context.TODO(), *rg.Name, identityName, msi.Identity{}); err != nil {
+		t.Fatalf("failed to create Managed Identity: %s", err)
 	}
 
 	// Create a dummy Role Assignment to ensure that this won't be returned by Find.
@@ -158,10 +158,10 @@ func TestRoleAssignmentFind_NoPrincipalID(t *testing.T) {
 	}
 
 	ra := &RoleAssignment{
-		Name:          to.StringPtr(vmssName),
+		Name:          to.StringPtr(identityName),
 		ResourceGroup: rg,
-		VMScaleSet: &VMScaleSet{
-			Name: to.StringPtr(vmssName),
+		ManagedIdentity: &ManagedIdentity{
+			Name: to.StringPtr(identityName),
 			// Do not set principal ID.
 		},
 	}
@@ -212,11 +212,3 @@ func TestRoleAssignmentCheckChanges(t *testing.T) {
 		})
 	}
 }
-
-func newTestVMSSParameters() compute.VirtualMachineScaleSet {
-	return compute.VirtualMachineScaleSet{
-		Identity: &compute.VirtualMachineScaleSetIdentity{
-			Type: compute.ResourceIdentityTypeSystemAssigned,
-		},
-	}
-}
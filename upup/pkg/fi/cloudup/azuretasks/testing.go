@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-06-01/network"
 
 	// Use 2018-01-01-preview API as we need the version to create
@@ -31,6 +32,7 @@ import (
 	authz "github.com/Azure/azure-sdk-for-go/services/preview/authorization/mgmt/2018-01-01-preview/authorization"
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2020-06-01/resources"
 	"github.com/Azure/go-autorest/autorest/to"
+	gofrsuuid "github.com/gofrs/uuid"
 	"github.com/google/uuid"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
@@ -46,18 +48,20 @@ const (
 
 // MockAzureCloud is a mock implementation of AzureCloud.
 type MockAzureCloud struct {
-	Location                string
-	ResourceGroupsClient    *MockResourceGroupsClient
-	VirtualNetworksClient   *MockVirtualNetworksClient
-	SubnetsClient           *MockSubnetsClient
-	RouteTablesClient       *MockRouteTablesClient
-	VMScaleSetsClient       *MockVMScaleSetsClient
-	VMScaleSetVMsClient     *MockVMScaleSetVMsClient
-	DisksClient             *MockDisksClient
-	RoleAssignmentsClient   *MockRoleAssignmentsClient
-	NetworkInterfacesClient *MockNetworkInterfacesClient
-	LoadBalancersClient     *MockLoadBalancersClient
-	PublicIPAddressesClient *MockPublicIPAddressesClient
+	Location                    string
+	ResourceGroupsClient        *MockResourceGroupsClient
+	VirtualNetworksClient       *MockVirtualNetworksClient
+	SubnetsClient               *MockSubnetsClient
+	RouteTablesClient           *MockRouteTablesClient
+	VMScaleSetsClient           *MockVMScaleSetsClient
+	VMScaleSetVMsClient         *MockVMScaleSetVMsClient
+	DisksClient                 *MockDisksClient
+	RoleAssignmentsClient       *MockRoleAssignmentsClient
+	NetworkInterfacesClient     *MockNetworkInterfacesClient
+	LoadBalancersClient         *MockLoadBalancersClient
+	PublicIPAddressesClient     *MockPublicIPAddressesClient
+	NetworkSecurityGroupsClient *MockNetworkSecurityGroupsClient
+	ManagedIdentitiesClient     *MockManagedIdentitiesClient
 }
 
 var _ azure.AzureCloud = &MockAzureCloud{}
@@ -99,6 +103,12 @@ func NewMockAzureCloud(location string) *MockAzureCloud {
 		PublicIPAddressesClient: &MockPublicIPAddressesClient{
 			PubIPs: map[string]network.PublicIPAddress{},
 		},
+		NetworkSecurityGroupsClient: &MockNetworkSecurityGroupsClient{
+			NSGs: map[string]network.SecurityGroup{},
+		},
+		ManagedIdentitiesClient: &MockManagedIdentitiesClient{
+			Identities: map[string]msi.Identity{},
+		},
 	}
 }
 
@@ -222,6 +232,16 @@ func (c *MockAzureCloud) PublicIPAddress() azure.PublicIPAddressesClient {
 	return c.PublicIPAddressesClient
 }
 
+// NetworkSecurityGroup returns the network security group client.
+func (c *MockAzureCloud) NetworkSecurityGroup() azure.NetworkSecurityGroupsClient {
+	return c.NetworkSecurityGroupsClient
+}
+
+// ManagedIdentity returns the managed identity client.
+func (c *MockAzureCloud) ManagedIdentity() azure.ManagedIdentitiesClient {
+	return c.ManagedIdentitiesClient
+}
+
 // MockResourceGroupsClient is a mock implementation of resource group client.
 type MockResourceGroupsClient struct {
 	RGs map[string]resources.Group
@@ -381,7 +401,9 @@ func (c *MockVMScaleSetsClient) CreateOrUpdate(ctx context.Context, resourceGrou
 		return nil, fmt.Errorf("update not supported")
 	}
 	parameters.Name = &vmScaleSetName
-	parameters.Identity.PrincipalID = fi.String(uuid.New().String())
+	if parameters.Identity != nil && parameters.Identity.Type == compute.ResourceIdentityTypeSystemAssigned {
+		parameters.Identity.PrincipalID = fi.String(uuid.New().String())
+	}
 	c.VMSSes[vmScaleSetName] = parameters
 	return &parameters, nil
 }
@@ -595,3 +617,84 @@ func (c *MockPublicIPAddressesClient) Delete(ctx context.Context, scope, publicI
 	delete(c.PubIPs, publicIPAddressName)
 	return nil
 }
+
+// MockNetworkSecurityGroupsClient is a mock implementation of network security group client.
+type MockNetworkSecurityGroupsClient struct {
+	NSGs map[string]network.SecurityGroup
+}
+
+var _ azure.NetworkSecurityGroupsClient = &MockNetworkSecurityGroupsClient{}
+
+// CreateOrUpdate creates or updates a network security group.
+func (c *MockNetworkSecurityGroupsClient) CreateOrUpdate(ctx context.Context, resourceGroupName, networkSecurityGroupName string, parameters network.SecurityGroup) error {
+	// Ignore resourceGroupName for simplicity.
+	parameters.Name = &networkSecurityGroupName
+	parameters.ID = to.StringPtr(fmt.Sprintf("/subscriptions/sub/resourceGroups/%s/providers/Microsoft.Network/networkSecurityGroups/%s", resourceGroupName, networkSecurityGroupName))
+	c.NSGs[networkSecurityGroupName] = parameters
+	return nil
+}
+
+// List returns a slice of network security groups.
+func (c *MockNetworkSecurityGroupsClient) List(ctx context.Context, resourceGroupName string) ([]network.SecurityGroup, error) {
+	var l []network.SecurityGroup
+	for _, nsg := range c.NSGs {
+		l = append(l, nsg)
+	}
+	return l, nil
+}
+
+// Delete deletes a specified network security group.
+func (c *MockNetworkSecurityGroupsClient) Delete(ctx context.Context, resourceGroupName, networkSecurityGroupName string) error {
+	// Ignore resourceGroupName for simplicity.
+	if _, ok := c.NSGs[networkSecurityGroupName]; !ok {
+		return fmt.Errorf("%s does not exist", networkSecurityGroupName)
+	}
+	delete(c.NSGs, networkSecurityGroupName)
+	return nil
+}
+
+// MockManagedIdentitiesClient is a mock implementation of managed identity client.
+type MockManagedIdentitiesClient struct {
+	Identities map[string]msi.Identity
+}
+
+var _ azure.ManagedIdentitiesClient = &MockManagedIdentitiesClient{}
+
+// CreateOrUpdate creates or updates a managed identity.
+func (c *MockManagedIdentitiesClient) CreateOrUpdate(ctx context.Context, resourceGroupName, identityName string, parameters msi.Identity) (*msi.Identity, error) {
+	// Ignore resourceGroupName for simplicity.
+	if existing, ok := c.Identities[identityName]; ok {
+		// Azure keeps the principal of an identity when it is updated.
+		parameters.UserAssignedIdentityProperties = existing.UserAssignedIdentityProperties
+	} else {
+		principalID := gofrsuuid.Must(gofrsuuid.NewV4())
+		clientID := gofrsuuid.Must(gofrsuuid.NewV4())
+		parameters.UserAssignedIdentityProperties = &msi.UserAssignedIdentityProperties{
+			PrincipalID: &principalID,
+			ClientID:    &clientID,
+		}
+	}
+	parameters.Name = &identityName
+	parameters.ID = to.StringPtr(fmt.Sprintf("/subscriptions/sub/resourceGroups/%s/providers/Microsoft.ManagedIdentity/userAssignedIdentities/%s", resourceGroupName, identityName))
+	c.Identities[identityName] = parameters
+	return &parameters, nil
+}
+
+// List returns a slice of managed identities.
+func (c *MockManagedIdentitiesClient) List(ctx context.Context, resourceGroupName string) ([]msi.Identity, error) {
+	var l []msi.Identity
+	for _, identity := range c.Identities {
+		l = append(l, identity)
+	}
+	return l, nil
+}
+
+// Delete deletes a specified managed identity.
+func (c *MockManagedIdentitiesClient) Delete(ctx context.Context, resourceGroupName, identityName string) error {
+	// Ignore resourceGroupName for simplicity.
+	if _, ok := c.Identities[identityName]; !ok {
+		return fmt.Errorf("%s does not exist", identityName)
+	}
+	delete(c.Identities, identityName)
+	return nil
+}
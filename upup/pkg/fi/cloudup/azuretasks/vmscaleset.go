@@ -115,9 +115,12 @@ type VMScaleSet struct {
 	AdminUser    *string
 	SSHPublicKey *string
 	// CustomData is the user data configuration
-	CustomData  fi.Resource
-	Tags        map[string]*string
-	PrincipalID *string
+	CustomData fi.Resource
+	Tags       map[string]*string
+	// ManagedIdentity is the user-assigned identity the VMs use to access Azure APIs.
+	ManagedIdentity *ManagedIdentity
+	// NetworkSecurityGroup is the Network Security Group attached to the network interfaces of the VMs.
+	NetworkSecurityGroup *NetworkSecurityGroup
 }
 
 // VMScaleSetStorageProfile wraps *compute.VirtualMachineScaleSetStorageProfile
@@ -225,13 +228,32 @@ func (s *VMScaleSet) Find(c *fi.Context) (*VMScaleSet, error) {
 		AdminUser:          osProfile.AdminUsername,
 		SSHPublicKey:       sshKeys[0].KeyData,
 		Tags:               found.Tags,
-		PrincipalID:        found.Identity.PrincipalID,
 	}
 	if loadBalancerID != nil {
 		vmss.LoadBalancer = &LoadBalancer{
 			Name: to.StringPtr(loadBalancerID.LoadBalancerName),
 		}
 	}
+	if found.Identity != nil && found.Identity.UserAssignedIdentities != nil {
+		for id := range found.Identity.UserAssignedIdentities {
+			identityID, err := ParseManagedIdentityID(id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse managed identity ID %s", id)
+			}
+			vmss.ManagedIdentity = &ManagedIdentity{
+				Name: to.StringPtr(identityID.ManagedIdentityName),
+			}
+		}
+	}
+	if nsg := nwConfig.NetworkSecurityGroup; nsg != nil && nsg.ID != nil {
+		nsgID, err := ParseNetworkSecurityGroupID(*nsg.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse network security group ID %s", *nsg.ID)
+		}
+		vmss.NetworkSecurityGroup = &NetworkSecurityGroup{
+			Name: to.StringPtr(nsgID.NetworkSecurityGroupName),
+		}
+	}
 	return vmss, nil
 }
 
@@ -341,6 +363,16 @@ func (s *VMScaleSet) RenderAzure(t *azure.AzureAPITarget, a, e, changes *VMScale
 			},
 		},
 	}
+	if e.NetworkSecurityGroup != nil {
+		nsgID := NetworkSecurityGroupID{
+			SubscriptionID:           t.Cloud.SubscriptionID(),
+			ResourceGroupName:        *e.ResourceGroup.Name,
+			NetworkSecurityGroupName: *e.NetworkSecurityGroup.Name,
+		}
+		networkConfig.NetworkSecurityGroup = &compute.SubResource{
+			ID: to.StringPtr(nsgID.String()),
+		}
+	}
 
 	vmss := compute.VirtualMachineScaleSet{
 		Location: to.StringPtr(t.Cloud.Region()),
@@ -362,23 +394,30 @@ func (s *VMScaleSet) RenderAzure(t *azure.AzureAPITarget, a, e, changes *VMScale
 				},
 			},
 		},
-		// Assign a system-assigned managed identity so that
-		// Azure creates an identity for VMs and provision
-		// its credentials on the VMs.
-		Identity: &compute.VirtualMachineScaleSetIdentity{
-			Type: compute.ResourceIdentityTypeSystemAssigned,
-		},
 		Tags: e.Tags,
 	}
+	if e.ManagedIdentity != nil {
+		// Assign the user-assigned managed identity so that Azure
+		// provisions its credentials on the VMs. As it is the only
+		// identity of the VMs, it is used by default when requesting
+		// tokens from the Instance Metadata Service.
+		identityID := ManagedIdentityID{
+			SubscriptionID:      t.Cloud.SubscriptionID(),
+			ResourceGroupName:   *e.ResourceGroup.Name,
+			ManagedIdentityName: *e.ManagedIdentity.Name,
+		}
+		vmss.Identity = &compute.VirtualMachineScaleSetIdentity{
+			Type: compute.ResourceIdentityTypeUserAssigned,
+			UserAssignedIdentities: map[string]*compute.VirtualMachineScaleSetIdentityUserAssignedIdentitiesValue{
+				identityID.String(): {},
+			},
+		}
+	}
 
-	result, err := t.Cloud.VMScaleSet().CreateOrUpdate(
+	_, err := t.Cloud.VMScaleSet().CreateOrUpdate(
 		context.TODO(),
 		*e.ResourceGroup.Name,
 		name,
 		vmss)
-	if err != nil {
-		return err
-	}
-	e.PrincipalID = result.Identity.PrincipalID
-	return nil
+	return err
 }
@@ -85,6 +85,12 @@ func newTestVMScaleSet() *VMScaleSet {
 		SSHPublicKey:       to.StringPtr("ssh"),
 		CustomData:         fi.NewStringResource("custom"),
 		Tags:               map[string]*string{},
+		ManagedIdentity: &ManagedIdentity{
+			Name: to.StringPtr("mi"),
+		},
+		NetworkSecurityGroup: &NetworkSecurityGroup{
+			Name: to.StringPtr("nsg"),
+		},
 	}
 }
 
@@ -124,8 +130,25 @@ func TestVMScaleSetRenderAzure(t *testing.T) {
 		t.Errorf("unexpected custom data: expected %v, but got %v", expectedCData, actualCData)
 	}
 
-	if expected.PrincipalID == nil {
-		t.Errorf("unexpected nil principalID")
+	if a, e := actual.Identity.Type, compute.ResourceIdentityTypeUserAssigned; a != e {
+		t.Errorf("unexpected identity type: expected %s, but got %s", e, a)
+	}
+	identityID := ManagedIdentityID{
+		SubscriptionID:      cloud.SubscriptionID(),
+		ResourceGroupName:   *expected.ResourceGroup.Name,
+		ManagedIdentityName: *expected.ManagedIdentity.Name,
+	}
+	if _, ok := actual.Identity.UserAssignedIdentities[identityID.String()]; !ok || len(actual.Identity.UserAssignedIdentities) != 1 {
+		t.Errorf("unexpected user assigned identities: expected %s, but got %+v", identityID.String(), actual.Identity.UserAssignedIdentities)
+	}
+	nwConfig := (*actual.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations)[0]
+	nsgID := NetworkSecurityGroupID{
+		SubscriptionID:           cloud.SubscriptionID(),
+		ResourceGroupName:        *expected.ResourceGroup.Name,
+		NetworkSecurityGroupName: *expected.NetworkSecurityGroup.Name,
+	}
+	if a, e := *nwConfig.NetworkSecurityGroup.ID, nsgID.String(); a != e {
+		t.Errorf("unexpected network security group ID: expected %s, but got %s", e, a)
 	}
 }
 
@@ -197,6 +220,16 @@ func TestVMScaleSetFind(t *testing.T) {
 		ResourceGroupName: *rg.Name,
 		LoadBalancerName:  "api-lb",
 	}
+	identityID := ManagedIdentityID{
+		SubscriptionID:      "subID",
+		ResourceGroupName:   *rg.Name,
+		ManagedIdentityName: "mi",
+	}
+	nsgID := NetworkSecurityGroupID{
+		SubscriptionID:           "subID",
+		ResourceGroupName:        *rg.Name,
+		NetworkSecurityGroupName: "nsg",
+	}
 	ipConfigProperties := &compute.VirtualMachineScaleSetIPConfigurationProperties{
 		Subnet: &compute.APIEntityReference{
 			ID: to.StringPtr(subnetID.String()),
@@ -226,6 +259,9 @@ func TestVMScaleSetFind(t *testing.T) {
 					VirtualMachineScaleSetIPConfigurationProperties: ipConfigProperties,
 				},
 			},
+			NetworkSecurityGroup: &compute.SubResource{
+				ID: to.StringPtr(nsgID.String()),
+			},
 		},
 	}
 
@@ -250,7 +286,10 @@ func TestVMScaleSetFind(t *testing.T) {
 			},
 		},
 		Identity: &compute.VirtualMachineScaleSetIdentity{
-			Type: compute.ResourceIdentityTypeSystemAssigned,
+			Type: compute.ResourceIdentityTypeUserAssigned,
+			UserAssignedIdentities: map[string]*compute.VirtualMachineScaleSetIdentityUserAssignedIdentitiesValue{
+				identityID.String(): {},
+			},
 		},
 	}
 	if _, err := cloud.VMScaleSet().CreateOrUpdate(context.Background(), *rg.Name, *vmss.Name, vmssParameters); err != nil {
@@ -287,6 +326,12 @@ func TestVMScaleSetFind(t *testing.T) {
 	if !*actual.RequirePublicIP {
 		t.Errorf("unexpected require public IP")
 	}
+	if a, e := *actual.ManagedIdentity.Name, identityID.ManagedIdentityName; a != e {
+		t.Errorf("unexpected Managed Identity name: expected %s, but got %s", e, a)
+	}
+	if a, e := *actual.NetworkSecurityGroup.Name, nsgID.NetworkSecurityGroupName; a != e {
+		t.Errorf("unexpected Network Security Group name: expected %s, but got %s", e, a)
+	}
 }
 
 func TestVMScaleSetRun(t *testing.T) {
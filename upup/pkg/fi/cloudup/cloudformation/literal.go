@@ -71,6 +71,20 @@ func LiteralString(v string) *Literal {
 	return &Literal{json: j}
 }
 
+// Select returns the element of a list with the given index
+func Select(index uint64, list *Literal) *Literal {
+	j := make(map[string]interface{})
+	j["Fn::Select"] = []interface{}{index, list}
+	return &Literal{json: j}
+}
+
+// Cidr returns a list of count CIDR blocks with cidrBits host bits, allocated in order from ipBlock
+func Cidr(ipBlock *Literal, count uint64, cidrBits int) *Literal {
+	j := make(map[string]interface{})
+	j["Fn::Cidr"] = []interface{}{ipBlock, count, cidrBits}
+	return &Literal{json: j}
+}
+
 //
 //func LiteralSelfLink(resourceType, resourceName string) *Literal {
 //	return LiteralProperty(resourceType, resourceName, "self_link")
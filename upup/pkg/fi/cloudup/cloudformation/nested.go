@@ -0,0 +1,186 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudformation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+)
+
+// nestedStacksDir is the directory, relative to the main template, that holds the nested stack templates
+const nestedStacksDir = "stacks"
+
+// stackSplitter splits resources into a main template and nested stack templates.
+// References between stacks are replaced by parameters of the nested stack
+// when they point out of it, and by outputs of the nested stack when they point into it.
+type stackSplitter struct {
+	// stackOf maps the logical ID of each resource to its nested stack, or "" for the main template
+	stackOf map[string]string
+
+	main   *cloudformationTemplate
+	nested map[string]*cloudformationTemplate
+	// parameters holds the values passed to the parameters of each nested stack
+	parameters map[string]map[string]interface{}
+}
+
+// splitNestedStacks returns the main template and a template for each nested stack, keyed by their relative paths
+func (t *CloudformationTarget) splitNestedStacks(outputs map[string]*cloudformationOutput) (map[string]*cloudformationTemplate, error) {
+	// We rewrite references in the generic JSON form of the resources
+	resources := make(map[string]interface{})
+	if err := toGenericJSON(t.resources, &resources); err != nil {
+		return nil, err
+	}
+	genericOutputs := make(map[string]*cloudformationOutput)
+	if err := toGenericJSON(outputs, &genericOutputs); err != nil {
+		return nil, err
+	}
+
+	s := &stackSplitter{
+		stackOf:    make(map[string]string),
+		main:       &cloudformationTemplate{Resources: make(map[string]interface{})},
+		nested:     make(map[string]*cloudformationTemplate),
+		parameters: make(map[string]map[string]interface{}),
+	}
+	for id := range resources {
+		stack := t.nestedStacks[id]
+		s.stackOf[id] = stack
+		if stack != "" && s.nested[stack] == nil {
+			s.nested[stack] = &cloudformationTemplate{
+				Parameters: make(map[string]*cloudformationParameter),
+				Resources:  make(map[string]interface{}),
+				Outputs:    make(map[string]*cloudformationOutput),
+			}
+			s.parameters[stack] = make(map[string]interface{})
+		}
+	}
+
+	for id, res := range resources {
+		stack := s.stackOf[id]
+		if stack == "" {
+			s.main.Resources[id] = s.rewrite(res, "")
+		} else {
+			s.nested[stack].Resources[id] = s.rewrite(res, stack)
+		}
+	}
+
+	if len(genericOutputs) != 0 {
+		s.main.Outputs = make(map[string]*cloudformationOutput)
+		for name, output := range genericOutputs {
+			s.main.Outputs[name] = &cloudformationOutput{Value: s.rewrite(output.Value, "")}
+		}
+	}
+
+	templates := make(map[string]*cloudformationTemplate)
+	for stack, template := range s.nested {
+		templateURL := path.Join(nestedStacksDir, stack+".json")
+		templates[templateURL] = template
+
+		properties := map[string]interface{}{
+			"TemplateURL": templateURL,
+		}
+		if len(s.parameters[stack]) != 0 {
+			properties["Parameters"] = s.parameters[stack]
+		}
+		s.main.Resources[stackLogicalID(stack)] = &cloudformationResource{
+			Type:       "AWS::CloudFormation::Stack",
+			Properties: properties,
+		}
+	}
+	templates["kubernetes.json"] = s.main
+
+	return templates, nil
+}
+
+// rewrite returns v, as used in the given stack, with the references to resources in other stacks replaced
+func (s *stackSplitter) rewrite(v interface{}, stack string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if id, attribute, ok := reference(v); ok {
+			if target, found := s.stackOf[id]; found && target != stack {
+				return s.crossStackReference(v, id+attribute, target, stack)
+			}
+		}
+		m := make(map[string]interface{})
+		for k, value := range v {
+			m[k] = s.rewrite(value, stack)
+		}
+		return m
+	case []interface{}:
+		var l []interface{}
+		for _, value := range v {
+			l = append(l, s.rewrite(value, stack))
+		}
+		return l
+	default:
+		return v
+	}
+}
+
+// crossStackReference replaces a reference from one stack to a resource in another stack
+func (s *stackSplitter) crossStackReference(ref map[string]interface{}, name string, target string, stack string) interface{} {
+	name = sanitizeCloudformationResourceName(name)
+
+	if stack != "" {
+		// The reference points out of a nested stack, so we pass the value in as a parameter
+		s.nested[stack].Parameters[name] = &cloudformationParameter{Type: "String"}
+		s.parameters[stack][name] = s.rewrite(ref, "")
+		return map[string]interface{}{"Ref": name}
+	}
+
+	// The reference points into a nested stack, so we read the value from an output of that stack
+	s.nested[target].Outputs[name] = &cloudformationOutput{Value: ref}
+	return map[string]interface{}{"Fn::GetAtt": []interface{}{stackLogicalID(target), "Outputs." + name}}
+}
+
+// reference returns the logical ID and attribute of a Ref or Fn::GetAtt
+func reference(m map[string]interface{}) (string, string, bool) {
+	if len(m) != 1 {
+		return "", "", false
+	}
+	if id, ok := m["Ref"].(string); ok {
+		return id, "", true
+	}
+	if getAtt, ok := m["Fn::GetAtt"].([]interface{}); ok && len(getAtt) == 2 {
+		id, ok1 := getAtt[0].(string)
+		attribute, ok2 := getAtt[1].(string)
+		if ok1 && ok2 {
+			return id, attribute, true
+		}
+	}
+	return "", "", false
+}
+
+// stackLogicalID returns the logical ID of the resource for a nested stack
+func stackLogicalID(stack string) string {
+	return sanitizeCloudformationResourceName("AWS::CloudFormation::Stack::" + stack)
+}
+
+// toGenericJSON converts v to its generic JSON form in out, preserving numbers
+func toGenericJSON(v interface{}, out interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error marshaling cloudformation data to json: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("error parsing cloudformation data: %v", err)
+	}
+	return nil
+}
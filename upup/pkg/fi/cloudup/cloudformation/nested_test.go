@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testProperties struct {
+	VpcId    *Literal   `json:"VpcId,omitempty"`
+	SubnetId *Literal   `json:"SubnetId,omitempty"`
+	Groups   []*Literal `json:"Groups,omitempty"`
+}
+
+func TestSplitNestedStacks(t *testing.T) {
+	target := NewCloudformationTarget(nil, "", "")
+	target.NestedStacks = true
+
+	if err := target.RenderResource("AWS::EC2::VPC", "vpc", &testProperties{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.RenderResource("AWS::EC2::Subnet", "subnet", &testProperties{VpcId: Ref("AWS::EC2::VPC", "vpc")}); err != nil {
+		t.Fatal(err)
+	}
+	target.AssignNestedStack("nodes", "AWS::EC2::Instance", "node")
+	if err := target.RenderResource("AWS::EC2::Instance", "node", &testProperties{
+		SubnetId: Ref("AWS::EC2::Subnet", "subnet"),
+		Groups:   []*Literal{GetAtt("AWS::EC2::VPC", "vpc", "DefaultSecurityGroup"), LiteralString("sg-1")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.AddOutputVariableArray("node_ids", Ref("AWS::EC2::Instance", "node")); err != nil {
+		t.Fatal(err)
+	}
+
+	outputs, err := target.buildOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	templates, err := target.splitNestedStacks(outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"kubernetes.json": `{"Resources":{"AWSCloudFormationStacknodes":{"Type":"AWS::CloudFormation::Stack","Properties":{"Parameters":{"AWSEC2Subnetsubnet":{"Ref":"AWSEC2Subnetsubnet"},"AWSEC2VPCvpcDefaultSecurityGroup":{"Fn::GetAtt":["AWSEC2VPCvpc","DefaultSecurityGroup"]}},"TemplateURL":"stacks/nodes.json"}},` +
+			`"AWSEC2Subnetsubnet":{"Properties":{"VpcId":{"Ref":"AWSEC2VPCvpc"}},"Type":"AWS::EC2::Subnet"},"AWSEC2VPCvpc":{"Properties":{},"Type":"AWS::EC2::VPC"}},` +
+			`"Outputs":{"NodeIds":{"Value":{"Fn::Join":[",",[{"Fn::GetAtt":["AWSCloudFormationStacknodes","Outputs.AWSEC2Instancenode"]}]]}}}}`,
+		"stacks/nodes.json": `{"Parameters":{"AWSEC2Subnetsubnet":{"Type":"String"},"AWSEC2VPCvpcDefaultSecurityGroup":{"Type":"String"}},` +
+			`"Resources":{"AWSEC2Instancenode":{"Properties":{"Groups":[{"Ref":"AWSEC2VPCvpcDefaultSecurityGroup"},"sg-1"],"SubnetId":{"Ref":"AWSEC2Subnetsubnet"}},"Type":"AWS::EC2::Instance"}},` +
+			`"Outputs":{"AWSEC2Instancenode":{"Value":{"Ref":"AWSEC2Instancenode"}}}}`,
+	}
+	if len(templates) != len(expected) {
+		t.Fatalf("unexpected number of templates: %d", len(templates))
+	}
+	for relativePath, template := range templates {
+		actual, err := json.Marshal(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != expected[relativePath] {
+			t.Errorf("unexpected template %s\nactual:   %s\nexpected: %s", relativePath, actual, expected[relativePath])
+		}
+	}
+}
+
+func TestFinishSplitsLargeTemplates(t *testing.T) {
+	outDir := t.TempDir()
+	target := NewCloudformationTarget(nil, "", outDir)
+
+	for i := 0; i < 2*maxTemplateResources; i++ {
+		stack := fmt.Sprintf("ig%d", i%4)
+		name := fmt.Sprintf("instance%d", i)
+		target.AssignNestedStack(stack, "AWS::EC2::Instance", name)
+		if err := target.RenderResource("AWS::EC2::Instance", name, &testProperties{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := target.Finish(nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, relativePath := range []string{"kubernetes.json", "stacks/ig0.json", "stacks/ig3.json"} {
+		if _, err := os.Stat(filepath.Join(outDir, relativePath)); err != nil {
+			t.Errorf("expected template %s: %v", relativePath, err)
+		}
+	}
+}
+
+func TestFinishFailsOverLimits(t *testing.T) {
+	target := NewCloudformationTarget(nil, "", t.TempDir())
+
+	for i := 0; i <= maxTemplateResources; i++ {
+		if err := target.RenderResource("AWS::EC2::Instance", fmt.Sprintf("instance%d", i), &testProperties{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := target.Finish(nil)
+	if err == nil || !strings.Contains(err.Error(), "exceeds the CloudFormation limit") {
+		t.Errorf("expected the template to exceed the CloudFormation limits, got %v", err)
+	}
+}
+
+func TestOutputName(t *testing.T) {
+	grid := map[string]string{
+		"vpc_id":                       "VpcId",
+		"subnet_us-test-1a_id":         "SubnetUsTest1aId",
+		"master_autoscaling_group_ids": "MasterAutoscalingGroupIds",
+	}
+	for key, expected := range grid {
+		if actual := outputName(key); actual != expected {
+			t.Errorf("outputName(%q) = %q, expected %q", key, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxTemplateSize is the maximum size of a template that is uploaded to S3
+	maxTemplateSize = 1024 * 1024
+	// maxTemplateResources is the maximum number of resources in a template
+	maxTemplateResources = 500
+	// MaxTemplateBodySize is the maximum size of a template that is passed inline, rather than uploaded to S3
+	MaxTemplateBodySize = 51200
+)
+
+type cloudformationTemplate struct {
+	Parameters map[string]*cloudformationParameter `json:"Parameters,omitempty"`
+	Resources  map[string]interface{}              `json:"Resources"`
+	Outputs    map[string]*cloudformationOutput    `json:"Outputs,omitempty"`
+}
+
+type cloudformationParameter struct {
+	Type string `json:"Type"`
+}
+
+type cloudformationOutput struct {
+	Value interface{} `json:"Value"`
+}
+
+type cloudformationOutputVariable struct {
+	Key        string
+	Value      *Literal
+	ValueArray []*Literal
+}
+
+// buildOutputs builds the Outputs section of the template.
+// Array outputs are joined into a comma-separated list, as stack outputs must be strings.
+func (t *CloudformationTarget) buildOutputs() (map[string]*cloudformationOutput, error) {
+	outputs := make(map[string]*cloudformationOutput)
+	for _, v := range t.outputs {
+		name := outputName(v.Key)
+		if outputs[name] != nil {
+			return nil, fmt.Errorf("duplicate output %q for variable %q", name, v.Key)
+		}
+
+		if v.Value != nil {
+			outputs[name] = &cloudformationOutput{Value: v.Value}
+			continue
+		}
+
+		values, err := sortedUniqueLiterals(v.ValueArray)
+		if err != nil {
+			return nil, err
+		}
+		join := make(map[string]interface{})
+		join["Fn::Join"] = []interface{}{",", values}
+		outputs[name] = &cloudformationOutput{Value: join}
+	}
+	return outputs, nil
+}
+
+// sortedUniqueLiterals removes duplicates from literals and sorts them by their JSON representation
+func sortedUniqueLiterals(literals []*Literal) ([]*Literal, error) {
+	byJSON := make(map[string]*Literal)
+	for _, l := range literals {
+		b, err := json.Marshal(l)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling output value: %v", err)
+		}
+		byJSON[string(b)] = l
+	}
+
+	keys := make([]string, 0, len(byJSON))
+	for k := range byJSON {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var values []*Literal
+	for _, k := range keys {
+		values = append(values, byJSON[k])
+	}
+	return values, nil
+}
+
+// outputName converts a terraform-style output name such as subnet_us-test-1a_id
+// to an alphanumeric CloudFormation output name such as SubnetUsTest1aId
+func outputName(key string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
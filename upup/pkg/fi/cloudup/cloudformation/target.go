@@ -33,19 +33,28 @@ type CloudformationTarget struct {
 	Cloud   fi.Cloud
 	Project string
 
+	// NestedStacks splits the resources of each instance group into a nested stack.
+	// The resources are also split when a single template would exceed the CloudFormation limits.
+	NestedStacks bool
+
 	outDir string
 
-	// mutex protects the following items (resources & files)
+	// mutex protects the following items (resources, outputs & nestedStacks)
 	mutex     sync.Mutex
 	resources map[string]*cloudformationResource
+	outputs   map[string]*cloudformationOutputVariable
+	// nestedStacks maps the logical ID of a resource to the nested stack that it belongs to
+	nestedStacks map[string]string
 }
 
 func NewCloudformationTarget(cloud fi.Cloud, project string, outDir string) *CloudformationTarget {
 	return &CloudformationTarget{
-		Cloud:     cloud,
-		Project:   project,
-		outDir:    outDir,
-		resources: make(map[string]*cloudformationResource),
+		Cloud:        cloud,
+		Project:      project,
+		outDir:       outDir,
+		resources:    make(map[string]*cloudformationResource),
+		outputs:      make(map[string]*cloudformationOutputVariable),
+		nestedStacks: make(map[string]string),
 	}
 }
 
@@ -54,6 +63,8 @@ var _ fi.Target = &CloudformationTarget{}
 type cloudformationResource struct {
 	Type       string
 	Properties interface{}
+	// DependsOn holds the logical IDs of resources that must be created first, but are not referenced by the properties
+	DependsOn []string `json:",omitempty"`
 }
 
 // A cloudformation resource name must be alphanumeric
@@ -90,6 +101,75 @@ func (t *CloudformationTarget) RenderResource(resourceType string, resourceName
 	return nil
 }
 
+// AddDependency makes a rendered resource depend on the resource that the given reference points to
+func (t *CloudformationTarget) AddDependency(resourceType string, resourceName string, dependency *Literal) error {
+	key := dependency.extractRef()
+	if key == "" {
+		return fmt.Errorf("unable to extract ref from %v", dependency)
+	}
+
+	name := sanitizeCloudformationResourceName(resourceType + "::" + resourceName)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	res := t.resources[name]
+	if res == nil {
+		return fmt.Errorf("resource %q not found in cloudformation", name)
+	}
+	res.DependsOn = append(res.DependsOn, key)
+
+	return nil
+}
+
+// AddOutputVariable adds a stack output, mirroring the outputs of the terraform target
+func (t *CloudformationTarget) AddOutputVariable(key string, literal *Literal) error {
+	v := &cloudformationOutputVariable{
+		Key:   key,
+		Value: literal,
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.outputs[key] != nil {
+		return fmt.Errorf("duplicate variable: %q", key)
+	}
+	t.outputs[key] = v
+
+	return nil
+}
+
+// AddOutputVariableArray adds a value to a stack output that is a comma-separated list
+func (t *CloudformationTarget) AddOutputVariableArray(key string, literal *Literal) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.outputs[key] == nil {
+		v := &cloudformationOutputVariable{
+			Key: key,
+		}
+		t.outputs[key] = v
+	}
+	if t.outputs[key].Value != nil {
+		return fmt.Errorf("variable %q is both an array and a scalar", key)
+	}
+
+	t.outputs[key].ValueArray = append(t.outputs[key].ValueArray, literal)
+
+	return nil
+}
+
+// AssignNestedStack places a resource in the nested stack with the given name, when the resources are split into nested stacks
+func (t *CloudformationTarget) AssignNestedStack(stack string, resourceType string, resourceName string) {
+	name := sanitizeCloudformationResourceName(resourceType + "::" + resourceName)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.nestedStacks[name] = stack
+}
+
 func (t *CloudformationTarget) Find(ref *Literal) (interface{}, bool) {
 	key := ref.extractRef()
 	if key == "" {
@@ -108,49 +188,40 @@ func (t *CloudformationTarget) Find(ref *Literal) (interface{}, bool) {
 }
 
 func (t *CloudformationTarget) Finish(taskMap map[string]fi.Task) error {
-	//resourcesByType := make(map[string]map[string]interface{})
-	//
-	//for _, res := range t.resources {
-	//	resources := resourcesByType[res.ResourceType]
-	//	if resources == nil {
-	//		resources = make(map[string]interface{})
-	//		resourcesByType[res.ResourceType] = resources
-	//	}
-	//
-	//	tfName := tfSanitize(res.ResourceName)
-	//
-	//	if resources[tfName] != nil {
-	//		return fmt.Errorf("duplicate resource found: %s.%s", res.ResourceType, tfName)
-	//	}
-	//
-	//	resources[tfName] = res.Item
-	//}
-
-	//providersByName := make(map[string]map[string]interface{})
-	//if t.Cloud.ProviderID() == kops.CloudProviderGCE {
-	//	providerGoogle := make(map[string]interface{})
-	//	providerGoogle["project"] = t.Project
-	//	providerGoogle["region"] = t.Region
-	//	providersByName["google"] = providerGoogle
-	//} else if t.Cloud.ProviderID() == kops.CloudProviderAWS {
-	//	providerAWS := make(map[string]interface{})
-	//	providerAWS["region"] = t.Region
-	//	providersByName["aws"] = providerAWS
-	//}
-
-	data := make(map[string]interface{})
-	data["Resources"] = t.resources
-	//if len(providersByName) != 0 {
-	//	data["provider"] = providersByName
-	//}
-
-	jsonBytes, err := json.MarshalIndent(data, "", "  ")
+	outputs, err := t.buildOutputs()
 	if err != nil {
-		return fmt.Errorf("error marshaling cloudformation data to json: %v", err)
+		return err
 	}
 
-	files := make(map[string][]byte)
-	files["kubernetes.json"] = jsonBytes
+	var files map[string][]byte
+	if t.NestedStacks {
+		files, err = t.renderNestedStacks(outputs)
+		if err != nil {
+			return err
+		}
+	} else {
+		resources := make(map[string]interface{})
+		for name, res := range t.resources {
+			resources[name] = res
+		}
+		templates := map[string]*cloudformationTemplate{
+			"kubernetes.json": {
+				Resources: resources,
+				Outputs:   outputs,
+			},
+		}
+		files, err = marshalTemplates(templates)
+		if err != nil {
+			return err
+		}
+		if err := checkTemplateLimits(templates, files); err != nil {
+			klog.Warningf("%v; splitting the instance groups into nested stacks", err)
+			files, err = t.renderNestedStacks(outputs)
+			if err != nil {
+				return err
+			}
+		}
+	}
 
 	for relativePath, contents := range files {
 		p := path.Join(t.outDir, relativePath)
@@ -170,3 +241,45 @@ func (t *CloudformationTarget) Finish(taskMap map[string]fi.Task) error {
 
 	return nil
 }
+
+// renderNestedStacks splits the resources into nested stacks, and returns the contents of each template keyed by its relative path
+func (t *CloudformationTarget) renderNestedStacks(outputs map[string]*cloudformationOutput) (map[string][]byte, error) {
+	templates, err := t.splitNestedStacks(outputs)
+	if err != nil {
+		return nil, err
+	}
+	files, err := marshalTemplates(templates)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTemplateLimits(templates, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// marshalTemplates returns the JSON form of each template
+func marshalTemplates(templates map[string]*cloudformationTemplate) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for relativePath, template := range templates {
+		jsonBytes, err := json.MarshalIndent(template, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling cloudformation data to json: %v", err)
+		}
+		files[relativePath] = jsonBytes
+	}
+	return files, nil
+}
+
+// checkTemplateLimits returns an error if a template exceeds the CloudFormation limits on resources or size
+func checkTemplateLimits(templates map[string]*cloudformationTemplate, files map[string][]byte) error {
+	for relativePath, template := range templates {
+		if len(template.Resources) > maxTemplateResources {
+			return fmt.Errorf("cloudformation template %s has %d resources, which exceeds the CloudFormation limit of %d resources", relativePath, len(template.Resources), maxTemplateResources)
+		}
+		if len(files[relativePath]) > maxTemplateSize {
+			return fmt.Errorf("cloudformation template %s is %d bytes, which exceeds the CloudFormation limit of %d bytes", relativePath, len(files[relativePath]), maxTemplateSize)
+		}
+	}
+	return nil
+}
@@ -43,6 +43,7 @@ type Droplet struct {
 	Tags     []string
 	Count    int
 	UserData fi.Resource
+	VPC      *VPC
 }
 
 var _ fi.Task = &Droplet{}
@@ -84,6 +85,7 @@ func (d *Droplet) Find(c *fi.Context) (*Droplet, error) {
 		Tags:      foundDroplet.Tags,
 		SSHKey:    d.SSHKey,   // TODO: get from droplet or ignore change
 		UserData:  d.UserData, // TODO: get from droplet or ignore change
+		VPC:       d.VPC,      // droplets cannot be moved to another VPC
 		Lifecycle: d.Lifecycle,
 	}, nil
 }
@@ -144,6 +146,11 @@ func (_ *Droplet) RenderDO(t *do.DOAPITarget, a, e, changes *Droplet) error {
 		newDropletCount = expectedCount - actualCount
 	}
 
+	var vpcUUID string
+	if e.VPC != nil {
+		vpcUUID = fi.StringValue(e.VPC.ID)
+	}
+
 	for i := 0; i < newDropletCount; i++ {
 		_, _, err = t.Cloud.Droplets().Create(context.TODO(), &godo.DropletCreateRequest{
 			Name:              fi.StringValue(e.Name),
@@ -154,6 +161,7 @@ func (_ *Droplet) RenderDO(t *do.DOAPITarget, a, e, changes *Droplet) error {
 			Tags:              e.Tags,
 			UserData:          userData,
 			SSHKeys:           []godo.DropletCreateSSHKey{{Fingerprint: fi.StringValue(e.SSHKey)}},
+			VPCUUID:           vpcUUID,
 		})
 
 		if err != nil {
@@ -0,0 +1,201 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dotasks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/digitalocean/godo"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/resources/digitalocean"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/do"
+)
+
+// Firewall represents a DigitalOcean Cloud Firewall, applied to the droplets matching DropletTags
+// +kops:fitask
+type Firewall struct {
+	Name      *string
+	ID        *string
+	Lifecycle *fi.Lifecycle
+
+	DropletTags   []string
+	InboundRules  []godo.InboundRule
+	OutboundRules []godo.OutboundRule
+
+	// APILoadBalancer, if set, is allowed to reach the droplets on the HTTPS port
+	APILoadBalancer *LoadBalancer
+}
+
+var _ fi.CompareWithID = &Firewall{}
+
+func (f *Firewall) CompareWithID() *string {
+	return f.ID
+}
+
+func (f *Firewall) Find(c *fi.Context) (*Firewall, error) {
+	cloud := c.Cloud.(*digitalocean.Cloud)
+
+	firewalls, err := cloud.GetAllFirewalls()
+	if err != nil {
+		return nil, fmt.Errorf("firewall service list request returned error %v", err)
+	}
+
+	var found *godo.Firewall
+	for i := range firewalls {
+		if firewalls[i].Name == fi.StringValue(f.Name) {
+			found = &firewalls[i]
+			break
+		}
+	}
+
+	if found == nil {
+		// Firewall = nil if not found
+		return nil, nil
+	}
+
+	actual := &Firewall{
+		Name:        fi.String(found.Name),
+		ID:          fi.String(found.ID),
+		DropletTags: found.Tags,
+
+		// Ignore system fields
+		Lifecycle: f.Lifecycle,
+	}
+
+	for _, rule := range found.InboundRules {
+		if rule.Sources != nil && len(rule.Sources.LoadBalancerUIDs) > 0 {
+			actual.APILoadBalancer = &LoadBalancer{ID: fi.String(rule.Sources.LoadBalancerUIDs[0])}
+			continue
+		}
+		rule.PortRange = normalizePortRange(rule.Protocol, rule.PortRange)
+		actual.InboundRules = append(actual.InboundRules, rule)
+	}
+	for _, rule := range found.OutboundRules {
+		rule.PortRange = normalizePortRange(rule.Protocol, rule.PortRange)
+		actual.OutboundRules = append(actual.OutboundRules, rule)
+	}
+
+	// The API does not preserve the order of rules
+	sortInboundRules(actual.InboundRules)
+	sortOutboundRules(actual.OutboundRules)
+	sortInboundRules(f.InboundRules)
+	sortOutboundRules(f.OutboundRules)
+
+	// Avoid spurious changes
+	f.ID = actual.ID
+
+	return actual, nil
+}
+
+// normalizePortRange maps the port range returned by the API to the one kops requests
+func normalizePortRange(protocol, portRange string) string {
+	if protocol == "icmp" {
+		return ""
+	}
+	if portRange == "" || portRange == "0" {
+		return "all"
+	}
+	return portRange
+}
+
+func sortInboundRules(rules []godo.InboundRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		return ruleKey(rules[i].Protocol, rules[i].PortRange, (*godo.Destinations)(rules[i].Sources)) <
+			ruleKey(rules[j].Protocol, rules[j].PortRange, (*godo.Destinations)(rules[j].Sources))
+	})
+}
+
+func sortOutboundRules(rules []godo.OutboundRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		return ruleKey(rules[i].Protocol, rules[i].PortRange, rules[i].Destinations) <
+			ruleKey(rules[j].Protocol, rules[j].PortRange, rules[j].Destinations)
+	})
+}
+
+func ruleKey(protocol, portRange string, target *godo.Destinations) string {
+	if target == nil {
+		return protocol + "/" + portRange
+	}
+	return fmt.Sprintf("%s/%s/%v/%v", protocol, portRange, target.Addresses, target.Tags)
+}
+
+func (f *Firewall) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(f, c)
+}
+
+func (_ *Firewall) CheckChanges(a, e, changes *Firewall) error {
+	if a != nil {
+		if changes.Name != nil {
+			return fi.CannotChangeField("Name")
+		}
+		if changes.ID != nil {
+			return fi.CannotChangeField("ID")
+		}
+	} else {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if len(e.DropletTags) == 0 {
+			return fi.RequiredField("DropletTags")
+		}
+	}
+	return nil
+}
+
+func (_ *Firewall) RenderDO(t *do.DOAPITarget, a, e, changes *Firewall) error {
+	inboundRules := append([]godo.InboundRule{}, e.InboundRules...)
+	if e.APILoadBalancer != nil {
+		lbID := fi.StringValue(e.APILoadBalancer.ID)
+		if lbID == "" {
+			return fmt.Errorf("load balancer %q for firewall %q has not been created yet", fi.StringValue(e.APILoadBalancer.Name), fi.StringValue(e.Name))
+		}
+		inboundRules = append(inboundRules, godo.InboundRule{
+			Protocol:  "tcp",
+			PortRange: "443",
+			Sources:   &godo.Sources{LoadBalancerUIDs: []string{lbID}},
+		})
+	}
+
+	request := &godo.FirewallRequest{
+		Name:          fi.StringValue(e.Name),
+		InboundRules:  inboundRules,
+		OutboundRules: e.OutboundRules,
+		Tags:          e.DropletTags,
+	}
+
+	if a == nil {
+		klog.V(2).Infof("Creating firewall with Name=%s", fi.StringValue(e.Name))
+		firewall, _, err := t.Cloud.Firewalls().Create(context.TODO(), request)
+		if err != nil {
+			return fmt.Errorf("error creating firewall with Name=%s: %v", fi.StringValue(e.Name), err)
+		}
+		e.ID = fi.String(firewall.ID)
+		return nil
+	}
+
+	klog.V(2).Infof("Updating firewall with Name=%s", fi.StringValue(e.Name))
+	_, _, err := t.Cloud.Firewalls().Update(context.TODO(), fi.StringValue(a.ID), request)
+	if err != nil {
+		return fmt.Errorf("error updating firewall with Name=%s: %v", fi.StringValue(e.Name), err)
+	}
+
+	return nil
+}
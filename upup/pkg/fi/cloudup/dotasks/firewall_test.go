@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dotasks
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/digitalocean/godo"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type fakeFirewallClient struct {
+	godo.FirewallsService
+
+	listFn func(context.Context, *godo.ListOptions) ([]godo.Firewall, *godo.Response, error)
+}
+
+func (f fakeFirewallClient) List(ctx context.Context, opts *godo.ListOptions) ([]godo.Firewall, *godo.Response, error) {
+	return f.listFn(ctx, opts)
+}
+
+func Test_FirewallFind(t *testing.T) {
+	clusterSources := &godo.Sources{Tags: []string{"KubernetesCluster:test-example-com"}}
+	anywhere := &godo.Destinations{Addresses: []string{"0.0.0.0/0", "::/0"}}
+
+	firewalls := []godo.Firewall{
+		{
+			ID:   "fw-1",
+			Name: "masters-test-example-com",
+			Tags: []string{"KubernetesCluster-Master:test-example-com"},
+			InboundRules: []godo.InboundRule{
+				{Protocol: "tcp", PortRange: "443", Sources: &godo.Sources{LoadBalancerUIDs: []string{"lb-1"}}},
+				{Protocol: "tcp", PortRange: "443", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}},
+			},
+		},
+		{
+			ID:   "fw-2",
+			Name: "k8s-test-example-com",
+			Tags: []string{"KubernetesCluster:test-example-com"},
+			InboundRules: []godo.InboundRule{
+				{Protocol: "udp", PortRange: "0", Sources: clusterSources},
+				{Protocol: "icmp", PortRange: "0", Sources: clusterSources},
+				{Protocol: "tcp", PortRange: "0", Sources: clusterSources},
+			},
+			OutboundRules: []godo.OutboundRule{
+				{Protocol: "tcp", PortRange: "0", Destinations: anywhere},
+			},
+		},
+	}
+
+	testcases := []struct {
+		name        string
+		inFirewall  *Firewall
+		outFirewall *Firewall
+	}{
+		{
+			"rules are normalized",
+			&Firewall{
+				Name:        fi.String("k8s-test-example-com"),
+				DropletTags: []string{"KubernetesCluster:test-example-com"},
+				InboundRules: []godo.InboundRule{
+					{Protocol: "tcp", PortRange: "all", Sources: clusterSources},
+					{Protocol: "udp", PortRange: "all", Sources: clusterSources},
+					{Protocol: "icmp", Sources: clusterSources},
+				},
+				OutboundRules: []godo.OutboundRule{
+					{Protocol: "tcp", PortRange: "all", Destinations: anywhere},
+				},
+			},
+			&Firewall{
+				Name:        fi.String("k8s-test-example-com"),
+				ID:          fi.String("fw-2"),
+				DropletTags: []string{"KubernetesCluster:test-example-com"},
+				InboundRules: []godo.InboundRule{
+					{Protocol: "icmp", Sources: clusterSources},
+					{Protocol: "tcp", PortRange: "all", Sources: clusterSources},
+					{Protocol: "udp", PortRange: "all", Sources: clusterSources},
+				},
+				OutboundRules: []godo.OutboundRule{
+					{Protocol: "tcp", PortRange: "all", Destinations: anywhere},
+				},
+			},
+		},
+		{
+			"load balancer rule is split out",
+			&Firewall{
+				Name:        fi.String("masters-test-example-com"),
+				DropletTags: []string{"KubernetesCluster-Master:test-example-com"},
+				InboundRules: []godo.InboundRule{
+					{Protocol: "tcp", PortRange: "443", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}},
+				},
+				APILoadBalancer: &LoadBalancer{Name: fi.String("api-test-example-com"), ID: fi.String("lb-1")},
+			},
+			&Firewall{
+				Name:        fi.String("masters-test-example-com"),
+				ID:          fi.String("fw-1"),
+				DropletTags: []string{"KubernetesCluster-Master:test-example-com"},
+				InboundRules: []godo.InboundRule{
+					{Protocol: "tcp", PortRange: "443", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}},
+				},
+				APILoadBalancer: &LoadBalancer{ID: fi.String("lb-1")},
+			},
+		},
+		{
+			"no firewall found",
+			&Firewall{
+				Name: fi.String("k8s-other-example-com"),
+			},
+			nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cloud := newCloud(godo.NewClient(nil))
+			cloud.Client.Firewalls = fakeFirewallClient{
+				listFn: func(context.Context, *godo.ListOptions) ([]godo.Firewall, *godo.Response, error) {
+					return firewalls, &godo.Response{}, nil
+				},
+			}
+			ctx := newContext(cloud)
+
+			actualFirewall, err := tc.inFirewall.Find(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actualFirewall, tc.outFirewall) {
+				t.Error("unexpected firewall")
+				t.Logf("actual firewall: %v", actualFirewall)
+				t.Logf("expected firewall: %v", tc.outFirewall)
+			}
+
+			if tc.outFirewall != nil {
+				changes := &Firewall{}
+				if fi.BuildChanges(actualFirewall, tc.inFirewall, changes) {
+					t.Errorf("unexpected changes: %v", changes)
+				}
+			}
+		})
+	}
+}
@@ -42,6 +42,7 @@ type LoadBalancer struct {
 	DropletTag   *string
 	IPAddress    *string
 	ForAPIServer bool
+	VPC          *VPC
 }
 
 var _ fi.CompareWithID = &LoadBalancer{}
@@ -74,6 +75,7 @@ func (lb *LoadBalancer) Find(c *fi.Context) (*LoadBalancer, error) {
 		// Ignore system fields
 		Lifecycle:    lb.Lifecycle,
 		ForAPIServer: lb.ForAPIServer,
+		VPC:          lb.VPC, // load balancers cannot be moved to another VPC
 	}, nil
 }
 
@@ -150,6 +152,11 @@ func (_ *LoadBalancer) RenderDO(t *do.DOAPITarget, a, e, changes *LoadBalancer)
 	// load balancer doesn't exist. Create one.
 	klog.V(10).Infof("Creating load balancer for DO")
 
+	var vpcUUID string
+	if e.VPC != nil {
+		vpcUUID = fi.StringValue(e.VPC.ID)
+	}
+
 	loadBalancerService := t.Cloud.LoadBalancers()
 	loadbalancer, _, err := loadBalancerService.Create(context.TODO(), &godo.LoadBalancerRequest{
 		Name:            fi.StringValue(e.Name),
@@ -157,6 +164,7 @@ func (_ *LoadBalancer) RenderDO(t *do.DOAPITarget, a, e, changes *LoadBalancer)
 		Tag:             fi.StringValue(e.DropletTag),
 		ForwardingRules: Rules,
 		HealthCheck:     HealthCheck,
+		VPCUUID:         vpcUUID,
 	})
 
 	if err != nil {
@@ -0,0 +1,192 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dotasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/resources/digitalocean"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/do"
+)
+
+// VPC represents the private network the droplets of the cluster are launched into
+// +kops:fitask
+type VPC struct {
+	Name      *string
+	ID        *string
+	Lifecycle *fi.Lifecycle
+
+	Region  *string
+	IPRange *string
+
+	// Shared is set if this is a shared VPC, which kops will not create or delete
+	Shared *bool
+
+	// DropletTag is the tag of the droplets of the cluster. If they already exist outside of a VPC
+	// created by kops, their VPC is used as a shared VPC, as droplets cannot be moved to another VPC.
+	DropletTag *string
+}
+
+var _ fi.CompareWithID = &VPC{}
+
+func (v *VPC) CompareWithID() *string {
+	return v.ID
+}
+
+func (v *VPC) Find(c *fi.Context) (*VPC, error) {
+	cloud := c.Cloud.(*digitalocean.Cloud)
+
+	var found *godo.VPC
+	if fi.StringValue(v.ID) != "" {
+		vpc, _, err := cloud.VPCs().Get(context.TODO(), fi.StringValue(v.ID))
+		if err != nil {
+			return nil, fmt.Errorf("vpc service get request returned error %v", err)
+		}
+		found = vpc
+	} else {
+		vpcs, err := cloud.GetAllVPCs()
+		if err != nil {
+			return nil, fmt.Errorf("vpc service list request returned error %v", err)
+		}
+		for _, vpc := range vpcs {
+			if vpc.Name == fi.StringValue(v.Name) && vpc.RegionSlug == fi.StringValue(v.Region) {
+				found = vpc
+				break
+			}
+		}
+
+		if found == nil && fi.StringValue(v.DropletTag) != "" {
+			vpc, err := findDropletsVPC(cloud, fi.StringValue(v.DropletTag))
+			if err != nil {
+				return nil, err
+			}
+			if vpc != nil {
+				klog.Infof("droplets with tag %q already exist in VPC %q, using it as a shared VPC", fi.StringValue(v.DropletTag), vpc.Name)
+				found = vpc
+				v.Shared = fi.Bool(true)
+			}
+		}
+	}
+
+	if found == nil {
+		// VPC = nil if not found
+		return nil, nil
+	}
+
+	actual := &VPC{
+		Name:    fi.String(found.Name),
+		ID:      fi.String(found.ID),
+		Region:  fi.String(found.RegionSlug),
+		IPRange: fi.String(found.IPRange),
+
+		// Ignore system fields
+		Lifecycle:  v.Lifecycle,
+		Shared:     v.Shared,
+		DropletTag: v.DropletTag,
+	}
+
+	if fi.BoolValue(v.Shared) {
+		// A shared VPC is named by its owner
+		actual.Name = v.Name
+	}
+
+	// Avoid spurious changes
+	v.ID = actual.ID
+
+	return actual, nil
+}
+
+// findDropletsVPC returns the VPC the droplets with the given tag were launched into, or nil if there are no such droplets
+func findDropletsVPC(cloud *digitalocean.Cloud, tag string) (*godo.VPC, error) {
+	droplets, _, err := cloud.Droplets().ListByTag(context.TODO(), tag, &godo.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("droplet service list request returned error %v", err)
+	}
+
+	for _, droplet := range droplets {
+		if droplet.VPCUUID == "" {
+			continue
+		}
+		vpc, _, err := cloud.VPCs().Get(context.TODO(), droplet.VPCUUID)
+		if err != nil {
+			return nil, fmt.Errorf("vpc service get request returned error %v", err)
+		}
+		return vpc, nil
+	}
+
+	return nil, nil
+}
+
+func (v *VPC) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(v, c)
+}
+
+func (_ *VPC) CheckChanges(a, e, changes *VPC) error {
+	if a != nil {
+		if changes.Name != nil {
+			return fi.CannotChangeField("Name")
+		}
+		if changes.ID != nil {
+			return fi.CannotChangeField("ID")
+		}
+		if changes.Region != nil {
+			return fi.CannotChangeField("Region")
+		}
+		if changes.IPRange != nil {
+			return fi.CannotChangeField("IPRange")
+		}
+	} else {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Region == nil {
+			return fi.RequiredField("Region")
+		}
+	}
+	return nil
+}
+
+func (_ *VPC) RenderDO(t *do.DOAPITarget, a, e, changes *VPC) error {
+	if a != nil {
+		// the region and IP range of a VPC cannot be changed
+		return nil
+	}
+
+	if fi.BoolValue(e.Shared) {
+		return fmt.Errorf("VPC with ID %q was set to be shared, but could not be found", fi.StringValue(e.ID))
+	}
+
+	klog.V(2).Infof("Creating VPC with Name=%s", fi.StringValue(e.Name))
+
+	vpc, _, err := t.Cloud.VPCs().Create(context.TODO(), &godo.VPCCreateRequest{
+		Name:       fi.StringValue(e.Name),
+		RegionSlug: fi.StringValue(e.Region),
+		IPRange:    fi.StringValue(e.IPRange),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating VPC with Name=%s: %v", fi.StringValue(e.Name), err)
+	}
+
+	e.ID = fi.String(vpc.ID)
+
+	return nil
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dotasks
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/digitalocean/godo"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type fakeVPCClient struct {
+	createFn func(context.Context, *godo.VPCCreateRequest) (*godo.VPC, *godo.Response, error)
+	getFn    func(context.Context, string) (*godo.VPC, *godo.Response, error)
+	listFn   func(context.Context, *godo.ListOptions) ([]*godo.VPC, *godo.Response, error)
+	updateFn func(context.Context, string, *godo.VPCUpdateRequest) (*godo.VPC, *godo.Response, error)
+	setFn    func(context.Context, string, ...godo.VPCSetField) (*godo.VPC, *godo.Response, error)
+	deleteFn func(context.Context, string) (*godo.Response, error)
+}
+
+func (f fakeVPCClient) Create(ctx context.Context, req *godo.VPCCreateRequest) (*godo.VPC, *godo.Response, error) {
+	return f.createFn(ctx, req)
+}
+
+func (f fakeVPCClient) Get(ctx context.Context, id string) (*godo.VPC, *godo.Response, error) {
+	return f.getFn(ctx, id)
+}
+
+func (f fakeVPCClient) List(ctx context.Context, opts *godo.ListOptions) ([]*godo.VPC, *godo.Response, error) {
+	return f.listFn(ctx, opts)
+}
+
+func (f fakeVPCClient) Update(ctx context.Context, id string, req *godo.VPCUpdateRequest) (*godo.VPC, *godo.Response, error) {
+	return f.updateFn(ctx, id, req)
+}
+
+func (f fakeVPCClient) Set(ctx context.Context, id string, fields ...godo.VPCSetField) (*godo.VPC, *godo.Response, error) {
+	return f.setFn(ctx, id, fields...)
+}
+
+func (f fakeVPCClient) Delete(ctx context.Context, id string) (*godo.Response, error) {
+	return f.deleteFn(ctx, id)
+}
+
+func Test_VPCFind(t *testing.T) {
+	vpcs := []*godo.VPC{
+		{
+			ID:         "default",
+			Name:       "default-nyc1",
+			RegionSlug: "nyc1",
+			IPRange:    "10.116.0.0/20",
+			Default:    true,
+		},
+		{
+			ID:         "vpc-1",
+			Name:       "test-example-com",
+			RegionSlug: "nyc1",
+			IPRange:    "10.10.0.0/20",
+		},
+	}
+
+	testcases := []struct {
+		name   string
+		vpcs   fakeVPCClient
+		inVPC  *VPC
+		outVPC *VPC
+		err    error
+	}{
+		{
+			"successfully found vpc by name",
+			fakeVPCClient{
+				listFn: func(context.Context, *godo.ListOptions) ([]*godo.VPC, *godo.Response, error) {
+					return vpcs, &godo.Response{}, nil
+				},
+			},
+			&VPC{
+				Name:   fi.String("test-example-com"),
+				Region: fi.String("nyc1"),
+			},
+			&VPC{
+				Name:    fi.String("test-example-com"),
+				ID:      fi.String("vpc-1"),
+				Region:  fi.String("nyc1"),
+				IPRange: fi.String("10.10.0.0/20"),
+			},
+			nil,
+		},
+		{
+			"vpc of another region is ignored",
+			fakeVPCClient{
+				listFn: func(context.Context, *godo.ListOptions) ([]*godo.VPC, *godo.Response, error) {
+					return vpcs, &godo.Response{}, nil
+				},
+			},
+			&VPC{
+				Name:   fi.String("test-example-com"),
+				Region: fi.String("nyc3"),
+			},
+			nil,
+			nil,
+		},
+		{
+			"no vpc found",
+			fakeVPCClient{
+				listFn: func(context.Context, *godo.ListOptions) ([]*godo.VPC, *godo.Response, error) {
+					return vpcs, &godo.Response{}, nil
+				},
+			},
+			&VPC{
+				Name:   fi.String("other-example-com"),
+				Region: fi.String("nyc1"),
+			},
+			nil,
+			nil,
+		},
+		{
+			"shared vpc found by id",
+			fakeVPCClient{
+				getFn: func(_ context.Context, id string) (*godo.VPC, *godo.Response, error) {
+					if id != "default" {
+						return nil, nil, errors.New("not found")
+					}
+					return vpcs[0], nil, nil
+				},
+			},
+			&VPC{
+				Name:   fi.String("test-example-com"),
+				ID:     fi.String("default"),
+				Region: fi.String("nyc1"),
+				Shared: fi.Bool(true),
+			},
+			&VPC{
+				Name:    fi.String("test-example-com"),
+				ID:      fi.String("default"),
+				Region:  fi.String("nyc1"),
+				IPRange: fi.String("10.116.0.0/20"),
+				Shared:  fi.Bool(true),
+			},
+			nil,
+		},
+		{
+			"error from server",
+			fakeVPCClient{
+				listFn: func(context.Context, *godo.ListOptions) ([]*godo.VPC, *godo.Response, error) {
+					return nil, nil, errors.New("error!")
+				},
+			},
+			&VPC{
+				Name:   fi.String("test-example-com"),
+				Region: fi.String("nyc1"),
+			},
+			nil,
+			errors.New("vpc service list request returned error error!"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cloud := newCloud(godo.NewClient(nil))
+			cloud.Client.VPCs = tc.vpcs
+			ctx := newContext(cloud)
+
+			actualVPC, err := tc.inVPC.Find(ctx)
+			if !reflect.DeepEqual(actualVPC, tc.outVPC) {
+				t.Error("unexpected vpc")
+				t.Logf("actual vpc: %v", actualVPC)
+				t.Logf("expected vpc: %v", tc.outVPC)
+			}
+
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Error("unexpected error")
+				t.Logf("actual err: %v", err)
+				t.Logf("expected err: %v", tc.err)
+			}
+		})
+	}
+}
+
+type fakeDropletClient struct {
+	godo.DropletsService
+
+	listByTagFn func(context.Context, string, *godo.ListOptions) ([]godo.Droplet, *godo.Response, error)
+}
+
+func (f fakeDropletClient) ListByTag(ctx context.Context, tag string, opts *godo.ListOptions) ([]godo.Droplet, *godo.Response, error) {
+	return f.listByTagFn(ctx, tag, opts)
+}
+
+func Test_VPCFindDropletsVPC(t *testing.T) {
+	defaultVPC := &godo.VPC{
+		ID:         "default",
+		Name:       "default-nyc1",
+		RegionSlug: "nyc1",
+		IPRange:    "10.116.0.0/20",
+		Default:    true,
+	}
+
+	testcases := []struct {
+		name     string
+		droplets []godo.Droplet
+		outVPC   *VPC
+	}{
+		{
+			"vpc of existing droplets is shared",
+			[]godo.Droplet{{Name: "master-nyc1.test-example-com", VPCUUID: "default"}},
+			&VPC{
+				Name:       fi.String("test-example-com"),
+				ID:         fi.String("default"),
+				Region:     fi.String("nyc1"),
+				IPRange:    fi.String("10.116.0.0/20"),
+				Shared:     fi.Bool(true),
+				DropletTag: fi.String("KubernetesCluster:test-example-com"),
+			},
+		},
+		{
+			"no existing droplets",
+			nil,
+			nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cloud := newCloud(godo.NewClient(nil))
+			cloud.Client.VPCs = fakeVPCClient{
+				listFn: func(context.Context, *godo.ListOptions) ([]*godo.VPC, *godo.Response, error) {
+					return []*godo.VPC{defaultVPC}, &godo.Response{}, nil
+				},
+				getFn: func(_ context.Context, id string) (*godo.VPC, *godo.Response, error) {
+					if id != "default" {
+						return nil, nil, errors.New("not found")
+					}
+					return defaultVPC, nil, nil
+				},
+			}
+			cloud.Client.Droplets = fakeDropletClient{
+				listByTagFn: func(_ context.Context, tag string, _ *godo.ListOptions) ([]godo.Droplet, *godo.Response, error) {
+					if tag != "KubernetesCluster:test-example-com" {
+						return nil, nil, errors.New("unexpected tag")
+					}
+					return tc.droplets, &godo.Response{}, nil
+				},
+			}
+			ctx := newContext(cloud)
+
+			vpc := &VPC{
+				Name:       fi.String("test-example-com"),
+				Region:     fi.String("nyc1"),
+				Shared:     fi.Bool(false),
+				DropletTag: fi.String("KubernetesCluster:test-example-com"),
+			}
+			actualVPC, err := vpc.Find(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actualVPC, tc.outVPC) {
+				t.Error("unexpected vpc")
+				t.Logf("actual vpc: %v", actualVPC)
+				t.Logf("expected vpc: %v", tc.outVPC)
+			}
+		})
+	}
+}
@@ -257,6 +257,11 @@ func (c *gceCloudImplementation) Labels() map[string]string {
 
 // Zones returns the zones in a region
 func (c *gceCloudImplementation) Zones() ([]string, error) {
+	return findZones(c)
+}
+
+// findZones lists the zones of the region the cloud is bound to
+func findZones(c GCECloud) ([]string, error) {
 	var zones []string
 	// TODO: Only zones in api.Cluster object, if we have one?
 	gceZones, err := c.Compute().Zones.List(c.Project()).Do()
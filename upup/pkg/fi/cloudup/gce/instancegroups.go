@@ -21,6 +21,7 @@ import (
 	"encoding/base32"
 	"fmt"
 	"hash/fnv"
+	"strconv"
 	"strings"
 
 	compute "google.golang.org/api/compute/v1"
@@ -35,10 +36,21 @@ func (c *gceCloudImplementation) DeleteGroup(g *cloudinstances.CloudInstanceGrou
 	return deleteCloudInstanceGroup(c, g)
 }
 
-// deleteCloudInstanceGroup deletes the InstanceGroupManager and current InstanceTemplate
+// deleteCloudInstanceGroup deletes the InstanceGroupManager, its detached instances and current InstanceTemplate
 func deleteCloudInstanceGroup(c GCECloud, g *cloudinstances.CloudInstanceGroup) error {
 	mig := g.Raw.(*compute.InstanceGroupManager)
-	err := DeleteInstanceGroupManager(c, mig)
+
+	detached, err := findDetachedInstances(c, mig)
+	if err != nil {
+		return fmt.Errorf("error searching for detached instances for InstanceGroupManager %q: %v", mig.Name, err)
+	}
+	for _, instance := range detached {
+		if err := DeleteInstance(c, instance.SelfLink); err != nil {
+			return err
+		}
+	}
+
+	err = DeleteInstanceGroupManager(c, mig)
 	if err != nil {
 		return err
 	}
@@ -53,24 +65,100 @@ func (c *mockGCECloud) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
 
 // DeleteInstance deletes a GCE instance
 func (c *gceCloudImplementation) DeleteInstance(i *cloudinstances.CloudInstance) error {
-	return recreateCloudInstance(c, i)
+	return deleteCloudInstance(c, i)
 }
 
 // DeleteInstance deletes a GCE instance
 func (c *mockGCECloud) DeleteInstance(i *cloudinstances.CloudInstance) error {
+	return deleteCloudInstance(c, i)
+}
+
+// deleteCloudInstance deletes an instance that was detached from its InstanceGroupManager,
+// and has the InstanceGroupManager recreate any other instance
+func deleteCloudInstance(c GCECloud, i *cloudinstances.CloudInstance) error {
+	if i.Status == cloudinstances.CloudInstanceStatusDetached {
+		return DeleteInstance(c, i.ID)
+	}
 	return recreateCloudInstance(c, i)
 }
 
-// DetachInstance is not implemented yet. It needs to cause a cloud instance to no longer be counted against the group's size limits.
+// DetachInstance causes a GCE instance to no longer be counted against the InstanceGroupManager's size
 func (c *gceCloudImplementation) DetachInstance(i *cloudinstances.CloudInstance) error {
-	klog.V(8).Info("gce cloud provider DetachInstance not implemented yet")
-	return fmt.Errorf("gce cloud provider does not support surging")
+	return detachInstance(c, i)
 }
 
-// DetachInstance is not implemented yet. It needs to cause a cloud instance to no longer be counted against the group's size limits.
+// DetachInstance causes a GCE instance to no longer be counted against the InstanceGroupManager's size
 func (c *mockGCECloud) DetachInstance(i *cloudinstances.CloudInstance) error {
-	klog.V(8).Info("gce cloud provider DetachInstance not implemented yet")
-	return fmt.Errorf("gce cloud provider does not support surging")
+	return detachInstance(c, i)
+}
+
+// detachInstance abandons the instance from its InstanceGroupManager and grows the group again,
+// so that a replacement is created while the instance keeps running until it is deleted
+func detachInstance(c GCECloud, i *cloudinstances.CloudInstance) error {
+	if i.Status == cloudinstances.CloudInstanceStatusDetached {
+		return nil
+	}
+
+	mig := i.CloudInstanceGroup.Raw.(*compute.InstanceGroupManager)
+
+	migURL, err := ParseGoogleCloudURL(mig.SelfLink)
+	if err != nil {
+		return err
+	}
+	instanceURL, err := ParseGoogleCloudURL(i.ID)
+	if err != nil {
+		return err
+	}
+
+	klog.V(2).Infof("Detaching GCE Instance %s from MIG %s", i.ID, mig.Name)
+
+	// Label the instance first, so we can still find it if we are interrupted before it is deleted
+	instance, err := c.Compute().Instances.Get(instanceURL.Project, instanceURL.Zone, instanceURL.Name).Do()
+	if err != nil {
+		return fmt.Errorf("error getting Instance %s: %v", i.ID, err)
+	}
+	labels := make(map[string]string)
+	for k, v := range instance.Labels {
+		labels[k] = v
+	}
+	labels[GceLabelNameDetachedInstance] = mig.Name
+	op, err := c.Compute().Instances.SetLabels(instanceURL.Project, instanceURL.Zone, instanceURL.Name, &compute.InstancesSetLabelsRequest{
+		Labels:           labels,
+		LabelFingerprint: instance.LabelFingerprint,
+	}).Do()
+	if err != nil {
+		return fmt.Errorf("error labeling Instance %s: %v", i.ID, err)
+	}
+	if err := c.WaitForOp(op); err != nil {
+		return fmt.Errorf("error labeling Instance %s: %v", i.ID, err)
+	}
+
+	op, err = c.Compute().InstanceGroupManagers.AbandonInstances(migURL.Project, migURL.Zone, migURL.Name, &compute.InstanceGroupManagersAbandonInstancesRequest{
+		Instances: []string{i.ID},
+	}).Do()
+	if err != nil {
+		return fmt.Errorf("error abandoning Instance %s: %v", i.ID, err)
+	}
+	if err := c.WaitForOp(op); err != nil {
+		return fmt.Errorf("error abandoning Instance %s: %v", i.ID, err)
+	}
+
+	// Abandoning decreased the target size of the MIG; restore it so a replacement instance is created
+	current, err := c.Compute().InstanceGroupManagers.Get(migURL.Project, migURL.Zone, migURL.Name).Do()
+	if err != nil {
+		return fmt.Errorf("error getting InstanceGroupManager %s: %v", mig.Name, err)
+	}
+	op, err = c.Compute().InstanceGroupManagers.Resize(migURL.Project, migURL.Zone, migURL.Name, current.TargetSize+1).Do()
+	if err != nil {
+		return fmt.Errorf("error resizing InstanceGroupManager %s: %v", mig.Name, err)
+	}
+	if err := c.WaitForOp(op); err != nil {
+		return fmt.Errorf("error resizing InstanceGroupManager %s: %v", mig.Name, err)
+	}
+
+	i.Status = cloudinstances.CloudInstanceStatusDetached
+
+	return nil
 }
 
 // recreateCloudInstance recreates the specified instances, managed by an InstanceGroupManager
@@ -182,8 +270,7 @@ func getCloudGroups(c GCECloud, cluster *kops.Cluster, instancegroups []*kops.In
 					return err
 				}
 
-				for _, i := range instances {
-					id := i.Instance
+				newCloudInstance := func(id string) *cloudinstances.CloudInstance {
 					cm := &cloudinstances.CloudInstance{
 						ID:                 id,
 						CloudInstanceGroup: g,
@@ -199,7 +286,11 @@ func getCloudGroups(c GCECloud, cluster *kops.Cluster, instancegroups []*kops.In
 					} else {
 						klog.V(8).Infof("unable to find node for instance: %s", id)
 					}
+					return cm
+				}
 
+				for _, i := range instances {
+					cm := newCloudInstance(i.Instance)
 					if i.Version != nil && latestInstanceTemplate == i.Version.InstanceTemplate {
 						g.Ready = append(g.Ready, cm)
 					} else {
@@ -207,6 +298,16 @@ func getCloudGroups(c GCECloud, cluster *kops.Cluster, instancegroups []*kops.In
 					}
 				}
 
+				detached, err := findDetachedInstances(c, mig)
+				if err != nil {
+					return fmt.Errorf("error searching for detached instances for MIG %q: %v", name, err)
+				}
+				for _, i := range detached {
+					cm := newCloudInstance(i.SelfLink)
+					cm.Status = cloudinstances.CloudInstanceStatusDetached
+					g.NeedUpdate = append(g.NeedUpdate, cm)
+				}
+
 			}
 			return nil
 		})
@@ -219,6 +320,104 @@ func getCloudGroups(c GCECloud, cluster *kops.Cluster, instancegroups []*kops.In
 	return groups, nil
 }
 
+// findDetachedInstances returns the instances that were detached from the InstanceGroupManager, but not yet deleted
+func findDetachedInstances(c GCECloud, mig *compute.InstanceGroupManager) ([]*compute.Instance, error) {
+	ctx := context.Background()
+
+	filter := fmt.Sprintf("labels.%s = %q", GceLabelNameDetachedInstance, mig.Name)
+
+	var instances []*compute.Instance
+	err := c.Compute().Instances.List(c.Project(), LastComponent(mig.Zone)).Filter(filter).Pages(ctx, func(page *compute.InstanceList) error {
+		instances = append(instances, page.Items...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing Instances: %v", err)
+	}
+
+	return instances, nil
+}
+
+// DeleteStaleInstanceTemplates deletes the InstanceTemplates that kops created with the specified name prefix,
+// once they are no longer referenced by any InstanceGroupManager nor by any of the instances they manage.
+func DeleteStaleInstanceTemplates(c GCECloud, namePrefix string) error {
+	ctx := context.Background()
+	project := c.Project()
+
+	templates := make(map[string]*compute.InstanceTemplate)
+	err := c.Compute().InstanceTemplates.List(project).Pages(ctx, func(page *compute.InstanceTemplateList) error {
+		for _, t := range page.Items {
+			// kops names templates <prefix>-<timestamp>
+			suffix := strings.TrimPrefix(t.Name, namePrefix+"-")
+			if suffix == t.Name {
+				continue
+			}
+			if _, err := strconv.ParseInt(suffix, 10, 64); err != nil {
+				continue
+			}
+			templates[t.SelfLink] = t
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error listing InstanceTemplates: %v", err)
+	}
+
+	stale := make(map[string]*compute.InstanceTemplate)
+	for k, v := range templates {
+		stale[k] = v
+	}
+
+	zones, err := c.Zones()
+	if err != nil {
+		return err
+	}
+	for _, zone := range zones {
+		err := c.Compute().InstanceGroupManagers.List(project, zone).Pages(ctx, func(page *compute.InstanceGroupManagerList) error {
+			for _, mig := range page.Items {
+				used := []string{mig.InstanceTemplate}
+				for _, version := range mig.Versions {
+					used = append(used, version.InstanceTemplate)
+				}
+
+				ours := false
+				for _, u := range used {
+					if templates[u] != nil {
+						ours = true
+					}
+					delete(stale, u)
+				}
+				if !ours {
+					continue
+				}
+
+				// Keep the templates that instances which have not been updated yet were created from
+				instances, err := ListManagedInstances(c, mig)
+				if err != nil {
+					return err
+				}
+				for _, i := range instances {
+					if i.Version != nil {
+						delete(stale, i.Version.InstanceTemplate)
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error listing InstanceGroupManagers: %v", err)
+		}
+	}
+
+	for selfLink := range stale {
+		if err := DeleteInstanceTemplate(c, selfLink); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // NameForInstanceGroupManager builds a name for an InstanceGroupManager in the specified zone
 func NameForInstanceGroupManager(c *kops.Cluster, ig *kops.InstanceGroup, zone string) string {
 	shortZone := zone
@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"reflect"
+	"testing"
+
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+const testZone = "us-test1-a"
+
+func doOp(t *testing.T, c GCECloud, op *compute.Operation, err error) {
+	if err == nil {
+		err = c.WaitForOp(op)
+	}
+	if err != nil {
+		t.Fatalf("error calling mock compute API: %v", err)
+	}
+}
+
+func createTemplates(t *testing.T, c GCECloud, names ...string) {
+	for _, name := range names {
+		template := &compute.InstanceTemplate{
+			Name:       name,
+			Properties: &compute.InstanceProperties{},
+		}
+		op, err := c.Compute().InstanceTemplates.Insert(c.Project(), template).Do()
+		doOp(t, c, op, err)
+	}
+}
+
+func templateURL(c GCECloud, name string) string {
+	return "https://www.googleapis.com/compute/v1/projects/" + c.Project() + "/global/instanceTemplates/" + name
+}
+
+func TestDeleteStaleInstanceTemplates(t *testing.T) {
+	c := buildMockGCECloud("us-test1", "testproject")
+	defer c.MockCompute.TeardownHTTP()
+
+	createTemplates(t, c, "nodes-100", "nodes-200", "nodes-300", "nodes-other-400", "other-500")
+
+	// Two instances run from nodes-100 and one from nodes-300, which the MIG now uses
+	mig := &compute.InstanceGroupManager{
+		Name:             "a-nodes",
+		BaseInstanceName: "nodes",
+		InstanceTemplate: templateURL(c, "nodes-100"),
+		TargetSize:       2,
+	}
+	op, err := c.Compute().InstanceGroupManagers.Insert(c.Project(), testZone, mig).Do()
+	doOp(t, c, op, err)
+	op, err = c.Compute().InstanceGroupManagers.SetInstanceTemplate(c.Project(), testZone, mig.Name, &compute.InstanceGroupManagersSetInstanceTemplateRequest{
+		InstanceTemplate: templateURL(c, "nodes-300"),
+	}).Do()
+	doOp(t, c, op, err)
+	op, err = c.Compute().InstanceGroupManagers.Resize(c.Project(), testZone, mig.Name, 3).Do()
+	doOp(t, c, op, err)
+
+	if err := DeleteStaleInstanceTemplates(c, "nodes"); err != nil {
+		t.Fatalf("error deleting stale templates: %v", err)
+	}
+	expected := []string{"nodes-100", "nodes-300", "nodes-other-400", "other-500"}
+	if actual := c.MockCompute.InstanceTemplates(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected templates after first cleanup; expected %v, got %v", expected, actual)
+	}
+
+	// Once all instances are recreated from nodes-300, nodes-100 is no longer needed
+	mig, err = c.Compute().InstanceGroupManagers.Get(c.Project(), testZone, mig.Name).Do()
+	if err != nil {
+		t.Fatalf("error getting MIG: %v", err)
+	}
+	instances, err := ListManagedInstances(c, mig)
+	if err != nil {
+		t.Fatalf("error listing instances: %v", err)
+	}
+	for _, i := range instances {
+		if err := recreateCloudInstance(c, &cloudinstances.CloudInstance{
+			ID:                 i.Instance,
+			CloudInstanceGroup: &cloudinstances.CloudInstanceGroup{Raw: mig},
+		}); err != nil {
+			t.Fatalf("error recreating instance: %v", err)
+		}
+	}
+
+	if err := DeleteStaleInstanceTemplates(c, "nodes"); err != nil {
+		t.Fatalf("error deleting stale templates: %v", err)
+	}
+	expected = []string{"nodes-300", "nodes-other-400", "other-500"}
+	if actual := c.MockCompute.InstanceTemplates(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected templates after second cleanup; expected %v, got %v", expected, actual)
+	}
+}
+
+func TestDeleteGroupDeletesDetachedInstances(t *testing.T) {
+	c := buildMockGCECloud("us-test1", "testproject")
+	defer c.MockCompute.TeardownHTTP()
+
+	createTemplates(t, c, "nodes-100")
+	mig := &compute.InstanceGroupManager{
+		Name:             "a-nodes",
+		BaseInstanceName: "nodes",
+		InstanceTemplate: templateURL(c, "nodes-100"),
+		TargetSize:       2,
+	}
+	op, err := c.Compute().InstanceGroupManagers.Insert(c.Project(), testZone, mig).Do()
+	doOp(t, c, op, err)
+	mig, err = c.Compute().InstanceGroupManagers.Get(c.Project(), testZone, mig.Name).Do()
+	if err != nil {
+		t.Fatalf("error getting MIG: %v", err)
+	}
+
+	group := &cloudinstances.CloudInstanceGroup{Raw: mig}
+	instances, err := ListManagedInstances(c, mig)
+	if err != nil {
+		t.Fatalf("error listing instances: %v", err)
+	}
+	if err := c.DetachInstance(&cloudinstances.CloudInstance{ID: instances[0].Instance, CloudInstanceGroup: group}); err != nil {
+		t.Fatalf("error detaching instance: %v", err)
+	}
+
+	detached, err := findDetachedInstances(c, mig)
+	if err != nil {
+		t.Fatalf("error finding detached instances: %v", err)
+	}
+	if len(detached) != 1 || detached[0].SelfLink != instances[0].Instance {
+		t.Fatalf("expected %s to be detached, got %v", instances[0].Instance, detached)
+	}
+
+	if err := c.DeleteGroup(group); err != nil {
+		t.Fatalf("error deleting group: %v", err)
+	}
+
+	remaining, err := c.Compute().Instances.List(c.Project(), testZone).Do()
+	if err != nil {
+		t.Fatalf("error listing instances: %v", err)
+	}
+	if len(remaining.Items) != 0 {
+		t.Errorf("expected all instances to be deleted, %d remaining", len(remaining.Items))
+	}
+	if templates := c.MockCompute.InstanceTemplates(); len(templates) != 0 {
+		t.Errorf("expected instance template to be deleted, got %v", templates)
+	}
+}
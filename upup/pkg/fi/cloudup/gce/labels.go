@@ -29,6 +29,10 @@ const (
 
 	GceLabelNameRolePrefix        = "k8s-io-role-"
 	GceLabelNameEtcdClusterPrefix = "k8s-io-etcd-"
+
+	// GceLabelNameDetachedInstance is set on instances that were abandoned by their InstanceGroupManager while
+	// surging, with the name of the InstanceGroupManager as value
+	GceLabelNameDetachedInstance = "k8s-io-detached-from-mig"
 )
 
 // EncodeGCELabel encodes a string into an RFC1035 compatible value, suitable for use as GCE label key or value
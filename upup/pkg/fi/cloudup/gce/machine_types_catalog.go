@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by hack/machine_types; DO NOT EDIT.
+
+package gce
+
+import "k8s.io/kops/util/pkg/architectures"
+
+// machineTypeCatalog is the offline list of machine types, regenerate it with `make update-machine-types`
+var machineTypeCatalog = []*MachineTypeInfo{
+	{Name: "a2-highgpu-1g", Cores: 12, MemoryGB: 85, GPUs: 1, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "a2-highgpu-2g", Cores: 24, MemoryGB: 170, GPUs: 2, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "a2-highgpu-4g", Cores: 48, MemoryGB: 340, GPUs: 4, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "a2-highgpu-8g", Cores: 96, MemoryGB: 680, GPUs: 8, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "a2-megagpu-16g", Cores: 96, MemoryGB: 1360, GPUs: 16, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "c2-standard-16", Cores: 16, MemoryGB: 64, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "c2-standard-30", Cores: 30, MemoryGB: 120, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "c2-standard-4", Cores: 4, MemoryGB: 16, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "c2-standard-60", Cores: 60, MemoryGB: 240, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "c2-standard-8", Cores: 8, MemoryGB: 32, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-highcpu-16", Cores: 16, MemoryGB: 16, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-highcpu-2", Cores: 2, MemoryGB: 2, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-highcpu-32", Cores: 32, MemoryGB: 32, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-highcpu-4", Cores: 4, MemoryGB: 4, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-highcpu-8", Cores: 8, MemoryGB: 8, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-highmem-16", Cores: 16, MemoryGB: 128, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-highmem-2", Cores: 2, MemoryGB: 16, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-highmem-4", Cores: 4, MemoryGB: 32, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-highmem-8", Cores: 8, MemoryGB: 64, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-medium", Cores: 2, MemoryGB: 4, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: true},
+	{Name: "e2-micro", Cores: 2, MemoryGB: 1, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: true},
+	{Name: "e2-small", Cores: 2, MemoryGB: 2, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: true},
+	{Name: "e2-standard-16", Cores: 16, MemoryGB: 64, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-standard-2", Cores: 2, MemoryGB: 8, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-standard-32", Cores: 32, MemoryGB: 128, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-standard-4", Cores: 4, MemoryGB: 16, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "e2-standard-8", Cores: 8, MemoryGB: 32, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "f1-micro", Cores: 1, MemoryGB: 0.6, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: true},
+	{Name: "g1-small", Cores: 1, MemoryGB: 1.7, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: true},
+	{Name: "m1-megamem-96", Cores: 96, MemoryGB: 1433.6, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "m1-ultramem-160", Cores: 160, MemoryGB: 3844, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "m1-ultramem-40", Cores: 40, MemoryGB: 961, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "m1-ultramem-80", Cores: 80, MemoryGB: 1922, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highcpu-16", Cores: 16, MemoryGB: 14.4, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highcpu-2", Cores: 2, MemoryGB: 1.8, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highcpu-32", Cores: 32, MemoryGB: 28.8, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highcpu-4", Cores: 4, MemoryGB: 3.6, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highcpu-64", Cores: 64, MemoryGB: 57.6, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highcpu-8", Cores: 8, MemoryGB: 7.2, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highcpu-96", Cores: 96, MemoryGB: 86.4, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highmem-16", Cores: 16, MemoryGB: 104, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highmem-2", Cores: 2, MemoryGB: 13, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highmem-32", Cores: 32, MemoryGB: 208, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highmem-4", Cores: 4, MemoryGB: 26, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highmem-64", Cores: 64, MemoryGB: 416, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highmem-8", Cores: 8, MemoryGB: 52, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-highmem-96", Cores: 96, MemoryGB: 624, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-standard-1", Cores: 1, MemoryGB: 3.75, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-standard-16", Cores: 16, MemoryGB: 60, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-standard-2", Cores: 2, MemoryGB: 7.5, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-standard-32", Cores: 32, MemoryGB: 120, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-standard-4", Cores: 4, MemoryGB: 15, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-standard-64", Cores: 64, MemoryGB: 240, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-standard-8", Cores: 8, MemoryGB: 30, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n1-standard-96", Cores: 96, MemoryGB: 360, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highcpu-16", Cores: 16, MemoryGB: 16, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highcpu-2", Cores: 2, MemoryGB: 2, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highcpu-32", Cores: 32, MemoryGB: 32, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highcpu-4", Cores: 4, MemoryGB: 4, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highcpu-48", Cores: 48, MemoryGB: 48, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highcpu-64", Cores: 64, MemoryGB: 64, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highcpu-8", Cores: 8, MemoryGB: 8, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highcpu-80", Cores: 80, MemoryGB: 80, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highcpu-96", Cores: 96, MemoryGB: 96, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highmem-128", Cores: 128, MemoryGB: 1024, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highmem-16", Cores: 16, MemoryGB: 128, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highmem-2", Cores: 2, MemoryGB: 16, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highmem-32", Cores: 32, MemoryGB: 256, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highmem-4", Cores: 4, MemoryGB: 32, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highmem-48", Cores: 48, MemoryGB: 384, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highmem-64", Cores: 64, MemoryGB: 512, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highmem-8", Cores: 8, MemoryGB: 64, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highmem-80", Cores: 80, MemoryGB: 640, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-highmem-96", Cores: 96, MemoryGB: 768, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-standard-128", Cores: 128, MemoryGB: 512, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-standard-16", Cores: 16, MemoryGB: 64, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-standard-2", Cores: 2, MemoryGB: 8, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-standard-32", Cores: 32, MemoryGB: 128, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-standard-4", Cores: 4, MemoryGB: 16, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-standard-48", Cores: 48, MemoryGB: 192, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-standard-64", Cores: 64, MemoryGB: 256, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-standard-8", Cores: 8, MemoryGB: 32, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-standard-80", Cores: 80, MemoryGB: 320, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2-standard-96", Cores: 96, MemoryGB: 384, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-128", Cores: 128, MemoryGB: 128, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-16", Cores: 16, MemoryGB: 16, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-2", Cores: 2, MemoryGB: 2, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-224", Cores: 224, MemoryGB: 224, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-32", Cores: 32, MemoryGB: 32, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-4", Cores: 4, MemoryGB: 4, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-48", Cores: 48, MemoryGB: 48, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-64", Cores: 64, MemoryGB: 64, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-8", Cores: 8, MemoryGB: 8, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-80", Cores: 80, MemoryGB: 80, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highcpu-96", Cores: 96, MemoryGB: 96, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highmem-16", Cores: 16, MemoryGB: 128, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highmem-2", Cores: 2, MemoryGB: 16, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highmem-32", Cores: 32, MemoryGB: 256, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highmem-4", Cores: 4, MemoryGB: 32, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highmem-48", Cores: 48, MemoryGB: 384, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highmem-64", Cores: 64, MemoryGB: 512, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highmem-8", Cores: 8, MemoryGB: 64, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highmem-80", Cores: 80, MemoryGB: 640, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-highmem-96", Cores: 96, MemoryGB: 768, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-128", Cores: 128, MemoryGB: 512, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-16", Cores: 16, MemoryGB: 64, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-2", Cores: 2, MemoryGB: 8, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-224", Cores: 224, MemoryGB: 896, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-32", Cores: 32, MemoryGB: 128, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-4", Cores: 4, MemoryGB: 16, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-48", Cores: 48, MemoryGB: 192, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-64", Cores: 64, MemoryGB: 256, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-8", Cores: 8, MemoryGB: 32, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-80", Cores: 80, MemoryGB: 320, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+	{Name: "n2d-standard-96", Cores: 96, MemoryGB: 384, GPUs: 0, Architecture: architectures.ArchitectureAmd64, Burstable: false},
+}
@@ -17,14 +17,17 @@ limitations under the License.
 package gce
 
 import (
+	"context"
 	"fmt"
 
 	compute "google.golang.org/api/compute/v1"
 	"google.golang.org/api/dns/v1"
 	"google.golang.org/api/iam/v1"
+	"google.golang.org/api/option"
 	"google.golang.org/api/storage/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/kops/cloudmock/gce/mockcompute"
 	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
 	dnsproviderclouddns "k8s.io/kops/dnsprovider/pkg/dnsprovider/providers/google/clouddns"
 	"k8s.io/kops/pkg/apis/kops"
@@ -37,6 +40,11 @@ type mockGCECloud struct {
 	region  string
 	project string
 	labels  map[string]string
+
+	compute *compute.Service
+
+	// MockCompute is the in-memory compute API backing Compute()
+	MockCompute *mockcompute.MockClient
 }
 
 var _ GCECloud = &mockGCECloud{}
@@ -50,19 +58,33 @@ func InstallMockGCECloud(region string, project string) *mockGCECloud {
 
 // buildMockGCECloud creates a mockGCECloud implementation for the specified region & project
 func buildMockGCECloud(region string, project string) *mockGCECloud {
-	i := &mockGCECloud{region: region, project: project}
+	mockCompute := mockcompute.CreateClient()
+	for _, suffix := range []string{"a", "b", "c"} {
+		mockCompute.AddZone(region, region+"-"+suffix)
+	}
+
+	computeService, err := compute.NewService(context.Background(), option.WithEndpoint(mockCompute.Endpoint()), option.WithoutAuthentication())
+	if err != nil {
+		klog.Fatalf("error building mock compute API client: %v", err)
+	}
+
+	i := &mockGCECloud{
+		region:      region,
+		project:     project,
+		compute:     computeService,
+		MockCompute: mockCompute,
+	}
 	return i
 }
 
-// GetCloudGroups is not implemented yet
+// GetCloudGroups implements fi.Cloud::GetCloudGroups
 func (c *mockGCECloud) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
-	klog.V(8).Infof("mockGCECloud cloud provider GetCloudGroups not implemented yet")
-	return nil, fmt.Errorf("mockGCECloud cloud provider does not support getting cloud groups at this time")
+	return getCloudGroups(c, cluster, instancegroups, warnUnmatched, nodes)
 }
 
-// Zones is not implemented yet
+// Zones implements GCECloud::Zones
 func (c *mockGCECloud) Zones() ([]string, error) {
-	return nil, fmt.Errorf("not yet implemented")
+	return findZones(c)
 }
 
 // WithLabels returns a copy of the mockGCECloud bound to the specified labels
@@ -90,8 +112,7 @@ func (c *mockGCECloud) DNS() (dnsprovider.Interface, error) {
 
 // Compute implements GCECloud::Compute
 func (c *mockGCECloud) Compute() *compute.Service {
-	klog.Fatalf("mockGCECloud::Compute not implemented")
-	return nil
+	return c.compute
 }
 
 // Storage implements GCECloud::Storage
@@ -114,7 +135,7 @@ func (c *mockGCECloud) CloudDNS() *dns.Service {
 
 // WaitForOp implements GCECloud::WaitForOp
 func (c *mockGCECloud) WaitForOp(op *compute.Operation) error {
-	return fmt.Errorf("mockGCECloud::WaitForOp not implemented")
+	return WaitForOp(c.compute, op)
 }
 
 // FindClusterStatus implements GCECloud::FindClusterStatus
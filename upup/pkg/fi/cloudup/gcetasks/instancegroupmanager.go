@@ -21,6 +21,7 @@ import (
 	"reflect"
 
 	compute "google.golang.org/api/compute/v1"
+	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
@@ -147,6 +148,13 @@ func (_ *InstanceGroupManager) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Ins
 				return fmt.Errorf("error updating InstanceTemplate for InstanceGroupManager: %v", err)
 			}
 
+			// Templates are never updated in place, so clean up the ones no instance uses anymore
+			if namePrefix := fi.StringValue(e.InstanceTemplate.NamePrefix); namePrefix != "" {
+				if err := gce.DeleteStaleInstanceTemplates(t.Cloud, namePrefix); err != nil {
+					klog.Warningf("error deleting stale InstanceTemplates for InstanceGroupManager %q: %v", i.Name, err)
+				}
+			}
+
 			changes.InstanceTemplate = nil
 		}
 
@@ -204,5 +212,14 @@ func (_ *InstanceGroupManager) RenderTerraform(t *terraform.TerraformTarget, a,
 		tf.TargetPools = append(tf.TargetPools, targetPool.TerraformLink())
 	}
 
+	ig, err := e.InstanceTemplate.instanceGroupName()
+	if err != nil {
+		return err
+	}
+	if ig != "" {
+		zone := fi.StringValue(e.Zone)
+		t.AddInputVariable(ig+"_"+zone+"_target_size", "Number of instances in instance group "+ig+" in zone "+zone, "google_compute_instance_group_manager", *e.Name, "target_size")
+	}
+
 	return t.RenderResource("google_compute_instance_group_manager", *e.Name, tf)
 }
@@ -27,6 +27,7 @@ import (
 	compute "google.golang.org/api/compute/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/diff"
+	nodeidentitygce "k8s.io/kops/pkg/nodeidentity/gce"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
@@ -587,9 +588,26 @@ func (_ *InstanceTemplate) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 		}
 	}
 
+	ig, err := e.instanceGroupName()
+	if err != nil {
+		return err
+	}
+	if ig != "" {
+		t.AddInputVariable(ig+"_machine_type", "Machine type of the instances in instance group "+ig, "google_compute_instance_template", name, "machine_type")
+	}
+
 	return t.RenderResource("google_compute_instance_template", name, tf)
 }
 
+// instanceGroupName returns the name of the kops instance group that the template is for, if it is known
+func (e *InstanceTemplate) instanceGroupName() (string, error) {
+	r := e.Metadata[nodeidentitygce.MetadataKeyInstanceGroupName]
+	if r == nil {
+		return "", nil
+	}
+	return fi.ResourceAsString(r)
+}
+
 func (i *InstanceTemplate) TerraformLink() *terraform.Literal {
 	return terraform.LiteralSelfLink("google_compute_instance_template", *i.Name)
 }
@@ -0,0 +1,336 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	kopsroot "k8s.io/kops"
+	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	TagKubernetesClusterName   = "kops.k8s.io/cluster"
+	TagKubernetesInstanceGroup = "kops.k8s.io/instance-group"
+	TagKubernetesInstanceRole  = "kops.k8s.io/instance-role"
+	TagKubernetesVolumeRole    = "kops.k8s.io/volume-role"
+	TagKubernetesEtcdMember    = "kops.k8s.io/etcd-member"
+
+	// TagKubernetesInstanceSpecHash is the hash of the specification and user data a server was created with
+	TagKubernetesInstanceSpecHash = "kops.k8s.io/spec-hash"
+	// TagKubernetesInstanceTargetSpecHash is the hash of the current specification of the server's group,
+	// servers where it differs from TagKubernetesInstanceSpecHash need to be replaced
+	TagKubernetesInstanceTargetSpecHash = "kops.k8s.io/target-spec-hash"
+)
+
+// HetznerCloud exposes all the interfaces required to operate on Hetzner Cloud resources
+type HetznerCloud interface {
+	fi.Cloud
+	ActionClient() *hcloud.ActionClient
+	SSHKeyClient() *hcloud.SSHKeyClient
+	NetworkClient() *hcloud.NetworkClient
+	FirewallClient() *hcloud.FirewallClient
+	PlacementGroupClient() *hcloud.PlacementGroupClient
+	ServerClient() *hcloud.ServerClient
+	LoadBalancerClient() *hcloud.LoadBalancerClient
+	VolumeClient() *hcloud.VolumeClient
+
+	// WaitForAction blocks until the given actions have finished
+	WaitForAction(actions ...*hcloud.Action) error
+
+	GetApiIngressStatus(cluster *kops.Cluster) ([]kops.ApiIngressStatus, error)
+	FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error)
+}
+
+type hetznerCloudImplementation struct {
+	Client *hcloud.Client
+
+	region string
+}
+
+var _ fi.Cloud = &hetznerCloudImplementation{}
+
+// hetznerCloudInstances is a cache of the clouds by region; it also allows mocks to be injected
+var hetznerCloudInstances = make(map[string]HetznerCloud)
+
+// NewHetznerCloud returns a HetznerCloud, expecting the env var HCLOUD_TOKEN
+// NewHetznerCloud will return an err if HCLOUD_TOKEN is not defined
+func NewHetznerCloud(region string) (HetznerCloud, error) {
+	if c, ok := hetznerCloudInstances[region]; ok {
+		return c, nil
+	}
+
+	token := os.Getenv("HCLOUD_TOKEN")
+	if token == "" {
+		return nil, errors.New("HCLOUD_TOKEN is required")
+	}
+
+	client := hcloud.NewClient(
+		hcloud.WithToken(token),
+		hcloud.WithApplication("kops", kopsroot.Version),
+	)
+
+	c := &hetznerCloudImplementation{
+		Client: client,
+		region: region,
+	}
+	hetznerCloudInstances[region] = c
+
+	return c, nil
+}
+
+// NetworkZone returns the Hetzner network zone that contains the given location
+func NetworkZone(location string) (hcloud.NetworkZone, error) {
+	switch location {
+	case "fsn1", "nbg1", "hel1":
+		return hcloud.NetworkZoneEUCentral, nil
+	case "ash":
+		return hcloud.NetworkZone("us-east"), nil
+	default:
+		return "", fmt.Errorf("unknown Hetzner location %q", location)
+	}
+}
+
+// ClusterLabelSelector returns the label selector matching all resources owned by the cluster
+func ClusterLabelSelector(clusterName string) string {
+	return TagKubernetesClusterName + "=" + clusterName
+}
+
+func (c *hetznerCloudImplementation) ActionClient() *hcloud.ActionClient {
+	return &c.Client.Action
+}
+
+func (c *hetznerCloudImplementation) SSHKeyClient() *hcloud.SSHKeyClient {
+	return &c.Client.SSHKey
+}
+
+func (c *hetznerCloudImplementation) NetworkClient() *hcloud.NetworkClient {
+	return &c.Client.Network
+}
+
+func (c *hetznerCloudImplementation) FirewallClient() *hcloud.FirewallClient {
+	return &c.Client.Firewall
+}
+
+func (c *hetznerCloudImplementation) PlacementGroupClient() *hcloud.PlacementGroupClient {
+	return &c.Client.PlacementGroup
+}
+
+func (c *hetznerCloudImplementation) ServerClient() *hcloud.ServerClient {
+	return &c.Client.Server
+}
+
+func (c *hetznerCloudImplementation) LoadBalancerClient() *hcloud.LoadBalancerClient {
+	return &c.Client.LoadBalancer
+}
+
+func (c *hetznerCloudImplementation) VolumeClient() *hcloud.VolumeClient {
+	return &c.Client.Volume
+}
+
+func (c *hetznerCloudImplementation) WaitForAction(actions ...*hcloud.Action) error {
+	var pending []*hcloud.Action
+	for _, action := range actions {
+		if action != nil {
+			pending = append(pending, action)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	_, errCh := c.Client.Action.WatchOverallProgress(context.TODO(), pending)
+	return <-errCh
+}
+
+// ProviderID returns the kops api identifier for Hetzner Cloud
+func (c *hetznerCloudImplementation) ProviderID() kops.CloudProviderID {
+	return kops.CloudProviderHetzner
+}
+
+// Region returns the Hetzner location we will target
+func (c *hetznerCloudImplementation) Region() string {
+	return c.region
+}
+
+// DNS is not implemented, Hetzner clusters must use gossip
+func (c *hetznerCloudImplementation) DNS() (dnsprovider.Interface, error) {
+	return nil, errors.New("DNS is not supported on Hetzner Cloud, use a gossip-based cluster name ending in .k8s.local")
+}
+
+// FindVPCInfo is not implemented, it's only here to satisfy the fi.Cloud interface
+func (c *hetznerCloudImplementation) FindVPCInfo(id string) (*fi.VPCInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+// DeleteInstance deletes the server backing the given cloud instance
+func (c *hetznerCloudImplementation) DeleteInstance(i *cloudinstances.CloudInstance) error {
+	serverID, err := strconv.Atoi(i.ID)
+	if err != nil {
+		return fmt.Errorf("failed to convert server ID %q to int: %v", i.ID, err)
+	}
+
+	klog.V(2).Infof("deleting Hetzner server %d", serverID)
+
+	_, err = c.Client.Server.Delete(context.TODO(), &hcloud.Server{ID: serverID})
+	if err != nil {
+		return fmt.Errorf("error deleting server %d: %v", serverID, err)
+	}
+
+	return nil
+}
+
+// DeleteGroup deletes all the servers of the given cloud instance group
+func (c *hetznerCloudImplementation) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
+	for _, instance := range append(g.Ready, g.NeedUpdate...) {
+		if err := c.DeleteInstance(instance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DetachInstance is not implemented yet. It needs to cause a cloud instance to no longer be counted against the group's size limits.
+func (c *hetznerCloudImplementation) DetachInstance(i *cloudinstances.CloudInstance) error {
+	return fmt.Errorf("hetzner cloud provider does not support surging")
+}
+
+func (c *hetznerCloudImplementation) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	nodeMap := cloudinstances.GetNodeMap(nodes, cluster)
+
+	servers, err := c.Client.Server.AllWithOpts(context.TODO(), hcloud.ServerListOpts{
+		ListOpts: hcloud.ListOpts{LabelSelector: ClusterLabelSelector(cluster.Name)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing servers: %v", err)
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for _, server := range servers {
+		igName := server.Labels[TagKubernetesInstanceGroup]
+
+		var ig *kops.InstanceGroup
+		for _, g := range instancegroups {
+			if g.Name == igName {
+				ig = g
+				break
+			}
+		}
+		if ig == nil {
+			if warnUnmatched {
+				klog.Warningf("Found server %q with no corresponding instance group %q", server.Name, igName)
+			}
+			continue
+		}
+
+		group := groups[ig.Name]
+		if group == nil {
+			group = &cloudinstances.CloudInstanceGroup{
+				HumanName:     ig.Name,
+				InstanceGroup: ig,
+				MinSize:       int(fi.Int32Value(ig.Spec.MinSize)),
+				TargetSize:    int(fi.Int32Value(ig.Spec.MinSize)),
+				MaxSize:       int(fi.Int32Value(ig.Spec.MaxSize)),
+			}
+			groups[ig.Name] = group
+		}
+
+		status := cloudinstances.CloudInstanceStatusUpToDate
+		if server.Labels[TagKubernetesInstanceSpecHash] != server.Labels[TagKubernetesInstanceTargetSpecHash] {
+			status = cloudinstances.CloudInstanceStatusNeedsUpdate
+		}
+
+		_, err := group.NewCloudInstance(strconv.Itoa(server.ID), status, nodeMap)
+		if err != nil {
+			return nil, fmt.Errorf("error creating cloud instance group member: %v", err)
+		}
+	}
+
+	return groups, nil
+}
+
+// GetApiIngressStatus returns the public addresses of the API load balancer
+func (c *hetznerCloudImplementation) GetApiIngressStatus(cluster *kops.Cluster) ([]kops.ApiIngressStatus, error) {
+	if cluster.Spec.MasterPublicName == "" {
+		return nil, nil
+	}
+
+	loadBalancers, err := c.Client.LoadBalancer.AllWithOpts(context.TODO(), hcloud.LoadBalancerListOpts{
+		ListOpts: hcloud.ListOpts{LabelSelector: ClusterLabelSelector(cluster.Name)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing load balancers: %v", err)
+	}
+
+	var ingresses []kops.ApiIngressStatus
+	for _, lb := range loadBalancers {
+		if lb.PublicNet.Enabled && lb.PublicNet.IPv4.IP != nil {
+			ingresses = append(ingresses, kops.ApiIngressStatus{IP: lb.PublicNet.IPv4.IP.String()})
+		}
+	}
+
+	return ingresses, nil
+}
+
+// FindClusterStatus discovers the status of the cluster, by looking for the labelled etcd volumes
+func (c *hetznerCloudImplementation) FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error) {
+	volumes, err := c.Client.Volume.AllWithOpts(context.TODO(), hcloud.VolumeListOpts{
+		ListOpts: hcloud.ListOpts{LabelSelector: ClusterLabelSelector(cluster.Name)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing volumes: %v", err)
+	}
+
+	statusMap := make(map[string]*kops.EtcdClusterStatus)
+	for _, volume := range volumes {
+		etcdClusterName := volume.Labels[TagKubernetesVolumeRole]
+		if etcdClusterName == "" {
+			continue
+		}
+
+		status := statusMap[etcdClusterName]
+		if status == nil {
+			status = &kops.EtcdClusterStatus{
+				Name: etcdClusterName,
+			}
+			statusMap[etcdClusterName] = status
+		}
+
+		status.Members = append(status.Members, &kops.EtcdMemberStatus{
+			Name:     volume.Labels[TagKubernetesEtcdMember],
+			VolumeId: strconv.Itoa(volume.ID),
+		})
+	}
+
+	status := &kops.ClusterStatus{}
+	for _, etcdStatus := range statusMap {
+		status.EtcdClusters = append(status.EtcdClusters, *etcdStatus)
+	}
+	klog.V(2).Infof("Cluster status (from cloud): %v", fi.DebugAsJsonString(status))
+
+	return status, nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetzner
+
+import (
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"k8s.io/kops/cloudmock/hetzner/mockhcloud"
+)
+
+// MockCloud is a HetznerCloud backed by an in-memory mock of the Hetzner Cloud API
+type MockCloud struct {
+	hetznerCloudImplementation
+
+	MockClient *mockhcloud.MockClient
+}
+
+var _ HetznerCloud = &MockCloud{}
+
+// BuildMockHetznerCloud returns a HetznerCloud talking to a new mock API server
+func BuildMockHetznerCloud(region string) *MockCloud {
+	mockClient := mockhcloud.CreateClient()
+
+	return &MockCloud{
+		hetznerCloudImplementation: hetznerCloudImplementation{
+			Client: hcloud.NewClient(
+				hcloud.WithEndpoint(mockClient.Endpoint()),
+				hcloud.WithToken("mock"),
+				hcloud.WithPollInterval(time.Millisecond),
+			),
+			region: region,
+		},
+		MockClient: mockClient,
+	}
+}
+
+// InstallMockHetznerCloud builds a mock cloud and makes NewHetznerCloud return it for the region
+func InstallMockHetznerCloud(region string) *MockCloud {
+	c := BuildMockHetznerCloud(region)
+	hetznerCloudInstances[region] = c
+	return c
+}
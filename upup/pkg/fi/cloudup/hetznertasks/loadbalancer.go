@@ -0,0 +1,273 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznertasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+)
+
+// LoadBalancer is a Hetzner Cloud load balancer, forwarding TCP traffic to the servers matching a label selector
+// +kops:fitask
+type LoadBalancer struct {
+	Name      *string
+	ID        *int
+	Lifecycle *fi.Lifecycle
+
+	Location string
+	Type     string
+	Services []*LoadBalancerService
+	Target   string
+	Network  *Network
+	Labels   map[string]string
+
+	// ForAPIServer indicates the address of the load balancer must be added to the API server certificate
+	ForAPIServer bool
+}
+
+// LoadBalancerService is a TCP service of a LoadBalancer
+type LoadBalancerService struct {
+	ListenerPort    *int
+	DestinationPort *int
+}
+
+func (_ *LoadBalancerService) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	return nil
+}
+
+var _ fi.CompareWithID = &LoadBalancer{}
+var _ fi.HasAddress = &LoadBalancer{}
+
+func (v *LoadBalancer) CompareWithID() *string {
+	return v.Name
+}
+
+func (v *LoadBalancer) Find(c *fi.Context) (*LoadBalancer, error) {
+	cloud := c.Cloud.(hetzner.HetznerCloud)
+
+	loadBalancer, _, err := cloud.LoadBalancerClient().GetByName(context.TODO(), fi.StringValue(v.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error finding load balancer %q: %v", fi.StringValue(v.Name), err)
+	}
+	if loadBalancer == nil {
+		return nil, nil
+	}
+
+	actual := &LoadBalancer{
+		Name:      fi.String(loadBalancer.Name),
+		ID:        fi.Int(loadBalancer.ID),
+		Lifecycle: v.Lifecycle,
+		Labels:    loadBalancer.Labels,
+
+		// Ignore system fields
+		ForAPIServer: v.ForAPIServer,
+	}
+	if loadBalancer.Location != nil {
+		actual.Location = loadBalancer.Location.Name
+	}
+	if loadBalancer.LoadBalancerType != nil {
+		actual.Type = loadBalancer.LoadBalancerType.Name
+	}
+	for _, service := range loadBalancer.Services {
+		actual.Services = append(actual.Services, &LoadBalancerService{
+			ListenerPort:    fi.Int(service.ListenPort),
+			DestinationPort: fi.Int(service.DestinationPort),
+		})
+	}
+	for _, target := range loadBalancer.Targets {
+		if target.Type == hcloud.LoadBalancerTargetTypeLabelSelector && target.LabelSelector != nil {
+			actual.Target = target.LabelSelector.Selector
+		}
+	}
+	if v.Network != nil {
+		for _, privateNet := range loadBalancer.PrivateNet {
+			if privateNet.Network != nil && privateNet.Network.ID == fi.IntValue(v.Network.ID) {
+				actual.Network = v.Network
+			}
+		}
+	}
+
+	// Avoid spurious changes
+	v.ID = actual.ID
+
+	return actual, nil
+}
+
+func (v *LoadBalancer) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(v, c)
+}
+
+func (_ *LoadBalancer) CheckChanges(a, e, changes *LoadBalancer) error {
+	if a != nil {
+		if changes.Name != nil {
+			return fi.CannotChangeField("Name")
+		}
+		if changes.Location != "" {
+			return fi.CannotChangeField("Location")
+		}
+		if changes.Type != "" {
+			return fi.CannotChangeField("Type")
+		}
+		if changes.Network != nil {
+			return fi.CannotChangeField("Network")
+		}
+	} else {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Location == "" {
+			return fi.RequiredField("Location")
+		}
+		if e.Type == "" {
+			return fi.RequiredField("Type")
+		}
+		if e.Target == "" {
+			return fi.RequiredField("Target")
+		}
+	}
+	return nil
+}
+
+func (_ *LoadBalancer) RenderHetzner(t *hetzner.HetznerAPITarget, a, e, changes *LoadBalancer) error {
+	client := t.Cloud.LoadBalancerClient()
+
+	if a == nil {
+		opts := hcloud.LoadBalancerCreateOpts{
+			Name:             fi.StringValue(e.Name),
+			LoadBalancerType: &hcloud.LoadBalancerType{Name: e.Type},
+			Algorithm:        &hcloud.LoadBalancerAlgorithm{Type: hcloud.LoadBalancerAlgorithmTypeRoundRobin},
+			Location:         &hcloud.Location{Name: e.Location},
+			Labels:           e.Labels,
+			Targets: []hcloud.LoadBalancerCreateOptsTarget{
+				{
+					Type:          hcloud.LoadBalancerTargetTypeLabelSelector,
+					LabelSelector: hcloud.LoadBalancerCreateOptsTargetLabelSelector{Selector: e.Target},
+					UsePrivateIP:  fi.Bool(e.Network != nil),
+				},
+			},
+			PublicInterface: fi.Bool(true),
+		}
+		for _, service := range e.Services {
+			opts.Services = append(opts.Services, hcloud.LoadBalancerCreateOptsService{
+				Protocol:        hcloud.LoadBalancerServiceProtocolTCP,
+				ListenPort:      service.ListenerPort,
+				DestinationPort: service.DestinationPort,
+			})
+		}
+		if e.Network != nil {
+			opts.Network = &hcloud.Network{ID: fi.IntValue(e.Network.ID)}
+		}
+
+		result, _, err := client.Create(context.TODO(), opts)
+		if err != nil {
+			return fmt.Errorf("error creating load balancer %q: %v", fi.StringValue(e.Name), err)
+		}
+		if err := t.Cloud.WaitForAction(result.Action); err != nil {
+			return fmt.Errorf("error waiting for load balancer %q: %v", fi.StringValue(e.Name), err)
+		}
+		e.ID = fi.Int(result.LoadBalancer.ID)
+
+		return nil
+	}
+
+	loadBalancer := &hcloud.LoadBalancer{ID: fi.IntValue(a.ID)}
+
+	if changes.Labels != nil {
+		_, _, err := client.Update(context.TODO(), loadBalancer, hcloud.LoadBalancerUpdateOpts{
+			Labels: e.Labels,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating labels of load balancer %q: %v", fi.StringValue(e.Name), err)
+		}
+	}
+
+	if changes.Services != nil {
+		for _, service := range e.Services {
+			if a.hasListenerPort(fi.IntValue(service.ListenerPort)) {
+				continue
+			}
+			action, _, err := client.AddService(context.TODO(), loadBalancer, hcloud.LoadBalancerAddServiceOpts{
+				Protocol:        hcloud.LoadBalancerServiceProtocolTCP,
+				ListenPort:      service.ListenerPort,
+				DestinationPort: service.DestinationPort,
+			})
+			if err != nil {
+				return fmt.Errorf("error adding service to load balancer %q: %v", fi.StringValue(e.Name), err)
+			}
+			if err := t.Cloud.WaitForAction(action); err != nil {
+				return fmt.Errorf("error waiting for load balancer %q: %v", fi.StringValue(e.Name), err)
+			}
+		}
+	}
+
+	if changes.Target != "" {
+		action, _, err := client.AddLabelSelectorTarget(context.TODO(), loadBalancer, hcloud.LoadBalancerAddLabelSelectorTargetOpts{
+			Selector:     e.Target,
+			UsePrivateIP: fi.Bool(e.Network != nil),
+		})
+		if err != nil {
+			return fmt.Errorf("error adding target to load balancer %q: %v", fi.StringValue(e.Name), err)
+		}
+		if err := t.Cloud.WaitForAction(action); err != nil {
+			return fmt.Errorf("error waiting for load balancer %q: %v", fi.StringValue(e.Name), err)
+		}
+
+		if a.Target != "" {
+			action, _, err := client.RemoveLabelSelectorTarget(context.TODO(), loadBalancer, a.Target)
+			if err != nil {
+				return fmt.Errorf("error removing target %q from load balancer %q: %v", a.Target, fi.StringValue(e.Name), err)
+			}
+			if err := t.Cloud.WaitForAction(action); err != nil {
+				return fmt.Errorf("error waiting for load balancer %q: %v", fi.StringValue(e.Name), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *LoadBalancer) hasListenerPort(port int) bool {
+	for _, service := range v.Services {
+		if fi.IntValue(service.ListenerPort) == port {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *LoadBalancer) IsForAPIServer() bool {
+	return v.ForAPIServer
+}
+
+func (v *LoadBalancer) FindIPAddress(c *fi.Context) (*string, error) {
+	cloud := c.Cloud.(hetzner.HetznerCloud)
+
+	loadBalancer, _, err := cloud.LoadBalancerClient().GetByName(context.TODO(), fi.StringValue(v.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error finding load balancer %q: %v", fi.StringValue(v.Name), err)
+	}
+	if loadBalancer == nil || loadBalancer.PublicNet.IPv4.IP == nil {
+		return nil, nil
+	}
+
+	address := loadBalancer.PublicNet.IPv4.IP.String()
+	return &address, nil
+}
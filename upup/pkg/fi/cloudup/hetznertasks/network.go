@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznertasks
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+)
+
+// Network is a Hetzner Cloud private network, with one cloud subnet per network zone
+// +kops:fitask
+type Network struct {
+	Name      *string
+	ID        *int
+	Lifecycle *fi.Lifecycle
+
+	IPRange string
+	Subnets []string
+	Region  string
+	Labels  map[string]string
+}
+
+var _ fi.CompareWithID = &Network{}
+
+func (v *Network) CompareWithID() *string {
+	return v.Name
+}
+
+func (v *Network) Find(c *fi.Context) (*Network, error) {
+	cloud := c.Cloud.(hetzner.HetznerCloud)
+
+	network, _, err := cloud.NetworkClient().GetByName(context.TODO(), fi.StringValue(v.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error finding network %q: %v", fi.StringValue(v.Name), err)
+	}
+	if network == nil {
+		return nil, nil
+	}
+
+	actual := &Network{
+		Name:      fi.String(network.Name),
+		ID:        fi.Int(network.ID),
+		Lifecycle: v.Lifecycle,
+		Labels:    network.Labels,
+		Region:    v.Region,
+	}
+	if network.IPRange != nil {
+		actual.IPRange = network.IPRange.String()
+	}
+	for _, subnet := range network.Subnets {
+		if subnet.IPRange != nil {
+			actual.Subnets = append(actual.Subnets, subnet.IPRange.String())
+		}
+	}
+
+	// Avoid spurious changes
+	v.ID = actual.ID
+
+	return actual, nil
+}
+
+func (v *Network) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(v, c)
+}
+
+func (_ *Network) CheckChanges(a, e, changes *Network) error {
+	if a != nil {
+		if changes.Name != nil {
+			return fi.CannotChangeField("Name")
+		}
+		if changes.IPRange != "" {
+			return fi.CannotChangeField("IPRange")
+		}
+	} else {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.IPRange == "" {
+			return fi.RequiredField("IPRange")
+		}
+		if e.Region == "" {
+			return fi.RequiredField("Region")
+		}
+	}
+	return nil
+}
+
+func (_ *Network) RenderHetzner(t *hetzner.HetznerAPITarget, a, e, changes *Network) error {
+	client := t.Cloud.NetworkClient()
+
+	networkZone, err := hetzner.NetworkZone(e.Region)
+	if err != nil {
+		return err
+	}
+
+	if a == nil {
+		_, ipRange, err := net.ParseCIDR(e.IPRange)
+		if err != nil {
+			return fmt.Errorf("error parsing IP range %q of network %q: %v", e.IPRange, fi.StringValue(e.Name), err)
+		}
+
+		opts := hcloud.NetworkCreateOpts{
+			Name:    fi.StringValue(e.Name),
+			IPRange: ipRange,
+			Labels:  e.Labels,
+		}
+		for _, subnet := range e.Subnets {
+			_, subnetIPRange, err := net.ParseCIDR(subnet)
+			if err != nil {
+				return fmt.Errorf("error parsing subnet %q of network %q: %v", subnet, fi.StringValue(e.Name), err)
+			}
+			opts.Subnets = append(opts.Subnets, hcloud.NetworkSubnet{
+				Type:        hcloud.NetworkSubnetTypeCloud,
+				IPRange:     subnetIPRange,
+				NetworkZone: networkZone,
+			})
+		}
+
+		network, _, err := client.Create(context.TODO(), opts)
+		if err != nil {
+			return fmt.Errorf("error creating network %q: %v", fi.StringValue(e.Name), err)
+		}
+		e.ID = fi.Int(network.ID)
+
+		return nil
+	}
+
+	network := &hcloud.Network{ID: fi.IntValue(a.ID)}
+
+	if changes.Labels != nil {
+		_, _, err := client.Update(context.TODO(), network, hcloud.NetworkUpdateOpts{
+			Labels: e.Labels,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating labels of network %q: %v", fi.StringValue(e.Name), err)
+		}
+	}
+
+	if changes.Subnets != nil {
+		for _, subnet := range e.Subnets {
+			if fi.ArrayContains(a.Subnets, subnet) {
+				continue
+			}
+
+			_, subnetIPRange, err := net.ParseCIDR(subnet)
+			if err != nil {
+				return fmt.Errorf("error parsing subnet %q of network %q: %v", subnet, fi.StringValue(e.Name), err)
+			}
+			action, _, err := client.AddSubnet(context.TODO(), network, hcloud.NetworkAddSubnetOpts{
+				Subnet: hcloud.NetworkSubnet{
+					Type:        hcloud.NetworkSubnetTypeCloud,
+					IPRange:     subnetIPRange,
+					NetworkZone: networkZone,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("error adding subnet %q to network %q: %v", subnet, fi.StringValue(e.Name), err)
+			}
+			if err := t.Cloud.WaitForAction(action); err != nil {
+				return fmt.Errorf("error waiting for subnet %q of network %q: %v", subnet, fi.StringValue(e.Name), err)
+			}
+		}
+	}
+
+	return nil
+}
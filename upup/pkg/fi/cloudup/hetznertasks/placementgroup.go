@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznertasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+)
+
+// PlacementGroup is a Hetzner Cloud placement group, used to spread servers across physical hosts
+// +kops:fitask
+type PlacementGroup struct {
+	Name      *string
+	ID        *int
+	Lifecycle *fi.Lifecycle
+
+	Type   string
+	Labels map[string]string
+}
+
+var _ fi.CompareWithID = &PlacementGroup{}
+
+func (v *PlacementGroup) CompareWithID() *string {
+	return v.Name
+}
+
+func (v *PlacementGroup) Find(c *fi.Context) (*PlacementGroup, error) {
+	cloud := c.Cloud.(hetzner.HetznerCloud)
+
+	placementGroup, _, err := cloud.PlacementGroupClient().GetByName(context.TODO(), fi.StringValue(v.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error finding placement group %q: %v", fi.StringValue(v.Name), err)
+	}
+	if placementGroup == nil {
+		return nil, nil
+	}
+
+	// Avoid spurious changes
+	v.ID = fi.Int(placementGroup.ID)
+
+	return &PlacementGroup{
+		Name:      fi.String(placementGroup.Name),
+		ID:        fi.Int(placementGroup.ID),
+		Lifecycle: v.Lifecycle,
+		Type:      string(placementGroup.Type),
+		Labels:    placementGroup.Labels,
+	}, nil
+}
+
+func (v *PlacementGroup) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(v, c)
+}
+
+func (_ *PlacementGroup) CheckChanges(a, e, changes *PlacementGroup) error {
+	if a != nil {
+		if changes.Name != nil {
+			return fi.CannotChangeField("Name")
+		}
+		if changes.Type != "" {
+			return fi.CannotChangeField("Type")
+		}
+	} else {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Type == "" {
+			return fi.RequiredField("Type")
+		}
+	}
+	return nil
+}
+
+func (_ *PlacementGroup) RenderHetzner(t *hetzner.HetznerAPITarget, a, e, changes *PlacementGroup) error {
+	client := t.Cloud.PlacementGroupClient()
+
+	if a == nil {
+		result, _, err := client.Create(context.TODO(), hcloud.PlacementGroupCreateOpts{
+			Name:   fi.StringValue(e.Name),
+			Type:   hcloud.PlacementGroupType(e.Type),
+			Labels: e.Labels,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating placement group %q: %v", fi.StringValue(e.Name), err)
+		}
+		e.ID = fi.Int(result.PlacementGroup.ID)
+
+		return nil
+	}
+
+	if changes.Labels != nil {
+		_, _, err := client.Update(context.TODO(), &hcloud.PlacementGroup{ID: fi.IntValue(a.ID)}, hcloud.PlacementGroupUpdateOpts{
+			Labels: e.Labels,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating labels of placement group %q: %v", fi.StringValue(e.Name), err)
+		}
+	}
+
+	return nil
+}
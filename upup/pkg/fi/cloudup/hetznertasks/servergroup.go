@@ -0,0 +1,284 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznertasks
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+)
+
+// ServerGroup represents the group of servers of an instance group.
+// Hetzner Cloud has no managed instance groups, so kops creates and deletes
+// the servers itself to keep Count servers running.
+// +kops:fitask
+type ServerGroup struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	Count    int
+	Location string
+	Size     string
+	Image    string
+
+	SSHKeys        []*SSHKey
+	Network        *Network
+	PlacementGroup *PlacementGroup
+
+	UserData fi.Resource
+	Labels   map[string]string
+
+	// SpecHash is the hash of the specification and user data of the servers, computed by Find.
+	// Servers are labelled with the hash they were created with and the current hash of their group,
+	// so that a rolling update can replace the servers where the two differ.
+	SpecHash string
+}
+
+var _ fi.CompareWithID = &ServerGroup{}
+
+func (v *ServerGroup) CompareWithID() *string {
+	return v.Name
+}
+
+func (v *ServerGroup) Find(c *fi.Context) (*ServerGroup, error) {
+	cloud := c.Cloud.(hetzner.HetznerCloud)
+
+	specHash, err := v.specHash()
+	if err != nil {
+		return nil, err
+	}
+	v.SpecHash = specHash
+
+	servers, err := v.listServers(cloud)
+	if err != nil {
+		return nil, err
+	}
+	if len(servers) == 0 {
+		return nil, nil
+	}
+
+	// Servers are immutable: changes to the specification only apply to new servers,
+	// existing ones are labelled with the new hash and replaced by a rolling update
+	actualSpecHash := specHash
+	for _, server := range servers {
+		if server.Labels[hetzner.TagKubernetesInstanceTargetSpecHash] != specHash {
+			actualSpecHash = server.Labels[hetzner.TagKubernetesInstanceTargetSpecHash]
+			break
+		}
+	}
+
+	return &ServerGroup{
+		Name:           v.Name,
+		Lifecycle:      v.Lifecycle,
+		Count:          len(servers),
+		Location:       v.Location,
+		Size:           v.Size,
+		Image:          v.Image,
+		SSHKeys:        v.SSHKeys,
+		Network:        v.Network,
+		PlacementGroup: v.PlacementGroup,
+		UserData:       v.UserData,
+		Labels:         v.Labels,
+		SpecHash:       actualSpecHash,
+	}, nil
+}
+
+// specHash returns a hash of the specification and user data of the servers, short enough to be a label value
+func (v *ServerGroup) specHash() (string, error) {
+	userData, err := fi.ResourceAsString(v.UserData)
+	if err != nil {
+		return "", err
+	}
+
+	var sshKeys []string
+	for _, sshKey := range v.SSHKeys {
+		sshKeys = append(sshKeys, fi.StringValue(sshKey.Name))
+	}
+	var network, placementGroup string
+	if v.Network != nil {
+		network = fi.StringValue(v.Network.Name)
+	}
+	if v.PlacementGroup != nil {
+		placementGroup = fi.StringValue(v.PlacementGroup.Name)
+	}
+
+	spec := strings.Join([]string{v.Location, v.Size, v.Image, strings.Join(sshKeys, ","), network, placementGroup, userData}, "\n")
+	hash := sha256.Sum256([]byte(spec))
+	return hex.EncodeToString(hash[:16]), nil
+}
+
+// listServers returns the servers of the group, oldest first
+func (v *ServerGroup) listServers(cloud hetzner.HetznerCloud) ([]*hcloud.Server, error) {
+	selector := fmt.Sprintf("%s=%s,%s=%s",
+		hetzner.TagKubernetesClusterName, v.Labels[hetzner.TagKubernetesClusterName],
+		hetzner.TagKubernetesInstanceGroup, v.Labels[hetzner.TagKubernetesInstanceGroup])
+
+	servers, err := cloud.ServerClient().AllWithOpts(context.TODO(), hcloud.ServerListOpts{
+		ListOpts: hcloud.ListOpts{LabelSelector: selector},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing servers of group %q: %v", fi.StringValue(v.Name), err)
+	}
+
+	sort.SliceStable(servers, func(i, j int) bool {
+		if servers[i].Created.Equal(servers[j].Created) {
+			return servers[i].ID < servers[j].ID
+		}
+		return servers[i].Created.Before(servers[j].Created)
+	})
+
+	return servers, nil
+}
+
+func (v *ServerGroup) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(v, c)
+}
+
+func (_ *ServerGroup) CheckChanges(a, e, changes *ServerGroup) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Location == "" {
+			return fi.RequiredField("Location")
+		}
+		if e.Size == "" {
+			return fi.RequiredField("Size")
+		}
+		if e.Image == "" {
+			return fi.RequiredField("Image")
+		}
+	}
+	if e.Labels[hetzner.TagKubernetesClusterName] == "" || e.Labels[hetzner.TagKubernetesInstanceGroup] == "" {
+		return fmt.Errorf("server group %q must be labelled with the cluster and instance group names", fi.StringValue(e.Name))
+	}
+	return nil
+}
+
+func (_ *ServerGroup) RenderHetzner(t *hetzner.HetznerAPITarget, a, e, changes *ServerGroup) error {
+	actualCount := 0
+	if a != nil {
+		actualCount = a.Count
+	}
+
+	if a != nil && changes.SpecHash != "" {
+		servers, err := e.listServers(t.Cloud)
+		if err != nil {
+			return err
+		}
+		for _, server := range servers {
+			if server.Labels[hetzner.TagKubernetesInstanceTargetSpecHash] == e.SpecHash {
+				continue
+			}
+			labels := make(map[string]string)
+			for k, v := range server.Labels {
+				labels[k] = v
+			}
+			labels[hetzner.TagKubernetesInstanceTargetSpecHash] = e.SpecHash
+
+			klog.V(2).Infof("Labelling server %q of group %q with the new specification hash", server.Name, fi.StringValue(e.Name))
+			if _, _, err := t.Cloud.ServerClient().Update(context.TODO(), server, hcloud.ServerUpdateOpts{Labels: labels}); err != nil {
+				return fmt.Errorf("error updating labels of server %q: %v", server.Name, err)
+			}
+		}
+	}
+
+	if actualCount == e.Count {
+		return nil
+	}
+
+	if actualCount > e.Count {
+		servers, err := e.listServers(t.Cloud)
+		if err != nil {
+			return err
+		}
+		// Remove the most recent servers first
+		for i := len(servers) - 1; i >= e.Count; i-- {
+			klog.V(2).Infof("Deleting server %q of group %q", servers[i].Name, fi.StringValue(e.Name))
+			if _, err := t.Cloud.ServerClient().Delete(context.TODO(), servers[i]); err != nil {
+				return fmt.Errorf("error deleting server %q: %v", servers[i].Name, err)
+			}
+		}
+		return nil
+	}
+
+	userData, err := fi.ResourceAsString(e.UserData)
+	if err != nil {
+		return err
+	}
+
+	labels := make(map[string]string)
+	for k, v := range e.Labels {
+		labels[k] = v
+	}
+	labels[hetzner.TagKubernetesInstanceSpecHash] = e.SpecHash
+	labels[hetzner.TagKubernetesInstanceTargetSpecHash] = e.SpecHash
+
+	opts := hcloud.ServerCreateOpts{
+		ServerType: &hcloud.ServerType{Name: e.Size},
+		Image:      &hcloud.Image{Name: e.Image},
+		Location:   &hcloud.Location{Name: e.Location},
+		UserData:   userData,
+		Labels:     labels,
+	}
+	for _, sshKey := range e.SSHKeys {
+		opts.SSHKeys = append(opts.SSHKeys, &hcloud.SSHKey{ID: fi.IntValue(sshKey.ID)})
+	}
+	if e.Network != nil {
+		opts.Networks = append(opts.Networks, &hcloud.Network{ID: fi.IntValue(e.Network.ID)})
+	}
+	if e.PlacementGroup != nil {
+		opts.PlacementGroup = &hcloud.PlacementGroup{ID: fi.IntValue(e.PlacementGroup.ID)}
+	}
+
+	for i := actualCount; i < e.Count; i++ {
+		suffix, err := randomSuffix()
+		if err != nil {
+			return err
+		}
+		opts.Name = fi.StringValue(e.Name) + "-" + suffix
+
+		klog.V(2).Infof("Creating server %q of group %q", opts.Name, fi.StringValue(e.Name))
+		result, _, err := t.Cloud.ServerClient().Create(context.TODO(), opts)
+		if err != nil {
+			return fmt.Errorf("error creating server %q: %v", opts.Name, err)
+		}
+		if err := t.Cloud.WaitForAction(append([]*hcloud.Action{result.Action}, result.NextActions...)...); err != nil {
+			return fmt.Errorf("error waiting for server %q: %v", opts.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// randomSuffix returns a short random hex string to make server names unique
+func randomSuffix() (string, error) {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating server name: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznertasks
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+)
+
+var testRunTasksOptions = fi.RunTasksOptions{
+	MaxTaskDuration:         2 * time.Second,
+	WaitAfterAllTasksFailed: 500 * time.Millisecond,
+}
+
+const testPublicKey = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAAgQCtWu40XQo8dczLsCq0OWV+hxm9uV3WxeH9Kgh4sMzQxNtoU1pvW0XdjpkBesRKGoolfWeCLXWxpyQb1IaiMkKoz7MdhQ/6UKjMjP66aFWWp3pwD0uj0HuJ7tq4gKHKRYGTaZIRWpzUiANBrjugVgA+Sd7E/mYwc/DMXkIyRZbvhQ=="
+
+func TestServerGroup(t *testing.T) {
+	cloud := hetzner.BuildMockHetznerCloud("fsn1")
+	defer cloud.MockClient.TeardownHTTP()
+
+	labels := map[string]string{
+		hetzner.TagKubernetesClusterName:   "minimal.k8s.local",
+		hetzner.TagKubernetesInstanceGroup: "nodes-fsn1",
+	}
+
+	// We define a function so we can rebuild the tasks, because we modify in-place when running
+	buildTasks := func(count int) map[string]fi.Task {
+		sshKey := &SSHKey{
+			Name:      fi.String("minimal.k8s.local"),
+			PublicKey: testPublicKey,
+			Labels:    map[string]string{hetzner.TagKubernetesClusterName: "minimal.k8s.local"},
+		}
+		network := &Network{
+			Name:    fi.String("minimal.k8s.local"),
+			IPRange: "10.10.0.0/16",
+			Subnets: []string{"10.10.0.0/16"},
+			Region:  "fsn1",
+			Labels:  map[string]string{hetzner.TagKubernetesClusterName: "minimal.k8s.local"},
+		}
+		serverGroup := &ServerGroup{
+			Name:     fi.String("nodes-fsn1"),
+			Count:    count,
+			Location: "fsn1",
+			Size:     "cx21",
+			Image:    "ubuntu-20.04",
+			SSHKeys:  []*SSHKey{sshKey},
+			Network:  network,
+			UserData: fi.NewStringResource("#!/bin/bash"),
+			Labels:   labels,
+		}
+		return map[string]fi.Task{
+			"sshKey":      sshKey,
+			"network":     network,
+			"serverGroup": serverGroup,
+		}
+	}
+
+	runTasks(t, cloud, buildTasks(3))
+
+	servers := listServers(t, cloud, labels)
+	if len(servers) != 3 {
+		t.Fatalf("expected 3 servers, found %d", len(servers))
+	}
+	for _, server := range servers {
+		if len(server.PrivateNet) != 1 {
+			t.Errorf("expected server %q to be attached to the network, found %v", server.Name, server.PrivateNet)
+		}
+	}
+
+	checkNoChanges(t, cloud, buildTasks(3))
+
+	runTasks(t, cloud, buildTasks(1))
+
+	remaining := listServers(t, cloud, labels)
+	if len(remaining) != 1 {
+		t.Fatalf("expected 1 server after scaling down, found %d", len(remaining))
+	}
+	if remaining[0].ID != servers[0].ID {
+		t.Errorf("expected the oldest server %q to be kept, found %q", servers[0].Name, remaining[0].Name)
+	}
+
+	checkNoChanges(t, cloud, buildTasks(1))
+
+	cluster := &kops.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "minimal.k8s.local"}}
+	instanceGroups := []*kops.InstanceGroup{{ObjectMeta: metav1.ObjectMeta{Name: "nodes-fsn1"}}}
+	checkCloudGroup := func(ready, needUpdate int) {
+		t.Helper()
+		groups, err := cloud.GetCloudGroups(cluster, instanceGroups, false, nil)
+		if err != nil {
+			t.Fatalf("error getting cloud groups: %v", err)
+		}
+		group := groups["nodes-fsn1"]
+		if group == nil {
+			t.Fatalf("expected cloud group %q, found %v", "nodes-fsn1", groups)
+		}
+		if len(group.Ready) != ready || len(group.NeedUpdate) != needUpdate {
+			t.Errorf("expected %d ready and %d outdated servers, found %d and %d", ready, needUpdate, len(group.Ready), len(group.NeedUpdate))
+		}
+	}
+
+	checkCloudGroup(1, 0)
+
+	withNewImage := func(count int) map[string]fi.Task {
+		tasks := buildTasks(count)
+		tasks["serverGroup"].(*ServerGroup).Image = "ubuntu-22.04"
+		return tasks
+	}
+
+	runTasks(t, cloud, withNewImage(1))
+	checkNoChanges(t, cloud, withNewImage(1))
+	checkCloudGroup(0, 1)
+
+	runTasks(t, cloud, withNewImage(2))
+	checkCloudGroup(1, 1)
+}
+
+func TestLoadBalancer(t *testing.T) {
+	cloud := hetzner.BuildMockHetznerCloud("fsn1")
+	defer cloud.MockClient.TeardownHTTP()
+
+	buildTasks := func(ports ...int) map[string]fi.Task {
+		loadBalancer := &LoadBalancer{
+			Name:     fi.String("api.minimal.k8s.local"),
+			Location: "fsn1",
+			Type:     "lb11",
+			Target:   "kops.k8s.io/cluster=minimal.k8s.local,kops.k8s.io/instance-role=Master",
+			Labels:   map[string]string{hetzner.TagKubernetesClusterName: "minimal.k8s.local"},
+		}
+		for _, port := range ports {
+			loadBalancer.Services = append(loadBalancer.Services, &LoadBalancerService{
+				ListenerPort:    fi.Int(port),
+				DestinationPort: fi.Int(port),
+			})
+		}
+		return map[string]fi.Task{
+			"loadBalancer": loadBalancer,
+		}
+	}
+
+	allTasks := buildTasks(443)
+	runTasks(t, cloud, allTasks)
+
+	loadBalancer := allTasks["loadBalancer"].(*LoadBalancer)
+	if fi.IntValue(loadBalancer.ID) == 0 {
+		t.Fatalf("ID not set after create")
+	}
+
+	c := &fi.Context{Cloud: cloud}
+	address, err := loadBalancer.FindIPAddress(c)
+	if err != nil {
+		t.Fatalf("error finding load balancer address: %v", err)
+	}
+	if fi.StringValue(address) == "" {
+		t.Errorf("expected load balancer to have a public address")
+	}
+
+	checkNoChanges(t, cloud, buildTasks(443))
+
+	runTasks(t, cloud, buildTasks(443, 3988))
+
+	actual, err := buildTasks()["loadBalancer"].(*LoadBalancer).Find(c)
+	if err != nil {
+		t.Fatalf("error finding load balancer: %v", err)
+	}
+	if len(actual.Services) != 2 {
+		t.Errorf("expected 2 services after update, found %d", len(actual.Services))
+	}
+
+	checkNoChanges(t, cloud, buildTasks(443, 3988))
+}
+
+func runTasks(t *testing.T, cloud hetzner.HetznerCloud, allTasks map[string]fi.Task) {
+	target := hetzner.NewHetznerAPITarget(cloud)
+	context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	defer context.Close()
+
+	if err := context.RunTasks(testRunTasksOptions); err != nil {
+		t.Fatalf("unexpected error during Run: %v", err)
+	}
+}
+
+func checkNoChanges(t *testing.T, cloud fi.Cloud, allTasks map[string]fi.Task) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			KubernetesVersion: "v1.21.0",
+		},
+	}
+	assetBuilder := assets.NewAssetBuilder(cluster, "")
+	target := fi.NewDryRunTarget(assetBuilder, os.Stderr)
+	context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	defer context.Close()
+
+	if err := context.RunTasks(testRunTasksOptions); err != nil {
+		t.Fatalf("unexpected error during Run: %v", err)
+	}
+
+	if target.HasChanges() {
+		var b bytes.Buffer
+		if err := target.PrintReport(allTasks, &b); err != nil {
+			t.Fatalf("error building report: %v", err)
+		}
+		t.Fatalf("Target had changes after executing: %v", b.String())
+	}
+}
+
+func listServers(t *testing.T, cloud hetzner.HetznerCloud, labels map[string]string) []*hcloud.Server {
+	servers, err := (&ServerGroup{Name: fi.String("test"), Labels: labels}).listServers(cloud)
+	if err != nil {
+		t.Fatalf("error listing servers: %v", err)
+	}
+	return servers
+}
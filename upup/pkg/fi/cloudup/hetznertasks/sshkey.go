@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznertasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+)
+
+// SSHKey is an SSH key registered with Hetzner Cloud, injected into the servers we create
+// +kops:fitask
+type SSHKey struct {
+	Name      *string
+	ID        *int
+	Lifecycle *fi.Lifecycle
+
+	PublicKey string
+	Labels    map[string]string
+}
+
+var _ fi.CompareWithID = &SSHKey{}
+
+func (v *SSHKey) CompareWithID() *string {
+	return v.Name
+}
+
+func (v *SSHKey) Find(c *fi.Context) (*SSHKey, error) {
+	cloud := c.Cloud.(hetzner.HetznerCloud)
+
+	fingerprint, err := pki.ComputeOpenSSHKeyFingerprint(v.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error computing fingerprint of SSH key %q: %v", fi.StringValue(v.Name), err)
+	}
+
+	// Hetzner Cloud rejects duplicate keys, so we reuse a key with the same fingerprint
+	sshKey, _, err := cloud.SSHKeyClient().GetByFingerprint(context.TODO(), fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("error finding SSH key %q: %v", fi.StringValue(v.Name), err)
+	}
+	if sshKey == nil {
+		return nil, nil
+	}
+
+	if sshKey.Name != fi.StringValue(v.Name) {
+		klog.V(2).Infof("Reusing SSH key %q with fingerprint %s", sshKey.Name, fingerprint)
+	}
+
+	// Avoid spurious changes
+	v.ID = fi.Int(sshKey.ID)
+
+	return &SSHKey{
+		Name:      v.Name,
+		ID:        fi.Int(sshKey.ID),
+		Lifecycle: v.Lifecycle,
+		PublicKey: v.PublicKey,
+		Labels:    v.Labels,
+	}, nil
+}
+
+func (v *SSHKey) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(v, c)
+}
+
+func (_ *SSHKey) CheckChanges(a, e, changes *SSHKey) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.PublicKey == "" {
+			return fi.RequiredField("PublicKey")
+		}
+	}
+	return nil
+}
+
+func (_ *SSHKey) RenderHetzner(t *hetzner.HetznerAPITarget, a, e, changes *SSHKey) error {
+	if a != nil {
+		return nil
+	}
+
+	sshKey, _, err := t.Cloud.SSHKeyClient().Create(context.TODO(), hcloud.SSHKeyCreateOpts{
+		Name:      fi.StringValue(e.Name),
+		PublicKey: e.PublicKey,
+		Labels:    e.Labels,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating SSH key %q: %v", fi.StringValue(e.Name), err)
+	}
+
+	e.ID = fi.Int(sshKey.ID)
+
+	return nil
+}
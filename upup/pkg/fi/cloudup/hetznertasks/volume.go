@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetznertasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+)
+
+// Volume is a Hetzner Cloud block storage volume.
+// Volumes are created unattached, protokube attaches them to the control-plane servers.
+// +kops:fitask
+type Volume struct {
+	Name      *string
+	ID        *int
+	Lifecycle *fi.Lifecycle
+
+	Size     int
+	Location string
+	Labels   map[string]string
+}
+
+var _ fi.CompareWithID = &Volume{}
+
+func (v *Volume) CompareWithID() *string {
+	return v.Name
+}
+
+func (v *Volume) Find(c *fi.Context) (*Volume, error) {
+	cloud := c.Cloud.(hetzner.HetznerCloud)
+
+	volume, _, err := cloud.VolumeClient().GetByName(context.TODO(), fi.StringValue(v.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error finding volume %q: %v", fi.StringValue(v.Name), err)
+	}
+	if volume == nil {
+		return nil, nil
+	}
+
+	actual := &Volume{
+		Name:      fi.String(volume.Name),
+		ID:        fi.Int(volume.ID),
+		Lifecycle: v.Lifecycle,
+		Size:      volume.Size,
+		Labels:    volume.Labels,
+	}
+	if volume.Location != nil {
+		actual.Location = volume.Location.Name
+	}
+
+	// Avoid spurious changes
+	v.ID = actual.ID
+
+	return actual, nil
+}
+
+func (v *Volume) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(v, c)
+}
+
+func (_ *Volume) CheckChanges(a, e, changes *Volume) error {
+	if a != nil {
+		if changes.Name != nil {
+			return fi.CannotChangeField("Name")
+		}
+		if changes.Size != 0 {
+			return fi.CannotChangeField("Size")
+		}
+		if changes.Location != "" {
+			return fi.CannotChangeField("Location")
+		}
+	} else {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Size == 0 {
+			return fi.RequiredField("Size")
+		}
+		if e.Location == "" {
+			return fi.RequiredField("Location")
+		}
+	}
+	return nil
+}
+
+func (_ *Volume) RenderHetzner(t *hetzner.HetznerAPITarget, a, e, changes *Volume) error {
+	client := t.Cloud.VolumeClient()
+
+	if a == nil {
+		result, _, err := client.Create(context.TODO(), hcloud.VolumeCreateOpts{
+			Name:     fi.StringValue(e.Name),
+			Size:     e.Size,
+			Location: &hcloud.Location{Name: e.Location},
+			Labels:   e.Labels,
+			Format:   fi.String("ext4"),
+		})
+		if err != nil {
+			return fmt.Errorf("error creating volume %q: %v", fi.StringValue(e.Name), err)
+		}
+		if err := t.Cloud.WaitForAction(append([]*hcloud.Action{result.Action}, result.NextActions...)...); err != nil {
+			return fmt.Errorf("error waiting for volume %q: %v", fi.StringValue(e.Name), err)
+		}
+		e.ID = fi.Int(result.Volume.ID)
+
+		return nil
+	}
+
+	if changes.Labels != nil {
+		_, _, err := client.Update(context.TODO(), &hcloud.Volume{ID: fi.IntValue(a.ID)}, hcloud.VolumeUpdateOpts{
+			Labels: e.Labels,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating labels of volume %q: %v", fi.StringValue(e.Name), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// StagedBootstrapScriptPath is where kops uploads the latest bootstrap script of a host
+	StagedBootstrapScriptPath = "/var/lib/kops-metal/bootstrap-staged.sh"
+	// AppliedBootstrapScriptPath is the bootstrap script the host was last configured with
+	AppliedBootstrapScriptPath = "/var/lib/kops-metal/bootstrap.sh"
+
+	// ProviderIDPrefix is the scheme of the provider IDs of the nodes running on pre-provisioned hosts
+	ProviderIDPrefix = "metal://"
+
+	// EtcdVolumesDirectory is where etcd-manager looks for the directories holding the etcd data of the masters
+	EtcdVolumesDirectory = "/mnt/disks"
+)
+
+// MetalCloud exposes the operations kops performs on pre-provisioned hosts
+type MetalCloud interface {
+	fi.Cloud
+
+	// Connect opens a connection to the given host
+	Connect(host *kops.MetalHostSpec) (HostConnection, error)
+}
+
+type metalCloudImplementation struct {
+	// connect opens connections to the hosts; it is replaced by the mock
+	connect func(host *kops.MetalHostSpec) (HostConnection, error)
+}
+
+var _ fi.Cloud = &metalCloudImplementation{}
+
+// metalCloudInstance allows a mock to be injected
+var metalCloudInstance MetalCloud
+
+// NewMetalCloud returns a MetalCloud, which connects to the hosts over SSH
+func NewMetalCloud() MetalCloud {
+	if metalCloudInstance != nil {
+		return metalCloudInstance
+	}
+
+	return &metalCloudImplementation{
+		connect: connectSSH,
+	}
+}
+
+// ProviderID returns the provider ID of the node of the given host
+func ProviderID(instanceGroup string, hostname string) string {
+	return ProviderIDPrefix + instanceGroup + "/" + hostname
+}
+
+// ParseProviderID returns the instance group and the hostname encoded in the provider ID of a node
+func ParseProviderID(providerID string) (string, string, error) {
+	if !strings.HasPrefix(providerID, ProviderIDPrefix) {
+		return "", "", fmt.Errorf("provider ID %q is missing prefix %q", providerID, ProviderIDPrefix)
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(providerID, ProviderIDPrefix), "/")
+	if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+		return "", "", fmt.Errorf("provider ID %q is not of the form %s<instance-group>/<hostname>", providerID, ProviderIDPrefix)
+	}
+
+	return tokens[0], tokens[1], nil
+}
+
+// EtcdVolumePrefix returns the prefix of the names of the etcd data directories of the given etcd cluster
+func EtcdVolumePrefix(clusterName string, etcdClusterName string) string {
+	return clusterName + "--" + etcdClusterName + "--"
+}
+
+// EtcdVolumeDirectory returns the directory holding the data of the given etcd member.
+// etcd-manager mounts its "mnt" subdirectory, as it would mount a cloud volume.
+func EtcdVolumeDirectory(clusterName string, etcdClusterName string, memberName string) string {
+	return path.Join(EtcdVolumesDirectory, EtcdVolumePrefix(clusterName, etcdClusterName)+memberName)
+}
+
+// GossipSeeds returns the addresses of the master hosts, which the other hosts use to join the gossip network
+func GossipSeeds(instanceGroups []*kops.InstanceGroup) []string {
+	var seeds []string
+	for _, ig := range instanceGroups {
+		if !ig.IsMaster() || ig.Spec.Metal == nil {
+			continue
+		}
+		for _, host := range ig.Spec.Metal.Hosts {
+			seeds = append(seeds, host.Address)
+		}
+	}
+	sort.Strings(seeds)
+	return seeds
+}
+
+func (c *metalCloudImplementation) Connect(host *kops.MetalHostSpec) (HostConnection, error) {
+	return c.connect(host)
+}
+
+// ProviderID returns the kops api identifier for pre-provisioned hosts
+func (c *metalCloudImplementation) ProviderID() kops.CloudProviderID {
+	return kops.CloudProviderMetal
+}
+
+// Region returns "", as pre-provisioned hosts have no notion of a region
+func (c *metalCloudImplementation) Region() string {
+	return ""
+}
+
+// DNS is not implemented, metal clusters must use gossip
+func (c *metalCloudImplementation) DNS() (dnsprovider.Interface, error) {
+	return nil, errors.New("DNS is not supported for pre-provisioned hosts, use a gossip-based cluster name ending in .k8s.local")
+}
+
+// FindVPCInfo is not implemented, it's only here to satisfy the fi.Cloud interface
+func (c *metalCloudImplementation) FindVPCInfo(id string) (*fi.VPCInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+// DeleteInstance re-runs nodeup in place on the host, with its staged bootstrap script.
+// kops does not own pre-provisioned hosts, so rolling updates reconfigure them instead of terminating them.
+func (c *metalCloudImplementation) DeleteInstance(i *cloudinstances.CloudInstance) error {
+	host, err := findHost(i)
+	if err != nil {
+		return err
+	}
+
+	conn, err := c.connect(host)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	script, err := conn.ReadFile(StagedBootstrapScriptPath)
+	if err != nil {
+		return fmt.Errorf("error reading the staged bootstrap script of host %q: %v", host.Name, err)
+	}
+	if script == nil {
+		return fmt.Errorf("host %q has no staged bootstrap script, run `kops update cluster` first", host.Name)
+	}
+
+	klog.Infof("re-running nodeup on host %q", host.Name)
+	if err := conn.Run("/bin/bash " + StagedBootstrapScriptPath); err != nil {
+		return fmt.Errorf("error running the bootstrap script on host %q: %v", host.Name, err)
+	}
+	if err := conn.WriteFile(AppliedBootstrapScriptPath, script); err != nil {
+		return fmt.Errorf("error recording the applied bootstrap script of host %q: %v", host.Name, err)
+	}
+
+	// The rolling update deleted the node; restarting the kubelet makes it register again, uncordoned
+	if err := conn.Run("systemctl restart kubelet"); err != nil {
+		return fmt.Errorf("error restarting the kubelet on host %q: %v", host.Name, err)
+	}
+
+	return nil
+}
+
+// DeleteGroup does not touch the hosts, as kops does not own them
+func (c *metalCloudImplementation) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
+	klog.Warningf("the hosts of instance group %q are not managed by kops and must be decommissioned manually", g.HumanName)
+	return nil
+}
+
+// DetachInstance is not supported, there are no spare hosts to surge onto
+func (c *metalCloudImplementation) DetachInstance(i *cloudinstances.CloudInstance) error {
+	return fmt.Errorf("metal cloud provider does not support surging")
+}
+
+// GetCloudGroups returns the hosts of the instance groups.
+// A host needs an update when its staged bootstrap script differs from the one it was last configured with,
+// or when its scripts can't be read.
+func (c *metalCloudImplementation) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	nodeMap := cloudinstances.GetNodeMap(nodes, cluster)
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for _, ig := range instancegroups {
+		if ig.Spec.Metal == nil {
+			if warnUnmatched {
+				klog.Warningf("instance group %q has no metal hosts", ig.Name)
+			}
+			continue
+		}
+
+		group := &cloudinstances.CloudInstanceGroup{
+			HumanName:     ig.Name,
+			InstanceGroup: ig,
+			MinSize:       len(ig.Spec.Metal.Hosts),
+			TargetSize:    len(ig.Spec.Metal.Hosts),
+			MaxSize:       len(ig.Spec.Metal.Hosts),
+		}
+
+		for i := range ig.Spec.Metal.Hosts {
+			host := &ig.Spec.Metal.Hosts[i]
+
+			// An unreachable host must not hide the others; it is rolled, so the update surfaces the problem
+			status, err := c.hostStatus(host)
+			if err != nil {
+				klog.Warningf("unable to determine the status of host %q, assuming it needs an update: %v", host.Name, err)
+				status = cloudinstances.CloudInstanceStatusNeedsUpdate
+			}
+
+			instance, err := group.NewCloudInstance(host.Name, status, nodeMap)
+			if err != nil {
+				return nil, fmt.Errorf("error creating cloud instance group member: %v", err)
+			}
+			instance.PrivateIP = host.Address
+		}
+
+		groups[ig.Name] = group
+	}
+
+	return groups, nil
+}
+
+// hostStatus compares the staged and the applied bootstrap scripts of the host
+func (c *metalCloudImplementation) hostStatus(host *kops.MetalHostSpec) (string, error) {
+	conn, err := c.connect(host)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	staged, err := conn.ReadFile(StagedBootstrapScriptPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading the staged bootstrap script of host %q: %v", host.Name, err)
+	}
+	if staged == nil {
+		klog.Warningf("host %q has not been enrolled yet, run `kops update cluster`", host.Name)
+		return cloudinstances.CloudInstanceStatusUpToDate, nil
+	}
+
+	applied, err := conn.ReadFile(AppliedBootstrapScriptPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading the applied bootstrap script of host %q: %v", host.Name, err)
+	}
+
+	if !bytes.Equal(staged, applied) {
+		return cloudinstances.CloudInstanceStatusNeedsUpdate, nil
+	}
+	return cloudinstances.CloudInstanceStatusUpToDate, nil
+}
+
+// findHost returns the inventory entry of the host backing the cloud instance
+func findHost(i *cloudinstances.CloudInstance) (*kops.MetalHostSpec, error) {
+	ig := i.CloudInstanceGroup.InstanceGroup
+	if ig.Spec.Metal != nil {
+		for j := range ig.Spec.Metal.Hosts {
+			if ig.Spec.Metal.Hosts[j].Name == i.ID {
+				return &ig.Spec.Metal.Hosts[j], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("host %q not found in instance group %q", i.ID, ig.Name)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func TestParseProviderID(t *testing.T) {
+	grid := []struct {
+		providerID    string
+		instanceGroup string
+		hostname      string
+		expectError   bool
+	}{
+		{providerID: ProviderID("nodes", "node-1"), instanceGroup: "nodes", hostname: "node-1"},
+		{providerID: "aws:///us-east-1a/i-0123456789", expectError: true},
+		{providerID: "metal://nodes", expectError: true},
+		{providerID: "metal://nodes/", expectError: true},
+		{providerID: "metal://nodes/node-1/extra", expectError: true},
+	}
+	for _, g := range grid {
+		instanceGroup, hostname, err := ParseProviderID(g.providerID)
+		if g.expectError {
+			if err == nil {
+				t.Errorf("expected error parsing %q", g.providerID)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %v", g.providerID, err)
+			continue
+		}
+		if instanceGroup != g.instanceGroup || hostname != g.hostname {
+			t.Errorf("unexpected result parsing %q: %q, %q", g.providerID, instanceGroup, hostname)
+		}
+	}
+}
+
+func TestGossipSeeds(t *testing.T) {
+	igs := []*kops.InstanceGroup{
+		testInstanceGroup("master", kops.InstanceGroupRoleMaster, "master-1", "10.0.0.2"),
+		testInstanceGroup("master-b", kops.InstanceGroupRoleMaster, "master-2", "10.0.0.1"),
+		testInstanceGroup("nodes", kops.InstanceGroupRoleNode, "node-1", "10.0.0.3"),
+	}
+
+	seeds := GossipSeeds(igs)
+	expected := []string{"10.0.0.1", "10.0.0.2"}
+	if !reflect.DeepEqual(seeds, expected) {
+		t.Errorf("unexpected gossip seeds %v, expected %v", seeds, expected)
+	}
+}
+
+func TestGetCloudGroups(t *testing.T) {
+	cloud := NewMockCloud()
+	enrolled := cloud.AddHost("10.0.0.1")
+	enrolled.Files[StagedBootstrapScriptPath] = []byte("v1")
+	enrolled.Files[AppliedBootstrapScriptPath] = []byte("v1")
+	staged := cloud.AddHost("10.0.0.2")
+	staged.Files[StagedBootstrapScriptPath] = []byte("v2")
+	staged.Files[AppliedBootstrapScriptPath] = []byte("v1")
+
+	ig := testInstanceGroup("nodes", kops.InstanceGroupRoleNode, "node-1", "10.0.0.1")
+	ig.Spec.Metal.Hosts = append(ig.Spec.Metal.Hosts,
+		kops.MetalHostSpec{Name: "node-2", Address: "10.0.0.2"},
+		kops.MetalHostSpec{Name: "node-3", Address: "10.0.0.3"},
+	)
+
+	cluster := &kops.Cluster{}
+	cluster.Name = "metal.k8s.local"
+
+	groups, err := cloud.GetCloudGroups(cluster, []*kops.InstanceGroup{ig}, false, nil)
+	if err != nil {
+		t.Fatalf("error getting cloud groups: %v", err)
+	}
+
+	group := groups["nodes"]
+	if group == nil {
+		t.Fatalf("instance group %q not found in %v", "nodes", groups)
+	}
+	if group.TargetSize != 3 {
+		t.Errorf("unexpected target size %d", group.TargetSize)
+	}
+	if len(group.Ready) != 1 || group.Ready[0].ID != "node-1" {
+		t.Errorf("expected node-1 to be up to date, found %v", group.Ready)
+	}
+	// node-3 is unreachable
+	if len(group.NeedUpdate) != 2 || group.NeedUpdate[0].ID != "node-2" || group.NeedUpdate[1].ID != "node-3" {
+		t.Errorf("expected node-2 and node-3 to need an update, found %v", group.NeedUpdate)
+	}
+}
+
+func TestDeleteInstance(t *testing.T) {
+	cloud := NewMockCloud()
+	host := cloud.AddHost("10.0.0.1")
+	host.Files[StagedBootstrapScriptPath] = []byte("v2")
+	host.Files[AppliedBootstrapScriptPath] = []byte("v1")
+
+	ig := testInstanceGroup("nodes", kops.InstanceGroupRoleNode, "node-1", "10.0.0.1")
+	instance := &cloudinstances.CloudInstance{
+		ID: "node-1",
+		CloudInstanceGroup: &cloudinstances.CloudInstanceGroup{
+			HumanName:     ig.Name,
+			InstanceGroup: ig,
+		},
+	}
+
+	if err := cloud.DeleteInstance(instance); err != nil {
+		t.Fatalf("error deleting instance: %v", err)
+	}
+
+	if got := string(host.Files[AppliedBootstrapScriptPath]); got != "v2" {
+		t.Errorf("expected the staged script to be applied, found %q", got)
+	}
+	expected := []string{"/bin/bash " + StagedBootstrapScriptPath, "systemctl restart kubelet"}
+	if !reflect.DeepEqual(host.Commands, expected) {
+		t.Errorf("unexpected commands %v, expected %v", host.Commands, expected)
+	}
+}
+
+func testInstanceGroup(name string, role kops.InstanceGroupRole, hostname string, address string) *kops.InstanceGroup {
+	return &kops.InstanceGroup{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec: kops.InstanceGroupSpec{
+			Role: role,
+			Metal: &kops.MetalSpec{
+				Hosts: []kops.MetalHostSpec{
+					{Name: hostname, Address: address},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"fmt"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// MockCloud is a MetalCloud backed by in-memory hosts, for testing
+type MockCloud struct {
+	metalCloudImplementation
+
+	// Hosts are the mock hosts, by address
+	Hosts map[string]*MockHost
+}
+
+var _ MetalCloud = &MockCloud{}
+
+// MockHost records the files and the commands of a mock host
+type MockHost struct {
+	Files    map[string][]byte
+	Commands []string
+}
+
+// NewMockCloud returns a MockCloud without any hosts
+func NewMockCloud() *MockCloud {
+	c := &MockCloud{
+		Hosts: make(map[string]*MockHost),
+	}
+	c.connect = c.connectMock
+	return c
+}
+
+// InstallMockMetalCloud builds a mock cloud and makes NewMetalCloud return it
+func InstallMockMetalCloud() *MockCloud {
+	c := NewMockCloud()
+	metalCloudInstance = c
+	return c
+}
+
+// AddHost adds an empty mock host with the given address
+func (c *MockCloud) AddHost(address string) *MockHost {
+	host := &MockHost{
+		Files: make(map[string][]byte),
+	}
+	c.Hosts[address] = host
+	return host
+}
+
+func (c *MockCloud) connectMock(host *kops.MetalHostSpec) (HostConnection, error) {
+	h := c.Hosts[host.Address]
+	if h == nil {
+		return nil, fmt.Errorf("error connecting to host %q at %s: connection refused", host.Name, host.Address)
+	}
+	return h, nil
+}
+
+func (h *MockHost) ReadFile(p string) ([]byte, error) {
+	return h.Files[p], nil
+}
+
+func (h *MockHost) WriteFile(p string, data []byte) error {
+	h.Files[p] = data
+	return nil
+}
+
+func (h *MockHost) Run(cmd string) error {
+	h.Commands = append(h.Commands, cmd)
+	return nil
+}
+
+func (h *MockHost) Close() error {
+	return nil
+}
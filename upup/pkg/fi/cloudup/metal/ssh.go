@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	defaultSSHPort = 22
+	defaultSSHUser = "root"
+)
+
+// HostConnection is an open connection to a pre-provisioned host
+type HostConnection interface {
+	// ReadFile returns the contents of the file at the given path, or nil if the file does not exist
+	ReadFile(p string) ([]byte, error)
+	// WriteFile writes the file at the given path, creating its parent directories
+	WriteFile(p string, data []byte) error
+	// Run runs the command on the host as root
+	Run(cmd string) error
+	// Close closes the connection
+	Close() error
+}
+
+// sshHostConnection is a HostConnection over SSH, which reads and writes files with the vfs sftp plumbing
+type sshHostConnection struct {
+	address string
+	client  *ssh.Client
+	sudo    bool
+}
+
+var _ HostConnection = &sshHostConnection{}
+
+// connectSSH logs in to the host with its private key
+func connectSSH(host *kops.MetalHostSpec) (HostConnection, error) {
+	// We only talk to hosts presenting the key of their inventory entry, as we upload secrets to them
+	if host.SSHHostKey == "" {
+		return nil, fmt.Errorf("host %q has no sshHostKey to verify its SSH server against", host.Name)
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(host.SSHHostKey))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the sshHostKey of host %q: %v", host.Name, err)
+	}
+
+	user := host.SSHUser
+	if user == "" {
+		user = defaultSSHUser
+	}
+
+	keyFile := host.SSHPrivateKeyFile
+	if keyFile == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("error finding home directory for the default SSH key: %v", err)
+		}
+		keyFile = filepath.Join(homeDir, ".ssh", "id_rsa")
+	} else if strings.HasPrefix(keyFile, "~/") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("error finding home directory for SSH key %q: %v", keyFile, err)
+		}
+		keyFile = filepath.Join(homeDir, strings.TrimPrefix(keyFile, "~/"))
+	}
+
+	b, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading SSH key file %q: %v", keyFile, err)
+	}
+	signer, err := ssh.ParsePrivateKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing SSH key file %q: %v", keyFile, err)
+	}
+
+	port := defaultSSHPort
+	if host.SSHPort != nil {
+		port = int(*host.SSHPort)
+	}
+
+	config := &ssh.ClientConfig{
+		User:              user,
+		Auth:              []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback:   ssh.FixedHostKey(hostKey),
+		HostKeyAlgorithms: []string{hostKey.Type()},
+		Timeout:           30 * time.Second,
+	}
+
+	address := net.JoinHostPort(host.Address, strconv.Itoa(port))
+	klog.V(2).Infof("connecting to host %q at %s as %s", host.Name, address, user)
+	client, err := ssh.Dial("tcp", address, config)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to host %q at %s: %v", host.Name, address, err)
+	}
+
+	return &sshHostConnection{
+		address: host.Address,
+		client:  client,
+		sudo:    user != "root",
+	}, nil
+}
+
+func (c *sshHostConnection) ReadFile(p string) ([]byte, error) {
+	if err := c.Run("test -f " + shellQuote(p)); err != nil {
+		if exitErr, ok := err.(*ssh.ExitError); ok && exitErr.ExitStatus() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return vfs.NewSSHPath(c.client, c.address, p, c.sudo).ReadFile()
+}
+
+func (c *sshHostConnection) WriteFile(p string, data []byte) error {
+	acl := &vfs.SSHAcl{Mode: 0700}
+	return vfs.NewSSHPath(c.client, c.address, p, c.sudo).WriteFile(bytes.NewReader(data), acl)
+}
+
+func (c *sshHostConnection) Run(cmd string) error {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("error creating SSH session on %s: %v", c.address, err)
+	}
+	defer session.Close()
+
+	if c.sudo {
+		cmd = "sudo " + cmd
+	}
+
+	var output bytes.Buffer
+	session.Stdout = &output
+	session.Stderr = &output
+
+	klog.V(2).Infof("running %q on %s", cmd, c.address)
+	if err := session.Run(cmd); err != nil {
+		klog.Infof("output of %q on %s:\n%s", cmd, c.address, output.String())
+		return err
+	}
+	klog.V(4).Infof("output of %q on %s:\n%s", cmd, c.address, output.String())
+
+	return nil
+}
+
+func (c *sshHostConnection) Close() error {
+	return c.client.Close()
+}
+
+// shellQuote quotes a string for the shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
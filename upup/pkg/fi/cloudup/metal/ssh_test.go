@@ -0,0 +1,45 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestConnectSSHRequiresHostKey(t *testing.T) {
+	for _, hostKey := range []string{"", "not a key"} {
+		host := &kops.MetalHostSpec{Name: "node-1", Address: "127.0.0.1", SSHHostKey: hostKey}
+		if _, err := connectSSH(host); err == nil {
+			t.Errorf("expected error connecting with host key %q", hostKey)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	grid := map[string]string{
+		"/var/lib/kops-metal/bootstrap.sh": `'/var/lib/kops-metal/bootstrap.sh'`,
+		"/tmp/a b; rm -rf /":               `'/tmp/a b; rm -rf /'`,
+		"/tmp/it's":                        `'/tmp/it'\''s'`,
+	}
+	for s, expected := range grid {
+		if actual := shellQuote(s); actual != expected {
+			t.Errorf("unexpected quoting of %q: %s, expected %s", s, actual, expected)
+		}
+	}
+}
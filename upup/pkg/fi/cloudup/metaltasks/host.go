@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metaltasks
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+)
+
+// Host is a pre-provisioned machine, which kops enrolls by pushing its bootstrap script over SSH.
+// The first enrollment runs the script; later changes are only staged, and applied by a rolling update.
+// +kops:fitask
+type Host struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	Address           *string
+	SSHPort           *int32
+	SSHUser           *string
+	SSHPrivateKeyFile *string
+	SSHHostKey        *string
+
+	UserData fi.Resource
+}
+
+var _ fi.CompareWithID = &Host{}
+
+func (h *Host) CompareWithID() *string {
+	return h.Name
+}
+
+func (h *Host) Find(c *fi.Context) (*Host, error) {
+	cloud := c.Cloud.(metal.MetalCloud)
+
+	conn, err := cloud.Connect(h.hostSpec())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	staged, err := conn.ReadFile(metal.StagedBootstrapScriptPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the staged bootstrap script of host %q: %v", fi.StringValue(h.Name), err)
+	}
+	if staged == nil {
+		return nil, nil
+	}
+
+	return &Host{
+		Name:              h.Name,
+		Lifecycle:         h.Lifecycle,
+		Address:           h.Address,
+		SSHPort:           h.SSHPort,
+		SSHUser:           h.SSHUser,
+		SSHPrivateKeyFile: h.SSHPrivateKeyFile,
+		SSHHostKey:        h.SSHHostKey,
+		UserData:          fi.NewBytesResource(staged),
+	}, nil
+}
+
+func (h *Host) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(h, c)
+}
+
+func (_ *Host) CheckChanges(a, e, changes *Host) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if fi.StringValue(e.Address) == "" {
+			return fi.RequiredField("Address")
+		}
+	} else {
+		if changes.Address != nil {
+			return fi.CannotChangeField("Address")
+		}
+	}
+	return nil
+}
+
+func (_ *Host) RenderMetal(t *metal.MetalAPITarget, a, e, changes *Host) error {
+	script, err := fi.ResourceAsBytes(e.UserData)
+	if err != nil {
+		return fmt.Errorf("error rendering the bootstrap script of host %q: %v", fi.StringValue(e.Name), err)
+	}
+
+	conn, err := t.Cloud.Connect(e.hostSpec())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.WriteFile(metal.StagedBootstrapScriptPath, script); err != nil {
+		return fmt.Errorf("error uploading the bootstrap script of host %q: %v", fi.StringValue(e.Name), err)
+	}
+
+	if a != nil {
+		klog.Infof("staged a new bootstrap script on host %q; run `kops rolling-update cluster` to apply it", fi.StringValue(e.Name))
+		return nil
+	}
+
+	klog.Infof("enrolling host %q", fi.StringValue(e.Name))
+	if err := conn.Run("/bin/bash " + metal.StagedBootstrapScriptPath); err != nil {
+		return fmt.Errorf("error running the bootstrap script on host %q: %v", fi.StringValue(e.Name), err)
+	}
+	if err := conn.WriteFile(metal.AppliedBootstrapScriptPath, script); err != nil {
+		return fmt.Errorf("error recording the applied bootstrap script of host %q: %v", fi.StringValue(e.Name), err)
+	}
+
+	return nil
+}
+
+// hostSpec returns the connection details of the host
+func (h *Host) hostSpec() *kops.MetalHostSpec {
+	return &kops.MetalHostSpec{
+		Name:              fi.StringValue(h.Name),
+		Address:           fi.StringValue(h.Address),
+		SSHPort:           h.SSHPort,
+		SSHUser:           fi.StringValue(h.SSHUser),
+		SSHPrivateKeyFile: fi.StringValue(h.SSHPrivateKeyFile),
+		SSHHostKey:        fi.StringValue(h.SSHHostKey),
+	}
+}
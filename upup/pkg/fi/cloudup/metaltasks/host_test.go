@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metaltasks
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+)
+
+var testRunTasksOptions = fi.RunTasksOptions{
+	MaxTaskDuration:         2 * time.Second,
+	WaitAfterAllTasksFailed: 500 * time.Millisecond,
+}
+
+func TestHost(t *testing.T) {
+	cloud := metal.NewMockCloud()
+	mockHost := cloud.AddHost("192.168.1.10")
+
+	buildTasks := func(script string) map[string]fi.Task {
+		return map[string]fi.Task{
+			"host": &Host{
+				Name:     fi.String("node-1"),
+				Address:  fi.String("192.168.1.10"),
+				UserData: fi.NewStringResource(script),
+			},
+		}
+	}
+
+	// The first run enrolls the host
+	runTasks(t, cloud, buildTasks("#!/bin/bash\necho v1"))
+
+	if got := string(mockHost.Files[metal.StagedBootstrapScriptPath]); got != "#!/bin/bash\necho v1" {
+		t.Errorf("unexpected staged script %q", got)
+	}
+	if got := string(mockHost.Files[metal.AppliedBootstrapScriptPath]); got != "#!/bin/bash\necho v1" {
+		t.Errorf("unexpected applied script %q", got)
+	}
+	if len(mockHost.Commands) != 1 || mockHost.Commands[0] != "/bin/bash "+metal.StagedBootstrapScriptPath {
+		t.Errorf("expected the bootstrap script to be run once, ran %v", mockHost.Commands)
+	}
+
+	checkNoChanges(t, cloud, buildTasks("#!/bin/bash\necho v1"))
+
+	// Later changes are only staged
+	runTasks(t, cloud, buildTasks("#!/bin/bash\necho v2"))
+
+	if got := string(mockHost.Files[metal.StagedBootstrapScriptPath]); got != "#!/bin/bash\necho v2" {
+		t.Errorf("unexpected staged script %q", got)
+	}
+	if got := string(mockHost.Files[metal.AppliedBootstrapScriptPath]); got != "#!/bin/bash\necho v1" {
+		t.Errorf("expected the applied script to be unchanged, found %q", got)
+	}
+	if len(mockHost.Commands) != 1 {
+		t.Errorf("expected no more commands to be run, ran %v", mockHost.Commands)
+	}
+
+	checkNoChanges(t, cloud, buildTasks("#!/bin/bash\necho v2"))
+}
+
+func runTasks(t *testing.T, cloud metal.MetalCloud, allTasks map[string]fi.Task) {
+	target := metal.NewMetalAPITarget(cloud)
+	context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	defer context.Close()
+
+	if err := context.RunTasks(testRunTasksOptions); err != nil {
+		t.Fatalf("unexpected error during Run: %v", err)
+	}
+}
+
+func checkNoChanges(t *testing.T, cloud fi.Cloud, allTasks map[string]fi.Task) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			KubernetesVersion: "v1.21.0",
+		},
+	}
+	assetBuilder := assets.NewAssetBuilder(cluster, "")
+	target := fi.NewDryRunTarget(assetBuilder, os.Stderr)
+	context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	defer context.Close()
+
+	if err := context.RunTasks(testRunTasksOptions); err != nil {
+		t.Fatalf("unexpected error during Run: %v", err)
+	}
+
+	if target.HasChanges() {
+		var b bytes.Buffer
+		if err := target.PrintReport(allTasks, &b); err != nil {
+			t.Fatalf("error building report: %v", err)
+		}
+		t.Fatalf("Target had changes after executing: %v", b.String())
+	}
+}
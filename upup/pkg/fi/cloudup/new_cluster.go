@@ -39,6 +39,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 )
 
@@ -504,6 +505,22 @@ func setupZones(opt *NewClusterOptions, cluster *api.Cluster, allZones sets.Stri
 			zoneToSubnetMap[zoneName] = subnet
 		}
 
+	case api.CloudProviderHetzner:
+		// All the servers of a cluster share a single private network, which cannot span network zones
+		networkZone, err := hetzner.NetworkZone(allZones.List()[0])
+		if err != nil {
+			return nil, err
+		}
+		for _, zoneName := range allZones.List() {
+			zoneNetworkZone, err := hetzner.NetworkZone(zoneName)
+			if err != nil {
+				return nil, err
+			}
+			if zoneNetworkZone != networkZone {
+				return nil, fmt.Errorf("hetzner zones must belong to the same network zone, %q is in %q and %q is in %q", allZones.List()[0], networkZone, zoneName, zoneNetworkZone)
+			}
+		}
+
 	case api.CloudProviderAWS:
 		if len(opt.Zones) > 0 && len(opt.SubnetIDs) > 0 {
 			zoneToSubnetProviderID, err = getAWSZoneToSubnetProviderID(cluster.Spec.NetworkID, opt.Zones[0][:len(opt.Zones[0])-1], opt.SubnetIDs)
@@ -964,11 +981,12 @@ func setupAPI(opt *NewClusterOptions, cluster *api.Cluster) error {
 	if api.CloudProviderID(cluster.Spec.CloudProvider) == api.CloudProviderOpenstack {
 		initializeOpenstackAPI(opt, cluster)
 	} else if api.CloudProviderID(cluster.Spec.CloudProvider) == api.CloudProviderAzure {
-		// Do nothing to disable the use of loadbalancer for the k8s API server.
-		// TODO(kenji): Remove this condition once we support the loadbalancer
-		// in pkg/model/azuremodel/api_loadbalancer.go.
-		cluster.Spec.API = nil
-		return nil
+		// The loadbalancer for the k8s API server is only created when explicitly requested.
+		if opt.APILoadBalancerType == "" {
+			cluster.Spec.API = nil
+			return nil
+		}
+		cluster.Spec.API.LoadBalancer = &api.LoadBalancerAccessSpec{}
 	} else if opt.APILoadBalancerType != "" || opt.APISSLCertificate != "" {
 		cluster.Spec.API.LoadBalancer = &api.LoadBalancerAccessSpec{}
 	} else {
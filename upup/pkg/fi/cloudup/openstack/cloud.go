@@ -282,6 +282,9 @@ type OpenstackCloud interface {
 
 	ListPools(v2pools.ListOpts) ([]v2pools.Pool, error)
 
+	// CreateMonitor will create a HealthMonitor for a pool
+	CreateMonitor(opts monitors.CreateOpts) (*monitors.Monitor, error)
+
 	// ListMonitors will list HealthMonitors matching the provided options
 	ListMonitors(monitors.ListOpts) ([]monitors.Monitor, error)
 
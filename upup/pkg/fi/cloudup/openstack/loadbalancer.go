@@ -28,6 +28,31 @@ import (
 	"k8s.io/kops/util/pkg/vfs"
 )
 
+func (c *openstackCloud) CreateMonitor(opts monitors.CreateOpts) (monitor *monitors.Monitor, err error) {
+	return createMonitor(c, opts)
+}
+
+func createMonitor(c OpenstackCloud, opts monitors.CreateOpts) (monitor *monitors.Monitor, err error) {
+	if c.LoadBalancerClient() == nil {
+		return nil, fmt.Errorf("loadbalancer support not available in this deployment")
+	}
+
+	done, err := vfs.RetryWithBackoff(writeBackoff, func() (bool, error) {
+		monitor, err = monitors.Create(c.LoadBalancerClient(), opts).Extract()
+		if err != nil {
+			return false, fmt.Errorf("failed to create monitor: %v", err)
+		}
+		return true, nil
+	})
+	if !done {
+		if err == nil {
+			err = wait.ErrWaitTimeout
+		}
+		return monitor, err
+	}
+	return monitor, nil
+}
+
 func (c *openstackCloud) ListMonitors(opts monitors.ListOpts) (monitorList []monitors.Monitor, err error) {
 	return listMonitors(c, opts)
 }
@@ -187,6 +187,10 @@ func (c *MockCloud) CreateListener(opts listeners.CreateOpts) (listener *listene
 	return createListener(c, opts)
 }
 
+func (c *MockCloud) CreateMonitor(opts monitors.CreateOpts) (monitor *monitors.Monitor, err error) {
+	return createMonitor(c, opts)
+}
+
 func (c *MockCloud) CreateNetwork(opt networks.CreateOptsBuilder) (*networks.Network, error) {
 	return createNetwork(c, opt)
 }
@@ -39,6 +39,8 @@ type FloatingIP struct {
 	IP           *string
 	Lifecycle    *fi.Lifecycle
 	ForAPIServer bool
+	// Shared is set if this is an existing floating IP (one we don't create or own), which is found by its IP
+	Shared *bool
 }
 
 var _ fi.HasAddress = &FloatingIP{}
@@ -78,6 +80,9 @@ func (e *FloatingIP) IsForAPIServer() bool {
 }
 
 func (e *FloatingIP) FindIPAddress(context *fi.Context) (*string, error) {
+	if fi.BoolValue(e.Shared) {
+		return e.IP, nil
+	}
 	if e.ID == nil {
 		if e.LB != nil && e.LB.ID == nil {
 			return nil, nil
@@ -133,6 +138,9 @@ func (e *FloatingIP) Find(c *fi.Context) (*FloatingIP, error) {
 		return nil, nil
 	}
 	cloud := c.Cloud.(openstack.OpenstackCloud)
+	if fi.BoolValue(e.Shared) {
+		return findSharedFip(cloud, e)
+	}
 	if e.LB != nil && e.LB.PortID != nil {
 		fip, err := findFipByPortID(cloud, fi.StringValue(e.LB.PortID))
 
@@ -210,6 +218,33 @@ func (e *FloatingIP) Find(c *fi.Context) (*FloatingIP, error) {
 	return nil, nil
 }
 
+// findSharedFip finds an existing floating IP by its address. Its description belongs to its owner, so it isn't compared.
+func findSharedFip(cloud openstack.OpenstackCloud, e *FloatingIP) (*FloatingIP, error) {
+	fips, err := cloud.ListL3FloatingIPs(l3floatingip.ListOpts{
+		FloatingIP: fi.StringValue(e.IP),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list layer 3 floating ips for address %s: %v", fi.StringValue(e.IP), err)
+	}
+	if len(fips) != 1 {
+		return nil, fmt.Errorf("found %d floating ips with address %s, expected exactly one", len(fips), fi.StringValue(e.IP))
+	}
+
+	actual := &FloatingIP{
+		Name:         e.Name,
+		ID:           fi.String(fips[0].ID),
+		IP:           fi.String(fips[0].FloatingIP),
+		Lifecycle:    e.Lifecycle,
+		ForAPIServer: e.ForAPIServer,
+		Shared:       e.Shared,
+	}
+	if e.LB != nil && e.LB.PortID != nil && fips[0].PortID == fi.StringValue(e.LB.PortID) {
+		actual.LB = e.LB
+	}
+	e.ID = actual.ID
+	return actual, nil
+}
+
 func findFipByPortID(cloud openstack.OpenstackCloud, id string) (fip *l3floatingip.FloatingIP, err error) {
 	fips, err := cloud.ListL3FloatingIPs(l3floatingip.ListOpts{
 		PortID: id,
@@ -256,12 +291,31 @@ func (_ *FloatingIP) ShouldCreate(a, e, changes *FloatingIP) (bool, error) {
 	if changes.Name != nil {
 		return true, nil
 	}
+	if fi.BoolValue(e.Shared) && changes.LB != nil {
+		return true, nil
+	}
 	return false, nil
 }
 
 func (f *FloatingIP) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes *FloatingIP) error {
 	cloud := t.Cloud.(openstack.OpenstackCloud)
 
+	if fi.BoolValue(e.Shared) {
+		if a == nil {
+			return fmt.Errorf("floating ip %s not found", fi.StringValue(e.IP))
+		}
+		if changes.LB != nil {
+			klog.V(2).Infof("Associating floating ip %s with load balancer %s", fi.StringValue(e.IP), fi.StringValue(e.LB.Name))
+			_, err := l3floatingip.Update(cloud.NetworkingClient(), fi.StringValue(a.ID), l3floatingip.UpdateOpts{
+				PortID: e.LB.PortID,
+			}).Extract()
+			if err != nil {
+				return fmt.Errorf("failed to associate floating ip %s: %v", fi.StringValue(e.IP), err)
+			}
+		}
+		return nil
+	}
+
 	if a == nil {
 		external, err := cloud.GetExternalNetwork()
 		if err != nil {
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstacktasks
+
+import (
+	"testing"
+
+	l3floatingip "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestSharedFloatingIP(t *testing.T) {
+	cloud := buildMockLBCloud(t)
+
+	fip, err := cloud.CreateL3FloatingIP(l3floatingip.CreateOpts{
+		FloatingNetworkID: "external-network-id",
+		FloatingIP:        "192.0.2.10",
+		Description:       "reserved for the API",
+	})
+	if err != nil {
+		t.Fatalf("error creating floating ip: %v", err)
+	}
+
+	buildTasks := func() map[string]fi.Task {
+		return map[string]fi.Task{
+			"fip": &FloatingIP{
+				Name:   fi.String("fip-api"),
+				IP:     fi.String("192.0.2.10"),
+				Shared: fi.Bool(true),
+				LB: &LB{
+					ID:     fi.String("lb-id"),
+					Name:   fi.String("api"),
+					PortID: fi.String("lb-port-id"),
+				},
+			},
+		}
+	}
+
+	allTasks := buildTasks()
+	runTasks(t, cloud, allTasks)
+
+	if id := fi.StringValue(allTasks["fip"].(*FloatingIP).ID); id != fip.ID {
+		t.Errorf("expected the existing floating ip %s to be used, got %s", fip.ID, id)
+	}
+	fips, err := cloud.ListL3FloatingIPs(l3floatingip.ListOpts{})
+	if err != nil {
+		t.Fatalf("error listing floating ips: %v", err)
+	}
+	if len(fips) != 1 {
+		t.Fatalf("expected no floating ip to be allocated, found %v", fips)
+	}
+	if fips[0].PortID != "lb-port-id" {
+		t.Errorf("expected floating ip to be associated with the load balancer port, got %q", fips[0].PortID)
+	}
+	if fips[0].Description != "reserved for the API" {
+		t.Errorf("expected description of shared floating ip to be kept, got %q", fips[0].Description)
+	}
+
+	checkNoChanges(t, cloud, buildTasks())
+}
+
+func TestSharedFloatingIPNotFound(t *testing.T) {
+	cloud := buildMockLBCloud(t)
+
+	e := &FloatingIP{
+		Name:   fi.String("fip-api"),
+		IP:     fi.String("192.0.2.10"),
+		Shared: fi.Bool(true),
+	}
+	if _, err := e.Find(&fi.Context{Cloud: cloud}); err == nil {
+		t.Errorf("expected an error when the shared floating ip does not exist")
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstacktasks
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+)
+
+// +kops:fitask
+type LBHealthMonitor struct {
+	ID         *string
+	Name       *string
+	Lifecycle  *fi.Lifecycle
+	Pool       *LBPool
+	Delay      *int
+	Timeout    *int
+	MaxRetries *int
+	// Type is the type of health check, TCP when unset
+	Type *string
+}
+
+// GetDependencies returns the dependencies of the LBHealthMonitor task
+func (e *LBHealthMonitor) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	var deps []fi.Task
+	for _, task := range tasks {
+		if _, ok := task.(*LBPool); ok {
+			deps = append(deps, task)
+		}
+		// The load balancer is immutable while a listener is created, so wait for it
+		if _, ok := task.(*LBListener); ok {
+			deps = append(deps, task)
+		}
+	}
+	return deps
+}
+
+var _ fi.CompareWithID = &LBHealthMonitor{}
+
+func (s *LBHealthMonitor) CompareWithID() *string {
+	return s.ID
+}
+
+func (s *LBHealthMonitor) Find(context *fi.Context) (*LBHealthMonitor, error) {
+	if s.Name == nil {
+		return nil, nil
+	}
+
+	cloud := context.Cloud.(openstack.OpenstackCloud)
+	monitorList, err := cloud.ListMonitors(monitors.ListOpts{
+		ID:   fi.StringValue(s.ID),
+		Name: fi.StringValue(s.Name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list health monitors for name %s: %v", fi.StringValue(s.Name), err)
+	}
+	if len(monitorList) == 0 {
+		return nil, nil
+	}
+	if len(monitorList) > 1 {
+		return nil, fmt.Errorf("multiple health monitors found with name %s", fi.StringValue(s.Name))
+	}
+
+	monitor := monitorList[0]
+	actual := &LBHealthMonitor{
+		ID:         fi.String(monitor.ID),
+		Name:       fi.String(monitor.Name),
+		Lifecycle:  s.Lifecycle,
+		Pool:       s.Pool,
+		Delay:      fi.Int(monitor.Delay),
+		Timeout:    fi.Int(monitor.Timeout),
+		MaxRetries: fi.Int(monitor.MaxRetries),
+		Type:       fi.String(monitor.Type),
+	}
+	s.ID = actual.ID
+	return actual, nil
+}
+
+func (s *LBHealthMonitor) Run(context *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(s, context)
+}
+
+func (_ *LBHealthMonitor) CheckChanges(a, e, changes *LBHealthMonitor) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Pool == nil {
+			return fi.RequiredField("Pool")
+		}
+	} else {
+		if changes.ID != nil {
+			return fi.CannotChangeField("ID")
+		}
+		if changes.Name != nil {
+			return fi.CannotChangeField("Name")
+		}
+		if changes.Type != nil {
+			return fi.CannotChangeField("Type")
+		}
+	}
+	return nil
+}
+
+func (_ *LBHealthMonitor) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes *LBHealthMonitor) error {
+	if a == nil {
+		// wait that lb is in ACTIVE state
+		provisioningStatus, err := waitLoadbalancerActiveProvisioningStatus(t.Cloud.LoadBalancerClient(), fi.StringValue(e.Pool.Loadbalancer.ID))
+		if err != nil {
+			return fmt.Errorf("failed to loadbalancer ACTIVE provisioning status %v: %v", provisioningStatus, err)
+		}
+
+		klog.V(2).Infof("Creating health monitor with Name: %q", fi.StringValue(e.Name))
+		opts := monitors.CreateOpts{
+			Name:       fi.StringValue(e.Name),
+			PoolID:     fi.StringValue(e.Pool.ID),
+			Type:       monitors.TypeTCP,
+			Delay:      fi.IntValue(e.Delay),
+			Timeout:    fi.IntValue(e.Timeout),
+			MaxRetries: fi.IntValue(e.MaxRetries),
+		}
+		if e.Type != nil {
+			opts.Type = fi.StringValue(e.Type)
+		}
+		monitor, err := t.Cloud.CreateMonitor(opts)
+		if err != nil {
+			return fmt.Errorf("error creating health monitor: %v", err)
+		}
+		e.ID = fi.String(monitor.ID)
+		return nil
+	}
+
+	if changes.Delay != nil || changes.Timeout != nil || changes.MaxRetries != nil {
+		opts := monitors.UpdateOpts{
+			Delay:      fi.IntValue(e.Delay),
+			Timeout:    fi.IntValue(e.Timeout),
+			MaxRetries: fi.IntValue(e.MaxRetries),
+		}
+		_, err := monitors.Update(t.Cloud.LoadBalancerClient(), fi.StringValue(a.ID), opts).Extract()
+		if err != nil {
+			return fmt.Errorf("error updating health monitor: %v", err)
+		}
+		return nil
+	}
+
+	klog.V(2).Infof("Openstack task LBHealthMonitor::RenderOpenstack did nothing")
+	return nil
+}
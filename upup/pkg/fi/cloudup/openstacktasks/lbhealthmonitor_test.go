@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstacktasks
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
+	v2pools "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"k8s.io/kops/cloudmock/openstack/mockloadbalancer"
+	"k8s.io/kops/cloudmock/openstack/mocknetworking"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+)
+
+var testRunTasksOptions = fi.RunTasksOptions{
+	MaxTaskDuration:         2 * time.Second,
+	WaitAfterAllTasksFailed: 500 * time.Millisecond,
+}
+
+func buildMockLBCloud(t *testing.T) *openstack.MockCloud {
+	cloud := openstack.BuildMockOpenstackCloud("us-test1")
+	cloud.MockLBClient = mockloadbalancer.CreateClient()
+	cloud.MockNeutronClient = mocknetworking.CreateClient()
+	t.Cleanup(func() {
+		cloud.MockLBClient.TeardownHTTP()
+		cloud.MockNeutronClient.TeardownHTTP()
+	})
+	return cloud
+}
+
+// createLBPool creates a load balancer with a pool, and returns the pool task referring to them
+func createLBPool(t *testing.T, cloud openstack.OpenstackCloud, name string) *LBPool {
+	network, err := cloud.CreateNetwork(networks.CreateOpts{Name: name})
+	if err != nil {
+		t.Fatalf("error creating network: %v", err)
+	}
+	subnet, err := cloud.CreateSubnet(subnets.CreateOpts{Name: name, NetworkID: network.ID, CIDR: "192.168.0.0/24", EnableDHCP: fi.Bool(true)})
+	if err != nil {
+		t.Fatalf("error creating subnet: %v", err)
+	}
+	lb, err := cloud.CreateLB(loadbalancers.CreateOpts{Name: name, VipSubnetID: subnet.ID})
+	if err != nil {
+		t.Fatalf("error creating load balancer: %v", err)
+	}
+	pool, err := cloud.CreatePool(v2pools.CreateOpts{
+		Name:           name + "-https",
+		LBMethod:       v2pools.LBMethodRoundRobin,
+		Protocol:       v2pools.ProtocolTCP,
+		LoadbalancerID: lb.ID,
+	})
+	if err != nil {
+		t.Fatalf("error creating pool: %v", err)
+	}
+	return &LBPool{
+		ID:           fi.String(pool.ID),
+		Name:         fi.String(pool.Name),
+		Loadbalancer: &LB{ID: fi.String(lb.ID), Name: fi.String(lb.Name)},
+	}
+}
+
+func runTasks(t *testing.T, cloud openstack.OpenstackCloud, allTasks map[string]fi.Task) {
+	target := openstack.NewOpenstackAPITarget(cloud)
+	context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	defer context.Close()
+
+	if err := context.RunTasks(testRunTasksOptions); err != nil {
+		t.Fatalf("unexpected error during Run: %v", err)
+	}
+}
+
+func checkNoChanges(t *testing.T, cloud openstack.OpenstackCloud, allTasks map[string]fi.Task) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			KubernetesVersion: "v1.20.0",
+		},
+	}
+	assetBuilder := assets.NewAssetBuilder(cluster, "")
+	target := fi.NewDryRunTarget(assetBuilder, os.Stderr)
+	context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	defer context.Close()
+
+	if err := context.RunTasks(testRunTasksOptions); err != nil {
+		t.Fatalf("unexpected error during Run: %v", err)
+	}
+
+	if target.HasChanges() {
+		var b bytes.Buffer
+		if err := target.PrintReport(allTasks, &b); err != nil {
+			t.Fatalf("error building report: %v", err)
+		}
+		t.Fatalf("Target had changes after executing: %v", b.String())
+	}
+}
+
+func TestLBHealthMonitor(t *testing.T) {
+	cloud := buildMockLBCloud(t)
+	pool := createLBPool(t, cloud, "api")
+
+	buildTasks := func(delay int) map[string]fi.Task {
+		return map[string]fi.Task{
+			"pool": pool,
+			"monitor": &LBHealthMonitor{
+				Name:       pool.Name,
+				Pool:       pool,
+				Delay:      fi.Int(delay),
+				Timeout:    fi.Int(5),
+				MaxRetries: fi.Int(3),
+			},
+		}
+	}
+
+	getMonitor := func() monitors.Monitor {
+		monitorList, err := cloud.ListMonitors(monitors.ListOpts{PoolID: fi.StringValue(pool.ID)})
+		if err != nil {
+			t.Fatalf("error listing health monitors: %v", err)
+		}
+		if len(monitorList) != 1 {
+			t.Fatalf("expected exactly one health monitor, found %v", monitorList)
+		}
+		return monitorList[0]
+	}
+
+	{
+		allTasks := buildTasks(10)
+		runTasks(t, cloud, allTasks)
+
+		monitor := getMonitor()
+		if monitor.ID != fi.StringValue(allTasks["monitor"].(*LBHealthMonitor).ID) {
+			t.Errorf("ID of health monitor not set after create")
+		}
+		if monitor.Type != monitors.TypeTCP || monitor.Delay != 10 || monitor.Timeout != 5 || monitor.MaxRetries != 3 {
+			t.Errorf("unexpected health monitor after create: %+v", monitor)
+		}
+		checkNoChanges(t, cloud, buildTasks(10))
+	}
+
+	{
+		runTasks(t, cloud, buildTasks(20))
+
+		monitor := getMonitor()
+		if monitor.Delay != 20 || monitor.Timeout != 5 || monitor.MaxRetries != 3 {
+			t.Errorf("unexpected health monitor after update: %+v", monitor)
+		}
+		checkNoChanges(t, cloud, buildTasks(20))
+	}
+
+	{
+		// Once the health monitor is removed from the model, the pool deletes it
+		runTasks(t, cloud, map[string]fi.Task{"pool": pool})
+
+		monitorList, err := cloud.ListMonitors(monitors.ListOpts{PoolID: fi.StringValue(pool.ID)})
+		if err != nil {
+			t.Fatalf("error listing health monitors: %v", err)
+		}
+		if len(monitorList) != 0 {
+			t.Errorf("expected health monitor to be deleted, found %v", monitorList)
+		}
+		checkNoChanges(t, cloud, map[string]fi.Task{"pool": pool})
+	}
+}
@@ -34,6 +34,12 @@ type LBListener struct {
 	Pool         *LBPool
 	Lifecycle    *fi.Lifecycle
 	AllowedCIDRs []string
+	// TimeoutClientData is the client inactivity timeout in milliseconds
+	TimeoutClientData *int
+	// TimeoutMemberData is the member inactivity timeout in milliseconds
+	TimeoutMemberData *int
+	// Protocol is the protocol of the listener, TCP when unset
+	Protocol *string
 }
 
 // GetDependencies returns the dependencies of the Instance task
@@ -63,7 +69,11 @@ func NewLBListenerTaskFromCloud(cloud openstack.OpenstackCloud, lifecycle *fi.Li
 		ID:           fi.String(lb.ID),
 		Name:         fi.String(lb.Name),
 		AllowedCIDRs: lb.AllowedCIDRs,
+		Protocol:     fi.String(lb.Protocol),
 		Lifecycle:    lifecycle,
+
+		TimeoutClientData: fi.Int(lb.TimeoutClientData),
+		TimeoutMemberData: fi.Int(lb.TimeoutMemberData),
 	}
 
 	for _, pool := range lb.Pools {
@@ -124,6 +134,9 @@ func (_ *LBListener) CheckChanges(a, e, changes *LBListener) error {
 		if changes.Name != nil {
 			return fi.CannotChangeField("Name")
 		}
+		if changes.Protocol != nil {
+			return fi.CannotChangeField("Protocol")
+		}
 	}
 	return nil
 }
@@ -138,29 +151,55 @@ func (_ *LBListener) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, chan
 			Protocol:       listeners.ProtocolTCP,
 			ProtocolPort:   443,
 		}
+		if e.Protocol != nil {
+			listeneropts.Protocol = listeners.Protocol(fi.StringValue(e.Protocol))
+		}
 
 		if openstackutil.IsOctaviaFeatureSupported(t.Cloud.LoadBalancerClient(), openstackutil.OctaviaFeatureVIPACL) {
 			listeneropts.AllowedCIDRs = e.AllowedCIDRs
 		}
 
+		if e.TimeoutClientData != nil || e.TimeoutMemberData != nil {
+			if openstackutil.IsOctaviaFeatureSupported(t.Cloud.LoadBalancerClient(), openstackutil.OctaviaFeatureTimeout) {
+				listeneropts.TimeoutClientData = e.TimeoutClientData
+				listeneropts.TimeoutMemberData = e.TimeoutMemberData
+			} else {
+				klog.Warningf("Openstack Octavia listener timeouts not supported, ignoring them")
+			}
+		}
+
 		listener, err := t.Cloud.CreateListener(listeneropts)
 		if err != nil {
 			return fmt.Errorf("error creating LB listener: %v", err)
 		}
 		e.ID = fi.String(listener.ID)
 		return nil
-	} else if len(changes.AllowedCIDRs) > 0 {
+	}
+
+	opts := listeners.UpdateOpts{}
+	update := false
+	if len(changes.AllowedCIDRs) > 0 {
 		if openstackutil.IsOctaviaFeatureSupported(t.Cloud.LoadBalancerClient(), openstackutil.OctaviaFeatureVIPACL) {
-			opts := listeners.UpdateOpts{
-				AllowedCIDRs: &changes.AllowedCIDRs,
-			}
-			_, err := listeners.Update(t.Cloud.LoadBalancerClient(), fi.StringValue(a.ID), opts).Extract()
-			if err != nil {
-				return fmt.Errorf("error updating LB listener: %v", err)
-			}
+			opts.AllowedCIDRs = &changes.AllowedCIDRs
+			update = true
 		} else {
 			klog.V(2).Infof("Openstack Octavia VIPACLs not supported")
 		}
+	}
+	if changes.TimeoutClientData != nil || changes.TimeoutMemberData != nil {
+		if openstackutil.IsOctaviaFeatureSupported(t.Cloud.LoadBalancerClient(), openstackutil.OctaviaFeatureTimeout) {
+			opts.TimeoutClientData = e.TimeoutClientData
+			opts.TimeoutMemberData = e.TimeoutMemberData
+			update = true
+		} else {
+			klog.Warningf("Openstack Octavia listener timeouts not supported, ignoring them")
+		}
+	}
+	if update {
+		_, err := listeners.Update(t.Cloud.LoadBalancerClient(), fi.StringValue(a.ID), opts).Extract()
+		if err != nil {
+			return fmt.Errorf("error updating LB listener: %v", err)
+		}
 		return nil
 	}
 	klog.V(2).Infof("Openstack task LB::RenderOpenstack did nothing")
@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstacktasks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	v2pools "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+)
+
+func getListener(t *testing.T, cloud openstack.OpenstackCloud, name string) listeners.Listener {
+	listenerList, err := cloud.ListListeners(listeners.ListOpts{Name: name})
+	if err != nil {
+		t.Fatalf("error listing listeners: %v", err)
+	}
+	if len(listenerList) != 1 {
+		t.Fatalf("expected exactly one listener, found %v", listenerList)
+	}
+	return listenerList[0]
+}
+
+func TestLBListenerTimeouts(t *testing.T) {
+	cloud := buildMockLBCloud(t)
+	pool := createLBPool(t, cloud, "api")
+
+	buildTasks := func(timeout int) map[string]fi.Task {
+		return map[string]fi.Task{
+			"listener": &LBListener{
+				Name:              fi.String("api"),
+				Pool:              pool,
+				AllowedCIDRs:      []string{"192.0.2.0/24"},
+				TimeoutClientData: fi.Int(timeout),
+				TimeoutMemberData: fi.Int(timeout),
+			},
+		}
+	}
+
+	{
+		runTasks(t, cloud, buildTasks(300000))
+
+		listener := getListener(t, cloud, "api")
+		if listener.TimeoutClientData != 300000 || listener.TimeoutMemberData != 300000 {
+			t.Errorf("unexpected timeouts after create: client %d, member %d", listener.TimeoutClientData, listener.TimeoutMemberData)
+		}
+		if !reflect.DeepEqual(listener.AllowedCIDRs, []string{"192.0.2.0/24"}) {
+			t.Errorf("unexpected allowed CIDRs after create: %v", listener.AllowedCIDRs)
+		}
+		checkNoChanges(t, cloud, buildTasks(300000))
+	}
+
+	{
+		runTasks(t, cloud, buildTasks(600000))
+
+		listener := getListener(t, cloud, "api")
+		if listener.TimeoutClientData != 600000 || listener.TimeoutMemberData != 600000 {
+			t.Errorf("unexpected timeouts after update: client %d, member %d", listener.TimeoutClientData, listener.TimeoutMemberData)
+		}
+		checkNoChanges(t, cloud, buildTasks(600000))
+	}
+}
+
+func TestLBListenerDefaultTimeouts(t *testing.T) {
+	cloud := buildMockLBCloud(t)
+	pool := createLBPool(t, cloud, "api")
+
+	buildTasks := func(allowedCIDRs ...string) map[string]fi.Task {
+		return map[string]fi.Task{
+			"listener": &LBListener{
+				Name:         fi.String("api"),
+				Pool:         pool,
+				AllowedCIDRs: allowedCIDRs,
+			},
+		}
+	}
+
+	runTasks(t, cloud, buildTasks("192.0.2.0/24"))
+	// Octavia applies its own timeouts, which kops leaves alone unless they are configured
+	checkNoChanges(t, cloud, buildTasks("192.0.2.0/24"))
+
+	runTasks(t, cloud, buildTasks("192.0.2.0/24", "198.51.100.0/24"))
+	listener := getListener(t, cloud, "api")
+	if !reflect.DeepEqual(listener.AllowedCIDRs, []string{"192.0.2.0/24", "198.51.100.0/24"}) {
+		t.Errorf("unexpected allowed CIDRs after update: %v", listener.AllowedCIDRs)
+	}
+}
+
+func TestLBListenerTLSPassthrough(t *testing.T) {
+	cloud := buildMockLBCloud(t)
+	lb := createLBPool(t, cloud, "api").Loadbalancer
+
+	buildTasks := func() map[string]fi.Task {
+		pool := &LBPool{
+			Name:         fi.String("api-tls"),
+			Loadbalancer: lb,
+			Protocol:     fi.String("HTTPS"),
+		}
+		return map[string]fi.Task{
+			"pool": pool,
+			"listener": &LBListener{
+				Name:     fi.String("api-tls"),
+				Pool:     pool,
+				Protocol: fi.String("HTTPS"),
+			},
+		}
+	}
+
+	runTasks(t, cloud, buildTasks())
+
+	listener := getListener(t, cloud, "api-tls")
+	if listener.Protocol != "HTTPS" {
+		t.Errorf("unexpected listener protocol after create: %s", listener.Protocol)
+	}
+	poolList, err := cloud.ListPools(v2pools.ListOpts{Name: "api-tls"})
+	if err != nil {
+		t.Fatalf("error listing pools: %v", err)
+	}
+	if len(poolList) != 1 || poolList[0].Protocol != "HTTPS" {
+		t.Errorf("expected one HTTPS pool, found %v", poolList)
+	}
+	checkNoChanges(t, cloud, buildTasks())
+
+	// Octavia cannot change the protocol of an existing listener
+	a := &LBListener{Name: fi.String("api-tls"), Protocol: fi.String("HTTPS")}
+	e := &LBListener{Name: fi.String("api-tls"), Protocol: fi.String("TCP")}
+	if err := (&LBListener{}).CheckChanges(a, e, &LBListener{Protocol: e.Protocol}); err == nil {
+		t.Errorf("expected an error when changing the listener protocol")
+	}
+}
@@ -19,6 +19,7 @@ package openstacktasks
 import (
 	"fmt"
 
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
 	v2pools "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
@@ -31,6 +32,8 @@ type LBPool struct {
 	Name         *string
 	Lifecycle    *fi.Lifecycle
 	Loadbalancer *LB
+	// Protocol is the protocol of the pool members, TCP when unset
+	Protocol *string
 }
 
 // GetDependencies returns the dependencies of the Instance task
@@ -59,6 +62,7 @@ func NewLBPoolTaskFromCloud(cloud openstack.OpenstackCloud, lifecycle *fi.Lifecy
 	a := &LBPool{
 		ID:        fi.String(pool.ID),
 		Name:      fi.String(pool.Name),
+		Protocol:  fi.String(pool.Protocol),
 		Lifecycle: lifecycle,
 	}
 	if len(pool.Loadbalancers) == 1 {
@@ -120,6 +124,9 @@ func (_ *LBPool) CheckChanges(a, e, changes *LBPool) error {
 		if changes.Name != nil {
 			return fi.CannotChangeField("Name")
 		}
+		if changes.Protocol != nil {
+			return fi.CannotChangeField("Protocol")
+		}
 	}
 	return nil
 }
@@ -139,6 +146,9 @@ func (_ *LBPool) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes
 			Protocol:       v2pools.ProtocolTCP,
 			LoadbalancerID: fi.StringValue(e.Loadbalancer.ID),
 		}
+		if e.Protocol != nil {
+			poolopts.Protocol = v2pools.Protocol(fi.StringValue(e.Protocol))
+		}
 		pool, err := t.Cloud.CreatePool(poolopts)
 		if err != nil {
 			return fmt.Errorf("error creating LB pool: %v", err)
@@ -151,3 +161,70 @@ func (_ *LBPool) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes
 	klog.V(2).Infof("Openstack task LB::RenderOpenstack did nothing")
 	return nil
 }
+
+var _ fi.ProducesDeletions = &LBPool{}
+
+// FindDeletions finds the health monitors of the pool that no LBHealthMonitor task manages anymore
+func (p *LBPool) FindDeletions(c *fi.Context) ([]fi.Deletion, error) {
+	if p.ID == nil {
+		return nil, nil
+	}
+
+	cloud := c.Cloud.(openstack.OpenstackCloud)
+	monitorList, err := cloud.ListMonitors(monitors.ListOpts{
+		PoolID: fi.StringValue(p.ID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list health monitors for pool %s: %v", fi.StringValue(p.Name), err)
+	}
+
+	var removals []fi.Deletion
+	for _, monitor := range monitorList {
+		found := false
+		for _, t := range c.AllTasks() {
+			e, ok := t.(*LBHealthMonitor)
+			if !ok || e.Pool == nil {
+				continue
+			}
+			if fi.StringValue(e.Pool.Name) == fi.StringValue(p.Name) && fi.StringValue(e.Name) == monitor.Name {
+				found = true
+			}
+		}
+		if !found {
+			removals = append(removals, &deleteLBHealthMonitor{
+				monitor: monitor,
+				pool:    p,
+			})
+		}
+	}
+	return removals, nil
+}
+
+type deleteLBHealthMonitor struct {
+	monitor monitors.Monitor
+	pool    *LBPool
+}
+
+var _ fi.Deletion = &deleteLBHealthMonitor{}
+
+func (d *deleteLBHealthMonitor) Delete(t fi.Target) error {
+	klog.V(2).Infof("deleting health monitor: %v", d.monitor.Name)
+
+	os, ok := t.(*openstack.OpenstackAPITarget)
+	if !ok {
+		return fmt.Errorf("unexpected target type for deletion: %T", t)
+	}
+	err := os.Cloud.DeleteMonitor(d.monitor.ID)
+	if err != nil {
+		return fmt.Errorf("error deleting health monitor: %v", err)
+	}
+	return nil
+}
+
+func (d *deleteLBHealthMonitor) TaskName() string {
+	return "LBHealthMonitor"
+}
+
+func (d *deleteLBHealthMonitor) Item() string {
+	return fmt.Sprintf("healthmonitor=%s pool=%s", d.monitor.Name, fi.StringValue(d.pool.Name))
+}
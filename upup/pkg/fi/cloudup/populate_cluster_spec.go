@@ -350,5 +350,23 @@ func (c *populateClusterSpec) assignSubnets(cluster *kopsapi.Cluster) error {
 		klog.V(2).Infof("Defaulted ServiceClusterIPRange to %v", cluster.Spec.ServiceClusterIPRange)
 	}
 
+	if cluster.Spec.UsesIPv6() {
+		// The IPv6 pod and service networks default to unique local addresses, which the CNI masquerades on egress
+		if cluster.Spec.PodIPv6CIDR == "" {
+			cluster.Spec.PodIPv6CIDR = "fd00:10:244::/48"
+			klog.V(2).Infof("Defaulted PodIPv6CIDR to %v", cluster.Spec.PodIPv6CIDR)
+		}
+		if cluster.Spec.ServiceClusterIPv6Range == "" {
+			cluster.Spec.ServiceClusterIPv6Range = "fd00:10:96::/112"
+			klog.V(2).Infof("Defaulted ServiceClusterIPv6Range to %v", cluster.Spec.ServiceClusterIPv6Range)
+		}
+
+		// kube-controller-manager allocates node pod CIDRs from the range of each IP family
+		if !strings.Contains(cluster.Spec.KubeControllerManager.ClusterCIDR, ",") {
+			cluster.Spec.KubeControllerManager.ClusterCIDR = strings.Join(cluster.Spec.PodCIDRs(), ",")
+			klog.V(2).Infof("Defaulted KubeControllerManager.ClusterCIDR to %v", cluster.Spec.KubeControllerManager.ClusterCIDR)
+		}
+	}
+
 	return nil
 }
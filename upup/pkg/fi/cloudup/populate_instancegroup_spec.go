@@ -27,6 +27,8 @@ import (
 	"k8s.io/kops/pkg/apis/kops/validation"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/reflectutils"
@@ -34,23 +36,26 @@ import (
 
 // Default Machine types for various types of instance group machine
 const (
-	defaultNodeMachineTypeGCE   = "n1-standard-2"
-	defaultNodeMachineTypeDO    = "s-2vcpu-4gb"
-	defaultNodeMachineTypeALI   = "ecs.n2.medium"
-	defaultNodeMachineTypeAzure = "Standard_B2ms"
+	defaultNodeMachineTypeGCE     = "n1-standard-2"
+	defaultNodeMachineTypeDO      = "s-2vcpu-4gb"
+	defaultNodeMachineTypeALI     = "ecs.n2.medium"
+	defaultNodeMachineTypeAzure   = "Standard_B2ms"
+	defaultNodeMachineTypeHetzner = "cx21"
 
 	defaultBastionMachineTypeGCE   = "f1-micro"
 	defaultBastionMachineTypeALI   = "ecs.n2.small"
 	defaultBastionMachineTypeAzure = "Standard_B2ms"
 
-	defaultMasterMachineTypeGCE   = "n1-standard-1"
-	defaultMasterMachineTypeDO    = "s-2vcpu-4gb"
-	defaultMasterMachineTypeALI   = "ecs.n2.medium"
-	defaultMasterMachineTypeAzure = "Standard_B2ms"
+	defaultMasterMachineTypeGCE     = "n1-standard-1"
+	defaultMasterMachineTypeDO      = "s-2vcpu-4gb"
+	defaultMasterMachineTypeALI     = "ecs.n2.medium"
+	defaultMasterMachineTypeAzure   = "Standard_B2ms"
+	defaultMasterMachineTypeHetzner = "cx21"
 
-	defaultDONodeImage    = "ubuntu-20-04-x64"
-	defaultALINodeImage   = "centos_7_04_64_20G_alibase_201701015.vhd"
-	defaultAzureNodeImage = "Canonical:UbuntuServer:20.04-LTS:latest"
+	defaultDONodeImage      = "ubuntu-20-04-x64"
+	defaultALINodeImage     = "centos_7_04_64_20G_alibase_201701015.vhd"
+	defaultAzureNodeImage   = "Canonical:UbuntuServer:20.04-LTS:latest"
+	defaultHetznerNodeImage = "ubuntu-20.04"
 )
 
 // TODO: this hardcoded list can be replaced with DescribeInstanceTypes' DedicatedHostsSupported field
@@ -75,6 +80,16 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 	ig := &kops.InstanceGroup{}
 	reflectutils.JSONMergeStruct(ig, input)
 
+	// The size of an instance group of pre-provisioned hosts is the size of its inventory
+	if ig.Spec.Metal != nil {
+		if ig.Spec.MinSize == nil {
+			ig.Spec.MinSize = fi.Int32(int32(len(ig.Spec.Metal.Hosts)))
+		}
+		if ig.Spec.MaxSize == nil {
+			ig.Spec.MaxSize = fi.Int32(int32(len(ig.Spec.Metal.Hosts)))
+		}
+	}
+
 	// TODO: Clean up
 	if ig.IsMaster() {
 		if ig.Spec.MachineType == "" {
@@ -118,7 +133,8 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 		}
 	}
 
-	if ig.Spec.Image == "" {
+	// Pre-provisioned hosts come with their own operating system
+	if ig.Spec.Image == "" && ig.Spec.Metal == nil {
 		architecture, err := MachineArchitecture(cloud, ig.Spec.MachineType)
 		if err != nil {
 			return nil, fmt.Errorf("unable to determine machine architecture for InstanceGroup %q: %v", ig.ObjectMeta.Name, err)
@@ -224,6 +240,15 @@ func defaultMachineType(cloud fi.Cloud, cluster *kops.Cluster, ig *kops.Instance
 		case kops.InstanceGroupRoleBastion:
 			return defaultBastionMachineTypeAzure, nil
 		}
+
+	case kops.CloudProviderHetzner:
+		switch ig.Spec.Role {
+		case kops.InstanceGroupRoleMaster:
+			return defaultMasterMachineTypeHetzner, nil
+
+		case kops.InstanceGroupRoleNode:
+			return defaultNodeMachineTypeHetzner, nil
+		}
 	}
 
 	klog.V(2).Infof("Cannot set default MachineType for CloudProvider=%q, Role=%q", cluster.Spec.CloudProvider, ig.Spec.Role)
@@ -256,6 +281,8 @@ func defaultImage(cluster *kops.Cluster, channel *kops.Channel, architecture arc
 		return defaultALINodeImage
 	case kops.CloudProviderAzure:
 		return defaultAzureNodeImage
+	case kops.CloudProviderHetzner:
+		return defaultHetznerNodeImage
 	}
 	klog.Infof("Cannot set default Image for CloudProvider=%q", cluster.Spec.CloudProvider)
 	return ""
@@ -280,6 +307,17 @@ func MachineArchitecture(cloud fi.Cloud, machineType string) (architectures.Arch
 		default:
 			return "", fmt.Errorf("unsupported architecture for instance type %q: %s", machineType, arch)
 		}
+	case kops.CloudProviderGCE:
+		// Custom machine types are not in the catalog
+		if info, err := gce.GetMachineTypeInfo(machineType); err == nil {
+			return info.Architecture, nil
+		}
+		return architectures.ArchitectureAmd64, nil
+	case kops.CloudProviderAzure:
+		if info, err := azure.GetMachineTypeInfo(machineType); err == nil {
+			return info.Architecture, nil
+		}
+		return architectures.ArchitectureAmd64, nil
 	default:
 		// No other clouds are known to support any other architectures at this time
 		return architectures.ArchitectureAmd64, nil
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcegraph
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+var (
+	typeTask     = reflect.TypeOf((*fi.Task)(nil)).Elem()
+	typeResource = reflect.TypeOf((*fi.Resource)(nil)).Elem()
+)
+
+// converter converts the fields of tasks to generic JSON values
+type converter struct {
+	// ids maps each task to its ID in the graph
+	ids map[fi.Task]string
+}
+
+// convertStruct converts the exported fields of a struct, omitting fields that are not set
+func (c *converter) convertStruct(v reflect.Value) (map[string]interface{}, error) {
+	properties := make(map[string]interface{})
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			// Unexported
+			continue
+		}
+		if field.Name == "Lifecycle" {
+			// How kOps manages the task, rather than part of the resource
+			continue
+		}
+
+		value, err := c.convert(v.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		if value != nil {
+			properties[field.Name] = value
+		}
+	}
+	return properties, nil
+}
+
+// convert converts a value, returning nil if the value is not set
+func (c *converter) convert(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	if v.Type().Implements(typeTask) && v.Kind() != reflect.Interface {
+		return c.reference(v.Interface().(fi.Task))
+	}
+	if v.Type().Implements(typeResource) {
+		s, err := fi.ResourceAsString(v.Interface().(fi.Resource))
+		if err != nil {
+			return nil, fmt.Errorf("error rendering resource: %v", err)
+		}
+		return s, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return c.convert(v.Elem())
+
+	case reflect.Struct:
+		return c.convertStruct(v)
+
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			value, err := c.convert(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+
+	case reflect.Map:
+		values := make(map[string]interface{})
+		for _, key := range v.MapKeys() {
+			value, err := c.convert(v.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			values[fmt.Sprintf("%v", key.Interface())] = value
+		}
+		return values, nil
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil, nil
+
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// reference returns a reference to another task
+func (c *converter) reference(task fi.Task) (interface{}, error) {
+	id, found := c.ids[task]
+	if !found {
+		// The task is not in the task map, for example an existing resource that is referenced by ID
+		hasName, ok := task.(fi.HasName)
+		if !ok {
+			return nil, fmt.Errorf("task %T is not in the task map and has no name", task)
+		}
+		id = fi.TypeNameForTask(task) + "/" + fi.StringValue(hasName.GetName())
+	}
+	return map[string]interface{}{"$ref": id}, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcegraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"sync"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// OutputFile is the name of the file that the resource graph is written to
+const OutputFile = "resources.json"
+
+// ResourceGraphTarget writes the expected state of each task, and the dependencies between tasks,
+// as a provider-neutral JSON resource graph, for consumption by other infrastructure-as-code tools.
+type ResourceGraphTarget struct {
+	Cloud fi.Cloud
+
+	outDir string
+
+	// mutex protects tasks
+	mutex sync.Mutex
+	tasks []fi.Task
+}
+
+// Graph is the resource graph that is written to OutputFile
+type Graph struct {
+	// CloudProvider is the cloud provider of the cluster
+	CloudProvider string `json:"cloudProvider"`
+	// Resources are the resources of the cluster, sorted by ID
+	Resources []*Resource `json:"resources"`
+}
+
+// Resource is the expected state of a task
+type Resource struct {
+	// ID identifies the resource in the graph, and is the type and name of the task
+	ID string `json:"id"`
+	// Type is the type of the task, for example VPC or AutoscalingGroup
+	Type string `json:"type"`
+	// Name is the name of the task
+	Name string `json:"name"`
+	// Properties are the fields of the task. References to other tasks are written as {"$ref": "<id>"}.
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	// DependsOn are the IDs of the resources that the resource depends on
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+var _ fi.Target = &ResourceGraphTarget{}
+var _ fi.TaskRenderer = &ResourceGraphTarget{}
+
+func NewResourceGraphTarget(cloud fi.Cloud, outDir string) *ResourceGraphTarget {
+	return &ResourceGraphTarget{
+		Cloud:  cloud,
+		outDir: outDir,
+	}
+}
+
+func (t *ResourceGraphTarget) ProcessDeletions() bool {
+	// The consumer of the graph is responsible for deletions
+	return false
+}
+
+// RenderTask implements fi.TaskRenderer
+func (t *ResourceGraphTarget) RenderTask(e fi.Task) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.tasks = append(t.tasks, e)
+	return nil
+}
+
+func (t *ResourceGraphTarget) Finish(taskMap map[string]fi.Task) error {
+	graph, err := t.buildGraph(taskMap)
+	if err != nil {
+		return err
+	}
+
+	// Scripts such as user data are easier to read without HTML escaping
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(graph); err != nil {
+		return fmt.Errorf("error marshaling resource graph to json: %v", err)
+	}
+
+	p := path.Join(t.outDir, OutputFile)
+	if err := os.MkdirAll(path.Dir(p), os.FileMode(0755)); err != nil {
+		return fmt.Errorf("error creating output directory %q: %v", path.Dir(p), err)
+	}
+	if err := ioutil.WriteFile(p, data.Bytes(), os.FileMode(0644)); err != nil {
+		return fmt.Errorf("error writing resource graph to output file %q: %v", p, err)
+	}
+
+	klog.Infof("Resource graph is in %s", t.outDir)
+
+	return nil
+}
+
+func (t *ResourceGraphTarget) buildGraph(taskMap map[string]fi.Task) (*Graph, error) {
+	ids := make(map[fi.Task]string)
+	for id, task := range taskMap {
+		ids[task] = id
+	}
+
+	rendered := make(map[string]bool)
+	for _, task := range t.tasks {
+		rendered[ids[task]] = true
+	}
+
+	dependencies := fi.FindTaskDependencies(taskMap)
+
+	graph := &Graph{}
+	if t.Cloud != nil {
+		graph.CloudProvider = string(t.Cloud.ProviderID())
+	}
+
+	for _, task := range t.tasks {
+		id, found := ids[task]
+		if !found {
+			return nil, fmt.Errorf("task %v not found in the task map", task)
+		}
+
+		resource := &Resource{
+			ID:   id,
+			Type: fi.TypeNameForTask(task),
+		}
+		if hasName, ok := task.(fi.HasName); ok {
+			resource.Name = fi.StringValue(hasName.GetName())
+		}
+
+		c := &converter{ids: ids}
+		properties, err := c.convertStruct(reflect.ValueOf(task).Elem())
+		if err != nil {
+			return nil, fmt.Errorf("error converting %s: %v", id, err)
+		}
+		resource.Properties = properties
+
+		// Tasks that were not rendered, such as secrets in the state store, are not in the graph
+		for _, dependency := range dependencies[id] {
+			if rendered[dependency] {
+				resource.DependsOn = append(resource.DependsOn, dependency)
+			}
+		}
+		sort.Strings(resource.DependsOn)
+
+		graph.Resources = append(graph.Resources, resource)
+	}
+
+	sort.Slice(graph.Resources, func(i, j int) bool {
+		return graph.Resources[i].ID < graph.Resources[j].ID
+	})
+
+	return graph, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcegraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type Network struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+	CIDR      *string
+	Tags      map[string]string
+}
+
+func (e *Network) Run(c *fi.Context) error { return nil }
+func (e *Network) GetName() *string        { return e.Name }
+
+type Instance struct {
+	Name     *string
+	Network  *Network
+	Secret   *Network
+	Zones    []string
+	UserData fi.Resource
+	Count    int
+	ignored  string
+}
+
+func (e *Instance) Run(c *fi.Context) error { return nil }
+func (e *Instance) GetName() *string        { return e.Name }
+
+func TestBuildGraph(t *testing.T) {
+	lifecycle := fi.LifecycleSync
+	network := &Network{
+		Name:      fi.String("main"),
+		Lifecycle: &lifecycle,
+		CIDR:      fi.String("10.0.0.0/16"),
+		Tags:      map[string]string{"cluster": "example"},
+	}
+	secret := &Network{Name: fi.String("secret")}
+	instance := &Instance{
+		Name:     fi.String("node"),
+		Network:  network,
+		Secret:   secret,
+		Zones:    []string{"a", "b"},
+		UserData: fi.NewStringResource("#!/bin/sh"),
+		Count:    2,
+		ignored:  "ignored",
+	}
+	taskMap := map[string]fi.Task{
+		"Network/main":   network,
+		"Network/secret": secret,
+		"Instance/node":  instance,
+	}
+
+	target := NewResourceGraphTarget(nil, "")
+	// The secret task is applied directly rather than rendered, so it is not in the graph
+	for _, task := range []fi.Task{instance, network} {
+		if err := target.RenderTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	graph, err := target.buildGraph(taskMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := json.Marshal(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"cloudProvider":"","resources":[` +
+		`{"id":"Instance/node","type":"Instance","name":"node","properties":{"Count":2,"Name":"node","Network":{"$ref":"Network/main"},"Secret":{"$ref":"Network/secret"},"UserData":"#!/bin/sh","Zones":["a","b"]},"dependsOn":["Network/main"]},` +
+		`{"id":"Network/main","type":"Network","name":"main","properties":{"CIDR":"10.0.0.0/16","Name":"main","Tags":{"cluster":"example"}}}]}`
+	if string(actual) != expected {
+		t.Errorf("unexpected graph\nactual:   %s\nexpected: %s", actual, expected)
+	}
+}
@@ -210,3 +210,16 @@ func allSubnetsHaveCIDRs(c *kops.Cluster) bool {
 
 	return true
 }
+
+// assignIPv6CIDRsToSubnets gives each subnet created by kops its own /64 of the VPC's IPv6 CIDR.
+// The VPC's IPv6 CIDR is allocated by AWS, so the subnets are specified relative to it.
+func assignIPv6CIDRsToSubnets(c *kops.Cluster) {
+	for i := range c.Spec.Subnets {
+		subnet := &c.Spec.Subnets[i]
+		if subnet.ProviderID != "" || subnet.IPv6CIDR != "" {
+			continue
+		}
+		subnet.IPv6CIDR = fmt.Sprintf("/64#%d", i)
+		klog.V(2).Infof("Assigned IPv6 CIDR %s to subnet %s", subnet.IPv6CIDR, subnet.Name)
+	}
+}
@@ -101,8 +101,17 @@ func writeValue(body *hclwrite.Body, key string, value cty.Value) {
 // key = "value1"
 // key = res_type.res_name.res_prop
 // key = file("${module.path}/foo")
+// key = cidrsubnet(res_type.res_name.res_prop, 8, 1)
 func writeLiteral(body *hclwrite.Body, key string, literal *Literal) {
-	if literal.FilePath != "" {
+	if literal.Expression != "" {
+		tokens := hclwrite.Tokens{
+			{
+				Type:  hclsyntax.TokenIdent,
+				Bytes: []byte(literal.Expression),
+			},
+		}
+		body.SetAttributeRaw(key, tokens)
+	} else if literal.FilePath != "" {
 		tokens := hclwrite.Tokens{
 			{
 				Type:  hclsyntax.TokenIdent,
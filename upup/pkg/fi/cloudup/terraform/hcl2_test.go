@@ -138,6 +138,11 @@ func TestWriteLiteral(t *testing.T) {
 			},
 			expected: `foo = file("${path.module}/foo")`,
 		},
+		{
+			name:     "function",
+			literal:  LiteralFunctionExpression("cidrsubnet", LiteralProperty("type", "name", "prop").Expr(), "8", "1"),
+			expected: "foo = cidrsubnet(type.name.prop, 8, 1)",
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// importScript is the name of the script that imports existing resources into terraform state
+const importScript = "import.sh"
+
+// Importable is implemented by tasks whose existing cloud resources can be imported into terraform state
+type Importable interface {
+	// TerraformImport returns the resources that RenderTerraform emits for the task,
+	// with the IDs that terraform imports them with, given the existing resource that Find returned
+	TerraformImport(actual fi.Task) []*Import
+}
+
+// Import is an existing cloud resource that terraform can import into its state
+type Import struct {
+	// ResourceType is the terraform type of the resource, as passed to RenderResource
+	ResourceType string
+	// ResourceName is the name of the resource, as passed to RenderResource
+	ResourceName string
+	// ID is the identifier that `terraform import` accepts for the resource
+	ID string
+}
+
+var _ fi.ExistingResourceRecorder = &TerraformTarget{}
+
+// ShouldFindExisting implements fi.ExistingResourceRecorder
+func (t *TerraformTarget) ShouldFindExisting(e fi.Task) bool {
+	if !t.ImportExisting {
+		return false
+	}
+	_, ok := e.(Importable)
+	return ok
+}
+
+// RecordExisting implements fi.ExistingResourceRecorder
+func (t *TerraformTarget) RecordExisting(e fi.Task, a fi.Task) error {
+	imports := e.(Importable).TerraformImport(a)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, i := range imports {
+		if i.ID == "" {
+			continue
+		}
+		t.imports = append(t.imports, i)
+	}
+	return nil
+}
+
+// writeImportScript writes a script that imports the existing resources into terraform state.
+// Resources that were not rendered, such as shared resources, are skipped.
+func (t *TerraformTarget) writeImportScript() {
+	rendered := make(map[string]bool)
+	for _, res := range t.resources {
+		rendered[res.ResourceType+"."+tfSanitize(res.ResourceName)] = true
+	}
+
+	ids := make(map[string]string)
+	for _, i := range t.imports {
+		address := i.ResourceType + "." + tfSanitize(i.ResourceName)
+		if rendered[address] {
+			ids[address] = i.ID
+		}
+	}
+
+	addresses := make([]string, 0, len(ids))
+	for address := range ids {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	var b bytes.Buffer
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Imports the existing cloud resources of the cluster into terraform state.\n")
+	b.WriteString("# If the configuration is used as a module, pass the address of the module, for example module.kubernetes\n")
+	b.WriteString("set -e\n")
+	b.WriteString("prefix=\"${1:+$1.}\"\n")
+	for _, address := range addresses {
+		b.WriteString("terraform import \"${prefix}\"" + shellQuote(address) + " " + shellQuote(ids[address]) + "\n")
+	}
+	t.files[importScript] = b.Bytes()
+}
+
+// shellQuote quotes a string for the shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
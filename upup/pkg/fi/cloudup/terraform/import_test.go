@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/diff"
+)
+
+func TestWriteImportScript(t *testing.T) {
+	target := &TerraformTarget{
+		ImportExisting: true,
+		files:          make(map[string][]byte),
+	}
+	target.RenderResource("aws_vpc", "example.com", struct{}{})
+	target.RenderResource("aws_iam_role_policy", "nodes.example.com", struct{}{})
+
+	target.imports = []*Import{
+		{ResourceType: "aws_vpc", ResourceName: "example.com", ID: "vpc-12345678"},
+		{ResourceType: "aws_iam_role_policy", ResourceName: "nodes.example.com", ID: "nodes:it's"},
+		// Shared resources are found, but not rendered
+		{ResourceType: "aws_subnet", ResourceName: "us-east-1a.example.com", ID: "subnet-12345678"},
+	}
+
+	target.writeImportScript()
+
+	expected := `#!/bin/sh
+# Imports the existing cloud resources of the cluster into terraform state.
+# If the configuration is used as a module, pass the address of the module, for example module.kubernetes
+set -e
+prefix="${1:+$1.}"
+terraform import "${prefix}"'aws_iam_role_policy.nodes-example-com' 'nodes:it'\''s'
+terraform import "${prefix}"'aws_vpc.example-com' 'vpc-12345678'
+`
+	actual := string(target.files[importScript])
+	if actual != expected {
+		t.Logf("diff:\n%s\n", diff.FormatDiff(expected, actual))
+		t.Errorf("unexpected import script: %s", strings.TrimSpace(actual))
+	}
+}
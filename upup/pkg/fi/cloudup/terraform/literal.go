@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
 	"k8s.io/klog/v2"
 )
@@ -48,6 +49,8 @@ type Literal struct {
 	FilePath string `cty:"file_path"`
 	// FileFn represents the function used to reference the file
 	FileFn fileFn `cty:"file_fn"`
+	// Expression represents a function call expression
+	Expression string `cty:"expression"`
 }
 
 var _ json.Marshaler = &Literal{}
@@ -85,6 +88,27 @@ func LiteralProperty(resourceType, resourceName, prop string) *Literal {
 	}
 }
 
+// LiteralFunctionExpression constructs a literal calling a terraform function.
+// The args are terraform expressions, such as the result of LiteralProperty(...).Expr().
+func LiteralFunctionExpression(functionName string, args ...string) *Literal {
+	expr := functionName + "(" + strings.Join(args, ", ") + ")"
+	return &Literal{
+		Value:      "${" + expr + "}",
+		Expression: expr,
+	}
+}
+
+// Expr returns the literal as a terraform 0.12 expression, for use as a function argument
+func (l *Literal) Expr() string {
+	if l.ResourceType != "" && l.ResourceName != "" && l.ResourceProp != "" {
+		return l.ResourceType + "." + l.ResourceName + "." + l.ResourceProp
+	}
+	if l.Expression != "" {
+		return l.Expression
+	}
+	return fmt.Sprintf("%q", l.Value)
+}
+
 func LiteralFromStringValue(s string) *Literal {
 	return &Literal{Value: s}
 }
@@ -37,6 +37,12 @@ type TerraformTarget struct {
 
 	ClusterName string
 
+	// Module is true if the output is a reusable module, with input variables for key values and no provider configuration
+	Module bool
+
+	// ImportExisting writes a script that imports the existing cloud resources of the cluster into terraform state
+	ImportExisting bool
+
 	outDir string
 
 	// mutex protects the following items (resources & files)
@@ -45,6 +51,10 @@ type TerraformTarget struct {
 	resources []*terraformResource
 	// outputs is a list of our TF output variables
 	outputs map[string]*terraformOutputVariable
+	// inputs is a list of the input variables of the module
+	inputs []*terraformInputVariable
+	// imports is a list of the existing resources to import into terraform state
+	imports []*Import
 	// files is a map of TF resource files that should be created
 	files map[string][]byte
 	// extra config to add to the provider block
@@ -85,6 +95,15 @@ type terraformOutputVariable struct {
 	ValueArray []*Literal
 }
 
+// terraformInputVariable is a variable of a module, which sets an attribute of a resource
+type terraformInputVariable struct {
+	Name         string
+	Description  string
+	ResourceType string
+	ResourceName string
+	Attribute    string
+}
+
 // A TF name can't have dots in it (if we want to refer to it from a literal),
 // so we replace them
 func tfSanitize(name string) string {
@@ -169,6 +188,27 @@ func (t *TerraformTarget) AddOutputVariableArray(key string, literal *Literal) e
 	return nil
 }
 
+// AddInputVariable sets an attribute of a resource from an input variable when the output is a module.
+// The variable defaults to the value the attribute would otherwise have.
+func (t *TerraformTarget) AddInputVariable(name string, description string, resourceType string, resourceName string, attribute string) {
+	if !t.Module {
+		return
+	}
+
+	v := &terraformInputVariable{
+		Name:         tfSanitize(name),
+		Description:  description,
+		ResourceType: resourceType,
+		ResourceName: tfSanitize(resourceName),
+		Attribute:    attribute,
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.inputs = append(t.inputs, v)
+}
+
 // tfGetProviderExtraConfig is a helper function to get extra config with safety checks on the pointers.
 func tfGetProviderExtraConfig(c *kops.TargetSpec) map[string]string {
 	if c != nil &&
@@ -182,6 +222,9 @@ func tfGetProviderExtraConfig(c *kops.TargetSpec) map[string]string {
 func (t *TerraformTarget) Finish(taskMap map[string]fi.Task) error {
 	var err error
 	if featureflag.TerraformJSON.Enabled() {
+		if t.Module {
+			return fmt.Errorf("terraform module output is not supported with the TerraformJSON feature flag")
+		}
 		err = t.finishJSON(taskMap)
 	} else {
 		err = t.finishHCL2(taskMap)
@@ -190,19 +233,35 @@ func (t *TerraformTarget) Finish(taskMap map[string]fi.Task) error {
 		return err
 	}
 
+	if t.ImportExisting {
+		t.writeImportScript()
+	}
+
 	for relativePath, contents := range t.files {
 		p := path.Join(t.outDir, relativePath)
 
+		mode := os.FileMode(0644)
+		if relativePath == importScript {
+			mode = os.FileMode(0755)
+		}
+
 		err = os.MkdirAll(path.Dir(p), os.FileMode(0755))
 		if err != nil {
 			return fmt.Errorf("error creating output directory %q: %v", path.Dir(p), err)
 		}
 
-		err = ioutil.WriteFile(p, contents, os.FileMode(0644))
+		err = ioutil.WriteFile(p, contents, mode)
 		if err != nil {
 			return fmt.Errorf("error writing terraform data to output file %q: %v", p, err)
 		}
 	}
+	if t.Module {
+		// A kubernetes.tf from an earlier run would duplicate the resources in main.tf
+		p := path.Join(t.outDir, "kubernetes.tf")
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing %q: %v", p, err)
+		}
+	}
 	klog.Infof("Terraform output is in %s", t.outDir)
 
 	return nil
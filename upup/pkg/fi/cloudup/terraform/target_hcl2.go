@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
@@ -29,7 +30,9 @@ import (
 )
 
 func (t *TerraformTarget) finishHCL2(taskMap map[string]fi.Task) error {
-	resourcesByType := make(map[string]map[string]interface{})
+	if t.Module {
+		return t.finishHCL2Module()
+	}
 
 	f := hclwrite.NewEmptyFile()
 	rootBody := f.Body()
@@ -48,6 +51,61 @@ func (t *TerraformTarget) finishHCL2(taskMap map[string]fi.Task) error {
 	}
 	rootBody.AppendNewline()
 
+	if _, err := t.writeResources(rootBody); err != nil {
+		return err
+	}
+
+	t.writeTerraformBlock(rootBody)
+
+	bytes := hclwrite.Format(f.Bytes())
+	t.files["kubernetes.tf"] = bytes
+
+	return nil
+}
+
+// finishHCL2Module writes the resources as a module, leaving the provider configuration to the caller:
+// main.tf holds the resources, variables.tf the input variables, outputs.tf the output variables,
+// and versions.tf the required providers.
+func (t *TerraformTarget) finishHCL2Module() error {
+	mainFile := hclwrite.NewEmptyFile()
+	defaults, err := t.writeResources(mainFile.Body())
+	if err != nil {
+		return err
+	}
+
+	variablesFile := hclwrite.NewEmptyFile()
+	if err := writeInputVariables(variablesFile.Body(), t.inputs, defaults); err != nil {
+		return err
+	}
+
+	outputsFile := hclwrite.NewEmptyFile()
+	if err := writeLocalsOutputs(outputsFile.Body(), t.outputs); err != nil {
+		return err
+	}
+
+	versionsFile := hclwrite.NewEmptyFile()
+	t.writeTerraformBlock(versionsFile.Body())
+
+	t.files["main.tf"] = hclwrite.Format(mainFile.Bytes())
+	t.files["variables.tf"] = hclwrite.Format(variablesFile.Bytes())
+	t.files["outputs.tf"] = hclwrite.Format(outputsFile.Bytes())
+	t.files["versions.tf"] = hclwrite.Format(versionsFile.Bytes())
+
+	return nil
+}
+
+// writeResources writes a block for each resource.
+// Attributes that are set from an input variable refer to the variable;
+// the values they would otherwise have are returned, keyed by variable name.
+func (t *TerraformTarget) writeResources(rootBody *hclwrite.Body) (map[string]cty.Value, error) {
+	resourcesByType := make(map[string]map[string]interface{})
+
+	inputs := make(map[string]*terraformInputVariable)
+	for _, v := range t.inputs {
+		inputs[v.ResourceType+"."+v.ResourceName+"."+v.Attribute] = v
+	}
+	defaults := make(map[string]cty.Value)
+
 	sort.Sort(byTypeAndName(t.resources))
 	for _, res := range t.resources {
 		resources := resourcesByType[res.ResourceType]
@@ -59,7 +117,7 @@ func (t *TerraformTarget) finishHCL2(taskMap map[string]fi.Task) error {
 		tfName := tfSanitize(res.ResourceName)
 
 		if resources[tfName] != nil {
-			return fmt.Errorf("duplicate resource found: %s.%s", res.ResourceType, tfName)
+			return nil, fmt.Errorf("duplicate resource found: %s.%s", res.ResourceType, tfName)
 		}
 		resources[tfName] = res.Item
 
@@ -67,22 +125,35 @@ func (t *TerraformTarget) finishHCL2(taskMap map[string]fi.Task) error {
 		resBody := resBlock.Body()
 		resType, err := gocty.ImpliedType(res.Item)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		resVal, err := gocty.ToCtyValue(res.Item, resType)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if resVal.IsNull() {
 			continue
 		}
 		resVal.ForEachElement(func(key cty.Value, value cty.Value) bool {
+			if v := inputs[res.ResourceType+"."+tfName+"."+key.AsString()]; v != nil && !value.IsNull() {
+				defaults[v.Name] = value
+				resBody.SetAttributeTraversal(key.AsString(), hcl.Traversal{
+					hcl.TraverseRoot{Name: "var"},
+					hcl.TraverseAttr{Name: v.Name},
+				})
+				return false
+			}
 			writeValue(resBody, key.AsString(), value)
 			return false
 		})
 		rootBody.AppendNewline()
 	}
 
+	return defaults, nil
+}
+
+// writeTerraformBlock writes the terraform block with the required versions of terraform and the providers
+func (t *TerraformTarget) writeTerraformBlock(rootBody *hclwrite.Body) {
 	terraformBlock := rootBody.AppendNewBlock("terraform", []string{})
 	terraformBody := terraformBlock.Body()
 	terraformBody.SetAttributeValue("required_version", cty.StringVal(">= 0.12.26"))
@@ -98,7 +169,7 @@ func (t *TerraformTarget) finishHCL2(taskMap map[string]fi.Task) error {
 	} else if t.Cloud.ProviderID() == kops.CloudProviderAWS {
 		writeMap(requiredProvidersBody, "aws", map[string]cty.Value{
 			"source":  cty.StringVal("hashicorp/aws"),
-			"version": cty.StringVal(">= 2.46.0"),
+			"version": cty.StringVal(">= 3.39.0"),
 		})
 		if featureflag.Spotinst.Enabled() {
 			writeMap(requiredProvidersBody, "spotinst", map[string]cty.Value{
@@ -107,10 +178,46 @@ func (t *TerraformTarget) finishHCL2(taskMap map[string]fi.Task) error {
 			})
 		}
 	}
+}
 
-	bytes := hclwrite.Format(f.Bytes())
-	t.files["kubernetes.tf"] = bytes
+// writeInputVariables creates a variable block for each input variable that sets an attribute,
+// defaulting to the value of the attribute.
+// Example:
+// variable "nodes_min_size" {
+//   description = "Minimum number of instances in instance group nodes"
+//   type        = number
+//   default     = 2
+// }
+func writeInputVariables(body *hclwrite.Body, inputs []*terraformInputVariable, defaults map[string]cty.Value) error {
+	sorted := make([]*terraformInputVariable, 0, len(inputs))
+	seen := make(map[string]bool)
+	for _, v := range inputs {
+		if _, found := defaults[v.Name]; !found {
+			// The attribute is not set, so there is nothing to parameterize
+			continue
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("duplicate input variable found: %s", v.Name)
+		}
+		seen[v.Name] = true
+		sorted = append(sorted, v)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
 
+	for _, v := range sorted {
+		value := defaults[v.Name]
+
+		variableBlock := body.AppendNewBlock("variable", []string{v.Name})
+		variableBody := variableBlock.Body()
+		if v.Description != "" {
+			variableBody.SetAttributeValue("description", cty.StringVal(v.Description))
+		}
+		variableBody.SetAttributeTraversal("type", hcl.Traversal{
+			hcl.TraverseRoot{Name: value.Type().FriendlyNameForConstraint()},
+		})
+		variableBody.SetAttributeValue("default", value)
+		body.AppendNewline()
+	}
 	return nil
 }
 
@@ -22,6 +22,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/upup/pkg/fi"
 )
 
 func TestWriteLocalsOutputs(t *testing.T) {
@@ -134,3 +135,76 @@ output "key1" {
 		})
 	}
 }
+
+func TestWriteResourcesWithInputVariables(t *testing.T) {
+	type testResource struct {
+		MinSize      *int64  `cty:"min_size"`
+		MaxSize      *int64  `cty:"max_size"`
+		InstanceType *string `cty:"instance_type"`
+		ImageID      *string `cty:"image_id"`
+	}
+
+	target := &TerraformTarget{Module: true}
+	if err := target.RenderResource("test_group", "nodes.example.com", &testResource{
+		MinSize:      fi.Int64(2),
+		MaxSize:      fi.Int64(3),
+		InstanceType: fi.String("t3.medium"),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	target.AddInputVariable("nodes_min_size", "Minimum number of instances in instance group nodes", "test_group", "nodes.example.com", "min_size")
+	target.AddInputVariable("nodes_instance_type", "", "test_group", "nodes.example.com", "instance_type")
+	target.AddInputVariable("nodes_image_id", "", "test_group", "nodes.example.com", "image_id")
+
+	resources := hclwrite.NewEmptyFile()
+	defaults, err := target.writeResources(resources.Body())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	variables := hclwrite.NewEmptyFile()
+	if err := writeInputVariables(variables.Body(), target.inputs, defaults); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedResources := `
+resource "test_group" "nodes-example-com" {
+  instance_type = var.nodes_instance_type
+  max_size      = 3
+  min_size      = var.nodes_min_size
+}`
+	// The image is not set, so it is not parameterized
+	expectedVariables := `
+variable "nodes_instance_type" {
+  type    = string
+  default = "t3.medium"
+}
+
+variable "nodes_min_size" {
+  description = "Minimum number of instances in instance group nodes"
+  type        = number
+  default     = 2
+}`
+
+	for _, c := range []struct {
+		expected string
+		actual   []byte
+	}{
+		{expectedResources, hclwrite.Format(resources.Bytes())},
+		{expectedVariables, hclwrite.Format(variables.Bytes())},
+	} {
+		actual := strings.TrimSpace(string(c.actual))
+		expected := strings.TrimSpace(c.expected)
+		if actual != expected {
+			t.Logf("diff:\n%s\n", diff.FormatDiff(expected, actual))
+			t.Errorf("expected: '%s', got: '%s'\n", expected, actual)
+		}
+	}
+}
+
+func TestAddInputVariableIgnoredWithoutModule(t *testing.T) {
+	target := &TerraformTarget{}
+	target.AddInputVariable("nodes_min_size", "", "test_group", "nodes.example.com", "min_size")
+	if len(target.inputs) != 0 {
+		t.Errorf("expected no input variables when not writing a module, got %v", target.inputs)
+	}
+}
@@ -128,7 +128,7 @@ func (t *TerraformTarget) finishJSON(taskMap map[string]fi.Task) error {
 	} else if t.Cloud.ProviderID() == kops.CloudProviderAWS {
 		requiredProviderAWS := make(map[string]interface{})
 		requiredProviderAWS["source"] = "hashicorp/aws"
-		requiredProviderAWS["version"] = ">= 2.46.0"
+		requiredProviderAWS["version"] = ">= 3.39.0"
 		for k, v := range tfGetProviderExtraConfig(t.clusterSpecTarget) {
 			requiredProviderAWS[k] = v
 		}
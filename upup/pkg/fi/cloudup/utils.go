@@ -30,6 +30,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 )
 
@@ -109,6 +111,23 @@ func BuildCloud(cluster *kops.Cluster) (fi.Cloud, error) {
 			cloud = doCloud
 		}
 
+	case kops.CloudProviderHetzner:
+		{
+			// All the servers of a cluster live in a single location
+			region := cluster.Spec.Subnets[0].Zone
+			hetznerCloud, err := hetzner.NewHetznerCloud(region)
+			if err != nil {
+				return nil, fmt.Errorf("error initializing hetzner cloud: %s", err)
+			}
+
+			cloud = hetznerCloud
+		}
+
+	case kops.CloudProviderMetal:
+		{
+			cloud = metal.NewMetalCloud()
+		}
+
 	case kops.CloudProviderOpenstack:
 		{
 			cloudTags := map[string]string{openstack.TagClusterName: cluster.ObjectMeta.Name}
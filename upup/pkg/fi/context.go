@@ -212,6 +212,9 @@ func (c *Context) Render(a, e, changes Task) error {
 
 	}
 	if renderer == nil {
+		if taskRenderer, ok := c.Target.(TaskRenderer); ok {
+			return taskRenderer.RenderTask(e)
+		}
 		return fmt.Errorf("could not find Render method on type %T (target %T)", e, c.Target)
 	}
 	rendererArgs = append(rendererArgs, reflect.ValueOf(a))
@@ -54,6 +54,16 @@ func DefaultDeltaRunMethod(e Task, c *Context) error {
 			}
 			return err
 		}
+	} else if recorder, ok := c.Target.(ExistingResourceRecorder); ok && recorder.ShouldFindExisting(e) {
+		existing, err := invokeFind(e, c)
+		if err != nil {
+			return fmt.Errorf("error finding existing resource: %v", err)
+		}
+		if existing != nil {
+			if err := recorder.RecordExisting(e, existing); err != nil {
+				return err
+			}
+		}
 	}
 
 	if a == nil {
@@ -271,6 +271,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.KubeControllerManagerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeSchedulerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.EtcdManagerTLSBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.EtcdManagerVolumesBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeProxyBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KopsControllerBuilder{NodeupModelContext: modelContext})
 
@@ -295,6 +296,17 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	}
 	// Protokube load image task is in ProtokubeBuilder
 
+	// Instances of autoscaling groups with a warm pool complete the warm pool lifecycle hook once configured.
+	// Instances launched into the warm pool only install their assets and images; they join the cluster when
+	// started from the warm pool, as nodeup runs again at boot.
+	useWarmPool := c.Target == "direct" && api.CloudProviderID(c.cluster.Spec.CloudProvider) == api.CloudProviderAWS &&
+		c.instanceGroup != nil && c.instanceGroup.Spec.WarmPool != nil
+	warming := useWarmPool && isWarming()
+	if warming {
+		klog.Infof("instance is warming; won't start services joining the cluster")
+		prepareWarmingTasks(taskMap)
+	}
+
 	var cloud fi.Cloud
 	var target fi.Target
 	checkExisting := true
@@ -333,6 +345,16 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		klog.Exitf("error closing target: %v", err)
 	}
 
+	if useWarmPool {
+		if err := completeWarmPoolLifecycleAction(); err != nil {
+			klog.Warningf("error completing warm pool lifecycle action: %v", err)
+		}
+	}
+	if warming && waitForInService() {
+		klog.Infof("instance moved from the warm pool into service; joining the cluster")
+		return c.Run(out)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// warmPoolPollInterval is the interval between checks of the target lifecycle state of a running warm instance
+const warmPoolPollInterval = 10 * time.Second
+
+// warmingServices are the services that would join the instance to the cluster, and are not started while warming
+var warmingServices = map[string]bool{
+	"kubelet.service":   true,
+	"protokube.service": true,
+}
+
+// targetLifecycleState returns the lifecycle state the instance is transitioning to, e.g. Warmed:Stopped or InService
+func targetLifecycleState() (string, error) {
+	b, err := vfs.Context.ReadFile("metadata://aws/meta-data/autoscaling/target-lifecycle-state")
+	if err != nil {
+		return "", fmt.Errorf("error reading target lifecycle state from AWS metadata: %v", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// isWarming returns true if the instance is being launched into the warm pool of its autoscaling group
+func isWarming() bool {
+	state, err := targetLifecycleState()
+	if err != nil {
+		klog.Warning(err)
+		return false
+	}
+	klog.Infof("target lifecycle state is %q", state)
+	return strings.HasPrefix(state, "Warmed:")
+}
+
+// waitForInService waits until a running instance of the warm pool is moved into the autoscaling group.
+// Stopped instances don't need to wait, as nodeup runs again when they are started.
+func waitForInService() bool {
+	for {
+		state, err := targetLifecycleState()
+		if err != nil {
+			klog.Warning(err)
+		} else if state == "InService" {
+			return true
+		} else if state != "Warmed:Running" {
+			return false
+		}
+		time.Sleep(warmPoolPollInterval)
+	}
+}
+
+// prepareWarmingTasks removes the tasks bootstrapping the node and keeps the services joining the cluster stopped,
+// so that a warming instance only installs its assets and images before it is stopped
+func prepareWarmingTasks(taskMap map[string]fi.Task) {
+	for name, task := range taskMap {
+		switch t := task.(type) {
+		case *nodetasks.BootstrapClientTask:
+			delete(taskMap, name)
+		case *nodetasks.File:
+			if r, ok := t.Contents.(*fi.TaskDependentResource); ok {
+				if _, ok := r.Task.(*nodetasks.BootstrapClientTask); ok {
+					delete(taskMap, name)
+				}
+			}
+		case *nodetasks.Service:
+			if warmingServices[t.Name] {
+				t.Running = fi.Bool(false)
+			}
+		}
+	}
+}
+
+// completeWarmPoolLifecycleAction completes the launch lifecycle hook of the autoscaling group, which holds
+// the instance until it is configured
+func completeWarmPoolLifecycleAction() error {
+	azBytes, err := vfs.Context.ReadFile("metadata://aws/meta-data/placement/availability-zone")
+	if err != nil {
+		return fmt.Errorf("error reading availability zone from AWS metadata: %v", err)
+	}
+	region := string(azBytes[:len(azBytes)-1])
+
+	instanceIDBytes, err := vfs.Context.ReadFile("metadata://aws/meta-data/instance-id")
+	if err != nil {
+		return fmt.Errorf("error reading instance-id from AWS metadata: %v", err)
+	}
+	instanceID := string(instanceIDBytes)
+
+	config := aws.NewConfig().WithCredentialsChainVerboseErrors(true).WithRegion(region)
+	s, err := session.NewSession(config)
+	if err != nil {
+		return fmt.Errorf("error starting new AWS session: %v", err)
+	}
+
+	result, err := ec2.New(s).DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{&instanceID},
+	})
+	if err != nil {
+		return fmt.Errorf("error describing instances: %v", err)
+	}
+
+	asgName := ""
+	for _, reservation := range result.Reservations {
+		for _, instance := range reservation.Instances {
+			for _, tag := range instance.Tags {
+				if aws.StringValue(tag.Key) == "aws:autoscaling:groupName" {
+					asgName = aws.StringValue(tag.Value)
+				}
+			}
+		}
+	}
+	if asgName == "" {
+		return fmt.Errorf("instance %q is not part of an autoscaling group", instanceID)
+	}
+
+	klog.Infof("completing lifecycle action %q of instance %q in autoscaling group %q", awsup.WarmPoolLifecycleHookName, instanceID, asgName)
+	_, err = autoscaling.New(s).CompleteLifecycleAction(&autoscaling.CompleteLifecycleActionInput{
+		AutoScalingGroupName:  aws.String(asgName),
+		InstanceId:            aws.String(instanceID),
+		LifecycleActionResult: aws.String("CONTINUE"),
+		LifecycleHookName:     aws.String(awsup.WarmPoolLifecycleHookName),
+	})
+	if err != nil {
+		return fmt.Errorf("error completing lifecycle action: %v", err)
+	}
+	return nil
+}
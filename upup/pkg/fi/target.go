@@ -24,3 +24,19 @@ type Target interface {
 	// Some providers (e.g. Terraform) actively keep state, and will delete resources automatically
 	ProcessDeletions() bool
 }
+
+// ExistingResourceRecorder is implemented by targets that render tasks without checking for existing resources,
+// but that record which resources exist already.
+type ExistingResourceRecorder interface {
+	// ShouldFindExisting returns true if the target records whether the resource for the task exists
+	ShouldFindExisting(e Task) bool
+	// RecordExisting is called with the task and the existing resource that its Find returned
+	RecordExisting(e Task, a Task) error
+}
+
+// TaskRenderer is implemented by targets that can render any task,
+// and is used for tasks that have no Render method for the target.
+type TaskRenderer interface {
+	// RenderTask renders the expected state of the task
+	RenderTask(e Task) error
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ociregistry
+
+import (
+	"io/ioutil"
+	"net/url"
+	"sync"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	"k8s.io/klog/v2"
+)
+
+// Credentials are the credentials used to authenticate to a registry
+type Credentials struct {
+	Username string
+	Password string
+	// IdentityToken is an OAuth refresh token, used instead of the username and password
+	IdentityToken string
+}
+
+// Keychain looks up the credentials for a registry.
+// key is the registry domain, or https://index.docker.io/v1/ for Docker Hub, matching the keys in the docker config file.
+type Keychain interface {
+	Credentials(key string) (*Credentials, error)
+}
+
+// dockerConfigKeychain reads credentials from the docker config file,
+// including any credential helpers that it configures.
+type dockerConfigKeychain struct {
+	once       sync.Once
+	configFile *configfile.ConfigFile
+}
+
+// DockerConfigKeychain returns a Keychain that reads credentials the same way as the docker CLI,
+// from $DOCKER_CONFIG/config.json or ~/.docker/config.json
+func DockerConfigKeychain() Keychain {
+	return &dockerConfigKeychain{}
+}
+
+func (k *dockerConfigKeychain) Credentials(key string) (*Credentials, error) {
+	k.once.Do(func() {
+		k.configFile = config.LoadDefaultConfigFile(ioutil.Discard)
+	})
+
+	authConfig, err := k.configFile.GetAuthConfig(key)
+	if err != nil {
+		return nil, err
+	}
+	return credentialsFromAuthConfig(authConfig), nil
+}
+
+func credentialsFromAuthConfig(authConfig types.AuthConfig) *Credentials {
+	return &Credentials{
+		Username:      authConfig.Username,
+		Password:      authConfig.Password,
+		IdentityToken: authConfig.IdentityToken,
+	}
+}
+
+// credentialStore adapts Credentials to the interface used by the distribution auth handlers
+type credentialStore struct {
+	credentials *Credentials
+}
+
+func (s *credentialStore) Basic(*url.URL) (string, string) {
+	return s.credentials.Username, s.credentials.Password
+}
+
+func (s *credentialStore) RefreshToken(*url.URL, string) string {
+	return s.credentials.IdentityToken
+}
+
+func (s *credentialStore) SetRefreshToken(realm *url.URL, service, token string) {
+	klog.V(4).Infof("ignoring refresh token issued by %s for %q", realm, service)
+}
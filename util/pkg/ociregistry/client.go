@@ -0,0 +1,375 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ociregistry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/auth/challenge"
+	"github.com/docker/distribution/registry/client/transport"
+	"k8s.io/klog/v2"
+)
+
+// Media types of the manifests that can be copied
+const (
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+)
+
+var acceptedManifestTypes = []string{
+	MediaTypeDockerManifestList,
+	MediaTypeOCIIndex,
+	MediaTypeDockerManifest,
+	MediaTypeOCIManifest,
+}
+
+// Client talks to container registries using the OCI distribution API
+type Client struct {
+	// Transport is the transport used for requests; http.DefaultTransport is used if nil
+	Transport http.RoundTripper
+	// Keychain provides the credentials for each registry
+	Keychain Keychain
+}
+
+// NewClient builds a Client that authenticates using the docker config file
+func NewClient() *Client {
+	return &Client{
+		Transport: http.DefaultTransport,
+		Keychain:  DockerConfigKeychain(),
+	}
+}
+
+// repository is an authorized session against a single repository
+type repository struct {
+	ref    *imageReference
+	base   string
+	client *http.Client
+
+	// mountFrom is a repository in the same registry that blobs are mounted from when they are written
+	mountFrom string
+}
+
+// openRepository pings the registry to discover how it authenticates,
+// and returns a session authorized for the requested actions on the repository.
+// Any additional scopes are requested along with the repository scope; they are
+// needed to mount blobs from another repository.
+func (c *Client) openRepository(ctx context.Context, ref *imageReference, actions []string, additionalScopes ...auth.Scope) (*repository, error) {
+	baseTransport := c.Transport
+	if baseTransport == nil {
+		baseTransport = http.DefaultTransport
+	}
+
+	host := ref.host()
+	manager := challenge.NewSimpleManager()
+
+	base := "https://" + host
+	resp, err := ping(ctx, baseTransport, base)
+	if err != nil && isLocalhost(host) {
+		// docker allows plain HTTP for registries on the local machine
+		klog.V(2).Infof("falling back to http for registry %q: %v", host, err)
+		base = "http://" + host
+		resp, err = ping(ctx, baseTransport, base)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to registry %q: %v", host, err)
+	}
+	if err := manager.AddResponse(resp); err != nil {
+		return nil, fmt.Errorf("error reading authentication challenge from registry %q: %v", host, err)
+	}
+
+	keychain := c.Keychain
+	if keychain == nil {
+		keychain = DockerConfigKeychain()
+	}
+	credentials, err := keychain.Credentials(ref.authKey())
+	if err != nil {
+		return nil, fmt.Errorf("error reading credentials for registry %q: %v", ref.domain, err)
+	}
+	creds := &credentialStore{credentials: credentials}
+
+	scopes := []auth.Scope{
+		auth.RepositoryScope{Repository: ref.repository, Actions: actions},
+	}
+	scopes = append(scopes, additionalScopes...)
+
+	authorizer := auth.NewAuthorizer(manager,
+		auth.NewTokenHandlerWithOptions(auth.TokenHandlerOptions{
+			Transport:   baseTransport,
+			Credentials: creds,
+			Scopes:      scopes,
+		}),
+		auth.NewBasicHandler(creds))
+
+	return &repository{
+		ref:    ref,
+		base:   base,
+		client: &http.Client{Transport: transport.NewTransport(baseTransport, authorizer)},
+	}, nil
+}
+
+// ping requests the API version check endpoint, which returns any authentication challenge
+func ping(ctx context.Context, rt http.RoundTripper, base string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/v2/", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnauthorized {
+		return nil, fmt.Errorf("unexpected status %q from %s/v2/", resp.Status, base)
+	}
+	return resp, nil
+}
+
+func isLocalhost(host string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if hostname == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(hostname)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (r *repository) String() string {
+	return r.ref.domain + "/" + r.ref.repository
+}
+
+func (r *repository) url(kind, ref string) string {
+	return r.base + "/v2/" + r.ref.repository + "/" + kind + "/" + ref
+}
+
+func (r *repository) do(ctx context.Context, method, u string, body io.Reader, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	return r.client.Do(req)
+}
+
+// manifest is a manifest as stored in the registry
+type manifest struct {
+	MediaType string
+	Digest    string
+	Data      []byte
+}
+
+// headManifest returns the digest of the manifest, or "" if it does not exist
+func (r *repository) headManifest(ctx context.Context, ref string) (string, error) {
+	header := http.Header{"Accept": {strings.Join(acceptedManifestTypes, ", ")}}
+	resp, err := r.do(ctx, http.MethodHead, r.url("manifests", ref), nil, header)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if d := resp.Header.Get("Docker-Content-Digest"); d != "" {
+			return d, nil
+		}
+		// The digest header is optional; fall back to reading the manifest
+		m, err := r.getManifest(ctx, ref)
+		if err != nil {
+			return "", err
+		}
+		return m.Digest, nil
+	case http.StatusNotFound:
+		return "", nil
+	default:
+		return "", client.HandleErrorResponse(resp)
+	}
+}
+
+func (r *repository) getManifest(ctx context.Context, ref string) (*manifest, error) {
+	header := http.Header{"Accept": {strings.Join(acceptedManifestTypes, ", ")}}
+	resp, err := r.do(ctx, http.MethodGet, r.url("manifests", ref), nil, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.HandleErrorResponse(resp)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest %s: %v", r.ref, err)
+	}
+
+	m := &manifest{
+		MediaType: resp.Header.Get("Content-Type"),
+		Digest:    sha256Digest(data),
+		Data:      data,
+	}
+	if strings.HasPrefix(ref, "sha256:") && ref != m.Digest {
+		return nil, fmt.Errorf("manifest for %s has digest %s, expected %s", r.ref, m.Digest, ref)
+	}
+	return m, nil
+}
+
+func (r *repository) putManifest(ctx context.Context, ref string, m *manifest) error {
+	header := http.Header{"Content-Type": {m.MediaType}}
+	resp, err := r.do(ctx, http.MethodPut, r.url("manifests", ref), strings.NewReader(string(m.Data)), header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !client.SuccessStatus(resp.StatusCode) {
+		return client.HandleErrorResponse(resp)
+	}
+	return nil
+}
+
+// blobExists returns true if the repository has the blob
+func (r *repository) blobExists(ctx context.Context, digest string) (bool, error) {
+	resp, err := r.do(ctx, http.MethodHead, r.url("blobs", digest), nil, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, client.HandleErrorResponse(resp)
+	}
+}
+
+// openBlob returns a reader for the blob; the caller must close it
+func (r *repository) openBlob(ctx context.Context, digest string) (io.ReadCloser, error) {
+	resp, err := r.do(ctx, http.MethodGet, r.url("blobs", digest), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, client.HandleErrorResponse(resp)
+	}
+	return resp.Body, nil
+}
+
+// writeBlob uploads a blob, mounting it instead if it is available in the mountFrom repository
+func (r *repository) writeBlob(ctx context.Context, d descriptor, open func() (io.ReadCloser, error)) error {
+	location, err := r.startUpload(ctx, d.Digest, r.mountFrom)
+	if err != nil {
+		return fmt.Errorf("error starting upload: %v", err)
+	}
+	if location == "" {
+		klog.V(4).Infof("mounted blob %s from %s", d.Digest, r.mountFrom)
+		return nil
+	}
+
+	klog.V(2).Infof("uploading blob %s (%d bytes) to %s", d.Digest, d.Size, r)
+	body, err := open()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return r.uploadBlob(ctx, location, d.Digest, d.Size, body)
+}
+
+// startUpload starts a blob upload, and returns the location to upload the blob to.
+// If mountFrom is set, the registry is asked to mount the blob from that repository instead,
+// in which case "" is returned if the mount succeeded.
+func (r *repository) startUpload(ctx context.Context, digest string, mountFrom string) (string, error) {
+	u := r.base + "/v2/" + r.ref.repository + "/blobs/uploads/"
+	if mountFrom != "" {
+		u += "?" + url.Values{"mount": {digest}, "from": {mountFrom}}.Encode()
+	}
+	resp, err := r.do(ctx, http.MethodPost, u, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return "", nil
+	case http.StatusAccepted:
+		location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+		if err != nil {
+			return "", fmt.Errorf("error parsing upload location %q: %v", resp.Header.Get("Location"), err)
+		}
+		return location.String(), nil
+	default:
+		return "", client.HandleErrorResponse(resp)
+	}
+}
+
+// uploadBlob completes an upload started with startUpload in a single request
+func (r *repository) uploadBlob(ctx context.Context, location string, digest string, size int64, body io.Reader) error {
+	u, err := url.Parse(location)
+	if err != nil {
+		return err
+	}
+	q := u.Query()
+	q.Set("digest", digest)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return client.HandleErrorResponse(resp)
+	}
+	return nil
+}
+
+func sha256Digest(data []byte) string {
+	hash := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
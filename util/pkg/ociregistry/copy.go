@@ -0,0 +1,305 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ociregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+
+	"github.com/docker/distribution/registry/client/auth"
+	"k8s.io/klog/v2"
+)
+
+// descriptor references a manifest or blob from a manifest
+type descriptor struct {
+	MediaType string   `json:"mediaType,omitempty"`
+	Digest    string   `json:"digest"`
+	Size      int64    `json:"size"`
+	URLs      []string `json:"urls,omitempty"`
+	// Annotations are only used in the index of OCI image layouts
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// manifestContents holds the fields we need from image manifests and manifest lists
+type manifestContents struct {
+	MediaType string       `json:"mediaType,omitempty"`
+	Config    *descriptor  `json:"config,omitempty"`
+	Layers    []descriptor `json:"layers,omitempty"`
+	Manifests []descriptor `json:"manifests,omitempty"`
+}
+
+// Digest returns the digest of the image's manifest, or "" if the image does not exist
+func (c *Client) Digest(ctx context.Context, image string) (string, error) {
+	ref, err := parseReference(image)
+	if err != nil {
+		return "", err
+	}
+
+	repo, err := c.openRepository(ctx, ref, []string{"pull"})
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := repo.headManifest(ctx, ref.manifestReference())
+	if err != nil {
+		return "", fmt.Errorf("error reading manifest for %s: %v", ref, err)
+	}
+	return digest, nil
+}
+
+// imageSource is a store that images are copied from
+type imageSource interface {
+	fmt.Stringer
+	getManifest(ctx context.Context, ref string) (*manifest, error)
+	openBlob(ctx context.Context, digest string) (io.ReadCloser, error)
+}
+
+// imageTarget is a store that images are copied to
+type imageTarget interface {
+	fmt.Stringer
+	headManifest(ctx context.Context, ref string) (string, error)
+	putManifest(ctx context.Context, ref string, m *manifest) error
+	blobExists(ctx context.Context, digest string) (bool, error)
+	// writeBlob stores a blob; open is only called if the target cannot obtain the blob by other means
+	writeBlob(ctx context.Context, d descriptor, open func() (io.ReadCloser, error)) error
+}
+
+// Copy copies an image from one registry to another.
+// Manifest lists are copied along with every image they reference, so multi-architecture images remain intact.
+// Blobs that already exist in the target are not copied again.
+func (c *Client) Copy(ctx context.Context, sourceImage, targetImage string) error {
+	source, err := parseReference(sourceImage)
+	if err != nil {
+		return err
+	}
+	target, err := parseReference(targetImage)
+	if err != nil {
+		return err
+	}
+	if target.tag == "" {
+		return fmt.Errorf("target image %q must have a tag", targetImage)
+	}
+
+	sourceRepo, err := c.openRepository(ctx, source, []string{"pull"})
+	if err != nil {
+		return err
+	}
+
+	// Blobs can be mounted rather than copied between repositories in the same registry
+	var additionalScopes []auth.Scope
+	if source.host() == target.host() {
+		additionalScopes = append(additionalScopes, auth.RepositoryScope{Repository: source.repository, Actions: []string{"pull"}})
+	}
+	targetRepo, err := c.openRepository(ctx, target, []string{"pull", "push"}, additionalScopes...)
+	if err != nil {
+		return err
+	}
+	if additionalScopes != nil {
+		targetRepo.mountFrom = source.repository
+	}
+
+	return copyImage(ctx, sourceRepo, source.manifestReference(), targetRepo, target.tag)
+}
+
+// Pull copies an image from a registry into an OCI image layout, where it is recorded under the image name
+func (c *Client) Pull(ctx context.Context, image string, layout *Layout) error {
+	source, err := parseReference(image)
+	if err != nil {
+		return err
+	}
+
+	sourceRepo, err := c.openRepository(ctx, source, []string{"pull"})
+	if err != nil {
+		return err
+	}
+
+	return copyImage(ctx, sourceRepo, source.manifestReference(), layout, image)
+}
+
+// Push copies an image recorded in an OCI image layout under name to a registry.
+// The image is tagged in the registry if the target has a tag, otherwise it is only pushed by digest.
+func (c *Client) Push(ctx context.Context, layout *Layout, name string, targetImage string) error {
+	target, err := parseReference(targetImage)
+	if err != nil {
+		return err
+	}
+	targetRef := target.tag
+	if targetRef == "" {
+		targetRef = target.digest
+	}
+
+	targetRepo, err := c.openRepository(ctx, target, []string{"pull", "push"})
+	if err != nil {
+		return err
+	}
+
+	return copyImage(ctx, layout, name, targetRepo, targetRef)
+}
+
+// copyImage copies the manifest sourceRef and everything it references from source to target, as targetRef
+func copyImage(ctx context.Context, source imageSource, sourceRef string, target imageTarget, targetRef string) error {
+	m, err := source.getManifest(ctx, sourceRef)
+	if err != nil {
+		return fmt.Errorf("error reading manifest %s from %s: %v", sourceRef, source, err)
+	}
+
+	if existing, err := target.headManifest(ctx, targetRef); err != nil {
+		return fmt.Errorf("error reading manifest %s from %s: %v", targetRef, target, err)
+	} else if existing == m.Digest {
+		klog.V(2).Infof("image %s in %s is already up to date (%s)", targetRef, target, existing)
+		return nil
+	}
+
+	copier := &imageCopier{
+		source:      source,
+		target:      target,
+		copiedBlobs: make(map[string]bool),
+	}
+	if err := copier.copyManifest(ctx, m); err != nil {
+		return err
+	}
+
+	klog.V(2).Infof("writing manifest %s as %s to %s", m.Digest, targetRef, target)
+	if err := target.putManifest(ctx, targetRef, m); err != nil {
+		return fmt.Errorf("error writing manifest %s to %s: %v", targetRef, target, err)
+	}
+	return nil
+}
+
+// imageCopier copies the contents of manifests from one store to another
+type imageCopier struct {
+	source      imageSource
+	target      imageTarget
+	copiedBlobs map[string]bool
+}
+
+// copyManifest copies everything that the manifest references, but not the manifest itself
+func (c *imageCopier) copyManifest(ctx context.Context, m *manifest) error {
+	contents := &manifestContents{}
+	if err := json.Unmarshal(m.Data, contents); err != nil {
+		return fmt.Errorf("error parsing manifest %s: %v", m.Digest, err)
+	}
+
+	mediaType := ""
+	if m.MediaType != "" {
+		if t, _, err := mime.ParseMediaType(m.MediaType); err == nil {
+			mediaType = t
+		}
+	}
+	if mediaType == "" || mediaType == "application/json" {
+		mediaType = contents.MediaType
+	}
+	if mediaType == "" {
+		// OCI manifests are not required to set a mediaType; tell them apart by their content
+		if contents.Manifests != nil {
+			mediaType = MediaTypeOCIIndex
+		} else {
+			mediaType = MediaTypeOCIManifest
+		}
+	}
+	m.MediaType = mediaType
+
+	switch mediaType {
+	case MediaTypeDockerManifestList, MediaTypeOCIIndex:
+		for _, child := range contents.Manifests {
+			if err := c.copyChildManifest(ctx, child); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case MediaTypeDockerManifest, MediaTypeOCIManifest:
+		var blobs []descriptor
+		if contents.Config != nil {
+			blobs = append(blobs, *contents.Config)
+		}
+		blobs = append(blobs, contents.Layers...)
+		for _, blob := range blobs {
+			if err := c.copyBlob(ctx, blob); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("manifest %s has unsupported media type %q", m.Digest, mediaType)
+	}
+}
+
+// copyChildManifest copies an image referenced from a manifest list, by digest
+func (c *imageCopier) copyChildManifest(ctx context.Context, d descriptor) error {
+	existing, err := c.target.headManifest(ctx, d.Digest)
+	if err != nil {
+		return fmt.Errorf("error reading manifest %s from %s: %v", d.Digest, c.target, err)
+	}
+	if existing == d.Digest {
+		klog.V(4).Infof("manifest %s already exists in %s", d.Digest, c.target)
+		return nil
+	}
+
+	m, err := c.source.getManifest(ctx, d.Digest)
+	if err != nil {
+		return fmt.Errorf("error reading manifest %s from %s: %v", d.Digest, c.source, err)
+	}
+	if m.MediaType == "" {
+		m.MediaType = d.MediaType
+	}
+	if err := c.copyManifest(ctx, m); err != nil {
+		return err
+	}
+
+	klog.V(4).Infof("writing manifest %s to %s", d.Digest, c.target)
+	if err := c.target.putManifest(ctx, d.Digest, m); err != nil {
+		return fmt.Errorf("error writing manifest %s to %s: %v", d.Digest, c.target, err)
+	}
+	return nil
+}
+
+// copyBlob copies a blob, unless it is already present in the target
+func (c *imageCopier) copyBlob(ctx context.Context, d descriptor) error {
+	if c.copiedBlobs[d.Digest] {
+		return nil
+	}
+
+	if len(d.URLs) != 0 {
+		// Foreign layers, such as Windows base layers, are downloaded from their URLs rather than the registry
+		klog.V(4).Infof("skipping foreign layer %s", d.Digest)
+		return nil
+	}
+
+	exists, err := c.target.blobExists(ctx, d.Digest)
+	if err != nil {
+		return fmt.Errorf("error checking for blob %s in %s: %v", d.Digest, c.target, err)
+	}
+	if exists {
+		klog.V(4).Infof("blob %s already exists in %s", d.Digest, c.target)
+		c.copiedBlobs[d.Digest] = true
+		return nil
+	}
+
+	open := func() (io.ReadCloser, error) {
+		return c.source.openBlob(ctx, d.Digest)
+	}
+	if err := c.target.writeBlob(ctx, d, open); err != nil {
+		return fmt.Errorf("error copying blob %s from %s to %s: %v", d.Digest, c.source, c.target, err)
+	}
+	c.copiedBlobs[d.Digest] = true
+	return nil
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ociregistry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// staticKeychain returns fixed credentials for each key
+type staticKeychain map[string]*Credentials
+
+func (k staticKeychain) Credentials(key string) (*Credentials, error) {
+	if c, found := k[key]; found {
+		return c, nil
+	}
+	return &Credentials{}, nil
+}
+
+// pushImage stores an image with one layer in the registry, returning the digest of its manifest
+func pushImage(r *fakeRegistry, repository, tag, layer string) string {
+	config := `{"architecture":"amd64","os":"linux"}`
+	configDigest := r.putBlob(repository, []byte(config))
+	layerDigest := r.putBlob(repository, []byte(layer))
+	manifest := fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"config":{"mediaType":"application/vnd.docker.container.image.v1+json","digest":%q,"size":%d},"layers":[{"mediaType":"application/vnd.docker.image.rootfs.diff.tar.gzip","digest":%q,"size":%d}]}`,
+		MediaTypeDockerManifest, configDigest, len(config), layerDigest, len(layer))
+	return r.putManifest(repository, tag, MediaTypeDockerManifest, []byte(manifest))
+}
+
+func newTestClient(registries ...*fakeRegistry) *Client {
+	keychain := staticKeychain{}
+	for _, r := range registries {
+		if r.username != "" {
+			keychain[r.host()] = &Credentials{Username: r.username, Password: r.password}
+		}
+	}
+	return &Client{
+		Transport: registries[0].server.Client().Transport,
+		Keychain:  keychain,
+	}
+}
+
+func TestCopyImage(t *testing.T) {
+	ctx := context.TODO()
+
+	source := newFakeRegistry("", "")
+	defer source.Close()
+	target := newFakeRegistry("user", "secret")
+	defer target.Close()
+
+	digest := pushImage(source, "kube-proxy", "v1.21.0", "layer-amd64")
+
+	c := newTestClient(source, target)
+	if err := c.Copy(ctx, source.host()+"/kube-proxy:v1.21.0", target.host()+"/mirror/kube-proxy:v1.21.0"); err != nil {
+		t.Fatalf("error copying image: %v", err)
+	}
+
+	m := target.getManifest("mirror/kube-proxy", "v1.21.0")
+	if m == nil {
+		t.Fatalf("manifest was not copied")
+	}
+	if m.Digest != digest {
+		t.Errorf("expected manifest digest %s, got %s", digest, m.Digest)
+	}
+	if m.MediaType != MediaTypeDockerManifest {
+		t.Errorf("expected manifest media type %s, got %s", MediaTypeDockerManifest, m.MediaType)
+	}
+	if !target.hasBlob("mirror/kube-proxy", sha256Digest([]byte("layer-amd64"))) {
+		t.Errorf("layer was not copied")
+	}
+
+	actual, err := c.Digest(ctx, target.host()+"/mirror/kube-proxy:v1.21.0")
+	if err != nil {
+		t.Fatalf("error reading digest: %v", err)
+	}
+	if actual != digest {
+		t.Errorf("expected digest %s, got %s", digest, actual)
+	}
+
+	// Copying again should not upload anything
+	if err := c.Copy(ctx, source.host()+"/kube-proxy:v1.21.0", target.host()+"/mirror/kube-proxy:v1.21.0"); err != nil {
+		t.Fatalf("error copying image again: %v", err)
+	}
+	if n := target.countRequests("PUT", ".*"); n != 3 {
+		t.Errorf("expected 3 uploads in total, got %d", n)
+	}
+}
+
+func TestCopyManifestList(t *testing.T) {
+	ctx := context.TODO()
+
+	source := newFakeRegistry("", "")
+	defer source.Close()
+	target := newFakeRegistry("", "")
+	defer target.Close()
+
+	amd64 := pushImage(source, "pause", "", "layer-amd64")
+	arm64 := pushImage(source, "pause", "", "layer-arm64")
+	list := fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"manifests":[{"mediaType":%q,"digest":%q,"size":1,"platform":{"architecture":"amd64","os":"linux"}},{"mediaType":%q,"digest":%q,"size":1,"platform":{"architecture":"arm64","os":"linux"}}]}`,
+		MediaTypeDockerManifestList, MediaTypeDockerManifest, amd64, MediaTypeDockerManifest, arm64)
+	listDigest := source.putManifest("pause", "3.2", MediaTypeDockerManifestList, []byte(list))
+
+	c := newTestClient(source, target)
+	if err := c.Copy(ctx, source.host()+"/pause:3.2", target.host()+"/pause:3.2"); err != nil {
+		t.Fatalf("error copying image: %v", err)
+	}
+
+	m := target.getManifest("pause", "3.2")
+	if m == nil || m.Digest != listDigest {
+		t.Fatalf("expected manifest list %s to be copied, got %+v", listDigest, m)
+	}
+	for _, d := range []string{amd64, arm64} {
+		if target.getManifest("pause", d) == nil {
+			t.Errorf("manifest %s was not copied", d)
+		}
+	}
+	for _, layer := range []string{"layer-amd64", "layer-arm64"} {
+		if !target.hasBlob("pause", sha256Digest([]byte(layer))) {
+			t.Errorf("layer %q was not copied", layer)
+		}
+	}
+
+	// The config blob is shared by both images, and should only be uploaded once
+	if n := target.countRequests("PUT", "/blobs/uploads/"); n != 3 {
+		t.Errorf("expected 3 blob uploads, got %d", n)
+	}
+}
+
+func TestCopyMountsWithinRegistry(t *testing.T) {
+	ctx := context.TODO()
+
+	r := newFakeRegistry("", "")
+	defer r.Close()
+
+	pushImage(r, "k8s/etcd", "3.4.13", "layer-etcd")
+
+	c := newTestClient(r)
+	if err := c.Copy(ctx, r.host()+"/k8s/etcd:3.4.13", r.host()+"/mirror/etcd:3.4.13"); err != nil {
+		t.Fatalf("error copying image: %v", err)
+	}
+
+	if !r.hasBlob("mirror/etcd", sha256Digest([]byte("layer-etcd"))) {
+		t.Errorf("layer was not copied")
+	}
+	if n := r.countRequests("PUT", "/blobs/uploads/"); n != 0 {
+		t.Errorf("expected blobs to be mounted, got %d uploads", n)
+	}
+}
+
+func TestCopyRequiresCredentials(t *testing.T) {
+	ctx := context.TODO()
+
+	source := newFakeRegistry("", "")
+	defer source.Close()
+	target := newFakeRegistry("user", "secret")
+	defer target.Close()
+
+	pushImage(source, "kube-proxy", "v1.21.0", "layer-amd64")
+
+	c := newTestClient(source)
+	if err := c.Copy(ctx, source.host()+"/kube-proxy:v1.21.0", target.host()+"/kube-proxy:v1.21.0"); err == nil {
+		t.Fatalf("expected error copying image without credentials")
+	}
+}
+
+func TestDigestNotFound(t *testing.T) {
+	r := newFakeRegistry("", "")
+	defer r.Close()
+
+	digest, err := newTestClient(r).Digest(context.TODO(), r.host()+"/missing:v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if digest != "" {
+		t.Errorf("expected no digest, got %q", digest)
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	grid := []struct {
+		image      string
+		host       string
+		authKey    string
+		repository string
+		ref        string
+	}{
+		{
+			image:      "busybox",
+			host:       "registry-1.docker.io",
+			authKey:    "https://index.docker.io/v1/",
+			repository: "library/busybox",
+			ref:        "latest",
+		},
+		{
+			image:      "k8s.gcr.io/kube-proxy:v1.21.0",
+			host:       "k8s.gcr.io",
+			authKey:    "k8s.gcr.io",
+			repository: "kube-proxy",
+			ref:        "v1.21.0",
+		},
+		{
+			image:      "localhost:5000/calico/node:v3.18.1@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			host:       "localhost:5000",
+			authKey:    "localhost:5000",
+			repository: "calico/node",
+			ref:        "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.image, func(t *testing.T) {
+			ref, err := parseReference(g.image)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ref.host() != g.host {
+				t.Errorf("expected host %q, got %q", g.host, ref.host())
+			}
+			if ref.authKey() != g.authKey {
+				t.Errorf("expected auth key %q, got %q", g.authKey, ref.authKey())
+			}
+			if ref.repository != g.repository {
+				t.Errorf("expected repository %q, got %q", g.repository, ref.repository)
+			}
+			if ref.manifestReference() != g.ref {
+				t.Errorf("expected reference %q, got %q", g.ref, ref.manifestReference())
+			}
+		})
+	}
+}
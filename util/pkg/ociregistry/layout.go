@@ -0,0 +1,303 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ociregistry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// AnnotationRefName is the annotation that records the name of an image in the index of an OCI image layout
+	AnnotationRefName = "org.opencontainers.image.ref.name"
+
+	layoutFile    = "oci-layout"
+	indexFile     = "index.json"
+	layoutVersion = `{"imageLayoutVersion":"1.0.0"}`
+)
+
+// ociIndex is the index.json of an OCI image layout
+type ociIndex struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Manifests     []descriptor `json:"manifests"`
+}
+
+// Layout is a directory in the OCI image layout format, holding any number of images.
+// Images are recorded in the index under their names.
+type Layout struct {
+	dir string
+
+	mutex sync.Mutex
+}
+
+// OpenLayout opens the OCI image layout in dir, creating it if it does not exist
+func OpenLayout(dir string) (*Layout, error) {
+	l := &Layout{dir: dir}
+
+	if err := os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0755); err != nil {
+		return nil, fmt.Errorf("error creating %s: %v", dir, err)
+	}
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, layoutFile)); err == nil {
+		var version struct {
+			ImageLayoutVersion string `json:"imageLayoutVersion"`
+		}
+		if err := json.Unmarshal(b, &version); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", filepath.Join(dir, layoutFile), err)
+		}
+		if version.ImageLayoutVersion != "1.0.0" {
+			return nil, fmt.Errorf("unsupported OCI image layout version %q in %s", version.ImageLayoutVersion, dir)
+		}
+	} else if os.IsNotExist(err) {
+		if err := ioutil.WriteFile(filepath.Join(dir, layoutFile), []byte(layoutVersion), 0644); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, err
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, indexFile)); os.IsNotExist(err) {
+		if err := l.writeIndex(&ociIndex{SchemaVersion: 2}); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+func (l *Layout) String() string {
+	return l.dir
+}
+
+// Images returns the names of the images in the layout
+func (l *Layout) Images() ([]string, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	index, err := l.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, d := range index.Manifests {
+		if name := d.Annotations[AnnotationRefName]; name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (l *Layout) readIndex() (*ociIndex, error) {
+	p := filepath.Join(l.dir, indexFile)
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	index := &ociIndex{}
+	if err := json.Unmarshal(b, index); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", p, err)
+	}
+	return index, nil
+}
+
+func (l *Layout) writeIndex(index *ociIndex) error {
+	b, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(l.dir, indexFile), b, 0644)
+}
+
+func (l *Layout) blobPath(digest string) (string, error) {
+	hex := strings.TrimPrefix(digest, "sha256:")
+	if hex == digest || len(hex) != 64 || strings.ContainsAny(hex, "/.") {
+		return "", fmt.Errorf("unsupported digest %q", digest)
+	}
+	return filepath.Join(l.dir, "blobs", "sha256", hex), nil
+}
+
+// lookup finds the index entry for a name, or nil if it is not in the index
+func (l *Layout) lookup(name string) (*descriptor, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	index, err := l.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	for i := range index.Manifests {
+		if index.Manifests[i].Annotations[AnnotationRefName] == name {
+			return &index.Manifests[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// getManifest reads a manifest by digest, or by the name it is recorded under in the index
+func (l *Layout) getManifest(ctx context.Context, ref string) (*manifest, error) {
+	digest := ref
+	mediaType := ""
+	if !strings.HasPrefix(ref, "sha256:") {
+		d, err := l.lookup(ref)
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			return nil, fmt.Errorf("image %q not found in %s", ref, l.dir)
+		}
+		digest = d.Digest
+		mediaType = d.MediaType
+	}
+
+	p, err := l.blobPath(digest)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	if actual := sha256Digest(data); actual != digest {
+		return nil, fmt.Errorf("manifest %s in %s is corrupt: has digest %s", digest, l.dir, actual)
+	}
+
+	return &manifest{MediaType: mediaType, Digest: digest, Data: data}, nil
+}
+
+// headManifest returns the digest of a manifest, or "" if it does not exist
+func (l *Layout) headManifest(ctx context.Context, ref string) (string, error) {
+	if strings.HasPrefix(ref, "sha256:") {
+		exists, err := l.blobExists(ctx, ref)
+		if err != nil || !exists {
+			return "", err
+		}
+		return ref, nil
+	}
+
+	d, err := l.lookup(ref)
+	if err != nil || d == nil {
+		return "", err
+	}
+	return d.Digest, nil
+}
+
+// putManifest stores a manifest; unless ref is a digest, the manifest is recorded in the index under that name
+func (l *Layout) putManifest(ctx context.Context, ref string, m *manifest) error {
+	if err := l.writeBlobData(m.Digest, strings.NewReader(string(m.Data))); err != nil {
+		return err
+	}
+	if strings.HasPrefix(ref, "sha256:") {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	index, err := l.readIndex()
+	if err != nil {
+		return err
+	}
+	d := descriptor{
+		MediaType:   m.MediaType,
+		Digest:      m.Digest,
+		Size:        int64(len(m.Data)),
+		Annotations: map[string]string{AnnotationRefName: ref},
+	}
+	replaced := false
+	for i := range index.Manifests {
+		if index.Manifests[i].Annotations[AnnotationRefName] == ref {
+			index.Manifests[i] = d
+			replaced = true
+		}
+	}
+	if !replaced {
+		index.Manifests = append(index.Manifests, d)
+	}
+	return l.writeIndex(index)
+}
+
+func (l *Layout) blobExists(ctx context.Context, digest string) (bool, error) {
+	p, err := l.blobPath(digest)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(p); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (l *Layout) openBlob(ctx context.Context, digest string) (io.ReadCloser, error) {
+	p, err := l.blobPath(digest)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+func (l *Layout) writeBlob(ctx context.Context, d descriptor, open func() (io.ReadCloser, error)) error {
+	body, err := open()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return l.writeBlobData(d.Digest, body)
+}
+
+// writeBlobData writes a blob, verifying its digest before it becomes visible
+func (l *Layout) writeBlobData(digest string, r io.Reader) error {
+	p, err := l.blobPath(digest)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(p), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hasher), r); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing blob %s: %v", digest, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if actual := "sha256:" + hex.EncodeToString(hasher.Sum(nil)); actual != digest {
+		return fmt.Errorf("blob has digest %s, expected %s", actual, digest)
+	}
+	return os.Rename(f.Name(), p)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ociregistry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPullAndPush(t *testing.T) {
+	ctx := context.TODO()
+
+	source := newFakeRegistry("", "")
+	defer source.Close()
+	target := newFakeRegistry("user", "secret")
+	defer target.Close()
+
+	amd64 := pushImage(source, "pause", "", "layer-amd64")
+	arm64 := pushImage(source, "pause", "", "layer-arm64")
+	list := fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"manifests":[{"mediaType":%q,"digest":%q,"size":1},{"mediaType":%q,"digest":%q,"size":1}]}`,
+		MediaTypeDockerManifestList, MediaTypeDockerManifest, amd64, MediaTypeDockerManifest, arm64)
+	listDigest := source.putManifest("pause", "3.2", MediaTypeDockerManifestList, []byte(list))
+	etcd := pushImage(source, "etcd", "3.4.13", "layer-etcd")
+
+	dir, err := ioutil.TempDir("", "layout")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	layout, err := OpenLayout(dir)
+	if err != nil {
+		t.Fatalf("error opening layout: %v", err)
+	}
+
+	c := newTestClient(source, target)
+	pause := source.host() + "/pause:3.2"
+	if err := c.Pull(ctx, pause, layout); err != nil {
+		t.Fatalf("error pulling image: %v", err)
+	}
+	if err := c.Pull(ctx, source.host()+"/etcd:3.4.13", layout); err != nil {
+		t.Fatalf("error pulling image: %v", err)
+	}
+
+	// Reopening the layout should find the images that were pulled
+	layout, err = OpenLayout(dir)
+	if err != nil {
+		t.Fatalf("error reopening layout: %v", err)
+	}
+	images, err := layout.Images()
+	if err != nil {
+		t.Fatalf("error listing images: %v", err)
+	}
+	expected := []string{source.host() + "/etcd:3.4.13", pause}
+	if !reflect.DeepEqual(images, expected) {
+		t.Errorf("expected images %v, got %v", expected, images)
+	}
+
+	if err := c.Push(ctx, layout, pause, target.host()+"/mirror/pause:3.2"); err != nil {
+		t.Fatalf("error pushing image: %v", err)
+	}
+	if m := target.getManifest("mirror/pause", "3.2"); m == nil || m.Digest != listDigest {
+		t.Fatalf("expected manifest list %s to be pushed, got %+v", listDigest, m)
+	}
+	if m := target.getManifest("mirror/pause", "3.2"); m.MediaType != MediaTypeDockerManifestList {
+		t.Errorf("expected media type %s, got %s", MediaTypeDockerManifestList, m.MediaType)
+	}
+	for _, d := range []string{amd64, arm64} {
+		if target.getManifest("mirror/pause", d) == nil {
+			t.Errorf("manifest %s was not pushed", d)
+		}
+	}
+	for _, layer := range []string{"layer-amd64", "layer-arm64"} {
+		if !target.hasBlob("mirror/pause", sha256Digest([]byte(layer))) {
+			t.Errorf("layer %q was not pushed", layer)
+		}
+	}
+	if target.hasBlob("mirror/pause", sha256Digest([]byte("layer-etcd"))) {
+		t.Errorf("layer from another image was pushed")
+	}
+
+	if err := c.Push(ctx, layout, source.host()+"/etcd:3.4.13", target.host()+"/etcd:3.4.13"); err != nil {
+		t.Fatalf("error pushing image: %v", err)
+	}
+	if m := target.getManifest("etcd", "3.4.13"); m == nil || m.Digest != etcd {
+		t.Fatalf("expected manifest %s to be pushed, got %+v", etcd, m)
+	}
+
+	if err := c.Push(ctx, layout, source.host()+"/missing:v1", target.host()+"/missing:v1"); err == nil {
+		t.Errorf("expected error pushing image that is not in the layout")
+	}
+}
+
+func TestLayoutRejectsCorruptBlob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "layout")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	layout, err := OpenLayout(dir)
+	if err != nil {
+		t.Fatalf("error opening layout: %v", err)
+	}
+
+	digest := sha256Digest([]byte("expected"))
+	open := func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader("actual")), nil
+	}
+	if err := layout.writeBlob(context.TODO(), descriptor{Digest: digest}, open); err == nil {
+		t.Fatalf("expected error writing blob with the wrong digest")
+	}
+	exists, err := layout.blobExists(context.TODO(), digest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists {
+		t.Errorf("corrupt blob should not have been stored")
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ociregistry
+
+import (
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+)
+
+const (
+	// dockerHubDomain is the domain used in image names for Docker Hub
+	dockerHubDomain = "docker.io"
+	// dockerHubHost is the host that serves the registry API for Docker Hub
+	dockerHubHost = "registry-1.docker.io"
+	// dockerHubAuthKey is the key that docker uses for Docker Hub credentials in its config file
+	dockerHubAuthKey = "https://index.docker.io/v1/"
+)
+
+// imageReference is a parsed image name
+type imageReference struct {
+	// domain is the registry domain, as written in the image name
+	domain string
+	// repository is the path of the repository within the registry
+	repository string
+	// tag is the tag of the image, if it is not referenced by digest
+	tag string
+	// digest is the digest of the image manifest, if it is referenced by digest
+	digest string
+}
+
+// parseReference parses an image name, applying the same defaults as docker
+func parseReference(image string) (*imageReference, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing image %q: %v", image, err)
+	}
+
+	ref := &imageReference{
+		domain:     reference.Domain(named),
+		repository: reference.Path(named),
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		ref.digest = digested.Digest().String()
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		ref.tag = tagged.Tag()
+	}
+	if ref.tag == "" && ref.digest == "" {
+		ref.tag = "latest"
+	}
+	return ref, nil
+}
+
+// host returns the host that serves the registry API
+func (r *imageReference) host() string {
+	if r.domain == dockerHubDomain {
+		return dockerHubHost
+	}
+	return r.domain
+}
+
+// authKey returns the key under which docker stores the credentials for the registry
+func (r *imageReference) authKey() string {
+	if r.domain == dockerHubDomain {
+		return dockerHubAuthKey
+	}
+	return r.domain
+}
+
+// manifestReference returns the tag or digest used to fetch the manifest; the digest takes precedence
+func (r *imageReference) manifestReference() string {
+	if r.digest != "" {
+		return r.digest
+	}
+	return r.tag
+}
+
+func (r *imageReference) String() string {
+	s := r.domain + "/" + r.repository
+	if r.tag != "" {
+		s += ":" + r.tag
+	}
+	if r.digest != "" {
+		s += "@" + r.digest
+	}
+	return s
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ociregistry
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// fakeRegistry is an in-process stand-in for a registry, implementing the parts of the distribution API that we use
+type fakeRegistry struct {
+	server *httptest.Server
+
+	// username and password are required to get a token, if set
+	username string
+	password string
+
+	mutex     sync.Mutex
+	manifests map[string]*manifest
+	blobs     map[string][]byte
+	uploads   map[string]string
+	nextID    int
+
+	// requests records the method and path of each registry API request
+	requests []string
+}
+
+const fakeToken = "fake-token"
+
+var (
+	manifestPath = regexp.MustCompile(`^/v2/(.+)/manifests/([^/]+)$`)
+	blobPath     = regexp.MustCompile(`^/v2/(.+)/blobs/(sha256:[0-9a-f]+)$`)
+	uploadsPath  = regexp.MustCompile(`^/v2/(.+)/blobs/uploads/$`)
+	uploadPath   = regexp.MustCompile(`^/v2/.+/blobs/uploads/([0-9]+)$`)
+)
+
+func newFakeRegistry(username, password string) *fakeRegistry {
+	r := &fakeRegistry{
+		username:  username,
+		password:  password,
+		manifests: make(map[string]*manifest),
+		blobs:     make(map[string][]byte),
+		uploads:   make(map[string]string),
+	}
+	r.server = httptest.NewTLSServer(r)
+	return r
+}
+
+// host returns the host:port of the registry, for use in image names
+func (r *fakeRegistry) host() string {
+	return strings.TrimPrefix(r.server.URL, "https://")
+}
+
+func (r *fakeRegistry) Close() {
+	r.server.Close()
+}
+
+// putBlob stores a blob in the registry, returning its digest
+func (r *fakeRegistry) putBlob(repository string, data []byte) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	digest := sha256Digest(data)
+	r.blobs[repository+"@"+digest] = data
+	return digest
+}
+
+// putManifest stores a manifest in the registry under its digest and the tag, if set, returning its digest
+func (r *fakeRegistry) putManifest(repository, tag, mediaType string, data []byte) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	m := &manifest{MediaType: mediaType, Digest: sha256Digest(data), Data: data}
+	r.manifests[repository+"@"+m.Digest] = m
+	if tag != "" {
+		r.manifests[repository+":"+tag] = m
+	}
+	return m.Digest
+}
+
+func (r *fakeRegistry) getManifest(repository, ref string) *manifest {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if strings.HasPrefix(ref, "sha256:") {
+		return r.manifests[repository+"@"+ref]
+	}
+	return r.manifests[repository+":"+ref]
+}
+
+func (r *fakeRegistry) hasBlob(repository, digest string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, found := r.blobs[repository+"@"+digest]
+	return found
+}
+
+// countRequests returns the number of requests with the method and a path matching the pattern
+func (r *fakeRegistry) countRequests(method string, pathPattern string) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	re := regexp.MustCompile(pathPattern)
+	n := 0
+	for _, req := range r.requests {
+		if strings.HasPrefix(req, method+" ") && re.MatchString(strings.TrimPrefix(req, method+" ")) {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		r.serveToken(w, req)
+		return
+	}
+
+	if r.username != "" && req.Header.Get("Authorization") != "Bearer "+fakeToken {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, r.server.URL))
+		http.Error(w, `{"errors":[{"code":"UNAUTHORIZED","message":"authentication required"}]}`, http.StatusUnauthorized)
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if req.URL.Path != "/v2/" {
+		r.requests = append(r.requests, req.Method+" "+req.URL.Path)
+	}
+
+	switch {
+	case req.URL.Path == "/v2/":
+		w.WriteHeader(http.StatusOK)
+
+	case manifestPath.MatchString(req.URL.Path):
+		match := manifestPath.FindStringSubmatch(req.URL.Path)
+		r.serveManifest(w, req, match[1], match[2])
+
+	case blobPath.MatchString(req.URL.Path):
+		match := blobPath.FindStringSubmatch(req.URL.Path)
+		data, found := r.blobs[match[1]+"@"+match[2]]
+		if !found {
+			http.Error(w, `{"errors":[{"code":"BLOB_UNKNOWN","message":"blob unknown"}]}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+		w.WriteHeader(http.StatusOK)
+		if req.Method == http.MethodGet {
+			w.Write(data)
+		}
+
+	case req.Method == http.MethodPost && uploadsPath.MatchString(req.URL.Path):
+		repository := uploadsPath.FindStringSubmatch(req.URL.Path)[1]
+		if mount := req.URL.Query().Get("mount"); mount != "" {
+			if data, found := r.blobs[req.URL.Query().Get("from")+"@"+mount]; found {
+				r.blobs[repository+"@"+mount] = data
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+		}
+		r.nextID++
+		id := fmt.Sprintf("%d", r.nextID)
+		r.uploads[id] = repository
+		w.Header().Set("Location", "/v2/"+repository+"/blobs/uploads/"+id+"?state=abc")
+		w.WriteHeader(http.StatusAccepted)
+
+	case req.Method == http.MethodPut && uploadPath.MatchString(req.URL.Path):
+		id := uploadPath.FindStringSubmatch(req.URL.Path)[1]
+		repository, found := r.uploads[id]
+		if !found || req.URL.Query().Get("state") != "abc" {
+			http.Error(w, "upload unknown", http.StatusNotFound)
+			return
+		}
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		digest := req.URL.Query().Get("digest")
+		if sha256Digest(data) != digest {
+			http.Error(w, `{"errors":[{"code":"DIGEST_INVALID","message":"digest mismatch"}]}`, http.StatusBadRequest)
+			return
+		}
+		delete(r.uploads, id)
+		r.blobs[repository+"@"+digest] = data
+		w.WriteHeader(http.StatusCreated)
+
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (r *fakeRegistry) serveManifest(w http.ResponseWriter, req *http.Request, repository, ref string) {
+	key := repository + ":" + ref
+	if strings.HasPrefix(ref, "sha256:") {
+		key = repository + "@" + ref
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		m, found := r.manifests[key]
+		if !found {
+			http.Error(w, `{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", m.MediaType)
+		w.Header().Set("Docker-Content-Digest", m.Digest)
+		w.WriteHeader(http.StatusOK)
+		if req.Method == http.MethodGet {
+			w.Write(m.Data)
+		}
+
+	case http.MethodPut:
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		m := &manifest{MediaType: req.Header.Get("Content-Type"), Digest: sha256Digest(data), Data: data}
+		r.manifests[key] = m
+		r.manifests[repository+"@"+m.Digest] = m
+		w.WriteHeader(http.StatusCreated)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *fakeRegistry) serveToken(w http.ResponseWriter, req *http.Request) {
+	username, password, ok := req.BasicAuth()
+	if !ok || username != r.username || password != r.password {
+		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"token": %q}`, fakeToken)
+}
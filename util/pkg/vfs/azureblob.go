@@ -106,6 +106,34 @@ func (p *AzureBlobPath) ReadFile() ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// ReadFileWithVersion implements Path::ReadFileWithVersion, using the blob ETag as the version.
+func (p *AzureBlobPath) ReadFileWithVersion() ([]byte, string, error) {
+	cURL, err := p.client.newContainerURL(p.container)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := cURL.NewBlockBlobURL(p.key).Download(
+		context.TODO(),
+		0, /* offset */
+		azblob.CountToEnd,
+		azblob.BlobAccessConditions{},
+		false, /* rangeGetContentMD5 */
+		azblob.ClientProvidedKeyOptions{},
+	)
+	if err != nil {
+		serr, ok := err.(azblob.StorageError)
+		if ok && serr.ServiceCode() == azblob.ServiceCodeBlobNotFound {
+			return nil, "", os.ErrNotExist
+		}
+		return nil, "", err
+	}
+	var b bytes.Buffer
+	if _, err := io.Copy(&b, resp.Body(azblob.RetryReaderOptions{MaxRetryRequests: 10})); err != nil {
+		return nil, "", err
+	}
+	return b.Bytes(), string(resp.ETag()), nil
+}
+
 // WriteTo writes the content of the blob to the writer.
 func (p *AzureBlobPath) WriteTo(w io.Writer) (n int64, err error) {
 	cURL, err := p.client.newContainerURL(p.container)
@@ -156,21 +184,40 @@ func (p *AzureBlobPath) CreateFile(data io.ReadSeeker, acl ACL) error {
 //
 // TODO(kenji): Support ACL.
 func (p *AzureBlobPath) WriteFile(data io.ReadSeeker, acl ACL) error {
+	_, err := p.writeFile(data, acl, azblob.BlobAccessConditions{})
+	return err
+}
+
+// WriteFileIfVersion implements Path::WriteFileIfVersion, using the blob ETag as the version.
+func (p *AzureBlobPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	if version == "" {
+		return "", fmt.Errorf("version is required for conditional write to %s", p.Path())
+	}
+	conditions := azblob.BlobAccessConditions{
+		ModifiedAccessConditions: azblob.ModifiedAccessConditions{
+			IfMatch: azblob.ETag(version),
+		},
+	}
+	return p.writeFile(data, acl, conditions)
+}
+
+// writeFile uploads the blob subject to the access conditions, returning the new ETag.
+func (p *AzureBlobPath) writeFile(data io.ReadSeeker, acl ACL, conditions azblob.BlobAccessConditions) (string, error) {
 	md5Hash, err := hashing.HashAlgorithmMD5.Hash(data)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if _, err := data.Seek(0, 0); err != nil {
-		return fmt.Errorf("error seeking to start of data stream: %v", err)
+		return "", fmt.Errorf("error seeking to start of data stream: %v", err)
 	}
 
 	cURL, err := p.client.newContainerURL(p.container)
 	if err != nil {
-		return err
+		return "", err
 	}
 	// Use block blob. Other options are page blobs (optimized for
 	// random read/write) and append blob (optimized for append).
-	_, err = cURL.NewBlockBlobURL(p.key).Upload(
+	resp, err := cURL.NewBlockBlobURL(p.key).Upload(
 		context.TODO(),
 		data,
 		azblob.BlobHTTPHeaders{
@@ -178,12 +225,19 @@ func (p *AzureBlobPath) WriteFile(data io.ReadSeeker, acl ACL) error {
 			ContentMD5:  md5Hash.HashValue,
 		},
 		azblob.Metadata{},
-		azblob.BlobAccessConditions{},
+		conditions,
 		azblob.AccessTierNone,
 		azblob.BlobTagsMap{},
 		azblob.ClientProvidedKeyOptions{},
 	)
-	return err
+	if err != nil {
+		serr, ok := err.(azblob.StorageError)
+		if ok && serr.ServiceCode() == azblob.ServiceCodeConditionNotMet {
+			return "", fmt.Errorf("error writing %s: %w", p.Path(), ErrConflict)
+		}
+		return "", err
+	}
+	return string(resp.ETag()), nil
 }
 
 // Remove deletes the blob.
@@ -197,6 +251,34 @@ func (p *AzureBlobPath) Remove() error {
 	return err
 }
 
+// RemoveIfVersion deletes the blob, but only if its ETag still matches the version.
+func (p *AzureBlobPath) RemoveIfVersion(version string) error {
+	if version == "" {
+		return fmt.Errorf("version is required for conditional remove of %s", p.Path())
+	}
+	cURL, err := p.client.newContainerURL(p.container)
+	if err != nil {
+		return err
+	}
+	conditions := azblob.BlobAccessConditions{
+		ModifiedAccessConditions: azblob.ModifiedAccessConditions{
+			IfMatch: azblob.ETag(version),
+		},
+	}
+	_, err = cURL.NewBlockBlobURL(p.key).Delete(context.TODO(), azblob.DeleteSnapshotsOptionNone, conditions)
+	if err != nil {
+		serr, ok := err.(azblob.StorageError)
+		if ok && serr.ServiceCode() == azblob.ServiceCodeConditionNotMet {
+			return fmt.Errorf("error removing %s: %w", p.Path(), ErrConflict)
+		}
+		if ok && serr.ServiceCode() == azblob.ServiceCodeBlobNotFound {
+			return os.ErrNotExist
+		}
+		return err
+	}
+	return nil
+}
+
 func (p *AzureBlobPath) RemoveAllVersions() error {
 	cURL, err := p.client.newContainerURL(p.container)
 	if err != nil {
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+// ErrConflict is returned by WriteFileIfVersion when the file has been changed since the expected version was read
+var ErrConflict = errors.New("file has been modified concurrently")
+
+// IsConflict returns true if the error indicates a failed conditional write
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// contentVersion computes a version for stores that have no native object versions;
+// the version is the hash of the contents, so any change to the contents changes the version.
+func contentVersion(data []byte) (string, error) {
+	h, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return h.Hex(), nil
+}
+
+// readFileWithContentVersion implements ReadFileWithVersion for stores without native object versions
+func readFileWithContentVersion(p Path) ([]byte, string, error) {
+	data, err := p.ReadFile()
+	if err != nil {
+		return nil, "", err
+	}
+	version, err := contentVersion(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, version, nil
+}
+
+// writeFileIfContentVersion implements WriteFileIfVersion for stores without native preconditions.
+// withLock must serialize all conditional writers of p; the contents are compared and replaced while it is held.
+func writeFileIfContentVersion(p Path, data io.ReadSeeker, acl ACL, version string, withLock func(fn func() error) error) (string, error) {
+	if version == "" {
+		return "", fmt.Errorf("version is required for conditional write to %s", p)
+	}
+
+	b, err := ioutil.ReadAll(data)
+	if err != nil {
+		return "", fmt.Errorf("error reading data: %v", err)
+	}
+	newVersion, err := contentVersion(b)
+	if err != nil {
+		return "", err
+	}
+
+	err = withLock(func() error {
+		_, currentVersion, err := readFileWithContentVersion(p)
+		if err != nil {
+			return err
+		}
+		if currentVersion != version {
+			return fmt.Errorf("error writing %s: %w", p, ErrConflict)
+		}
+		return p.WriteFile(bytes.NewReader(b), acl)
+	})
+	if err != nil {
+		return "", err
+	}
+	return newVersion, nil
+}
+
+// removeIfContentVersion implements RemoveIfVersion for stores without native preconditions.
+// withLock must serialize all conditional writers of p; the contents are compared and removed while it is held.
+func removeIfContentVersion(p Path, version string, withLock func(fn func() error) error) error {
+	if version == "" {
+		return fmt.Errorf("version is required for conditional remove of %s", p)
+	}
+
+	return withLock(func() error {
+		_, currentVersion, err := readFileWithContentVersion(p)
+		if err != nil {
+			return err
+		}
+		if currentVersion != version {
+			return fmt.Errorf("error removing %s: %w", p, ErrConflict)
+		}
+		return p.Remove()
+	})
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func testWriteFileIfVersion(t *testing.T, p Path) {
+	if err := p.CreateFile(bytes.NewReader([]byte("v1")), nil); err != nil {
+		t.Fatalf("error creating %s: %v", p, err)
+	}
+
+	data, version1, err := p.ReadFileWithVersion()
+	if err != nil {
+		t.Fatalf("error reading %s: %v", p, err)
+	}
+	if string(data) != "v1" {
+		t.Errorf("unexpected contents %q", data)
+	}
+
+	version2, err := p.WriteFileIfVersion(bytes.NewReader([]byte("v2")), nil, version1)
+	if err != nil {
+		t.Fatalf("error writing %s at current version: %v", p, err)
+	}
+	if version2 == version1 {
+		t.Errorf("expected version to change after write, was %q", version2)
+	}
+
+	// A writer holding the old version must be rejected
+	_, err = p.WriteFileIfVersion(bytes.NewReader([]byte("v3")), nil, version1)
+	if !IsConflict(err) {
+		t.Errorf("expected conflict writing %s at stale version, got %v", p, err)
+	}
+
+	data, version, err := p.ReadFileWithVersion()
+	if err != nil {
+		t.Fatalf("error reading %s: %v", p, err)
+	}
+	if string(data) != "v2" {
+		t.Errorf("expected stale write to be rejected, contents are %q", data)
+	}
+	if version != version2 {
+		t.Errorf("expected version %q, got %q", version2, version)
+	}
+}
+
+func testRemoveIfVersion(t *testing.T, p Path) {
+	if err := p.CreateFile(bytes.NewReader([]byte("v1")), nil); err != nil {
+		t.Fatalf("error creating %s: %v", p, err)
+	}
+	_, version1, err := p.ReadFileWithVersion()
+	if err != nil {
+		t.Fatalf("error reading %s: %v", p, err)
+	}
+	version2, err := p.WriteFileIfVersion(bytes.NewReader([]byte("v2")), nil, version1)
+	if err != nil {
+		t.Fatalf("error writing %s at current version: %v", p, err)
+	}
+
+	// A remover holding the old version must be rejected
+	if err := p.RemoveIfVersion(version1); !IsConflict(err) {
+		t.Errorf("expected conflict removing %s at stale version, got %v", p, err)
+	}
+	if _, err := p.ReadFile(); err != nil {
+		t.Errorf("expected stale remove to be rejected, got %v", err)
+	}
+
+	if err := p.RemoveIfVersion(version2); err != nil {
+		t.Fatalf("error removing %s at current version: %v", p, err)
+	}
+	if _, err := p.ReadFile(); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", p, err)
+	}
+}
+
+func TestMemFsWriteFileIfVersion(t *testing.T) {
+	testWriteFileIfVersion(t, NewMemFSPath(NewMemFSContext(), "/root/subdir/test1.data"))
+}
+
+func TestMemFsRemoveIfVersion(t *testing.T) {
+	testRemoveIfVersion(t, NewMemFSPath(NewMemFSContext(), "/root/subdir/test1.data"))
+}
+
+func TestFSRemoveIfVersion(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "test")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer func() {
+		err := os.RemoveAll(tempDir)
+		if err != nil {
+			t.Errorf("failed to remove temp dir %q: %v", tempDir, err)
+		}
+	}()
+
+	testRemoveIfVersion(t, NewFSPath(path.Join(tempDir, "SubDir", "test1.tmp")))
+}
+
+func TestFSWriteFileIfVersion(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "test")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer func() {
+		err := os.RemoveAll(tempDir)
+		if err != nil {
+			t.Errorf("failed to remove temp dir %q: %v", tempDir, err)
+		}
+	}()
+
+	p := NewFSPath(path.Join(tempDir, "SubDir", "test1.tmp"))
+	testWriteFileIfVersion(t, p)
+
+	// The lock file must be cleaned up after writing
+	files, err := ioutil.ReadDir(path.Join(tempDir, "SubDir"))
+	if err != nil {
+		t.Fatalf("error reading dir: %v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected only the written file to remain, found %d files", len(files))
+	}
+
+	// A held lock is reported as a conflict
+	lockPath := path.Join(tempDir, "SubDir", ".test1.tmp.lock")
+	if err := ioutil.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatalf("error creating lock file: %v", err)
+	}
+	_, version, err := p.ReadFileWithVersion()
+	if err != nil {
+		t.Fatalf("error reading %s: %v", p, err)
+	}
+	if _, err := p.WriteFileIfVersion(bytes.NewReader([]byte("v4")), nil, version); !IsConflict(err) {
+		t.Errorf("expected conflict writing %s while locked, got %v", p, err)
+	}
+}
@@ -112,6 +112,43 @@ func (p *FSPath) CreateFile(data io.ReadSeeker, acl ACL) error {
 	return p.WriteFile(data, acl)
 }
 
+// ReadFileWithVersion implements Path::ReadFileWithVersion, using the hash of the contents as the version
+func (p *FSPath) ReadFileWithVersion() ([]byte, string, error) {
+	return readFileWithContentVersion(p)
+}
+
+// WriteFileIfVersion implements Path::WriteFileIfVersion.
+// The local filesystem has no native preconditions, so writers are serialized with a lock file.
+func (p *FSPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	return writeFileIfContentVersion(p, data, acl, version, p.withLockFile)
+}
+
+// RemoveIfVersion implements Path::RemoveIfVersion
+func (p *FSPath) RemoveIfVersion(version string) error {
+	return removeIfContentVersion(p, version, p.withLockFile)
+}
+
+// withLockFile runs fn while holding an exclusive lock file alongside the file
+func (p *FSPath) withLockFile(fn func() error) error {
+	lockPath := path.Join(path.Dir(p.location), "."+path.Base(p.location)+".lock")
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("%s is being written by another process (remove %s if that process has exited): %w", p, lockPath, ErrConflict)
+		}
+		return fmt.Errorf("error creating lock file %s: %v", lockPath, err)
+	}
+	try.CloseFile(f)
+
+	defer func() {
+		if err := os.Remove(lockPath); err != nil {
+			klog.Warningf("unable to remove lock file %q: %v", lockPath, err)
+		}
+	}()
+
+	return fn()
+}
+
 // ReadFile implements Path::ReadFile
 func (p *FSPath) ReadFile() ([]byte, error) {
 	file, err := ioutil.ReadFile(p.location)
@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -132,6 +133,37 @@ func (p *GSPath) Remove() error {
 	}
 }
 
+// RemoveIfVersion implements Path::RemoveIfVersion, using the object generation as the version
+func (p *GSPath) RemoveIfVersion(version string) error {
+	generation, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid generation %q for %s: %v", version, p, err)
+	}
+
+	done, err := RetryWithBackoff(gcsWriteBackoff, func() (bool, error) {
+		err := p.client.Objects.Delete(p.bucket, p.key).IfGenerationMatch(generation).Do()
+		if err != nil {
+			if isGCSNotFound(err) {
+				return true, os.ErrNotExist
+			}
+			if isGCSPreconditionFailed(err) {
+				return true, fmt.Errorf("error removing %s: %w", p, ErrConflict)
+			}
+			return false, fmt.Errorf("error deleting %s: %v", p, err)
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return err
+	} else if done {
+		return nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return wait.ErrWaitTimeout
+	}
+}
+
 func (p *GSPath) RemoveAllVersions() error {
 	return p.Remove()
 }
@@ -148,11 +180,35 @@ func (p *GSPath) Join(relativePath ...string) Path {
 }
 
 func (p *GSPath) WriteFile(data io.ReadSeeker, acl ACL) error {
+	_, err := p.writeFile(data, acl, "")
+	return err
+}
+
+// WriteFileIfVersion implements Path::WriteFileIfVersion, using the object generation as the version
+func (p *GSPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	if version == "" {
+		return "", fmt.Errorf("version is required for conditional write to %s", p)
+	}
+	return p.writeFile(data, acl, version)
+}
+
+// writeFile writes the object, returning the new generation.
+// If ifGeneration is set, the write only succeeds if the object is still at that generation.
+func (p *GSPath) writeFile(data io.ReadSeeker, acl ACL, ifGeneration string) (string, error) {
 	md5Hash, err := hashing.HashAlgorithmMD5.Hash(data)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	var generation int64
+	if ifGeneration != "" {
+		generation, err = strconv.ParseInt(ifGeneration, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid generation %q for %s: %v", ifGeneration, p, err)
+		}
 	}
 
+	var written *storage.Object
 	done, err := RetryWithBackoff(gcsWriteBackoff, func() (bool, error) {
 		obj := &storage.Object{
 			Name:    p.key,
@@ -174,20 +230,27 @@ func (p *GSPath) WriteFile(data io.ReadSeeker, acl ACL) error {
 			return false, fmt.Errorf("error seeking to start of data stream for write to %s: %v", p, err)
 		}
 
-		_, err = p.client.Objects.Insert(p.bucket, obj).Media(data).Do()
+		call := p.client.Objects.Insert(p.bucket, obj).Media(data)
+		if generation != 0 {
+			call = call.IfGenerationMatch(generation)
+		}
+		written, err = call.Do()
 		if err != nil {
+			if isGCSPreconditionFailed(err) {
+				return true, fmt.Errorf("error writing %s: %w", p, ErrConflict)
+			}
 			return false, fmt.Errorf("error writing %s: %v", p, err)
 		}
 
 		return true, nil
 	})
 	if err != nil {
-		return err
+		return "", err
 	} else if done {
-		return nil
+		return strconv.FormatInt(written.Generation, 10), nil
 	} else {
 		// Shouldn't happen - we always return a non-nil error with false
-		return wait.ErrWaitTimeout
+		return "", wait.ErrWaitTimeout
 	}
 }
 
@@ -240,6 +303,34 @@ func (p *GSPath) ReadFile() ([]byte, error) {
 	}
 }
 
+// ReadFileWithVersion implements Path::ReadFileWithVersion, using the object generation as the version
+func (p *GSPath) ReadFileWithVersion() ([]byte, string, error) {
+	klog.V(4).Infof("Reading file %q", p)
+
+	response, err := p.client.Objects.Get(p.bucket, p.key).Download()
+	if err != nil {
+		if isGCSNotFound(err) {
+			return nil, "", os.ErrNotExist
+		}
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+	if response == nil {
+		return nil, "", fmt.Errorf("no response returned from reading %s", p)
+	}
+	defer response.Body.Close()
+
+	var b bytes.Buffer
+	if _, err := io.Copy(&b, response.Body); err != nil {
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+
+	generation := response.Header.Get("X-Goog-Generation")
+	if generation == "" {
+		return nil, "", fmt.Errorf("no generation returned from reading %s", p)
+	}
+	return b.Bytes(), generation, nil
+}
+
 // WriteTo implements io.WriterTo::WriteTo
 func (p *GSPath) WriteTo(out io.Writer) (int64, error) {
 	klog.V(4).Infof("Reading file %q", p)
@@ -379,3 +470,11 @@ func isGCSNotFound(err error) bool {
 	ae, ok := err.(*googleapi.Error)
 	return ok && ae.Code == http.StatusNotFound
 }
+
+func isGCSPreconditionFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	ae, ok := err.(*googleapi.Error)
+	return ok && ae.Code == http.StatusPreconditionFailed
+}
@@ -91,6 +91,19 @@ func (p *KubernetesPath) CreateFile(data io.ReadSeeker, acl ACL) error {
 	return fmt.Errorf("KubernetesPath::CreateFile not supported")
 }
 
+func (p *KubernetesPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	return "", fmt.Errorf("KubernetesPath::WriteFileIfVersion not supported")
+}
+
+func (p *KubernetesPath) RemoveIfVersion(version string) error {
+	return fmt.Errorf("KubernetesPath::RemoveIfVersion not supported")
+}
+
+// ReadFileWithVersion implements Path::ReadFileWithVersion, using the hash of the contents as the version
+func (p *KubernetesPath) ReadFileWithVersion() ([]byte, string, error) {
+	return readFileWithContentVersion(p)
+}
+
 // ReadFile implements Path::ReadFile
 func (p *KubernetesPath) ReadFile() ([]byte, error) {
 	var b bytes.Buffer
@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -33,6 +34,8 @@ type MemFSPath struct {
 	mutex    sync.Mutex
 	contents []byte
 	children map[string]*MemFSPath
+	// version is incremented on every write, for conditional writes
+	version int64
 }
 
 var _ Path = &MemFSPath{}
@@ -105,9 +108,41 @@ func (p *MemFSPath) WriteFile(r io.ReadSeeker, acl ACL) error {
 		return fmt.Errorf("error reading data: %v", err)
 	}
 	p.contents = data
+	p.version++
 	return nil
 }
 
+// WriteFileIfVersion implements Path::WriteFileIfVersion
+func (p *MemFSPath) WriteFileIfVersion(r io.ReadSeeker, acl ACL, version string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil {
+		return "", os.ErrNotExist
+	}
+	if version != strconv.FormatInt(p.version, 10) {
+		return "", fmt.Errorf("error writing %s: %w", p, ErrConflict)
+	}
+	if err := p.WriteFile(r, acl); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(p.version, 10), nil
+}
+
+// RemoveIfVersion implements Path::RemoveIfVersion
+func (p *MemFSPath) RemoveIfVersion(version string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil {
+		return os.ErrNotExist
+	}
+	if version != strconv.FormatInt(p.version, 10) {
+		return fmt.Errorf("error removing %s: %w", p, ErrConflict)
+	}
+	return p.Remove()
+}
+
 func (p *MemFSPath) CreateFile(data io.ReadSeeker, acl ACL) error {
 	// Check if exists
 	if p.contents != nil {
@@ -126,6 +161,17 @@ func (p *MemFSPath) ReadFile() ([]byte, error) {
 	return p.contents, nil
 }
 
+// ReadFileWithVersion implements Path::ReadFileWithVersion
+func (p *MemFSPath) ReadFileWithVersion() ([]byte, string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil {
+		return nil, "", os.ErrNotExist
+	}
+	return p.contents, strconv.FormatInt(p.version, 10), nil
+}
+
 // WriteTo implements io.WriterTo
 func (p *MemFSPath) WriteTo(out io.Writer) (int64, error) {
 	if p.contents == nil {
@@ -103,6 +103,33 @@ func (p *OSSPath) Join(relativePath ...string) Path {
 	}
 }
 
+// ReadFileWithVersion implements Path::ReadFileWithVersion, using the hash of the contents as the version
+func (p *OSSPath) ReadFileWithVersion() ([]byte, string, error) {
+	return readFileWithContentVersion(p)
+}
+
+// conditionalWriteLockOSS serializes conditional writes within this process.
+// OSS has no native write preconditions, so concurrent writers in other processes are not detected.
+var conditionalWriteLockOSS sync.Mutex
+
+// WriteFileIfVersion implements Path::WriteFileIfVersion
+func (p *OSSPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	return writeFileIfContentVersion(p, data, acl, version, func(fn func() error) error {
+		conditionalWriteLockOSS.Lock()
+		defer conditionalWriteLockOSS.Unlock()
+		return fn()
+	})
+}
+
+// RemoveIfVersion implements Path::RemoveIfVersion
+func (p *OSSPath) RemoveIfVersion(version string) error {
+	return removeIfContentVersion(p, version, func(fn func() error) error {
+		conditionalWriteLockOSS.Lock()
+		defer conditionalWriteLockOSS.Unlock()
+		return fn()
+	})
+}
+
 func (p *OSSPath) ReadFile() ([]byte, error) {
 	var b bytes.Buffer
 	done, err := RetryWithBackoff(ossReadBackoff, func() (bool, error) {
@@ -106,6 +106,39 @@ func (p *S3Path) Remove() error {
 	return nil
 }
 
+// RemoveIfVersion implements Path::RemoveIfVersion, using the object ETag as the version
+func (p *S3Path) RemoveIfVersion(version string) error {
+	if version == "" {
+		return fmt.Errorf("version is required for conditional remove of %s", p)
+	}
+
+	client, err := p.client()
+	if err != nil {
+		return err
+	}
+
+	klog.V(8).Infof("removing file %s if it matches %q", p, version)
+
+	request := &s3.DeleteObjectInput{}
+	request.Bucket = aws.String(p.bucket)
+	request.Key = aws.String(p.key)
+
+	// The SDK version we use does not model conditional deletes, so we set the header directly
+	req, _ := client.DeleteObjectRequest(request)
+	req.HTTPRequest.Header.Set("If-Match", version)
+	if err := req.Send(); err != nil {
+		switch AWSErrorCode(err) {
+		case "PreconditionFailed", "ConditionalRequestConflict":
+			return fmt.Errorf("error removing %s: %w", p, ErrConflict)
+		case "NoSuchKey":
+			return os.ErrNotExist
+		}
+		return fmt.Errorf("error deleting %s: %v", p, err)
+	}
+
+	return nil
+}
+
 func (p *S3Path) RemoveAllVersions() error {
 	client, err := p.client()
 	if err != nil {
@@ -191,9 +224,24 @@ func (p *S3Path) Join(relativePath ...string) Path {
 }
 
 func (p *S3Path) WriteFile(data io.ReadSeeker, aclObj ACL) error {
+	_, err := p.writeFile(data, aclObj, "")
+	return err
+}
+
+// WriteFileIfVersion implements Path::WriteFileIfVersion, using the object ETag as the version
+func (p *S3Path) WriteFileIfVersion(data io.ReadSeeker, aclObj ACL, version string) (string, error) {
+	if version == "" {
+		return "", fmt.Errorf("version is required for conditional write to %s", p)
+	}
+	return p.writeFile(data, aclObj, version)
+}
+
+// writeFile writes the object, returning the new ETag.
+// If ifMatch is set, the write only succeeds if the current ETag of the object matches.
+func (p *S3Path) writeFile(data io.ReadSeeker, aclObj ACL, ifMatch string) (string, error) {
 	client, err := p.client()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	klog.V(4).Infof("Writing file %q", p)
@@ -227,24 +275,33 @@ func (p *S3Path) WriteFile(data io.ReadSeeker, aclObj ACL) error {
 	} else if aclObj != nil {
 		s3Acl, ok := aclObj.(*S3Acl)
 		if !ok {
-			return fmt.Errorf("write to %s with ACL of unexpected type %T", p, aclObj)
+			return "", fmt.Errorf("write to %s with ACL of unexpected type %T", p, aclObj)
 		}
 		request.ACL = s3Acl.RequestACL
 	}
 
 	// We don't need Content-MD5: https://github.com/aws/aws-sdk-go/issues/208
 
-	klog.V(8).Infof("Calling S3 PutObject Bucket=%q Key=%q SSE=%q ACL=%q", p.bucket, p.key, sseLog, acl)
+	klog.V(8).Infof("Calling S3 PutObject Bucket=%q Key=%q SSE=%q ACL=%q IfMatch=%q", p.bucket, p.key, sseLog, acl, ifMatch)
 
-	_, err = client.PutObject(request)
+	// The SDK version we use does not model conditional puts, so we set the header directly
+	req, response := client.PutObjectRequest(request)
+	if ifMatch != "" {
+		req.HTTPRequest.Header.Set("If-Match", ifMatch)
+	}
+	err = req.Send()
 	if err != nil {
+		switch AWSErrorCode(err) {
+		case "PreconditionFailed", "ConditionalRequestConflict":
+			return "", fmt.Errorf("error writing %s: %w", p, ErrConflict)
+		}
 		if acl != "" {
-			return fmt.Errorf("error writing %s (with ACL=%q): %v", p, acl, err)
+			return "", fmt.Errorf("error writing %s (with ACL=%q): %v", p, acl, err)
 		}
-		return fmt.Errorf("error writing %s: %v", p, err)
+		return "", fmt.Errorf("error writing %s: %v", p, err)
 	}
 
-	return nil
+	return aws.StringValue(response.ETag), nil
 }
 
 // To prevent concurrent creates on the same file while maintaining atomicity of writes,
@@ -280,6 +337,35 @@ func (p *S3Path) ReadFile() ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// ReadFileWithVersion implements Path::ReadFileWithVersion, using the object ETag as the version
+func (p *S3Path) ReadFileWithVersion() ([]byte, string, error) {
+	client, err := p.client()
+	if err != nil {
+		return nil, "", err
+	}
+
+	klog.V(4).Infof("Reading file %q", p)
+
+	request := &s3.GetObjectInput{}
+	request.Bucket = aws.String(p.bucket)
+	request.Key = aws.String(p.key)
+
+	response, err := client.GetObject(request)
+	if err != nil {
+		if AWSErrorCode(err) == "NoSuchKey" {
+			return nil, "", os.ErrNotExist
+		}
+		return nil, "", fmt.Errorf("error fetching %s: %v", p, err)
+	}
+	defer response.Body.Close()
+
+	var b bytes.Buffer
+	if _, err := io.Copy(&b, response.Body); err != nil {
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+	return b.Bytes(), aws.StringValue(response.ETag), nil
+}
+
 // WriteTo implements io.WriterTo
 func (p *S3Path) WriteTo(out io.Writer) (int64, error) {
 	client, err := p.client()
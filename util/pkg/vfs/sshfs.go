@@ -247,6 +247,51 @@ func (p *SSHPath) CreateFile(data io.ReadSeeker, acl ACL) error {
 	return p.WriteFile(data, acl)
 }
 
+// ReadFileWithVersion implements Path::ReadFileWithVersion, using the hash of the contents as the version
+func (p *SSHPath) ReadFileWithVersion() ([]byte, string, error) {
+	return readFileWithContentVersion(p)
+}
+
+// WriteFileIfVersion implements Path::WriteFileIfVersion.
+// There are no native preconditions over sftp, so writers are serialized with a lock file.
+func (p *SSHPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	return writeFileIfContentVersion(p, data, acl, version, p.withLockFile)
+}
+
+// RemoveIfVersion implements Path::RemoveIfVersion
+func (p *SSHPath) RemoveIfVersion(version string) error {
+	return removeIfContentVersion(p, version, p.withLockFile)
+}
+
+// withLockFile runs fn while holding an exclusive lock file alongside the file
+func (p *SSHPath) withLockFile(fn func() error) error {
+	sftpClient, err := p.newClient()
+	if err != nil {
+		return err
+	}
+	defer sftpClient.Close()
+
+	lockPath := path.Join(path.Dir(p.path), "."+path.Base(p.path)+".lock")
+	f, err := sftpClient.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY)
+	if err != nil {
+		if _, statErr := sftpClient.Stat(lockPath); statErr == nil {
+			return fmt.Errorf("%s is being written by another process (remove %s if that process has exited): %w", p, lockPath, ErrConflict)
+		}
+		return fmt.Errorf("error creating lock file %s over sftp: %v", lockPath, err)
+	}
+	if err := f.Close(); err != nil {
+		klog.Warningf("unable to close lock file %q: %v", lockPath, err)
+	}
+
+	defer func() {
+		if err := sftpClient.Remove(lockPath); err != nil {
+			klog.Warningf("unable to remove lock file %q: %v", lockPath, err)
+		}
+	}()
+
+	return fn()
+}
+
 // ReadFile implements Path::ReadFile
 func (p *SSHPath) ReadFile() ([]byte, error) {
 	var b bytes.Buffer
@@ -400,6 +400,33 @@ func (p *SwiftPath) createBucket() error {
 	}
 }
 
+// ReadFileWithVersion implements Path::ReadFileWithVersion, using the hash of the contents as the version
+func (p *SwiftPath) ReadFileWithVersion() ([]byte, string, error) {
+	return readFileWithContentVersion(p)
+}
+
+// conditionalWriteLockSwift serializes conditional writes within this process.
+// Swift has no native write preconditions, so concurrent writers in other processes are not detected.
+var conditionalWriteLockSwift sync.Mutex
+
+// WriteFileIfVersion implements Path::WriteFileIfVersion
+func (p *SwiftPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	return writeFileIfContentVersion(p, data, acl, version, func(fn func() error) error {
+		conditionalWriteLockSwift.Lock()
+		defer conditionalWriteLockSwift.Unlock()
+		return fn()
+	})
+}
+
+// RemoveIfVersion implements Path::RemoveIfVersion
+func (p *SwiftPath) RemoveIfVersion(version string) error {
+	return removeIfContentVersion(p, version, func(fn func() error) error {
+		conditionalWriteLockSwift.Lock()
+		defer conditionalWriteLockSwift.Unlock()
+		return fn()
+	})
+}
+
 // ReadFile implements Path::ReadFile
 func (p *SwiftPath) ReadFile() ([]byte, error) {
 	var b bytes.Buffer
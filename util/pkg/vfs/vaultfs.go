@@ -25,6 +25,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
 
 	vault "github.com/hashicorp/vault/api"
 	"k8s.io/klog/v2"
@@ -90,6 +91,33 @@ func (p *VaultPath) CreateFile(data io.ReadSeeker, acl ACL) error {
 	}
 }
 
+// ReadFileWithVersion implements Path::ReadFileWithVersion, using the hash of the contents as the version
+func (p *VaultPath) ReadFileWithVersion() ([]byte, string, error) {
+	return readFileWithContentVersion(p)
+}
+
+// conditionalWriteLockVault serializes conditional writes within this process.
+// Vault has no native write preconditions, so concurrent writers in other processes are not detected.
+var conditionalWriteLockVault sync.Mutex
+
+// WriteFileIfVersion implements Path::WriteFileIfVersion
+func (p *VaultPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	return writeFileIfContentVersion(p, data, acl, version, func(fn func() error) error {
+		conditionalWriteLockVault.Lock()
+		defer conditionalWriteLockVault.Unlock()
+		return fn()
+	})
+}
+
+// RemoveIfVersion implements Path::RemoveIfVersion
+func (p *VaultPath) RemoveIfVersion(version string) error {
+	return removeIfContentVersion(p, version, func(fn func() error) error {
+		conditionalWriteLockVault.Lock()
+		defer conditionalWriteLockVault.Unlock()
+		return fn()
+	})
+}
+
 func (p *VaultPath) ReadFile() ([]byte, error) {
 	secret, err := p.vaultClient.Logical().Read(p.dataPath())
 	if err != nil {
@@ -56,6 +56,17 @@ type Path interface {
 	// CreateFile writes the file contents, but only if the file does not already exist
 	CreateFile(data io.ReadSeeker, acl ACL) error
 
+	// ReadFileWithVersion returns the contents of the file along with an opaque version,
+	// which changes whenever the file is written.
+	// If the file did not exist, err = os.ErrNotExist
+	ReadFileWithVersion() ([]byte, string, error)
+	// WriteFileIfVersion writes the file contents, but only if the file is still at the specified version,
+	// returning the new version.  If the file has been changed since, the error satisfies IsConflict.
+	WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error)
+	// RemoveIfVersion deletes the file, but only if it is still at the specified version.
+	// If the file has been changed since, the error satisfies IsConflict.
+	RemoveIfVersion(version string) error
+
 	// Remove deletes the file
 	Remove() error
 